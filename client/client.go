@@ -0,0 +1,204 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package client is a typed Go client for the xgql GraphQL API. It allows Go
+// programs to query xgql without hand-writing GraphQL documents. The typed
+// operations in generated.go are generated from queries.graphql by genqlient.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Khan/genqlient/graphql"
+	"github.com/pkg/errors"
+)
+
+const (
+	errMarshalRequest    = "cannot marshal GraphQL request"
+	errNewRequest        = "cannot create HTTP request"
+	errDoRequest         = "cannot make HTTP request"
+	errUnmarshalResponse = "cannot unmarshal GraphQL response"
+	errUnmarshalData     = "cannot unmarshal GraphQL response data"
+	errFmtStatus         = "unexpected HTTP status %q: %s"
+)
+
+// maxErrBody is the maximum number of bytes of an unexpected HTTP response
+// body that will be included in an error.
+const maxErrBody = 1024
+
+var _ graphql.Client = &Client{}
+
+// A Client of the xgql API.
+type Client struct {
+	url   string
+	http  *http.Client
+	token string
+	ua    string
+}
+
+// An Option configures a Client.
+type Option func(c *Client)
+
+// WithHTTPClient configures the HTTP client used to make requests. The
+// http.DefaultClient is used by default.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.http = hc
+	}
+}
+
+// WithBearerToken configures the bearer token the client will use to
+// authenticate to xgql. xgql uses this token to authenticate to the Kubernetes
+// API server on the client's behalf.
+func WithBearerToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithUserAgent configures the user agent of the client.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		c.ua = ua
+	}
+}
+
+// New returns a client of the xgql API served at the supplied URL, e.g.
+// https://xgql.example.org/query.
+func New(url string, o ...Option) *Client {
+	c := &Client{url: url, http: http.DefaultClient}
+	for _, fn := range o {
+		fn(c)
+	}
+	return c
+}
+
+// A Request to the xgql API.
+type Request struct {
+	// Query is the GraphQL document to execute.
+	Query string `json:"query"`
+
+	// OperationName is the name of the operation within the query to execute.
+	// It may be omitted if the query contains only one operation.
+	OperationName string `json:"operationName,omitempty"`
+
+	// Variables of the query. Any value that marshals to a JSON object may be
+	// supplied.
+	Variables interface{} `json:"variables,omitempty"`
+}
+
+// An Error returned by the xgql API.
+type Error struct {
+	// Message describing the error.
+	Message string `json:"message"`
+
+	// Path of the field that caused the error, if any.
+	Path []interface{} `json:"path,omitempty"`
+}
+
+// Error returns the error's message, prefixed with its path if it has one.
+func (e Error) Error() string {
+	if len(e.Path) == 0 {
+		return e.Message
+	}
+	p := make([]string, len(e.Path))
+	for i := range e.Path {
+		p[i] = fmt.Sprint(e.Path[i])
+	}
+	return strings.Join(p, ".") + ": " + e.Message
+}
+
+// Errors returned by the xgql API. xgql may return errors alongside partial
+// data; for example if it could not resolve one field of an otherwise valid
+// response.
+type Errors []Error
+
+// Error returns all errors, separated by semicolons.
+func (e Errors) Error() string {
+	s := make([]string, len(e))
+	for i := range e {
+		s[i] = e[i].Error()
+	}
+	return strings.Join(s, "; ")
+}
+
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors Errors          `json:"errors"`
+}
+
+// Do executes the supplied request, unmarshalling the data it returns into the
+// supplied data. Do returns Errors if the xgql API returned any errors. Any
+// partial data xgql returned alongside errors will be unmarshalled into the
+// supplied data.
+func (c *Client) Do(ctx context.Context, r *Request, data interface{}) error {
+	body, err := json.Marshal(r)
+	if err != nil {
+		return errors.Wrap(err, errMarshalRequest)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, errNewRequest)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.ua != "" {
+		req.Header.Set("User-Agent", c.ua)
+	}
+
+	rsp, err := c.http.Do(req)
+	if err != nil {
+		return errors.Wrap(err, errDoRequest)
+	}
+	defer rsp.Body.Close() //nolint:errcheck // Nothing useful to do with this error.
+
+	// xgql returns 422 Unprocessable Entity for GraphQL documents that cannot
+	// be parsed or validated, with the reasons in the errors of the body.
+	if rsp.StatusCode != http.StatusOK && rsp.StatusCode != http.StatusUnprocessableEntity {
+		b, _ := io.ReadAll(io.LimitReader(rsp.Body, maxErrBody))
+		return errors.Errorf(errFmtStatus, rsp.Status, strings.TrimSpace(string(b)))
+	}
+
+	out := &response{}
+	if err := json.NewDecoder(rsp.Body).Decode(out); err != nil {
+		return errors.Wrap(err, errUnmarshalResponse)
+	}
+
+	if len(out.Data) > 0 && data != nil {
+		if err := json.Unmarshal(out.Data, data); err != nil {
+			return errors.Wrap(err, errUnmarshalData)
+		}
+	}
+
+	if len(out.Errors) > 0 {
+		return out.Errors
+	}
+	return nil
+}
+
+// MakeRequest executes the supplied genqlient request. It allows the Client to
+// be used with the typed operations generated from queries.graphql.
+func (c *Client) MakeRequest(ctx context.Context, req *graphql.Request, resp *graphql.Response) error {
+	return c.Do(ctx, &Request{Query: req.Query, OperationName: req.OpName, Variables: req.Variables}, resp.Data)
+}
@@ -0,0 +1,291 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestDo(t *testing.T) {
+	type data struct {
+		Cool string `json:"cool"`
+	}
+
+	type want struct {
+		data *data
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		handler http.HandlerFunc
+		want    want
+	}{
+		"Success": {
+			reason: "Data returned by xgql should be unmarshalled.",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"data":{"cool":"very"}}`))
+			},
+			want: want{
+				data: &data{Cool: "very"},
+			},
+		},
+		"PartialData": {
+			reason: "Partial data returned by xgql alongside errors should be unmarshalled, and the errors returned.",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"data":{"cool":"kinda"},"errors":[{"message":"boom","path":["cool",0]}]}`))
+			},
+			want: want{
+				data: &data{Cool: "kinda"},
+				err:  Errors{{Message: "boom", Path: []interface{}{"cool", float64(0)}}},
+			},
+		},
+		"InvalidQuery": {
+			reason: "Errors returned by xgql for invalid queries should be returned.",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				_, _ = w.Write([]byte(`{"errors":[{"message":"boom"}],"data":null}`))
+			},
+			want: want{
+				data: &data{},
+				err:  Errors{{Message: "boom"}},
+			},
+		},
+		"UnexpectedStatus": {
+			reason: "An unexpected HTTP status should be returned as an error.",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusTeapot)
+				_, _ = w.Write([]byte("short and stout\n"))
+			},
+			want: want{
+				data: &data{},
+				err:  errors.Errorf(errFmtStatus, "418 I'm a teapot", "short and stout"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(tc.handler)
+			defer srv.Close()
+
+			got := &data{}
+			err := New(srv.URL).Do(context.Background(), &Request{Query: "query { cool }"}, got)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.Do(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.data, got); diff != "" {
+				t.Errorf("\n%s\nc.Do(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDoRequest(t *testing.T) {
+	var (
+		gotAuthz string
+		gotUA    string
+		gotReq   Request
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuthz = r.Header.Get("Authorization")
+		gotUA = r.Header.Get("User-Agent")
+		_ = json.NewDecoder(r.Body).Decode(&gotReq)
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, WithBearerToken("cooltoken"), WithUserAgent("coolclient"))
+	req := Request{
+		Query:         "query Cool($a: String) { cool(a: $a) }",
+		OperationName: "Cool",
+		Variables:     map[string]interface{}{"a": "b"},
+	}
+	if err := c.Do(context.Background(), &req, nil); err != nil {
+		t.Fatalf("c.Do(...): unexpected error: %s", err)
+	}
+
+	if diff := cmp.Diff("Bearer cooltoken", gotAuthz); diff != "" {
+		t.Errorf("c.Do(...): -want Authorization, +got Authorization:\n%s", diff)
+	}
+	if diff := cmp.Diff("coolclient", gotUA); diff != "" {
+		t.Errorf("c.Do(...): -want User-Agent, +got User-Agent:\n%s", diff)
+	}
+	if diff := cmp.Diff(req, gotReq); diff != "" {
+		t.Errorf("c.Do(...): -want request, +got request:\n%s", diff)
+	}
+}
+
+func TestListProviders(t *testing.T) {
+	id := ReferenceID{APIVersion: "pkg.crossplane.io/v1", Kind: "Provider", Name: "cool"}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"providers":{"totalCount":2,"nodes":[{
+			"id":"` + id.String() + `",
+			"apiVersion":"pkg.crossplane.io/v1",
+			"kind":"Provider",
+			"metadata":{"name":"cool","uid":"no-you-id","creationTime":"2021-01-01T00:00:00Z"},
+			"spec":{"package":"crossplane/provider-cool:v0.1.0"},
+			"status":{"conditions":[{"type":"Healthy","status":"TRUE","reason":"HealthyPackageRevision","lastTransitionTime":"2021-01-01T00:00:00Z"}]}
+		}]}}}`))
+	}))
+	defer srv.Close()
+
+	got, err := ListProviders(context.Background(), New(srv.URL))
+	if err != nil {
+		t.Fatalf("ListProviders(...): unexpected error: %s", err)
+	}
+
+	ts := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	want := &ListProvidersResponse{Providers: ProviderConnection{
+		TotalCount: 2,
+		Nodes: []Provider{{
+			Id:         id.String(),
+			ApiVersion: "pkg.crossplane.io/v1",
+			Kind:       "Provider",
+			Metadata: ProviderMetadataObjectMeta{ObjectMetaFields: ObjectMetaFields{
+				Name:         "cool",
+				Uid:          "no-you-id",
+				CreationTime: ts,
+			}},
+			Spec: ProviderSpec{Package: "crossplane/provider-cool:v0.1.0"},
+			Status: &ProviderStatus{Conditions: []ProviderStatusConditionsCondition{{ConditionFields: ConditionFields{
+				Type:               "Healthy",
+				Status:             ConditionStatusTrue,
+				Reason:             "HealthyPackageRevision",
+				LastTransitionTime: ts,
+			}}}},
+		}},
+	}}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ListProviders(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestGetKubernetesResource(t *testing.T) {
+	id := ReferenceID{APIVersion: "example.org/v1", Kind: "CoolResource", Namespace: "default", Name: "cool"}
+	ts := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		reason string
+		body   string
+		want   *GetKubernetesResourceResponse
+	}{
+		"Found": {
+			reason: "A resource of any type should be returned with the fields common to all Kubernetes resources.",
+			body: `{"data":{"kubernetesResource":{
+				"__typename":"GenericResource",
+				"id":"` + id.String() + `",
+				"apiVersion":"example.org/v1",
+				"kind":"CoolResource",
+				"metadata":{"name":"cool","namespace":"default","creationTime":"2021-01-01T00:00:00Z"}
+			}}}`,
+			want: &GetKubernetesResourceResponse{KubernetesResource: &KubernetesResource{
+				Typename:   "GenericResource",
+				Id:         id.String(),
+				ApiVersion: "example.org/v1",
+				Kind:       "CoolResource",
+				Metadata: KubernetesResourceMetadataObjectMeta{ObjectMetaFields: ObjectMetaFields{
+					Name:         "cool",
+					Namespace:    "default",
+					CreationTime: ts,
+				}},
+			}},
+		},
+		"NotFound": {
+			reason: "A resource that doesn't exist should be returned as nil.",
+			body:   `{"data":{"kubernetesResource":null}}`,
+			want:   &GetKubernetesResourceResponse{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var gotReq Request
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&gotReq)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer srv.Close()
+
+			got, err := GetKubernetesResource(context.Background(), New(srv.URL), id.String())
+			if err != nil {
+				t.Fatalf("\n%s\nGetKubernetesResource(...): unexpected error: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nGetKubernetesResource(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(map[string]interface{}{"id": id.String()}, gotReq.Variables); diff != "" {
+				t.Errorf("\n%s\nGetKubernetesResource(...): -want variables, +got variables:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestListManagedResources(t *testing.T) {
+	ready := ConditionStatusTrue
+	pages := map[int]string{
+		0: `{"data":{"managedResources":{"totalCount":3,"nodes":[{"id":"a"},{"id":"b"}]}}}`,
+		2: `{"data":{"managedResources":{"totalCount":3,"nodes":[{"id":"c"}]}}}`,
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := &struct {
+			Variables struct {
+				Ready  *ConditionStatus `json:"ready"`
+				Limit  int              `json:"limit"`
+				Offset int              `json:"offset"`
+			} `json:"variables"`
+		}{}
+		_ = json.NewDecoder(r.Body).Decode(req)
+		if req.Variables.Ready == nil || *req.Variables.Ready != ready || req.Variables.Limit != 2 {
+			t.Errorf("ListManagedResources(...): unexpected variables: %+v", req.Variables)
+		}
+		_, _ = w.Write([]byte(pages[req.Variables.Offset]))
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL)
+	page := func(ctx context.Context, limit, offset int) ([]ManagedResource, int, error) {
+		rsp, err := ListManagedResources(ctx, c, &ready, nil, nil, nil, &limit, &offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		return rsp.ManagedResources.Nodes, rsp.ManagedResources.TotalCount, nil
+	}
+
+	got := []string{}
+	err := Iterate(context.Background(), 2, page, func(mr ManagedResource) error {
+		got = append(got, mr.Id)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Iterate(...): unexpected error: %s", err)
+	}
+	if diff := cmp.Diff([]string{"a", "b", "c"}, got); diff != "" {
+		t.Errorf("Iterate(...): -want IDs, +got IDs:\n%s", diff)
+	}
+}
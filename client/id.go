@@ -0,0 +1,66 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/upbound/xgql/internal/graph/model"
+)
+
+const errType = "id must be a string"
+
+// A ReferenceID uniquely identifies a Kubernetes resource in the xgql API.
+// xgql represents IDs as opaque strings; a ReferenceID may be used to build an
+// ID from, or to parse an ID into, the Kubernetes reference it represents.
+type ReferenceID struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+}
+
+// ParseReferenceID parses the supplied xgql ID string.
+func ParseReferenceID(id string) (ReferenceID, error) {
+	in, err := model.ParseReferenceID(id)
+	return ReferenceID(in), err
+}
+
+// String returns the ReferenceID as an xgql ID string.
+func (id ReferenceID) String() string {
+	m := model.ReferenceID(id)
+	return m.String()
+}
+
+// MarshalJSON marshals the ReferenceID as an xgql ID string.
+func (id ReferenceID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(id.String())
+}
+
+// UnmarshalJSON unmarshals an xgql ID string.
+func (id *ReferenceID) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return errors.Wrap(err, errType)
+	}
+	in, err := ParseReferenceID(s)
+	if err != nil {
+		return err
+	}
+	*id = in
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/upbound/xgql/internal/graph/model"
+)
+
+func TestReferenceID(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		id     ReferenceID
+	}{
+		"ClusterScoped": {
+			reason: "It should be possible to round-trip a cluster scoped ReferenceID.",
+			id: ReferenceID{
+				APIVersion: "pkg.crossplane.io/v1",
+				Kind:       "Provider",
+				Name:       "provider-cool",
+			},
+		},
+		"Namespaced": {
+			reason: "It should be possible to round-trip a namespaced ReferenceID.",
+			id: ReferenceID{
+				APIVersion: "example.org/v1",
+				Kind:       "CoolClaim",
+				Namespace:  "default",
+				Name:       "cool",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Our IDs must be compatible with those served by xgql.
+			m := model.ReferenceID(tc.id)
+			if diff := cmp.Diff(m.String(), tc.id.String()); diff != "" {
+				t.Errorf("\n%s\nid.String(): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			b, err := json.Marshal(tc.id)
+			if err != nil {
+				t.Fatalf("\n%s\njson.Marshal(...): unexpected error: %s", tc.reason, err)
+			}
+			got := ReferenceID{}
+			if err := json.Unmarshal(b, &got); err != nil {
+				t.Fatalf("\n%s\njson.Unmarshal(...): unexpected error: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.id, got); diff != "" {
+				t.Errorf("\n%s\njson.Unmarshal(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
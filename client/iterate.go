@@ -0,0 +1,53 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+const errFmtPage = "cannot get page at offset %d"
+
+// A PageFunc returns at most limit nodes of a connection, starting at the
+// supplied offset, and the total number of nodes in the connection. It
+// typically wraps a generated operation that accepts limit and offset
+// variables.
+type PageFunc[T any] func(ctx context.Context, limit, offset int) (nodes []T, totalCount int, err error)
+
+// Iterate calls the supplied function for each node of a connection, in order.
+// It reads the connection one page of the supplied size at a time, advancing
+// the offset of each page by the number of nodes returned, until it has read
+// the connection's total count of nodes or a page returns no nodes. Iteration
+// stops at the first error, which is returned.
+func Iterate[T any](ctx context.Context, size int, page PageFunc[T], fn func(n T) error) error {
+	offset := 0
+	for {
+		nodes, total, err := page(ctx, size, offset)
+		if err != nil {
+			return errors.Wrapf(err, errFmtPage, offset)
+		}
+		for i := range nodes {
+			if err := fn(nodes[i]); err != nil {
+				return err
+			}
+		}
+		offset += len(nodes)
+		if len(nodes) == 0 || offset >= total {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestIterate(t *testing.T) {
+	errBoom := errors.New("boom")
+	nodes := []string{"a", "b", "c", "d", "e"}
+
+	// pages returns a page of nodes, recording the limit and offset of each
+	// page it was asked for.
+	pages := func(got *[][2]int) PageFunc[string] {
+		return func(_ context.Context, limit, offset int) ([]string, int, error) {
+			*got = append(*got, [2]int{limit, offset})
+			end := offset + limit
+			if end > len(nodes) {
+				end = len(nodes)
+			}
+			return nodes[offset:end], len(nodes), nil
+		}
+	}
+
+	type args struct {
+		size int
+		page func(got *[][2]int) PageFunc[string]
+		fn   func(got *[]string) func(n string) error
+	}
+	type want struct {
+		nodes []string
+		pages [][2]int
+		err   error
+	}
+
+	collect := func(got *[]string) func(n string) error {
+		return func(n string) error {
+			*got = append(*got, n)
+			return nil
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"SeveralPages": {
+			reason: "Each node should be visited once, reading one page at a time.",
+			args: args{
+				size: 2,
+				page: pages,
+				fn:   collect,
+			},
+			want: want{
+				nodes: nodes,
+				pages: [][2]int{{2, 0}, {2, 2}, {2, 4}},
+			},
+		},
+		"OnePage": {
+			reason: "A single page should be read if it contains every node.",
+			args: args{
+				size: 10,
+				page: pages,
+				fn:   collect,
+			},
+			want: want{
+				nodes: nodes,
+				pages: [][2]int{{10, 0}},
+			},
+		},
+		"EmptyPage": {
+			reason: "Iteration should stop when a page returns no nodes, even if the total count suggests there are more.",
+			args: args{
+				size: 2,
+				page: func(got *[][2]int) PageFunc[string] {
+					return func(_ context.Context, limit, offset int) ([]string, int, error) {
+						*got = append(*got, [2]int{limit, offset})
+						return nil, 3, nil
+					}
+				},
+				fn: collect,
+			},
+			want: want{
+				pages: [][2]int{{2, 0}},
+			},
+		},
+		"PageError": {
+			reason: "Errors getting a page should be returned.",
+			args: args{
+				size: 2,
+				page: func(got *[][2]int) PageFunc[string] {
+					return func(_ context.Context, limit, offset int) ([]string, int, error) {
+						*got = append(*got, [2]int{limit, offset})
+						return nil, 0, errBoom
+					}
+				},
+				fn: collect,
+			},
+			want: want{
+				pages: [][2]int{{2, 0}},
+				err:   errors.Wrapf(errBoom, errFmtPage, 0),
+			},
+		},
+		"FnError": {
+			reason: "Iteration should stop at the first error returned by the supplied function.",
+			args: args{
+				size: 2,
+				page: pages,
+				fn: func(got *[]string) func(n string) error {
+					return func(n string) error {
+						*got = append(*got, n)
+						if n == "c" {
+							return errBoom
+						}
+						return nil
+					}
+				},
+			},
+			want: want{
+				nodes: []string{"a", "b", "c"},
+				pages: [][2]int{{2, 0}, {2, 2}},
+				err:   errBoom,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var gotNodes []string
+			var gotPages [][2]int
+			err := Iterate(context.Background(), tc.args.size, tc.args.page(&gotPages), tc.args.fn(&gotNodes))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nIterate(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.nodes, gotNodes); diff != "" {
+				t.Errorf("\n%s\nIterate(...): -want nodes, +got nodes:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.pages, gotPages); diff != "" {
+				t.Errorf("\n%s\nIterate(...): -want pages, +got pages:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
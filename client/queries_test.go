@@ -0,0 +1,44 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"os"
+	"testing"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/upbound/xgql/schema"
+)
+
+// TestQueries validates the operations the client is generated from against
+// the xgql schema, so that changes to the schema that break the client break
+// the build even if the client has not been regenerated.
+func TestQueries(t *testing.T) {
+	s, gerr := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: string(schema.SDL())})
+	if gerr != nil {
+		t.Fatalf("gqlparser.LoadSchema(...): %s", gerr)
+	}
+
+	q, err := os.ReadFile("queries.graphql")
+	if err != nil {
+		t.Fatalf("os.ReadFile(...): %s", err)
+	}
+
+	if _, errs := gqlparser.LoadQuery(s, string(q)); len(errs) > 0 {
+		t.Errorf("gqlparser.LoadQuery(...): %s", errs)
+	}
+}
@@ -16,16 +16,22 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"expvar"
 	"io/ioutil"
 	stdlog "log"
+	"net"
 	"net/http"
+	"net/http/pprof"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
-	"github.com/99designs/gqlgen/graphql/handler"
-	"github.com/99designs/gqlgen/graphql/handler/apollotracing"
 	"github.com/99designs/gqlgen/graphql/playground"
 	google "github.com/GoogleCloudPlatform/opentelemetry-operations-go/exporter/trace"
 	"github.com/go-chi/chi/v5"
@@ -44,55 +50,28 @@ import (
 	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/sdk/trace"
 	"gopkg.in/alecthomas/kingpin.v2"
-	appsv1 "k8s.io/api/apps/v1"
-	corev1 "k8s.io/api/core/v1"
-	rbacv1 "k8s.io/api/rbac/v1"
-	kextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
-	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
-	extv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
-	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
 
+	"github.com/upbound/xgql"
+	"github.com/upbound/xgql/internal/allowlist"
 	"github.com/upbound/xgql/internal/auth"
 	"github.com/upbound/xgql/internal/clients"
-	"github.com/upbound/xgql/internal/graph/generated"
+	"github.com/upbound/xgql/internal/config"
+	"github.com/upbound/xgql/internal/feature"
 	"github.com/upbound/xgql/internal/graph/present"
 	"github.com/upbound/xgql/internal/graph/resolvers"
 	"github.com/upbound/xgql/internal/opentelemetry"
+	"github.com/upbound/xgql/internal/proxy"
+	"github.com/upbound/xgql/internal/sharedcache"
+	"github.com/upbound/xgql/internal/sizelimit"
 	"github.com/upbound/xgql/internal/version"
+	"github.com/upbound/xgql/schema"
 )
 
-// A set of resources that we never want to cache. Clients take a watch on any
-// kind of resource they're asked to read unless it's in this list. We allow
-// caching of arbitrary resources (i.e. *unstructured.Unstructured, which may
-// have any GVK) in order to allow us to cache managed and composite resources.
-// We're particularly at risk of caching resources like these unexpectedly when
-// iterating through arrays of arbitrary object references (e.g. owner refs).
-var noCache = []client.Object{
-	// We don't cache these resources because there's a (very slim) possibility
-	// they could end up as the owner reference of a resource we're concerned
-	// with, and we don't want to try to watch (e.g.) all pods in the cluster
-	// just because a pod somehow became the owner reference of an XR.
-	&corev1.Node{},
-	&corev1.Namespace{},
-	&corev1.Pod{},
-	&corev1.ConfigMap{},
-	&corev1.Service{},
-	&corev1.ServiceAccount{},
-	&appsv1.Deployment{},
-	&appsv1.DaemonSet{},
-	&rbacv1.RoleBinding{},
-	&rbacv1.ClusterRoleBinding{},
-
-	// We don't cache secrets because there's a high risk that the caller won't
-	// have access to list and watch secrets across all namespaces.
-	&corev1.Secret{},
-}
-
 func main() {
 	var (
 		app      = kingpin.New(filepath.Base(os.Args[0]), "A GraphQL API for Crossplane.").DefaultEnvars()
@@ -104,13 +83,55 @@ func main() {
 		play     = app.Flag("enable-playground", "Serve a GraphQL Playground.").Bool()
 		tracer   = app.Flag("trace-backend", "Tracer to use.").Default("jaeger").Enum("jaeger", "gcp")
 		ratio    = app.Flag("trace-ratio", "Ratio of queries that should be traced.").Default("0.01").Float()
+		opRatios = app.Flag("trace-operation-ratio", "Ratio of a particular GraphQL operation that should be traced, as [operation]=[ratio]. Overrides --trace-ratio for that operation. May be repeated.").StringMap()
 		agent    = app.Flag("trace-agent", "Address of the Jaeger trace agent as [host]:[port]").TCP()
+		endpoint = app.Flag("trace-endpoint", "URL of the Jaeger collector to which traces are sent, e.g. http://jaeger:14268/api/traces. Takes precedence over --trace-agent.").URL()
+		theaders = app.Flag("trace-header", "A header to send with each batch of traces sent to --trace-endpoint, as [name]=[value]. May be repeated.").StringMap()
+		nlimit   = app.Flag("nested-limit", "Default maximum number of nodes returned by connections nested within a list. Zero disables the limit.").Default(strconv.Itoa(resolvers.DefaultNestedLimit)).Int()
+		conc     = app.Flag("concurrency", "Maximum number of Kubernetes objects each resolver may get concurrently, e.g. the composed resources of a composite resource.").Default(strconv.Itoa(resolvers.DefaultConcurrency)).Int()
+		wbuffer  = app.Flag("watch-buffer", "Number of events buffered for each subscription before events are dropped.").Default(strconv.Itoa(clients.DefaultWatchBuffer)).Int()
+		overflow = app.Flag("watch-overflow", "Which events to drop when a subscription's buffer is full.").Default(string(clients.DropNewest)).Enum(string(clients.DropNewest), string(clients.DropOldest))
+		cstrip   = app.Flag("cache-strip-metadata", "Strip managed fields and the last-applied-configuration annotation from cached objects to reduce memory usage.").Default("true").Bool()
+		cmax     = app.Flag("cache-max-object-size", "Maximum size in bytes of a cached object. Only the metadata of larger objects is cached; they're read from the API server when needed. Zero disables the limit.").Default("0").Int()
+		dlisten  = app.Flag("debug-listen", "Address at which to serve pprof and expvar debug endpoints. Debug endpoints are disabled if unset.").String()
+		cfgFile  = app.Flag("config", "Path to an optional YAML configuration file of auth, cache, limits, and features. Values in the file take precedence over flags. Limits and features are reloaded when the file changes.").ExistingFile()
+		reload   = app.Flag("config-reload-interval", "How often to check the configuration file for changes.").Default(config.DefaultReloadInterval.String()).Duration()
+		enable   = app.Flag("enable-feature", "Enable an experimental feature. May be repeated.").Enums(feature.Known()...)
+		disable  = app.Flag("disable-feature", "Disable a feature. May be repeated.").Enums(feature.Known()...)
+		xpns     = app.Flag("crossplane-namespace", "Namespace in which Crossplane runs, and in which package pull secrets must exist.").Default(resolvers.DefaultCrossplaneNamespace).String()
+		redact   = app.Flag("error-redaction", "What to redact from error messages sent to clients. Errors are logged before they're redacted.").Default(string(present.RedactSensitive)).Enum(present.RedactionPolicies...)
+		tcookie  = app.Flag("token-cookie", "Name of a cookie from which to read the caller's bearer token if they don't supply an Authorization header. Reading tokens from cookies is disabled if unset.").String()
+		ccookie  = app.Flag("csrf-cookie", "Name of the cookie used to protect callers who authenticate using a token cookie from cross-site request forgery.").Default(auth.DefaultCSRFCookie).String()
+		planes   = app.Flag("control-planes", "Path to a kubeconfig file with a context for each control plane to serve. Requests must identify a control plane (context) using the "+proxy.HeaderControlPlane+" header or the "+proxy.QueryControlPlane+" query parameter. Proxy mode is disabled if unset.").ExistingFile()
+		demo     = app.Flag("demo", "Serve an embedded dataset of representative Crossplane resources instead of a control plane. No cluster is required. Every caller may do anything, and writes are kept in memory. Takes precedence over --control-planes.").Bool()
+		nointro  = app.Flag("disable-introspection", "Disable GraphQL schema introspection, and do not serve the schema at /schema.graphql.").Bool()
+		allow    = app.Flag("operation-allowlist", "Path to a file listing the only GraphQL operations that may be executed, one per line. Operations are identified by the hex encoded SHA-256 hash of their document, or by name. All operations are allowed if unset.").ExistingFile()
+		maxBody  = app.Flag("max-body-size", "Maximum size in bytes of a request body. Zero disables the limit.").Default(strconv.Itoa(sizelimit.DefaultMaxBodySize)).Int64()
+		maxVars  = app.Flag("max-variables-size", "Maximum size in bytes of the variables of a GraphQL operation, encoded as JSON. Zero disables the limit.").Default(strconv.Itoa(sizelimit.DefaultMaxVariablesSize)).Int()
+		mrKinds  = app.Flag("managed-resource-kind", "A kind of resource that should be treated as a managed resource, as group/Kind or group/version/Kind. Segments may be '*', and a group of the form '*.example.org' matches its subdomains. May be repeated.").Strings()
+		pcKinds  = app.Flag("provider-config-kind", "A kind of resource that should be treated as a provider config, in the same form as --managed-resource-kind. Takes precedence over managed resource kinds. May be repeated.").Strings()
+		shared   = app.Flag("shared-cache-url", "URL of a Redis server in which to cache persisted queries and responses, shared by all replicas, as redis://[[username]:password@]host[:port][/database]. Use the rediss scheme to connect using TLS. Shared caching is disabled if unset.").String()
+		maxQs    = app.Flag("max-inflight-queries", "Maximum number of GraphQL queries served concurrently. Further queries are rejected with a RETRYABLE error. Mutations and subscriptions are never rejected. Zero disables the limit.").Default("0").Int()
+		maxHeap  = app.Flag("max-heap-size", "Heap usage in bytes above which new GraphQL queries are rejected with a RETRYABLE error. Zero disables the limit.").Default("0").Uint64()
+		bthresh  = app.Flag("circuit-breaker-threshold", "Number of consecutive API server calls that must fail because the API server is unreachable before a caller's requests fail fast with an UNREACHABLE error. Zero disables the circuit breaker.").Default(strconv.Itoa(clients.DefaultBreakerThreshold)).Int()
+		bcool    = app.Flag("circuit-breaker-cooldown", "How long a caller's requests fail fast once the API server is considered unreachable.").Default(clients.DefaultBreakerCooldown.String()).Duration()
+		rtimeout = app.Flag("http-read-timeout", "Maximum duration for reading an entire request, including its body.").Default("5s").Duration()
+		htimeout = app.Flag("http-read-header-timeout", "Maximum duration for reading a request's headers.").Default("5s").Duration()
+		wtimeout = app.Flag("http-write-timeout", "Maximum duration before timing out writes of a response. Does not apply to websocket connections once established.").Default("10s").Duration()
+		itimeout = app.Flag("http-idle-timeout", "Maximum duration to wait for the next request on a keep-alive connection. Zero uses the read timeout.").Default("0s").Duration()
+		maxHdr   = app.Flag("http-max-header-size", "Maximum size in bytes of a request's headers, including its request line.").Default(strconv.Itoa(http.DefaultMaxHeaderBytes)).Int()
+		keep     = app.Flag("http-keep-alive", "Keep connections alive between requests.").Default("true").Bool()
+		http2    = app.Flag("http2", "Serve HTTP/2 to callers that support it. HTTP/2 is only served over TLS.").Default("true").Bool()
+		mops     = app.Flag("metrics-max-operations", "Maximum number of distinct GraphQL operation names used to label metrics. Further operations are labelled 'other'. Zero disables the limit.").Default("100").Int()
+		mlen     = app.Flag("metrics-max-operation-name-length", "Maximum length of the GraphQL operation names used to label metrics. Longer names are truncated and suffixed with a hash. Zero disables the limit.").Default("64").Int()
+		mkinds   = app.Flag("metrics-max-kinds", "Maximum number of kinds of object for which cache metrics are reported. The kinds with the fewest cached objects are reported together as 'other'. Zero disables the limit.").Default("100").Int()
+		grace    = app.Flag("shutdown-grace-period", "How long to wait for in-flight requests to complete when shutting down.").Default("20s").Duration()
 	)
 	app.Version(version.Version)
 	kingpin.MustParse(app.Parse(os.Args[1:]))
 
 	zl := zap.New(zap.UseDevMode(*debug))
-	log := logging.NewLogrLogger(zl.WithName("xgql"))
+	log := present.ScrubLogger(logging.NewLogrLogger(zl.WithName("xgql")))
 
 	kingpin.FatalIfError(otelruntime.Start(), "cannot add OpenTelemetry runtime instrumentation")
 
@@ -126,18 +147,40 @@ func main() {
 	// TODO(negz): Can we avoid this global? Should we?
 	global.SetMeterProvider(prom.MeterProvider())
 
+	// Spans are sampled if their parent was, so that a trace is either sampled
+	// in its entirety or not at all. Operations with their own ratio are the
+	// exception; see OperationSampler.
+	sampler := trace.ParentBased(trace.TraceIDRatioBased(*ratio))
+	if len(*opRatios) > 0 {
+		ratios := make(map[string]float64, len(*opRatios))
+		for op, r := range *opRatios {
+			f, err := strconv.ParseFloat(r, 64)
+			kingpin.FatalIfError(err, "cannot parse trace ratio of operation %q", op)
+			ratios[op] = f
+		}
+		sampler = opentelemetry.OperationSampler(sampler, ratios)
+	}
+
 	switch *tracer {
 	case "jaeger":
-		// We require the Jaeger agent address to be specified in order
-		// to enable Jaeger for backward compatibility with older xgql
-		// versions that only supported Jaeger.
-		if *agent == nil {
+		// We require the Jaeger agent or collector address to be specified
+		// in order to enable Jaeger for backward compatibility with older
+		// xgql versions that only supported Jaeger.
+		var ep jaeger.EndpointOption
+		switch {
+		case *endpoint != nil:
+			hc := &http.Client{Transport: &headerTransport{wrapped: http.DefaultTransport, headers: *theaders}}
+			ep = jaeger.WithCollectorEndpoint(jaeger.WithEndpoint((*endpoint).String()), jaeger.WithHTTPClient(hc))
+		case *agent != nil:
+			ep = jaeger.WithAgentEndpoint(jaeger.WithAgentHost((*agent).IP.String()), jaeger.WithAgentPort(strconv.Itoa((*agent).Port)))
+		}
+		if ep == nil {
 			break
 		}
 		log.Debug("Enabling Jaeger tracer")
-		exp, err := jaeger.New(jaeger.WithAgentEndpoint(jaeger.WithAgentHost((*agent).IP.String()), jaeger.WithAgentPort(strconv.Itoa((*agent).Port))))
+		exp, err := jaeger.New(ep)
 		kingpin.FatalIfError(err, "cannot create OpenTelemetry Jaeger exporter")
-		tp := trace.NewTracerProvider(trace.WithSampler(trace.ParentBased(trace.TraceIDRatioBased(*ratio))), trace.WithResource(res), trace.WithBatcher(exp))
+		tp := trace.NewTracerProvider(trace.WithSampler(sampler), trace.WithResource(res), trace.WithBatcher(exp))
 		defer func() {
 			kingpin.FatalIfError(tp.Shutdown(context.Background()), "cannot shutdown Jaeger exporter")
 		}()
@@ -146,7 +189,7 @@ func main() {
 		log.Debug("Enabling GCP tracer")
 		exp, err := google.New()
 		kingpin.FatalIfError(err, "cannot create OpenTelemetry GCP exporter")
-		tp := trace.NewTracerProvider(trace.WithSampler(trace.ParentBased(trace.TraceIDRatioBased(*ratio))), trace.WithResource(res), trace.WithBatcher(exp))
+		tp := trace.NewTracerProvider(trace.WithSampler(sampler), trace.WithResource(res), trace.WithBatcher(exp))
 		defer func() {
 			kingpin.FatalIfError(tp.Shutdown(context.Background()), "cannot shutdown GCP exporter")
 		}()
@@ -163,78 +206,304 @@ func main() {
 	// need) or the cache expires.
 	utilruntime.ErrorHandlers = []func(error){func(err error) { log.Debug("Kubernetes runtime error", "err", err) }}
 
+	// Kinds of resource that xgql's built in type detection misses may be
+	// configured as provider configs or managed resources.
+	for _, k := range *pcKinds {
+		p, err := xgql.ParseKindPattern(k)
+		kingpin.FatalIfError(err, "cannot parse provider config kind")
+		xgql.RegisterResourceConverterPattern(p, xgql.ProviderConfigConverter)
+	}
+	for _, k := range *mrKinds {
+		p, err := xgql.ParseKindPattern(k)
+		kingpin.FatalIfError(err, "cannot parse managed resource kind")
+		xgql.RegisterResourceConverterPattern(p, xgql.ManagedResourceConverter)
+	}
+
+	// A nil watcher's configuration is nil, which causes all configurable
+	// values to fall back to their flags.
+	var xcfg *config.Watcher
+	if *cfgFile != "" {
+		xcfg, err = config.NewWatcher(*cfgFile, config.WithReloadInterval(*reload), config.WithLogger(log))
+		kingpin.FatalIfError(err, "cannot load configuration file")
+	}
+
+	// Features may be enabled or disabled by flags, which are overridden by
+	// the configuration file.
+	fs := feature.Set{}
+	for _, f := range *enable {
+		fs[f] = true
+	}
+	for _, f := range *disable {
+		fs[f] = false
+	}
+	flags := feature.FlagsFn(func(f string) bool {
+		if e, ok := xcfg.Get().Feature(f); ok {
+			return e
+		}
+		return fs.Enabled(f)
+	})
+
+	// Callers' authentication is configured by flags, which are overridden by
+	// the configuration file. It's only read at startup.
+	tc := xcfg.Get().TokenCookie(*tcookie)
+	cc := xcfg.Get().CSRFCookie(*ccookie)
+
+	// Subscriptions are served over long-lived websocket connections that
+	// would never drain, so we close them as soon as we start shutting down.
+	// Clients are expected to reconnect, presumably to another replica.
+	subs, closeSubs := context.WithCancel(context.Background())
+	defer closeSubs()
+
 	rt := chi.NewRouter()
-	rt.Use(middleware.RequestLogger(&formatter{log}))
+	rt.Use(closeWebsockets(subs))
+	rt.Use(middleware.RequestID)
+	rt.Use(middleware.RequestLogger(&formatter{log: log, cookie: tc}))
 	rt.Use(middleware.Compress(5)) // Chi recommends compression level 5.
-	rt.Use(auth.Middleware)
 	rt.Use(version.Middleware)
 
-	s := runtime.NewScheme()
-	kingpin.FatalIfError(corev1.AddToScheme(s), "cannot add Kubernetes core/v1 to scheme")
-	kingpin.FatalIfError(kextv1.AddToScheme(s), "cannot add Kubernetes apiextensions/v1 to scheme")
-	kingpin.FatalIfError(pkgv1.AddToScheme(s), "cannot add Crossplane pkg/v1 to scheme")
-	kingpin.FatalIfError(extv1.AddToScheme(s), "cannot add Crossplane apiextensions/v1 to scheme")
-	kingpin.FatalIfError(appsv1.AddToScheme(s), "cannot add Kubernetes apps/v1 to scheme")
-	kingpin.FatalIfError(rbacv1.AddToScheme(s), "cannot add Kubernetes rbac/v1 to scheme")
-
-	cfg, err := clients.Config()
-	kingpin.FatalIfError(err, "cannot create client config")
-
-	// Our Kubernetes clients need to know what REST API resources are offered
-	// by the API server. The discovery process takes a few ms and makes many
-	// API server calls. Kubernetes allows any authenticated user to access the
-	// discovery API via the system:discovery ClusterRoleBinding, so we create
-	// a global REST mapper using our own credentials for all clients to share.
-	// Discovery happens once at startup, and then once any time a client asks
-	// for an unknown kind of API resource (subject to caching/rate limiting).
-	rm, err := clients.RESTMapper(cfg)
-	kingpin.FatalIfError(err, "cannot create REST mapper")
-
-	ca := clients.NewCache(s,
-		clients.Anonymize(cfg),
-		clients.WithRESTMapper(rm),
-		clients.DoNotCache(noCache),
-		clients.WithLogger(log),
-	)
-	srv := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{Resolvers: resolvers.New(ca)}))
-	srv.SetErrorPresenter(present.Error)
-	srv.Use(opentelemetry.MetricEmitter{})
-	srv.Use(opentelemetry.Tracer{})
-	srv.Use(apollotracing.Tracer{})
+	hopts := []xgql.Option{
+		xgql.WithLogger(log),
+		xgql.WithCrossplaneNamespace(*xpns),
+		xgql.WithCacheExpiry(xcfg.Get().CacheExpiry(clients.DefaultExpiry)),
+		xgql.WithStrippedMetadata(*cstrip),
+		xgql.WithMaxCachedObjectSize(*cmax),
+		xgql.WithWatchBuffer(xcfg.Get().WatchBuffer(*wbuffer)),
+		xgql.WithWatchOverflow(xcfg.Get().WatchOverflow(xgql.OverflowPolicy(*overflow))),
+		xgql.WithFeatures(flags),
+		xgql.WithErrorRedaction(xgql.RedactionPolicy(*redact)),
+		xgql.WithNestedLimit(func() int { return xcfg.Get().NestedLimit(*nlimit) }),
+		xgql.WithConcurrency(func() int { return xcfg.Get().Concurrency(*conc) }),
+		xgql.WithMaxBodySize(*maxBody),
+		xgql.WithMaxVariablesSize(*maxVars),
+		xgql.WithLoadShedding(*maxQs, *maxHeap),
+		xgql.WithCircuitBreaker(*bthresh, *bcool),
+		xgql.WithMetricLimits(*mops, *mlen, *mkinds),
+	}
+	if tc != "" {
+		hopts = append(hopts, xgql.WithTokenCookie(tc, cc))
+	}
+	if *nointro {
+		hopts = append(hopts, xgql.WithIntrospection(false))
+	}
+	if *allow != "" {
+		entries, err := allowlist.Load(*allow)
+		kingpin.FatalIfError(err, "cannot load operation allowlist")
+		log.Debug("Enabling operation allowlist", "entries", len(entries))
+		hopts = append(hopts, xgql.WithOperationAllowlist(entries...))
+	}
+	if *shared != "" {
+		sc, err := sharedcache.NewRedis(*shared)
+		kingpin.FatalIfError(err, "cannot create shared cache")
+		defer sc.Close() //nolint:errcheck // Nothing to do about this; we're shutting down.
+		hopts = append(hopts, xgql.WithSharedCache(sc))
+	}
+
+	// Each control plane we serve has its own REST mapper, client cache, and
+	// GraphQL server.
+	newBackend := func(_ string, cfg *rest.Config) (proxy.Backend, error) {
+		h, err := xgql.NewHandler(cfg, hopts...)
+		if err != nil {
+			return nil, err
+		}
+		return h, nil
+	}
+
+	var query proxy.Backend
+	switch {
+	case *demo:
+		// In demo mode we serve fixtures, e.g. for frontend development or
+		// hermetic end-to-end tests, so we don't need a control plane.
+		log.Info("Serving demo data; no control plane is connected")
+		query, err = xgql.NewDemoHandler(hopts...)
+		kingpin.FatalIfError(err, "cannot create demo GraphQL server")
+	case *planes != "":
+		// In proxy mode each request must identify the control plane it is
+		// intended for, which must be a context of the supplied kubeconfig.
+		cfgs, err := clients.ControlPlaneConfigs(*planes)
+		kingpin.FatalIfError(err, "cannot load control plane configurations")
+		log.Debug("Enabling proxy mode", "control-planes", len(cfgs))
+		query = proxy.NewRouter(cfgs, newBackend, proxy.WithLogger(log))
+	default:
+		cfg, err := clients.Config()
+		kingpin.FatalIfError(err, "cannot create client config")
+		query, err = newBackend("", cfg)
+		kingpin.FatalIfError(err, "cannot create GraphQL server")
+	}
 
-	rt.Handle("/query", otelhttp.NewHandler(srv, "/query"))
+	rt.Handle("/query", otelhttp.NewHandler(query, "/query"))
 	rt.Handle("/metrics", prom)
 	rt.Handle("/version", version.Handler())
+	if !*nointro {
+		rt.Handle("/schema.graphql", schema.Handler())
+	}
 	if *play {
 		rt.Handle("/", playground.Handler("GraphQL playground", "/query"))
 	}
 
 	h := http.Server{
 		Handler:           rt,
-		WriteTimeout:      10 * time.Second,
-		ReadTimeout:       5 * time.Second,
-		ReadHeaderTimeout: 5 * time.Second,
+		WriteTimeout:      *wtimeout,
+		ReadTimeout:       *rtimeout,
+		ReadHeaderTimeout: *htimeout,
+		IdleTimeout:       *itimeout,
+		MaxHeaderBytes:    *maxHdr,
 		ErrorLog:          stdlog.New(ioutil.Discard, "", 0),
 	}
+	if !*http2 {
+		// A non-nil, empty map disables the server's automatic HTTP/2 support.
+		h.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+	}
+	h.SetKeepAlivesEnabled(*keep)
+	h.RegisterOnShutdown(closeSubs)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if xcfg != nil {
+		go xcfg.Watch(ctx)
+	}
 
 	if *tlsCert != "" && *tlsKey != "" {
+		l, err := net.Listen("tcp", *listen)
+		kingpin.FatalIfError(err, "cannot listen for TLS connections")
+		log.Debug("Listening for TLS connections", "address", *listen)
+		go func() {
+			if err := h.ServeTLS(l, *tlsCert, *tlsKey); !errors.Is(err, http.ErrServerClosed) {
+				kingpin.FatalIfError(err, "cannot serve TLS HTTP")
+			}
+		}()
+	}
+
+	var dh *http.Server
+	if *dlisten != "" {
+		dl, err := net.Listen("tcp", *dlisten)
+		kingpin.FatalIfError(err, "cannot listen for debug connections")
+		log.Debug("Listening for debug connections", "address", *dlisten)
+
+		// Profiles may take longer than our usual write timeout to capture.
+		dh = &http.Server{
+			Handler:           debugHandler(),
+			ReadHeaderTimeout: 5 * time.Second,
+			ErrorLog:          stdlog.New(ioutil.Discard, "", 0),
+		}
 		go func() {
-			log.Debug("Listening for TLS connections", "address", *listen)
-			h.Addr = *listen
-			kingpin.FatalIfError(h.ListenAndServeTLS(*tlsCert, *tlsKey), "cannot serve TLS HTTP")
+			if err := dh.Serve(dl); !errors.Is(err, http.ErrServerClosed) {
+				kingpin.FatalIfError(err, "cannot serve debug HTTP")
+			}
 		}()
 	}
 
+	l, err := net.Listen("tcp", *insecure)
+	kingpin.FatalIfError(err, "cannot listen for insecure connections")
 	log.Debug("Listening for insecure connections", "address", *insecure)
-	h.Addr = *insecure
-	kingpin.FatalIfError(h.ListenAndServe(), "cannot serve insecure HTTP")
+	go func() {
+		if err := h.Serve(l); !errors.Is(err, http.ErrServerClosed) {
+			kingpin.FatalIfError(err, "cannot serve insecure HTTP")
+		}
+	}()
+
+	<-ctx.Done()
+
+	// Restore the default signal handling so that a second signal terminates
+	// us immediately.
+	stop()
+
+	// Shutdown stops accepting new connections, closes subscriptions, then
+	// waits for in-flight queries and mutations to complete.
+	log.Debug("Shutting down", "grace-period", *grace)
+	sctx, cancel := context.WithTimeout(context.Background(), *grace)
+	defer cancel()
+	if err := h.Shutdown(sctx); err != nil {
+		log.Info("Shut down before all in-flight requests completed", "error", err)
+	}
+
+	// The debug server is shut down last so that in-flight requests may be
+	// profiled while they complete.
+	if dh != nil {
+		if err := dh.Shutdown(sctx); err != nil {
+			log.Info("Shut down debug server before all in-flight requests completed", "error", err)
+		}
+	}
+
+	// Stop our client caches, and thus their informers.
+	query.Stop()
+	log.Debug("Shut down")
 }
 
-type formatter struct{ log logging.Logger }
+// debugHandler serves pprof profiles and expvar variables. We don't use the
+// http.DefaultServeMux these packages register with in order to ensure they're
+// never served by our main listeners.
+func debugHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	return mux
+}
+
+// closeWebsockets returns middleware that closes websocket connections when
+// the supplied context is done, by cancelling their request contexts. Other
+// requests are unaffected.
+func closeWebsockets(done context.Context) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithCancel(r.Context())
+			defer cancel()
+			go func() {
+				select {
+				case <-done.Done():
+					cancel()
+				case <-ctx.Done():
+				}
+			}()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// A headerTransport adds headers to each request it sends.
+type headerTransport struct {
+	wrapped http.RoundTripper
+	headers map[string]string
+}
+
+func (t *headerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if len(t.headers) == 0 {
+		return t.wrapped.RoundTrip(r)
+	}
+	r = r.Clone(r.Context())
+	for k, v := range t.headers {
+		r.Header.Set(k, v)
+	}
+	return t.wrapped.RoundTrip(r)
+}
+
+type formatter struct {
+	log    logging.Logger
+	cookie string
+}
 
 func (f *formatter) NewLogEntry(r *http.Request) middleware.LogEntry {
+	// Requests are logged before they're authenticated, so we determine the
+	// caller's credentials here. We log a fingerprint of the credentials so
+	// that requests from the same caller can be correlated without logging
+	// the credentials themselves.
+	cr := auth.FromRequest(r)
+	if c, err := r.Cookie(f.cookie); err == nil && f.cookie != "" && cr.BearerToken == "" {
+		cr.BearerToken = c.Value
+	}
 	return &entry{log: f.log.WithValues(
 		"id", middleware.GetReqID(r.Context()),
+		"identity", cr.Fingerprint(),
 		"method", r.Method,
 		"tls", r.TLS != nil,
 		"host", r.Host,
@@ -0,0 +1,126 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xgql
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+
+	"github.com/upbound/xgql/internal/auth"
+)
+
+// Credentials identify the caller of a GraphQL operation.
+type Credentials = auth.Credentials
+
+// A Field that is being resolved.
+type Field struct {
+	// Object is the name of the GraphQL type whose field is being resolved,
+	// e.g. Provider.
+	Object string
+
+	// Name of the field being resolved, e.g. metadata.
+	Name string
+
+	// Args supplied to the field, if any.
+	Args map[string]interface{}
+
+	// Parent is the resolved object whose field is being resolved, if any,
+	// e.g. a *model.Provider. Fields of the Query type have no parent.
+	Parent interface{}
+
+	// Caller that supplied the operation being resolved.
+	Caller Credentials
+}
+
+// A ResolverHook is called around the resolution of every GraphQL field, for
+// example in order to enforce custom authorization, emit metrics, or filter
+// fields. Either function may be nil.
+type ResolverHook struct {
+	// Before is called before a field is resolved. The field is not resolved
+	// if it returns an error, which is returned instead.
+	Before func(ctx context.Context, f Field) error
+
+	// After is called after a field is resolved, with its result and any error
+	// that occurred. Whatever it returns replaces the field's result.
+	After func(ctx context.Context, f Field, res interface{}, err error) (interface{}, error)
+}
+
+// WithResolverHooks configures hooks that are called around the resolution of
+// every GraphQL field, in the order they're supplied. Hooks are not called for
+// fields of introspection types.
+func WithResolverHooks(h ...ResolverHook) Option {
+	return func(o *options) {
+		o.hooks = append(o.hooks, h...)
+	}
+}
+
+// hooks is a GraphQL server extension that calls resolver hooks.
+type hooks []ResolverHook
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.FieldInterceptor
+} = hooks{}
+
+// ExtensionName returns the name of this extension.
+func (h hooks) ExtensionName() string {
+	return "ResolverHooks"
+}
+
+// Validate this extension (a no-op).
+func (h hooks) Validate(_ graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptField calls each hook around the resolution of a field.
+func (h hooks) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || isIntrospection(fc.Object) {
+		return next(ctx)
+	}
+
+	f := Field{Object: fc.Object, Args: fc.Args}
+	if fc.Field.Field != nil {
+		f.Name = fc.Field.Name
+	}
+	if fc.Parent != nil {
+		f.Parent = fc.Parent.Result
+	}
+	f.Caller, _ = auth.FromContext(ctx)
+
+	for _, hk := range h {
+		if hk.Before == nil {
+			continue
+		}
+		if err := hk.Before(ctx, f); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := next(ctx)
+
+	for _, hk := range h {
+		if hk.After == nil {
+			continue
+		}
+		res, err = hk.After(ctx, f, res, err)
+	}
+	return res, err
+}
+
+func isIntrospection(object string) bool {
+	return len(object) > 2 && object[:2] == "__"
+}
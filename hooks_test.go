@@ -0,0 +1,138 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xgql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/upbound/xgql/internal/auth"
+)
+
+func TestHooksInterceptField(t *testing.T) {
+	errBoom := errors.New("boom")
+	creds := auth.Credentials{BearerToken: "toke"}
+
+	field := func(object string) *graphql.FieldContext {
+		return &graphql.FieldContext{
+			Object: object,
+			Field:  graphql.CollectedField{Field: &ast.Field{Name: "cool"}},
+			Args:   map[string]interface{}{"arg": "value"},
+		}
+	}
+	wantField := Field{
+		Object: "Provider",
+		Name:   "cool",
+		Args:   map[string]interface{}{"arg": "value"},
+		Parent: "parent",
+		Caller: creds,
+	}
+
+	type args struct {
+		fc   *graphql.FieldContext
+		next graphql.Resolver
+	}
+	type want struct {
+		res    interface{}
+		err    error
+		called bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		h      hooks
+		args   args
+		want   want
+	}{
+		"BeforeError": {
+			reason: "A field should not be resolved if a before hook returns an error.",
+			h: hooks{
+				{Before: func(_ context.Context, f Field) error {
+					if diff := cmp.Diff(wantField, f); diff != "" {
+						t.Errorf("Before(...): -want field, +got field:\n%s", diff)
+					}
+					return errBoom
+				}},
+			},
+			args: args{
+				fc:   field("Provider"),
+				next: func(_ context.Context) (interface{}, error) { return "result", nil },
+			},
+			want: want{err: errBoom},
+		},
+		"AfterReplacesResult": {
+			reason: "After hooks should be called in order, each replacing the result of the last.",
+			h: hooks{
+				{After: func(_ context.Context, f Field, res interface{}, err error) (interface{}, error) {
+					if diff := cmp.Diff(wantField, f); diff != "" {
+						t.Errorf("After(...): -want field, +got field:\n%s", diff)
+					}
+					return res.(string) + "-filtered", err
+				}},
+				{After: func(_ context.Context, _ Field, res interface{}, _ error) (interface{}, error) {
+					return res.(string) + "-again", errBoom
+				}},
+			},
+			args: args{
+				fc:   field("Provider"),
+				next: func(_ context.Context) (interface{}, error) { return "result", nil },
+			},
+			want: want{res: "result-filtered-again", err: errBoom, called: true},
+		},
+		"Introspection": {
+			reason: "Hooks should not be called for fields of introspection types.",
+			h: hooks{
+				{Before: func(_ context.Context, _ Field) error { return errBoom }},
+			},
+			args: args{
+				fc:   field("__Type"),
+				next: func(_ context.Context) (interface{}, error) { return "result", nil },
+			},
+			want: want{res: "result", called: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			called := false
+			next := func(ctx context.Context) (interface{}, error) {
+				called = true
+				return tc.args.next(ctx)
+			}
+
+			ctx := auth.WithCredentials(context.Background(), creds)
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{Object: "Query", Result: "parent"})
+			ctx = graphql.WithFieldContext(ctx, tc.args.fc)
+			res, err := tc.h.InterceptField(ctx, next)
+
+			if diff := cmp.Diff(tc.want.res, res); diff != "" {
+				t.Errorf("\n%s\nh.InterceptField(...): -want result, +got result:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nh.InterceptField(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.called, called); diff != "" {
+				t.Errorf("\n%s\nh.InterceptField(...): -want called, +got called:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
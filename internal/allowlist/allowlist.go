@@ -0,0 +1,140 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package allowlist restricts the GraphQL operations xgql will execute to an
+// allowlist of known operations, so that a public-facing xgql serves only the
+// operations its clients are known to need.
+package allowlist
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/pkg/errors"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	errReadFile = "cannot read operation allowlist"
+	errDenied   = "operation is not allowlisted"
+)
+
+// Hashes are the hex encoded SHA-256 hashes of GraphQL documents, i.e. the
+// hashes used by automatic persisted queries.
+var hash = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// An Allowlist of GraphQL operations. Operations are allowed by the SHA-256
+// hash of their GraphQL document, or by name. Names are chosen by callers, so
+// allowing operations by name does not prevent a caller from executing an
+// arbitrary query; it only requires them to know an allowed name.
+type Allowlist struct {
+	hashes map[string]bool
+	names  map[string]bool
+}
+
+// New returns an allowlist of the supplied entries. Each entry is either the
+// hex encoded SHA-256 hash of a GraphQL document or the name of an operation.
+func New(entries ...string) *Allowlist {
+	a := &Allowlist{hashes: map[string]bool{}, names: map[string]bool{}}
+	for _, e := range entries {
+		e = strings.TrimSpace(e)
+		switch {
+		case e == "":
+			continue
+		case hash.MatchString(e):
+			a.hashes[e] = true
+		default:
+			a.names[e] = true
+		}
+	}
+	return a
+}
+
+// Load the allowlist entries from the supplied file, which contains one entry
+// per line. Blank lines and lines starting with # are ignored.
+func Load(path string) ([]string, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrap(err, errReadFile)
+	}
+	defer f.Close() //nolint:errcheck // Nothing useful to do with this error.
+
+	out := make([]string, 0)
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		l := strings.TrimSpace(s.Text())
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		out = append(out, l)
+	}
+	return out, errors.Wrap(s.Err(), errReadFile)
+}
+
+// Hash returns the hex encoded SHA-256 hash of the supplied GraphQL document.
+func Hash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+// Allows returns true if the supplied GraphQL document, or the supplied
+// operation name, is allowed.
+func (a *Allowlist) Allows(query, operation string) bool {
+	if operation != "" && a.names[operation] {
+		return true
+	}
+	return a.hashes[Hash(query)]
+}
+
+// Gate is a GraphQL server extension that refuses to execute operations that
+// are not allowlisted.
+type Gate struct {
+	Allowlist *Allowlist
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = Gate{}
+
+// ExtensionName returns the name of this extension.
+func (g Gate) ExtensionName() string {
+	return "OperationAllowlist"
+}
+
+// Validate this extension (a no-op).
+func (g Gate) Validate(_ graphql.ExecutableSchema) error {
+	return nil
+}
+
+// MutateOperationContext returns an error if the operation is not allowlisted.
+// Operations that are sent as automatic persisted queries are checked using
+// the document they refer to.
+func (g Gate) MutateOperationContext(_ context.Context, oc *graphql.OperationContext) *gqlerror.Error {
+	name := oc.OperationName
+	if name == "" && oc.Operation != nil {
+		name = oc.Operation.Name
+	}
+	if g.Allowlist.Allows(oc.RawQuery, name) {
+		return nil
+	}
+	return gqlerror.Errorf(errDenied)
+}
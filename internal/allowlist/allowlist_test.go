@@ -0,0 +1,86 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package allowlist
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const query = "query Providers { providers { totalCount } }"
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "allowlist")
+	content := "# Our dashboard's queries.\n\n" + Hash(query) + "\n  Providers  \n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("os.WriteFile(...): %s", err)
+	}
+
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load(...): %s", err)
+	}
+	if diff := cmp.Diff([]string{Hash(query), "Providers"}, got); diff != "" {
+		t.Errorf("Load(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestGateMutateOperationContext(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		a      *Allowlist
+		oc     *graphql.OperationContext
+		want   *gqlerror.Error
+	}{
+		"AllowedByHash": {
+			reason: "An operation whose document's hash is allowlisted should be allowed.",
+			a:      New(Hash(query)),
+			oc:     &graphql.OperationContext{RawQuery: query},
+		},
+		"AllowedByName": {
+			reason: "An operation whose name is allowlisted should be allowed.",
+			a:      New("Providers"),
+			oc:     &graphql.OperationContext{RawQuery: query, OperationName: "Providers"},
+		},
+		"AllowedByDocumentName": {
+			reason: "An operation should be identified by the name in its document if none was supplied.",
+			a:      New("Providers"),
+			oc:     &graphql.OperationContext{RawQuery: query, Operation: &ast.OperationDefinition{Name: "Providers"}},
+		},
+		"Denied": {
+			reason: "An operation that is not allowlisted should be denied.",
+			a:      New("Configurations", Hash("query { configurations { totalCount } }")),
+			oc:     &graphql.OperationContext{RawQuery: query, OperationName: "Providers"},
+			want:   gqlerror.Errorf(errDenied),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Gate{Allowlist: tc.a}.MutateOperationContext(context.Background(), tc.oc)
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreUnexported(gqlerror.Error{})); diff != "" {
+				t.Errorf("\n%s\nMutateOperationContext(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ package auth
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/sha256"
 	"fmt"
 	"net/http"
@@ -96,6 +97,28 @@ func (c Credentials) Hash(extra []byte) string {
 	return fmt.Sprintf("%x", h.Sum(nil))
 }
 
+// fingerprintSalt is hashed with credentials to produce their fingerprint, so
+// that fingerprints can't be matched to known credentials. It's random, so
+// fingerprints may only be compared within a single xgql process.
+var fingerprintSalt = func() []byte {
+	salt := make([]byte, 32)
+	_, _ = rand.Read(salt)
+	return salt
+}()
+
+// fingerprintLength is the number of hex characters in a fingerprint.
+const fingerprintLength = 12
+
+// Fingerprint returns a short, salted hash of the supplied credentials. It may
+// be logged to correlate requests made using the same credentials without
+// revealing them. Anonymous credentials have an empty fingerprint.
+func (c Credentials) Fingerprint() string {
+	if c.BearerToken == "" && c.BasicUsername == "" && c.BasicPassword == "" {
+		return ""
+	}
+	return c.Hash(fingerprintSalt)[:fingerprintLength]
+}
+
 // ExtractBearerToken (if any) from the supplied request.
 func ExtractBearerToken(r *http.Request) string {
 	h := strings.Split(r.Header.Get(headerAuthn), " ")
@@ -126,17 +149,22 @@ func ExtractImpersonation(r *http.Request) Impersonation {
 	return i
 }
 
+// FromRequest extracts credentials from the headers of the supplied request.
+func FromRequest(r *http.Request) Credentials {
+	bu, bp, _ := r.BasicAuth()
+	return Credentials{
+		BasicUsername: bu,
+		BasicPassword: bp,
+		BearerToken:   ExtractBearerToken(r),
+		Impersonate:   ExtractImpersonation(r),
+	}
+}
+
 // Middleware extracts credentials from the HTTP request and stashes them in its
 // context.
 func Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		bu, bp, _ := r.BasicAuth()
-		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), key, Credentials{
-			BasicUsername: bu,
-			BasicPassword: bp,
-			BearerToken:   ExtractBearerToken(r),
-			Impersonate:   ExtractImpersonation(r),
-		})))
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), key, FromRequest(r))))
 	})
 }
 
@@ -145,3 +173,9 @@ func FromContext(ctx context.Context) (Credentials, bool) {
 	c, ok := ctx.Value(key).(Credentials)
 	return c, ok
 }
+
+// WithCredentials returns a copy of the supplied context with the supplied
+// credentials stashed in it.
+func WithCredentials(ctx context.Context, c Credentials) context.Context {
+	return context.WithValue(ctx, key, c)
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -114,6 +115,24 @@ func TestCredentialsHash(t *testing.T) {
 
 }
 
+func TestCredentialsFingerprint(t *testing.T) {
+	a := Credentials{BearerToken: "toke-one"}
+	b := Credentials{BearerToken: "toke-two"}
+
+	if got := (Credentials{}).Fingerprint(); got != "" {
+		t.Errorf("c.Fingerprint(): want empty fingerprint for anonymous credentials, got %q", got)
+	}
+	if got := a.Fingerprint(); len(got) != fingerprintLength || strings.Contains(got, a.BearerToken) {
+		t.Errorf("c.Fingerprint(): want %d character fingerprint that does not contain the token, got %q", fingerprintLength, got)
+	}
+	if a.Fingerprint() != a.Fingerprint() {
+		t.Errorf("c.Fingerprint(): want the same fingerprint for the same credentials")
+	}
+	if a.Fingerprint() == b.Fingerprint() {
+		t.Errorf("c.Fingerprint(): want different fingerprints for different credentials")
+	}
+}
+
 func TestMiddleware(t *testing.T) {
 	token := "toke-one"
 
@@ -0,0 +1,105 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// DefaultCSRFCookie is the default name of the cookie used for CSRF protection.
+const DefaultCSRFCookie = "xgql-csrf"
+
+// CSRF double-submit protection.
+const (
+	headerCSRF     = "X-CSRF-Token"
+	csrfTokenBytes = 32
+
+	errCSRF = "missing or invalid " + headerCSRF + " header"
+)
+
+// CookieMiddleware extracts a bearer token from the supplied cookie and stashes
+// it in the request's context, unless the request's Authorization header
+// already supplied a bearer token. It must be used after Middleware.
+//
+// Browsers send cookies with cross-site requests, so a request authenticated
+// by cookie must also prove it originated from a page that can read the
+// supplied CSRF cookie, by echoing the cookie's value in the X-CSRF-Token
+// header. Such requests are otherwise rejected, except websocket upgrades,
+// which can't set headers and must instead supply credentials in their init
+// payload. The CSRF cookie is set if the request did not include it.
+func CookieMiddleware(token, csrf string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ensureCSRFCookie(w, r, csrf)
+
+			c, _ := FromContext(r.Context())
+			t, err := r.Cookie(token)
+			if c.BearerToken != "" || err != nil || t.Value == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !validCSRF(r, csrf) {
+				if isWebsocketUpgrade(r) {
+					next.ServeHTTP(w, r)
+					return
+				}
+				http.Error(w, errCSRF, http.StatusForbidden)
+				return
+			}
+
+			c.BearerToken = t.Value
+			next.ServeHTTP(w, r.WithContext(WithCredentials(r.Context(), c)))
+		})
+	}
+}
+
+// ensureCSRFCookie sets a random CSRF cookie if the request did not include
+// one. The cookie must be readable by JavaScript, so it is not HTTP only.
+func ensureCSRFCookie(w http.ResponseWriter, r *http.Request, csrf string) {
+	if c, err := r.Cookie(csrf); err == nil && c.Value != "" {
+		return
+	}
+	b := make([]byte, csrfTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		// Callers will be unable to authenticate by cookie until they
+		// receive a CSRF cookie, which we'll try to set again next time.
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     csrf,
+		Value:    hex.EncodeToString(b),
+		Path:     "/",
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+// validCSRF returns true if the request's CSRF header matches its CSRF cookie.
+func validCSRF(r *http.Request, csrf string) bool {
+	c, err := r.Cookie(csrf)
+	if err != nil || c.Value == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(c.Value), []byte(r.Header.Get(headerCSRF))) == 1
+}
+
+func isWebsocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
@@ -0,0 +1,132 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCookieMiddleware(t *testing.T) {
+	const (
+		tokenCookie = "token"
+		csrfCookie  = "csrf"
+	)
+
+	type want struct {
+		code  int
+		token string
+		csrf  bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		r      func() *http.Request
+		want   want
+	}{
+		"NoCookie": {
+			reason: "A request without a token cookie should be passed through, and sent a CSRF cookie.",
+			r: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/query", nil)
+			},
+			want: want{code: http.StatusOK, csrf: true},
+		},
+		"AuthorizationHeader": {
+			reason: "A bearer token supplied by the Authorization header should take precedence over the cookie.",
+			r: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/query", nil)
+				r.Header.Set(headerAuthn, "Bearer header")
+				r.AddCookie(&http.Cookie{Name: tokenCookie, Value: "cookie"})
+				r.AddCookie(&http.Cookie{Name: csrfCookie, Value: "abc"})
+				return r
+			},
+			want: want{code: http.StatusOK, token: "header"},
+		},
+		"ValidCSRF": {
+			reason: "A request whose CSRF header matches its CSRF cookie should be authenticated by its token cookie.",
+			r: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/query", nil)
+				r.Header.Set(headerCSRF, "abc")
+				r.AddCookie(&http.Cookie{Name: tokenCookie, Value: "cookie"})
+				r.AddCookie(&http.Cookie{Name: csrfCookie, Value: "abc"})
+				return r
+			},
+			want: want{code: http.StatusOK, token: "cookie"},
+		},
+		"InvalidCSRF": {
+			reason: "A request whose CSRF header does not match its CSRF cookie should be rejected.",
+			r: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/query", nil)
+				r.Header.Set(headerCSRF, "xyz")
+				r.AddCookie(&http.Cookie{Name: tokenCookie, Value: "cookie"})
+				r.AddCookie(&http.Cookie{Name: csrfCookie, Value: "abc"})
+				return r
+			},
+			want: want{code: http.StatusForbidden},
+		},
+		"MissingCSRFCookie": {
+			reason: "A request without a CSRF cookie should be rejected, and sent a CSRF cookie.",
+			r: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/query", nil)
+				r.AddCookie(&http.Cookie{Name: tokenCookie, Value: "cookie"})
+				return r
+			},
+			want: want{code: http.StatusForbidden, csrf: true},
+		},
+		"WebsocketUpgrade": {
+			reason: "A websocket upgrade that fails CSRF validation should be passed through without using its token cookie.",
+			r: func() *http.Request {
+				r := httptest.NewRequest(http.MethodGet, "/query", nil)
+				r.Header.Set("Upgrade", "websocket")
+				r.AddCookie(&http.Cookie{Name: tokenCookie, Value: "cookie"})
+				r.AddCookie(&http.Cookie{Name: csrfCookie, Value: "abc"})
+				return r
+			},
+			want: want{code: http.StatusOK},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			token := ""
+			h := Middleware(CookieMiddleware(tokenCookie, csrfCookie)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				c, _ := FromContext(r.Context())
+				token = c.BearerToken
+			})))
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, tc.r())
+
+			if diff := cmp.Diff(tc.want.code, w.Code); diff != "" {
+				t.Errorf("\n%s\nServeHTTP(...): -want status code, +got status code:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.token, token); diff != "" {
+				t.Errorf("\n%s\nServeHTTP(...): -want bearer token, +got bearer token:\n%s", tc.reason, diff)
+			}
+			csrf := false
+			for _, c := range w.Result().Cookies() {
+				if c.Name == csrfCookie && c.Value != "" {
+					csrf = true
+				}
+			}
+			if diff := cmp.Diff(tc.want.csrf, csrf); diff != "" {
+				t.Errorf("\n%s\nServeHTTP(...): -want CSRF cookie set, +got CSRF cookie set:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
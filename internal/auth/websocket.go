@@ -0,0 +1,39 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+)
+
+// WebsocketInit extracts a bearer token (if any) from the payload of the init
+// message that starts a GraphQL websocket connection, and stashes it in the
+// supplied context. Web browsers can't set headers when they open a websocket,
+// so they must instead supply credentials in the init payload. A bearer token
+// in the init payload replaces any bearer token that was extracted from the
+// HTTP request that opened the websocket.
+func WebsocketInit(ctx context.Context, p transport.InitPayload) (context.Context, error) {
+	h := strings.Split(p.Authorization(), " ")
+	if len(h) != 2 || h[0] != prefixBearer {
+		return ctx, nil
+	}
+
+	c, _ := FromContext(ctx)
+	c.BearerToken = h[1]
+	return context.WithValue(ctx, key, c), nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWebsocketInit(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		ctx     context.Context
+		payload transport.InitPayload
+		want    Credentials
+	}{
+		"NoToken": {
+			reason:  "Credentials from the HTTP request should be preserved if the payload contains no bearer token.",
+			ctx:     context.WithValue(context.Background(), key, Credentials{BearerToken: "header"}),
+			payload: transport.InitPayload{},
+			want:    Credentials{BearerToken: "header"},
+		},
+		"MalformedToken": {
+			reason:  "Malformed authorization payloads should be ignored.",
+			ctx:     context.Background(),
+			payload: transport.InitPayload{"Authorization": "Basic dXNlcjpwYXNz"},
+			want:    Credentials{},
+		},
+		"Token": {
+			reason:  "A bearer token in the payload should replace any bearer token from the HTTP request.",
+			ctx:     context.WithValue(context.Background(), key, Credentials{BearerToken: "header", BasicUsername: "so"}),
+			payload: transport.InitPayload{"Authorization": "Bearer payload"},
+			want:    Credentials{BearerToken: "payload", BasicUsername: "so"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx, err := WebsocketInit(tc.ctx, tc.payload)
+			if err != nil {
+				t.Fatalf("\n%s\nWebsocketInit(...): %s", tc.reason, err)
+			}
+			got, _ := FromContext(ctx)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nWebsocketInit(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
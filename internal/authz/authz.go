@@ -0,0 +1,248 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package authz gates GraphQL fields annotated with the @requiresVerb directive
+// behind Kubernetes RBAC, so that sensitive fields may only be resolved by
+// callers who are permitted to perform the supplied verb.
+package authz
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/pkg/errors"
+	authv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/upbound/xgql/internal/auth"
+	"github.com/upbound/xgql/internal/clients"
+	"github.com/upbound/xgql/internal/feature"
+	"github.com/upbound/xgql/internal/graph/model"
+)
+
+const directive = "requiresVerb"
+
+const (
+	errGetClient    = "cannot get client"
+	errCreateReview = "cannot create self subject access review"
+
+	errFmtForbidden = "field requires permission to %s %q in API group %q"
+)
+
+// A Reviewer determines whether the caller may perform an action.
+type Reviewer interface {
+	// Review whether the caller may perform the supplied action.
+	Review(ctx context.Context, ra authv1.ResourceAttributes) (bool, error)
+}
+
+// A ReviewerFn determines whether the caller may perform an action.
+type ReviewerFn func(ctx context.Context, ra authv1.ResourceAttributes) (bool, error)
+
+// Review whether the caller may perform the supplied action.
+func (fn ReviewerFn) Review(ctx context.Context, ra authv1.ResourceAttributes) (bool, error) {
+	return fn(ctx, ra)
+}
+
+// A ClientCache can produce a client for a given user.
+type ClientCache interface {
+	// Get a client for the given credentials.
+	Get(cr auth.Credentials, o ...clients.GetOption) (client.Client, error)
+}
+
+// A SelfReviewer determines whether the caller may perform an action by
+// creating a SelfSubjectAccessReview using the caller's credentials.
+type SelfReviewer struct {
+	clients ClientCache
+}
+
+// NewSelfReviewer returns a Reviewer that creates SelfSubjectAccessReviews
+// using clients from the supplied cache.
+func NewSelfReviewer(cc ClientCache) *SelfReviewer {
+	return &SelfReviewer{clients: cc}
+}
+
+// Review whether the caller may perform the supplied action.
+func (r *SelfReviewer) Review(ctx context.Context, ra authv1.ResourceAttributes) (bool, error) {
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		return false, errors.Wrap(err, errGetClient)
+	}
+
+	sar := &authv1.SelfSubjectAccessReview{
+		Spec: authv1.SelfSubjectAccessReviewSpec{ResourceAttributes: &ra},
+	}
+	if err := c.Create(ctx, sar); err != nil {
+		return false, errors.Wrap(err, errCreateReview)
+	}
+	return sar.Status.Allowed, nil
+}
+
+type key int
+
+const memoKey key = iota
+
+// A review that may be shared by many fields of the same operation.
+type review struct {
+	mx      sync.Mutex
+	done    bool
+	allowed bool
+}
+
+// A memo of the reviews made while resolving an operation. Many fields may
+// require the same permission, e.g. the data of each secret in a list. Reviews
+// are keyed by their resource attributes, which include the namespace.
+type memo struct {
+	mx      sync.Mutex
+	reviews map[authv1.ResourceAttributes]*review
+}
+
+func (m *memo) review(ctx context.Context, r Reviewer, ra authv1.ResourceAttributes) (bool, error) {
+	m.mx.Lock()
+	rv, ok := m.reviews[ra]
+	if !ok {
+		rv = &review{}
+		m.reviews[ra] = rv
+	}
+	m.mx.Unlock()
+
+	rv.mx.Lock()
+	defer rv.mx.Unlock()
+	if rv.done {
+		return rv.allowed, nil
+	}
+
+	// We don't memoize errors. They may be transient, e.g. because the
+	// context of the field that made the review was cancelled.
+	allowed, err := r.Review(ctx, ra)
+	if err != nil {
+		return false, err
+	}
+	rv.done, rv.allowed = true, allowed
+	return allowed, nil
+}
+
+// Gate is a GraphQL server extension that returns an error when a field that
+// requires a verb is resolved by a caller who may not perform it. Fields are
+// only gated when the FieldAuthorization feature is enabled.
+type Gate struct {
+	Flags    feature.Flags
+	Reviewer Reviewer
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationInterceptor
+	graphql.FieldInterceptor
+} = Gate{}
+
+// ExtensionName returns the name of this extension.
+func (g Gate) ExtensionName() string {
+	return "FieldAuthorization"
+}
+
+// Validate this extension (a no-op).
+func (g Gate) Validate(_ graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptOperation memoizes the reviews made while resolving an operation.
+func (g Gate) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	return next(context.WithValue(ctx, memoKey, &memo{reviews: make(map[authv1.ResourceAttributes]*review)}))
+}
+
+// InterceptField returns an error if the field being resolved requires a verb
+// the caller may not perform.
+func (g Gate) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	ra, ok := getRequiredVerb(graphql.GetFieldContext(ctx))
+	if !ok || !g.Flags.Enabled(feature.FieldAuthorization) {
+		return next(ctx)
+	}
+
+	m, ok := ctx.Value(memoKey).(*memo)
+	if !ok {
+		// We're not resolving an operation, e.g. because we're being tested.
+		m = &memo{reviews: make(map[authv1.ResourceAttributes]*review)}
+	}
+
+	allowed, err := m.review(ctx, g.Reviewer, ra)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, errors.Errorf(errFmtForbidden, ra.Verb, ra.Resource, ra.Group)
+	}
+	return next(ctx)
+}
+
+// getRequiredVerb returns the action that must be permitted in order to
+// resolve the supplied field, if any. The action is scoped to the namespace of
+// the object whose field is being resolved.
+func getRequiredVerb(fc *graphql.FieldContext) (authv1.ResourceAttributes, bool) {
+	if fc == nil || fc.Field.Field == nil || fc.Field.Definition == nil {
+		return authv1.ResourceAttributes{}, false
+	}
+	d := fc.Field.Definition.Directives.ForName(directive)
+	if d == nil {
+		return authv1.ResourceAttributes{}, false
+	}
+	ra := authv1.ResourceAttributes{Namespace: getNamespace(fc)}
+	for _, a := range d.Arguments {
+		if a.Value == nil {
+			continue
+		}
+		switch a.Name {
+		case "group":
+			ra.Group = a.Value.Raw
+		case "resource":
+			ra.Resource = a.Value.Raw
+		case "verb":
+			ra.Verb = a.Value.Raw
+		}
+	}
+	return ra, true
+}
+
+// getNamespace returns the namespace of the object whose field is being
+// resolved. It returns an empty string, i.e. all namespaces, if the object is
+// cluster scoped or has no metadata.
+func getNamespace(fc *graphql.FieldContext) string {
+	if fc.Parent == nil {
+		return ""
+	}
+
+	// The parent field's result is the object whose field is being resolved,
+	// e.g. a *model.Secret, or a pointer to an element of a list of them.
+	v := reflect.ValueOf(fc.Parent.Result)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	f := v.FieldByName("Metadata")
+	if !f.IsValid() || !f.CanInterface() {
+		return ""
+	}
+	om, ok := f.Interface().(*model.ObjectMeta)
+	if !ok || om == nil || om.Namespace == nil {
+		return ""
+	}
+	return *om.Namespace
+}
@@ -0,0 +1,242 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/vektah/gqlparser/v2/ast"
+	authv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/upbound/xgql/internal/auth"
+	"github.com/upbound/xgql/internal/clients"
+	"github.com/upbound/xgql/internal/feature"
+	"github.com/upbound/xgql/internal/graph/model"
+)
+
+type clientCacheFn func(cr auth.Credentials, o ...clients.GetOption) (client.Client, error)
+
+func (fn clientCacheFn) Get(cr auth.Credentials, o ...clients.GetOption) (client.Client, error) {
+	return fn(cr, o...)
+}
+
+func TestSelfReviewer(t *testing.T) {
+	errBoom := errors.New("boom")
+	ra := authv1.ResourceAttributes{Resource: "secrets", Verb: "get"}
+
+	type want struct {
+		allowed bool
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		want    want
+	}{
+		"GetClientError": {
+			reason: "We should return any error encountered getting a client.",
+			clients: clientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return nil, errBoom
+			}),
+			want: want{err: errors.Wrap(errBoom, errGetClient)},
+		},
+		"CreateError": {
+			reason: "We should return any error encountered creating a review.",
+			clients: clientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{MockCreate: test.NewMockCreateFn(errBoom)}, nil
+			}),
+			want: want{err: errors.Wrap(errBoom, errCreateReview)},
+		},
+		"Allowed": {
+			reason: "We should return whether the review allowed the action.",
+			clients: clientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{MockCreate: test.NewMockCreateFn(nil, func(obj client.Object) error {
+					sar := obj.(*authv1.SelfSubjectAccessReview)
+					if diff := cmp.Diff(&ra, sar.Spec.ResourceAttributes); diff != "" {
+						t.Errorf("-want resource attributes, +got resource attributes:\n%s", diff)
+					}
+					sar.Status.Allowed = true
+					return nil
+				})}, nil
+			}),
+			want: want{allowed: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			allowed, err := NewSelfReviewer(tc.clients).Review(context.Background(), ra)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Review(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.allowed, allowed); diff != "" {
+				t.Errorf("\n%s\nr.Review(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGate(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	field := func(d ...*ast.Directive) context.Context {
+		return graphql.WithFieldContext(context.Background(), &graphql.FieldContext{Field: graphql.CollectedField{Field: &ast.Field{
+			Definition: &ast.FieldDefinition{Directives: d},
+		}}})
+	}
+	gated := &ast.Directive{Name: directive, Arguments: ast.ArgumentList{
+		{Name: "group", Value: &ast.Value{Kind: ast.StringValue, Raw: ""}},
+		{Name: "resource", Value: &ast.Value{Kind: ast.StringValue, Raw: "secrets"}},
+		{Name: "verb", Value: &ast.Value{Kind: ast.StringValue, Raw: "get"}},
+	}}
+	ns := "default"
+	secret := graphql.WithFieldContext(context.Background(), &graphql.FieldContext{Result: &model.Secret{
+		Metadata: &model.ObjectMeta{Namespace: &ns},
+	}})
+	next := func(ctx context.Context) (interface{}, error) { return "resolved", nil }
+	enabled := feature.Set{feature.FieldAuthorization: true}
+
+	type want struct {
+		res interface{}
+		err error
+	}
+	cases := map[string]struct {
+		reason   string
+		flags    feature.Flags
+		reviewer Reviewer
+		ctx      context.Context
+		want     want
+	}{
+		"NotGated": {
+			reason: "A field without a @requiresVerb directive should be resolved.",
+			flags:  enabled,
+			ctx:    field(),
+			want:   want{res: "resolved"},
+		},
+		"FeatureDisabled": {
+			reason: "A gated field should be resolved when field authorization is disabled.",
+			flags:  feature.Set{},
+			ctx:    field(gated),
+			want:   want{res: "resolved"},
+		},
+		"ReviewError": {
+			reason: "A gated field should return an error if we can't review access to it.",
+			flags:  enabled,
+			reviewer: ReviewerFn(func(_ context.Context, _ authv1.ResourceAttributes) (bool, error) {
+				return false, errBoom
+			}),
+			ctx:  field(gated),
+			want: want{err: errBoom},
+		},
+		"Forbidden": {
+			reason: "A gated field should return an error if the caller may not perform its verb.",
+			flags:  enabled,
+			reviewer: ReviewerFn(func(_ context.Context, _ authv1.ResourceAttributes) (bool, error) {
+				return false, nil
+			}),
+			ctx:  field(gated),
+			want: want{err: errors.Errorf(errFmtForbidden, "get", "secrets", "")},
+		},
+		"Allowed": {
+			reason: "A gated field should be resolved if the caller may perform its verb.",
+			flags:  enabled,
+			reviewer: ReviewerFn(func(_ context.Context, ra authv1.ResourceAttributes) (bool, error) {
+				return ra == authv1.ResourceAttributes{Resource: "secrets", Verb: "get"}, nil
+			}),
+			ctx:  field(gated),
+			want: want{res: "resolved"},
+		},
+		"AllowedInNamespace": {
+			reason: "A gated field of a namespaced object should be resolved if the caller may perform its verb in that namespace.",
+			flags:  enabled,
+			reviewer: ReviewerFn(func(_ context.Context, ra authv1.ResourceAttributes) (bool, error) {
+				return ra == authv1.ResourceAttributes{Namespace: ns, Resource: "secrets", Verb: "get"}, nil
+			}),
+			ctx: graphql.WithFieldContext(secret, &graphql.FieldContext{Field: graphql.CollectedField{Field: &ast.Field{
+				Definition: &ast.FieldDefinition{Directives: ast.DirectiveList{gated}},
+			}}}),
+			want: want{res: "resolved"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			res, err := Gate{Flags: tc.flags, Reviewer: tc.reviewer}.InterceptField(tc.ctx, next)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ng.InterceptField(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.res, res); diff != "" {
+				t.Errorf("\n%s\ng.InterceptField(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMemo(t *testing.T) {
+	reviews := 0
+	r := ReviewerFn(func(_ context.Context, _ authv1.ResourceAttributes) (bool, error) {
+		reviews++
+		return true, nil
+	})
+
+	m := &memo{reviews: make(map[authv1.ResourceAttributes]*review)}
+	secrets := authv1.ResourceAttributes{Resource: "secrets", Verb: "get"}
+	nsSecrets := authv1.ResourceAttributes{Namespace: "default", Resource: "secrets", Verb: "get"}
+	pods := authv1.ResourceAttributes{Resource: "pods", Verb: "get"}
+	for _, ra := range []authv1.ResourceAttributes{secrets, secrets, pods, nsSecrets, secrets, nsSecrets} {
+		if _, err := m.review(context.Background(), r, ra); err != nil {
+			t.Fatalf("m.review(...): %s", err)
+		}
+	}
+
+	// Each distinct action should only be reviewed once.
+	if diff := cmp.Diff(3, reviews); diff != "" {
+		t.Errorf("m.review(...): -want reviews, +got reviews:\n%s", diff)
+	}
+}
+
+func TestMemoError(t *testing.T) {
+	reviews := 0
+	r := ReviewerFn(func(_ context.Context, _ authv1.ResourceAttributes) (bool, error) {
+		reviews++
+		if reviews == 1 {
+			return false, context.Canceled
+		}
+		return true, nil
+	})
+
+	m := &memo{reviews: make(map[authv1.ResourceAttributes]*review)}
+	ra := authv1.ResourceAttributes{Resource: "secrets", Verb: "get"}
+	if _, err := m.review(context.Background(), r, ra); !errors.Is(err, context.Canceled) {
+		t.Fatalf("m.review(...): want context.Canceled, got %v", err)
+	}
+
+	// A failed review should not be memoized; it should be made again.
+	allowed, err := m.review(context.Background(), r, ra)
+	if err != nil {
+		t.Fatalf("m.review(...): %s", err)
+	}
+	if diff := cmp.Diff(true, allowed); diff != "" {
+		t.Errorf("m.review(...): -want allowed, +got allowed:\n%s", diff)
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cachecontrol derives how long a GraphQL response may be cached from
+// the @cacheControl hints of the fields it resolved, and reports it via the
+// response's extensions and Cache-Control HTTP header.
+package cachecontrol
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const (
+	directive = "cacheControl"
+	extension = "cacheControl"
+	header    = "Cache-Control"
+)
+
+// A Scope indicates who may cache a response.
+type Scope string
+
+// Cache scopes.
+const (
+	// ScopePublic responses may be cached by shared caches, e.g. a CDN.
+	ScopePublic Scope = "PUBLIC"
+
+	// ScopePrivate responses may only be cached by the caller, e.g. their
+	// browser. Most xgql responses depend on the caller's RBAC permissions.
+	ScopePrivate Scope = "PRIVATE"
+)
+
+// A Hint indicates how long the field at the supplied path may be cached.
+type Hint struct {
+	Path   ast.Path `json:"path"`
+	MaxAge int      `json:"maxAge"`
+	Scope  Scope    `json:"scope"`
+}
+
+type key int
+
+const (
+	policyKey key = iota
+	headerKey
+)
+
+// A policy accumulates the hints of the fields resolved by a query. A query
+// may be cached for as long as its most short-lived field.
+type policy struct {
+	mx     sync.Mutex
+	maxAge *int
+	scope  Scope
+	hints  []Hint
+}
+
+func (p *policy) add(maxAge int, scope Scope) {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+	if p.maxAge == nil || maxAge < *p.maxAge {
+		p.maxAge = &maxAge
+	}
+	if p.scope != ScopePrivate {
+		p.scope = scope
+	}
+}
+
+func (p *policy) hint(h Hint) {
+	p.add(h.MaxAge, h.Scope)
+	p.mx.Lock()
+	defer p.mx.Unlock()
+	p.hints = append(p.hints, h)
+}
+
+// CacheControl returns the value of the Cache-Control header for a query that
+// resolved the hinted fields.
+func (p *policy) CacheControl() string {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+	if p.maxAge == nil || *p.maxAge <= 0 {
+		return "no-store"
+	}
+	if p.scope == ScopePublic {
+		return fmt.Sprintf("max-age=%d, public", *p.maxAge)
+	}
+	return fmt.Sprintf("max-age=%d, private", *p.maxAge)
+}
+
+// getHint returns the @cacheControl hint of the supplied field, if any.
+func getHint(fc *graphql.FieldContext, vars map[string]interface{}) (Hint, bool) {
+	if fc.Field.Field == nil || fc.Field.Definition == nil {
+		return Hint{}, false
+	}
+	d := fc.Field.Definition.Directives.ForName(directive)
+	if d == nil {
+		return Hint{}, false
+	}
+	h := Hint{Path: fc.Path(), Scope: ScopePrivate}
+	args := d.ArgumentMap(vars)
+	if v, ok := args["maxAge"].(int64); ok {
+		h.MaxAge = int(v)
+	}
+	if v, ok := args["scope"].(string); ok {
+		h.Scope = Scope(v)
+	}
+	return h, true
+}
+
+// Extension is a GraphQL server extension that derives how long a query may be
+// cached from the @cacheControl hints of the fields it resolves. A field that
+// is not hinted inherits the hint of its parent, unless it needs a resolver to
+// fetch its data. Such a field (including any root field) may not be cached.
+// Mutations and subscriptions are never cached.
+type Extension struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationInterceptor
+	graphql.FieldInterceptor
+} = Extension{}
+
+// ExtensionName returns the name of this extension.
+func (e Extension) ExtensionName() string {
+	return "CacheControl"
+}
+
+// Validate this extension (a no-op).
+func (e Extension) Validate(_ graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptOperation annotates query contexts with a cache policy, and reports
+// the policy once the query has been resolved.
+func (e Extension) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	oc := graphql.GetOperationContext(ctx)
+	if oc.Operation == nil || oc.Operation.Operation != ast.Query {
+		return next(ctx)
+	}
+
+	p := &policy{}
+	rh := next(context.WithValue(ctx, policyKey, p))
+	return func(ctx context.Context) *graphql.Response {
+		rsp := rh(ctx)
+		if rsp == nil {
+			return rsp
+		}
+
+		cc := p.CacheControl()
+		if len(rsp.Errors) > 0 {
+			// Don't cache partial failures.
+			cc = "no-store"
+		}
+		if h, ok := ctx.Value(headerKey).(http.Header); ok {
+			h.Set(header, cc)
+		}
+
+		p.mx.Lock()
+		defer p.mx.Unlock()
+		if rsp.Extensions == nil {
+			rsp.Extensions = map[string]interface{}{}
+		}
+		rsp.Extensions[extension] = map[string]interface{}{"version": 1, "hints": p.hints}
+		return rsp
+	}
+}
+
+// InterceptField adds the hint of each field to the query's cache policy.
+func (e Extension) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	p, ok := ctx.Value(policyKey).(*policy)
+	fc := graphql.GetFieldContext(ctx)
+	if !ok || fc == nil {
+		return next(ctx)
+	}
+
+	h, ok := getHint(fc, graphql.GetOperationContext(ctx).Variables)
+	switch {
+	case ok:
+		p.hint(h)
+	case fc.IsResolver:
+		p.add(0, ScopePrivate)
+	}
+
+	return next(ctx)
+}
+
+// Middleware allows the cache control extension to set the Cache-Control header
+// of GraphQL HTTP responses.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), headerKey, w.Header())))
+	})
+}
@@ -0,0 +1,130 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cachecontrol
+
+import (
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/google/go-cmp/cmp"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestGetHint(t *testing.T) {
+	def := &ast.DirectiveDefinition{
+		Name: directive,
+		Arguments: ast.ArgumentDefinitionList{
+			{Name: "maxAge", DefaultValue: &ast.Value{Kind: ast.IntValue, Raw: "0"}},
+			{Name: "scope", DefaultValue: &ast.Value{Kind: ast.EnumValue, Raw: "PRIVATE"}},
+		},
+	}
+	field := func(d ...*ast.Directive) *graphql.FieldContext {
+		return &graphql.FieldContext{Field: graphql.CollectedField{Field: &ast.Field{
+			Alias:      "crds",
+			Definition: &ast.FieldDefinition{Directives: d},
+		}}}
+	}
+
+	type want struct {
+		h  Hint
+		ok bool
+	}
+	cases := map[string]struct {
+		reason string
+		fc     *graphql.FieldContext
+		want   want
+	}{
+		"NotHinted": {
+			reason: "A field without a @cacheControl directive should not be hinted.",
+			fc:     field(),
+			want:   want{ok: false},
+		},
+		"Defaults": {
+			reason: "A field with a @cacheControl directive should use its default arguments.",
+			fc:     field(&ast.Directive{Name: directive, Definition: def}),
+			want: want{
+				h:  Hint{Path: ast.Path{ast.PathName("crds")}, MaxAge: 0, Scope: ScopePrivate},
+				ok: true,
+			},
+		},
+		"Hinted": {
+			reason: "A field with a @cacheControl directive should use its arguments.",
+			fc: field(&ast.Directive{Name: directive, Definition: def, Arguments: ast.ArgumentList{
+				{Name: "maxAge", Value: &ast.Value{Kind: ast.IntValue, Raw: "60"}},
+				{Name: "scope", Value: &ast.Value{Kind: ast.EnumValue, Raw: "PUBLIC"}},
+			}}),
+			want: want{
+				h:  Hint{Path: ast.Path{ast.PathName("crds")}, MaxAge: 60, Scope: ScopePublic},
+				ok: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h, ok := getHint(tc.fc, nil)
+			if diff := cmp.Diff(tc.want.h, h); diff != "" {
+				t.Errorf("\n%s\ngetHint(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ok, ok); diff != "" {
+				t.Errorf("\n%s\ngetHint(...): -want ok, +got ok:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCacheControl(t *testing.T) {
+	type hint struct {
+		maxAge int
+		scope  Scope
+	}
+	cases := map[string]struct {
+		reason string
+		hints  []hint
+		want   string
+	}{
+		"NoHints": {
+			reason: "A query that resolved no hinted fields should not be cached.",
+			want:   "no-store",
+		},
+		"Uncacheable": {
+			reason: "A query that resolved any field with a zero max age should not be cached.",
+			hints:  []hint{{maxAge: 60, scope: ScopePublic}, {maxAge: 0, scope: ScopePublic}},
+			want:   "no-store",
+		},
+		"Public": {
+			reason: "A query that resolved only public fields should be cached for its shortest max age.",
+			hints:  []hint{{maxAge: 60, scope: ScopePublic}, {maxAge: 30, scope: ScopePublic}},
+			want:   "max-age=30, public",
+		},
+		"Private": {
+			reason: "A query that resolved any private field should be private.",
+			hints:  []hint{{maxAge: 60, scope: ScopePrivate}, {maxAge: 120, scope: ScopePublic}},
+			want:   "max-age=60, private",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := &policy{}
+			for _, h := range tc.hints {
+				p.add(h.maxAge, h.scope)
+			}
+			if diff := cmp.Diff(tc.want, p.CacheControl()); diff != "" {
+				t.Errorf("\n%s\np.CacheControl(): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
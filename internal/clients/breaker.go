@@ -0,0 +1,147 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const errFmtUnreachable = "control plane unreachable; retry after %s"
+
+// Circuit breaker defaults.
+const (
+	// DefaultBreakerThreshold is the default number of consecutive failed API
+	// server calls that open a client's circuit.
+	DefaultBreakerThreshold = 5
+
+	// DefaultBreakerCooldown is the default duration for which a client's
+	// circuit stays open.
+	DefaultBreakerCooldown = 30 * time.Second
+)
+
+// An UnreachableError is returned instead of calling the API server while a
+// client's circuit is open.
+type UnreachableError struct {
+	retryAfter time.Duration
+}
+
+// Error returns the error's message.
+func (e *UnreachableError) Error() string {
+	return fmt.Sprintf(errFmtUnreachable, e.retryAfter.Round(time.Second))
+}
+
+// RetryAfter returns how long the caller should wait before retrying.
+func (e *UnreachableError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+// WithCircuitBreaker configures each client to stop calling the API server for
+// the supplied cooldown once the supplied number of consecutive calls have
+// failed because the API server was unreachable. Clients return an
+// UnreachableError while their circuit is open. Circuits are tracked per
+// client, and thus per bearer token. A threshold of zero disables the circuit
+// breaker.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) CacheOption {
+	return func(c *Cache) {
+		c.bthreshold = threshold
+		c.bcooldown = cooldown
+	}
+}
+
+// A breaker is a circuit breaker. It opens once too many consecutive API
+// server calls fail because the API server is unreachable, and closes again
+// once it has been open for its cooldown. A nil breaker never opens.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mx        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+func newBreaker(threshold int, cooldown time.Duration) *breaker {
+	if threshold < 1 {
+		return nil
+	}
+	return &breaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+// allow returns an UnreachableError if the circuit is open.
+func (b *breaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mx.Lock()
+	defer b.mx.Unlock()
+	if d := b.openUntil.Sub(b.now()); d > 0 {
+		return &UnreachableError{retryAfter: d}
+	}
+	return nil
+}
+
+// record the outcome of an API server call. The supplied error is returned
+// unchanged. Once the circuit's cooldown has passed calls are allowed again,
+// but a single further failure will reopen it; only a success resets it.
+func (b *breaker) record(err error) error {
+	if b == nil {
+		return err
+	}
+	b.mx.Lock()
+	defer b.mx.Unlock()
+	switch {
+	case unreachable(err):
+		b.failures++
+		if b.failures >= b.threshold {
+			b.openUntil = b.now().Add(b.cooldown)
+		}
+	case answered(err):
+		b.failures = 0
+	}
+	return err
+}
+
+// unreachable returns true if the supplied error indicates that the API server
+// could not be reached, or that it said it was unavailable.
+func unreachable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if kerrors.IsServiceUnavailable(err) || kerrors.IsServerTimeout(err) {
+		return true
+	}
+	var nerr net.Error
+	return errors.As(err, &nerr)
+}
+
+// answered returns true if the supplied error indicates that the API server
+// answered the call. Timeouts are ambiguous; a client's cache times out when
+// the API server is unreachable, but also when the caller isn't allowed to
+// watch the kind of resource they're reading.
+func answered(err error) bool {
+	if err == nil {
+		return true
+	}
+	s := kerrors.APIStatus(nil)
+	return errors.As(err, &s) && !kerrors.IsTimeout(err) && !unreachable(err)
+}
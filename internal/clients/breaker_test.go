@@ -0,0 +1,117 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestBreaker(t *testing.T) {
+	errRefused := &url.Error{Op: "Get", URL: "https://example.org", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}
+	errNotFound := kerrors.NewNotFound(schema.GroupResource{Resource: "cools"}, "cool")
+	errCanceled := &url.Error{Op: "Get", URL: "https://example.org", Err: context.Canceled}
+	errTimeout := kerrors.NewTimeoutError("too slow", 0)
+
+	cases := map[string]struct {
+		reason string
+		calls  []error
+		want   error
+	}{
+		"UnderThreshold": {
+			reason: "The circuit should stay closed until the threshold is reached.",
+			calls:  []error{errRefused, errRefused},
+		},
+		"ReachedThreshold": {
+			reason: "The circuit should open once enough consecutive calls can't reach the API server.",
+			calls:  []error{errRefused, kerrors.NewServiceUnavailable("down"), errRefused},
+			want:   &UnreachableError{retryAfter: 30 * time.Second},
+		},
+		"Reset": {
+			reason: "A call the API server answers should reset the consecutive failures.",
+			calls:  []error{errRefused, errRefused, errNotFound, errRefused},
+		},
+		"Canceled": {
+			reason: "Calls that were cancelled by the caller should not be considered failures.",
+			calls:  []error{errRefused, errRefused, errCanceled},
+		},
+		"Timeout": {
+			reason: "Timeouts should neither open the circuit nor reset it.",
+			calls:  []error{errRefused, errRefused, errTimeout, errTimeout, errRefused},
+			want:   &UnreachableError{retryAfter: 30 * time.Second},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			now := time.Now()
+			b := newBreaker(3, 30*time.Second)
+			b.now = func() time.Time { return now }
+
+			for _, err := range tc.calls {
+				_ = b.record(err)
+			}
+
+			got := b.allow()
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nb.allow(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBreakerCooldown(t *testing.T) {
+	errRefused := &url.Error{Op: "Get", URL: "https://example.org", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}
+
+	now := time.Now()
+	b := newBreaker(1, 30*time.Second)
+	b.now = func() time.Time { return now }
+
+	_ = b.record(errRefused)
+	if err := b.allow(); err == nil {
+		t.Fatalf("b.allow(): want error while circuit is open, got nil")
+	}
+
+	now = now.Add(30 * time.Second)
+	if err := b.allow(); err != nil {
+		t.Errorf("b.allow(): want nil once cooldown has passed, got %s", err)
+	}
+
+	_ = b.record(errRefused)
+	if err := b.allow(); err == nil {
+		t.Errorf("b.allow(): want error after a failure once cooldown has passed, got nil")
+	}
+}
+
+func TestNilBreaker(t *testing.T) {
+	b := newBreaker(0, DefaultBreakerCooldown)
+	errBoom := errors.New("boom")
+	if err := b.record(errBoom); !errors.Is(err, errBoom) {
+		t.Errorf("b.record(...): want %s, got %s", errBoom, err)
+	}
+	if err := b.allow(); err != nil {
+		t.Errorf("b.allow(): want nil from a disabled breaker, got %s", err)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+// DefaultCategoryRefresh is the default interval at which the categories of
+// API resources are rediscovered.
+const DefaultCategoryRefresh = 1 * time.Minute
+
+const errDiscoverCategories = "cannot discover API resource categories"
+
+// Categories indexes the categories each kind of API resource belongs to, for
+// example 'managed', 'composite', or 'claim'. Categories are read from the
+// discovery API, which serves the categories declared by each CRD. Kubernetes
+// allows any authenticated user to access the discovery API via the
+// system:discovery ClusterRoleBinding, so unlike most clients Categories uses
+// xgql's own credentials.
+type Categories struct {
+	client discovery.ServerResourcesInterface
+	log    logging.Logger
+
+	mx    sync.RWMutex
+	index map[schema.GroupKind][]string
+}
+
+// NewCategories returns Categories that connect to the API server using the
+// supplied REST config. The index is empty until Refresh is called.
+func NewCategories(c *rest.Config, log logging.Logger) (*Categories, error) {
+	dc, err := discovery.NewDiscoveryClientForConfig(c)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewDiscovery)
+	}
+	return &Categories{client: dc, log: log, index: map[schema.GroupKind][]string{}}, nil
+}
+
+// Refresh the index by rediscovering the API resources served by the API
+// server. Resources that were discovered are indexed even if an error is
+// returned because some API groups could not be discovered, e.g. because an
+// aggregated API server is down. The existing index is kept if nothing could
+// be discovered.
+func (c *Categories) Refresh() error {
+	_, lists, err := c.client.ServerGroupsAndResources()
+	if len(lists) == 0 {
+		return errors.Wrap(err, errDiscoverCategories)
+	}
+
+	index := make(map[schema.GroupKind][]string)
+	for _, rl := range lists {
+		gv, perr := schema.ParseGroupVersion(rl.GroupVersion)
+		if perr != nil {
+			continue
+		}
+		for _, r := range rl.APIResources {
+			// Subresources (e.g. foos/status) share their parent's kind.
+			if len(r.Categories) == 0 || strings.Contains(r.Name, "/") {
+				continue
+			}
+			index[schema.GroupKind{Group: gv.Group, Kind: r.Kind}] = r.Categories
+		}
+	}
+
+	c.mx.Lock()
+	c.index = index
+	c.mx.Unlock()
+
+	return errors.Wrap(err, errDiscoverCategories)
+}
+
+// Run refreshes the index at the supplied interval, starting immediately.
+// It blocks until the supplied context is done.
+func (c *Categories) Run(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		if err := c.Refresh(); err != nil {
+			c.log.Debug("Cannot fully refresh API resource categories", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Categories returns the categories the supplied kind of API resource belongs
+// to, if any.
+func (c *Categories) Categories(gk schema.GroupKind) []string {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+	return c.index[gk]
+}
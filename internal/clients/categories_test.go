@@ -0,0 +1,123 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+type mockServerResources struct {
+	discovery.ServerResourcesInterface
+
+	lists []*metav1.APIResourceList
+	err   error
+}
+
+func (m *mockServerResources) ServerGroupsAndResources() ([]*metav1.APIGroup, []*metav1.APIResourceList, error) {
+	return nil, m.lists, m.err
+}
+
+func TestCategoriesRefresh(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	managed := schema.GroupKind{Group: "ec2.aws.upbound.io", Kind: "VPC"}
+	composite := schema.GroupKind{Group: "example.org", Kind: "XNetwork"}
+	existing := map[schema.GroupKind][]string{managed: {"crossplane", "managed", "aws"}}
+
+	lists := []*metav1.APIResourceList{
+		{
+			GroupVersion: "ec2.aws.upbound.io/v1beta1",
+			APIResources: []metav1.APIResource{
+				{Name: "vpcs", Kind: "VPC", Categories: []string{"crossplane", "managed", "aws"}},
+				{Name: "vpcs/status", Kind: "VPC"},
+			},
+		},
+		{
+			GroupVersion: "example.org/v1alpha1",
+			APIResources: []metav1.APIResource{
+				{Name: "xnetworks", Kind: "XNetwork", Categories: []string{"composite"}},
+			},
+		},
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "configmaps", Kind: "ConfigMap"},
+			},
+		},
+	}
+
+	type want struct {
+		index map[schema.GroupKind][]string
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client discovery.ServerResourcesInterface
+		want   want
+	}{
+		"DiscoveryFailed": {
+			reason: "The existing index should be kept if nothing could be discovered.",
+			client: &mockServerResources{err: errBoom},
+			want: want{
+				index: existing,
+				err:   errors.Wrap(errBoom, errDiscoverCategories),
+			},
+		},
+		"PartialDiscovery": {
+			reason: "Resources that were discovered should be indexed even if some groups could not be discovered.",
+			client: &mockServerResources{lists: lists[1:], err: errBoom},
+			want: want{
+				index: map[schema.GroupKind][]string{composite: {"composite"}},
+				err:   errors.Wrap(errBoom, errDiscoverCategories),
+			},
+		},
+		"Success": {
+			reason: "Only resources that belong to a category should be indexed.",
+			client: &mockServerResources{lists: lists},
+			want: want{
+				index: map[schema.GroupKind][]string{
+					managed:   {"crossplane", "managed", "aws"},
+					composite: {"composite"},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &Categories{client: tc.client, index: existing}
+			err := c.Refresh()
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.Refresh(): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.index, c.index); diff != "" {
+				t.Errorf("\n%s\nc.Refresh(): -want index, +got index:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.index[managed], c.Categories(managed)); diff != "" {
+				t.Errorf("\n%s\nc.Categories(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
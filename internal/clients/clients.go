@@ -26,7 +26,9 @@ import (
 	"golang.org/x/time/rate"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -43,8 +45,14 @@ const (
 	errNewCache         = "cannot create new read cache"
 	errDelegClient      = "cannot create cache-backed client"
 	errWaitForCacheSync = "cannot sync client cache"
+	errLoadKubeconfig   = "cannot load kubeconfig"
+
+	errFmtContextConfig = "cannot create configuration for context %q"
 )
 
+// DefaultExpiry is the default duration until an unused client expires.
+const DefaultExpiry = 5 * time.Minute
+
 // A NewCacheFn creates a new controller-runtime cache.
 type NewCacheFn func(cfg *rest.Config, o cache.Options) (cache.Cache, error)
 
@@ -63,7 +71,30 @@ func Config() (*rest.Config, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot create in-cluster configuration")
 	}
+	return tune(cfg), nil
+}
+
+// ControlPlaneConfigs returns a REST config for each context of the supplied
+// kubeconfig file, keyed by context name. Each context is expected to identify
+// a distinct (e.g. hosted) control plane.
+func ControlPlaneConfigs(kubeconfig string) (map[string]*rest.Config, error) {
+	raw, err := clientcmd.LoadFromFile(kubeconfig)
+	if err != nil {
+		return nil, errors.Wrap(err, errLoadKubeconfig)
+	}
 
+	out := make(map[string]*rest.Config, len(raw.Contexts))
+	for name := range raw.Contexts {
+		cfg, err := clientcmd.NewNonInteractiveClientConfig(*raw, name, &clientcmd.ConfigOverrides{}, nil).ClientConfig()
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtContextConfig, name)
+		}
+		out[name] = tune(cfg)
+	}
+	return out, nil
+}
+
+func tune(cfg *rest.Config) *rest.Config {
 	// ctrl.GetConfig tunes QPS and burst for Kubernetes controllers. We're not
 	// a controller and we expect to be creating many clients, so we tune these
 	// back down to the client-go defaults.
@@ -72,7 +103,7 @@ func Config() (*rest.Config, error) {
 
 	cfg.UserAgent = "xgql/" + version.Version
 
-	return cfg, nil
+	return cfg
 }
 
 // RESTMapper returns a 'REST mapper' that discovers an API server's available
@@ -112,7 +143,9 @@ func Anonymize(cfg *rest.Config) *rest.Config {
 // bearer token, which is used to authenticate to an API server. Each client is
 // backed by its own cache, which is populated by automatically watching any
 // type the client is asked to get or list. Clients (and their caches) expire
-// and are garbage collected if they are unused for five minutes.
+// and are garbage collected if they are unused for five minutes. A client with
+// active watches is never considered unused; its expiry starts when its last
+// watch stops.
 type Cache struct {
 	active map[string]*session
 	mx     sync.RWMutex
@@ -123,9 +156,28 @@ type Cache struct {
 	nocache []client.Object
 	expiry  time.Duration
 
+	// The kinds of the nocache objects.
+	uncached map[schema.GroupVersionKind]bool
+
+	strip         bool
+	maxObjectSize int
+
+	bthreshold int
+	bcooldown  time.Duration
+
+	legacyCRDs bool
+
+	// The maximum number of kinds of object observed by our metrics.
+	maxMetricKinds int
+
 	newCache  NewCacheFn
 	newClient NewClientFn
 
+	objects   map[objectWatchKey]*broadcaster
+	omx       sync.Mutex
+	wbuffer   int
+	woverflow OverflowPolicy
+
 	salt []byte
 	log  logging.Logger
 }
@@ -180,11 +232,18 @@ func NewCache(s *runtime.Scheme, c *rest.Config, o ...CacheOption) *Cache {
 
 		cfg:    c,
 		scheme: s,
-		expiry: 5 * time.Minute,
+		expiry: DefaultExpiry,
 
 		newCache:  DefaultNewCacheFn,
 		newClient: DefaultNewClientFn,
 
+		objects:   make(map[objectWatchKey]*broadcaster),
+		wbuffer:   DefaultWatchBuffer,
+		woverflow: DropNewest,
+
+		bthreshold: DefaultBreakerThreshold,
+		bcooldown:  DefaultBreakerCooldown,
+
 		salt: salt,
 		log:  logging.NewNopLogger(),
 	}
@@ -193,11 +252,20 @@ func NewCache(s *runtime.Scheme, c *rest.Config, o ...CacheOption) *Cache {
 		fn(ch)
 	}
 
+	ch.uncached = make(map[schema.GroupVersionKind]bool, len(ch.nocache))
+	for _, obj := range ch.nocache {
+		if gvk, err := apiutil.GVKForObject(obj, s); err == nil {
+			ch.uncached[gvk] = true
+		}
+	}
+
+	observe(ch)
 	return ch
 }
 
 type getOptions struct {
 	Namespace string
+	Uncached  bool
 }
 
 // A GetOption modifies the kind of client returned.
@@ -211,6 +279,15 @@ func ForNamespace(n string) GetOption {
 	}
 }
 
+// Uncached returns a client that reads directly from the API server rather
+// than from its cache. This allows reads to use features the cache doesn't
+// support, like field selectors. Writes always go to the API server.
+func Uncached() GetOption {
+	return func(o *getOptions) {
+		o.Uncached = true
+	}
+}
+
 // Get a client that uses the specified bearer token.
 func (c *Cache) Get(cr auth.Credentials, o ...GetOption) (client.Client, error) {
 	opts := &getOptions{}
@@ -218,6 +295,28 @@ func (c *Cache) Get(cr auth.Credentials, o ...GetOption) (client.Client, error)
 		fn(opts)
 	}
 
+	sn, err := c.get(cr, o...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fail fast rather than have the caller wait for the API server calls
+	// they're about to make to time out.
+	if err := sn.breaker.allow(); err != nil {
+		return nil, err
+	}
+	if opts.Uncached {
+		return &uncachedSession{session: sn}, nil
+	}
+	return sn, nil
+}
+
+func (c *Cache) get(cr auth.Credentials, o ...GetOption) (*session, error) {
+	opts := &getOptions{}
+	for _, fn := range o {
+		fn(opts)
+	}
+
 	extra := bytes.Buffer{}
 	extra.Write(c.salt)
 	extra.WriteString(opts.Namespace)
@@ -245,7 +344,7 @@ func (c *Cache) Get(cr auth.Credentials, o ...GetOption) (client.Client, error)
 		return nil, errors.Wrap(err, errNewClient)
 	}
 
-	ca, err := c.newCache(cfg, cache.Options{Scheme: c.scheme, Mapper: c.mapper, Namespace: opts.Namespace})
+	ca, err := c.newCache(c.transform(cfg), cache.Options{Scheme: c.scheme, Mapper: c.mapper, Namespace: opts.Namespace})
 	if err != nil {
 		return nil, errors.Wrap(err, errNewCache)
 	}
@@ -267,13 +366,18 @@ func (c *Cache) Get(cr auth.Credentials, o ...GetOption) (client.Client, error)
 		return nil, errors.Wrap(err, errDelegClient)
 	}
 
+	var rc, dwc client.Client = dc, wc
+	if c.legacyCRDs {
+		rc, dwc = &legacyCRDClient{Client: dc}, &legacyCRDClient{Client: wc}
+	}
+
 	// We use a distinct s.expiry ticker rather than a context deadline or timeout
 	// because it's not possible to extend a context's deadline or timeout, but it
 	// is possible to 'reset' (i.e. extend) a ticker.
 	expiration := &tickerExpiration{t: time.NewTicker(c.expiry)}
 	newExpiry := time.Now().Add(c.expiry)
 	ctx, cancel := context.WithCancel(context.Background())
-	sn = &session{client: dc, cancel: cancel, expiry: c.expiry, expiration: expiration, log: log}
+	sn = &session{client: rc, direct: dwc, cache: ca, cancel: cancel, expiry: c.expiry, expiration: expiration, truncates: c.maxObjectSize > 0, breaker: newBreaker(c.bthreshold, c.bcooldown), uncached: c.uncached, log: log}
 
 	c.mx.Lock()
 	c.active[id] = sn
@@ -291,16 +395,25 @@ func (c *Cache) Get(cr auth.Credentials, o ...GetOption) (client.Client, error)
 
 	// Stop our cache when we expire.
 	go func() {
-		select {
-		case <-expiration.C():
-			// We expired, and should remove ourself from the session cache.
-			log.Debug("Client expired")
-			c.remove(id)
-		case <-ctx.Done():
-			log.Debug("Client stopped")
-			// We're done for some other reason (e.g. the cache crashed). We assume
-			// whatever cancelled our context did so by calling done() - we just need
-			// to let this goroutine finish.
+		for {
+			select {
+			case <-expiration.C():
+				if sn.referenced() {
+					// Something (e.g. a subscription) is still using our
+					// informers, so we're not idle.
+					continue
+				}
+				// We expired, and should remove ourself from the session cache.
+				log.Debug("Client expired")
+				c.remove(id)
+				return
+			case <-ctx.Done():
+				log.Debug("Client stopped")
+				// We're done for some other reason (e.g. the cache crashed). We assume
+				// whatever cancelled our context did so by calling done() - we just need
+				// to let this goroutine finish.
+				return
+			}
 		}
 	}()
 
@@ -324,11 +437,37 @@ func (c *Cache) remove(id string) {
 	if sn, ok := c.active[id]; ok {
 		sn.cancel()
 		sn.expiration.Stop()
+		sn.stopWatches()
 		delete(c.active, id)
 		c.log.Debug("Removed client cache", "client-id", id)
 	}
 }
 
+// Stop all object watches and client sessions, stopping their caches. Stop is
+// intended to be called when shutting down; clients got from the Cache should
+// not be used after it is called.
+func (c *Cache) Stop() {
+	unobserve(c)
+
+	c.omx.Lock()
+	for k, b := range c.objects {
+		b.stop()
+		delete(c.objects, k)
+	}
+	c.omx.Unlock()
+
+	c.mx.RLock()
+	ids := make([]string, 0, len(c.active))
+	for id := range c.active {
+		ids = append(ids, id)
+	}
+	c.mx.RUnlock()
+
+	for _, id := range ids {
+		c.remove(id)
+	}
+}
+
 type expiration interface {
 	Reset(d time.Duration)
 	Stop()
@@ -343,17 +482,64 @@ func (e *tickerExpiration) C() <-chan time.Time   { return e.t.C }
 
 type session struct {
 	client     client.Client
+	direct     client.Client
+	cache      cache.Cache
 	cancel     context.CancelFunc
 	expiry     time.Duration
 	expiration expiration
 
+	// Whether objects may be too large to cache; see WithMaxObjectSize.
+	truncates bool
+
+	// Tracks whether the API server is reachable; see WithCircuitBreaker.
+	breaker *breaker
+
+	// Kinds of object that are never cached.
+	uncached map[schema.GroupVersionKind]bool
+
+	wmx       sync.Mutex
+	watches   map[watchKey]*broadcaster
+	informers map[watchKey]*informerStats
+	refs      int
+
 	log logging.Logger
 }
 
+// acquire a reference to the session, preventing it from expiring while the
+// reference is held.
+func (s *session) acquire() {
+	s.wmx.Lock()
+	defer s.wmx.Unlock()
+	s.refs++
+}
+
+// release a reference to the session. The session's expiry is reset when its
+// last reference is released, so it may expire once it has been idle for its
+// full expiry duration.
+func (s *session) release() {
+	s.wmx.Lock()
+	defer s.wmx.Unlock()
+	s.refs--
+	if s.refs == 0 {
+		s.expiration.Reset(s.expiry)
+	}
+}
+
+func (s *session) referenced() bool {
+	s.wmx.Lock()
+	defer s.wmx.Unlock()
+	return s.refs > 0
+}
+
 func (s *session) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
 	t := time.Now()
 	s.expiration.Reset(s.expiry)
-	err := s.client.Get(ctx, key, obj)
+	err := s.breaker.record(s.client.Get(ctx, key, obj))
+	if err == nil {
+		s.track(ctx, obj)
+		err = s.untruncate(ctx, obj)
+	}
+	recordVersions(ctx, obj, err)
 	s.log.Debug("Client called",
 		"operation", "Get",
 		"duration", time.Since(t),
@@ -365,7 +551,12 @@ func (s *session) Get(ctx context.Context, key client.ObjectKey, obj client.Obje
 func (s *session) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
 	t := time.Now()
 	s.expiration.Reset(s.expiry)
-	err := s.client.List(ctx, list, opts...)
+	err := s.breaker.record(s.client.List(ctx, list, opts...))
+	if err == nil {
+		s.track(ctx, list)
+		err = s.untruncateList(ctx, list)
+	}
+	recordVersions(ctx, list, err)
 	s.log.Debug("Client called",
 		"operation", "List",
 		"duration", time.Since(t),
@@ -377,7 +568,8 @@ func (s *session) List(ctx context.Context, list client.ObjectList, opts ...clie
 func (s *session) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
 	t := time.Now()
 	s.expiration.Reset(s.expiry)
-	err := s.client.Create(ctx, obj, opts...)
+	recordAPICall(ctx)
+	err := s.breaker.record(s.client.Create(ctx, obj, opts...))
 	s.log.Debug("Client called",
 		"operation", "Create",
 		"duration", time.Since(t),
@@ -389,7 +581,8 @@ func (s *session) Create(ctx context.Context, obj client.Object, opts ...client.
 func (s *session) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
 	t := time.Now()
 	s.expiration.Reset(s.expiry)
-	err := s.client.Delete(ctx, obj, opts...)
+	recordAPICall(ctx)
+	err := s.breaker.record(s.client.Delete(ctx, obj, opts...))
 	s.log.Debug("Client called",
 		"operation", "Delete",
 		"duration", time.Since(t),
@@ -401,7 +594,8 @@ func (s *session) Delete(ctx context.Context, obj client.Object, opts ...client.
 func (s *session) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
 	t := time.Now()
 	s.expiration.Reset(s.expiry)
-	err := s.client.Update(ctx, obj, opts...)
+	recordAPICall(ctx)
+	err := s.breaker.record(s.client.Update(ctx, obj, opts...))
 	s.log.Debug("Client called",
 		"operation", "Update",
 		"duration", time.Since(t),
@@ -413,7 +607,8 @@ func (s *session) Update(ctx context.Context, obj client.Object, opts ...client.
 func (s *session) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
 	t := time.Now()
 	s.expiration.Reset(s.expiry)
-	err := s.client.Patch(ctx, obj, patch, opts...)
+	recordAPICall(ctx)
+	err := s.breaker.record(s.client.Patch(ctx, obj, patch, opts...))
 	s.log.Debug("Client called",
 		"operation", "Patch",
 		"duration", time.Since(t),
@@ -425,7 +620,8 @@ func (s *session) Patch(ctx context.Context, obj client.Object, patch client.Pat
 func (s *session) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
 	t := time.Now()
 	s.expiration.Reset(s.expiry)
-	err := s.client.DeleteAllOf(ctx, obj, opts...)
+	recordAPICall(ctx)
+	err := s.breaker.record(s.client.DeleteAllOf(ctx, obj, opts...))
 	s.log.Debug("Client called",
 		"operation", "DeleteallOf",
 		"duration", time.Since(t),
@@ -469,3 +665,38 @@ func (s *session) RESTMapper() meta.RESTMapper {
 	)
 	return rm
 }
+
+// An uncachedSession is a session that reads directly from the API server.
+type uncachedSession struct {
+	*session
+}
+
+func (s *uncachedSession) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	t := time.Now()
+	s.expiration.Reset(s.expiry)
+	recordAPICall(ctx)
+	err := s.breaker.record(s.direct.Get(ctx, key, obj))
+	recordVersions(ctx, obj, err)
+	s.log.Debug("Client called",
+		"operation", "Get",
+		"uncached", true,
+		"duration", time.Since(t),
+		"new-expiry", t.Add(s.expiry),
+	)
+	return err
+}
+
+func (s *uncachedSession) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	t := time.Now()
+	s.expiration.Reset(s.expiry)
+	recordAPICall(ctx)
+	err := s.breaker.record(s.direct.List(ctx, list, opts...))
+	recordVersions(ctx, list, err)
+	s.log.Debug("Client called",
+		"operation", "List",
+		"uncached", true,
+		"duration", time.Since(t),
+		"new-expiry", t.Add(s.expiry),
+	)
+	return err
+}
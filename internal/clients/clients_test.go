@@ -162,6 +162,86 @@ func TestGet(t *testing.T) {
 	}
 }
 
+func TestStop(t *testing.T) {
+	stopped := make(chan struct{})
+	c := NewCache(runtime.NewScheme(), &rest.Config{},
+		WithNewClientFn(NewClientFn(func(cfg *rest.Config, o client.Options) (client.Client, error) {
+			return test.NewMockClient(), nil
+		})),
+		WithNewCacheFn(NewCacheFn(func(cfg *rest.Config, o cache.Options) (cache.Cache, error) {
+			ca := &MockCache{
+				MockStart: func(stop context.Context) error {
+					<-stop.Done()
+					close(stopped)
+					return nil
+				},
+				MockWaitForCacheSync: func(ctx context.Context) bool { return true },
+			}
+			return ca, nil
+		})),
+	)
+
+	if _, err := c.Get(auth.Credentials{}); err != nil {
+		t.Fatalf("c.Get(...): %s", err)
+	}
+
+	c.Stop()
+
+	select {
+	case <-stopped:
+	case <-time.After(1 * time.Second):
+		t.Errorf("c.Stop(): cache was not stopped")
+	}
+	if diff := cmp.Diff(0, len(c.active)); diff != "" {
+		t.Errorf("c.Stop(): -want active clients, +got:\n%s", diff)
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	c := NewCache(runtime.NewScheme(), &rest.Config{},
+		WithExpiry(50*time.Millisecond),
+		WithNewClientFn(NewClientFn(func(cfg *rest.Config, o client.Options) (client.Client, error) {
+			return test.NewMockClient(), nil
+		})),
+		WithNewCacheFn(NewCacheFn(func(cfg *rest.Config, o cache.Options) (cache.Cache, error) {
+			ca := &MockCache{
+				MockStart: func(stop context.Context) error {
+					<-stop.Done()
+					return nil
+				},
+				MockWaitForCacheSync: func(ctx context.Context) bool { return true },
+			}
+			return ca, nil
+		})),
+	)
+	defer c.Stop()
+
+	sn, err := c.get(auth.Credentials{})
+	if err != nil {
+		t.Fatalf("c.get(...): %s", err)
+	}
+
+	// A referenced client should not expire, even if it's idle.
+	sn.acquire()
+	time.Sleep(200 * time.Millisecond)
+	c.mx.RLock()
+	active := len(c.active)
+	c.mx.RUnlock()
+	if diff := cmp.Diff(1, active); diff != "" {
+		t.Errorf("referenced client: -want active clients, +got:\n%s", diff)
+	}
+
+	// An unreferenced client should expire once it's idle.
+	sn.release()
+	time.Sleep(200 * time.Millisecond)
+	c.mx.RLock()
+	active = len(c.active)
+	c.mx.RUnlock()
+	if diff := cmp.Diff(0, active); diff != "" {
+		t.Errorf("unreferenced client: -want active clients, +got:\n%s", diff)
+	}
+}
+
 type mockExpiration struct{ expiry time.Duration }
 
 func (e *mockExpiration) Reset(d time.Duration) { e.expiry = d }
@@ -205,6 +285,7 @@ func TestSessionGet(t *testing.T) {
 				expiration: &mockExpiration{},
 				log:        logging.NewNopLogger(),
 			},
+			args: args{ctx: context.Background()},
 			want: want{
 				err:    errBoom,
 				expiry: expiry,
@@ -218,6 +299,7 @@ func TestSessionGet(t *testing.T) {
 				expiration: &mockExpiration{},
 				log:        logging.NewNopLogger(),
 			},
+			args: args{ctx: context.Background()},
 			want: want{
 				expiry: expiry,
 			},
@@ -282,6 +364,7 @@ func TestSessionList(t *testing.T) {
 				expiration: &mockExpiration{},
 				log:        logging.NewNopLogger(),
 			},
+			args: args{ctx: context.Background()},
 			want: want{
 				err:    errBoom,
 				expiry: expiry,
@@ -295,6 +378,7 @@ func TestSessionList(t *testing.T) {
 				expiration: &mockExpiration{},
 				log:        logging.NewNopLogger(),
 			},
+			args: args{ctx: context.Background()},
 			want: want{
 				expiry: expiry,
 			},
@@ -359,6 +443,7 @@ func TestSessionCreate(t *testing.T) {
 				expiration: &mockExpiration{},
 				log:        logging.NewNopLogger(),
 			},
+			args: args{ctx: context.Background()},
 			want: want{
 				err:    errBoom,
 				expiry: expiry,
@@ -372,6 +457,7 @@ func TestSessionCreate(t *testing.T) {
 				expiration: &mockExpiration{},
 				log:        logging.NewNopLogger(),
 			},
+			args: args{ctx: context.Background()},
 			want: want{
 				expiry: expiry,
 			},
@@ -436,6 +522,7 @@ func TestSessionDelete(t *testing.T) {
 				expiration: &mockExpiration{},
 				log:        logging.NewNopLogger(),
 			},
+			args: args{ctx: context.Background()},
 			want: want{
 				err:    errBoom,
 				expiry: expiry,
@@ -449,6 +536,7 @@ func TestSessionDelete(t *testing.T) {
 				expiration: &mockExpiration{},
 				log:        logging.NewNopLogger(),
 			},
+			args: args{ctx: context.Background()},
 			want: want{
 				expiry: expiry,
 			},
@@ -513,6 +601,7 @@ func TestSessionDeleteAllOf(t *testing.T) {
 				expiration: &mockExpiration{},
 				log:        logging.NewNopLogger(),
 			},
+			args: args{ctx: context.Background()},
 			want: want{
 				err:    errBoom,
 				expiry: expiry,
@@ -526,6 +615,7 @@ func TestSessionDeleteAllOf(t *testing.T) {
 				expiration: &mockExpiration{},
 				log:        logging.NewNopLogger(),
 			},
+			args: args{ctx: context.Background()},
 			want: want{
 				expiry: expiry,
 			},
@@ -0,0 +1,115 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	kext "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/install"
+	kextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kextv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const errConvertCRD = "cannot convert apiextensions.k8s.io/v1beta1 CustomResourceDefinition to v1"
+
+// crdKind is the kind of CustomResourceDefinition, sans version.
+var crdKind = kextv1.Kind("CustomResourceDefinition")
+
+// crdScheme knows how to default and convert all versions of the
+// CustomResourceDefinition type, by way of its internal version.
+var crdScheme = func() *runtime.Scheme {
+	s := runtime.NewScheme()
+	install.Install(s)
+	return s
+}()
+
+// ServesLegacyCRDs returns true if the API server the supplied REST mapper
+// discovers serves CustomResourceDefinitions at apiextensions.k8s.io/v1beta1
+// but not at v1. Kubernetes added v1 in 1.16.
+func ServesLegacyCRDs(m meta.RESTMapper) bool {
+	if _, err := m.RESTMapping(crdKind, kextv1.SchemeGroupVersion.Version); err == nil {
+		return false
+	}
+	_, err := m.RESTMapping(crdKind, kextv1beta1.SchemeGroupVersion.Version)
+	return err == nil
+}
+
+// ConvertLegacyCRDs configures clients to read apiextensions.k8s.io/v1beta1
+// CustomResourceDefinitions from the API server when asked to read v1
+// CustomResourceDefinitions, and to convert them to v1. This allows callers
+// that only know about v1 to work against API servers that predate it. Only
+// reads are converted; writes are passed through unchanged. The scheme
+// supplied to NewCache must include v1beta1 CustomResourceDefinitions.
+func ConvertLegacyCRDs(convert bool) CacheOption {
+	return func(c *Cache) {
+		c.legacyCRDs = convert
+	}
+}
+
+// A legacyCRDClient reads v1beta1 CustomResourceDefinitions on behalf of
+// callers who asked for v1 CustomResourceDefinitions.
+type legacyCRDClient struct {
+	client.Client
+}
+
+func (c *legacyCRDClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	crd, ok := obj.(*kextv1.CustomResourceDefinition)
+	if !ok {
+		return c.Client.Get(ctx, key, obj)
+	}
+	in := &kextv1beta1.CustomResourceDefinition{}
+	if err := c.Client.Get(ctx, key, in); err != nil {
+		return err
+	}
+	return errors.Wrap(convertCRD(in, crd), errConvertCRD)
+}
+
+func (c *legacyCRDClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	crds, ok := list.(*kextv1.CustomResourceDefinitionList)
+	if !ok {
+		return c.Client.List(ctx, list, opts...)
+	}
+	in := &kextv1beta1.CustomResourceDefinitionList{}
+	if err := c.Client.List(ctx, in, opts...); err != nil {
+		return err
+	}
+	crds.ListMeta = in.ListMeta
+	crds.Items = make([]kextv1.CustomResourceDefinition, len(in.Items))
+	for i := range in.Items {
+		if err := convertCRD(&in.Items[i], &crds.Items[i]); err != nil {
+			return errors.Wrap(err, errConvertCRD)
+		}
+	}
+	return nil
+}
+
+// convertCRD converts the supplied v1beta1 CustomResourceDefinition to v1.
+// The input is defaulted first so that CustomResourceDefinitions that only
+// specify the deprecated top-level version field convert correctly.
+func convertCRD(in *kextv1beta1.CustomResourceDefinition, out *kextv1.CustomResourceDefinition) error {
+	in = in.DeepCopy()
+	crdScheme.Default(in)
+
+	internal := &kext.CustomResourceDefinition{}
+	if err := crdScheme.Convert(in, internal, nil); err != nil {
+		return err
+	}
+	return crdScheme.Convert(internal, out, nil)
+}
@@ -0,0 +1,173 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kextv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestLegacyCRDClientGet(t *testing.T) {
+	errBoom := errors.New("boom")
+	preserve := false
+
+	legacy := &kextv1beta1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "cools.example.org"},
+		Spec: kextv1beta1.CustomResourceDefinitionSpec{
+			Group:   "example.org",
+			Version: "v1",
+			Names: kextv1beta1.CustomResourceDefinitionNames{
+				Plural:   "cools",
+				Singular: "cool",
+				Kind:     "Cool",
+				ListKind: "CoolList",
+			},
+			Scope:                 kextv1beta1.ClusterScoped,
+			Validation:            &kextv1beta1.CustomResourceValidation{OpenAPIV3Schema: &kextv1beta1.JSONSchemaProps{Type: "object"}},
+			Conversion:            &kextv1beta1.CustomResourceConversion{Strategy: kextv1beta1.NoneConverter},
+			PreserveUnknownFields: &preserve,
+		},
+	}
+
+	converted := &kextv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "cools.example.org"},
+		Spec: kextv1.CustomResourceDefinitionSpec{
+			Group: "example.org",
+			Names: kextv1.CustomResourceDefinitionNames{
+				Plural:   "cools",
+				Singular: "cool",
+				Kind:     "Cool",
+				ListKind: "CoolList",
+			},
+			Scope: kextv1.ClusterScoped,
+			Versions: []kextv1.CustomResourceDefinitionVersion{{
+				Name:    "v1",
+				Served:  true,
+				Storage: true,
+				Schema:  &kextv1.CustomResourceValidation{OpenAPIV3Schema: &kextv1.JSONSchemaProps{Type: "object"}},
+			}},
+			Conversion: &kextv1.CustomResourceConversion{Strategy: kextv1.NoneConverter},
+		},
+		Status: kextv1.CustomResourceDefinitionStatus{StoredVersions: []string{"v1"}},
+	}
+
+	type want struct {
+		obj client.Object
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Client
+		obj    client.Object
+		want   want
+	}{
+		"GetCRD": {
+			reason: "A v1beta1 CRD should be read and converted to v1 when a v1 CRD is requested.",
+			client: &test.MockClient{
+				MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+					if _, ok := obj.(*kextv1beta1.CustomResourceDefinition); !ok {
+						return errors.Errorf("wanted a v1beta1 CRD, got %T", obj)
+					}
+					legacy.DeepCopyInto(obj.(*kextv1beta1.CustomResourceDefinition))
+					return nil
+				},
+			},
+			obj:  &kextv1.CustomResourceDefinition{},
+			want: want{obj: converted},
+		},
+		"GetCRDError": {
+			reason: "Errors reading a v1beta1 CRD should be returned.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			obj:    &kextv1.CustomResourceDefinition{},
+			want:   want{obj: &kextv1.CustomResourceDefinition{}, err: errBoom},
+		},
+		"GetOther": {
+			reason: "Reads of types other than v1 CRDs should be passed through.",
+			client: &test.MockClient{
+				MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+					obj.SetName("cool")
+					return nil
+				},
+			},
+			obj:  &corev1.ConfigMap{},
+			want: want{obj: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cool"}}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &legacyCRDClient{Client: tc.client}
+			err := c.Get(context.Background(), client.ObjectKey{Name: "cool"}, tc.obj)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.Get(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.obj, tc.obj); diff != "" {
+				t.Errorf("\n%s\nc.Get(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestLegacyCRDClientList(t *testing.T) {
+	c := &legacyCRDClient{Client: &test.MockClient{
+		MockList: func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+			l, ok := list.(*kextv1beta1.CustomResourceDefinitionList)
+			if !ok {
+				return errors.Errorf("wanted a v1beta1 CRD list, got %T", list)
+			}
+			l.ResourceVersion = "42"
+			l.Items = []kextv1beta1.CustomResourceDefinition{{
+				ObjectMeta: metav1.ObjectMeta{Name: "cools.example.org"},
+				Spec: kextv1beta1.CustomResourceDefinitionSpec{
+					Versions: []kextv1beta1.CustomResourceDefinitionVersion{
+						{Name: "v1alpha1", Served: true},
+						{Name: "v1", Served: true, Storage: true},
+					},
+				},
+			}}
+			return nil
+		},
+	}}
+
+	got := &kextv1.CustomResourceDefinitionList{}
+	if err := c.List(context.Background(), got); err != nil {
+		t.Fatalf("c.List(...): %s", err)
+	}
+
+	if diff := cmp.Diff("42", got.ResourceVersion); diff != "" {
+		t.Errorf("c.List(...): -want resource version, +got resource version:\n%s", diff)
+	}
+	if len(got.Items) != 1 {
+		t.Fatalf("c.List(...): want 1 item, got %d", len(got.Items))
+	}
+	want := []kextv1.CustomResourceDefinitionVersion{
+		{Name: "v1alpha1", Served: true},
+		{Name: "v1", Served: true, Storage: true},
+	}
+	if diff := cmp.Diff(want, got.Items[0].Spec.Versions); diff != "" {
+		t.Errorf("c.List(...): -want versions, +got versions:\n%s", diff)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+
+	"github.com/upbound/xgql/internal/auth"
+)
+
+const (
+	errNewDiscovery       = "cannot create new discovery client"
+	errDiscoverGroups     = "cannot discover API groups"
+	errFmtDiscoverVersion = "cannot discover API resources of %s"
+)
+
+// Discovery discovers the API resources served by the API server. Discovery
+// isn't cached; each caller's view of the API server is read using their own
+// credentials.
+type Discovery struct {
+	cfg *rest.Config
+}
+
+// NewDiscovery returns Discovery that connects to the API server using a copy
+// of the supplied REST config with specific credentials injected.
+func NewDiscovery(c *rest.Config) *Discovery {
+	return &Discovery{cfg: c}
+}
+
+// Discover the API groups served by the API server and the API resources of
+// each of their versions, using the supplied credentials. Only the supplied
+// group is discovered, if any. The resources of any versions that were
+// discovered successfully are returned along with an error if any versions
+// could not be discovered, e.g. because an aggregated API server is down.
+func (d *Discovery) Discover(ctx context.Context, cr auth.Credentials, group *string) ([]metav1.APIGroup, []*metav1.APIResourceList, error) {
+	recordUnversioned(ctx)
+
+	dc, err := discovery.NewDiscoveryClientForConfig(cr.Inject(d.cfg))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errNewDiscovery)
+	}
+
+	gl, err := dc.ServerGroups()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errDiscoverGroups)
+	}
+
+	groups := make([]metav1.APIGroup, 0, len(gl.Groups))
+	lists := make([]*metav1.APIResourceList, 0, len(gl.Groups))
+	errs := make([]error, 0)
+	for _, g := range gl.Groups {
+		if group != nil && g.Name != *group {
+			continue
+		}
+		groups = append(groups, g)
+		for _, v := range g.Versions {
+			rl, err := dc.ServerResourcesForGroupVersion(v.GroupVersion)
+			if err != nil {
+				errs = append(errs, errors.Wrapf(err, errFmtDiscoverVersion, v.GroupVersion))
+				continue
+			}
+			lists = append(lists, rl)
+		}
+	}
+
+	return groups, lists, kerrors.NewAggregate(errs)
+}
@@ -0,0 +1,57 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"context"
+	"io"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/upbound/xgql/internal/auth"
+)
+
+const (
+	errNewClientset = "cannot create new clientset"
+	errStreamLogs   = "cannot stream pod logs"
+)
+
+// PodLogs streams the logs of pods. Pod logs are a subresource that can't be
+// read using a controller-runtime client, let alone cached, so each stream is
+// read directly from the API server using the caller's credentials.
+type PodLogs struct {
+	cfg *rest.Config
+}
+
+// NewPodLogs returns PodLogs that connect to the API server using a copy of
+// the supplied REST config with specific credentials injected.
+func NewPodLogs(c *rest.Config) *PodLogs {
+	return &PodLogs{cfg: c}
+}
+
+// Stream the logs of the supplied pod using the supplied credentials.
+func (p *PodLogs) Stream(ctx context.Context, cr auth.Credentials, namespace, name string, o *corev1.PodLogOptions) (io.ReadCloser, error) {
+	recordUnversioned(ctx)
+
+	cs, err := kubernetes.NewForConfig(cr.Inject(p.cfg))
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClientset)
+	}
+	rc, err := cs.CoreV1().Pods(namespace).GetLogs(name, o).Stream(ctx)
+	return rc, errors.Wrap(err, errStreamLogs)
+}
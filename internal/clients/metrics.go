@@ -0,0 +1,297 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/global"
+	"go.opentelemetry.io/otel/metric/unit"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kcache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+const (
+	controlPlane = attribute.Key("crossplane.io/control-plane")
+	apiVersion   = attribute.Key("crossplane.io/api-version")
+	kind         = attribute.Key("crossplane.io/kind")
+)
+
+// OpenTelemetry metrics. Every cache that hasn't been stopped is observed each
+// time metrics are collected.
+var (
+	meter = global.GetMeterProvider().Meter("crossplane.io/xgql")
+
+	clientsCached    metric.Int64GaugeObserver
+	informersRunning metric.Int64GaugeObserver
+	objectsCached    metric.Int64GaugeObserver
+	bytesCached      metric.Int64GaugeObserver
+)
+
+// The batch observer's callback reads the instruments it creates, so they're
+// created here rather than in the var block above to avoid an initialization
+// cycle.
+func init() {
+	observer := metric.Must(meter).NewBatchObserver(observeCaches)
+
+	clientsCached = observer.NewInt64GaugeObserver("cache.clients",
+		metric.WithDescription("Number of cached clients, each of which is associated with a bearer token"),
+		metric.WithUnit(unit.Dimensionless))
+
+	informersRunning = observer.NewInt64GaugeObserver("cache.informers",
+		metric.WithDescription("Number of informers running across all cached clients"),
+		metric.WithUnit(unit.Dimensionless))
+
+	objectsCached = observer.NewInt64GaugeObserver("cache.objects",
+		metric.WithDescription("Number of objects cached across all cached clients"),
+		metric.WithUnit(unit.Dimensionless))
+
+	bytesCached = observer.NewInt64GaugeObserver("cache.objects.bytes",
+		metric.WithDescription("Estimated size of the objects cached across all cached clients, as JSON"),
+		metric.WithUnit(unit.Bytes))
+}
+
+// Labels used in place of the API version and kind of objects.
+const labelOther = "other"
+
+// WithMaxMetricKinds limits the number of kinds of object for which the number
+// and size of cached objects is reported. Metrics are reported for the kinds
+// with the most cached objects; the remaining kinds are reported together with
+// an API version and kind of 'other'. Zero, the default, disables the limit.
+func WithMaxMetricKinds(n int) CacheOption {
+	return func(c *Cache) {
+		c.maxMetricKinds = n
+	}
+}
+
+var (
+	observed   = map[*Cache]bool{}
+	observedMx sync.Mutex
+)
+
+func observe(c *Cache) {
+	observedMx.Lock()
+	defer observedMx.Unlock()
+	observed[c] = true
+}
+
+func unobserve(c *Cache) {
+	observedMx.Lock()
+	defer observedMx.Unlock()
+	delete(observed, c)
+}
+
+func observeCaches(_ context.Context, r metric.BatchObserverResult) {
+	observedMx.Lock()
+	cs := make([]*Cache, 0, len(observed))
+	for c := range observed {
+		cs = append(cs, c)
+	}
+	observedMx.Unlock()
+
+	for _, c := range cs {
+		c.observe(r)
+	}
+}
+
+type informerTotals struct {
+	objects int64
+	bytes   int64
+}
+
+// observe the supplied cache's clients and informers. Each client has its own
+// informers, so the objects of a particular kind are cached once per client
+// that has read them.
+func (c *Cache) observe(r metric.BatchObserverResult) {
+	c.mx.RLock()
+	sessions := make([]*session, 0, len(c.active))
+	for _, sn := range c.active {
+		sessions = append(sessions, sn)
+	}
+	c.mx.RUnlock()
+
+	informers := 0
+	kinds := map[schema.GroupVersionKind]*informerTotals{}
+	for _, sn := range sessions {
+		sn.wmx.Lock()
+		for k, st := range sn.informers {
+			informers++
+			t, ok := kinds[k.gvk]
+			if !ok {
+				t = &informerTotals{}
+				kinds[k.gvk] = t
+			}
+			t.objects += atomic.LoadInt64(&st.objects)
+			t.bytes += atomic.LoadInt64(&st.bytes)
+		}
+		sn.wmx.Unlock()
+	}
+
+	host := controlPlane.String(c.cfg.Host)
+	r.Observe([]attribute.KeyValue{host},
+		clientsCached.Observation(int64(len(sessions))),
+		informersRunning.Observation(int64(informers)))
+	for gvk, t := range limitKinds(kinds, c.maxMetricKinds) {
+		av, k := labelOther, labelOther
+		if !gvk.Empty() {
+			av, k = gvk.GroupVersion().String(), gvk.Kind
+		}
+		r.Observe([]attribute.KeyValue{host, apiVersion.String(av), kind.String(k)},
+			objectsCached.Observation(t.objects),
+			bytesCached.Observation(t.bytes))
+	}
+}
+
+// limitKinds returns the max kinds with the most objects. The totals of the
+// remaining kinds are summed and returned under the empty kind. Zero disables
+// the limit.
+func limitKinds(kinds map[schema.GroupVersionKind]*informerTotals, max int) map[schema.GroupVersionKind]*informerTotals {
+	if max <= 0 || len(kinds) <= max {
+		return kinds
+	}
+
+	gvks := make([]schema.GroupVersionKind, 0, len(kinds))
+	for gvk := range kinds {
+		gvks = append(gvks, gvk)
+	}
+	sort.Slice(gvks, func(i, j int) bool {
+		if kinds[gvks[i]].objects != kinds[gvks[j]].objects {
+			return kinds[gvks[i]].objects > kinds[gvks[j]].objects
+		}
+		return gvks[i].String() < gvks[j].String()
+	})
+
+	out := make(map[schema.GroupVersionKind]*informerTotals, max+1)
+	other := &informerTotals{}
+	for i, gvk := range gvks {
+		if i < max {
+			out[gvk] = kinds[gvk]
+			continue
+		}
+		other.objects += kinds[gvk].objects
+		other.bytes += kinds[gvk].bytes
+	}
+	out[schema.GroupVersionKind{}] = other
+	return out
+}
+
+// track the informer that backs the supplied object or list, so that the
+// objects it caches are reflected in our metrics. It must only be called once
+// the object or list has been successfully read from the cache, which ensures
+// the informer is running. The read is recorded to the context's Usage, if any;
+// a read is a cache hit if its informer was already tracked.
+func (s *session) track(ctx context.Context, obj runtime.Object) {
+	if s.cache == nil {
+		return
+	}
+	gvk, err := apiutil.GVKForObject(obj, s.client.Scheme())
+	if err != nil {
+		return
+	}
+
+	// This mirrors how controller-runtime's delegating client determines
+	// whether to read from the cache.
+	if meta.IsListType(obj) {
+		gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	}
+	if s.uncached[gvk] {
+		recordAPICall(ctx)
+		return
+	}
+
+	var o client.Object
+	switch obj.(type) {
+	case *unstructured.Unstructured, *unstructured.UnstructuredList:
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		o = u
+	default:
+		n, err := s.client.Scheme().New(gvk)
+		if err != nil {
+			return
+		}
+		co, ok := n.(client.Object)
+		if !ok {
+			return
+		}
+		o = co
+	}
+	_, u := o.(*unstructured.Unstructured)
+	k := watchKey{gvk: gvk, unstructured: u}
+
+	s.wmx.Lock()
+	defer s.wmx.Unlock()
+	_, hit := s.informers[k]
+	recordCacheRead(ctx, hit)
+	if hit {
+		return
+	}
+	i, err := s.cache.GetInformer(ctx, o)
+	if err != nil {
+		return
+	}
+	st := &informerStats{}
+	i.AddEventHandler(st)
+	if s.informers == nil {
+		s.informers = make(map[watchKey]*informerStats)
+	}
+	s.informers[k] = st
+}
+
+// informerStats counts the objects cached by an informer, and estimates their
+// size.
+type informerStats struct {
+	objects int64
+	bytes   int64
+}
+
+func (st *informerStats) OnAdd(obj interface{}) {
+	atomic.AddInt64(&st.objects, 1)
+	atomic.AddInt64(&st.bytes, size(obj))
+}
+
+func (st *informerStats) OnUpdate(old, obj interface{}) {
+	atomic.AddInt64(&st.bytes, size(obj)-size(old))
+}
+
+func (st *informerStats) OnDelete(obj interface{}) {
+	if d, ok := obj.(kcache.DeletedFinalStateUnknown); ok {
+		obj = d.Obj
+	}
+	atomic.AddInt64(&st.objects, -1)
+	atomic.AddInt64(&st.bytes, -size(obj))
+}
+
+// size estimates the memory used by the supplied object as its size when
+// encoded as JSON.
+func size(obj interface{}) int64 {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return 0
+	}
+	return int64(len(b))
+}
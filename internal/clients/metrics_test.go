@@ -0,0 +1,91 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kcache "k8s.io/client-go/tools/cache"
+)
+
+func TestInformerStats(t *testing.T) {
+	small := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cool"}}
+	large := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cool"}, Data: map[string]string{"key": "value"}}
+	other := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other"}}
+
+	st := &informerStats{}
+	st.OnAdd(small)
+	st.OnAdd(other)
+	st.OnUpdate(small, large)
+	st.OnDelete(kcache.DeletedFinalStateUnknown{Key: "other", Obj: other})
+
+	want := &informerStats{objects: 1, bytes: size(large)}
+	if diff := cmp.Diff(want, st, cmp.AllowUnexported(informerStats{})); diff != "" {
+		t.Errorf("informerStats: -want, +got:\n%s", diff)
+	}
+}
+
+func TestLimitKinds(t *testing.T) {
+	a := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "A"}
+	b := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "B"}
+	c := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "C"}
+	other := schema.GroupVersionKind{}
+
+	kinds := func() map[schema.GroupVersionKind]*informerTotals {
+		return map[schema.GroupVersionKind]*informerTotals{
+			a: {objects: 3, bytes: 30},
+			b: {objects: 1, bytes: 10},
+			c: {objects: 2, bytes: 20},
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		max    int
+		want   map[schema.GroupVersionKind]*informerTotals
+	}{
+		"NoLimit": {
+			reason: "All kinds should be returned if there is no limit.",
+			max:    0,
+			want:   kinds(),
+		},
+		"UnderLimit": {
+			reason: "All kinds should be returned if there are no more than the limit.",
+			max:    3,
+			want:   kinds(),
+		},
+		"OverLimit": {
+			reason: "The kinds with the most objects should be returned, and the rest summed under the empty kind.",
+			max:    1,
+			want: map[schema.GroupVersionKind]*informerTotals{
+				a:     {objects: 3, bytes: 30},
+				other: {objects: 3, bytes: 30},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := limitKinds(kinds(), tc.max)
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(informerTotals{})); diff != "" {
+				t.Errorf("\n%s\nlimitKinds(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	authnv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const errReviewToken = "cannot review token"
+
+// TokenReviews reviews bearer tokens. Callers are rarely permitted to create
+// token reviews, so unlike most clients TokenReviews uses xgql's own
+// credentials. It must be bound to the system:auth-delegator ClusterRole.
+type TokenReviews struct {
+	client kubernetes.Interface
+}
+
+// NewTokenReviews returns TokenReviews that connect to the API server using
+// the supplied REST config.
+func NewTokenReviews(c *rest.Config) (*TokenReviews, error) {
+	cs, err := kubernetes.NewForConfig(c)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClientset)
+	}
+	return &TokenReviews{client: cs}, nil
+}
+
+// Review the supplied bearer token.
+func (t *TokenReviews) Review(ctx context.Context, token string) (*authnv1.TokenReviewStatus, error) {
+	tr := &authnv1.TokenReview{Spec: authnv1.TokenReviewSpec{Token: token}}
+	tr, err := t.client.AuthenticationV1().TokenReviews().Create(ctx, tr, metav1.CreateOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, errReviewToken)
+	}
+	return &tr.Status, nil
+}
@@ -0,0 +1,289 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	kcache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	annotationLastApplied = "kubectl.kubernetes.io/last-applied-configuration"
+
+	// Objects that were too large to cache are cached with this annotation,
+	// and without their annotations or anything but their metadata.
+	annotationTruncated = "xgql.upbound.io/truncated"
+
+	// How long to wait to read a truncated object from the API server when
+	// sending it to watchers.
+	untruncateTimeout = 10 * time.Second
+)
+
+// StripMetadata configures whether cached objects are stripped of bulky
+// metadata that xgql callers rarely need, i.e. their managed fields and the
+// last-applied-configuration annotation. Objects read directly from the API
+// server, e.g. by uncached clients, are not stripped.
+func StripMetadata(strip bool) CacheOption {
+	return func(c *Cache) {
+		c.strip = strip
+	}
+}
+
+// WithMaxObjectSize configures the maximum size in bytes of a cached object,
+// after any metadata is stripped. Only the metadata of larger objects is
+// cached; clients read them directly from the API server. Objects of any size
+// are cached by default.
+func WithMaxObjectSize(bytes int) CacheOption {
+	return func(c *Cache) {
+		c.maxObjectSize = bytes
+	}
+}
+
+// transforms returns true if the cache transforms the objects it caches.
+func (c *Cache) transforms() bool {
+	return c.strip || c.maxObjectSize > 0
+}
+
+// transform returns a copy of the supplied config that transforms the objects
+// returned by the API server before they're cached.
+func (c *Cache) transform(cfg *rest.Config) *rest.Config {
+	if !c.transforms() {
+		return cfg
+	}
+	out := rest.CopyConfig(cfg)
+	wrap := cfg.WrapTransport
+	out.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+		if wrap != nil {
+			rt = wrap(rt)
+		}
+		return &transformer{wrapped: rt, strip: c.strip, max: c.maxObjectSize}
+	}
+	return out
+}
+
+// A transformer transforms the JSON objects returned by list and watch calls to
+// the API server.
+type transformer struct {
+	wrapped http.RoundTripper
+	strip   bool
+	max     int
+}
+
+func (t *transformer) RoundTrip(req *http.Request) (*http.Response, error) {
+	rsp, err := t.wrapped.RoundTrip(req)
+	if err != nil || req.Method != http.MethodGet || rsp.StatusCode != http.StatusOK {
+		return rsp, err
+	}
+	if mt, _, _ := mime.ParseMediaType(rsp.Header.Get("Content-Type")); mt != "application/json" {
+		return rsp, nil
+	}
+
+	if w, _ := strconv.ParseBool(req.URL.Query().Get("watch")); w {
+		rsp.Body = t.transformWatch(rsp.Body)
+		return rsp, nil
+	}
+
+	body, err := ioutil.ReadAll(rsp.Body)
+	_ = rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	rsp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+	obj := map[string]interface{}{}
+	if err := d.Decode(&obj); err != nil {
+		// This isn't something we know how to transform.
+		return rsp, nil
+	}
+	if items, ok := obj["items"].([]interface{}); ok {
+		for i := range items {
+			if item, ok := items[i].(map[string]interface{}); ok {
+				items[i] = t.transform(item)
+			}
+		}
+	} else {
+		obj = t.transform(obj)
+	}
+
+	out, err := json.Marshal(obj)
+	if err != nil {
+		return rsp, nil
+	}
+	rsp.Body = ioutil.NopCloser(bytes.NewReader(out))
+	rsp.ContentLength = int64(len(out))
+	rsp.Header.Del("Content-Length")
+	return rsp, nil
+}
+
+// transformWatch transforms the object of each event in the supplied stream.
+func (t *transformer) transformWatch(body io.ReadCloser) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		d := json.NewDecoder(body)
+		d.UseNumber()
+		e := json.NewEncoder(pw)
+		for {
+			event := map[string]interface{}{}
+			if err := d.Decode(&event); err != nil {
+				// Our reader will see io.EOF if the watch ended cleanly.
+				_ = pw.CloseWithError(err)
+				return
+			}
+			if obj, ok := event["object"].(map[string]interface{}); ok && event["type"] != "ERROR" {
+				event["object"] = t.transform(obj)
+			}
+			if err := e.Encode(event); err != nil {
+				// Our reader was closed.
+				return
+			}
+		}
+	}()
+	return &pipeBody{PipeReader: pr, body: body}
+}
+
+// transform the supplied object, which is modified in place.
+func (t *transformer) transform(obj map[string]interface{}) map[string]interface{} {
+	md, ok := obj["metadata"].(map[string]interface{})
+	if !ok {
+		return obj
+	}
+	if t.strip {
+		delete(md, "managedFields")
+		if a, ok := md["annotations"].(map[string]interface{}); ok {
+			delete(a, annotationLastApplied)
+			if len(a) == 0 {
+				delete(md, "annotations")
+			}
+		}
+	}
+
+	if t.max <= 0 {
+		return obj
+	}
+	b, err := json.Marshal(obj)
+	if err != nil || len(b) <= t.max {
+		return obj
+	}
+
+	// We keep most of the object's metadata, because the cache needs it to
+	// track the object and to filter it by label.
+	delete(md, "managedFields")
+	md["annotations"] = map[string]interface{}{annotationTruncated: "true"}
+	return map[string]interface{}{
+		"apiVersion": obj["apiVersion"],
+		"kind":       obj["kind"],
+		"metadata":   md,
+	}
+}
+
+// A pipeBody is the body of a transformed watch response. Closing it closes
+// the original body too, which stops the transforming goroutine.
+type pipeBody struct {
+	*io.PipeReader
+	body io.ReadCloser
+}
+
+func (b *pipeBody) Close() error {
+	_ = b.PipeReader.Close()
+	return b.body.Close()
+}
+
+func truncated(obj client.Object) bool {
+	_, ok := obj.GetAnnotations()[annotationTruncated]
+	return ok
+}
+
+// untruncate replaces the supplied object, which was read from the cache, with
+// the object stored by the API server if it was too large to be cached.
+func (s *session) untruncate(ctx context.Context, obj client.Object) error {
+	if !s.truncates || !truncated(obj) {
+		return nil
+	}
+	recordAPICall(ctx)
+	return s.direct.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, obj)
+}
+
+// untruncateList replaces any object in the supplied list that was too large
+// to be cached with the object stored by the API server.
+func (s *session) untruncateList(ctx context.Context, l client.ObjectList) error {
+	if !s.truncates {
+		return nil
+	}
+	items, err := meta.ExtractList(l)
+	if err != nil {
+		return err
+	}
+	for _, i := range items {
+		o, ok := i.(client.Object)
+		if !ok {
+			continue
+		}
+		if err := s.untruncate(ctx, o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// An untruncatingHandler sends watchers the objects stored by the API server
+// in place of any objects that were too large to be cached. Deleted objects
+// are sent as they were cached.
+type untruncatingHandler struct {
+	kcache.ResourceEventHandler
+	session *session
+}
+
+func (h *untruncatingHandler) OnAdd(obj interface{}) {
+	h.ResourceEventHandler.OnAdd(h.untruncate(obj))
+}
+
+func (h *untruncatingHandler) OnUpdate(old, obj interface{}) {
+	h.ResourceEventHandler.OnUpdate(old, h.untruncate(obj))
+}
+
+func (h *untruncatingHandler) untruncate(obj interface{}) interface{} {
+	o, ok := obj.(client.Object)
+	if !ok || !truncated(o) {
+		return obj
+	}
+
+	// The supplied object is shared with the cache, so we must not modify it.
+	full, ok := o.DeepCopyObject().(client.Object)
+	if !ok {
+		return obj
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), untruncateTimeout)
+	defer cancel()
+	if err := h.session.untruncate(ctx, full); err != nil {
+		h.session.log.Debug("Cannot read truncated object", "error", err)
+		return obj
+	}
+	return full
+}
@@ -0,0 +1,105 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type roundTripperFn func(req *http.Request) (*http.Response, error)
+
+func (fn roundTripperFn) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+func TestTransformerRoundTrip(t *testing.T) {
+	bulky := `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cool","managedFields":[{"manager":"kubectl"}],"annotations":{"kubectl.kubernetes.io/last-applied-configuration":"{}"}},"data":{"big":"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"}}`
+	stripped := `{"apiVersion":"v1","data":{"big":"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"},"kind":"ConfigMap","metadata":{"name":"cool"}}`
+	truncated := `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"annotations":{"xgql.upbound.io/truncated":"true"},"name":"cool"}}`
+
+	type args struct {
+		url         string
+		contentType string
+		body        string
+	}
+
+	cases := map[string]struct {
+		reason string
+		t      *transformer
+		args   args
+		want   string
+	}{
+		"NotJSON": {
+			reason: "Responses that aren't JSON should not be transformed.",
+			t:      &transformer{strip: true},
+			args:   args{url: "/api/v1/configmaps", contentType: "application/vnd.kubernetes.protobuf", body: bulky},
+			want:   bulky,
+		},
+		"StripList": {
+			reason: "Bulky metadata should be stripped from each item of a list.",
+			t:      &transformer{strip: true},
+			args:   args{url: "/api/v1/configmaps", contentType: "application/json", body: `{"kind":"ConfigMapList","items":[` + bulky + `]}`},
+			want:   `{"items":[` + stripped + `],"kind":"ConfigMapList"}`,
+		},
+		"TruncateList": {
+			reason: "Only the metadata of items that are too large should be kept.",
+			t:      &transformer{strip: true, max: 100},
+			args:   args{url: "/api/v1/configmaps", contentType: "application/json", body: `{"kind":"ConfigMapList","items":[` + bulky + `]}`},
+			want:   `{"items":[` + truncated + `],"kind":"ConfigMapList"}`,
+		},
+		"StripWatch": {
+			reason: "Bulky metadata should be stripped from the object of each watch event.",
+			t:      &transformer{strip: true},
+			args: args{
+				url:         "/api/v1/configmaps?watch=true",
+				contentType: "application/json",
+				body:        `{"type":"ADDED","object":` + bulky + "}\n" + `{"type":"DELETED","object":` + bulky + "}\n",
+			},
+			want: `{"object":` + stripped + `,"type":"ADDED"}` + "\n" + `{"object":` + stripped + `,"type":"DELETED"}` + "\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.t.wrapped = roundTripperFn(func(req *http.Request) (*http.Response, error) {
+				rsp := &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{tc.args.contentType}},
+					Body:       ioutil.NopCloser(strings.NewReader(tc.args.body)),
+				}
+				return rsp, nil
+			})
+
+			rsp, err := tc.t.RoundTrip(httptest.NewRequest(http.MethodGet, tc.args.url, nil))
+			if err != nil {
+				t.Fatalf("\n%s\nt.RoundTrip(...): %s", tc.reason, err)
+			}
+			defer rsp.Body.Close()
+			got, err := ioutil.ReadAll(rsp.Body)
+			if err != nil {
+				t.Fatalf("\n%s\nioutil.ReadAll(...): %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+				t.Errorf("\n%s\nt.RoundTrip(...): -want body, +got body:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
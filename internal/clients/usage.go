@@ -0,0 +1,81 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type usageKey struct{}
+
+// A Usage counts the calls clients make on behalf of a request, e.g. a GraphQL
+// operation. Its methods are safe to call concurrently.
+type Usage struct {
+	apiCalls    int64
+	cacheHits   int64
+	cacheMisses int64
+}
+
+// WithUsage returns a context that records the calls clients make using it to
+// the supplied Usage.
+func WithUsage(ctx context.Context, u *Usage) context.Context {
+	return context.WithValue(ctx, usageKey{}, u)
+}
+
+// APICalls returns the number of calls made to the API server. Reads that
+// started an informer are included, because the informer had to list and
+// watch the kind of object that was read.
+func (u *Usage) APICalls() int64 {
+	return atomic.LoadInt64(&u.apiCalls)
+}
+
+// CacheHits returns the number of reads served by an informer that was
+// already running.
+func (u *Usage) CacheHits() int64 {
+	return atomic.LoadInt64(&u.cacheHits)
+}
+
+// CacheMisses returns the number of reads that started an informer.
+func (u *Usage) CacheMisses() int64 {
+	return atomic.LoadInt64(&u.cacheMisses)
+}
+
+func usageFrom(ctx context.Context) *Usage {
+	u, _ := ctx.Value(usageKey{}).(*Usage)
+	return u
+}
+
+// recordAPICall records a call made directly to the API server.
+func recordAPICall(ctx context.Context) {
+	if u := usageFrom(ctx); u != nil {
+		atomic.AddInt64(&u.apiCalls, 1)
+	}
+}
+
+// recordCacheRead records a read served by the cache. A miss is also an API
+// call, because the cache had to start an informer to serve it.
+func recordCacheRead(ctx context.Context, hit bool) {
+	u := usageFrom(ctx)
+	if u == nil {
+		return
+	}
+	if hit {
+		atomic.AddInt64(&u.cacheHits, 1)
+		return
+	}
+	atomic.AddInt64(&u.cacheMisses, 1)
+	atomic.AddInt64(&u.apiCalls, 1)
+}
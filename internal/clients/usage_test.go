@@ -0,0 +1,43 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUsage(t *testing.T) {
+	u := &Usage{}
+	ctx := WithUsage(context.Background(), u)
+
+	recordAPICall(ctx)
+	recordCacheRead(ctx, true)
+	recordCacheRead(ctx, true)
+	recordCacheRead(ctx, false)
+
+	// Calls made without a Usage should not be recorded anywhere.
+	recordAPICall(context.Background())
+	recordCacheRead(context.Background(), false)
+
+	type counts struct{ APICalls, CacheHits, CacheMisses int64 }
+	want := counts{APICalls: 2, CacheHits: 2, CacheMisses: 1}
+	got := counts{APICalls: u.APICalls(), CacheHits: u.CacheHits(), CacheMisses: u.CacheMisses()}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Usage: -want, +got:\n%s", diff)
+	}
+}
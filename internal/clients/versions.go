@@ -0,0 +1,137 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type versionsKey struct{}
+
+// Versions records the resource versions of the objects clients read on
+// behalf of a request, e.g. a GraphQL query. Two requests that read the same
+// versions of the same objects read the same data. Its methods are safe to
+// call concurrently.
+type Versions struct {
+	mx   sync.Mutex
+	read map[string]bool
+
+	// Whether any data was read that we can't version, e.g. because a read
+	// failed or because the data has no resource version.
+	unversioned bool
+}
+
+// WithVersions returns a context that records the resource versions of the
+// objects clients read using it to the supplied Versions.
+func WithVersions(ctx context.Context, v *Versions) context.Context {
+	return context.WithValue(ctx, versionsKey{}, v)
+}
+
+// Sum returns a digest of the versions of all objects read. It returns false
+// if nothing was read, or if anything was read that could not be versioned.
+func (v *Versions) Sum() (string, bool) {
+	v.mx.Lock()
+	defer v.mx.Unlock()
+	if v.unversioned || len(v.read) == 0 {
+		return "", false
+	}
+
+	read := make([]string, 0, len(v.read))
+	for r := range v.read {
+		read = append(read, r)
+	}
+	sort.Strings(read)
+
+	h := sha256.New()
+	for _, r := range read {
+		_, _ = h.Write([]byte(r))
+		_, _ = h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+func (v *Versions) add(o metav1.Object) {
+	v.mx.Lock()
+	defer v.mx.Unlock()
+	if o.GetResourceVersion() == "" {
+		v.unversioned = true
+		return
+	}
+	if v.read == nil {
+		v.read = make(map[string]bool)
+	}
+	v.read[string(o.GetUID())+"@"+o.GetResourceVersion()] = true
+}
+
+func (v *Versions) fail() {
+	v.mx.Lock()
+	defer v.mx.Unlock()
+	v.unversioned = true
+}
+
+// recordVersions records the resource versions of the supplied object, or of
+// the items of the supplied list, to the context's Versions, if any. A failed
+// read is recorded as unversioned; an object that didn't exist may exist next
+// time.
+func recordVersions(ctx context.Context, obj runtime.Object, err error) {
+	v, _ := ctx.Value(versionsKey{}).(*Versions)
+	if v == nil {
+		return
+	}
+	if err != nil {
+		v.fail()
+		return
+	}
+
+	if !meta.IsListType(obj) {
+		a, err := meta.Accessor(obj)
+		if err != nil {
+			v.fail()
+			return
+		}
+		v.add(a)
+		return
+	}
+
+	items, err := meta.ExtractList(obj)
+	if err != nil {
+		v.fail()
+		return
+	}
+	for _, i := range items {
+		a, err := meta.Accessor(i)
+		if err != nil {
+			v.fail()
+			return
+		}
+		v.add(a)
+	}
+}
+
+// recordUnversioned records that data was read that can't be versioned, e.g.
+// the results of API discovery.
+func recordUnversioned(ctx context.Context) {
+	if v, _ := ctx.Value(versionsKey{}).(*Versions); v != nil {
+		v.fail()
+	}
+}
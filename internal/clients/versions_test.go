@@ -0,0 +1,125 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestVersions(t *testing.T) {
+	errBoom := errors.New("boom")
+	cm := func(uid, rv string) corev1.ConfigMap {
+		return corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{UID: types.UID(uid), ResourceVersion: rv}}
+	}
+
+	type read struct {
+		obj runtime.Object
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		reads  []read
+		other  []read
+		same   bool
+		ok     bool
+	}{
+		"NothingRead": {
+			reason: "Nothing can be versioned if nothing was read.",
+			ok:     false,
+		},
+		"SameVersions": {
+			reason: "Reading the same versions of the same objects should produce the same sum, regardless of order.",
+			reads: []read{
+				{obj: &corev1.ConfigMapList{Items: []corev1.ConfigMap{cm("a", "1"), cm("b", "2")}}},
+			},
+			other: []read{
+				{obj: &corev1.ConfigMapList{Items: []corev1.ConfigMap{cm("b", "2"), cm("a", "1")}}},
+			},
+			same: true,
+			ok:   true,
+		},
+		"DifferentVersions": {
+			reason: "Reading a different version of an object should produce a different sum.",
+			reads:  []read{{obj: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{UID: "a", ResourceVersion: "1"}}}},
+			other:  []read{{obj: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{UID: "a", ResourceVersion: "2"}}}},
+			same:   false,
+			ok:     true,
+		},
+		"FailedRead": {
+			reason: "Nothing can be versioned if any read failed.",
+			reads: []read{
+				{obj: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{UID: "a", ResourceVersion: "1"}}},
+				{obj: &corev1.ConfigMap{}, err: errBoom},
+			},
+			ok: false,
+		},
+		"Unversioned": {
+			reason: "Nothing can be versioned if any object read had no resource version.",
+			reads: []read{
+				{obj: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{UID: "a", ResourceVersion: "1"}}},
+				{obj: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{UID: "b"}}},
+			},
+			ok: false,
+		},
+	}
+
+	sum := func(reads []read) (string, bool) {
+		v := &Versions{}
+		ctx := WithVersions(context.Background(), v)
+		for _, r := range reads {
+			recordVersions(ctx, r.obj, r.err)
+		}
+		return v.Sum()
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s, ok := sum(tc.reads)
+			if diff := cmp.Diff(tc.ok, ok); diff != "" {
+				t.Errorf("\n%s\nv.Sum(): -want ok, +got ok:\n%s", tc.reason, diff)
+			}
+			if tc.other == nil {
+				return
+			}
+			o, _ := sum(tc.other)
+			if diff := cmp.Diff(tc.same, s == o); diff != "" {
+				t.Errorf("\n%s\nv.Sum(): -want same, +got same:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRecordUnversioned(t *testing.T) {
+	v := &Versions{}
+	ctx := WithVersions(context.Background(), v)
+	recordVersions(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{UID: "a", ResourceVersion: "1"}}, nil)
+	recordUnversioned(ctx)
+
+	// Reads made without Versions should not be recorded anywhere.
+	recordUnversioned(context.Background())
+
+	if _, ok := v.Sum(); ok {
+		t.Errorf("v.Sum(): want false after an unversioned read, got true")
+	}
+}
@@ -0,0 +1,463 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	kcache "k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/upbound/xgql/internal/auth"
+)
+
+const (
+	errGetGVK        = "cannot determine kind of object to watch"
+	errGetInformer   = "cannot get informer"
+	errGetMapping    = "cannot determine resource of object to watch"
+	errNewDynamic    = "cannot create new dynamic client"
+	errListObject    = "cannot list object to watch"
+	errNewRetryWatch = "cannot start watch"
+)
+
+// DefaultWatchBuffer is the default number of events buffered for each
+// watcher.
+const DefaultWatchBuffer = 100
+
+// An OverflowPolicy determines what happens when a watcher falls so far behind
+// that its buffer is full. Events are always dropped rather than blocking the
+// underlying watch (and thus all other watchers that share it).
+type OverflowPolicy string
+
+// Overflow policies.
+const (
+	// DropNewest drops the event that would overflow the buffer, so the
+	// watcher misses the most recent events.
+	DropNewest OverflowPolicy = "drop-newest"
+
+	// DropOldest drops the oldest buffered event to make room for the event
+	// that would overflow the buffer, so the watcher misses older events.
+	DropOldest OverflowPolicy = "drop-oldest"
+)
+
+// WithWatchBuffer configures the number of events buffered for each watcher.
+// DefaultWatchBuffer events are buffered by default.
+func WithWatchBuffer(n int) CacheOption {
+	return func(c *Cache) {
+		c.wbuffer = n
+	}
+}
+
+// WithOverflowPolicy configures what happens when a watcher's buffer is full.
+// The DropNewest policy is used by default.
+func WithOverflowPolicy(p OverflowPolicy) CacheOption {
+	return func(c *Cache) {
+		c.woverflow = p
+	}
+}
+
+// A WatchEvent is a change to a watched object.
+type WatchEvent struct {
+	// Type of change, i.e. Added, Modified, or Deleted.
+	Type watch.EventType
+
+	// Object that changed. Deleted objects are in their last known state.
+	// Objects are shared by all watchers and must not be mutated.
+	Object client.Object
+}
+
+// Watch objects of the supplied kind using the specified credentials. Events
+// are sent to the returned channel until the supplied context is cancelled or
+// the underlying client cache stops, at which point the channel is closed.
+//
+// All watches of the same kind made using the same credentials share the
+// informer that backs the cache of the client returned by Get, so each kind is
+// watched at most once per client. Note that informers replay all extant
+// objects as Added events when they start watching.
+func (c *Cache) Watch(ctx context.Context, cr auth.Credentials, obj client.Object, o ...GetOption) (<-chan WatchEvent, error) {
+	sn, err := c.get(cr, o...)
+	if err != nil {
+		return nil, err
+	}
+	return sn.watch(ctx, obj, c.newBroadcaster)
+}
+
+// Object watches are keyed by the credentials used to watch the object. It's
+// not safe to share a watch between subjects with different RBAC access.
+type objectWatchKey struct {
+	creds     string
+	gvk       schema.GroupVersionKind
+	namespace string
+	name      string
+}
+
+// WatchObject watches the object of the supplied kind, namespace, and name
+// using the specified credentials. The object's current state is sent to the
+// returned channel as an Added event (if the object exists), followed by an
+// event each time it changes. The channel is closed when the supplied context
+// is cancelled or the object is deleted.
+//
+// Unlike Watch, WatchObject does not use an informer. It starts an API server
+// watch that uses a field selector to watch exactly one object, which is
+// considerably cheaper than watching all objects of a kind when only one is of
+// interest. All watches of the same object made using the same credentials
+// share one API server watch, which is stopped when its last watcher stops.
+func (c *Cache) WatchObject(ctx context.Context, cr auth.Credentials, gvk schema.GroupVersionKind, namespace, name string) (<-chan WatchEvent, error) {
+	k := objectWatchKey{creds: cr.Hash(c.salt), gvk: gvk, namespace: namespace, name: name}
+
+	c.omx.Lock()
+	b, ok := c.objects[k]
+	if !ok {
+		var err error
+		if b, err = c.startObjectWatch(ctx, k, cr); err != nil {
+			c.omx.Unlock()
+			return nil, err
+		}
+		c.objects[k] = b
+	}
+	ch := b.subscribe()
+	c.omx.Unlock()
+
+	go func() {
+		select {
+		case <-b.done:
+			return
+		case <-ctx.Done():
+		}
+
+		c.omx.Lock()
+		defer c.omx.Unlock()
+		if b.unsubscribe(ch) > 0 {
+			return
+		}
+
+		// We were the last watcher. Stop the API server watch.
+		b.stop()
+		if c.objects[k] == b {
+			delete(c.objects, k)
+		}
+	}()
+
+	return ch, nil
+}
+
+func (c *Cache) startObjectWatch(ctx context.Context, k objectWatchKey, cr auth.Credentials) (*broadcaster, error) {
+	sn, err := c.get(cr)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := sn.RESTMapper().RESTMapping(k.gvk.GroupKind(), k.gvk.Version)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetMapping)
+	}
+
+	dc, err := dynamic.NewForConfig(cr.Inject(c.cfg))
+	if err != nil {
+		return nil, errors.Wrap(err, errNewDynamic)
+	}
+	ri := dc.Resource(m.Resource).Namespace(k.namespace)
+	fs := fields.OneTermEqualSelector("metadata.name", k.name).String()
+
+	// We list rather than get the object in order to determine the resource
+	// version from which to start watching, even if the object doesn't exist.
+	l, err := ri.List(ctx, metav1.ListOptions{FieldSelector: fs})
+	if err != nil {
+		return nil, errors.Wrap(err, errListObject)
+	}
+
+	// The watch outlives the context of the watcher that started it. It's
+	// stopped when its last watcher stops.
+	wctx, cancel := context.WithCancel(context.Background())
+
+	// A RetryWatcher transparently restarts the watch when the API server
+	// closes it, which API servers do periodically.
+	rw, err := watchtools.NewRetryWatcher(l.GetResourceVersion(), &kcache.ListWatch{
+		WatchFunc: func(o metav1.ListOptions) (watch.Interface, error) {
+			o.FieldSelector = fs
+			return ri.Watch(wctx, o)
+		},
+	})
+	if err != nil {
+		cancel()
+		return nil, errors.Wrap(err, errNewRetryWatch)
+	}
+
+	// Watchers that join later should start with the object's current state,
+	// just like the watcher that started the watch.
+	b := c.newBroadcaster()
+	b.replay = true
+	for i := range l.Items {
+		b.send(watch.Added, &l.Items[i])
+	}
+
+	log := c.log.WithValues("gvk", k.gvk, "namespace", k.namespace, "name", k.name)
+	go func() {
+		defer log.Debug("Stopped object watch")
+		defer c.stopObjectWatch(k, b)
+		defer cancel()
+		defer rw.Stop()
+		for {
+			select {
+			case <-b.done:
+				return
+			case e, ok := <-rw.ResultChan():
+				if !ok {
+					return
+				}
+				if _, ok := e.Object.(client.Object); !ok {
+					// Probably a watch.Error. The RetryWatcher will try to
+					// recover from it if it can.
+					continue
+				}
+				b.send(e.Type, e.Object)
+				if e.Type == watch.Deleted {
+					return
+				}
+			}
+		}
+	}()
+
+	log.Debug("Started object watch")
+	return b, nil
+}
+
+func (c *Cache) stopObjectWatch(k objectWatchKey, b *broadcaster) {
+	c.omx.Lock()
+	defer c.omx.Unlock()
+	b.stop()
+	if c.objects[k] == b {
+		delete(c.objects, k)
+	}
+}
+
+func (c *Cache) newBroadcaster() *broadcaster {
+	return &broadcaster{
+		subs:     make(map[chan WatchEvent]struct{}),
+		done:     make(chan struct{}),
+		buffer:   c.wbuffer,
+		overflow: c.woverflow,
+	}
+}
+
+// Watches are keyed by kind. The cache maintains distinct informers for typed
+// and unstructured objects of the same kind.
+type watchKey struct {
+	gvk          schema.GroupVersionKind
+	unstructured bool
+}
+
+func (s *session) watch(ctx context.Context, obj client.Object, newBroadcaster func() *broadcaster) (<-chan WatchEvent, error) {
+	gvk, err := apiutil.GVKForObject(obj, s.client.Scheme())
+	if err != nil {
+		return nil, errors.Wrap(err, errGetGVK)
+	}
+	_, u := obj.(*unstructured.Unstructured)
+	k := watchKey{gvk: gvk, unstructured: u}
+
+	s.wmx.Lock()
+	b, ok := s.watches[k]
+	if !ok {
+		i, err := s.cache.GetInformer(ctx, obj)
+		if err != nil {
+			s.wmx.Unlock()
+			return nil, errors.Wrap(err, errGetInformer)
+		}
+		b = newBroadcaster()
+		if s.truncates {
+			i.AddEventHandler(&untruncatingHandler{ResourceEventHandler: b, session: s})
+		} else {
+			i.AddEventHandler(b)
+		}
+		if s.watches == nil {
+			s.watches = make(map[watchKey]*broadcaster)
+		}
+		s.watches[k] = b
+	}
+	ch := b.subscribe()
+
+	// Each watcher holds a reference to our session, so that it won't expire
+	// while the watcher is listening, even if it makes no other client calls.
+	s.refs++
+	s.wmx.Unlock()
+	s.log.Debug("Started watch", "gvk", gvk)
+
+	go func() {
+		select {
+		case <-b.done:
+			// Our session was stopped, so there's nothing to release.
+		case <-ctx.Done():
+			s.unwatch(k, b, ch)
+			s.release()
+			s.log.Debug("Stopped watch", "gvk", gvk)
+		}
+	}()
+
+	return ch, nil
+}
+
+// unwatch unsubscribes the supplied channel from the supplied broadcaster. The
+// broadcaster is stopped when its last subscriber unsubscribes. Informers can't
+// remove event handlers, so the stopped broadcaster remains registered with
+// its informer, but it no longer holds any events.
+func (s *session) unwatch(k watchKey, b *broadcaster, ch chan WatchEvent) {
+	s.wmx.Lock()
+	defer s.wmx.Unlock()
+	if b.unsubscribe(ch) > 0 {
+		return
+	}
+	b.stop()
+	if s.watches[k] == b {
+		delete(s.watches, k)
+	}
+}
+
+func (s *session) stopWatches() {
+	s.wmx.Lock()
+	defer s.wmx.Unlock()
+	for _, b := range s.watches {
+		b.stop()
+	}
+	s.watches = nil
+}
+
+// A broadcaster fans the events of a shared watch out to its subscribers. Each
+// subscriber has its own buffer, so that one slow subscriber can't block the
+// others.
+type broadcaster struct {
+	mx   sync.RWMutex
+	subs map[chan WatchEvent]struct{}
+	done chan struct{}
+
+	buffer   int
+	overflow OverflowPolicy
+
+	// If replay is true new subscribers are first sent the latest event.
+	replay bool
+	latest *WatchEvent
+}
+
+func (b *broadcaster) subscribe() chan WatchEvent {
+	ch := make(chan WatchEvent, b.buffer)
+
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	if b.replay && b.latest != nil && b.buffer > 0 {
+		ch <- *b.latest
+	}
+
+	select {
+	case <-b.done:
+		// We've already stopped; there will be no more events.
+		close(ch)
+	default:
+		b.subs[ch] = struct{}{}
+	}
+	return ch
+}
+
+// unsubscribe the supplied channel, returning the number of subscribers that
+// remain.
+func (b *broadcaster) unsubscribe(ch chan WatchEvent) int {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	if _, ok := b.subs[ch]; ok {
+		delete(b.subs, ch)
+		close(ch)
+	}
+	return len(b.subs)
+}
+
+func (b *broadcaster) stop() {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	for ch := range b.subs {
+		delete(b.subs, ch)
+		close(ch)
+	}
+	select {
+	case <-b.done:
+	default:
+		close(b.done)
+	}
+}
+
+func (b *broadcaster) send(t watch.EventType, obj interface{}) {
+	o, ok := obj.(client.Object)
+	if !ok {
+		return
+	}
+	e := WatchEvent{Type: t, Object: o}
+
+	if b.replay {
+		b.mx.Lock()
+		b.latest = &e
+		b.mx.Unlock()
+	}
+
+	b.mx.RLock()
+	defer b.mx.RUnlock()
+
+	for ch := range b.subs {
+		select {
+		case ch <- e:
+			continue
+		default:
+		}
+
+		// This subscriber isn't keeping up.
+		if b.overflow != DropOldest {
+			continue
+		}
+		select {
+		case <-ch:
+		default:
+		}
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+func (b *broadcaster) OnAdd(obj interface{}) {
+	b.send(watch.Added, obj)
+}
+
+func (b *broadcaster) OnUpdate(_, obj interface{}) {
+	b.send(watch.Modified, obj)
+}
+
+func (b *broadcaster) OnDelete(obj interface{}) {
+	// The informer may have missed the deletion, in which case it tells us
+	// about the last state of the object it knew about.
+	if d, ok := obj.(kcache.DeletedFinalStateUnknown); ok {
+		obj = d.Obj
+	}
+	b.send(watch.Deleted, obj)
+}
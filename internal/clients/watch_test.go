@@ -0,0 +1,139 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clients
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	kcache "k8s.io/client-go/tools/cache"
+)
+
+func TestBroadcaster(t *testing.T) {
+	a := &corev1.Event{ObjectMeta: metav1.ObjectMeta{Name: "a"}}
+	b := &corev1.Event{ObjectMeta: metav1.ObjectMeta{Name: "b"}}
+
+	bc := NewCache(nil, nil).newBroadcaster()
+	one := bc.subscribe()
+	two := bc.subscribe()
+
+	bc.OnAdd(a)
+	bc.OnUpdate(a, b)
+	bc.OnDelete(kcache.DeletedFinalStateUnknown{Key: "b", Obj: b})
+	bc.OnAdd("not an object")
+
+	want := []WatchEvent{
+		{Type: watch.Added, Object: a},
+		{Type: watch.Modified, Object: b},
+		{Type: watch.Deleted, Object: b},
+	}
+
+	// Each subscriber should receive every event.
+	for name, ch := range map[string]chan WatchEvent{"one": one, "two": two} {
+		bc.unsubscribe(ch)
+		got := []WatchEvent{}
+		for e := range ch {
+			got = append(got, e)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("subscriber %s: -want, +got:\n%s", name, diff)
+		}
+	}
+
+	// A subscriber that falls behind should miss events rather than block.
+	slow := bc.subscribe()
+	for i := 0; i < DefaultWatchBuffer+1; i++ {
+		bc.OnAdd(a)
+	}
+	if got := len(slow); got != DefaultWatchBuffer {
+		t.Errorf("slow subscriber: want %d buffered events, got %d", DefaultWatchBuffer, got)
+	}
+
+	// Stopping should close all subscribers, and any subsequent subscribers.
+	bc.stop()
+	for range slow {
+	}
+	if _, ok := <-bc.subscribe(); ok {
+		t.Errorf("subscribe(...) after stop(): want closed channel")
+	}
+}
+
+func TestBroadcasterOverflow(t *testing.T) {
+	a := &corev1.Event{ObjectMeta: metav1.ObjectMeta{Name: "a"}}
+	b := &corev1.Event{ObjectMeta: metav1.ObjectMeta{Name: "b"}}
+	c := &corev1.Event{ObjectMeta: metav1.ObjectMeta{Name: "c"}}
+
+	cases := map[string]struct {
+		reason string
+		o      []CacheOption
+		replay bool
+		want   []WatchEvent
+	}{
+		"DropNewest": {
+			reason: "A slow subscriber should miss the newest events by default.",
+			o:      []CacheOption{WithWatchBuffer(2)},
+			want: []WatchEvent{
+				{Type: watch.Added, Object: a},
+				{Type: watch.Modified, Object: b},
+			},
+		},
+		"DropOldest": {
+			reason: "A slow subscriber should miss the oldest events when configured to.",
+			o:      []CacheOption{WithWatchBuffer(2), WithOverflowPolicy(DropOldest)},
+			want: []WatchEvent{
+				{Type: watch.Modified, Object: b},
+				{Type: watch.Modified, Object: c},
+			},
+		},
+		"Replay": {
+			reason: "A new subscriber should first be sent the latest event when replay is enabled.",
+			o:      []CacheOption{WithWatchBuffer(2)},
+			replay: true,
+			want: []WatchEvent{
+				{Type: watch.Modified, Object: c},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			bc := NewCache(nil, nil, tc.o...).newBroadcaster()
+			bc.replay = tc.replay
+
+			var ch chan WatchEvent
+			if !tc.replay {
+				ch = bc.subscribe()
+			}
+			bc.OnAdd(a)
+			bc.OnUpdate(a, b)
+			bc.OnUpdate(b, c)
+			if tc.replay {
+				ch = bc.subscribe()
+			}
+			bc.stop()
+
+			got := []WatchEvent{}
+			for e := range ch {
+				got = append(got, e)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nbroadcaster: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package config loads xgql's optional configuration file, and reloads it when
+// it changes so that xgql may be tuned without restarting it.
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"io/ioutil"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/upbound/xgql/internal/clients"
+	"github.com/upbound/xgql/internal/feature"
+)
+
+// DefaultReloadInterval is the default interval at which a Watcher checks its
+// configuration file for changes.
+const DefaultReloadInterval = 10 * time.Second
+
+const (
+	errReadConfig    = "cannot read configuration file"
+	errDecodeConfig  = "cannot decode configuration file"
+	errInvalidConfig = "invalid configuration file"
+
+	errFmtNegative = "%s must not be negative"
+	errFmtEmpty    = "%s must not be empty"
+	errFmtOverflow = "cache.watchOverflow must be %q or %q"
+	errFmtFeature  = "unknown feature %q"
+)
+
+// Config is xgql's configuration file. All fields are optional. Fields that are
+// set take precedence over the corresponding flags. Unknown fields are errors.
+//
+// Limits and features are reloaded when the file changes. Auth and cache
+// configuration is only read when xgql starts.
+type Config struct {
+	Auth     Auth            `json:"auth,omitempty"`
+	Limits   Limits          `json:"limits,omitempty"`
+	Cache    Cache           `json:"cache,omitempty"`
+	Features map[string]bool `json:"features,omitempty"`
+}
+
+// Auth configures how callers authenticate to xgql.
+type Auth struct {
+	// TokenCookie is the name of a cookie from which to read the caller's
+	// bearer token if they don't supply an Authorization header. Reading
+	// tokens from cookies is disabled if it is empty.
+	TokenCookie *string `json:"tokenCookie,omitempty"`
+
+	// CSRFCookie is the name of the cookie used to protect callers who
+	// authenticate using a token cookie from cross-site request forgery.
+	CSRFCookie *string `json:"csrfCookie,omitempty"`
+}
+
+// Limits bound the work done to resolve each GraphQL operation.
+type Limits struct {
+	// NestedLimit is the default maximum number of nodes returned by
+	// connections nested within a list. Zero disables the limit.
+	NestedLimit *int `json:"nestedLimit,omitempty"`
+
+	// Concurrency is the maximum number of Kubernetes objects each resolver
+	// may get concurrently.
+	Concurrency *int `json:"concurrency,omitempty"`
+}
+
+// Cache configures xgql's per-caller client caches.
+type Cache struct {
+	// Expiry is how long a client cache lives without being used.
+	Expiry *metav1.Duration `json:"expiry,omitempty"`
+
+	// WatchBuffer is the number of events buffered for each subscription
+	// before events are dropped.
+	WatchBuffer *int `json:"watchBuffer,omitempty"`
+
+	// WatchOverflow determines which events to drop when a subscription's
+	// buffer is full.
+	WatchOverflow *clients.OverflowPolicy `json:"watchOverflow,omitempty"`
+}
+
+// Validate this configuration.
+func (c *Config) Validate() error {
+	for name, v := range map[string]*int{
+		"limits.nestedLimit": c.Limits.NestedLimit,
+		"limits.concurrency": c.Limits.Concurrency,
+		"cache.watchBuffer":  c.Cache.WatchBuffer,
+	} {
+		if v != nil && *v < 0 {
+			return errors.Errorf(errFmtNegative, name)
+		}
+	}
+	if c.Auth.CSRFCookie != nil && *c.Auth.CSRFCookie == "" {
+		return errors.Errorf(errFmtEmpty, "auth.csrfCookie")
+	}
+	if c.Cache.Expiry != nil && c.Cache.Expiry.Duration < 0 {
+		return errors.Errorf(errFmtNegative, "cache.expiry")
+	}
+	if p := c.Cache.WatchOverflow; p != nil && *p != clients.DropNewest && *p != clients.DropOldest {
+		return errors.Errorf(errFmtOverflow, clients.DropNewest, clients.DropOldest)
+	}
+	for f := range c.Features {
+		if !feature.IsKnown(f) {
+			return errors.Errorf(errFmtFeature, f)
+		}
+	}
+	return nil
+}
+
+// Feature returns whether the supplied feature is enabled, and true if it is
+// configured. It is safe to call on a nil Config.
+func (c *Config) Feature(name string) (enabled, ok bool) {
+	if c == nil {
+		return false, false
+	}
+	enabled, ok = c.Features[name]
+	return enabled, ok
+}
+
+// TokenCookie returns the configured token cookie, or def if none is
+// configured. It is safe to call on a nil Config.
+func (c *Config) TokenCookie(def string) string {
+	if c == nil || c.Auth.TokenCookie == nil {
+		return def
+	}
+	return *c.Auth.TokenCookie
+}
+
+// CSRFCookie returns the configured CSRF cookie, or def if none is configured.
+// It is safe to call on a nil Config.
+func (c *Config) CSRFCookie(def string) string {
+	if c == nil || c.Auth.CSRFCookie == nil {
+		return def
+	}
+	return *c.Auth.CSRFCookie
+}
+
+// NestedLimit returns the configured nested limit, or def if none is
+// configured. It is safe to call on a nil Config.
+func (c *Config) NestedLimit(def int) int {
+	if c == nil || c.Limits.NestedLimit == nil {
+		return def
+	}
+	return *c.Limits.NestedLimit
+}
+
+// Concurrency returns the configured concurrency, or def if none is
+// configured. It is safe to call on a nil Config.
+func (c *Config) Concurrency(def int) int {
+	if c == nil || c.Limits.Concurrency == nil {
+		return def
+	}
+	return *c.Limits.Concurrency
+}
+
+// CacheExpiry returns the configured cache expiry, or def if none is
+// configured. It is safe to call on a nil Config.
+func (c *Config) CacheExpiry(def time.Duration) time.Duration {
+	if c == nil || c.Cache.Expiry == nil {
+		return def
+	}
+	return c.Cache.Expiry.Duration
+}
+
+// WatchBuffer returns the configured watch buffer, or def if none is
+// configured. It is safe to call on a nil Config.
+func (c *Config) WatchBuffer(def int) int {
+	if c == nil || c.Cache.WatchBuffer == nil {
+		return def
+	}
+	return *c.Cache.WatchBuffer
+}
+
+// WatchOverflow returns the configured watch overflow policy, or def if none
+// is configured. It is safe to call on a nil Config.
+func (c *Config) WatchOverflow(def clients.OverflowPolicy) clients.OverflowPolicy {
+	if c == nil || c.Cache.WatchOverflow == nil {
+		return def
+	}
+	return *c.Cache.WatchOverflow
+}
+
+// Parse the supplied YAML or JSON configuration. Unknown or duplicate fields are
+// rejected, so that a misspelt field isn't silently ignored.
+func Parse(data []byte) (*Config, error) {
+	c := &Config{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return c, nil
+	}
+	if err := yaml.UnmarshalStrict(data, c); err != nil {
+		return nil, errors.Wrap(err, errDecodeConfig)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, errors.Wrap(err, errInvalidConfig)
+	}
+	return c, nil
+}
+
+// A Watcher loads a configuration file, and reloads it when it changes.
+type Watcher struct {
+	path     string
+	interval time.Duration
+	log      logging.Logger
+
+	current atomic.Value // *Config
+	hash    [sha256.Size]byte
+}
+
+// A WatcherOption configures a Watcher.
+type WatcherOption func(w *Watcher)
+
+// WithReloadInterval configures how often a Watcher checks its configuration
+// file for changes.
+func WithReloadInterval(d time.Duration) WatcherOption {
+	return func(w *Watcher) {
+		w.interval = d
+	}
+}
+
+// WithLogger configures the logger used by a Watcher.
+func WithLogger(l logging.Logger) WatcherOption {
+	return func(w *Watcher) {
+		w.log = l
+	}
+}
+
+// NewWatcher returns a Watcher of the supplied configuration file. The file is
+// loaded immediately; an error is returned if it can't be.
+func NewWatcher(path string, o ...WatcherOption) (*Watcher, error) {
+	w := &Watcher{path: path, interval: DefaultReloadInterval, log: logging.NewNopLogger()}
+	for _, fn := range o {
+		fn(w)
+	}
+	if _, err := w.reload(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Get the current configuration. It returns nil if w is nil, which callers may
+// pass to Config's methods in order to use their defaults.
+func (w *Watcher) Get() *Config {
+	if w == nil {
+		return nil
+	}
+	c, _ := w.current.Load().(*Config)
+	return c
+}
+
+// Watch the configuration file for changes until the supplied context is done.
+// A configuration file that can't be loaded is logged and ignored; the
+// previous configuration remains current.
+func (w *Watcher) Watch(ctx context.Context) {
+	// We poll rather than relying on filesystem notifications because the
+	// latter are unreliable for files mounted from a ConfigMap, which are
+	// updated by atomically swapping a symlink.
+	t := time.NewTicker(w.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		changed, err := w.reload()
+		if err != nil {
+			w.log.Info("Cannot reload configuration file", "path", w.path, "error", err)
+			continue
+		}
+		if changed {
+			w.log.Info("Reloaded configuration file", "path", w.path)
+		}
+	}
+}
+
+// reload the configuration file, returning true if it changed.
+func (w *Watcher) reload() (bool, error) {
+	data, err := ioutil.ReadFile(w.path)
+	if err != nil {
+		return false, errors.Wrap(err, errReadConfig)
+	}
+	h := sha256.Sum256(data)
+	if w.Get() != nil && h == w.hash {
+		return false, nil
+	}
+	c, err := Parse(data)
+	if err != nil {
+		return false, err
+	}
+	w.hash = h
+	w.current.Store(c)
+	return true, nil
+}
@@ -0,0 +1,201 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/upbound/xgql/internal/clients"
+)
+
+func TestParse(t *testing.T) {
+	ten, five, fifty := 10, 5, 50
+	tcookie, ccookie := "token", "csrf"
+	oldest := clients.DropOldest
+
+	type want struct {
+		c   *Config
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		data   string
+		want   want
+	}{
+		"Empty": {
+			reason: "An empty file should produce an empty configuration.",
+			data:   "\n",
+			want:   want{c: &Config{}},
+		},
+		"Full": {
+			reason: "All supported fields should be parsed.",
+			data: `
+auth:
+  tokenCookie: token
+  csrfCookie: csrf
+limits:
+  nestedLimit: 10
+  concurrency: 5
+cache:
+  expiry: 10m
+  watchBuffer: 50
+  watchOverflow: drop-oldest
+features:
+  EnvironmentConfigs: true
+`,
+			want: want{c: &Config{
+				Auth: Auth{
+					TokenCookie: &tcookie,
+					CSRFCookie:  &ccookie,
+				},
+				Limits: Limits{
+					NestedLimit: &ten,
+					Concurrency: &five,
+				},
+				Cache: Cache{
+					Expiry:        &metav1.Duration{Duration: 10 * time.Minute},
+					WatchBuffer:   &fifty,
+					WatchOverflow: &oldest,
+				},
+				Features: map[string]bool{"EnvironmentConfigs": true},
+			}},
+		},
+		"UnknownField": {
+			reason: "Unknown fields should be rejected, rather than silently ignored.",
+			data:   "limit: {concurrency: 1}",
+			want: want{
+				err: errors.Wrap(errors.New(`error unmarshaling JSON: while decoding JSON: json: unknown field "limit"`), errDecodeConfig),
+			},
+		},
+		"EmptyCSRFCookie": {
+			reason: "An empty CSRF cookie name should be rejected.",
+			data:   `auth: {csrfCookie: ""}`,
+			want: want{
+				err: errors.Wrap(errors.Errorf(errFmtEmpty, "auth.csrfCookie"), errInvalidConfig),
+			},
+		},
+		"Negative": {
+			reason: "Negative limits should be rejected.",
+			data:   "limits: {concurrency: -1}",
+			want: want{
+				err: errors.Wrap(errors.Errorf(errFmtNegative, "limits.concurrency"), errInvalidConfig),
+			},
+		},
+		"UnknownFeature": {
+			reason: "Unknown features should be rejected.",
+			data:   "features: {Cool: true}",
+			want: want{
+				err: errors.Wrap(errors.Errorf(errFmtFeature, "Cool"), errInvalidConfig),
+			},
+		},
+		"UnknownOverflow": {
+			reason: "Unknown watch overflow policies should be rejected.",
+			data:   "cache: {watchOverflow: drop-everything}",
+			want: want{
+				err: errors.Wrap(errors.Errorf(errFmtOverflow, clients.DropNewest, clients.DropOldest), errInvalidConfig),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Parse([]byte(tc.data))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nParse(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.c, got); diff != "" {
+				t.Errorf("\n%s\nParse(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestConfigDefaults(t *testing.T) {
+	var c *Config
+	if diff := cmp.Diff(3, c.NestedLimit(3)); diff != "" {
+		t.Errorf("c.NestedLimit(...): -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(clients.DropNewest, c.WatchOverflow(clients.DropNewest)); diff != "" {
+		t.Errorf("c.WatchOverflow(...): -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff("csrf", c.CSRFCookie("csrf")); diff != "" {
+		t.Errorf("c.CSRFCookie(...): -want, +got:\n%s", diff)
+	}
+
+	zero := 0
+	c = &Config{Limits: Limits{NestedLimit: &zero}}
+	if diff := cmp.Diff(0, c.NestedLimit(3)); diff != "" {
+		t.Errorf("c.NestedLimit(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(data string) {
+		t.Helper()
+		if err := ioutil.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatalf("cannot write configuration file: %s", err)
+		}
+	}
+
+	if _, err := NewWatcher(path); err == nil {
+		t.Errorf("NewWatcher(...): want error loading missing file")
+	}
+
+	write("limits: {nestedLimit: 10}")
+	w, err := NewWatcher(path, WithReloadInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewWatcher(...): %s", err)
+	}
+	if diff := cmp.Diff(10, w.Get().NestedLimit(0)); diff != "" {
+		t.Errorf("w.Get(): -want nested limit, +got:\n%s", diff)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Watch(ctx)
+
+	// An invalid file should be ignored.
+	write("limits: {nestedLimit: -1}")
+	time.Sleep(50 * time.Millisecond)
+	if diff := cmp.Diff(10, w.Get().NestedLimit(0)); diff != "" {
+		t.Errorf("w.Get(): -want nested limit, +got:\n%s", diff)
+	}
+
+	write("limits: {nestedLimit: 20}")
+	deadline := time.Now().Add(5 * time.Second)
+	for w.Get().NestedLimit(0) != 20 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if diff := cmp.Diff(20, w.Get().NestedLimit(0)); diff != "" {
+		t.Errorf("w.Get(): -want nested limit, +got:\n%s", diff)
+	}
+
+	var nw *Watcher
+	if nw.Get() != nil {
+		t.Errorf("nw.Get(): want nil configuration from nil watcher")
+	}
+}
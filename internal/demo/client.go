@@ -0,0 +1,154 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package demo
+
+import (
+	"context"
+
+	authv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// A demoClient reads and writes a Cluster's objects. It fills the gaps between
+// controller-runtime's fake client and a real API server that xgql's
+// resolvers depend on.
+type demoClient struct {
+	client.Client
+	cluster *Cluster
+}
+
+// RESTMapper returns a REST mapper that knows the kinds of resource defined by
+// the Cluster's CustomResourceDefinitions.
+func (c *demoClient) RESTMapper() meta.RESTMapper {
+	return c.cluster.mapper
+}
+
+// List objects. The fake client ignores field selectors, so we apply them.
+func (c *demoClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if err := c.Client.List(ctx, list, opts...); err != nil {
+		return err
+	}
+	lo := &client.ListOptions{}
+	lo.ApplyOptions(opts)
+	if lo.FieldSelector == nil || lo.FieldSelector.Empty() {
+		return nil
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+	selected := make([]runtime.Object, 0, len(items))
+	for _, i := range items {
+		if lo.FieldSelector.Matches(selectableFields(i)) {
+			selected = append(selected, i)
+		}
+	}
+	return meta.SetList(list, selected)
+}
+
+// Create an object. Access reviews are allowed without being stored, because
+// the demo user may do anything.
+func (c *demoClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if sar, ok := obj.(*authv1.SelfSubjectAccessReview); ok {
+		sar.Status = authv1.SubjectAccessReviewStatus{Allowed: true, Reason: "the demo user may do anything"}
+		return nil
+	}
+
+	// The API server would set these, but the fake client doesn't.
+	if obj.GetUID() == "" {
+		obj.SetUID(types.UID(utilrand.String(32)))
+	}
+	if ts := obj.GetCreationTimestamp(); ts.IsZero() {
+		obj.SetCreationTimestamp(metav1.Now())
+	}
+	if err := c.Client.Create(ctx, obj, opts...); err != nil {
+		return err
+	}
+	c.cluster.publish(watch.Added, obj)
+	return nil
+}
+
+// Update an object.
+func (c *demoClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if err := c.Client.Update(ctx, obj, opts...); err != nil {
+		return err
+	}
+	c.cluster.publish(watch.Modified, obj)
+	return nil
+}
+
+// Patch an object.
+func (c *demoClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if err := c.Client.Patch(ctx, obj, patch, opts...); err != nil {
+		return err
+	}
+	c.cluster.publish(watch.Modified, obj)
+	return nil
+}
+
+// Delete an object. Watchers are sent the object's last known state.
+func (c *demoClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return err
+	}
+	last := &unstructured.Unstructured{}
+	last.SetGroupVersionKind(gvk)
+	if err := c.Client.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, last); err != nil {
+		return err
+	}
+	if err := c.Client.Delete(ctx, obj, opts...); err != nil {
+		return err
+	}
+	c.cluster.publish(watch.Deleted, last)
+	return nil
+}
+
+// selectableFields returns the fields of the supplied object that may be used
+// in a field selector. The API server supports metadata.name and
+// metadata.namespace for all kinds, plus some kind specific fields.
+func selectableFields(obj runtime.Object) fields.Set {
+	f := fields.Set{}
+	if a, err := meta.Accessor(obj); err == nil {
+		f["metadata.name"] = a.GetName()
+		f["metadata.namespace"] = a.GetNamespace()
+	}
+	e, ok := obj.(*corev1.Event)
+	if !ok {
+		return f
+	}
+	f["involvedObject.kind"] = e.InvolvedObject.Kind
+	f["involvedObject.namespace"] = e.InvolvedObject.Namespace
+	f["involvedObject.name"] = e.InvolvedObject.Name
+	f["involvedObject.uid"] = string(e.InvolvedObject.UID)
+	f["involvedObject.apiVersion"] = e.InvolvedObject.APIVersion
+	f["involvedObject.resourceVersion"] = e.InvolvedObject.ResourceVersion
+	f["involvedObject.fieldPath"] = e.InvolvedObject.FieldPath
+	f["reason"] = e.Reason
+	f["source"] = e.Source.Component
+	f["type"] = e.Type
+	return f
+}
@@ -0,0 +1,433 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package demo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	authnv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	kextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/upbound/xgql/internal/auth"
+	"github.com/upbound/xgql/internal/clients"
+)
+
+const (
+	errConvertCRD     = "cannot convert CustomResourceDefinition"
+	errFmtConvert     = "cannot convert %s %q"
+	errFmtUnknownKind = "cannot watch unknown kind %s"
+	errWatch          = "cannot list objects to watch"
+)
+
+// The demo user. The API server would authenticate a real user.
+var viewer = authnv1.UserInfo{
+	Username: "demo",
+	Groups:   []string{"system:authenticated"},
+}
+
+// A Cluster is an in-memory stand-in for a Kubernetes API server that serves
+// the embedded dataset. It implements the interfaces xgql's resolvers use to
+// read and watch objects, discover API resources, review tokens, and stream
+// pod logs. Every caller is the demo user, who may do anything. Writes are
+// applied in memory, and are lost when the Cluster is.
+type Cluster struct {
+	client client.Client
+	scheme *runtime.Scheme
+	mapper meta.RESTMapper
+
+	groups     []metav1.APIGroup
+	resources  map[string]*metav1.APIResourceList
+	categories map[schema.GroupKind][]string
+
+	mx       sync.Mutex
+	watchers map[*watcher]bool
+}
+
+// New returns a Cluster that serves the embedded dataset. The kinds of custom
+// resource defined by the dataset's CustomResourceDefinitions are added to the
+// supplied scheme, unless it already knows them. They're read and written as
+// unstructured objects.
+func New(s *runtime.Scheme) (*Cluster, error) {
+	objs, err := Objects()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Cluster{
+		scheme:     s,
+		resources:  map[string]*metav1.APIResourceList{},
+		categories: map[schema.GroupKind][]string{},
+		watchers:   map[*watcher]bool{},
+	}
+
+	crds := make([]*kextv1.CustomResourceDefinition, 0)
+	for _, u := range objs {
+		if u.GroupVersionKind() != kextv1.SchemeGroupVersion.WithKind("CustomResourceDefinition") {
+			continue
+		}
+		crd := &kextv1.CustomResourceDefinition{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, crd); err != nil {
+			return nil, errors.Wrap(err, errConvertCRD)
+		}
+		crds = append(crds, crd)
+	}
+	c.define(crds)
+
+	// The fake client reads and writes objects of kinds the scheme knows as
+	// the scheme's types, so we must supply them as such.
+	init := make([]runtime.Object, len(objs))
+	for i, u := range objs {
+		init[i] = u
+		o, err := s.New(u.GroupVersionKind())
+		if err != nil {
+			continue
+		}
+		if _, ok := o.(runtime.Unstructured); ok {
+			continue
+		}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, o); err != nil {
+			return nil, errors.Wrapf(err, errFmtConvert, u.GetKind(), u.GetName())
+		}
+		init[i] = o
+	}
+	c.client = fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(init...).Build()
+
+	return c, nil
+}
+
+// define the kinds of resource the supplied CustomResourceDefinitions define,
+// as the API server would.
+func (c *Cluster) define(crds []*kextv1.CustomResourceDefinition) {
+	groups := map[string]*metav1.APIGroup{}
+	preferred := make([]schema.GroupVersion, 0, len(crds))
+	for _, crd := range crds {
+		g, ok := groups[crd.Spec.Group]
+		if !ok {
+			g = &metav1.APIGroup{Name: crd.Spec.Group}
+			groups[crd.Spec.Group] = g
+		}
+
+		c.categories[schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}] = crd.Spec.Names.Categories
+
+		for _, v := range crd.Spec.Versions {
+			if !v.Served {
+				continue
+			}
+			gv := schema.GroupVersion{Group: crd.Spec.Group, Version: v.Name}
+			gvk := gv.WithKind(crd.Spec.Names.Kind)
+			if !c.scheme.Recognizes(gvk) {
+				c.scheme.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+				c.scheme.AddKnownTypeWithName(gv.WithKind(crd.Spec.Names.ListKind), &unstructured.UnstructuredList{})
+			}
+
+			gvd := metav1.GroupVersionForDiscovery{GroupVersion: gv.String(), Version: v.Name}
+			if !hasVersion(g.Versions, gvd) {
+				g.Versions = append(g.Versions, gvd)
+			}
+			if v.Storage {
+				g.PreferredVersion = gvd
+				preferred = append(preferred, gv)
+			}
+
+			rl, ok := c.resources[gv.String()]
+			if !ok {
+				rl = &metav1.APIResourceList{GroupVersion: gv.String()}
+				c.resources[gv.String()] = rl
+			}
+			rl.APIResources = append(rl.APIResources, metav1.APIResource{
+				Name:         crd.Spec.Names.Plural,
+				SingularName: crd.Spec.Names.Singular,
+				Namespaced:   crd.Spec.Scope == kextv1.NamespaceScoped,
+				Kind:         crd.Spec.Names.Kind,
+				Verbs:        metav1.Verbs{"delete", "deletecollection", "get", "list", "patch", "create", "update", "watch"},
+				ShortNames:   crd.Spec.Names.ShortNames,
+				Categories:   crd.Spec.Names.Categories,
+			})
+		}
+	}
+
+	// The REST mapper prefers each group's storage version, which is the
+	// version the API server would prefer if it served only one.
+	rm := meta.NewDefaultRESTMapper(preferred)
+	for _, crd := range crds {
+		for _, v := range crd.Spec.Versions {
+			if !v.Served {
+				continue
+			}
+			gv := schema.GroupVersion{Group: crd.Spec.Group, Version: v.Name}
+			scope := meta.RESTScopeRoot
+			if crd.Spec.Scope == kextv1.NamespaceScoped {
+				scope = meta.RESTScopeNamespace
+			}
+			rm.AddSpecific(gv.WithKind(crd.Spec.Names.Kind),
+				gv.WithResource(crd.Spec.Names.Plural),
+				gv.WithResource(crd.Spec.Names.Singular),
+				scope)
+		}
+	}
+	c.mapper = rm
+
+	c.groups = make([]metav1.APIGroup, 0, len(groups))
+	for _, g := range groups {
+		c.groups = append(c.groups, *g)
+	}
+	sort.Slice(c.groups, func(i, j int) bool { return c.groups[i].Name < c.groups[j].Name })
+}
+
+func hasVersion(vs []metav1.GroupVersionForDiscovery, v metav1.GroupVersionForDiscovery) bool {
+	for _, e := range vs {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Get a client. Every caller gets a client that acts as the demo user.
+func (c *Cluster) Get(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+	return &demoClient{Client: c.client, cluster: c}, nil
+}
+
+// Categories returns the categories the supplied kind of resource belongs to.
+func (c *Cluster) Categories(gk schema.GroupKind) []string {
+	return c.categories[gk]
+}
+
+// Discover the API groups and resources defined by the dataset's
+// CustomResourceDefinitions. Only the supplied group is discovered, if any.
+func (c *Cluster) Discover(_ context.Context, _ auth.Credentials, group *string) ([]metav1.APIGroup, []*metav1.APIResourceList, error) {
+	groups := make([]metav1.APIGroup, 0, len(c.groups))
+	lists := make([]*metav1.APIResourceList, 0, len(c.groups))
+	for _, g := range c.groups {
+		if group != nil && g.Name != *group {
+			continue
+		}
+		groups = append(groups, g)
+		for _, v := range g.Versions {
+			lists = append(lists, c.resources[v.GroupVersion].DeepCopy())
+		}
+	}
+	return groups, lists, nil
+}
+
+// Review the supplied bearer token. Every token identifies the demo user.
+func (c *Cluster) Review(_ context.Context, _ string) (*authnv1.TokenReviewStatus, error) {
+	return &authnv1.TokenReviewStatus{Authenticated: true, User: viewer}, nil
+}
+
+// Stream the logs of the supplied pod. The dataset's pods don't run, so their
+// logs explain as much.
+func (c *Cluster) Stream(ctx context.Context, _ auth.Credentials, namespace, name string, o *corev1.PodLogOptions) (io.ReadCloser, error) {
+	p := &corev1.Pod{}
+	if err := c.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, p); err != nil {
+		return nil, err
+	}
+	container := o.Container
+	if container == "" && len(p.Spec.Containers) > 0 {
+		container = p.Spec.Containers[0].Name
+	}
+	lines := []string{
+		fmt.Sprintf("xgql is serving demo data; container %q of pod %s/%s is not running.", container, namespace, name),
+		"Connect xgql to a Kubernetes cluster to stream the logs of real pods.",
+	}
+	return ioutil.NopCloser(strings.NewReader(strings.Join(lines, "\n") + "\n")), nil
+}
+
+// Watch objects of the supplied kind. The objects that exist are sent as Added
+// events, followed by an event for each object that is written.
+func (c *Cluster) Watch(ctx context.Context, _ auth.Credentials, obj client.Object, _ ...clients.GetOption) (<-chan clients.WatchEvent, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	var l client.ObjectList = &unstructured.UnstructuredList{}
+	l.GetObjectKind().SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	_, u := obj.(*unstructured.Unstructured)
+	if !u {
+		tl, err := c.scheme.New(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtUnknownKind, gvk)
+		}
+		ol, ok := tl.(client.ObjectList)
+		if !ok {
+			return nil, errors.Errorf(errFmtUnknownKind, gvk)
+		}
+		l = ol
+	}
+
+	// We hold the lock while we list so that no write is missed between
+	// listing and watching.
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if err := c.client.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errWatch)
+	}
+	items, err := meta.ExtractList(l)
+	if err != nil {
+		return nil, errors.Wrap(err, errWatch)
+	}
+
+	w := &watcher{gk: gvk.GroupKind(), unstructured: u, events: make(chan clients.WatchEvent, len(items)+clients.DefaultWatchBuffer)}
+	for _, i := range items {
+		if o, ok := i.(client.Object); ok {
+			w.events <- clients.WatchEvent{Type: watch.Added, Object: o}
+		}
+	}
+	c.start(ctx, w)
+	return w.events, nil
+}
+
+// WatchObject watches the supplied object. Its current state is sent as an
+// Added event, if it exists, followed by an event each time it is written. The
+// returned channel is closed when the object is deleted.
+func (c *Cluster) WatchObject(ctx context.Context, _ auth.Credentials, gvk schema.GroupVersionKind, namespace, name string) (<-chan clients.WatchEvent, error) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	w := &watcher{gk: gvk.GroupKind(), namespace: namespace, name: name, unstructured: true, events: make(chan clients.WatchEvent, clients.DefaultWatchBuffer)}
+
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	err := c.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, u)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		w.events <- clients.WatchEvent{Type: watch.Added, Object: u}
+	}
+
+	c.start(ctx, w)
+	return w.events, nil
+}
+
+// start sending events to the supplied watcher until the supplied context is
+// done. The caller must hold the lock.
+func (c *Cluster) start(ctx context.Context, w *watcher) {
+	c.watchers[w] = true
+	go func() {
+		<-ctx.Done()
+		c.mx.Lock()
+		defer c.mx.Unlock()
+		c.stop(w)
+	}()
+}
+
+// stop sending events to the supplied watcher. The caller must hold the lock.
+func (c *Cluster) stop(w *watcher) {
+	if !c.watchers[w] {
+		return
+	}
+	delete(c.watchers, w)
+	close(w.events)
+}
+
+// publish an event for the supplied object to every watcher that is watching
+// it. Events are dropped if a watcher's buffer is full.
+func (c *Cluster) publish(t watch.EventType, obj client.Object) {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return
+	}
+	u := &unstructured.Unstructured{}
+	if in, ok := obj.(*unstructured.Unstructured); ok {
+		u = in.DeepCopy()
+	} else {
+		m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return
+		}
+		u.SetUnstructuredContent(m)
+	}
+	u.SetGroupVersionKind(gvk)
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	for w := range c.watchers {
+		if !w.watches(u) {
+			continue
+		}
+		o, err := c.convert(u, w.unstructured)
+		if err != nil {
+			continue
+		}
+		select {
+		case w.events <- clients.WatchEvent{Type: t, Object: o}:
+		default:
+		}
+		if t == watch.Deleted && w.name != "" {
+			c.stop(w)
+		}
+	}
+}
+
+// convert the supplied object to the scheme's type for its kind, unless it
+// should stay unstructured.
+func (c *Cluster) convert(u *unstructured.Unstructured, keep bool) (client.Object, error) {
+	if keep {
+		return u.DeepCopy(), nil
+	}
+	o, err := c.scheme.New(u.GroupVersionKind())
+	if err != nil {
+		return nil, err
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, o); err != nil {
+		return nil, err
+	}
+	co, ok := o.(client.Object)
+	if !ok {
+		return nil, errors.Errorf(errFmtConvert, u.GetKind(), u.GetName())
+	}
+	return co, nil
+}
+
+// A watcher watches objects of a particular kind, or a particular object.
+type watcher struct {
+	gk           schema.GroupKind
+	namespace    string
+	name         string
+	unstructured bool
+	events       chan clients.WatchEvent
+}
+
+func (w *watcher) watches(u *unstructured.Unstructured) bool {
+	if u.GroupVersionKind().GroupKind() != w.gk {
+		return false
+	}
+	if w.name == "" {
+		return true
+	}
+	return u.GetNamespace() == w.namespace && u.GetName() == w.name
+}
@@ -0,0 +1,120 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package demo serves xgql from an embedded dataset of representative
+// Crossplane resources instead of a Kubernetes API server, so that xgql may
+// run without a cluster, e.g. to develop a frontend offline or to run
+// end-to-end tests hermetically.
+package demo
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	kyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const (
+	errReadFixtures   = "cannot read fixtures"
+	errFmtReadFixture = "cannot read fixture %s"
+)
+
+//go:embed fixtures/*.yaml
+var fixtures embed.FS
+
+// Objects returns the objects of the embedded dataset. Each object is given a
+// UID derived from its API version, kind, namespace, and name. The UIDs of the
+// owners of each object, and of the objects involved in each event, are
+// derived the same way so that fixtures needn't specify them.
+func Objects() ([]*unstructured.Unstructured, error) {
+	// The fixtures are embedded at build time, so ReadDir returns them
+	// sorted by name and the dataset is deterministic.
+	entries, err := fs.ReadDir(fixtures, "fixtures")
+	if err != nil {
+		return nil, errors.Wrap(err, errReadFixtures)
+	}
+
+	objs := make([]*unstructured.Unstructured, 0)
+	for _, e := range entries {
+		b, err := fixtures.ReadFile(path.Join("fixtures", e.Name()))
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtReadFixture, e.Name())
+		}
+		d := kyaml.NewYAMLOrJSONDecoder(bytes.NewReader(b), 4096)
+		for {
+			u := &unstructured.Unstructured{}
+			err := d.Decode(&u.Object)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, errors.Wrapf(err, errFmtReadFixture, e.Name())
+			}
+			if len(u.Object) == 0 {
+				// An empty document, e.g. one that only contains comments.
+				continue
+			}
+			objs = append(objs, u)
+		}
+	}
+
+	// Owners are either cluster scoped, or in the namespace of the objects
+	// they own.
+	clusterScoped := map[string]bool{}
+	for _, u := range objs {
+		u.SetUID(uid(u.GetAPIVersion(), u.GetKind(), u.GetNamespace(), u.GetName()))
+		if u.GetNamespace() == "" {
+			clusterScoped[u.GetAPIVersion()+"/"+u.GetKind()+"/"+u.GetName()] = true
+		}
+	}
+	for _, u := range objs {
+		refs := u.GetOwnerReferences()
+		for i := range refs {
+			ns := u.GetNamespace()
+			if clusterScoped[refs[i].APIVersion+"/"+refs[i].Kind+"/"+refs[i].Name] {
+				ns = ""
+			}
+			refs[i].UID = uid(refs[i].APIVersion, refs[i].Kind, ns, refs[i].Name)
+		}
+		if len(refs) > 0 {
+			u.SetOwnerReferences(refs)
+		}
+
+		if u.GetAPIVersion() != "v1" || u.GetKind() != "Event" {
+			continue
+		}
+		av, _, _ := unstructured.NestedString(u.Object, "involvedObject", "apiVersion")
+		k, _, _ := unstructured.NestedString(u.Object, "involvedObject", "kind")
+		ns, _, _ := unstructured.NestedString(u.Object, "involvedObject", "namespace")
+		n, _, _ := unstructured.NestedString(u.Object, "involvedObject", "name")
+		_ = unstructured.SetNestedField(u.Object, string(uid(av, k, ns, n)), "involvedObject", "uid")
+	}
+
+	return objs, nil
+}
+
+// uid returns a deterministic UID for the supplied object, formatted like the
+// UUIDs the API server generates.
+func uid(apiVersion, kind, namespace, name string) types.UID {
+	s := sha256.Sum256([]byte(apiVersion + "\n" + kind + "\n" + namespace + "\n" + name))
+	return types.UID(fmt.Sprintf("%x-%x-%x-%x-%x", s[0:4], s[4:6], s[6:8], s[8:10], s[10:16]))
+}
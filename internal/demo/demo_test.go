@@ -0,0 +1,62 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package demo
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestObjects(t *testing.T) {
+	objs, err := Objects()
+	if err != nil {
+		t.Fatalf("Objects(): %s", err)
+	}
+	if len(objs) == 0 {
+		t.Fatalf("Objects(): want objects, got none")
+	}
+
+	uids := map[types.UID]*unstructured.Unstructured{}
+	for _, u := range objs {
+		if u.GetAPIVersion() == "" || u.GetKind() == "" || u.GetName() == "" {
+			t.Errorf("Objects(): want an API version, kind, and name, got %q %q %q", u.GetAPIVersion(), u.GetKind(), u.GetName())
+		}
+		if o, ok := uids[u.GetUID()]; ok {
+			t.Errorf("Objects(): %s %s has the same UID as %s %s", u.GetKind(), u.GetName(), o.GetKind(), o.GetName())
+		}
+		uids[u.GetUID()] = u
+	}
+
+	for _, u := range objs {
+		for _, ref := range u.GetOwnerReferences() {
+			o, ok := uids[ref.UID]
+			if !ok || o.GetKind() != ref.Kind || o.GetName() != ref.Name {
+				t.Errorf("Objects(): %s %s is owned by %s %s, which does not exist", u.GetKind(), u.GetName(), ref.Kind, ref.Name)
+			}
+		}
+
+		if u.GetAPIVersion() != "v1" || u.GetKind() != "Event" {
+			continue
+		}
+		id, _, _ := unstructured.NestedString(u.Object, "involvedObject", "uid")
+		k, _, _ := unstructured.NestedString(u.Object, "involvedObject", "kind")
+		n, _, _ := unstructured.NestedString(u.Object, "involvedObject", "name")
+		if o, ok := uids[types.UID(id)]; !ok || o.GetKind() != k || o.GetName() != n {
+			t.Errorf("Objects(): event %s involves %s %s, which does not exist", u.GetName(), k, n)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package etag derives an ETag for GraphQL queries served via HTTP GET from
+// the resource versions of the objects they read, so that browsers and
+// intermediary caches can cheaply revalidate a response they already have.
+package etag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/upbound/xgql/internal/auth"
+	"github.com/upbound/xgql/internal/clients"
+)
+
+const header = "ETag"
+
+type key int
+
+const headerKey key = iota
+
+// Extension is a GraphQL server extension that sets the ETag header of a query
+// that was served via HTTP GET. The ETag is derived from the query, the
+// caller's credentials, and the resource versions of the objects read to
+// resolve the query. No ETag is set if the query failed, even partially, or if
+// it read data that has no resource version, e.g. pod logs.
+type Extension struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationInterceptor
+} = Extension{}
+
+// ExtensionName returns the name of this extension.
+func (e Extension) ExtensionName() string {
+	return "ETag"
+}
+
+// Validate this extension (a no-op).
+func (e Extension) Validate(_ graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptOperation records the resource versions of the objects read by a
+// query, and sets its ETag once the query has been resolved.
+func (e Extension) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	oc := graphql.GetOperationContext(ctx)
+	h, ok := ctx.Value(headerKey).(http.Header)
+	if !ok || oc.Operation == nil || oc.Operation.Operation != ast.Query {
+		return next(ctx)
+	}
+
+	v := &clients.Versions{}
+	rh := next(clients.WithVersions(ctx, v))
+	return func(ctx context.Context) *graphql.Response {
+		rsp := rh(ctx)
+		if rsp == nil || len(rsp.Errors) > 0 {
+			return rsp
+		}
+		sum, ok := v.Sum()
+		if !ok {
+			return rsp
+		}
+		vars, err := json.Marshal(oc.Variables)
+		if err != nil {
+			return rsp
+		}
+		creds, _ := auth.FromContext(ctx)
+		tag := creds.Hash([]byte(strings.Join([]string{oc.RawQuery, oc.OperationName, string(vars), sum}, "\n")))
+		h.Set(header, fmt.Sprintf("W/%q", tag))
+		return rsp
+	}
+}
+
+// Middleware allows the ETag extension to set the ETag header of GraphQL HTTP
+// GET responses. It responds 304 Not Modified to a request whose If-None-Match
+// header matches the ETag of its response.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.Header.Get("Upgrade") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cw := &conditionalWriter{ResponseWriter: w, match: r.Header.Get("If-None-Match")}
+		next.ServeHTTP(cw, r.WithContext(context.WithValue(r.Context(), headerKey, w.Header())))
+	})
+}
+
+// A conditionalWriter discards the body of a successful response if its ETag
+// matches the request's If-None-Match header, and responds 304 Not Modified.
+type conditionalWriter struct {
+	http.ResponseWriter
+	match string
+
+	wroteHeader bool
+	notModified bool
+}
+
+func (w *conditionalWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	if status == http.StatusOK && w.match != "" && matches(w.match, w.Header().Get(header)) {
+		w.notModified = true
+		h := w.Header()
+		h.Del("Content-Type")
+		h.Del("Content-Length")
+		w.ResponseWriter.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *conditionalWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.notModified {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// matches returns true if the supplied If-None-Match header matches the
+// supplied ETag. If-None-Match always uses the weak comparison function, so
+// weak and strong tags that are otherwise identical match.
+func matches(ifNoneMatch, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	if strings.TrimSpace(ifNoneMatch) == "*" {
+		return true
+	}
+	for _, t := range strings.Split(ifNoneMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(t), "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etag
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMatches(t *testing.T) {
+	cases := map[string]struct {
+		reason      string
+		ifNoneMatch string
+		etag        string
+		want        bool
+	}{
+		"NoETag": {
+			reason:      "A response without an ETag should never match.",
+			ifNoneMatch: "*",
+			want:        false,
+		},
+		"Wildcard": {
+			reason:      "The wildcard should match any ETag.",
+			ifNoneMatch: "*",
+			etag:        `W/"a"`,
+			want:        true,
+		},
+		"Weak": {
+			reason:      "Weak comparison should ignore whether either tag is weak.",
+			ifNoneMatch: `"a"`,
+			etag:        `W/"a"`,
+			want:        true,
+		},
+		"List": {
+			reason:      "Any of a list of tags should match.",
+			ifNoneMatch: `W/"b", W/"a"`,
+			etag:        `W/"a"`,
+			want:        true,
+		},
+		"NoMatch": {
+			reason:      "Different tags should not match.",
+			ifNoneMatch: `W/"b"`,
+			etag:        `W/"a"`,
+			want:        false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := matches(tc.ifNoneMatch, tc.etag)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nmatches(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	type want struct {
+		status int
+		body   string
+	}
+
+	cases := map[string]struct {
+		reason      string
+		method      string
+		ifNoneMatch string
+		status      int
+		want        want
+	}{
+		"NotModified": {
+			reason:      "A GET request whose If-None-Match header matches the response's ETag should not be sent the response.",
+			method:      http.MethodGet,
+			ifNoneMatch: `W/"a"`,
+			want:        want{status: http.StatusNotModified},
+		},
+		"Modified": {
+			reason:      "A GET request whose If-None-Match header doesn't match the response's ETag should be sent the response.",
+			method:      http.MethodGet,
+			ifNoneMatch: `W/"b"`,
+			want:        want{status: http.StatusOK, body: "{}"},
+		},
+		"Failed": {
+			reason:      "Unsuccessful responses should always be sent.",
+			method:      http.MethodGet,
+			ifNoneMatch: `W/"a"`,
+			status:      http.StatusBadRequest,
+			want:        want{status: http.StatusBadRequest, body: "{}"},
+		},
+		"POST": {
+			reason:      "Only GET requests should be conditional.",
+			method:      http.MethodPost,
+			ifNoneMatch: `W/"a"`,
+			want:        want{status: http.StatusOK, body: "{}"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(header, `W/"a"`)
+				if tc.status != 0 {
+					w.WriteHeader(tc.status)
+				}
+				_, _ = w.Write([]byte("{}"))
+			})
+
+			r := httptest.NewRequest(tc.method, "/query", nil)
+			r.Header.Set("If-None-Match", tc.ifNoneMatch)
+			w := httptest.NewRecorder()
+			Middleware(next).ServeHTTP(w, r)
+
+			got := want{status: w.Code, body: w.Body.String()}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nMiddleware(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package feature gates GraphQL fields annotated with the @feature directive
+// behind feature flags, so that experimental fields may be served but disabled
+// until an operator opts in to them.
+package feature
+
+import (
+	"context"
+	"sort"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/pkg/errors"
+)
+
+const directive = "feature"
+
+const errFmtDisabled = "feature %q is disabled"
+
+// Features that may be enabled or disabled.
+const (
+	// EnvironmentConfigs exposes the EnvironmentConfigs and environment of
+	// composite resources. Crossplane's composition environments are alpha.
+	EnvironmentConfigs = "EnvironmentConfigs"
+
+	// FieldAuthorization requires callers to be permitted to perform the verb
+	// specified by a field's @requiresVerb directive in order to resolve it.
+	FieldAuthorization = "FieldAuthorization"
+
+	// Mutations allows callers to create, update, and delete resources.
+	Mutations = "Mutations"
+
+	// PodLogs allows callers to read the logs of pods.
+	PodLogs = "PodLogs"
+
+	// Subscriptions allows callers to subscribe to changes.
+	Subscriptions = "Subscriptions"
+)
+
+// Defaults indicates whether each known feature is enabled by default.
+var Defaults = map[string]bool{
+	EnvironmentConfigs: false,
+	FieldAuthorization: false,
+	Mutations:          true,
+	PodLogs:            true,
+	Subscriptions:      true,
+}
+
+// Known returns the names of all known features, sorted.
+func Known() []string {
+	out := make([]string, 0, len(Defaults))
+	for f := range Defaults {
+		out = append(out, f)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// IsKnown returns true if the supplied feature is known.
+func IsKnown(f string) bool {
+	_, ok := Defaults[f]
+	return ok
+}
+
+// Flags determine which features are enabled.
+type Flags interface {
+	Enabled(feature string) bool
+}
+
+// A FlagsFn determines which features are enabled.
+type FlagsFn func(feature string) bool
+
+// Enabled returns true if the supplied feature is enabled.
+func (fn FlagsFn) Enabled(feature string) bool {
+	return fn(feature)
+}
+
+// A Set of features that have been explicitly enabled (true) or disabled
+// (false). Features that are not in the set use their default.
+type Set map[string]bool
+
+// Enabled returns true if the supplied feature is enabled.
+func (s Set) Enabled(feature string) bool {
+	if e, ok := s[feature]; ok {
+		return e
+	}
+	return Defaults[feature]
+}
+
+// Gate is a GraphQL server extension that returns an error when a field gated
+// by a disabled feature is resolved.
+type Gate struct {
+	Flags Flags
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.FieldInterceptor
+} = Gate{}
+
+// ExtensionName returns the name of this extension.
+func (g Gate) ExtensionName() string {
+	return "FeatureGate"
+}
+
+// Validate this extension (a no-op).
+func (g Gate) Validate(_ graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptField returns an error if the field being resolved is gated by a
+// disabled feature.
+func (g Gate) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	f, ok := getFeature(graphql.GetFieldContext(ctx))
+	if ok && !g.Flags.Enabled(f) {
+		return nil, errors.Errorf(errFmtDisabled, f)
+	}
+	return next(ctx)
+}
+
+// getFeature returns the feature that gates the supplied field, if any.
+func getFeature(fc *graphql.FieldContext) (string, bool) {
+	if fc == nil || fc.Field.Field == nil || fc.Field.Definition == nil {
+		return "", false
+	}
+	d := fc.Field.Definition.Directives.ForName(directive)
+	if d == nil {
+		return "", false
+	}
+	a := d.Arguments.ForName("name")
+	if a == nil || a.Value == nil {
+		return "", false
+	}
+	return a.Value.Raw, true
+}
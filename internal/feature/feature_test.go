@@ -0,0 +1,123 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package feature
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestSet(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		s       Set
+		feature string
+		want    bool
+	}{
+		"Default": {
+			reason:  "A feature that is not in the set should use its default.",
+			s:       Set{},
+			feature: Mutations,
+			want:    Defaults[Mutations],
+		},
+		"Enabled": {
+			reason:  "A feature that is enabled in the set should be enabled.",
+			s:       Set{EnvironmentConfigs: true},
+			feature: EnvironmentConfigs,
+			want:    true,
+		},
+		"Disabled": {
+			reason:  "A feature that is disabled in the set should be disabled.",
+			s:       Set{Mutations: false},
+			feature: Mutations,
+			want:    false,
+		},
+		"Unknown": {
+			reason:  "An unknown feature should be disabled by default.",
+			s:       Set{},
+			feature: "Unknown",
+			want:    false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.s.Enabled(tc.feature)); diff != "" {
+				t.Errorf("\n%s\ns.Enabled(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGate(t *testing.T) {
+	field := func(d ...*ast.Directive) context.Context {
+		return graphql.WithFieldContext(context.Background(), &graphql.FieldContext{Field: graphql.CollectedField{Field: &ast.Field{
+			Definition: &ast.FieldDefinition{Directives: d},
+		}}})
+	}
+	gated := &ast.Directive{Name: directive, Arguments: ast.ArgumentList{
+		{Name: "name", Value: &ast.Value{Kind: ast.StringValue, Raw: EnvironmentConfigs}},
+	}}
+	next := func(ctx context.Context) (interface{}, error) { return "resolved", nil }
+
+	type want struct {
+		res interface{}
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		flags  Flags
+		ctx    context.Context
+		want   want
+	}{
+		"NotGated": {
+			reason: "A field without a @feature directive should be resolved.",
+			flags:  Set{},
+			ctx:    field(),
+			want:   want{res: "resolved"},
+		},
+		"Enabled": {
+			reason: "A field gated by an enabled feature should be resolved.",
+			flags:  Set{EnvironmentConfigs: true},
+			ctx:    field(gated),
+			want:   want{res: "resolved"},
+		},
+		"Disabled": {
+			reason: "A field gated by a disabled feature should return an error.",
+			flags:  Set{},
+			ctx:    field(gated),
+			want:   want{err: errors.Errorf(errFmtDisabled, EnvironmentConfigs)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			res, err := Gate{Flags: tc.flags}.InterceptField(tc.ctx, next)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ng.InterceptField(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.res, res); diff != "" {
+				t.Errorf("\n%s\ng.InterceptField(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
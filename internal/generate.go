@@ -21,12 +21,16 @@
 // Generate xgql models, bindings, etc per gqlgen.yaml.
 //go:generate go run -tags generate github.com/99designs/gqlgen
 
+// Generate the Go client per client/genqlient.yaml.
+//go:generate go run -tags generate github.com/Khan/genqlient ../client/genqlient.yaml
+
 // Add license headers to all files.
-//go:generate go run -tags generate github.com/google/addlicense -v -c "Upbound Inc" . ../cmd
+//go:generate go run -tags generate github.com/google/addlicense -v -c "Upbound Inc" . ../cmd ../client
 
 package internal
 
 import (
 	_ "github.com/99designs/gqlgen"  //nolint:typecheck
+	_ "github.com/Khan/genqlient"    //nolint:typecheck
 	_ "github.com/google/addlicense" //nolint:typecheck
 )
@@ -33,8 +33,30 @@ type CompositeResourceDefinitionSpec struct {
 	ConnectionSecretKeys []string                             `json:"connectionSecretKeys"`
 	Versions             []CompositeResourceDefinitionVersion `json:"versions"`
 
-	DefaultCompositionReference  *xpv1.Reference
-	EnforcedCompositionReference *xpv1.Reference
+	DefaultCompositionRef  *CompositionReference `json:"defaultCompositionRef"`
+	EnforcedCompositionRef *CompositionReference `json:"enforcedCompositionRef"`
+}
+
+// GetCompositionReference from the supplied Crossplane reference.
+func GetCompositionReference(in *xpv1.Reference) *CompositionReference {
+	if in == nil {
+		return nil
+	}
+	return &CompositionReference{Name: in.Name}
+}
+
+// GetCompositeDeletePolicy from the supplied Crossplane policy.
+func GetCompositeDeletePolicy(p string) *CompositeDeletePolicy {
+	switch p {
+	case "Background":
+		out := CompositeDeletePolicyBackground
+		return &out
+	case "Foreground":
+		out := CompositeDeletePolicyForeground
+		return &out
+	default:
+		return nil
+	}
 }
 
 // GetCompositeResourceDefinitionNames from the supplied Kubernetes names.
@@ -59,8 +81,41 @@ func GetCompositeResourceDefinitionNames(in *kextv1.CustomResourceDefinitionName
 	return out
 }
 
-// GetCompositeResourceDefinitionVersions from the supplied Kubernetes versions.
-func GetCompositeResourceDefinitionVersions(in []extv1.CompositeResourceDefinitionVersion) []CompositeResourceDefinitionVersion {
+// A CompositeResourceDefinitionVersion describes a version of a composite
+// resource.
+type CompositeResourceDefinitionVersion struct {
+	Name          string                       `json:"name"`
+	Referenceable bool                         `json:"referenceable"`
+	Served        bool                         `json:"served"`
+	Schema        *CompositeResourceValidation `json:"schema"`
+
+	// DefinitionName is the name of the XRD that defines this version.
+	DefinitionName string
+}
+
+// GetCompositeResourceValidation from the supplied Crossplane validation.
+func GetCompositeResourceValidation(in *extv1.CompositeResourceValidation) *CompositeResourceValidation {
+	if in == nil {
+		return nil
+	}
+	raw, err := json.Marshal(in.OpenAPIV3Schema)
+	if err != nil {
+		return nil
+	}
+	out := &CompositeResourceValidation{OpenAPIV3Schema: raw}
+
+	// The structured schema is a convenience; we still return the raw schema
+	// if it can't be parsed.
+	props := &kextv1.JSONSchemaProps{}
+	if err := json.Unmarshal(in.OpenAPIV3Schema.Raw, props); err == nil {
+		out.OpenAPIV3 = GetOpenAPISchema(props)
+	}
+	return out
+}
+
+// GetCompositeResourceDefinitionVersions from the supplied Kubernetes versions
+// of the named XRD.
+func GetCompositeResourceDefinitionVersions(xrd string, in []extv1.CompositeResourceDefinitionVersion) []CompositeResourceDefinitionVersion {
 	if in == nil {
 		return nil
 	}
@@ -68,15 +123,11 @@ func GetCompositeResourceDefinitionVersions(in []extv1.CompositeResourceDefiniti
 	out := make([]CompositeResourceDefinitionVersion, len(in))
 	for i := range in {
 		out[i] = CompositeResourceDefinitionVersion{
-			Name:          in[i].Name,
-			Served:        in[i].Served,
-			Referenceable: in[i].Referenceable,
-		}
-
-		if s := in[i].Schema; s != nil {
-			if raw, err := json.Marshal(s.OpenAPIV3Schema); err == nil {
-				out[i].Schema = &CompositeResourceValidation{OpenAPIV3Schema: raw}
-			}
+			Name:           in[i].Name,
+			Served:         in[i].Served,
+			Referenceable:  in[i].Referenceable,
+			Schema:         GetCompositeResourceValidation(in[i].Schema),
+			DefinitionName: xrd,
 		}
 	}
 	return out
@@ -130,12 +181,13 @@ func GetCompositeResourceDefinition(xrd *extv1.CompositeResourceDefinition) Comp
 		Kind:       xrd.Kind,
 		Metadata:   GetObjectMeta(xrd),
 		Spec: &CompositeResourceDefinitionSpec{
-			Group:                        xrd.Spec.Group,
-			Names:                        GetCompositeResourceDefinitionNames(&xrd.Spec.Names),
-			ClaimNames:                   GetCompositeResourceDefinitionNames(xrd.Spec.ClaimNames),
-			Versions:                     GetCompositeResourceDefinitionVersions(xrd.Spec.Versions),
-			DefaultCompositionReference:  xrd.Spec.DefaultCompositionRef,
-			EnforcedCompositionReference: xrd.Spec.EnforcedCompositionRef,
+			Group:                  xrd.Spec.Group,
+			Names:                  GetCompositeResourceDefinitionNames(&xrd.Spec.Names),
+			ClaimNames:             GetCompositeResourceDefinitionNames(xrd.Spec.ClaimNames),
+			ConnectionSecretKeys:   xrd.Spec.ConnectionSecretKeys,
+			Versions:               GetCompositeResourceDefinitionVersions(xrd.GetName(), xrd.Spec.Versions),
+			DefaultCompositionRef:  GetCompositionReference(xrd.Spec.DefaultCompositionRef),
+			EnforcedCompositionRef: GetCompositionReference(xrd.Spec.EnforcedCompositionRef),
 		},
 		Status:       GetCompositeResourceDefinitionStatus(xrd.Status),
 		Unstructured: unstruct(xrd),
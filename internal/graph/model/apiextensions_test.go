@@ -30,7 +30,7 @@ import (
 )
 
 func TestGetCompositeResourceDefinition(t *testing.T) {
-	schema := []byte(`{"cool":true}`)
+	schema := []byte(`{"type":"object"}`)
 
 	rschema := runtime.RawExtension{}
 	json.Unmarshal(schema, &rschema)
@@ -76,6 +76,7 @@ func TestGetCompositeResourceDefinition(t *testing.T) {
 							OpenAPIV3Schema: rschema,
 						},
 					}},
+					ConnectionSecretKeys:   []string{"cool"},
 					DefaultCompositionRef:  &xpv1.Reference{Name: "default"},
 					EnforcedCompositionRef: &xpv1.Reference{Name: "enforced"},
 				},
@@ -128,10 +129,15 @@ func TestGetCompositeResourceDefinition(t *testing.T) {
 						Name:          "v1",
 						Referenceable: true,
 						Served:        true,
-						Schema:        &CompositeResourceValidation{OpenAPIV3Schema: schema},
+						Schema: &CompositeResourceValidation{
+							OpenAPIV3Schema: schema,
+							OpenAPIV3:       &OpenAPISchema{props: &v1.JSONSchemaProps{Type: "object"}},
+						},
+						DefinitionName: "cool",
 					}},
-					DefaultCompositionReference:  &xpv1.Reference{Name: "default"},
-					EnforcedCompositionReference: &xpv1.Reference{Name: "enforced"},
+					ConnectionSecretKeys:   []string{"cool"},
+					DefaultCompositionRef:  &CompositionReference{Name: "default"},
+					EnforcedCompositionRef: &CompositionReference{Name: "enforced"},
 				},
 				Status: &CompositeResourceDefinitionStatus{
 					Conditions: []Condition{{}},
@@ -163,7 +169,7 @@ func TestGetCompositeResourceDefinition(t *testing.T) {
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			got := GetCompositeResourceDefinition(tc.xrd)
-			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(CompositeResourceDefinition{}, "Unstructured"), cmp.AllowUnexported(ObjectMeta{})); diff != "" {
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(CompositeResourceDefinition{}, "Unstructured"), cmp.AllowUnexported(ObjectMeta{}, OpenAPISchema{})); diff != "" {
 				t.Errorf("\n%s\nGetCompositeResourceDefinition(...): -want, +got\n:%s", tc.reason, diff)
 			}
 		})
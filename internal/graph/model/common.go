@@ -16,10 +16,13 @@ package model
 
 import (
 	"io"
+	"sort"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	kextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -28,6 +31,7 @@ import (
 	"k8s.io/utils/pointer"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	extv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
 
@@ -46,17 +50,51 @@ func UnmarshalJSON(v interface{}) ([]byte, error) {
 	return json.Marshal(v)
 }
 
-// MarshalStringMap marshals a map[string]string to GraphQL.
+// MarshalStringMap marshals a map[string]string to GraphQL. Keys are always
+// written in sorted order, so that the same map is always marshalled to the
+// same bytes.
 func MarshalStringMap(val map[string]string) graphql.Marshaler {
 	return graphql.WriterFunc(func(w io.Writer) {
-		_ = json.NewEncoder(w).Encode(val)
+		if val == nil {
+			_, _ = io.WriteString(w, "null")
+			return
+		}
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		_, _ = io.WriteString(w, "{")
+		for i, k := range keys {
+			if i > 0 {
+				_, _ = io.WriteString(w, ",")
+			}
+			graphql.MarshalString(k).MarshalGQL(w)
+			_, _ = io.WriteString(w, ":")
+			graphql.MarshalString(val[k]).MarshalGQL(w)
+		}
+		_, _ = io.WriteString(w, "}")
 	})
 }
 
-// UnmarshalStringMap marshals a map[string]string from GraphQL.
+// UnmarshalStringMap marshals a map[string]string from GraphQL. GraphQL input
+// objects are decoded as a map[string]interface{}, so any map whose values
+// are all strings is accepted.
 func UnmarshalStringMap(v interface{}) (map[string]string, error) {
-	if m, ok := v.(map[string]string); ok {
+	switch m := v.(type) {
+	case map[string]string:
 		return m, nil
+	case map[string]interface{}:
+		out := make(map[string]string, len(m))
+		for k, v := range m {
+			s, ok := v.(string)
+			if !ok {
+				return nil, errors.Errorf("value of key %q is %T, not a string", k, v)
+			}
+			out[k] = s
+		}
+		return out, nil
 	}
 
 	return nil, errors.Errorf("%T is not a map", v)
@@ -99,6 +137,28 @@ func GetConditions(in []xpv1.Condition) []Condition {
 	return out
 }
 
+// GetTimeToReady returns the number of seconds between the supplied creation
+// time and the most recent time the supplied conditions became ready, or nil if
+// they are not ready.
+func GetTimeToReady(created metav1.Time, in []xpv1.Condition) *int {
+	for _, c := range in {
+		if c.Type != xpv1.TypeReady || c.Status != corev1.ConditionTrue {
+			continue
+		}
+		if created.IsZero() || c.LastTransitionTime.IsZero() {
+			return nil
+		}
+		s := int(c.LastTransitionTime.Sub(created.Time).Seconds())
+		if s < 0 {
+			// Possible if the clocks of the API server and the controller
+			// that set the condition are skewed.
+			s = 0
+		}
+		return &s
+	}
+	return nil
+}
+
 // GetLabelSelector from the supplied Kubernetes label selector
 func GetLabelSelector(s *metav1.LabelSelector) *LabelSelector {
 	if s == nil {
@@ -120,10 +180,27 @@ func GetGenericResource(u *kunstructured.Unstructured) GenericResource {
 		APIVersion:   u.GetAPIVersion(),
 		Kind:         u.GetKind(),
 		Metadata:     GetObjectMeta(u),
+		Conditions:   getGenericConditions(u),
 		Unstructured: unstruct(u),
 	}
 }
 
+// getGenericConditions returns the status conditions of the supplied resource,
+// or nil if they don't follow the standard shape, i.e. if any condition is
+// missing its type or status.
+func getGenericConditions(u *kunstructured.Unstructured) []Condition {
+	cs := []xpv1.Condition{}
+	if err := fieldpath.Pave(u.Object).GetValueInto("status.conditions", &cs); err != nil {
+		return nil
+	}
+	for _, c := range cs {
+		if c.Type == "" || c.Status == "" {
+			return nil
+		}
+	}
+	return GetConditions(cs)
+}
+
 // A Secret holds secret data.
 type Secret struct {
 	// An opaque identifier that is unique across all types.
@@ -169,6 +246,20 @@ func (s *Secret) Data(keys []string) map[string]string {
 	return out
 }
 
+// Keys of this secret's data, sorted by name. Values are omitted, but their
+// sizes are included.
+func (s *Secret) Keys() []SecretKey {
+	if s.data == nil {
+		return nil
+	}
+	out := make([]SecretKey, 0, len(s.data))
+	for k, v := range s.data {
+		out = append(out, SecretKey{Name: k, Size: len(v)})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
 // A ConfigMap holds configuration data.
 type ConfigMap struct {
 	// An opaque identifier that is unique across all types.
@@ -300,13 +391,17 @@ func GetCustomResourceDefinitionVersions(in []kextv1.CustomResourceDefinitionVer
 	out := make([]CustomResourceDefinitionVersion, len(in))
 	for i := range in {
 		out[i] = CustomResourceDefinitionVersion{
-			Name:   in[i].Name,
-			Served: in[i].Served,
+			Name:                     in[i].Name,
+			Served:                   in[i].Served,
+			AdditionalPrinterColumns: GetCustomResourceColumnDefinitions(in[i].AdditionalPrinterColumns),
 		}
 
 		if s := in[i].Schema; s != nil && s.OpenAPIV3Schema != nil {
 			if raw, err := json.Marshal(s.OpenAPIV3Schema); err == nil {
-				out[i].Schema = &CustomResourceValidation{OpenAPIV3Schema: raw}
+				out[i].Schema = &CustomResourceValidation{
+					OpenAPIV3Schema: raw,
+					OpenAPIV3:       GetOpenAPISchema(s.OpenAPIV3Schema),
+				}
 			}
 		}
 	}
@@ -339,10 +434,49 @@ func GetCustomResourceDefinitionConditions(in []kextv1.CustomResourceDefinitionC
 
 // GetCustomResourceDefinitionStatus from the supplied Crossplane status.
 func GetCustomResourceDefinitionStatus(in kextv1.CustomResourceDefinitionStatus) *CustomResourceDefinitionStatus {
-	if len(in.Conditions) == 0 {
+	if len(in.Conditions) == 0 && in.AcceptedNames.Kind == "" && len(in.StoredVersions) == 0 {
+		return nil
+	}
+	out := &CustomResourceDefinitionStatus{
+		Conditions:     GetCustomResourceDefinitionConditions(in.Conditions),
+		StoredVersions: in.StoredVersions,
+	}
+	if in.AcceptedNames.Kind != "" {
+		out.AcceptedNames = GetCustomResourceDefinitionNames(in.AcceptedNames)
+	}
+	return out
+}
+
+// GetCustomResourceConversion from the supplied Kubernetes conversion.
+func GetCustomResourceConversion(in *kextv1.CustomResourceConversion) *CustomResourceConversion {
+	if in == nil {
 		return nil
 	}
-	return &CustomResourceDefinitionStatus{Conditions: GetCustomResourceDefinitionConditions(in.Conditions)}
+
+	out := &CustomResourceConversion{Strategy: ConversionStrategyNone}
+	if in.Strategy == kextv1.WebhookConverter {
+		out.Strategy = ConversionStrategyWebhook
+	}
+
+	if in.Webhook == nil {
+		return out
+	}
+	out.Webhook = &WebhookConversion{ConversionReviewVersions: in.Webhook.ConversionReviewVersions}
+	if cc := in.Webhook.ClientConfig; cc != nil {
+		out.Webhook.URL = cc.URL
+		if svc := cc.Service; svc != nil {
+			out.Webhook.Service = &ServiceReference{
+				Namespace: svc.Namespace,
+				Name:      svc.Name,
+				Path:      svc.Path,
+			}
+			if svc.Port != nil {
+				port := int(*svc.Port)
+				out.Webhook.Service.Port = &port
+			}
+		}
+	}
+	return out
 }
 
 // GetCustomResourceDefinition from the suppled Kubernetes CRD.
@@ -358,10 +492,11 @@ func GetCustomResourceDefinition(crd *kextv1.CustomResourceDefinition) CustomRes
 		Kind:       crd.Kind,
 		Metadata:   GetObjectMeta(crd),
 		Spec: &CustomResourceDefinitionSpec{
-			Group:    crd.Spec.Group,
-			Names:    GetCustomResourceDefinitionNames(crd.Spec.Names),
-			Scope:    GetResourceScope(crd.Spec.Scope),
-			Versions: GetCustomResourceDefinitionVersions(crd.Spec.Versions),
+			Group:      crd.Spec.Group,
+			Names:      GetCustomResourceDefinitionNames(crd.Spec.Names),
+			Scope:      GetResourceScope(crd.Spec.Scope),
+			Versions:   GetCustomResourceDefinitionVersions(crd.Spec.Versions),
+			Conversion: GetCustomResourceConversion(crd.Spec.Conversion),
 		},
 		Status:       GetCustomResourceDefinitionStatus(crd.Status),
 		Unstructured: unstruct(crd),
@@ -371,16 +506,37 @@ func GetCustomResourceDefinition(crd *kextv1.CustomResourceDefinition) CustomRes
 // GetKubernetesResource from the supplied unstructured Kubernetes resource.
 // GetKubernetesResource attempts to determine what type of resource the
 // unstructured data contains (e.g. a managed resource, a provider, etc) and
-// return the appropriate model type. If no type can be detected it returns a
-// GenericResource.
-func GetKubernetesResource(u *kunstructured.Unstructured) (KubernetesResource, error) { //nolint:gocyclo
+// return the appropriate model type. Resources of a kind for which a converter
+// has been registered are converted by that converter. Otherwise the supplied
+// CategoryIndex, which may be nil, is consulted before the resource's structure.
+// If no type can be detected it returns a GenericResource.
+func GetKubernetesResource(u *kunstructured.Unstructured, ci CategoryIndex) (KubernetesResource, error) { //nolint:gocyclo
 	// This isn't _really_ that complex; it's a long but simple switch.
 
+	if c, ok := converter(u.GroupVersionKind()); ok {
+		return c.Convert(u)
+	}
+
+	// CRDs declare the categories their kind of resource belongs to, which
+	// is a more reliable signal than the resource's structure.
+	gk := u.GroupVersionKind().GroupKind()
+	switch {
+	case inCategory(ci, gk, CategoryComposite):
+		return GetCompositeResource(u), nil
+	case inCategory(ci, gk, CategoryClaim):
+		return GetCompositeResourceClaim(u), nil
+	case inCategory(ci, gk, CategoryManaged):
+		return GetManagedResource(u), nil
+	}
+
 	switch {
 
 	case unstructured.ProbablyProviderConfig(u):
 		return GetProviderConfig(u), nil
 
+	case unstructured.ProbablyStoreConfig(u):
+		return GetStoreConfig(u), nil
+
 	case unstructured.ProbablyComposite(u):
 		return GetCompositeResource(u), nil
 
@@ -423,6 +579,18 @@ func GetKubernetesResource(u *kunstructured.Unstructured) (KubernetesResource, e
 		}
 		return GetConfigurationRevision(cr), nil
 
+	// We don't build against a version of Crossplane that includes these
+	// kinds, so we match them at any API version. They're served at different
+	// versions depending on the version of Crossplane.
+	case u.GroupVersionKind().GroupKind() == schema.GroupKind{Group: pkgv1.Group, Kind: unstructured.KindFunction}:
+		return GetFunction(u), nil
+
+	case u.GroupVersionKind().GroupKind() == schema.GroupKind{Group: pkgv1.Group, Kind: unstructured.KindDeploymentRuntimeConfig}:
+		return GetDeploymentRuntimeConfig(u), nil
+
+	case u.GroupVersionKind().GroupKind() == schema.GroupKind{Group: pkgv1.Group, Kind: unstructured.KindLock}:
+		return GetLock(u), nil
+
 	case u.GroupVersionKind() == extv1.CompositeResourceDefinitionGroupVersionKind:
 		xrd := &extv1.CompositeResourceDefinition{}
 		if err := convert(u, xrd); err != nil {
@@ -458,6 +626,34 @@ func GetKubernetesResource(u *kunstructured.Unstructured) (KubernetesResource, e
 		}
 		return GetConfigMap(cm), nil
 
+	case u.GroupVersionKind() == appsv1.SchemeGroupVersion.WithKind("Deployment"):
+		d := &appsv1.Deployment{}
+		if err := convert(u, d); err != nil {
+			return nil, errors.Wrap(err, "cannot convert deployment")
+		}
+		return GetDeployment(d), nil
+
+	case u.GroupVersionKind() == corev1.SchemeGroupVersion.WithKind("Pod"):
+		p := &corev1.Pod{}
+		if err := convert(u, p); err != nil {
+			return nil, errors.Wrap(err, "cannot convert pod")
+		}
+		return GetPod(p), nil
+
+	case u.GroupVersionKind() == rbacv1.SchemeGroupVersion.WithKind("ClusterRole"):
+		cr := &rbacv1.ClusterRole{}
+		if err := convert(u, cr); err != nil {
+			return nil, errors.Wrap(err, "cannot convert cluster role")
+		}
+		return GetClusterRole(cr), nil
+
+	case u.GroupVersionKind() == rbacv1.SchemeGroupVersion.WithKind("ClusterRoleBinding"):
+		crb := &rbacv1.ClusterRoleBinding{}
+		if err := convert(u, crb); err != nil {
+			return nil, errors.Wrap(err, "cannot convert cluster role binding")
+		}
+		return GetClusterRoleBinding(crb), nil
+
 	default:
 		return GetGenericResource(u), nil
 	}
@@ -15,11 +15,13 @@
 package model
 
 import (
+	"bytes"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	kextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -37,6 +39,88 @@ import (
 	"github.com/upbound/xgql/internal/unstructured"
 )
 
+func TestMarshalStringMap(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		m      map[string]string
+		want   string
+	}{
+		"Nil": {
+			reason: "A nil map should be marshalled as null.",
+			want:   "null",
+		},
+		"Empty": {
+			reason: "An empty map should be marshalled as an empty object.",
+			m:      map[string]string{},
+			want:   "{}",
+		},
+		"Sorted": {
+			reason: "Keys should be marshalled in sorted order, with values escaped.",
+			m:      map[string]string{"zebra": "stripes", "apple": "pie", "quote": `"hi"`},
+			want:   `{"apple":"pie","quote":"\"hi\"","zebra":"stripes"}`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Marshal repeatedly to catch any nondeterminism in map iteration.
+			for i := 0; i < 10; i++ {
+				b := &bytes.Buffer{}
+				MarshalStringMap(tc.m).MarshalGQL(b)
+				if diff := cmp.Diff(tc.want, b.String()); diff != "" {
+					t.Fatalf("\n%s\nMarshalStringMap(...): -want, +got:\n%s", tc.reason, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestUnmarshalStringMap(t *testing.T) {
+	type want struct {
+		m   map[string]string
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		v      interface{}
+		want   want
+	}{
+		"StringMap": {
+			reason: "A map of strings should be returned unchanged.",
+			v:      map[string]string{"a": "b"},
+			want:   want{m: map[string]string{"a": "b"}},
+		},
+		"InputObject": {
+			reason: "An input object whose values are all strings should be converted.",
+			v:      map[string]interface{}{"a": "b"},
+			want:   want{m: map[string]string{"a": "b"}},
+		},
+		"NonStringValue": {
+			reason: "An input object with a value that is not a string should be rejected.",
+			v:      map[string]interface{}{"a": 1},
+			want:   want{err: errors.Errorf("value of key %q is %T, not a string", "a", 1)},
+		},
+		"NotAMap": {
+			reason: "A value that is not a map should be rejected.",
+			v:      "a",
+			want:   want{err: errors.Errorf("%T is not a map", "a")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := UnmarshalStringMap(tc.v)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nUnmarshalStringMap(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.m, got); diff != "" {
+				t.Errorf("\n%s\nUnmarshalStringMap(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestGetConditions(t *testing.T) {
 	c := xpv1.Available().WithMessage("I'm here!")
 	cases := map[string]struct {
@@ -72,6 +156,47 @@ func TestGetConditions(t *testing.T) {
 	}
 }
 
+func TestGetTimeToReady(t *testing.T) {
+	created := metav1.NewTime(time.Unix(100, 0))
+	ready := xpv1.Available()
+	ready.LastTransitionTime = metav1.NewTime(time.Unix(160, 0))
+	sixty := 60
+
+	cases := map[string]struct {
+		reason  string
+		created metav1.Time
+		c       []xpv1.Condition
+		want    *int
+	}{
+		"NotReady": {
+			reason:  "A resource that is not ready should have no time to ready.",
+			created: created,
+			c:       []xpv1.Condition{xpv1.Creating()},
+			want:    nil,
+		},
+		"Ready": {
+			reason:  "A ready resource's time to ready should be the seconds between its creation and becoming ready.",
+			created: created,
+			c:       []xpv1.Condition{xpv1.ReconcileSuccess(), ready},
+			want:    &sixty,
+		},
+		"NotCreated": {
+			reason: "A resource without a creation time should have no time to ready.",
+			c:      []xpv1.Condition{ready},
+			want:   nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GetTimeToReady(tc.created, tc.c)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nGetTimeToReady(...): -want, +got\n:%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestGetGenericResource(t *testing.T) {
 	cases := map[string]struct {
 		reason string
@@ -107,6 +232,37 @@ func TestGetGenericResource(t *testing.T) {
 				Metadata: &ObjectMeta{},
 			},
 		},
+		"StandardConditions": {
+			reason: "Status conditions that follow the standard shape should be converted to our model",
+			u: &kunstructured.Unstructured{Object: map[string]interface{}{
+				"status": map[string]interface{}{
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Ready", "status": "True", "reason": "Available"},
+					},
+				},
+			}},
+			want: GenericResource{
+				Metadata: &ObjectMeta{},
+				Conditions: []Condition{{
+					Type:   "Ready",
+					Status: ConditionStatusTrue,
+					Reason: "Available",
+				}},
+			},
+		},
+		"NonStandardConditions": {
+			reason: "Status conditions that don't follow the standard shape should be omitted from our model",
+			u: &kunstructured.Unstructured{Object: map[string]interface{}{
+				"status": map[string]interface{}{
+					"conditions": []interface{}{
+						map[string]interface{}{"kind": "Ready", "healthy": true},
+					},
+				},
+			}},
+			want: GenericResource{
+				Metadata: &ObjectMeta{},
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -161,6 +317,40 @@ func TestSecretData(t *testing.T) {
 	}
 }
 
+func TestSecretKeys(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		s      *Secret
+		want   []SecretKey
+	}{
+		"NilData": {
+			reason: "If no data exists no keys should be returned.",
+			s:      &Secret{},
+			want:   nil,
+		},
+		"Data": {
+			reason: "The sorted keys of the data and the sizes of their values should be returned.",
+			s: &Secret{data: map[string]string{
+				"username": "cool",
+				"password": "verysecret",
+			}},
+			want: []SecretKey{
+				{Name: "password", Size: 10},
+				{Name: "username", Size: 4},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.s.Keys()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ns.Keys(): -want, +got\n:%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestGetSecret(t *testing.T) {
 	cases := map[string]struct {
 		reason string
@@ -322,6 +512,7 @@ func TestGetCustomResourceDefinition(t *testing.T) {
 	schema := &kextv1.JSONSchemaProps{}
 	jschema, _ := json.Marshal(schema)
 	transition := time.Now()
+	port, gport := int32(8443), 8443
 
 	cases := map[string]struct {
 		reason string
@@ -353,9 +544,22 @@ func TestGetCustomResourceDefinition(t *testing.T) {
 						Name:   "v1",
 						Served: true,
 						Schema: &kextv1.CustomResourceValidation{
-							OpenAPIV3Schema: &kextv1.JSONSchemaProps{},
+							OpenAPIV3Schema: schema,
 						},
 					}},
+					Conversion: &kextv1.CustomResourceConversion{
+						Strategy: kextv1.WebhookConverter,
+						Webhook: &kextv1.WebhookConversion{
+							ClientConfig: &kextv1.WebhookClientConfig{
+								Service: &kextv1.ServiceReference{
+									Namespace: "default",
+									Name:      "webhook",
+									Port:      &port,
+								},
+							},
+							ConversionReviewVersions: []string{"v1"},
+						},
+					},
 				},
 				Status: kextv1.CustomResourceDefinitionStatus{
 					Conditions: []kextv1.CustomResourceDefinitionCondition{{
@@ -364,6 +568,11 @@ func TestGetCustomResourceDefinition(t *testing.T) {
 						Message:            "So cool",
 						LastTransitionTime: metav1.NewTime(transition),
 					}},
+					AcceptedNames: kextv1.CustomResourceDefinitionNames{
+						Plural: "clusterexamples",
+						Kind:   "ClusterExample",
+					},
+					StoredVersions: []string{"v1"},
 				},
 			},
 			want: CustomResourceDefinition{
@@ -391,8 +600,22 @@ func TestGetCustomResourceDefinition(t *testing.T) {
 					Versions: []CustomResourceDefinitionVersion{{
 						Name:   "v1",
 						Served: true,
-						Schema: &CustomResourceValidation{OpenAPIV3Schema: jschema},
+						Schema: &CustomResourceValidation{
+							OpenAPIV3Schema: jschema,
+							OpenAPIV3:       &OpenAPISchema{props: schema},
+						},
 					}},
+					Conversion: &CustomResourceConversion{
+						Strategy: ConversionStrategyWebhook,
+						Webhook: &WebhookConversion{
+							Service: &ServiceReference{
+								Namespace: "default",
+								Name:      "webhook",
+								Port:      &gport,
+							},
+							ConversionReviewVersions: []string{"v1"},
+						},
+					},
 				},
 				Status: &CustomResourceDefinitionStatus{
 					Conditions: []Condition{{
@@ -401,6 +624,11 @@ func TestGetCustomResourceDefinition(t *testing.T) {
 						Message:            pointer.StringPtr("So cool"),
 						LastTransitionTime: transition,
 					}},
+					AcceptedNames: &CustomResourceDefinitionNames{
+						Plural: "clusterexamples",
+						Kind:   "ClusterExample",
+					},
+					StoredVersions: []string{"v1"},
 				},
 			},
 		},
@@ -419,7 +647,7 @@ func TestGetCustomResourceDefinition(t *testing.T) {
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			got := GetCustomResourceDefinition(tc.crd)
-			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(CustomResourceDefinition{}, "Unstructured"), cmp.AllowUnexported(ObjectMeta{})); diff != "" {
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(CustomResourceDefinition{}, "Unstructured"), cmp.AllowUnexported(ObjectMeta{}, OpenAPISchema{})); diff != "" {
 				t.Errorf("\n%s\nGetCustomResourceDefinition(...): -want, +got\n:%s", tc.reason, diff)
 			}
 		})
@@ -431,12 +659,16 @@ func TestGetKubernetesResource(t *testing.T) {
 		cmp.AllowUnexported(Secret{}, ConfigMap{}, ObjectMeta{}),
 		cmpopts.IgnoreFields(ManagedResource{}, "Unstructured"),
 		cmpopts.IgnoreFields(ProviderConfig{}, "Unstructured"),
+		cmpopts.IgnoreFields(StoreConfig{}, "Unstructured"),
 		cmpopts.IgnoreFields(CompositeResource{}, "Unstructured"),
 		cmpopts.IgnoreFields(CompositeResourceClaim{}, "Unstructured"),
 		cmpopts.IgnoreFields(Provider{}, "Unstructured"),
 		cmpopts.IgnoreFields(ProviderRevision{}, "Unstructured"),
 		cmpopts.IgnoreFields(Configuration{}, "Unstructured"),
 		cmpopts.IgnoreFields(ConfigurationRevision{}, "Unstructured"),
+		cmpopts.IgnoreFields(Function{}, "Unstructured"),
+		cmpopts.IgnoreFields(DeploymentRuntimeConfig{}, "Unstructured"),
+		cmpopts.IgnoreFields(Lock{}, "Unstructured"),
 		cmpopts.IgnoreFields(CompositeResourceDefinition{}, "Unstructured"),
 		cmpopts.IgnoreFields(Composition{}, "Unstructured"),
 		cmpopts.IgnoreFields(CustomResourceDefinition{}, "Unstructured"),
@@ -469,8 +701,9 @@ func TestGetKubernetesResource(t *testing.T) {
 					ID:       ReferenceID{Name: "cool"},
 					Metadata: &ObjectMeta{Name: "cool"},
 					Spec: &ManagedResourceSpec{
-						ProviderConfigRef: &ProviderConfigReference{Name: "pr"},
-						DeletionPolicy:    &dp,
+						ProviderConfigRef:       &ProviderConfigReference{Name: "pr"},
+						DeletionPolicy:          &dp,
+						DeletesExternalResource: true,
 					},
 				},
 			},
@@ -486,6 +719,23 @@ func TestGetKubernetesResource(t *testing.T) {
 					ID:       ReferenceID{Kind: "ProviderConfig"},
 					Kind:     "ProviderConfig",
 					Metadata: &ObjectMeta{},
+					Scope:    ResourceScopeClusterScoped,
+				},
+			},
+		},
+		"StoreConfig": {
+			u: func() *kunstructured.Unstructured {
+				sc := &unstructured.StoreConfig{}
+				sc.SetKind("StoreConfig")
+				return sc.GetUnstructured()
+			}(),
+			want: want{
+				kr: StoreConfig{
+					ID:       ReferenceID{Kind: "StoreConfig"},
+					Kind:     "StoreConfig",
+					Metadata: &ObjectMeta{},
+					Scope:    ResourceScopeClusterScoped,
+					Spec:     &StoreConfigSpec{},
 				},
 			},
 		},
@@ -503,8 +753,9 @@ func TestGetKubernetesResource(t *testing.T) {
 					ID:       ReferenceID{Name: "cool"},
 					Metadata: &ObjectMeta{Name: "cool"},
 					Spec: &CompositeResourceSpec{
-						CompositionReference: &corev1.ObjectReference{Name: "cmp"},
-						ResourceReferences:   []corev1.ObjectReference{{Name: "cool"}},
+						CompositionReference:        &corev1.ObjectReference{Name: "cmp"},
+						ResourceReferences:          []corev1.ObjectReference{{Name: "cool"}},
+						EnvironmentConfigReferences: []corev1.ObjectReference{},
 					},
 				},
 			},
@@ -616,6 +867,65 @@ func TestGetKubernetesResource(t *testing.T) {
 				},
 			},
 		},
+		"Function": {
+			u: func() *kunstructured.Unstructured {
+				u := &kunstructured.Unstructured{}
+				u.SetAPIVersion(schema.GroupVersion{Group: pkgv1.Group, Version: "v1beta1"}.String())
+				u.SetKind(unstructured.KindFunction)
+				return u
+			}(),
+			want: want{
+				kr: Function{
+					ID: ReferenceID{
+						APIVersion: schema.GroupVersion{Group: pkgv1.Group, Version: "v1beta1"}.String(),
+						Kind:       unstructured.KindFunction,
+					},
+					APIVersion: schema.GroupVersion{Group: pkgv1.Group, Version: "v1beta1"}.String(),
+					Kind:       unstructured.KindFunction,
+					Metadata:   &ObjectMeta{},
+					Spec:       &FunctionSpec{},
+				},
+			},
+		},
+		"DeploymentRuntimeConfig": {
+			u: func() *kunstructured.Unstructured {
+				u := &kunstructured.Unstructured{}
+				u.SetAPIVersion(schema.GroupVersion{Group: pkgv1.Group, Version: "v1beta1"}.String())
+				u.SetKind(unstructured.KindDeploymentRuntimeConfig)
+				return u
+			}(),
+			want: want{
+				kr: DeploymentRuntimeConfig{
+					ID: ReferenceID{
+						APIVersion: schema.GroupVersion{Group: pkgv1.Group, Version: "v1beta1"}.String(),
+						Kind:       unstructured.KindDeploymentRuntimeConfig,
+					},
+					APIVersion: schema.GroupVersion{Group: pkgv1.Group, Version: "v1beta1"}.String(),
+					Kind:       unstructured.KindDeploymentRuntimeConfig,
+					Metadata:   &ObjectMeta{},
+					Spec:       &DeploymentRuntimeConfigSpec{},
+				},
+			},
+		},
+		"Lock": {
+			u: func() *kunstructured.Unstructured {
+				u := &kunstructured.Unstructured{}
+				u.SetAPIVersion(schema.GroupVersion{Group: pkgv1.Group, Version: "v1alpha1"}.String())
+				u.SetKind(unstructured.KindLock)
+				return u
+			}(),
+			want: want{
+				kr: Lock{
+					ID: ReferenceID{
+						APIVersion: schema.GroupVersion{Group: pkgv1.Group, Version: "v1alpha1"}.String(),
+						Kind:       unstructured.KindLock,
+					},
+					APIVersion: schema.GroupVersion{Group: pkgv1.Group, Version: "v1alpha1"}.String(),
+					Kind:       unstructured.KindLock,
+					Metadata:   &ObjectMeta{},
+				},
+			},
+		},
 		"CompositeResourceDefinition": {
 			u: func() *kunstructured.Unstructured {
 				u := &kunstructured.Unstructured{}
@@ -732,7 +1042,7 @@ func TestGetKubernetesResource(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			kr, err := GetKubernetesResource(tc.u)
+			kr, err := GetKubernetesResource(tc.u, nil)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("GetKubernetesResource(...): -want error, +got error:\n%s", diff)
 			}
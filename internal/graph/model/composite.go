@@ -26,11 +26,16 @@ import (
 
 // A CompositeResourceSpec defines the desired state of a composite resource.
 type CompositeResourceSpec struct {
-	CompositionSelector *LabelSelector `json:"compositionSelector"`
+	CompositionSelector        *LabelSelector                `json:"compositionSelector"`
+	CompositionUpdatePolicy    *CompositionUpdatePolicy      `json:"compositionUpdatePolicy"`
+	CompositionRevisionRef     *CompositionRevisionReference `json:"compositionRevisionRef"`
+	EnvironmentConfigRefs      []EnvironmentConfigReference  `json:"environmentConfigRefs"`
+	PublishConnectionDetailsTo *PublishConnectionDetailsTo   `json:"publishConnectionDetailsTo"`
 
 	CompositionReference              *corev1.ObjectReference
 	ClaimReference                    *corev1.ObjectReference
 	ResourceReferences                []corev1.ObjectReference
+	EnvironmentConfigReferences       []corev1.ObjectReference
 	WritesConnectionSecretToReference *xpv1.SecretReference
 }
 
@@ -42,10 +47,14 @@ func GetCompositeResourceStatus(xr *unstructured.Composite) *CompositeResourceSt
 	out := &CompositeResourceStatus{}
 	if len(c) > 0 {
 		out.Conditions = GetConditions(c)
+		out.TimeToReady = GetTimeToReady(xr.GetCreationTimestamp(), c)
 	}
 	if t != nil {
 		out.ConnectionDetails = &CompositeResourceConnectionDetails{LastPublishedTime: &t.Time}
 	}
+	if ct := xr.GetClaimConditionTypes(); len(ct) > 0 {
+		out.ClaimConditionTypes = ct
+	}
 
 	if cmp.Equal(out, &CompositeResourceStatus{}) {
 		return nil
@@ -61,6 +70,7 @@ func GetCompositeResource(u *kunstructured.Unstructured) CompositeResource {
 		ID: ReferenceID{
 			APIVersion: xr.GetAPIVersion(),
 			Kind:       xr.GetKind(),
+			Namespace:  xr.GetNamespace(),
 			Name:       xr.GetName(),
 		},
 
@@ -69,16 +79,70 @@ func GetCompositeResource(u *kunstructured.Unstructured) CompositeResource {
 		Metadata:   GetObjectMeta(xr),
 		Spec: &CompositeResourceSpec{
 			CompositionSelector:               GetLabelSelector(xr.GetCompositionSelector()),
+			CompositionUpdatePolicy:           GetCompositionUpdatePolicy(xr.GetCompositionUpdatePolicy()),
+			CompositionRevisionRef:            GetCompositionRevisionReference(xr.GetCompositionRevisionReference()),
 			CompositionReference:              xr.GetCompositionReference(),
 			ClaimReference:                    xr.GetClaimReference(),
-			ResourceReferences:                xr.GetResourceReferences(),
+			ResourceReferences:                localize(xr.GetResourceReferences(), xr.GetNamespace()),
+			EnvironmentConfigRefs:             GetEnvironmentConfigReferences(xr.GetEnvironmentConfigReferences()),
+			EnvironmentConfigReferences:       xr.GetEnvironmentConfigReferences(),
 			WritesConnectionSecretToReference: xr.GetWriteConnectionSecretToReference(),
+			PublishConnectionDetailsTo:        GetPublishConnectionDetailsTo(xr.GetPublishConnectionDetailsTo(), unstructured.APIVersionSecrets),
 		},
 		Status:       GetCompositeResourceStatus(xr),
 		Unstructured: unstruct(xr),
 	}
 }
 
+// GetCompositionUpdatePolicy from the supplied Crossplane policy.
+func GetCompositionUpdatePolicy(p string) *CompositionUpdatePolicy {
+	switch p {
+	case "Automatic":
+		out := CompositionUpdatePolicyAutomatic
+		return &out
+	case "Manual":
+		out := CompositionUpdatePolicyManual
+		return &out
+	default:
+		return nil
+	}
+}
+
+// GetCompositionRevisionReference from the supplied Kubernetes reference.
+func GetCompositionRevisionReference(ref *corev1.ObjectReference) *CompositionRevisionReference {
+	if ref == nil || ref.Name == "" {
+		return nil
+	}
+	return &CompositionRevisionReference{Name: ref.Name}
+}
+
+// GetEnvironmentConfigReferences from the supplied Kubernetes references.
+func GetEnvironmentConfigReferences(in []corev1.ObjectReference) []EnvironmentConfigReference {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]EnvironmentConfigReference, len(in))
+	for i := range in {
+		out[i] = EnvironmentConfigReference{Name: in[i].Name}
+	}
+	return out
+}
+
+// localize the supplied composed resource references to the supplied namespace.
+// Crossplane v2 namespaced composite resources may only compose resources in
+// their own namespace, so their resource references omit it.
+func localize(refs []corev1.ObjectReference, namespace string) []corev1.ObjectReference {
+	if namespace == "" {
+		return refs
+	}
+	for i := range refs {
+		if refs[i].Namespace == "" {
+			refs[i].Namespace = namespace
+		}
+	}
+	return refs
+}
+
 func delocalize(ref *xpv1.LocalSecretReference, namespace string) *xpv1.SecretReference {
 	if ref == nil {
 		return nil
@@ -89,7 +153,11 @@ func delocalize(ref *xpv1.LocalSecretReference, namespace string) *xpv1.SecretRe
 // A CompositeResourceClaimSpec represents the desired state of a composite
 // resource claim.
 type CompositeResourceClaimSpec struct {
-	CompositionSelector *LabelSelector `json:"compositionSelector"`
+	CompositionSelector        *LabelSelector                `json:"compositionSelector"`
+	CompositionUpdatePolicy    *CompositionUpdatePolicy      `json:"compositionUpdatePolicy"`
+	CompositionRevisionRef     *CompositionRevisionReference `json:"compositionRevisionRef"`
+	CompositeDeletePolicy      *CompositeDeletePolicy        `json:"compositeDeletePolicy"`
+	PublishConnectionDetailsTo *PublishConnectionDetailsTo   `json:"publishConnectionDetailsTo"`
 
 	CompositionReference *corev1.ObjectReference
 	ResourceReference    *corev1.ObjectReference
@@ -108,6 +176,7 @@ func GetCompositeResourceClaimStatus(xrc *unstructured.Claim) *CompositeResource
 	out := &CompositeResourceClaimStatus{}
 	if len(c) > 0 {
 		out.Conditions = GetConditions(c)
+		out.TimeToReady = GetTimeToReady(xrc.GetCreationTimestamp(), c)
 	}
 	if t != nil {
 		out.ConnectionDetails = &CompositeResourceClaimConnectionDetails{LastPublishedTime: &t.Time}
@@ -136,9 +205,13 @@ func GetCompositeResourceClaim(u *kunstructured.Unstructured) CompositeResourceC
 		Metadata:   GetObjectMeta(xrc),
 		Spec: &CompositeResourceClaimSpec{
 			CompositionSelector:               GetLabelSelector(xrc.GetCompositionSelector()),
+			CompositionUpdatePolicy:           GetCompositionUpdatePolicy(xrc.GetCompositionUpdatePolicy()),
+			CompositionRevisionRef:            GetCompositionRevisionReference(xrc.GetCompositionRevisionReference()),
+			CompositeDeletePolicy:             GetCompositeDeletePolicy(xrc.GetCompositeDeletePolicy()),
 			CompositionReference:              xrc.GetCompositionReference(),
 			ResourceReference:                 xrc.GetResourceReference(),
 			WritesConnectionSecretToReference: delocalize(xrc.GetWriteConnectionSecretToReference(), xrc.GetNamespace()),
+			PublishConnectionDetailsTo:        GetPublishConnectionDetailsTo(xrc.GetPublishConnectionDetailsTo(), unstructured.APIVersionSecrets),
 		},
 		Status:       GetCompositeResourceClaimStatus(xrc),
 		Unstructured: unstruct(xrc),
@@ -34,6 +34,7 @@ import (
 func TestGetCompositeResource(t *testing.T) {
 	pub := time.Now()
 	mp := metav1.NewTime(pub)
+	manual := CompositionUpdatePolicyManual
 
 	cases := map[string]struct {
 		reason string
@@ -50,10 +51,14 @@ func TestGetCompositeResource(t *testing.T) {
 				xr.SetName("cool")
 				xr.SetCompositionSelector(&metav1.LabelSelector{MatchLabels: map[string]string{"cool": "very"}})
 				xr.SetCompositionReference(&corev1.ObjectReference{Name: "coolcmp"})
+				xr.SetCompositionUpdatePolicy("Manual")
+				xr.SetCompositionRevisionReference(&corev1.ObjectReference{Name: "coolcmp-1234"})
 				xr.SetClaimReference(&corev1.ObjectReference{Name: "coolclaim"})
 				xr.SetResourceReferences([]corev1.ObjectReference{{Name: "coolmanaged"}})
+				xr.SetEnvironmentConfigReferences([]corev1.ObjectReference{{Name: "coolenv"}})
 				xr.SetWriteConnectionSecretToReference(&xpv1.SecretReference{Name: "coolsecret"})
 				xr.SetConnectionDetailsLastPublishedTime(&mp)
+				xr.SetClaimConditionTypes([]string{"DatabaseReady"})
 				xr.SetConditions(xpv1.Condition{})
 
 				return xr.GetUnstructured()
@@ -71,9 +76,13 @@ func TestGetCompositeResource(t *testing.T) {
 				},
 				Spec: &CompositeResourceSpec{
 					CompositionSelector:               &LabelSelector{MatchLabels: map[string]string{"cool": "very"}},
+					CompositionUpdatePolicy:           &manual,
+					CompositionRevisionRef:            &CompositionRevisionReference{Name: "coolcmp-1234"},
 					CompositionReference:              &corev1.ObjectReference{Name: "coolcmp"},
 					ClaimReference:                    &corev1.ObjectReference{Name: "coolclaim"},
 					ResourceReferences:                []corev1.ObjectReference{{Name: "coolmanaged"}},
+					EnvironmentConfigRefs:             []EnvironmentConfigReference{{Name: "coolenv"}},
+					EnvironmentConfigReferences:       []corev1.ObjectReference{{Name: "coolenv"}},
 					WritesConnectionSecretToReference: &xpv1.SecretReference{Name: "coolsecret"},
 				},
 				Status: &CompositeResourceStatus{
@@ -81,6 +90,43 @@ func TestGetCompositeResource(t *testing.T) {
 					ConnectionDetails: &CompositeResourceConnectionDetails{
 						LastPublishedTime: &pub,
 					},
+					ClaimConditionTypes: []string{"DatabaseReady"},
+				},
+			},
+		},
+		"NamespacedV2": {
+			reason: "A Crossplane v2 namespaced XR should include its namespace in its ID, and in its resource references.",
+			u: func() *kunstructured.Unstructured {
+				xr := &unstructured.Composite{Unstructured: kunstructured.Unstructured{Object: map[string]interface{}{
+					"spec": map[string]interface{}{"crossplane": map[string]interface{}{}},
+				}}}
+
+				xr.SetAPIVersion("example.org/v2")
+				xr.SetKind("CompositeResource")
+				xr.SetNamespace("default")
+				xr.SetName("cool")
+				xr.SetCompositionReference(&corev1.ObjectReference{Name: "coolcmp"})
+				xr.SetResourceReferences([]corev1.ObjectReference{{Name: "coolmanaged"}})
+
+				return xr.GetUnstructured()
+			}(),
+			want: CompositeResource{
+				ID: ReferenceID{
+					APIVersion: "example.org/v2",
+					Kind:       "CompositeResource",
+					Namespace:  "default",
+					Name:       "cool",
+				},
+				APIVersion: "example.org/v2",
+				Kind:       "CompositeResource",
+				Metadata: &ObjectMeta{
+					Namespace: pointer.StringPtr("default"),
+					Name:      "cool",
+				},
+				Spec: &CompositeResourceSpec{
+					CompositionReference:        &corev1.ObjectReference{Name: "coolcmp"},
+					ResourceReferences:          []corev1.ObjectReference{{Namespace: "default", Name: "coolmanaged"}},
+					EnvironmentConfigReferences: []corev1.ObjectReference{},
 				},
 			},
 		},
@@ -90,10 +136,11 @@ func TestGetCompositeResource(t *testing.T) {
 			want: CompositeResource{
 				Metadata: &ObjectMeta{},
 				Spec: &CompositeResourceSpec{
-					// We don't mind this empty list being here because it's
-					// not exposed as part of our GraphQL API. We use it instead
-					// to resolve the resources array.
-					ResourceReferences: []core.ObjectReference{},
+					// We don't mind these empty lists being here because
+					// they're not exposed as part of our GraphQL API. We use
+					// them instead to resolve the resources arrays.
+					ResourceReferences:          []core.ObjectReference{},
+					EnvironmentConfigReferences: []core.ObjectReference{},
 				},
 			},
 		},
@@ -118,6 +165,8 @@ func TestGetCompositeResource(t *testing.T) {
 func TestGetCompositeResourceClaim(t *testing.T) {
 	pub := time.Now()
 	mp := metav1.NewTime(pub)
+	manual := CompositionUpdatePolicyManual
+	foreground := CompositeDeletePolicyForeground
 
 	cases := map[string]struct {
 		reason string
@@ -127,7 +176,7 @@ func TestGetCompositeResourceClaim(t *testing.T) {
 		"Full": {
 			reason: "All supported fields should be converted to our model",
 			u: func() *kunstructured.Unstructured {
-				xrc := &unstructured.Claim{Unstructured: kunstructured.Unstructured{}}
+				xrc := &unstructured.Claim{Unstructured: kunstructured.Unstructured{Object: make(map[string]interface{})}}
 
 				xrc.SetAPIVersion("example.org/v1")
 				xrc.SetKind("CompositeResource")
@@ -135,6 +184,9 @@ func TestGetCompositeResourceClaim(t *testing.T) {
 				xrc.SetName("cool")
 				xrc.SetCompositionSelector(&metav1.LabelSelector{MatchLabels: map[string]string{"cool": "very"}})
 				xrc.SetCompositionReference(&corev1.ObjectReference{Name: "coolcmp"})
+				xrc.SetCompositionUpdatePolicy("Manual")
+				xrc.SetCompositionRevisionReference(&corev1.ObjectReference{Name: "coolcmp-1234"})
+				xrc.SetCompositeDeletePolicy("Foreground")
 				xrc.SetResourceReference(&corev1.ObjectReference{Name: "coolxr"})
 				xrc.SetWriteConnectionSecretToReference(&xpv1.LocalSecretReference{Name: "coolsecret"})
 				xrc.SetConnectionDetailsLastPublishedTime(&mp)
@@ -157,6 +209,9 @@ func TestGetCompositeResourceClaim(t *testing.T) {
 				},
 				Spec: &CompositeResourceClaimSpec{
 					CompositionSelector:               &LabelSelector{MatchLabels: map[string]string{"cool": "very"}},
+					CompositionUpdatePolicy:           &manual,
+					CompositionRevisionRef:            &CompositionRevisionReference{Name: "coolcmp-1234"},
+					CompositeDeletePolicy:             &foreground,
 					CompositionReference:              &corev1.ObjectReference{Name: "coolcmp"},
 					ResourceReference:                 &corev1.ObjectReference{Name: "coolxr"},
 					WritesConnectionSecretToReference: &xpv1.SecretReference{Namespace: "default", Name: "coolsecret"},
@@ -169,6 +224,27 @@ func TestGetCompositeResourceClaim(t *testing.T) {
 				},
 			},
 		},
+		"PropagatedConditions": {
+			reason: "Conditions propagated from the composite resource should be included alongside the claim's own conditions",
+			u: func() *kunstructured.Unstructured {
+				xrc := &unstructured.Claim{Unstructured: kunstructured.Unstructured{Object: make(map[string]interface{})}}
+				xrc.SetConditions(
+					xpv1.Condition{Type: xpv1.TypeSynced, Status: corev1.ConditionTrue},
+					xpv1.Condition{Type: "DatabaseReady", Status: corev1.ConditionFalse, Reason: "Creating"},
+				)
+				return xrc.GetUnstructured()
+			}(),
+			want: CompositeResourceClaim{
+				Metadata: &ObjectMeta{},
+				Spec:     &CompositeResourceClaimSpec{},
+				Status: &CompositeResourceClaimStatus{
+					Conditions: []Condition{
+						{Type: "Synced", Status: ConditionStatusTrue},
+						{Type: "DatabaseReady", Status: ConditionStatusFalse, Reason: "Creating"},
+					},
+				},
+			},
+		},
 		"Empty": {
 			reason: "Absent optional fields should be absent in our model",
 			u:      &kunstructured.Unstructured{Object: make(map[string]interface{})},
@@ -0,0 +1,57 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GetAPIResources from the supplied Kubernetes API groups and the API resource
+// lists of their versions. Subresources are omitted.
+func GetAPIResources(groups []metav1.APIGroup, lists []*metav1.APIResourceList) []APIResource {
+	preferred := make(map[string]string, len(groups))
+	for _, g := range groups {
+		preferred[g.Name] = g.PreferredVersion.Version
+	}
+
+	out := make([]APIResource, 0)
+	for _, l := range lists {
+		gv, err := schema.ParseGroupVersion(l.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, r := range l.APIResources {
+			if strings.Contains(r.Name, "/") {
+				continue
+			}
+			out = append(out, APIResource{
+				Group:        gv.Group,
+				Version:      gv.Version,
+				Kind:         r.Kind,
+				Name:         r.Name,
+				SingularName: r.SingularName,
+				Namespaced:   r.Namespaced,
+				Verbs:        r.Verbs,
+				ShortNames:   r.ShortNames,
+				Categories:   r.Categories,
+				Preferred:    preferred[gv.Group] == gv.Version,
+			})
+		}
+	}
+	return out
+}
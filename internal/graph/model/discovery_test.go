@@ -0,0 +1,61 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetAPIResources(t *testing.T) {
+	groups := []metav1.APIGroup{{
+		Name:             "pkg.crossplane.io",
+		PreferredVersion: metav1.GroupVersionForDiscovery{GroupVersion: "pkg.crossplane.io/v1", Version: "v1"},
+	}}
+	lists := []*metav1.APIResourceList{
+		{
+			GroupVersion: "pkg.crossplane.io/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "providers", SingularName: "provider", Kind: "Provider", Verbs: metav1.Verbs{"get", "list"}, Categories: []string{"crossplane"}},
+				{Name: "providers/status", Kind: "Provider", Verbs: metav1.Verbs{"get"}},
+			},
+		},
+		{
+			GroupVersion: "pkg.crossplane.io/v1beta1",
+			APIResources: []metav1.APIResource{
+				{Name: "locks", SingularName: "lock", Kind: "Lock", Verbs: metav1.Verbs{"get"}},
+			},
+		},
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "secrets", SingularName: "secret", Namespaced: true, Kind: "Secret", Verbs: metav1.Verbs{"get"}},
+			},
+		},
+	}
+
+	want := []APIResource{
+		{Group: "pkg.crossplane.io", Version: "v1", Kind: "Provider", Name: "providers", SingularName: "provider", Verbs: []string{"get", "list"}, Categories: []string{"crossplane"}, Preferred: true},
+		{Group: "pkg.crossplane.io", Version: "v1beta1", Kind: "Lock", Name: "locks", SingularName: "lock", Verbs: []string{"get"}},
+		{Group: "", Version: "v1", Kind: "Secret", Name: "secrets", SingularName: "secret", Namespaced: true, Verbs: []string{"get"}},
+	}
+
+	got := GetAPIResources(groups, lists)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GetAPIResources(...): -want, +got:\n%s", diff)
+	}
+}
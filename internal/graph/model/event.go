@@ -56,6 +56,23 @@ type Event struct {
 	// The time at which this event was most recently recorded.
 	LastTime *time.Time `json:"lastTime"`
 
+	// The time at which this event was first observed, with microsecond
+	// precision.
+	EventTime *time.Time `json:"eventTime"`
+
+	// The series of occurrences of this event, if it has occurred more than
+	// once.
+	Series *EventSeries `json:"series"`
+
+	// The action that was taken or failed, if any.
+	Action *string `json:"action"`
+
+	// The controller that emitted the event.
+	ReportingController *string `json:"reportingController"`
+
+	// The instance of the controller that emitted the event.
+	ReportingInstance *string `json:"reportingInstance"`
+
 	// An unstructured JSON representation of the event.
 	Unstructured []byte `json:"raw"`
 
@@ -106,13 +123,23 @@ func GetEvent(e *corev1.Event) Event {
 		c := int(e.Count)
 		out.Count = &c
 	}
-	if e.Source.Component != "" {
-		out.Source = &EventSource{Component: pointer.StringPtr(e.Source.Component)}
+	if e.Source.Component != "" || e.Source.Host != "" {
+		out.Source = &EventSource{Component: nonEmpty(e.Source.Component), Host: nonEmpty(e.Source.Host)}
 	}
 	ft := e.FirstTimestamp.Time
 	out.FirstTime = &ft
 	lt := e.LastTimestamp.Time
 	out.LastTime = &lt
+	if !e.EventTime.IsZero() {
+		et := e.EventTime.Time
+		out.EventTime = &et
+	}
+	if e.Series != nil {
+		out.Series = &EventSeries{Count: int(e.Series.Count), LastObservedTime: e.Series.LastObservedTime.Time}
+	}
+	out.Action = nonEmpty(e.Action)
+	out.ReportingController = nonEmpty(e.ReportingController)
+	out.ReportingInstance = nonEmpty(e.ReportingInstance)
 
 	return out
 }
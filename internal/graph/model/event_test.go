@@ -51,9 +51,18 @@ func TestGetEvent(t *testing.T) {
 				Count:   42,
 				Source: corev1.EventSource{
 					Component: "that-thing",
+					Host:      "that-host",
 				},
 				FirstTimestamp: metav1.Time{Time: now},
 				LastTimestamp:  metav1.Time{Time: now},
+				EventTime:      metav1.MicroTime{Time: now},
+				Series: &corev1.EventSeries{
+					Count:            3,
+					LastObservedTime: metav1.MicroTime{Time: now},
+				},
+				Action:              "Reconcile",
+				ReportingController: "crossplane.io/crossplane",
+				ReportingInstance:   "crossplane-5f4d8",
 			},
 			want: Event{
 				ID: ReferenceID{
@@ -72,9 +81,18 @@ func TestGetEvent(t *testing.T) {
 				Count:   func() *int { i := 42; return &i }(),
 				Source: &EventSource{
 					Component: pointer.StringPtr("that-thing"),
+					Host:      pointer.StringPtr("that-host"),
 				},
 				FirstTime: &now,
 				LastTime:  &now,
+				EventTime: &now,
+				Series: &EventSeries{
+					Count:            3,
+					LastObservedTime: now,
+				},
+				Action:              pointer.StringPtr("Reconcile"),
+				ReportingController: pointer.StringPtr("crossplane.io/crossplane"),
+				ReportingInstance:   pointer.StringPtr("crossplane-5f4d8"),
 			},
 		},
 		"Empty": {
@@ -0,0 +1,84 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/upbound/xgql/internal/unstructured"
+)
+
+// A FunctionSpec represents the desired state of a function.
+type FunctionSpec struct {
+	Package                     string                    `json:"package"`
+	RevisionActivationPolicy    *RevisionActivationPolicy `json:"revisionActivationPolicy"`
+	RevisionHistoryLimit        *int                      `json:"revisionHistoryLimit"`
+	PackagePullPolicy           *PackagePullPolicy        `json:"packagePullPolicy"`
+	PackagePullSecrets          []string                  `json:"packagePullSecrets"`
+	IgnoreCrossplaneConstraints *bool                     `json:"ignoreCrossplaneConstraints"`
+	SkipDependencyResolution    *bool                     `json:"skipDependencyResolution"`
+
+	// RuntimeConfigName is the name of the deployment runtime config this
+	// function uses, if any.
+	RuntimeConfigName string
+}
+
+// GetFunctionStatus from the supplied Crossplane function.
+func GetFunctionStatus(fn *unstructured.Function) *FunctionStatus {
+	ps := fn.GetPackageStatus()
+	out := &FunctionStatus{Conditions: GetConditions(fn.GetConditions())}
+	if ps.CurrentRevision != "" {
+		out.CurrentRevision = &ps.CurrentRevision
+	}
+	if ps.CurrentIdentifier != "" {
+		out.CurrentIdentifier = &ps.CurrentIdentifier
+	}
+	if cmp.Equal(out, &FunctionStatus{}) {
+		return nil
+	}
+	return out
+}
+
+// GetFunction from the supplied Crossplane function. Functions are read as
+// unstructured resources, so any API version of function may be supplied.
+func GetFunction(u *kunstructured.Unstructured) Function {
+	fn := &unstructured.Function{Unstructured: *u}
+	ps := fn.GetPackageSpec()
+	return Function{
+		ID: ReferenceID{
+			APIVersion: fn.GetAPIVersion(),
+			Kind:       fn.GetKind(),
+			Name:       fn.GetName(),
+		},
+
+		APIVersion: fn.GetAPIVersion(),
+		Kind:       fn.GetKind(),
+		Metadata:   GetObjectMeta(fn),
+		Spec: &FunctionSpec{
+			Package:                     ps.Package,
+			RevisionActivationPolicy:    GetRevisionActivationPolicy(ps.RevisionActivationPolicy),
+			RevisionHistoryLimit:        getIntPtr(ps.RevisionHistoryLimit),
+			PackagePullPolicy:           GetPackagePullPolicy(ps.PackagePullPolicy),
+			PackagePullSecrets:          GetPackagePullSecrets(ps.PackagePullSecrets),
+			IgnoreCrossplaneConstraints: ps.IgnoreCrossplaneConstraints,
+			SkipDependencyResolution:    ps.SkipDependencyResolution,
+			RuntimeConfigName:           fn.GetRuntimeConfigName(),
+		},
+		Status:       GetFunctionStatus(fn),
+		Unstructured: unstruct(fn),
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+)
+
+func TestGetFunction(t *testing.T) {
+	mrap := RevisionActivationPolicyManual
+	mlim := 42
+	mppp := PackagePullPolicyAlways
+
+	cases := map[string]struct {
+		reason string
+		u      *kunstructured.Unstructured
+		want   Function
+	}{
+		"Full": {
+			reason: "All supported fields should be converted to our model",
+			u: &kunstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "pkg.crossplane.io/v1",
+				"kind":       "Function",
+				"metadata": map[string]interface{}{
+					"name": "cool",
+				},
+				"spec": map[string]interface{}{
+					"package":                     "coolthing:v1",
+					"revisionActivationPolicy":    "Manual",
+					"revisionHistoryLimit":        int64(42),
+					"packagePullPolicy":           "Always",
+					"packagePullSecrets":          []interface{}{map[string]interface{}{"name": "secret"}},
+					"ignoreCrossplaneConstraints": true,
+					"skipDependencyResolution":    true,
+					"runtimeConfigRef":            map[string]interface{}{"name": "default"},
+				},
+				"status": map[string]interface{}{
+					"conditions":        []interface{}{map[string]interface{}{}},
+					"currentRevision":   "8",
+					"currentIdentifier": "coolthing:v1",
+				},
+			}},
+			want: Function{
+				ID: ReferenceID{
+					APIVersion: "pkg.crossplane.io/v1",
+					Kind:       "Function",
+					Name:       "cool",
+				},
+				APIVersion: "pkg.crossplane.io/v1",
+				Kind:       "Function",
+				Metadata: &ObjectMeta{
+					Name: "cool",
+				},
+				Spec: &FunctionSpec{
+					Package:                     "coolthing:v1",
+					RevisionActivationPolicy:    &mrap,
+					RevisionHistoryLimit:        &mlim,
+					PackagePullPolicy:           &mppp,
+					PackagePullSecrets:          []string{"secret"},
+					IgnoreCrossplaneConstraints: pointer.BoolPtr(true),
+					SkipDependencyResolution:    pointer.BoolPtr(true),
+					RuntimeConfigName:           "default",
+				},
+				Status: &FunctionStatus{
+					Conditions:        []Condition{{}},
+					CurrentRevision:   pointer.StringPtr("8"),
+					CurrentIdentifier: pointer.StringPtr("coolthing:v1"),
+				},
+			},
+		},
+		"Empty": {
+			reason: "Absent optional fields should be absent in our model",
+			u:      &kunstructured.Unstructured{Object: map[string]interface{}{}},
+			want: Function{
+				Metadata: &ObjectMeta{},
+				Spec:     &FunctionSpec{},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GetFunction(tc.u)
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(Function{}, "Unstructured"), cmp.AllowUnexported(ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\nGetFunction(...): -want, +got\n:%s", tc.reason, diff)
+			}
+		})
+	}
+}
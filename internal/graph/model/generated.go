@@ -7,6 +7,8 @@ import (
 	"io"
 	"strconv"
 	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 // A ConditionedStatus represents the observed state of a Kubernetes resource that
@@ -34,6 +36,11 @@ type Node interface {
 	IsNode()
 }
 
+// A PackageRevision is a revision of a provider or configuration package.
+type PackageRevision interface {
+	IsPackageRevision()
+}
+
 // A ProviderConfigDefinition defines a provider configuration.
 //
 // At the time of writing a ProviderConfigDefinition will always be a
@@ -43,6 +50,152 @@ type ProviderConfigDefinition interface {
 	IsProviderConfigDefinition()
 }
 
+// An APIResource is a kind of resource served by the API server.
+type APIResource struct {
+	// The API group of this resource. The core API group is ''.
+	Group string `json:"group"`
+	// The API version of this resource.
+	Version string `json:"version"`
+	// The kind of this resource.
+	Kind string `json:"kind"`
+	// The plural name of this resource, as used in API paths.
+	Name string `json:"name"`
+	// The singular name of this resource.
+	SingularName string `json:"singularName"`
+	// Whether resources of this kind are namespaced.
+	Namespaced bool `json:"namespaced"`
+	// The verbs this resource supports, e.g. 'get' and 'list'.
+	Verbs []string `json:"verbs"`
+	// Short names for this resource, e.g. 'xrd'.
+	ShortNames []string `json:"shortNames"`
+	// The categories this resource belongs to, e.g. 'crossplane'.
+	Categories []string `json:"categories"`
+	// Whether this is the preferred version of the resource's API group.
+	Preferred bool `json:"preferred"`
+}
+
+// An APIResourceConnection represents a connection to API resources.
+type APIResourceConnection struct {
+	// Connected nodes.
+	Nodes []APIResource `json:"nodes"`
+	// The total number of connected nodes.
+	TotalCount int `json:"totalCount"`
+}
+
+// An AccessDeniedObject is a placeholder for an object the caller is not
+// permitted to read.
+type AccessDeniedObject struct {
+	// The underlying Kubernetes API version of the object.
+	APIVersion string `json:"apiVersion"`
+	// The underlying Kubernetes API kind of the object.
+	Kind string `json:"kind"`
+	// The name of the object.
+	Name string `json:"name"`
+	// The namespace of the object, if it is namespaced.
+	Namespace *string `json:"namespace"`
+	// The reason the caller may not read the object.
+	Message string `json:"message"`
+}
+
+// ApplyManifestsPayload is the result of applying a file of Kubernetes resources.
+type ApplyManifestsPayload struct {
+	// Whether every resource passed validation.
+	Valid bool `json:"valid"`
+	// Whether the resources were applied. Resources are not applied if any failed
+	// validation, or if this was a dry run.
+	Applied bool `json:"applied"`
+	// The result of applying each resource, in the order they appear in the file.
+	Results []ManifestResult `json:"results"`
+}
+
+// BuildInfo is information about the running xgql server.
+type BuildInfo struct {
+	// The version of xgql.
+	Version string `json:"version"`
+	// An opaque identifier for the GraphQL schema served by xgql. It changes
+	// whenever the schema changes. The schema itself is served at /schema.graphql.
+	SchemaVersion string `json:"schemaVersion"`
+}
+
+// A ClusterRole is a cluster level, logical grouping of Kubernetes RBAC policy
+// rules that can be referenced as a unit by a ClusterRoleBinding.
+type ClusterRole struct {
+	// An opaque identifier that is unique across all types.
+	ID ReferenceID `json:"id"`
+	// The underlying Kubernetes API version of this resource.
+	APIVersion string `json:"apiVersion"`
+	// The underlying Kubernetes API kind of this resource.
+	Kind string `json:"kind"`
+	// Metadata that is common to all Kubernetes API resources.
+	Metadata *ObjectMeta `json:"metadata"`
+	// The policy rules granted by this cluster role.
+	Rules []PolicyRule `json:"rules"`
+	// The label selectors used to aggregate the rules of other cluster roles into
+	// this one. The rules of aggregated roles are reflected in `rules`.
+	AggregationRule []LabelSelector `json:"aggregationRule"`
+	// An unstructured JSON representation of the underlying Kubernetes resource.
+	Unstructured []byte `json:"unstructured"`
+	// Events pertaining to this resource.
+	Events *EventConnection `json:"events"`
+}
+
+func (ClusterRole) IsNode()               {}
+func (ClusterRole) IsKubernetesResource() {}
+
+// A ClusterRoleBinding grants the policy rules of a cluster role to a set of
+// subjects across the entire cluster.
+type ClusterRoleBinding struct {
+	// An opaque identifier that is unique across all types.
+	ID ReferenceID `json:"id"`
+	// The underlying Kubernetes API version of this resource.
+	APIVersion string `json:"apiVersion"`
+	// The underlying Kubernetes API kind of this resource.
+	Kind string `json:"kind"`
+	// Metadata that is common to all Kubernetes API resources.
+	Metadata *ObjectMeta `json:"metadata"`
+	// The role this binding grants to its subjects.
+	RoleRef *RoleReference `json:"roleRef"`
+	// The subjects to which the referenced role is granted.
+	Subjects []Subject `json:"subjects"`
+	// An unstructured JSON representation of the underlying Kubernetes resource.
+	Unstructured []byte `json:"unstructured"`
+	// Events pertaining to this resource.
+	Events *EventConnection `json:"events"`
+	// The cluster role this binding grants, if it exists.
+	ClusterRole *ClusterRole `json:"clusterRole"`
+}
+
+func (ClusterRoleBinding) IsNode()               {}
+func (ClusterRoleBinding) IsKubernetesResource() {}
+
+// A ClusterRoleBindingConnection represents a connection to cluster role
+// bindings.
+type ClusterRoleBindingConnection struct {
+	// Connected nodes.
+	Nodes []ClusterRoleBinding `json:"nodes"`
+	// The total number of connected nodes.
+	TotalCount int `json:"totalCount"`
+}
+
+// A ClusterRoleConnection represents a connection to cluster roles.
+type ClusterRoleConnection struct {
+	// Connected nodes.
+	Nodes []ClusterRole `json:"nodes"`
+	// The total number of connected nodes.
+	TotalCount int `json:"totalCount"`
+}
+
+// The readiness of the resources a composite resource is composed of. Composed
+// resources that can't be read count as not ready.
+type ComposedReadiness struct {
+	// The number of ready composed resources.
+	Ready int `json:"ready"`
+	// The total number of composed resources.
+	Total int `json:"total"`
+	// The message of the first composed resource that is not ready, if any.
+	Message *string `json:"message"`
+}
+
 // A CompositeResource is a resource this is reconciled by composing other
 // composite or managed resources. Composite resources use a Composition to
 // determine which resources to compose, and how.
@@ -65,6 +218,9 @@ type CompositeResource struct {
 	Events *EventConnection `json:"events"`
 	// The definition of this resource.
 	Definition *CompositeResourceDefinition `json:"definition"`
+	// The readiness of the resources this resource is composed of. This is cheaper
+	// than resolving the composed resources when only their progress is needed.
+	ComposedReadiness *ComposedReadiness `json:"composedReadiness"`
 }
 
 func (CompositeResource) IsNode()               {}
@@ -90,6 +246,9 @@ type CompositeResourceClaim struct {
 	Events *EventConnection `json:"events"`
 	// The definition of this resource.
 	Definition *CompositeResourceDefinition `json:"definition"`
+	// The readiness of the resources this resource's composite resource is composed
+	// of. Null if this resource is not bound to a composite resource.
+	ComposedReadiness *ComposedReadiness `json:"composedReadiness"`
 }
 
 func (CompositeResourceClaim) IsNode()               {}
@@ -115,8 +274,12 @@ type CompositeResourceClaimConnectionDetails struct {
 // A CompositeResourceClaimStatus represents the observed status of a composite
 // resource claim.
 type CompositeResourceClaimStatus struct {
-	// The observed condition of this resource.
+	// The observed condition of this resource, including any conditions propagated
+	// from its composite resource.
 	Conditions []Condition `json:"conditions"`
+	// The number of seconds between this resource's creation and it most recently
+	// becoming ready. Null if this resource is not ready.
+	TimeToReady *int `json:"timeToReady"`
 	// The status of this composite resource's connection details.
 	ConnectionDetails *CompositeResourceClaimConnectionDetails `json:"connectionDetails"`
 }
@@ -226,34 +389,20 @@ type CompositeResourceDefinitionStatus struct {
 
 func (CompositeResourceDefinitionStatus) IsConditionedStatus() {}
 
-// A CompositeResourceDefinitionVersion describes a version of a composite
-// resource.
-type CompositeResourceDefinitionVersion struct {
-	// Name of this version, e.g. “v1”, “v2beta1”, etc. Composite resources are
-	// served under this version at `/apis/<group>/<version>/...` if `served` is
-	// true.
-	Name string `json:"name"`
-	// Referenceable specifies that this version may be referenced by a Composition
-	// in order to configure which resources an XR may be composed of. Exactly one
-	// version must be marked as referenceable; all Compositions must target only the
-	// referenceable version. The referenceable version must be served.
-	Referenceable bool `json:"referenceable"`
-	// Served specifies that this version should be served via Kubernetes REST APIs.
-	Served bool `json:"served"`
-	// Schema describes the schema used for validation, pruning, and defaulting of
-	// this version of the defined composite resource. Fields required by all
-	// composite resources are injected into this schema automatically, and override
-	// equivalently named fields in this schema.
-	Schema *CompositeResourceValidation `json:"schema"`
-}
-
 // A CompositeResourceClaimStatus represents the observed state of a composite
 // resource.
 type CompositeResourceStatus struct {
 	// The observed condition of this resource.
 	Conditions []Condition `json:"conditions"`
+	// The number of seconds between this resource's creation and it most recently
+	// becoming ready. Null if this resource is not ready.
+	TimeToReady *int `json:"timeToReady"`
 	// The status of this composite resource's connection details.
 	ConnectionDetails *CompositeResourceConnectionDetails `json:"connectionDetails"`
+	// The types of the conditions this composite resource propagates to its claim,
+	// if any. Crossplane always propagates the Synced and Ready conditions; these
+	// are any additional condition types.
+	ClaimConditionTypes []string `json:"claimConditionTypes"`
 }
 
 func (CompositeResourceStatus) IsConditionedStatus() {}
@@ -263,6 +412,9 @@ func (CompositeResourceStatus) IsConditionedStatus() {}
 type CompositeResourceValidation struct {
 	// OpenAPIV3Schema is the OpenAPI v3 schema to use for validation and pruning.
 	OpenAPIV3Schema []byte `json:"openAPIV3Schema"`
+	// OpenAPIV3 is a structured representation of openAPIV3Schema, which may be
+	// used to query only the parts of the schema a client needs.
+	OpenAPIV3 *OpenAPISchema `json:"openAPIV3"`
 }
 
 // A Composition defines the group of resources to be created when a compatible
@@ -284,6 +436,10 @@ type Composition struct {
 	Unstructured []byte `json:"unstructured"`
 	// Events pertaining to this resource.
 	Events *EventConnection `json:"events"`
+	// Composite resources that currently reference this composition via their
+	// composition reference, ordered by ID. Use this to assess which composite
+	// resources would be affected by changes to this composition.
+	CompositeResources *CompositeResourceConnection `json:"compositeResources"`
 }
 
 func (Composition) IsNode()               {}
@@ -297,6 +453,18 @@ type CompositionConnection struct {
 	TotalCount int `json:"totalCount"`
 }
 
+// A CompositionReference references a composition by name.
+type CompositionReference struct {
+	// Name of the composition.
+	Name string `json:"name"`
+}
+
+// A CompositionRevisionReference references a composition revision by name.
+type CompositionRevisionReference struct {
+	// Name of the composition revision.
+	Name string `json:"name"`
+}
+
 // A CompositionSpec represents the desired state of a composition.
 type CompositionSpec struct {
 	// CompositeTypeRef specifies the type of composite resource that this
@@ -334,6 +502,32 @@ type Condition struct {
 	// A Message containing details about this condition's last transition from one
 	// status to another, if any.
 	Message *string `json:"message"`
+	// The number of seconds since this condition last transitioned from one status
+	// to another.
+	Age int `json:"age"`
+}
+
+// A ConditionPredicate matches resources that have a condition of a particular
+// type and status.
+type ConditionPredicate struct {
+	// The type of condition to match, for example 'Ready'.
+	Type string `json:"type"`
+	// The status the condition must have. Absent conditions have an Unknown status.
+	Status ConditionStatus `json:"status"`
+	// Only match conditions that have had this status for at least this long.
+	For *time.Duration `json:"for"`
+}
+
+// A ConditionsChange is a change to the conditions of a Kubernetes resource.
+type ConditionsChange struct {
+	// The resource whose conditions changed.
+	Resource KubernetesResource `json:"resource"`
+	// All of the resource's conditions.
+	Conditions []Condition `json:"conditions"`
+	// The conditions that were added, or whose status or reason changed.
+	Changed []Condition `json:"changed"`
+	// The types of any conditions that were removed.
+	Removed []string `json:"removed"`
 }
 
 // A Configuration extends Crossplane with support for new composite resources.
@@ -358,6 +552,9 @@ type Configuration struct {
 	Revisions *ConfigurationRevisionConnection `json:"revisions"`
 	// The active revision of this configuration.
 	ActiveRevision *ConfigurationRevision `json:"activeRevision"`
+	// The packages this configuration depends on, per its current revision. Null if
+	// the package manager has not yet resolved its dependencies.
+	Dependencies []PackageDependency `json:"dependencies"`
 }
 
 func (Configuration) IsNode()               {}
@@ -393,6 +590,7 @@ type ConfigurationRevision struct {
 
 func (ConfigurationRevision) IsNode()               {}
 func (ConfigurationRevision) IsKubernetesResource() {}
+func (ConfigurationRevision) IsPackageRevision()    {}
 
 // A ConfigurationRevisionConnection represents a connection to configuration
 // revisions.
@@ -423,27 +621,6 @@ type ConfigurationRevisionSpec struct {
 	SkipDependencyResolution *bool `json:"skipDependencyResolution"`
 }
 
-// A ConfigurationSpec represents the desired state of a configuration.
-type ConfigurationSpec struct {
-	// The name of the configuration package to pull from an OCI registry.
-	Package string `json:"package"`
-	// RevisionActivationPolicy specifies how the package controller should update
-	// from one revision to the next.
-	RevisionActivationPolicy *RevisionActivationPolicy `json:"revisionActivationPolicy"`
-	// RevisionHistoryLimit dictates how the package controller cleans up old
-	// inactive package revisions. Defaults to 1. Can be disabled by explicitly
-	// setting to 0.
-	RevisionHistoryLimit *int `json:"revisionHistoryLimit"`
-	// PackagePullPolicy defines the pull policy for the package.
-	PackagePullPolicy *PackagePullPolicy `json:"packagePullPolicy"`
-	// IgnoreCrossplaneConstraints indicates to the package manager whether to honor
-	// Crossplane version constraints specified by the package.
-	IgnoreCrossplaneConstraints *bool `json:"ignoreCrossplaneConstraints"`
-	// SkipDependencyResolution indicates to the package manager whether to skip
-	// resolving dependencies for a package.
-	SkipDependencyResolution *bool `json:"skipDependencyResolution"`
-}
-
 // A ConfigurationRevisionStatus represents the observed state of a configuration.
 type ConfigurationStatus struct {
 	// The observed condition of this resource.
@@ -460,6 +637,43 @@ type ConfigurationStatus struct {
 
 func (ConfigurationStatus) IsConditionedStatus() {}
 
+// A Container is the desired state of one of a pod's containers.
+type Container struct {
+	// The name of the container.
+	Name string `json:"name"`
+	// The image the container runs.
+	Image string `json:"image"`
+	// The compute resources required by the container.
+	Resources *ResourceRequirements `json:"resources"`
+}
+
+// A ContainerStatus represents the observed state of a container.
+type ContainerStatus struct {
+	// The name of the container.
+	Name string `json:"name"`
+	// The image the container is running.
+	Image string `json:"image"`
+	// Whether the container is ready.
+	Ready bool `json:"ready"`
+	// The number of times the container has restarted.
+	RestartCount int `json:"restartCount"`
+	// The state of the container, i.e. Waiting, Running, or Terminated.
+	State *string `json:"state"`
+	// A brief, machine-readable reason the container is in its current state, e.g.
+	// CrashLoopBackOff or ImagePullBackOff.
+	Reason *string `json:"reason"`
+	// A human-readable message about the container's current state.
+	Message *string `json:"message"`
+}
+
+// CreateClaimPayload is the result of creating a composite resource claim.
+type CreateClaimPayload struct {
+	// The created claim. Null if the claim failed validation.
+	Claim *CompositeResourceClaim `json:"claim"`
+	// The reasons the claim failed validation, if any.
+	Errors []ValidationError `json:"errors"`
+}
+
 // CreateKubernetesResourceInput is the input required to create a Kubernetes
 // resource.
 type CreateKubernetesResourceInput struct {
@@ -475,6 +689,34 @@ type CreateKubernetesResourcePayload struct {
 	Resource KubernetesResource `json:"resource"`
 }
 
+// A CustomResourceColumnDefinition specifies a column that is printed when
+// listing custom resources.
+type CustomResourceColumnDefinition struct {
+	// The human readable name of the column.
+	Name string `json:"name"`
+	// The OpenAPI type of the column's values, i.e. integer, number, string,
+	// boolean, or date.
+	Type string `json:"type"`
+	// An optional OpenAPI format of the column's values, e.g. byte or date-time.
+	Format *string `json:"format"`
+	// A human readable description of the column.
+	Description *string `json:"description"`
+	// The relative importance of the column. Columns with a priority greater than
+	// zero are only shown in wide views.
+	Priority int `json:"priority"`
+	// A simple JSONPath evaluated against each resource to produce its value.
+	JSONPath string `json:"jsonPath"`
+}
+
+// A CustomResourceConversion specifies how custom resources are converted between
+// versions.
+type CustomResourceConversion struct {
+	// The strategy used to convert custom resources between versions.
+	Strategy ConversionStrategy `json:"strategy"`
+	// How to call the conversion webhook. Only set if the strategy is WEBHOOK.
+	Webhook *WebhookConversion `json:"webhook"`
+}
+
 // A CustomResourceDefinition defines a type of custom resource that extends the
 // set of resources supported by the Kubernetes API.
 type CustomResourceDefinition struct {
@@ -494,8 +736,17 @@ type CustomResourceDefinition struct {
 	Unstructured []byte `json:"unstructured"`
 	// Events pertaining to this resource.
 	Events *EventConnection `json:"events"`
-	// Custom resources defined by this CRD
+	// Custom resources defined by this CRD, ordered by ID. The total count includes
+	// all defined resources, regardless of any limit or offset.
 	DefinedResources *KubernetesResourceConnection `json:"definedResources"`
+	// The additional printer columns of the supplied custom resource, which must be
+	// defined by this CRD, evaluated against it. Returns the same columns (in the
+	// same order) that `kubectl get` would show for the resource's version.
+	PrinterColumns []PrinterColumnValue `json:"printerColumns"`
+	// The package revision that installed this CRD, if any. Providers install CRDs
+	// directly, while configurations install the XRDs that define CRDs. The active
+	// revision is preferred when several revisions of a package own this CRD.
+	PackageRevision PackageRevision `json:"packageRevision"`
 }
 
 func (CustomResourceDefinition) IsNode()                      {}
@@ -555,6 +806,8 @@ type CustomResourceDefinitionSpec struct {
 	// major version, then minor version. An example sorted list of versions: v10,
 	// v2, v1, v11beta2, v10beta3, v3beta1, v12alpha1, v11alpha2, foo1, foo10.
 	Versions []CustomResourceDefinitionVersion `json:"versions"`
+	// Conversion specifies how custom resources are converted between versions.
+	Conversion *CustomResourceConversion `json:"conversion"`
 }
 
 // A CustomResourceDefinitionStatus represents the observed state of a custom
@@ -562,6 +815,12 @@ type CustomResourceDefinitionSpec struct {
 type CustomResourceDefinitionStatus struct {
 	// The observed condition of this resource.
 	Conditions []Condition `json:"conditions"`
+	// The names that are actually being used to serve discovery. They may be
+	// different from the names in the spec.
+	AcceptedNames *CustomResourceDefinitionNames `json:"acceptedNames"`
+	// The versions of custom resources that were ever persisted. Versions may not
+	// be removed from the spec while they exist in this list.
+	StoredVersions []string `json:"storedVersions"`
 }
 
 func (CustomResourceDefinitionStatus) IsConditionedStatus() {}
@@ -576,6 +835,9 @@ type CustomResourceDefinitionVersion struct {
 	// Schema describes the schema used for validation, pruning, and defaulting of
 	// this version of the defined custom resource.
 	Schema *CustomResourceValidation `json:"schema"`
+	// Additional columns that are printed when listing resources of this version,
+	// e.g. by `kubectl get`.
+	AdditionalPrinterColumns []CustomResourceColumnDefinition `json:"additionalPrinterColumns"`
 }
 
 // A CustomResourceValidation is a list of validation methods for a custom
@@ -583,6 +845,24 @@ type CustomResourceDefinitionVersion struct {
 type CustomResourceValidation struct {
 	// OpenAPIV3Schema is the OpenAPI v3 schema to use for validation and pruning.
 	OpenAPIV3Schema []byte `json:"openAPIV3Schema"`
+	// OpenAPIV3 is a structured representation of openAPIV3Schema, which may be
+	// used to query only the parts of the schema a client needs.
+	OpenAPIV3 *OpenAPISchema `json:"openAPIV3"`
+}
+
+// DeleteCompositePayload is the result of deleting a composite resource or claim.
+type DeleteCompositePayload struct {
+	// The deleted composite resource or claim. Null if the delete failed.
+	Resource KubernetesResource `json:"resource"`
+	// Whether the resource was deleted. False for a dry run.
+	Deleted bool `json:"deleted"`
+	// The resources the deletion cascades to. These are the composed resources of
+	// a composite resource (and of any composite resources it composes), and the
+	// composite resource of a claim.
+	AffectedResources []KubernetesResource `json:"affectedResources"`
+	// The number of affected managed resources whose deletion will delete an
+	// external resource, e.g. a cloud resource, rather than orphan it.
+	ExternalResourceCount int `json:"externalResourceCount"`
 }
 
 // DeleteKubernetesResourcePayload is the result of deleting a Kubernetes resource.
@@ -591,6 +871,111 @@ type DeleteKubernetesResourcePayload struct {
 	Resource KubernetesResource `json:"resource"`
 }
 
+// A Deployment runs a set of replicated pods, for example a provider's
+// controller.
+type Deployment struct {
+	// An opaque identifier that is unique across all types.
+	ID ReferenceID `json:"id"`
+	// The underlying Kubernetes API version of this resource.
+	APIVersion string `json:"apiVersion"`
+	// The underlying Kubernetes API kind of this resource.
+	Kind string `json:"kind"`
+	// Metadata that is common to all Kubernetes API resources.
+	Metadata *ObjectMeta `json:"metadata"`
+	// The desired state of this resource.
+	Spec *DeploymentSpec `json:"spec"`
+	// The observed state of this resource.
+	Status *DeploymentStatus `json:"status"`
+	// An unstructured JSON representation of the underlying Kubernetes resource.
+	Unstructured []byte `json:"unstructured"`
+	// Events pertaining to this resource.
+	Events *EventConnection `json:"events"`
+}
+
+func (Deployment) IsNode()               {}
+func (Deployment) IsKubernetesResource() {}
+
+// A DeploymentRuntimeConfig configures how the Crossplane package manager deploys
+// the controllers of providers and functions.
+type DeploymentRuntimeConfig struct {
+	// An opaque identifier that is unique across all types.
+	ID ReferenceID `json:"id"`
+	// The underlying Kubernetes API version of this resource.
+	APIVersion string `json:"apiVersion"`
+	// The underlying Kubernetes API kind of this resource.
+	Kind string `json:"kind"`
+	// Metadata that is common to all Kubernetes API resources.
+	Metadata *ObjectMeta `json:"metadata"`
+	// The desired state of this resource.
+	Spec *DeploymentRuntimeConfigSpec `json:"spec"`
+	// An unstructured JSON representation of the underlying Kubernetes resource.
+	Unstructured []byte `json:"unstructured"`
+	// Events pertaining to this resource.
+	Events *EventConnection `json:"events"`
+}
+
+func (DeploymentRuntimeConfig) IsNode()               {}
+func (DeploymentRuntimeConfig) IsKubernetesResource() {}
+
+// A DeploymentRuntimeConfigConnection represents a connection to deployment
+// runtime configs.
+type DeploymentRuntimeConfigConnection struct {
+	// Connected nodes.
+	Nodes []DeploymentRuntimeConfig `json:"nodes"`
+	// The total number of connected nodes.
+	TotalCount int `json:"totalCount"`
+}
+
+// A DeploymentRuntimeConfigSpec represents the desired state of a deployment
+// runtime config. Each template is a partial Kubernetes resource that the package
+// manager merges into the resource it creates.
+type DeploymentRuntimeConfigSpec struct {
+	// A partial Deployment used to deploy a package's controller.
+	DeploymentTemplate []byte `json:"deploymentTemplate"`
+	// A partial Service used to expose a package's controller.
+	ServiceTemplate []byte `json:"serviceTemplate"`
+	// A partial ServiceAccount used by a package's controller.
+	ServiceAccountTemplate []byte `json:"serviceAccountTemplate"`
+}
+
+// A DeploymentSpec represents the desired state of a deployment.
+type DeploymentSpec struct {
+	// The desired number of replicas.
+	Replicas *int `json:"replicas"`
+	// How long a new pod must be ready, without any of its containers crashing, to
+	// be considered available.
+	MinReady *time.Duration `json:"minReady"`
+	// How long the deployment may take to make progress before it is considered to
+	// have failed.
+	ProgressDeadline *time.Duration `json:"progressDeadline"`
+	// The containers of each of the deployment's pods.
+	Containers []Container `json:"containers"`
+}
+
+// A DeploymentStatus represents the observed state of a deployment.
+type DeploymentStatus struct {
+	// The observed condition of this resource.
+	Conditions []Condition `json:"conditions"`
+	// The desired number of replicas.
+	Replicas int `json:"replicas"`
+	// The number of replicas that have the desired pod template.
+	UpdatedReplicas int `json:"updatedReplicas"`
+	// The number of replicas that are ready.
+	ReadyReplicas int `json:"readyReplicas"`
+	// The number of replicas that have been ready for at least minReadySeconds.
+	AvailableReplicas int `json:"availableReplicas"`
+	// The number of replicas that are not yet available.
+	UnavailableReplicas int `json:"unavailableReplicas"`
+}
+
+func (DeploymentStatus) IsConditionedStatus() {}
+
+// An EnvironmentConfigReference references an EnvironmentConfig by name.
+type EnvironmentConfigReference struct {
+	// Name of the EnvironmentConfig.
+	Name string `json:"name"`
+}
+
 // An EventConnection represents a connection to events.
 type EventConnection struct {
 	// Connected nodes.
@@ -599,14 +984,76 @@ type EventConnection struct {
 	TotalCount int `json:"totalCount"`
 }
 
+// An EventSeries is a series of occurrences of an event.
+type EventSeries struct {
+	// The number of times the event has occurred.
+	Count int `json:"count"`
+	// The time at which the event was most recently observed.
+	LastObservedTime time.Time `json:"lastObservedTime"`
+}
+
 // An EventSource is the source of an event. Note that in this context 'source'
 // indicates the software or system that emitted the event, not the Kubernetes
 // resource it pertains to.
 type EventSource struct {
 	// The software component that emitted the event.
 	Component *string `json:"component"`
+	// The host on which the event was emitted.
+	Host *string `json:"host"`
+}
+
+// ForceReconcilePayload is the result of requesting a resource be reconciled.
+type ForceReconcilePayload struct {
+	// The updated Kubernetes resource. Null if the update failed.
+	Resource KubernetesResource `json:"resource"`
+}
+
+// A Function extends Crossplane with a composition function, which composite
+// resources may use to compose resources.
+type Function struct {
+	// An opaque identifier that is unique across all types.
+	ID ReferenceID `json:"id"`
+	// The underlying Kubernetes API version of this resource.
+	APIVersion string `json:"apiVersion"`
+	// The underlying Kubernetes API kind of this resource.
+	Kind string `json:"kind"`
+	// Metadata that is common to all Kubernetes API resources.
+	Metadata *ObjectMeta `json:"metadata"`
+	// The desired state of this resource.
+	Spec *FunctionSpec `json:"spec"`
+	// The observed state of this resource.
+	Status *FunctionStatus `json:"status"`
+	// An unstructured JSON representation of the underlying Kubernetes resource.
+	Unstructured []byte `json:"unstructured"`
+	// Events pertaining to this resource.
+	Events *EventConnection `json:"events"`
+}
+
+func (Function) IsNode()               {}
+func (Function) IsKubernetesResource() {}
+
+// A FunctionConnection represents a connection to functions.
+type FunctionConnection struct {
+	// Connected nodes.
+	Nodes []Function `json:"nodes"`
+	// The total number of connected nodes.
+	TotalCount int `json:"totalCount"`
+}
+
+// A FunctionStatus represents the observed state of a function.
+type FunctionStatus struct {
+	// The observed condition of this resource.
+	Conditions []Condition `json:"conditions"`
+	// CurrentRevision is the name of the current package revision. It will reflect
+	// the most up to date revision, whether it has been activated or not.
+	CurrentRevision *string `json:"currentRevision"`
+	// CurrentIdentifier is the most recent package source that was used to produce a
+	// revision.
+	CurrentIdentifier *string `json:"currentIdentifier"`
 }
 
+func (FunctionStatus) IsConditionedStatus() {}
+
 // A GenericResource represents a kind of Kubernetes resource that does not
 // correspond to a kind or class of resources that is more specifically modelled
 // by xgql.
@@ -619,6 +1066,13 @@ type GenericResource struct {
 	Kind string `json:"kind"`
 	// Metadata that is common to all Kubernetes API resources.
 	Metadata *ObjectMeta `json:"metadata"`
+	// The desired state of this resource, i.e. its spec field.
+	Spec []byte `json:"spec"`
+	// The observed state of this resource, i.e. its status field.
+	Status []byte `json:"status"`
+	// The observed condition of this resource. Null unless the resource's
+	// status.conditions field follows the standard Kubernetes condition shape.
+	Conditions []Condition `json:"conditions"`
 	// An unstructured JSON representation of the underlying Kubernetes resource.
 	Unstructured []byte `json:"unstructured"`
 	// Events pertaining to this resource.
@@ -628,12 +1082,21 @@ type GenericResource struct {
 func (GenericResource) IsNode()               {}
 func (GenericResource) IsKubernetesResource() {}
 
+// InstallPackagePayload is the result of installing a package.
+type InstallPackagePayload struct {
+	// The created provider, configuration, or function. Null if the create failed.
+	Resource KubernetesResource `json:"resource"`
+}
+
 // A KubernetesResourceConnection represents a connection to Kubernetes resources.
 type KubernetesResourceConnection struct {
 	// Connected nodes.
 	Nodes []KubernetesResource `json:"nodes"`
 	// The total number of connected nodes.
 	TotalCount int `json:"totalCount"`
+	// Objects that were omitted from the connection because the caller is not
+	// permitted to read them. Only populated when forbidden objects are skipped.
+	AccessDenied []AccessDeniedObject `json:"accessDenied"`
 }
 
 // A LabelSelector matches a Kubernetes resource by labels.
@@ -642,6 +1105,52 @@ type LabelSelector struct {
 	MatchLabels map[string]string `json:"matchLabels"`
 }
 
+// A Lock records the packages installed by the Crossplane package manager, and
+// their dependencies. There is a single lock per control plane.
+type Lock struct {
+	// An opaque identifier that is unique across all types.
+	ID ReferenceID `json:"id"`
+	// The underlying Kubernetes API version of this resource.
+	APIVersion string `json:"apiVersion"`
+	// The underlying Kubernetes API kind of this resource.
+	Kind string `json:"kind"`
+	// Metadata that is common to all Kubernetes API resources.
+	Metadata *ObjectMeta `json:"metadata"`
+	// The packages recorded in the lock.
+	Packages []LockPackage `json:"packages"`
+	// An unstructured JSON representation of the underlying Kubernetes resource.
+	Unstructured []byte `json:"unstructured"`
+	// Events pertaining to this resource.
+	Events *EventConnection `json:"events"`
+}
+
+func (Lock) IsNode()               {}
+func (Lock) IsKubernetesResource() {}
+
+// A LockDependency is a package upon which a locked package depends.
+type LockDependency struct {
+	// The OCI repository of the dependency, without a tag.
+	Package string `json:"package"`
+	// The type of the dependency.
+	Type *PackageType `json:"type"`
+	// The semantic version constraints of the dependency, if any.
+	Constraints *string `json:"constraints"`
+}
+
+// A LockPackage is a package recorded in the lock.
+type LockPackage struct {
+	// The name of the package revision.
+	Name string `json:"name"`
+	// The type of the package.
+	Type *PackageType `json:"type"`
+	// The OCI repository of the package, without a tag.
+	Source string `json:"source"`
+	// The version of the package.
+	Version string `json:"version"`
+	// The packages upon which this package depends.
+	Dependencies []LockDependency `json:"dependencies"`
+}
+
 // A ManagedResource is a Kubernetes API representation of a resource in an
 // external system, such as a cloud provider's API. Crossplane providers add
 // support for new kinds of managed resource.
@@ -664,18 +1173,35 @@ type ManagedResource struct {
 	Events *EventConnection `json:"events"`
 	// The definition of this resource.
 	Definition ManagedResourceDefinition `json:"definition"`
+	// The provider that installed the definition of this resource.
+	Provider *Provider `json:"provider"`
+	// The provider revision that installed the definition of this resource. This
+	// is the provider's active revision, if it is one of the revisions that
+	// installed the definition.
+	ProviderRevision *ProviderRevision `json:"providerRevision"`
 }
 
 func (ManagedResource) IsNode()               {}
 func (ManagedResource) IsKubernetesResource() {}
 
-// A ManagedResourceStatus represents the observed state of a managed resource.
-type ManagedResourceStatus struct {
-	// The observed condition of this resource.
-	Conditions []Condition `json:"conditions"`
+// A ManagedResourceConnection represents a connection to managed resources.
+type ManagedResourceConnection struct {
+	// Connected nodes.
+	Nodes []ManagedResource `json:"nodes"`
+	// The total number of connected nodes.
+	TotalCount int `json:"totalCount"`
 }
 
-func (ManagedResourceStatus) IsConditionedStatus() {}
+// A ManifestResult is the result of applying one resource of a file.
+type ManifestResult struct {
+	// The index of the resource within the file, starting from zero.
+	Index int `json:"index"`
+	// The resource as it was (or for a dry run would be) persisted, including any
+	// defaults or mutations applied by the API server. Null if it failed.
+	Resource KubernetesResource `json:"resource"`
+	// The reasons the resource failed validation or could not be applied, if any.
+	Errors []ValidationError `json:"errors"`
+}
 
 // An owner of a Kubernetes resource.
 type Owner struct {
@@ -693,6 +1219,40 @@ type OwnerConnection struct {
 	TotalCount int `json:"totalCount"`
 }
 
+// A PackageDependency is a package upon which another package depends.
+type PackageDependency struct {
+	// The OCI repository of the dependency, without a tag.
+	Package string `json:"package"`
+	// The type of the dependency.
+	Type *PackageType `json:"type"`
+	// The semantic version constraints of the dependency, if any.
+	Constraints *string `json:"constraints"`
+	// The installed package that satisfies this dependency. Null if no package from
+	// the dependency's OCI repository is installed, in which case the dependency is
+	// unmet.
+	Installed KubernetesResource `json:"installed"`
+}
+
+// The health of a kind of package.
+type PackageHealthSummary struct {
+	// The total number of packages.
+	Total int `json:"total"`
+	// The number of healthy packages.
+	Healthy int `json:"healthy"`
+	// The number of packages that are not known to be healthy.
+	Unhealthy int `json:"unhealthy"`
+}
+
+// A PackagePullSecret is a secret used to pull a package from a private registry.
+type PackagePullSecret struct {
+	// The name of the secret.
+	Name string `json:"name"`
+	// The namespace of the secret, which is the namespace Crossplane runs in.
+	Namespace string `json:"namespace"`
+	// The secret's metadata. Null if the secret does not exist.
+	Metadata *ObjectMeta `json:"metadata"`
+}
+
 // A Patch that should be applied to an unstructured input before it is submitted.
 type Patch struct {
 	// A field path references a field within a Kubernetes object via a simple
@@ -725,6 +1285,60 @@ type Patch struct {
 	Unstructured []byte `json:"unstructured"`
 }
 
+// A Pod is a group of containers, for example the containers that run a
+// provider's controller.
+type Pod struct {
+	// An opaque identifier that is unique across all types.
+	ID ReferenceID `json:"id"`
+	// The underlying Kubernetes API version of this resource.
+	APIVersion string `json:"apiVersion"`
+	// The underlying Kubernetes API kind of this resource.
+	Kind string `json:"kind"`
+	// Metadata that is common to all Kubernetes API resources.
+	Metadata *ObjectMeta `json:"metadata"`
+	// The observed state of this resource.
+	Status *PodStatus `json:"status"`
+	// An unstructured JSON representation of the underlying Kubernetes resource.
+	Unstructured []byte `json:"unstructured"`
+	// Events pertaining to this resource.
+	Events *EventConnection `json:"events"`
+}
+
+func (Pod) IsNode()               {}
+func (Pod) IsKubernetesResource() {}
+
+// A PodConnection represents a connection to pods.
+type PodConnection struct {
+	// Connected nodes.
+	Nodes []Pod `json:"nodes"`
+	// The total number of connected nodes.
+	TotalCount int `json:"totalCount"`
+}
+
+// PodLogs are the logs of a pod's container.
+type PodLogs struct {
+	// The container the logs were read from, if one was specified.
+	Container *string `json:"container"`
+	// The lines of the logs, oldest first.
+	Lines []string `json:"lines"`
+}
+
+// A PodStatus represents the observed state of a pod.
+type PodStatus struct {
+	// The observed condition of this resource.
+	Conditions []Condition `json:"conditions"`
+	// The phase of the pod, e.g. Pending, Running, Succeeded, Failed, or Unknown.
+	Phase *string `json:"phase"`
+	// Whether all of the pod's containers are ready.
+	Ready bool `json:"ready"`
+	// The total number of times the pod's containers have restarted.
+	RestartCount int `json:"restartCount"`
+	// The observed state of each of the pod's containers.
+	ContainerStatuses []ContainerStatus `json:"containerStatuses"`
+}
+
+func (PodStatus) IsConditionedStatus() {}
+
 // A PolicyRule holds information that describes a KubernetesRBAC policy rule.
 type PolicyRule struct {
 	// Verbs is a list of verbs that apply to ALL the resources specified by this
@@ -747,7 +1361,22 @@ type PolicyRule struct {
 	NonResourceURLs []string `json:"nonResourceURLs"`
 }
 
-// A Provider extends Crossplane with support for new managed resources.
+// A PrinterColumnValue is the value of a printer column for a particular custom
+// resource.
+type PrinterColumnValue struct {
+	// The human readable name of the column.
+	Name string `json:"name"`
+	// The OpenAPI type of the column's value.
+	Type string `json:"type"`
+	// The relative importance of the column. Columns with a priority greater than
+	// zero are only shown in wide views.
+	Priority int `json:"priority"`
+	// The value of the column, formatted as a string. Dates are RFC3339 formatted.
+	// Null if the column's JSONPath did not match the resource.
+	Value *string `json:"value"`
+}
+
+// A Provider extends Crossplane with support for new managed resources.
 type Provider struct {
 	// An opaque identifier that is unique across all types.
 	ID ReferenceID `json:"id"`
@@ -769,6 +1398,12 @@ type Provider struct {
 	Revisions *ProviderRevisionConnection `json:"revisions"`
 	// The active revision of this provider.
 	ActiveRevision *ProviderRevision `json:"activeRevision"`
+	// Kubernetes RBAC resources created for this provider by the Crossplane package
+	// manager and RBAC manager.
+	Rbac *ProviderRbac `json:"rbac"`
+	// Managed resources defined by custom resource definitions this provider
+	// installed, ordered by ID.
+	ManagedResources *ManagedResourceConnection `json:"managedResources"`
 }
 
 func (Provider) IsNode()               {}
@@ -785,6 +1420,9 @@ type ProviderConfig struct {
 	Kind string `json:"kind"`
 	// Metadata that is common to all Kubernetes API resources.
 	Metadata *ObjectMeta `json:"metadata"`
+	// The scope of this provider config. Namespaced provider configs may only be
+	// used by managed resources in their namespace.
+	Scope ResourceScope `json:"scope"`
 	// The observed state of this resource.
 	Status *ProviderConfigStatus `json:"status"`
 	// An unstructured JSON representation of the underlying Kubernetes resource.
@@ -798,6 +1436,14 @@ type ProviderConfig struct {
 func (ProviderConfig) IsNode()               {}
 func (ProviderConfig) IsKubernetesResource() {}
 
+// A ProviderConfigConnection represents a connection to provider configs.
+type ProviderConfigConnection struct {
+	// Connected nodes.
+	Nodes []ProviderConfig `json:"nodes"`
+	// The total number of connected nodes.
+	TotalCount int `json:"totalCount"`
+}
+
 // A reference to the ProviderConfig used by a particular managed resource.
 type ProviderConfigReference struct {
 	// Name of the provider config.
@@ -822,6 +1468,17 @@ type ProviderConnection struct {
 	TotalCount int `json:"totalCount"`
 }
 
+// A ProviderRBAC represents the Kubernetes RBAC resources that were created by
+// the Crossplane package manager and RBAC manager on behalf of a provider.
+type ProviderRbac struct {
+	// Cluster roles that were created for the provider's revisions, including the
+	// role granted to the provider's controller.
+	ClusterRoles *ClusterRoleConnection `json:"clusterRoles"`
+	// Cluster role bindings that were created for the provider's revisions. These
+	// grant the provider's controller its cluster role.
+	ClusterRoleBindings *ClusterRoleBindingConnection `json:"clusterRoleBindings"`
+}
+
 // A ProviderRevision represents a revision or 'version' of a provider.
 type ProviderRevision struct {
 	// An opaque identifier that is unique across all types.
@@ -840,8 +1497,17 @@ type ProviderRevision struct {
 	Unstructured []byte `json:"unstructured"`
 	// Events pertaining to this resource.
 	Events *EventConnection `json:"events"`
+	// Managed resources defined by custom resource definitions this provider revision
+	// installed, ordered by ID.
+	ManagedResources *ManagedResourceConnection `json:"managedResources"`
+	// The deployment that runs this provider revision's controller. Only active
+	// revisions have a deployment.
+	Deployment *Deployment `json:"deployment"`
+	// The pods that run this provider revision's controller, ordered by name.
+	Pods *PodConnection `json:"pods"`
 }
 
+func (ProviderRevision) IsPackageRevision()    {}
 func (ProviderRevision) IsNode()               {}
 func (ProviderRevision) IsKubernetesResource() {}
 
@@ -873,27 +1539,6 @@ type ProviderRevisionSpec struct {
 	SkipDependencyResolution *bool `json:"skipDependencyResolution"`
 }
 
-// A ProviderSpec represents the desired state of a provider.
-type ProviderSpec struct {
-	// The name of the provider package to pull from an OCI registry.
-	Package string `json:"package"`
-	// RevisionActivationPolicy specifies how the package controller should update
-	// from one revision to the next.
-	RevisionActivationPolicy *RevisionActivationPolicy `json:"revisionActivationPolicy"`
-	// RevisionHistoryLimit dictates how the package controller cleans up old
-	// inactive package revisions. Defaults to 1. Can be disabled by explicitly
-	// setting to 0.
-	RevisionHistoryLimit *int `json:"revisionHistoryLimit"`
-	// PackagePullPolicy defines the pull policy for the package.
-	PackagePullPolicy *PackagePullPolicy `json:"packagePullPolicy"`
-	// IgnoreCrossplaneConstraints indicates to the package manager whether to honor
-	// Crossplane version constraints specified by the package.
-	IgnoreCrossplaneConstraints *bool `json:"ignoreCrossplaneConstraints"`
-	// SkipDependencyResolution indicates to the package manager whether to skip
-	// resolving dependencies for a package.
-	SkipDependencyResolution *bool `json:"skipDependencyResolution"`
-}
-
 // A ProviderStatus represents the observed state of a provider.
 type ProviderStatus struct {
 	// The observed condition of this resource.
@@ -910,6 +1555,194 @@ type ProviderStatus struct {
 
 func (ProviderStatus) IsConditionedStatus() {}
 
+// The health of the resources of an API group.
+type ResourceHealthSummary struct {
+	// The API group.
+	Group string `json:"group"`
+	// The total number of resources in the API group.
+	Total int `json:"total"`
+	// The number of ready resources.
+	Ready int `json:"ready"`
+	// The number of resources that are not known to be ready.
+	NotReady int `json:"notReady"`
+	// The number of synced resources.
+	Synced int `json:"synced"`
+	// The number of resources that are not known to be synced.
+	NotSynced int `json:"notSynced"`
+}
+
+// A ResourceQuantity is an amount of a compute resource.
+type ResourceQuantity struct {
+	// The name of the resource, e.g. cpu or memory.
+	Resource string `json:"resource"`
+	// The amount of the resource.
+	Quantity resource.Quantity `json:"quantity"`
+}
+
+// ResourceRequirements are the compute resources required by a container.
+type ResourceRequirements struct {
+	// The maximum amount of each resource the container may use.
+	Limits []ResourceQuantity `json:"limits"`
+	// The amount of each resource the container is guaranteed.
+	Requests []ResourceQuantity `json:"requests"`
+}
+
+// A RevisionDiff describes the difference between the objects installed by two
+// package revisions. Objects installed by both revisions are compared by
+// reference and by content, which is everything but their metadata and status.
+type RevisionDiff struct {
+	// Objects installed by revision b but not revision a.
+	Added []RevisionObjectDiff `json:"added"`
+	// Objects installed by revision a but not revision b.
+	Removed []RevisionObjectDiff `json:"removed"`
+	// Objects installed by both revisions that changed, for example because they
+	// are installed at a different API version, were recreated, or have a
+	// different spec.
+	Changed []RevisionObjectDiff `json:"changed"`
+}
+
+// A RevisionObjectDiff describes an object that differs between two package
+// revisions.
+type RevisionObjectDiff struct {
+	// The Kubernetes API kind of the object.
+	Kind string `json:"kind"`
+	// The name of the object.
+	Name string `json:"name"`
+	// The API version of the object as installed by revision a, if any.
+	FromAPIVersion *string `json:"fromAPIVersion"`
+	// The API version of the object as installed by revision b, if any.
+	ToAPIVersion *string `json:"toAPIVersion"`
+	// The object, if it exists.
+	Resource KubernetesResource `json:"resource"`
+}
+
+// A RoleReference references the role granted by a role binding.
+type RoleReference struct {
+	// The API group of the referenced role.
+	APIGroup string `json:"apiGroup"`
+	// The kind of the referenced role, e.g. 'ClusterRole'.
+	Kind string `json:"kind"`
+	// The name of the referenced role.
+	Name string `json:"name"`
+}
+
+// A SecretKey is a key of the data stored in a secret.
+type SecretKey struct {
+	// The name of the key.
+	Name string `json:"name"`
+	// The size of the key's value in bytes.
+	Size int `json:"size"`
+}
+
+// A ServiceReference is a reference to a Kubernetes service.
+type ServiceReference struct {
+	// The namespace of the service.
+	Namespace string `json:"namespace"`
+	// The name of the service.
+	Name string `json:"name"`
+	// An optional URL path at which the service will be contacted.
+	Path *string `json:"path"`
+	// The port at which the service will be contacted. Defaults to 443.
+	Port *int `json:"port"`
+}
+
+// SetResourcePausedPayload is the result of pausing or resuming a resource.
+type SetResourcePausedPayload struct {
+	// The updated Kubernetes resource. Null if the update failed.
+	Resource KubernetesResource `json:"resource"`
+}
+
+// SetRevisionDesiredStatePayload is the result of activating or deactivating a
+// package revision.
+type SetRevisionDesiredStatePayload struct {
+	// The updated package revision. Null if the update failed.
+	Resource KubernetesResource `json:"resource"`
+}
+
+// A StoreConfig configures an external secret store, such as Vault, to which
+// Crossplane resources may publish their connection details. Crossplane offers
+// StoreConfigs for use by composite resources and claims, while providers offer
+// their own StoreConfigs for use by managed resources.
+type StoreConfig struct {
+	// An opaque identifier that is unique across all types.
+	ID ReferenceID `json:"id"`
+	// The underlying Kubernetes API version of this resource.
+	APIVersion string `json:"apiVersion"`
+	// The underlying Kubernetes API kind of this resource.
+	Kind string `json:"kind"`
+	// Metadata that is common to all Kubernetes API resources.
+	Metadata *ObjectMeta `json:"metadata"`
+	// The scope of this store config.
+	Scope ResourceScope `json:"scope"`
+	// The desired state of this resource.
+	Spec *StoreConfigSpec `json:"spec"`
+	// The observed state of this resource.
+	Status *StoreConfigStatus `json:"status"`
+	// An unstructured JSON representation of the underlying Kubernetes resource.
+	Unstructured []byte `json:"unstructured"`
+	// Events pertaining to this resource.
+	Events *EventConnection `json:"events"`
+}
+
+func (StoreConfig) IsNode()               {}
+func (StoreConfig) IsKubernetesResource() {}
+
+// A StoreConfigConnection represents a connection to store configs.
+type StoreConfigConnection struct {
+	// Connected nodes.
+	Nodes []StoreConfig `json:"nodes"`
+	// The total number of connected nodes.
+	TotalCount int `json:"totalCount"`
+}
+
+// A StoreConfigReference references a store config.
+type StoreConfigReference struct {
+	// Name of the store config.
+	Name string `json:"name"`
+}
+
+// A StoreConfigSpec represents the desired state of a store config.
+type StoreConfigSpec struct {
+	// The type of secret store this store config configures.
+	Type *SecretStoreType `json:"type"`
+	// The default scope of secrets published to this store, for example a
+	// Kubernetes namespace or a Vault path, used when a resource does not specify
+	// one.
+	DefaultScope string `json:"defaultScope"`
+}
+
+// A StoreConfigStatus represents the observed state of a store config.
+type StoreConfigStatus struct {
+	// The observed condition of this resource.
+	Conditions []Condition `json:"conditions"`
+}
+
+func (StoreConfigStatus) IsConditionedStatus() {}
+
+// A Subject is a user, group, or service account to which a role is granted.
+type Subject struct {
+	// The kind of subject, e.g. 'User', 'Group', or 'ServiceAccount'.
+	Kind string `json:"kind"`
+	// The API group of the subject. Empty for service accounts.
+	APIGroup *string `json:"apiGroup"`
+	// The name of the subject.
+	Name string `json:"name"`
+	// The namespace of the subject. Only set for service accounts.
+	Namespace *string `json:"namespace"`
+}
+
+// An aggregate summary of the health of Crossplane and the resources it manages.
+type Summary struct {
+	// The health of all providers.
+	Providers *PackageHealthSummary `json:"providers"`
+	// The health of all configurations.
+	Configurations *PackageHealthSummary `json:"configurations"`
+	// The health of all composite resources, grouped by API group.
+	CompositeResources []ResourceHealthSummary `json:"compositeResources"`
+	// The health of all managed resources, grouped by API group.
+	ManagedResources []ResourceHealthSummary `json:"managedResources"`
+}
+
 // A TypeReference references a type of Kubernetes resource by API version and
 // kind.
 type TypeReference struct {
@@ -919,6 +1752,15 @@ type TypeReference struct {
 	Kind string `json:"kind"`
 }
 
+// UpdateCompositionPayload is the result of updating a composition.
+type UpdateCompositionPayload struct {
+	// The updated composition, or for a dry run the composition as it would be
+	// persisted. Null if the update failed validation.
+	Composition *Composition `json:"composition"`
+	// The reasons the update failed validation, if any.
+	Errors []ValidationError `json:"errors"`
+}
+
 // UpdateKubernetesResourceInput is the input required to update a Kubernetes
 // resource.
 type UpdateKubernetesResourceInput struct {
@@ -934,6 +1776,210 @@ type UpdateKubernetesResourcePayload struct {
 	Resource KubernetesResource `json:"resource"`
 }
 
+// UpgradePackagePayload is the result of upgrading a package.
+type UpgradePackagePayload struct {
+	// The updated provider, configuration, or function. Null if the update failed.
+	Resource KubernetesResource `json:"resource"`
+}
+
+// ValidateResourceInput is the input required to validate a Kubernetes resource.
+type ValidateResourceInput struct {
+	// The Kubernetes resource to be validated, as raw JSON.
+	Unstructured []byte `json:"unstructured"`
+	// Patches that should be applied to the Kubernetes resource before validation.
+	Patches []Patch `json:"patches"`
+	// The operation to validate. Defaults to CREATE.
+	Operation *ValidationOperation `json:"operation"`
+}
+
+// ValidateResourcePayload is the result of validating a Kubernetes resource.
+type ValidateResourcePayload struct {
+	// Whether the resource passed validation.
+	Valid bool `json:"valid"`
+	// The resource as it would be persisted, including any defaults or mutations
+	// applied by the API server. Null if validation failed.
+	Resource KubernetesResource `json:"resource"`
+	// The reasons the resource failed validation, if any.
+	Errors []ValidationError `json:"errors"`
+}
+
+// A ValidationError is a reason a resource failed validation.
+type ValidationError struct {
+	// What rejected the resource.
+	Source ValidationErrorSource `json:"source"`
+	// A machine-readable reason the resource was rejected, e.g. Invalid or
+	// AlreadyExists.
+	Reason *string `json:"reason"`
+	// A machine-readable type of validation error, e.g. FieldValueRequired. Not all
+	// errors pertain to a specific field.
+	Type *string `json:"type"`
+	// The field of the resource that failed validation, e.g. spec.forProvider.
+	// Not all errors pertain to a specific field.
+	Field *string `json:"field"`
+	// A human-readable description of the validation error.
+	Message string `json:"message"`
+}
+
+// A Viewer is the identity of the caller.
+type Viewer struct {
+	// The caller's username.
+	Username string `json:"username"`
+	// The caller's unique identifier, if any.
+	UID *string `json:"uid"`
+	// The groups the caller is a member of.
+	Groups []string `json:"groups"`
+	// The audiences of the caller's token.
+	Audiences []string `json:"audiences"`
+}
+
+// A WebhookConversion specifies how to call a conversion webhook.
+type WebhookConversion struct {
+	// The URL of the webhook, if it is not running as a service within the
+	// Kubernetes cluster.
+	URL *string `json:"url"`
+	// The service of the webhook, if it is running within the Kubernetes cluster.
+	Service *ServiceReference `json:"service"`
+	// The ConversionReview versions the webhook expects, in order of preference.
+	ConversionReviewVersions []string `json:"conversionReviewVersions"`
+}
+
+// A CacheControlScope indicates who may cache a field.
+type CacheControlScope string
+
+const (
+	// The field may be cached by shared caches, such as a CDN.
+	CacheControlScopePublic CacheControlScope = "PUBLIC"
+	// The field may only be cached by the caller, for example by their browser. Use
+	// this scope for any field that depends on the caller's RBAC permissions.
+	CacheControlScopePrivate CacheControlScope = "PRIVATE"
+)
+
+var AllCacheControlScope = []CacheControlScope{
+	CacheControlScopePublic,
+	CacheControlScopePrivate,
+}
+
+func (e CacheControlScope) IsValid() bool {
+	switch e {
+	case CacheControlScopePublic, CacheControlScopePrivate:
+		return true
+	}
+	return false
+}
+
+func (e CacheControlScope) String() string {
+	return string(e)
+}
+
+func (e *CacheControlScope) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CacheControlScope(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CacheControlScope", str)
+	}
+	return nil
+}
+
+func (e CacheControlScope) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// A CompositeDeletePolicy specifies how the composite resource associated with a
+// claim is deleted when the claim is deleted.
+type CompositeDeletePolicy string
+
+const (
+	// Delete the composite resource in the background.
+	CompositeDeletePolicyBackground CompositeDeletePolicy = "BACKGROUND"
+	// Delete the composite resource in the foreground, waiting for its composed
+	// resources to be deleted before the claim is deleted.
+	CompositeDeletePolicyForeground CompositeDeletePolicy = "FOREGROUND"
+)
+
+var AllCompositeDeletePolicy = []CompositeDeletePolicy{
+	CompositeDeletePolicyBackground,
+	CompositeDeletePolicyForeground,
+}
+
+func (e CompositeDeletePolicy) IsValid() bool {
+	switch e {
+	case CompositeDeletePolicyBackground, CompositeDeletePolicyForeground:
+		return true
+	}
+	return false
+}
+
+func (e CompositeDeletePolicy) String() string {
+	return string(e)
+}
+
+func (e *CompositeDeletePolicy) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CompositeDeletePolicy(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CompositeDeletePolicy", str)
+	}
+	return nil
+}
+
+func (e CompositeDeletePolicy) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// A CompositionUpdatePolicy specifies how a composite resource is updated when
+// a new revision of its composition is available.
+type CompositionUpdatePolicy string
+
+const (
+	// Automatically update to the latest composition revision.
+	CompositionUpdatePolicyAutomatic CompositionUpdatePolicy = "AUTOMATIC"
+	// Only update to a new composition revision when the composition revision
+	// reference is changed.
+	CompositionUpdatePolicyManual CompositionUpdatePolicy = "MANUAL"
+)
+
+var AllCompositionUpdatePolicy = []CompositionUpdatePolicy{
+	CompositionUpdatePolicyAutomatic,
+	CompositionUpdatePolicyManual,
+}
+
+func (e CompositionUpdatePolicy) IsValid() bool {
+	switch e {
+	case CompositionUpdatePolicyAutomatic, CompositionUpdatePolicyManual:
+		return true
+	}
+	return false
+}
+
+func (e CompositionUpdatePolicy) String() string {
+	return string(e)
+}
+
+func (e *CompositionUpdatePolicy) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CompositionUpdatePolicy(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CompositionUpdatePolicy", str)
+	}
+	return nil
+}
+
+func (e CompositionUpdatePolicy) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 // A ConditionStatus represensts the status of a condition.
 type ConditionStatus string
 
@@ -981,6 +2027,51 @@ func (e ConditionStatus) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+// A ConversionStrategy determines how custom resources are converted between
+// versions.
+type ConversionStrategy string
+
+const (
+	// Only the apiVersion of a custom resource is changed when it is converted.
+	ConversionStrategyNone ConversionStrategy = "NONE"
+	// An external webhook is called to convert custom resources.
+	ConversionStrategyWebhook ConversionStrategy = "WEBHOOK"
+)
+
+var AllConversionStrategy = []ConversionStrategy{
+	ConversionStrategyNone,
+	ConversionStrategyWebhook,
+}
+
+func (e ConversionStrategy) IsValid() bool {
+	switch e {
+	case ConversionStrategyNone, ConversionStrategyWebhook:
+		return true
+	}
+	return false
+}
+
+func (e ConversionStrategy) String() string {
+	return string(e)
+}
+
+func (e *ConversionStrategy) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ConversionStrategy(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ConversionStrategy", str)
+	}
+	return nil
+}
+
+func (e ConversionStrategy) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 // A DeletionPolicy specifies what will happen to the underlying external resource
 // when this managed resource is deleted - either "Delete" or "Orphan" the external
 // resource.
@@ -1029,6 +2120,54 @@ func (e DeletionPolicy) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+// A DeletionPropagation determines whether and how the garbage collector deletes
+// the resources owned by a deleted resource.
+type DeletionPropagation string
+
+const (
+	// Delete owned resources before the owner is deleted.
+	DeletionPropagationForeground DeletionPropagation = "FOREGROUND"
+	// Delete the owner immediately, and its owned resources in the background.
+	DeletionPropagationBackground DeletionPropagation = "BACKGROUND"
+	// Leave owned resources in place when the owner is deleted.
+	DeletionPropagationOrphan DeletionPropagation = "ORPHAN"
+)
+
+var AllDeletionPropagation = []DeletionPropagation{
+	DeletionPropagationForeground,
+	DeletionPropagationBackground,
+	DeletionPropagationOrphan,
+}
+
+func (e DeletionPropagation) IsValid() bool {
+	switch e {
+	case DeletionPropagationForeground, DeletionPropagationBackground, DeletionPropagationOrphan:
+		return true
+	}
+	return false
+}
+
+func (e DeletionPropagation) String() string {
+	return string(e)
+}
+
+func (e *DeletionPropagation) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DeletionPropagation(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DeletionPropagation", str)
+	}
+	return nil
+}
+
+func (e DeletionPropagation) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 // An EventType indicates the type of an event.
 type EventType string
 
@@ -1073,6 +2212,64 @@ func (e EventType) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+// A ManagementPolicy specifies which actions Crossplane may take on the external
+// resource of a managed resource.
+type ManagementPolicy string
+
+const (
+	// Crossplane may take all actions on the external resource.
+	ManagementPolicyAll ManagementPolicy = "ALL"
+	// Crossplane may observe the external resource.
+	ManagementPolicyObserve ManagementPolicy = "OBSERVE"
+	// Crossplane may create the external resource.
+	ManagementPolicyCreate ManagementPolicy = "CREATE"
+	// Crossplane may update the external resource.
+	ManagementPolicyUpdate ManagementPolicy = "UPDATE"
+	// Crossplane may delete the external resource.
+	ManagementPolicyDelete ManagementPolicy = "DELETE"
+	// Crossplane may late-initialize the managed resource from the external
+	// resource.
+	ManagementPolicyLateInitialize ManagementPolicy = "LATE_INITIALIZE"
+)
+
+var AllManagementPolicy = []ManagementPolicy{
+	ManagementPolicyAll,
+	ManagementPolicyObserve,
+	ManagementPolicyCreate,
+	ManagementPolicyUpdate,
+	ManagementPolicyDelete,
+	ManagementPolicyLateInitialize,
+}
+
+func (e ManagementPolicy) IsValid() bool {
+	switch e {
+	case ManagementPolicyAll, ManagementPolicyObserve, ManagementPolicyCreate, ManagementPolicyUpdate, ManagementPolicyDelete, ManagementPolicyLateInitialize:
+		return true
+	}
+	return false
+}
+
+func (e ManagementPolicy) String() string {
+	return string(e)
+}
+
+func (e *ManagementPolicy) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ManagementPolicy(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ManagementPolicy", str)
+	}
+	return nil
+}
+
+func (e ManagementPolicy) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 // A PackagePullPolicy represents when to pull a package OCI image from a registry.
 type PackagePullPolicy string
 
@@ -1165,6 +2362,53 @@ func (e PackageRevisionDesiredState) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+// A PackageType is a type of Crossplane package.
+type PackageType string
+
+const (
+	// A provider package.
+	PackageTypeProvider PackageType = "PROVIDER"
+	// A configuration package.
+	PackageTypeConfiguration PackageType = "CONFIGURATION"
+	// A function package.
+	PackageTypeFunction PackageType = "FUNCTION"
+)
+
+var AllPackageType = []PackageType{
+	PackageTypeProvider,
+	PackageTypeConfiguration,
+	PackageTypeFunction,
+}
+
+func (e PackageType) IsValid() bool {
+	switch e {
+	case PackageTypeProvider, PackageTypeConfiguration, PackageTypeFunction:
+		return true
+	}
+	return false
+}
+
+func (e PackageType) String() string {
+	return string(e)
+}
+
+func (e *PackageType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PackageType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PackageType", str)
+	}
+	return nil
+}
+
+func (e PackageType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 // ResourceScope defines the scopes available to custom resources.
 type ResourceScope string
 
@@ -1256,3 +2500,141 @@ func (e *RevisionActivationPolicy) UnmarshalGQL(v interface{}) error {
 func (e RevisionActivationPolicy) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
+
+// A SecretStoreType is a type of external secret store.
+type SecretStoreType string
+
+const (
+	// A Kubernetes secret store, i.e. Kubernetes Secrets.
+	SecretStoreTypeKubernetes SecretStoreType = "KUBERNETES"
+	// A HashiCorp Vault secret store.
+	SecretStoreTypeVault SecretStoreType = "VAULT"
+	// A secret store implemented by an External Secret Store plugin.
+	SecretStoreTypePlugin SecretStoreType = "PLUGIN"
+)
+
+var AllSecretStoreType = []SecretStoreType{
+	SecretStoreTypeKubernetes,
+	SecretStoreTypeVault,
+	SecretStoreTypePlugin,
+}
+
+func (e SecretStoreType) IsValid() bool {
+	switch e {
+	case SecretStoreTypeKubernetes, SecretStoreTypeVault, SecretStoreTypePlugin:
+		return true
+	}
+	return false
+}
+
+func (e SecretStoreType) String() string {
+	return string(e)
+}
+
+func (e *SecretStoreType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SecretStoreType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SecretStoreType", str)
+	}
+	return nil
+}
+
+func (e SecretStoreType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// A ValidationErrorSource indicates what rejected a resource.
+type ValidationErrorSource string
+
+const (
+	// The resource was rejected by the API server's schema validation.
+	ValidationErrorSourceSchema ValidationErrorSource = "SCHEMA"
+	// The resource was rejected by an admission webhook.
+	ValidationErrorSourceAdmissionWebhook ValidationErrorSource = "ADMISSION_WEBHOOK"
+	// The resource was rejected for some other reason, e.g. it already exists.
+	ValidationErrorSourceOther ValidationErrorSource = "OTHER"
+)
+
+var AllValidationErrorSource = []ValidationErrorSource{
+	ValidationErrorSourceSchema,
+	ValidationErrorSourceAdmissionWebhook,
+	ValidationErrorSourceOther,
+}
+
+func (e ValidationErrorSource) IsValid() bool {
+	switch e {
+	case ValidationErrorSourceSchema, ValidationErrorSourceAdmissionWebhook, ValidationErrorSourceOther:
+		return true
+	}
+	return false
+}
+
+func (e ValidationErrorSource) String() string {
+	return string(e)
+}
+
+func (e *ValidationErrorSource) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ValidationErrorSource(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ValidationErrorSource", str)
+	}
+	return nil
+}
+
+func (e ValidationErrorSource) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// A ValidationOperation is an operation that may be validated by a dry-run.
+type ValidationOperation string
+
+const (
+	// Validate creating a new resource.
+	ValidationOperationCreate ValidationOperation = "CREATE"
+	// Validate updating an existing resource.
+	ValidationOperationUpdate ValidationOperation = "UPDATE"
+)
+
+var AllValidationOperation = []ValidationOperation{
+	ValidationOperationCreate,
+	ValidationOperationUpdate,
+}
+
+func (e ValidationOperation) IsValid() bool {
+	switch e {
+	case ValidationOperationCreate, ValidationOperationUpdate:
+		return true
+	}
+	return false
+}
+
+func (e ValidationOperation) String() string {
+	return string(e)
+}
+
+func (e *ValidationOperation) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ValidationOperation(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ValidationOperation", str)
+	}
+	return nil
+}
+
+func (e ValidationOperation) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
@@ -26,6 +26,17 @@ import (
 // Reference ID separator.
 const sep = "|"
 
+// Reference ID keys and separators, for IDs in keyed form.
+const (
+	keyAPIVersion = "apiVersion"
+	keyKind       = "kind"
+	keyNamespace  = "namespace"
+	keyName       = "name"
+
+	pairSep  = ","
+	valueSep = "="
+)
+
 // Reference ID encoder.
 var encoder = base64.RawURLEncoding
 
@@ -36,6 +47,8 @@ var (
 	errMalformed  = "malformed id"
 	errParse      = "cannot parse id"
 	errType       = "id must be a string"
+
+	errFmtKey = "unknown id key %q"
 )
 
 // A ReferenceID uniquely represents a Kubernetes resource in GraphQL. It
@@ -49,8 +62,19 @@ type ReferenceID struct {
 	Name       string
 }
 
-// ParseReferenceID parses the supplied ID string.
+// ParseReferenceID parses the supplied ID string. IDs are usually in the opaque
+// form produced by ReferenceID's String method, but may also be supplied in a
+// human-readable form for convenience, either "apiVersion|kind|namespace|name"
+// or "apiVersion=v1,kind=Secret,namespace=default,name=example". The namespace
+// of a cluster scoped resource may be omitted from the keyed form.
 func ParseReferenceID(id string) (ReferenceID, error) {
+	switch {
+	case strings.Contains(id, sep):
+		return parseParts(id)
+	case keyed(id):
+		return parseKeyed(id)
+	}
+
 	s, err := encoder.DecodeString(id)
 	if err != nil {
 		return ReferenceID{}, errors.Wrap(err, errDecode)
@@ -61,7 +85,11 @@ func ParseReferenceID(id string) (ReferenceID, error) {
 		return ReferenceID{}, errors.Wrap(err, errDecompress)
 	}
 
-	parts := strings.Split(string(b), sep)
+	return parseParts(string(b))
+}
+
+func parseParts(id string) (ReferenceID, error) {
+	parts := strings.Split(id, sep)
 	if len(parts) != 4 {
 		return ReferenceID{}, errors.New(errMalformed)
 	}
@@ -76,6 +104,43 @@ func ParseReferenceID(id string) (ReferenceID, error) {
 	return out, nil
 }
 
+// keyed returns true if the supplied ID appears to be in keyed form. Neither
+// of the keyed form's separators can appear in an opaque ID.
+func keyed(id string) bool {
+	for _, k := range []string{keyAPIVersion, keyKind, keyNamespace, keyName} {
+		if strings.HasPrefix(id, k+valueSep) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseKeyed(id string) (ReferenceID, error) {
+	out := ReferenceID{}
+	for _, pair := range strings.Split(id, pairSep) {
+		kv := strings.SplitN(pair, valueSep, 2)
+		if len(kv) != 2 {
+			return ReferenceID{}, errors.New(errMalformed)
+		}
+		switch k, v := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1]); k {
+		case keyAPIVersion:
+			out.APIVersion = v
+		case keyKind:
+			out.Kind = v
+		case keyNamespace:
+			out.Namespace = v
+		case keyName:
+			out.Name = v
+		default:
+			return ReferenceID{}, errors.Errorf(errFmtKey, k)
+		}
+	}
+	if out.APIVersion == "" || out.Kind == "" || out.Name == "" {
+		return ReferenceID{}, errors.New(errMalformed)
+	}
+	return out, nil
+}
+
 // A String representation of a ReferenceID. The idea is to store the data that
 // uniquely identifies a resource in the Kubernetes API (a reference) such that
 // we can extract that data from a given ID string in future. Representing this
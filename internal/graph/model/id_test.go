@@ -96,6 +96,43 @@ func TestParseReferenceID(t *testing.T) {
 				},
 			},
 		},
+		"HumanReadable": {
+			reason: "It should be possible to parse an ID that is not encoded",
+			id:     "example.org/v1|ExampleKind|default|example",
+			want: want{
+				id: ReferenceID{
+					APIVersion: "example.org/v1",
+					Kind:       "ExampleKind",
+					Namespace:  "default",
+					Name:       "example",
+				},
+			},
+		},
+		"Keyed": {
+			reason: "It should be possible to parse an ID in keyed form, in any order, without a namespace",
+			id:     "name=example,apiVersion=example.org/v1,kind=ExampleKind",
+			want: want{
+				id: ReferenceID{
+					APIVersion: "example.org/v1",
+					Kind:       "ExampleKind",
+					Name:       "example",
+				},
+			},
+		},
+		"KeyedUnknownKey": {
+			reason: "Attempting to parse a keyed ID with an unknown key should result in an error",
+			id:     "name=example,apiVersion=example.org/v1,kind=ExampleKind,color=blue",
+			want: want{
+				err: errors.Errorf(errFmtKey, "color"),
+			},
+		},
+		"KeyedMissingKey": {
+			reason: "Attempting to parse a keyed ID without a name should result in an error",
+			id:     "apiVersion=example.org/v1,kind=ExampleKind",
+			want: want{
+				err: errors.New(errMalformed),
+			},
+		},
 		"WrongEncoding": {
 			reason: "Attempting to parse an ID that is not base64 encoded should result in an error",
 			id:     "=",
@@ -24,12 +24,34 @@ import (
 
 // A ManagedResourceSpec specifies the desired state of a managed resource.
 type ManagedResourceSpec struct {
-	ProviderConfigRef *ProviderConfigReference `json:"providerConfigRef"`
-	DeletionPolicy    *DeletionPolicy          `json:"deletionPolicy"`
+	ProviderConfigRef          *ProviderConfigReference    `json:"providerConfigRef"`
+	DeletionPolicy             *DeletionPolicy             `json:"deletionPolicy"`
+	PublishConnectionDetailsTo *PublishConnectionDetailsTo `json:"publishConnectionDetailsTo"`
+	ManagementPolicies         []ManagementPolicy          `json:"managementPolicies"`
+	DeletesExternalResource    bool                        `json:"deletesExternalResource"`
 
 	WritesConnectionSecretToReference *xpv1.SecretReference
+
+	// Parameters of the external resource, i.e. spec.forProvider.
+	Parameters map[string]interface{}
+}
+
+// A ManagedResourceStatus represents the observed state of a managed resource.
+type ManagedResourceStatus struct {
+	Conditions []Condition `json:"conditions"`
+
+	// The number of seconds between this resource's creation and it most
+	// recently becoming ready, if it is ready.
+	TimeToReady *int `json:"timeToReady"`
+
+	// Observed state of the external resource, i.e. status.atProvider.
+	Observation map[string]interface{}
 }
 
+// IsConditionedStatus indicates that ManagedResourceStatus satisfies the
+// ConditionedStatus GraphQL interface.
+func (ManagedResourceStatus) IsConditionedStatus() {}
+
 // GetDeletionPolicy from the supplied Crossplane policy.
 func GetDeletionPolicy(p xpv1.DeletionPolicy) *DeletionPolicy {
 	switch p {
@@ -44,6 +66,51 @@ func GetDeletionPolicy(p xpv1.DeletionPolicy) *DeletionPolicy {
 	}
 }
 
+// GetManagementPolicies from the supplied Crossplane policies. Unknown policies
+// are omitted.
+func GetManagementPolicies(in []string) []ManagementPolicy {
+	if in == nil {
+		return nil
+	}
+
+	out := make([]ManagementPolicy, 0, len(in))
+	for _, p := range in {
+		switch p {
+		case "*":
+			out = append(out, ManagementPolicyAll)
+		case "Observe":
+			out = append(out, ManagementPolicyObserve)
+		case "Create":
+			out = append(out, ManagementPolicyCreate)
+		case "Update":
+			out = append(out, ManagementPolicyUpdate)
+		case "Delete":
+			out = append(out, ManagementPolicyDelete)
+		case "LateInitialize":
+			out = append(out, ManagementPolicyLateInitialize)
+		}
+	}
+	return out
+}
+
+// deletesExternalResource returns true if a managed resource with the supplied
+// policies will delete its external resource when it is deleted. Managed
+// resources that don't support management policies are fully managed.
+func deletesExternalResource(dp *DeletionPolicy, mp []ManagementPolicy) bool {
+	if dp != nil && *dp == DeletionPolicyOrphan {
+		return false
+	}
+	if len(mp) == 0 {
+		return true
+	}
+	for _, p := range mp {
+		if p == ManagementPolicyAll || p == ManagementPolicyDelete {
+			return true
+		}
+	}
+	return false
+}
+
 // GetProviderConfigReference from the supplied Crossplane reference.
 func GetProviderConfigReference(in *xpv1.Reference) *ProviderConfigReference {
 	if in == nil {
@@ -55,15 +122,22 @@ func GetProviderConfigReference(in *xpv1.Reference) *ProviderConfigReference {
 // GetManagedResourceStatus from the supplied Crossplane resource.
 func GetManagedResourceStatus(in *unstructured.Managed) *ManagedResourceStatus {
 	c := in.GetConditions()
-	if len(c) == 0 {
+	o := in.GetAtProvider()
+	if len(c) == 0 && o == nil {
 		return nil
 	}
-	return &ManagedResourceStatus{Conditions: GetConditions(c)}
+	return &ManagedResourceStatus{
+		Conditions:  GetConditions(c),
+		TimeToReady: GetTimeToReady(in.GetCreationTimestamp(), c),
+		Observation: o,
+	}
 }
 
 // GetManagedResource from the supplied Crossplane resource.
 func GetManagedResource(u *kunstructured.Unstructured) ManagedResource {
 	mg := &unstructured.Managed{Unstructured: *u}
+	dp := GetDeletionPolicy(mg.GetDeletionPolicy())
+	mp := GetManagementPolicies(mg.GetManagementPolicies())
 	return ManagedResource{
 		ID: ReferenceID{
 			APIVersion: mg.GetAPIVersion(),
@@ -77,7 +151,11 @@ func GetManagedResource(u *kunstructured.Unstructured) ManagedResource {
 		Spec: &ManagedResourceSpec{
 			WritesConnectionSecretToReference: mg.GetWriteConnectionSecretToReference(),
 			ProviderConfigRef:                 GetProviderConfigReference(mg.GetProviderConfigReference()),
-			DeletionPolicy:                    GetDeletionPolicy(mg.GetDeletionPolicy()),
+			DeletionPolicy:                    dp,
+			ManagementPolicies:                mp,
+			DeletesExternalResource:           deletesExternalResource(dp, mp),
+			PublishConnectionDetailsTo:        GetPublishConnectionDetailsTo(mg.GetPublishConnectionDetailsTo(), unstructured.ProviderStoreConfigAPIVersions(mg.GroupVersionKind().Group)...),
+			Parameters:                        mg.GetForProvider(),
 		},
 		Status:       GetManagedResourceStatus(mg),
 		Unstructured: unstruct(mg),
@@ -22,6 +22,7 @@ import (
 	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 
 	"github.com/upbound/xgql/internal/unstructured"
 )
@@ -47,6 +48,13 @@ func TestGetManagedResource(t *testing.T) {
 				mr.SetWriteConnectionSecretToReference(&xpv1.SecretReference{Name: "coolsecret"})
 				mr.SetConditions(xpv1.Condition{})
 				mr.SetDeletionPolicy(xpv1.DeletionOrphan)
+				_ = fieldpath.Pave(mr.Object).SetValue("spec.forProvider.region", "us-west-2")
+				_ = fieldpath.Pave(mr.Object).SetValue("status.atProvider.arn", "cool")
+				_ = fieldpath.Pave(mr.Object).SetValue("spec.managementPolicies", []string{"Observe", "Delete", "Wat"})
+				_ = fieldpath.Pave(mr.Object).SetValue("spec.publishConnectionDetailsTo", map[string]interface{}{
+					"name":      "coolsecret",
+					"configRef": map[string]interface{}{"name": "vault"},
+				})
 
 				return mr.GetUnstructured()
 			}(),
@@ -64,10 +72,19 @@ func TestGetManagedResource(t *testing.T) {
 				Spec: &ManagedResourceSpec{
 					ProviderConfigRef:                 &ProviderConfigReference{Name: "coolprov"},
 					DeletionPolicy:                    &orphan,
+					ManagementPolicies:                []ManagementPolicy{ManagementPolicyObserve, ManagementPolicyDelete},
+					DeletesExternalResource:           false,
 					WritesConnectionSecretToReference: &xpv1.SecretReference{Name: "coolsecret"},
+					Parameters:                        map[string]interface{}{"region": "us-west-2"},
+					PublishConnectionDetailsTo: &PublishConnectionDetailsTo{
+						Name:                   "coolsecret",
+						ConfigRef:              &StoreConfigReference{Name: "vault"},
+						StoreConfigAPIVersions: []string{"example.org/v1alpha1"},
+					},
 				},
 				Status: &ManagedResourceStatus{
-					Conditions: []Condition{{}},
+					Conditions:  []Condition{{}},
+					Observation: map[string]interface{}{"arn": "cool"},
 				},
 			},
 		},
@@ -80,7 +97,8 @@ func TestGetManagedResource(t *testing.T) {
 					// This is technically optional, but it's basically always
 					// set to 'delete' using CRD defaulting. We also default it
 					// to 'delete' in unstructured.Managed.
-					DeletionPolicy: &delete,
+					DeletionPolicy:          &delete,
+					DeletesExternalResource: true,
 				},
 			},
 		},
@@ -96,3 +114,48 @@ func TestGetManagedResource(t *testing.T) {
 		})
 	}
 }
+
+func TestDeletesExternalResource(t *testing.T) {
+	delete := DeletionPolicyDelete
+	orphan := DeletionPolicyOrphan
+
+	cases := map[string]struct {
+		reason string
+		dp     *DeletionPolicy
+		mp     []ManagementPolicy
+		want   bool
+	}{
+		"Orphan": {
+			reason: "A managed resource that orphans its external resource should not delete it.",
+			dp:     &orphan,
+			mp:     []ManagementPolicy{ManagementPolicyAll},
+			want:   false,
+		},
+		"NoManagementPolicies": {
+			reason: "A managed resource without management policies should delete its external resource.",
+			dp:     &delete,
+			want:   true,
+		},
+		"ObserveOnly": {
+			reason: "An observe only managed resource should not delete its external resource.",
+			dp:     &delete,
+			mp:     []ManagementPolicy{ManagementPolicyObserve},
+			want:   false,
+		},
+		"AllPolicies": {
+			reason: "A fully managed resource should delete its external resource.",
+			dp:     &delete,
+			mp:     []ManagementPolicy{ManagementPolicyAll},
+			want:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := deletesExternalResource(tc.dp, tc.mp)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ndeletesExternalResource(...): -want, +got\n:%s", tc.reason, diff)
+			}
+		})
+	}
+}
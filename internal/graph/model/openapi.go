@@ -0,0 +1,247 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"sort"
+
+	kextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+)
+
+const (
+	// DefaultOpenAPIDepth is the default depth to which the descendants of an
+	// OpenAPI schema are returned.
+	DefaultOpenAPIDepth = 3
+
+	// MaxOpenAPIDepth is the maximum depth to which the descendants of an
+	// OpenAPI schema are returned.
+	MaxOpenAPIDepth = 10
+)
+
+// An OpenAPISchema is a structured OpenAPI v3 schema. Its fields are derived
+// from the underlying schema only when they are resolved, so that clients may
+// traverse only the parts of a large schema that they need.
+type OpenAPISchema struct {
+	props *kextv1.JSONSchemaProps
+}
+
+// GetOpenAPISchema from the supplied Kubernetes schema. Returns nil if the
+// supplied schema is nil.
+func GetOpenAPISchema(in *kextv1.JSONSchemaProps) *OpenAPISchema {
+	if in == nil {
+		return nil
+	}
+	return &OpenAPISchema{props: in}
+}
+
+// An OpenAPIProperty is a named property of an OpenAPI object schema.
+type OpenAPIProperty struct {
+	// The name of the property.
+	Name string `json:"name"`
+
+	// The path to the property from the schema it was resolved from, e.g.
+	// spec.forProvider.region.
+	Path string `json:"path"`
+
+	// Whether the property is required by its parent.
+	Required bool `json:"required"`
+
+	// The schema of the property.
+	Schema *OpenAPISchema `json:"schema"`
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func optionalInt(i *int64) *int {
+	if i == nil {
+		return nil
+	}
+	out := int(*i)
+	return &out
+}
+
+// Type of the schema, e.g. object, array, string, integer, number, or boolean.
+func (s *OpenAPISchema) Type() *string { return optional(s.props.Type) }
+
+// Format of the schema, e.g. date-time or int64.
+func (s *OpenAPISchema) Format() *string { return optional(s.props.Format) }
+
+// Description of the schema.
+func (s *OpenAPISchema) Description() *string { return optional(s.props.Description) }
+
+// Pattern that string values must match.
+func (s *OpenAPISchema) Pattern() *string { return optional(s.props.Pattern) }
+
+// Required properties of an object schema.
+func (s *OpenAPISchema) Required() []string { return s.props.Required }
+
+// Nullable indicates whether null is a valid value.
+func (s *OpenAPISchema) Nullable() bool { return s.props.Nullable }
+
+// Minimum value of a number or integer schema.
+func (s *OpenAPISchema) Minimum() *float64 { return s.props.Minimum }
+
+// Maximum value of a number or integer schema.
+func (s *OpenAPISchema) Maximum() *float64 { return s.props.Maximum }
+
+// MinLength of a string schema.
+func (s *OpenAPISchema) MinLength() *int { return optionalInt(s.props.MinLength) }
+
+// MaxLength of a string schema.
+func (s *OpenAPISchema) MaxLength() *int { return optionalInt(s.props.MaxLength) }
+
+// MinItems of an array schema.
+func (s *OpenAPISchema) MinItems() *int { return optionalInt(s.props.MinItems) }
+
+// MaxItems of an array schema.
+func (s *OpenAPISchema) MaxItems() *int { return optionalInt(s.props.MaxItems) }
+
+// PreserveUnknownFields indicates whether fields not specified by the schema
+// are preserved, i.e. x-kubernetes-preserve-unknown-fields.
+func (s *OpenAPISchema) PreserveUnknownFields() bool {
+	return s.props.XPreserveUnknownFields != nil && *s.props.XPreserveUnknownFields
+}
+
+// Enum values of the schema, as raw JSON.
+func (s *OpenAPISchema) Enum() [][]byte {
+	if s.props.Enum == nil {
+		return nil
+	}
+	out := make([][]byte, len(s.props.Enum))
+	for i := range s.props.Enum {
+		out[i] = s.props.Enum[i].Raw
+	}
+	return out
+}
+
+// Default value of the schema, as raw JSON.
+func (s *OpenAPISchema) Default() []byte {
+	if s.props.Default == nil {
+		return nil
+	}
+	return s.props.Default.Raw
+}
+
+// Items is the schema of the items of an array schema.
+func (s *OpenAPISchema) Items() *OpenAPISchema {
+	if s.props.Items == nil {
+		return nil
+	}
+	return GetOpenAPISchema(s.props.Items.Schema)
+}
+
+// AdditionalProperties is the schema of the values of a map schema.
+func (s *OpenAPISchema) AdditionalProperties() *OpenAPISchema {
+	if s.props.AdditionalProperties == nil {
+		return nil
+	}
+	return GetOpenAPISchema(s.props.AdditionalProperties.Schema)
+}
+
+// Properties of an object schema, sorted by name.
+func (s *OpenAPISchema) Properties() []OpenAPIProperty {
+	return s.properties("")
+}
+
+func (s *OpenAPISchema) properties(prefix string) []OpenAPIProperty {
+	if len(s.props.Properties) == 0 {
+		return nil
+	}
+
+	required := make(map[string]bool, len(s.props.Required))
+	for _, r := range s.props.Required {
+		required[r] = true
+	}
+
+	out := make([]OpenAPIProperty, 0, len(s.props.Properties))
+	for name := range s.props.Properties {
+		p := s.props.Properties[name] // So we don't take the address of the range variable.
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		out = append(out, OpenAPIProperty{
+			Name:     name,
+			Path:     path,
+			Required: required[name],
+			Schema:   GetOpenAPISchema(&p),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Descendants of an object schema, depth first. Only the properties of nested
+// objects are traversed; array items and map values are not.
+func (s *OpenAPISchema) Descendants(maxDepth *int) []OpenAPIProperty {
+	d := DefaultOpenAPIDepth
+	if maxDepth != nil {
+		d = *maxDepth
+	}
+	if d > MaxOpenAPIDepth {
+		d = MaxOpenAPIDepth
+	}
+	return s.descendants("", d)
+}
+
+func (s *OpenAPISchema) descendants(prefix string, depth int) []OpenAPIProperty {
+	if depth < 1 {
+		return nil
+	}
+	out := make([]OpenAPIProperty, 0)
+	for _, p := range s.properties(prefix) {
+		out = append(out, p)
+		out = append(out, p.Schema.descendants(p.Path, depth-1)...)
+	}
+	return out
+}
+
+// Property returns the schema at the supplied field path, e.g.
+// spec.forProvider.tags[0].key, or nil if no such property exists. Array
+// indices traverse into the schema of the array's items.
+func (s *OpenAPISchema) Property(path string) *OpenAPISchema {
+	segments, err := fieldpath.Parse(path)
+	if err != nil {
+		return nil
+	}
+
+	cur := s
+	for _, seg := range segments {
+		switch seg.Type {
+		case fieldpath.SegmentField:
+			if p, ok := cur.props.Properties[seg.Field]; ok {
+				cur = GetOpenAPISchema(&p)
+				continue
+			}
+			if ap := cur.AdditionalProperties(); ap != nil {
+				cur = ap
+				continue
+			}
+			return nil
+		case fieldpath.SegmentIndex:
+			if cur = cur.Items(); cur == nil {
+				return nil
+			}
+		}
+	}
+	return cur
+}
@@ -0,0 +1,140 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	kextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/pointer"
+)
+
+var testSchema = &kextv1.JSONSchemaProps{
+	Type: "object",
+	Properties: map[string]kextv1.JSONSchemaProps{
+		"spec": {
+			Type:     "object",
+			Required: []string{"forProvider"},
+			Properties: map[string]kextv1.JSONSchemaProps{
+				"forProvider": {
+					Type: "object",
+					Properties: map[string]kextv1.JSONSchemaProps{
+						"region": {Type: "string", Description: "The region."},
+						"tags": {
+							Type: "array",
+							Items: &kextv1.JSONSchemaPropsOrArray{Schema: &kextv1.JSONSchemaProps{
+								Type: "object",
+								Properties: map[string]kextv1.JSONSchemaProps{
+									"key": {Type: "string"},
+								},
+							}},
+						},
+					},
+				},
+				"labels": {
+					Type: "object",
+					AdditionalProperties: &kextv1.JSONSchemaPropsOrBool{Schema: &kextv1.JSONSchemaProps{
+						Type: "string",
+					}},
+				},
+			},
+		},
+	},
+}
+
+func TestOpenAPISchemaDescendants(t *testing.T) {
+	zero, two := 0, 2
+
+	cases := map[string]struct {
+		reason   string
+		maxDepth *int
+		want     []string
+	}{
+		"Default": {
+			reason: "We should return descendants to the default depth.",
+			want:   []string{"spec", "spec.forProvider", "spec.forProvider.region", "spec.forProvider.tags", "spec.labels"},
+		},
+		"Shallow": {
+			reason:   "We should return descendants to the supplied depth.",
+			maxDepth: &two,
+			want:     []string{"spec", "spec.forProvider", "spec.labels"},
+		},
+		"Zero": {
+			reason:   "We should return no descendants if the depth is zero.",
+			maxDepth: &zero,
+			want:     []string{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := make([]string, 0)
+			for _, p := range GetOpenAPISchema(testSchema).Descendants(tc.maxDepth) {
+				got = append(got, p.Path)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nDescendants(...): -want, +got\n:%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestOpenAPISchemaProperty(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		path   string
+		want   *string
+	}{
+		"Nested": {
+			reason: "We should traverse object properties.",
+			path:   "spec.forProvider.region",
+			want:   pointer.StringPtr("The region."),
+		},
+		"ArrayItems": {
+			reason: "We should traverse into array items.",
+			path:   "spec.forProvider.tags[0].key",
+			want:   pointer.StringPtr("string"),
+		},
+		"MapValues": {
+			reason: "We should traverse into map values.",
+			path:   "spec.labels.cool",
+			want:   pointer.StringPtr("string"),
+		},
+		"NotFound": {
+			reason: "We should return nil if the property does not exist.",
+			path:   "spec.nope",
+		},
+		"InvalidPath": {
+			reason: "We should return nil if the path is invalid.",
+			path:   "spec..nope",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got *string
+			if s := GetOpenAPISchema(testSchema).Property(tc.path); s != nil {
+				got = s.Type()
+				if d := s.Description(); d != nil {
+					got = d
+				}
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nProperty(...): -want, +got\n:%s", tc.reason, diff)
+			}
+		})
+	}
+}
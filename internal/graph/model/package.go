@@ -15,15 +15,53 @@
 package model
 
 import (
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
 
 	"github.com/google/go-cmp/cmp"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+
+	"github.com/upbound/xgql/internal/unstructured"
 )
 
+// A ProviderSpec represents the desired state of a provider.
+type ProviderSpec struct {
+	Package                     string                    `json:"package"`
+	RevisionActivationPolicy    *RevisionActivationPolicy `json:"revisionActivationPolicy"`
+	RevisionHistoryLimit        *int                      `json:"revisionHistoryLimit"`
+	PackagePullPolicy           *PackagePullPolicy        `json:"packagePullPolicy"`
+	PackagePullSecrets          []string                  `json:"packagePullSecrets"`
+	PullSecrets                 []PackagePullSecret       `json:"pullSecrets"`
+	IgnoreCrossplaneConstraints *bool                     `json:"ignoreCrossplaneConstraints"`
+	SkipDependencyResolution    *bool                     `json:"skipDependencyResolution"`
+
+	// ProviderName is the name of the provider this spec belongs to.
+	ProviderName string
+}
+
+// A ConfigurationSpec represents the desired state of a configuration.
+type ConfigurationSpec struct {
+	Package                     string                    `json:"package"`
+	RevisionActivationPolicy    *RevisionActivationPolicy `json:"revisionActivationPolicy"`
+	RevisionHistoryLimit        *int                      `json:"revisionHistoryLimit"`
+	PackagePullPolicy           *PackagePullPolicy        `json:"packagePullPolicy"`
+	PackagePullSecrets          []string                  `json:"packagePullSecrets"`
+	PullSecrets                 []PackagePullSecret       `json:"pullSecrets"`
+	IgnoreCrossplaneConstraints *bool                     `json:"ignoreCrossplaneConstraints"`
+	SkipDependencyResolution    *bool                     `json:"skipDependencyResolution"`
+
+	// ConfigurationName is the name of the configuration this spec belongs
+	// to.
+	ConfigurationName string
+}
+
 // A ProviderRevisionStatus reflects the observed state of a ProviderRevision.
 type ProviderRevisionStatus struct {
 	Conditions            []Condition  `json:"conditions"`
@@ -31,6 +69,7 @@ type ProviderRevisionStatus struct {
 	InstalledDependencies *int         `json:"installedDependencies"`
 	InvalidDependencies   *int         `json:"invalidDependencies"`
 	PermissionRequests    []PolicyRule `json:"permissionRequests"`
+	PullError             *string      `json:"pullError"`
 
 	ObjectRefs []xpv1.TypedReference
 }
@@ -46,6 +85,7 @@ type ConfigurationRevisionStatus struct {
 	InstalledDependencies *int         `json:"installedDependencies"`
 	InvalidDependencies   *int         `json:"invalidDependencies"`
 	PermissionRequests    []PolicyRule `json:"permissionRequests"`
+	PullError             *string      `json:"pullError"`
 
 	ObjectRefs []xpv1.TypedReference
 }
@@ -89,6 +129,65 @@ func GetPackagePullPolicy(in *corev1.PullPolicy) *PackagePullPolicy {
 	return nil
 }
 
+// GetPackageType from the supplied package manager type, e.g. Provider.
+func GetPackageType(in string) *PackageType {
+	switch in {
+	case "Provider":
+		out := PackageTypeProvider
+		return &out
+	case "Configuration":
+		out := PackageTypeConfiguration
+		return &out
+	case "Function":
+		out := PackageTypeFunction
+		return &out
+	}
+	return nil
+}
+
+// Case insensitive fragments of condition messages that indicate the package
+// manager could not pull a package, e.g. due to a registry authentication
+// problem.
+var pullErrors = []string{
+	"failed to fetch package",
+	"cannot fetch package",
+	"unauthorized",
+	"access denied",
+	"requested access to the resource is denied",
+	"manifest unknown",
+	"manifest_unknown",
+}
+
+// GetPullError returns the message of the first of the supplied conditions that
+// is not true and indicates that a package could not be pulled, if any.
+func GetPullError(in []xpv1.Condition) *string {
+	for _, c := range in {
+		if c.Status == corev1.ConditionTrue {
+			continue
+		}
+		msg := strings.ToLower(c.Message)
+		for _, e := range pullErrors {
+			if strings.Contains(msg, e) {
+				out := c.Message
+				return &out
+			}
+		}
+	}
+	return nil
+}
+
+// GetPackagePullSecrets from the supplied Kubernetes references.
+func GetPackagePullSecrets(in []corev1.LocalObjectReference) []string {
+	if in == nil {
+		return nil
+	}
+	out := make([]string, len(in))
+	for i := range in {
+		out[i] = in[i].Name
+	}
+	return out
+}
+
 // GetPolicyRules from the supplied Kubernetes policy rules.
 func GetPolicyRules(in []rbacv1.PolicyRule) []PolicyRule {
 	if in == nil {
@@ -140,8 +239,10 @@ func GetProvider(p *pkgv1.Provider) Provider {
 			RevisionActivationPolicy:    GetRevisionActivationPolicy(p.Spec.RevisionActivationPolicy),
 			RevisionHistoryLimit:        getIntPtr(p.Spec.RevisionHistoryLimit),
 			PackagePullPolicy:           GetPackagePullPolicy(p.Spec.PackagePullPolicy),
+			PackagePullSecrets:          GetPackagePullSecrets(p.Spec.PackagePullSecrets),
 			IgnoreCrossplaneConstraints: p.Spec.IgnoreCrossplaneConstraints,
 			SkipDependencyResolution:    p.Spec.SkipDependencyResolution,
+			ProviderName:                p.GetName(),
 		},
 		Status:       GetProviderStatus(p.Status),
 		Unstructured: unstruct(p),
@@ -168,6 +269,7 @@ func GetProviderRevisionStatus(in pkgv1.PackageRevisionStatus) *ProviderRevision
 		InstalledDependencies: getIntPtr(&in.InstalledDependencies),
 		InvalidDependencies:   getIntPtr(&in.InvalidDependencies),
 		PermissionRequests:    GetPolicyRules(in.PermissionRequests),
+		PullError:             GetPullError(in.Conditions),
 	}
 	if cmp.Equal(out, &ProviderRevisionStatus{}) {
 		return nil
@@ -232,8 +334,10 @@ func GetConfiguration(c *pkgv1.Configuration) Configuration {
 			RevisionActivationPolicy:    GetRevisionActivationPolicy(c.Spec.RevisionActivationPolicy),
 			RevisionHistoryLimit:        getIntPtr(c.Spec.RevisionHistoryLimit),
 			PackagePullPolicy:           GetPackagePullPolicy(c.Spec.PackagePullPolicy),
+			PackagePullSecrets:          GetPackagePullSecrets(c.Spec.PackagePullSecrets),
 			IgnoreCrossplaneConstraints: c.Spec.IgnoreCrossplaneConstraints,
 			SkipDependencyResolution:    c.Spec.SkipDependencyResolution,
+			ConfigurationName:           c.GetName(),
 		},
 		Status:       GetConfigurationStatus(c.Status),
 		Unstructured: unstruct(c),
@@ -249,6 +353,7 @@ func GetConfigurationRevisionStatus(in pkgv1.PackageRevisionStatus) *Configurati
 		InstalledDependencies: getIntPtr(&in.InstalledDependencies),
 		InvalidDependencies:   getIntPtr(&in.InvalidDependencies),
 		PermissionRequests:    GetPolicyRules(in.PermissionRequests),
+		PullError:             GetPullError(in.Conditions),
 	}
 	if cmp.Equal(out, &ConfigurationRevisionStatus{}) {
 		return nil
@@ -280,3 +385,145 @@ func GetConfigurationRevision(cr *pkgv1.ConfigurationRevision) ConfigurationRevi
 		Unstructured: unstruct(cr),
 	}
 }
+
+// GetLockPackages from the supplied packages recorded in the package manager's
+// lock.
+func GetLockPackages(in []unstructured.LockPackage) []LockPackage {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]LockPackage, len(in))
+	for i, p := range in {
+		out[i] = LockPackage{
+			Name:    p.Name,
+			Type:    GetPackageType(p.Type),
+			Source:  p.Source,
+			Version: p.Version,
+		}
+		for _, d := range p.Dependencies {
+			ld := LockDependency{Package: d.Package, Type: GetPackageType(d.Type)}
+			if d.Constraints != "" {
+				ld.Constraints = pointer.StringPtr(d.Constraints)
+			}
+			out[i].Dependencies = append(out[i].Dependencies, ld)
+		}
+	}
+	return out
+}
+
+// GetLock from the supplied Crossplane lock. Locks are read as unstructured
+// resources, so any API version of lock may be supplied.
+func GetLock(u *kunstructured.Unstructured) Lock {
+	l := &unstructured.Lock{Unstructured: *u}
+	return Lock{
+		ID: ReferenceID{
+			APIVersion: l.GetAPIVersion(),
+			Kind:       l.GetKind(),
+			Name:       l.GetName(),
+		},
+
+		APIVersion:   l.GetAPIVersion(),
+		Kind:         l.GetKind(),
+		Metadata:     GetObjectMeta(l),
+		Packages:     GetLockPackages(l.GetPackages()),
+		Unstructured: unstruct(l),
+	}
+}
+
+// A RevisionObject is an object installed by a package revision.
+type RevisionObject struct {
+	// Reference to the object, as recorded by the revision.
+	Reference xpv1.TypedReference
+
+	// Object is the referenced object, or nil if it could not be read.
+	Object *kunstructured.Unstructured
+}
+
+// objectKey identifies an object installed by a package revision regardless of
+// the API version at which it was installed.
+type objectKey struct {
+	group string
+	kind  string
+	name  string
+}
+
+func keyOf(ref xpv1.TypedReference) objectKey {
+	return objectKey{
+		group: schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind).Group,
+		kind:  ref.Kind,
+		name:  ref.Name,
+	}
+}
+
+// GetRevisionDiff from the supplied objects of two package revisions. Objects
+// are compared by reference, and by content if both could be read.
+func GetRevisionDiff(a, b []RevisionObject) *RevisionDiff {
+	out := &RevisionDiff{}
+
+	inB := make(map[objectKey]RevisionObject, len(b))
+	for _, o := range b {
+		inB[keyOf(o.Reference)] = o
+	}
+
+	inA := make(map[objectKey]bool, len(a))
+	for _, o := range a {
+		from := o.Reference
+		k := keyOf(from)
+		inA[k] = true
+
+		t, ok := inB[k]
+		if !ok {
+			out.Removed = append(out.Removed, RevisionObjectDiff{
+				Kind:           from.Kind,
+				Name:           from.Name,
+				FromAPIVersion: pointer.StringPtr(from.APIVersion),
+			})
+			continue
+		}
+		to := t.Reference
+		if from.APIVersion == to.APIVersion && from.UID == to.UID && sameContent(o.Object, t.Object) {
+			continue
+		}
+		out.Changed = append(out.Changed, RevisionObjectDiff{
+			Kind:           to.Kind,
+			Name:           to.Name,
+			FromAPIVersion: pointer.StringPtr(from.APIVersion),
+			ToAPIVersion:   pointer.StringPtr(to.APIVersion),
+		})
+	}
+
+	for _, o := range b {
+		to := o.Reference
+		if inA[keyOf(to)] {
+			continue
+		}
+		out.Added = append(out.Added, RevisionObjectDiff{
+			Kind:         to.Kind,
+			Name:         to.Name,
+			ToAPIVersion: pointer.StringPtr(to.APIVersion),
+		})
+	}
+
+	return out
+}
+
+// sameContent returns false if both supplied objects exist and differ in
+// anything but their API version, kind, metadata, and status.
+func sameContent(a, b *kunstructured.Unstructured) bool {
+	if a == nil || b == nil {
+		return true
+	}
+	return cmp.Equal(content(a), content(b))
+}
+
+func content(u *kunstructured.Unstructured) map[string]interface{} {
+	out := make(map[string]interface{}, len(u.Object))
+	for k, v := range u.Object {
+		switch k {
+		case "apiVersion", "kind", "metadata", "status":
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
@@ -22,6 +22,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/pointer"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -58,6 +60,7 @@ func TestGetProvider(t *testing.T) {
 						RevisionActivationPolicy:    &rap,
 						RevisionHistoryLimit:        &lim,
 						PackagePullPolicy:           &ppp,
+						PackagePullSecrets:          []corev1.LocalObjectReference{{Name: "secret"}},
 						IgnoreCrossplaneConstraints: pointer.BoolPtr(true),
 						SkipDependencyResolution:    pointer.BoolPtr(true),
 					},
@@ -88,8 +91,10 @@ func TestGetProvider(t *testing.T) {
 					RevisionActivationPolicy:    &mrap,
 					RevisionHistoryLimit:        &mlim,
 					PackagePullPolicy:           &mppp,
+					PackagePullSecrets:          []string{"secret"},
 					IgnoreCrossplaneConstraints: pointer.BoolPtr(true),
 					SkipDependencyResolution:    pointer.BoolPtr(true),
+					ProviderName:                "cool",
 				},
 				Status: &ProviderStatus{
 					Conditions:        []Condition{{}},
@@ -250,6 +255,7 @@ func TestGetConfiguration(t *testing.T) {
 						RevisionActivationPolicy:    &rap,
 						RevisionHistoryLimit:        &lim,
 						PackagePullPolicy:           &ppp,
+						PackagePullSecrets:          []corev1.LocalObjectReference{{Name: "secret"}},
 						IgnoreCrossplaneConstraints: pointer.BoolPtr(true),
 						SkipDependencyResolution:    pointer.BoolPtr(true),
 					},
@@ -280,8 +286,10 @@ func TestGetConfiguration(t *testing.T) {
 					RevisionActivationPolicy:    &mrap,
 					RevisionHistoryLimit:        &mlim,
 					PackagePullPolicy:           &mppp,
+					PackagePullSecrets:          []string{"secret"},
 					IgnoreCrossplaneConstraints: pointer.BoolPtr(true),
 					SkipDependencyResolution:    pointer.BoolPtr(true),
+					ConfigurationName:           "cool",
 				},
 				Status: &ConfigurationStatus{
 					Conditions:        []Condition{{}},
@@ -411,3 +419,236 @@ func TestGetConfigurationRevision(t *testing.T) {
 		})
 	}
 }
+
+func TestGetLock(t *testing.T) {
+	cfg := PackageTypeConfiguration
+	prv := PackageTypeProvider
+
+	cases := map[string]struct {
+		reason string
+		u      *kunstructured.Unstructured
+		want   Lock
+	}{
+		"Full": {
+			reason: "All supported fields should be converted to our model",
+			u: &kunstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "pkg.crossplane.io/v1beta1",
+				"kind":       "Lock",
+				"metadata": map[string]interface{}{
+					"name": "lock",
+				},
+				"packages": []interface{}{
+					map[string]interface{}{
+						"name":    "config-abc",
+						"type":    "Configuration",
+						"source":  "example.org/config",
+						"version": "v1.0.0",
+						"dependencies": []interface{}{
+							map[string]interface{}{
+								"package":     "example.org/provider",
+								"type":        "Provider",
+								"constraints": ">=v1.0.0",
+							},
+							map[string]interface{}{
+								"package": "example.org/other",
+							},
+						},
+					},
+				},
+			}},
+			want: Lock{
+				ID: ReferenceID{
+					APIVersion: "pkg.crossplane.io/v1beta1",
+					Kind:       "Lock",
+					Name:       "lock",
+				},
+				APIVersion: "pkg.crossplane.io/v1beta1",
+				Kind:       "Lock",
+				Metadata: &ObjectMeta{
+					Name: "lock",
+				},
+				Packages: []LockPackage{{
+					Name:    "config-abc",
+					Type:    &cfg,
+					Source:  "example.org/config",
+					Version: "v1.0.0",
+					Dependencies: []LockDependency{
+						{
+							Package:     "example.org/provider",
+							Type:        &prv,
+							Constraints: pointer.StringPtr(">=v1.0.0"),
+						},
+						{
+							Package: "example.org/other",
+						},
+					},
+				}},
+			},
+		},
+		"Empty": {
+			reason: "Absent optional fields should be absent in our model",
+			u:      &kunstructured.Unstructured{Object: map[string]interface{}{}},
+			want: Lock{
+				Metadata: &ObjectMeta{},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GetLock(tc.u)
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(Lock{}, "Unstructured"), cmp.AllowUnexported(ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\nGetLock(...): -want, +got\n:%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGetRevisionDiff(t *testing.T) {
+	crd := func(name, uid string) RevisionObject {
+		return RevisionObject{Reference: xpv1.TypedReference{
+			APIVersion: "apiextensions.k8s.io/v1",
+			Kind:       "CustomResourceDefinition",
+			Name:       name,
+			UID:        types.UID(uid),
+		}}
+	}
+	withSpec := func(o RevisionObject, spec map[string]interface{}, rv string) RevisionObject {
+		u := &kunstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+		u.SetAPIVersion(o.Reference.APIVersion)
+		u.SetKind(o.Reference.Kind)
+		u.SetName(o.Reference.Name)
+		u.SetResourceVersion(rv)
+		o.Object = u
+		return o
+	}
+
+	cases := map[string]struct {
+		reason string
+		a      []RevisionObject
+		b      []RevisionObject
+		want   *RevisionDiff
+	}{
+		"Identical": {
+			reason: "Revisions that install the same objects should produce an empty diff.",
+			a:      []RevisionObject{crd("same", "1")},
+			b:      []RevisionObject{crd("same", "1")},
+			want:   &RevisionDiff{},
+		},
+		"SameContent": {
+			reason: "Objects whose content is the same should not be reported, even if their metadata differs.",
+			a:      []RevisionObject{withSpec(crd("same", "1"), map[string]interface{}{"group": "example.org"}, "1")},
+			b:      []RevisionObject{withSpec(crd("same", "1"), map[string]interface{}{"group": "example.org"}, "2")},
+			want:   &RevisionDiff{},
+		},
+		"ContentChanged": {
+			reason: "Objects whose content differs should be reported as changed, even if only their spec differs.",
+			a:      []RevisionObject{withSpec(crd("edited", "1"), map[string]interface{}{"group": "example.org"}, "1")},
+			b:      []RevisionObject{withSpec(crd("edited", "1"), map[string]interface{}{"group": "example.net"}, "1")},
+			want: &RevisionDiff{
+				Changed: []RevisionObjectDiff{{
+					Kind:           "CustomResourceDefinition",
+					Name:           "edited",
+					FromAPIVersion: pointer.StringPtr("apiextensions.k8s.io/v1"),
+					ToAPIVersion:   pointer.StringPtr("apiextensions.k8s.io/v1"),
+				}},
+			},
+		},
+		"Different": {
+			reason: "Objects that were added, removed, or changed should be reported.",
+			a: []RevisionObject{
+				crd("same", "1"),
+				crd("removed", "2"),
+				crd("recreated", "3"),
+				{Reference: xpv1.TypedReference{APIVersion: "apiextensions.k8s.io/v1beta1", Kind: "CustomResourceDefinition", Name: "upgraded", UID: "4"}},
+			},
+			b: []RevisionObject{
+				crd("same", "1"),
+				crd("recreated", "5"),
+				crd("upgraded", "4"),
+				crd("added", "6"),
+			},
+			want: &RevisionDiff{
+				Added: []RevisionObjectDiff{{
+					Kind:         "CustomResourceDefinition",
+					Name:         "added",
+					ToAPIVersion: pointer.StringPtr("apiextensions.k8s.io/v1"),
+				}},
+				Removed: []RevisionObjectDiff{{
+					Kind:           "CustomResourceDefinition",
+					Name:           "removed",
+					FromAPIVersion: pointer.StringPtr("apiextensions.k8s.io/v1"),
+				}},
+				Changed: []RevisionObjectDiff{
+					{
+						Kind:           "CustomResourceDefinition",
+						Name:           "recreated",
+						FromAPIVersion: pointer.StringPtr("apiextensions.k8s.io/v1"),
+						ToAPIVersion:   pointer.StringPtr("apiextensions.k8s.io/v1"),
+					},
+					{
+						Kind:           "CustomResourceDefinition",
+						Name:           "upgraded",
+						FromAPIVersion: pointer.StringPtr("apiextensions.k8s.io/v1beta1"),
+						ToAPIVersion:   pointer.StringPtr("apiextensions.k8s.io/v1"),
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GetRevisionDiff(tc.a, tc.b)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nGetRevisionDiff(...): -want, +got\n:%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGetPullError(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		in     []xpv1.Condition
+		want   *string
+	}{
+		"NoConditions": {
+			reason: "A revision without conditions has no pull error.",
+		},
+		"Healthy": {
+			reason: "A true condition is not a pull error, regardless of its message.",
+			in: []xpv1.Condition{{
+				Type:    "Healthy",
+				Status:  corev1.ConditionTrue,
+				Message: "previously unauthorized",
+			}},
+		},
+		"UnrelatedError": {
+			reason: "An unhealthy condition that does not indicate a pull failure is not a pull error.",
+			in: []xpv1.Condition{{
+				Type:    "Healthy",
+				Status:  corev1.ConditionFalse,
+				Message: "admission webhook \"cool.example.org\" denied the request",
+			}},
+		},
+		"Unauthorized": {
+			reason: "An unhealthy condition that indicates a registry auth failure is a pull error.",
+			in: []xpv1.Condition{{
+				Type:    "Healthy",
+				Status:  corev1.ConditionFalse,
+				Message: "cannot unpack package: GET https://registry.example.org: UNAUTHORIZED: authentication required",
+			}},
+			want: pointer.StringPtr("cannot unpack package: GET https://registry.example.org: UNAUTHORIZED: authentication required"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GetPullError(tc.in)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nGetPullError(...): -want, +got\n:%s", tc.reason, diff)
+			}
+		})
+	}
+}
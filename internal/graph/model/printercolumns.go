@@ -0,0 +1,131 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	kextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// GetCustomResourceColumnDefinitions from the supplied Kubernetes columns.
+func GetCustomResourceColumnDefinitions(in []kextv1.CustomResourceColumnDefinition) []CustomResourceColumnDefinition {
+	if in == nil {
+		return nil
+	}
+
+	out := make([]CustomResourceColumnDefinition, len(in))
+	for i := range in {
+		c := in[i] // So we don't take the address of the range variable.
+		out[i] = CustomResourceColumnDefinition{
+			Name:     c.Name,
+			Type:     c.Type,
+			Priority: int(c.Priority),
+			JSONPath: c.JSONPath,
+		}
+		if c.Format != "" {
+			out[i].Format = &c.Format
+		}
+		if c.Description != "" {
+			out[i].Description = &c.Description
+		}
+	}
+	return out
+}
+
+// GetPrinterColumnValues evaluates the supplied printer columns against the
+// supplied unstructured object, the same way kubectl get would. A column's
+// value is nil if its JSONPath is invalid or does not match the object.
+func GetPrinterColumnValues(cols []CustomResourceColumnDefinition, obj map[string]interface{}) []PrinterColumnValue {
+	if cols == nil {
+		return nil
+	}
+
+	out := make([]PrinterColumnValue, len(cols))
+	for i, c := range cols {
+		out[i] = PrinterColumnValue{
+			Name:     c.Name,
+			Type:     c.Type,
+			Priority: c.Priority,
+			Value:    evaluate(c, obj),
+		}
+	}
+	return out
+}
+
+func evaluate(c CustomResourceColumnDefinition, obj map[string]interface{}) *string {
+	jp := jsonpath.New(c.Name).AllowMissingKeys(true)
+	if err := jp.Parse(fmt.Sprintf("{%s}", c.JSONPath)); err != nil {
+		return nil
+	}
+	results, err := jp.FindResults(obj)
+	if err != nil || len(results) == 0 || len(results[0]) == 0 {
+		return nil
+	}
+
+	// Like kubectl, we only consider the first result.
+	v := results[0][0].Interface()
+
+	var s string
+	switch c.Type {
+	case "string":
+		buf := &bytes.Buffer{}
+		if err := jp.PrintResults(buf, []reflect.Value{reflect.ValueOf(v)}); err != nil {
+			return nil
+		}
+		s = buf.String()
+	case "integer":
+		switch n := v.(type) {
+		case int64:
+			s = strconv.FormatInt(n, 10)
+		case float64:
+			s = strconv.FormatInt(int64(n), 10)
+		default:
+			return nil
+		}
+	case "number":
+		switch n := v.(type) {
+		case int64:
+			s = strconv.FormatInt(n, 10)
+		case float64:
+			s = strconv.FormatFloat(n, 'f', -1, 64)
+		default:
+			return nil
+		}
+	case "boolean":
+		b, ok := v.(bool)
+		if !ok {
+			return nil
+		}
+		s = strconv.FormatBool(b)
+	case "date":
+		str, ok := v.(string)
+		if !ok {
+			return nil
+		}
+		if _, err := time.Parse(time.RFC3339, str); err != nil {
+			return nil
+		}
+		s = str
+	default:
+		s = fmt.Sprintf("%v", v)
+	}
+	return &s
+}
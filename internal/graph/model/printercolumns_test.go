@@ -0,0 +1,92 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/pointer"
+)
+
+func TestGetPrinterColumnValues(t *testing.T) {
+	obj := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"creationTimestamp": "2021-06-01T00:00:00Z",
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+			"ratio":    0.5,
+			"paused":   true,
+			"name":     "cool",
+		},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True"},
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		reason string
+		cols   []CustomResourceColumnDefinition
+		want   []PrinterColumnValue
+	}{
+		"Nil": {
+			reason: "No columns should produce no values.",
+		},
+		"Types": {
+			reason: "Each type of column should be formatted appropriately.",
+			cols: []CustomResourceColumnDefinition{
+				{Name: "NAME", Type: "string", JSONPath: ".spec.name"},
+				{Name: "REPLICAS", Type: "integer", JSONPath: ".spec.replicas"},
+				{Name: "RATIO", Type: "number", JSONPath: ".spec.ratio"},
+				{Name: "PAUSED", Type: "boolean", JSONPath: ".spec.paused", Priority: 1},
+				{Name: "AGE", Type: "date", JSONPath: ".metadata.creationTimestamp"},
+				{Name: "READY", Type: "string", JSONPath: ".status.conditions[?(@.type=='Ready')].status"},
+			},
+			want: []PrinterColumnValue{
+				{Name: "NAME", Type: "string", Value: pointer.StringPtr("cool")},
+				{Name: "REPLICAS", Type: "integer", Value: pointer.StringPtr("3")},
+				{Name: "RATIO", Type: "number", Value: pointer.StringPtr("0.5")},
+				{Name: "PAUSED", Type: "boolean", Priority: 1, Value: pointer.StringPtr("true")},
+				{Name: "AGE", Type: "date", Value: pointer.StringPtr("2021-06-01T00:00:00Z")},
+				{Name: "READY", Type: "string", Value: pointer.StringPtr("True")},
+			},
+		},
+		"Unmatched": {
+			reason: "A column that doesn't match the object, or that is of the wrong type, should have no value.",
+			cols: []CustomResourceColumnDefinition{
+				{Name: "MISSING", Type: "string", JSONPath: ".spec.missing"},
+				{Name: "INVALID", Type: "string", JSONPath: ".spec[["},
+				{Name: "WRONG", Type: "integer", JSONPath: ".spec.name"},
+			},
+			want: []PrinterColumnValue{
+				{Name: "MISSING", Type: "string"},
+				{Name: "INVALID", Type: "string"},
+				{Name: "WRONG", Type: "integer"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GetPrinterColumnValues(tc.cols, obj)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nGetPrinterColumnValues(...): -want, +got\n:%s", tc.reason, diff)
+			}
+		})
+	}
+}
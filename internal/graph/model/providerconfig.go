@@ -37,6 +37,14 @@ func GetProviderConfigStatus(pc *unstructured.ProviderConfig) *ProviderConfigSta
 	return out
 }
 
+// GetProviderConfigScope from the supplied Crossplane ProviderConfig.
+func GetProviderConfigScope(pc *unstructured.ProviderConfig) ResourceScope {
+	if pc.GetNamespace() != "" {
+		return ResourceScopeNamespaceScoped
+	}
+	return ResourceScopeClusterScoped
+}
+
 // GetProviderConfig from the suppled Crossplane ProviderConfig.
 func GetProviderConfig(u *kunstructured.Unstructured) ProviderConfig {
 	pc := &unstructured.ProviderConfig{Unstructured: *u}
@@ -44,12 +52,14 @@ func GetProviderConfig(u *kunstructured.Unstructured) ProviderConfig {
 		ID: ReferenceID{
 			APIVersion: pc.GetAPIVersion(),
 			Kind:       pc.GetKind(),
+			Namespace:  pc.GetNamespace(),
 			Name:       pc.GetName(),
 		},
 
 		APIVersion:   pc.GetAPIVersion(),
 		Kind:         pc.GetKind(),
 		Metadata:     GetObjectMeta(pc),
+		Scope:        GetProviderConfigScope(pc),
 		Status:       GetProviderConfigStatus(pc),
 		Unstructured: unstruct(pc),
 	}
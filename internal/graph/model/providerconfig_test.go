@@ -20,6 +20,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 
@@ -56,17 +57,47 @@ func TestGetProviderConfig(t *testing.T) {
 				Metadata: &ObjectMeta{
 					Name: "cool",
 				},
+				Scope: ResourceScopeClusterScoped,
 				Status: &ProviderConfigStatus{
 					Conditions: []Condition{{}},
 					Users:      func() *int { i := 42; return &i }(),
 				},
 			},
 		},
+		"Namespaced": {
+			reason: "A namespaced provider config should include its namespace in its ID, and be namespace scoped.",
+			u: func() *kunstructured.Unstructured {
+				pc := &unstructured.ProviderConfig{Unstructured: kunstructured.Unstructured{}}
+
+				pc.SetAPIVersion("example.org/v1")
+				pc.SetKind("ProviderConfig")
+				pc.SetNamespace("default")
+				pc.SetName("cool")
+
+				return pc.GetUnstructured()
+			}(),
+			want: ProviderConfig{
+				ID: ReferenceID{
+					APIVersion: "example.org/v1",
+					Kind:       "ProviderConfig",
+					Namespace:  "default",
+					Name:       "cool",
+				},
+				APIVersion: "example.org/v1",
+				Kind:       "ProviderConfig",
+				Metadata: &ObjectMeta{
+					Namespace: pointer.StringPtr("default"),
+					Name:      "cool",
+				},
+				Scope: ResourceScopeNamespaceScoped,
+			},
+		},
 		"Empty": {
 			reason: "Absent optional fields should be absent in our model",
 			u:      &kunstructured.Unstructured{Object: make(map[string]interface{})},
 			want: ProviderConfig{
 				Metadata: &ObjectMeta{},
+				Scope:    ResourceScopeClusterScoped,
 			},
 		},
 	}
@@ -0,0 +1,95 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/utils/pointer"
+)
+
+// GetAggregationRule from the supplied Kubernetes aggregation rule.
+func GetAggregationRule(in *rbacv1.AggregationRule) []LabelSelector {
+	if in == nil || len(in.ClusterRoleSelectors) == 0 {
+		return nil
+	}
+
+	out := make([]LabelSelector, 0, len(in.ClusterRoleSelectors))
+	for i := range in.ClusterRoleSelectors {
+		if ls := GetLabelSelector(&in.ClusterRoleSelectors[i]); ls != nil {
+			out = append(out, *ls)
+		}
+	}
+	return out
+}
+
+// GetClusterRole from the supplied Kubernetes ClusterRole.
+func GetClusterRole(cr *rbacv1.ClusterRole) ClusterRole {
+	return ClusterRole{
+		ID: ReferenceID{
+			APIVersion: rbacv1.SchemeGroupVersion.String(),
+			Kind:       "ClusterRole",
+			Name:       cr.GetName(),
+		},
+		APIVersion:      rbacv1.SchemeGroupVersion.String(),
+		Kind:            "ClusterRole",
+		Metadata:        GetObjectMeta(cr),
+		Rules:           GetPolicyRules(cr.Rules),
+		AggregationRule: GetAggregationRule(cr.AggregationRule),
+		Unstructured:    unstruct(cr),
+	}
+}
+
+// GetSubjects from the supplied Kubernetes subjects.
+func GetSubjects(in []rbacv1.Subject) []Subject {
+	if in == nil {
+		return nil
+	}
+
+	out := make([]Subject, len(in))
+	for i := range in {
+		out[i] = Subject{
+			Kind: in[i].Kind,
+			Name: in[i].Name,
+		}
+		if in[i].APIGroup != "" {
+			out[i].APIGroup = pointer.StringPtr(in[i].APIGroup)
+		}
+		if in[i].Namespace != "" {
+			out[i].Namespace = pointer.StringPtr(in[i].Namespace)
+		}
+	}
+	return out
+}
+
+// GetClusterRoleBinding from the supplied Kubernetes ClusterRoleBinding.
+func GetClusterRoleBinding(crb *rbacv1.ClusterRoleBinding) ClusterRoleBinding {
+	return ClusterRoleBinding{
+		ID: ReferenceID{
+			APIVersion: rbacv1.SchemeGroupVersion.String(),
+			Kind:       "ClusterRoleBinding",
+			Name:       crb.GetName(),
+		},
+		APIVersion: rbacv1.SchemeGroupVersion.String(),
+		Kind:       "ClusterRoleBinding",
+		Metadata:   GetObjectMeta(crb),
+		RoleRef: &RoleReference{
+			APIGroup: crb.RoleRef.APIGroup,
+			Kind:     crb.RoleRef.Kind,
+			Name:     crb.RoleRef.Name,
+		},
+		Subjects:     GetSubjects(crb.Subjects),
+		Unstructured: unstruct(crb),
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestGetClusterRole(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		cr     *rbacv1.ClusterRole
+		want   ClusterRole
+	}{
+		"Full": {
+			reason: "All supported fields should be converted to our model",
+			cr: &rbacv1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+				Rules: []rbacv1.PolicyRule{{
+					Verbs:     []string{"get"},
+					APIGroups: []string{"example.org"},
+					Resources: []string{"examples"},
+				}},
+				AggregationRule: &rbacv1.AggregationRule{
+					ClusterRoleSelectors: []metav1.LabelSelector{{MatchLabels: map[string]string{"cool": "true"}}},
+				},
+			},
+			want: ClusterRole{
+				ID: ReferenceID{
+					APIVersion: rbacv1.SchemeGroupVersion.String(),
+					Kind:       "ClusterRole",
+					Name:       "cool",
+				},
+				APIVersion: rbacv1.SchemeGroupVersion.String(),
+				Kind:       "ClusterRole",
+				Metadata:   &ObjectMeta{Name: "cool"},
+				Rules: []PolicyRule{{
+					Verbs:     []string{"get"},
+					APIGroups: []string{"example.org"},
+					Resources: []string{"examples"},
+				}},
+				AggregationRule: []LabelSelector{{MatchLabels: map[string]string{"cool": "true"}}},
+			},
+		},
+		"Empty": {
+			reason: "Absent optional fields should be absent in our model",
+			cr:     &rbacv1.ClusterRole{},
+			want: ClusterRole{
+				ID: ReferenceID{
+					APIVersion: rbacv1.SchemeGroupVersion.String(),
+					Kind:       "ClusterRole",
+				},
+				APIVersion: rbacv1.SchemeGroupVersion.String(),
+				Kind:       "ClusterRole",
+				Metadata:   &ObjectMeta{},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GetClusterRole(tc.cr)
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(ClusterRole{}, "Unstructured"), cmp.AllowUnexported(ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\nGetClusterRole(...): -want, +got\n:%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGetClusterRoleBinding(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		crb    *rbacv1.ClusterRoleBinding
+		want   ClusterRoleBinding
+	}{
+		"Full": {
+			reason: "All supported fields should be converted to our model",
+			crb: &rbacv1.ClusterRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+				RoleRef: rbacv1.RoleRef{
+					APIGroup: rbacv1.GroupName,
+					Kind:     "ClusterRole",
+					Name:     "coolrole",
+				},
+				Subjects: []rbacv1.Subject{
+					{
+						Kind:      rbacv1.ServiceAccountKind,
+						Namespace: "crossplane-system",
+						Name:      "coolsa",
+					},
+					{
+						Kind:     rbacv1.GroupKind,
+						APIGroup: rbacv1.GroupName,
+						Name:     "coolgroup",
+					},
+				},
+			},
+			want: ClusterRoleBinding{
+				ID: ReferenceID{
+					APIVersion: rbacv1.SchemeGroupVersion.String(),
+					Kind:       "ClusterRoleBinding",
+					Name:       "cool",
+				},
+				APIVersion: rbacv1.SchemeGroupVersion.String(),
+				Kind:       "ClusterRoleBinding",
+				Metadata:   &ObjectMeta{Name: "cool"},
+				RoleRef: &RoleReference{
+					APIGroup: rbacv1.GroupName,
+					Kind:     "ClusterRole",
+					Name:     "coolrole",
+				},
+				Subjects: []Subject{
+					{
+						Kind:      rbacv1.ServiceAccountKind,
+						Namespace: pointer.StringPtr("crossplane-system"),
+						Name:      "coolsa",
+					},
+					{
+						Kind:     rbacv1.GroupKind,
+						APIGroup: pointer.StringPtr(rbacv1.GroupName),
+						Name:     "coolgroup",
+					},
+				},
+			},
+		},
+		"Empty": {
+			reason: "Absent optional fields should be absent in our model",
+			crb:    &rbacv1.ClusterRoleBinding{},
+			want: ClusterRoleBinding{
+				ID: ReferenceID{
+					APIVersion: rbacv1.SchemeGroupVersion.String(),
+					Kind:       "ClusterRoleBinding",
+				},
+				APIVersion: rbacv1.SchemeGroupVersion.String(),
+				Kind:       "ClusterRoleBinding",
+				Metadata:   &ObjectMeta{},
+				RoleRef:    &RoleReference{},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GetClusterRoleBinding(tc.crb)
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(ClusterRoleBinding{}, "Unstructured"), cmp.AllowUnexported(ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\nGetClusterRoleBinding(...): -want, +got\n:%s", tc.reason, diff)
+			}
+		})
+	}
+}
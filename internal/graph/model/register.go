@@ -0,0 +1,189 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// A ResourceConverter converts an unstructured Kubernetes resource of a
+// particular kind to a model type.
+type ResourceConverter interface {
+	Convert(u *kunstructured.Unstructured) (KubernetesResource, error)
+}
+
+// A ResourceConverterFn is a function that satisfies ResourceConverter.
+type ResourceConverterFn func(u *kunstructured.Unstructured) (KubernetesResource, error)
+
+// Convert the supplied unstructured Kubernetes resource.
+func (fn ResourceConverterFn) Convert(u *kunstructured.Unstructured) (KubernetesResource, error) {
+	return fn(u)
+}
+
+var (
+	convertersMx sync.RWMutex
+	converters   = map[schema.GroupVersionKind]ResourceConverter{}
+	patterns     = []patternConverter{}
+)
+
+type patternConverter struct {
+	pattern KindPattern
+	ResourceConverter
+}
+
+const errFmtInvalidPattern = "invalid kind pattern %q: must be group/Kind or group/version/Kind"
+
+// A KindPattern matches kinds of resource. An empty or "*" group, version, or
+// kind matches any value. A group of the form "*.example.org" matches any
+// subdomain of example.org.
+type KindPattern struct {
+	Group   string
+	Version string
+	Kind    string
+}
+
+// ParseKindPattern parses a pattern of the form group/Kind, which matches any
+// version, or group/version/Kind. For example "*.aws.upbound.io/*" matches any
+// kind of any group that is a subdomain of aws.upbound.io.
+func ParseKindPattern(s string) (KindPattern, error) {
+	parts := strings.Split(s, "/")
+	for _, p := range parts {
+		if p == "" {
+			return KindPattern{}, errors.Errorf(errFmtInvalidPattern, s)
+		}
+	}
+	switch len(parts) {
+	case 2:
+		return KindPattern{Group: parts[0], Kind: parts[1]}, nil
+	case 3:
+		return KindPattern{Group: parts[0], Version: parts[1], Kind: parts[2]}, nil
+	default:
+		return KindPattern{}, errors.Errorf(errFmtInvalidPattern, s)
+	}
+}
+
+// Matches returns true if the supplied kind matches the pattern.
+func (p KindPattern) Matches(gvk schema.GroupVersionKind) bool {
+	return matches(p.Group, gvk.Group) && matches(p.Version, gvk.Version) && matches(p.Kind, gvk.Kind)
+}
+
+func matches(pattern, value string) bool {
+	switch {
+	case pattern == "" || pattern == "*":
+		return true
+	case strings.HasPrefix(pattern, "*."):
+		return strings.HasSuffix(value, pattern[1:])
+	default:
+		return pattern == value
+	}
+}
+
+// RegisterResourceConverter registers a converter that GetKubernetesResource
+// will use to convert resources of the supplied kind, in preference to its
+// built in type detection. Registering a converter for a kind that already has
+// one replaces it. The types a converter returns must be known to the GraphQL
+// schema; a converter may not return a type the schema does not define.
+func RegisterResourceConverter(gvk schema.GroupVersionKind, c ResourceConverter) {
+	convertersMx.Lock()
+	defer convertersMx.Unlock()
+	converters[gvk] = c
+}
+
+// RegisterResourceConverterPattern registers a converter that
+// GetKubernetesResource will use to convert resources of any kind that matches
+// the supplied pattern, in preference to its built in type detection. This
+// allows resources that the built in type detection misses, such as managed
+// resources of a provider that don't use a providerConfigRef, to be modelled
+// without registering each kind. Converters registered for a specific kind
+// take precedence over patterns. Patterns are matched in the order they were
+// registered.
+func RegisterResourceConverterPattern(p KindPattern, c ResourceConverter) {
+	convertersMx.Lock()
+	defer convertersMx.Unlock()
+	patterns = append(patterns, patternConverter{pattern: p, ResourceConverter: c})
+}
+
+// converter returns the converter registered for the supplied kind, if any.
+func converter(gvk schema.GroupVersionKind) (ResourceConverter, bool) {
+	convertersMx.RLock()
+	defer convertersMx.RUnlock()
+	if c, ok := converters[gvk]; ok {
+		return c, true
+	}
+	for _, pc := range patterns {
+		if pc.pattern.Matches(gvk) {
+			return pc.ResourceConverter, true
+		}
+	}
+	return nil, false
+}
+
+// Categories to which kinds of resource may belong, per their CRDs.
+const (
+	CategoryManaged   = "managed"
+	CategoryComposite = "composite"
+	CategoryClaim     = "claim"
+)
+
+// A CategoryIndex returns the categories to which a kind of resource belongs.
+// Each cluster has its own index; GetKubernetesResource consults the one it is
+// supplied to determine whether a resource is a managed resource, a composite
+// resource, or a composite resource claim.
+type CategoryIndex interface {
+	Categories(gk schema.GroupKind) []string
+}
+
+// inCategory returns true if the supplied index reports that the supplied kind
+// of resource belongs to the supplied category. A nil index reports nothing.
+func inCategory(ci CategoryIndex, gk schema.GroupKind, category string) bool {
+	if ci == nil {
+		return false
+	}
+	for _, c := range ci.Categories(gk) {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// Converters that may be registered for kinds of resource that
+// GetKubernetesResource would not otherwise detect, for example managed
+// resources that don't embed the usual Crossplane spec and status fields.
+var (
+	ManagedResourceConverter = ResourceConverterFn(func(u *kunstructured.Unstructured) (KubernetesResource, error) {
+		return GetManagedResource(u), nil
+	})
+	ProviderConfigConverter = ResourceConverterFn(func(u *kunstructured.Unstructured) (KubernetesResource, error) {
+		return GetProviderConfig(u), nil
+	})
+	StoreConfigConverter = ResourceConverterFn(func(u *kunstructured.Unstructured) (KubernetesResource, error) {
+		return GetStoreConfig(u), nil
+	})
+	CompositeResourceConverter = ResourceConverterFn(func(u *kunstructured.Unstructured) (KubernetesResource, error) {
+		return GetCompositeResource(u), nil
+	})
+	CompositeResourceClaimConverter = ResourceConverterFn(func(u *kunstructured.Unstructured) (KubernetesResource, error) {
+		return GetCompositeResourceClaim(u), nil
+	})
+	GenericResourceConverter = ResourceConverterFn(func(u *kunstructured.Unstructured) (KubernetesResource, error) {
+		return GetGenericResource(u), nil
+	})
+)
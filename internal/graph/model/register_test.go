@@ -0,0 +1,255 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestRegisterResourceConverter(t *testing.T) {
+	errBoom := errors.New("boom")
+	managed := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Managed"}
+	broken := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Broken"}
+	matched := schema.GroupVersionKind{Group: "compute.cloud.example.org", Version: "v1", Kind: "Config"}
+
+	RegisterResourceConverter(managed, ManagedResourceConverter)
+	RegisterResourceConverter(broken, ResourceConverterFn(func(_ *kunstructured.Unstructured) (KubernetesResource, error) {
+		return nil, errBoom
+	}))
+	RegisterResourceConverterPattern(KindPattern{Group: "*.cloud.example.org"}, ProviderConfigConverter)
+	defer func() {
+		convertersMx.Lock()
+		delete(converters, managed)
+		delete(converters, broken)
+		patterns = nil
+		convertersMx.Unlock()
+	}()
+
+	u := func(gvk schema.GroupVersionKind) *kunstructured.Unstructured {
+		u := &kunstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		u.SetName("cool")
+		return u
+	}
+
+	type want struct {
+		kr  KubernetesResource
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		u      *kunstructured.Unstructured
+		want   want
+	}{
+		"Registered": {
+			reason: "A resource of a kind with a registered converter should be converted by that converter.",
+			u:      u(managed),
+			want:   want{kr: GetManagedResource(u(managed))},
+		},
+		"Pattern": {
+			reason: "A resource of a kind that matches a registered pattern should be converted by that pattern's converter.",
+			u:      u(matched),
+			want:   want{kr: GetProviderConfig(u(matched))},
+		},
+		"ConverterError": {
+			reason: "Errors returned by a registered converter should be returned.",
+			u:      u(broken),
+			want:   want{err: errBoom},
+		},
+		"Unregistered": {
+			reason: "A resource of an unrecognised kind without a registered converter should be a generic resource.",
+			u:      u(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Other"}),
+			want:   want{kr: GetGenericResource(u(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Other"}))},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			kr, err := GetKubernetesResource(tc.u, nil)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nGetKubernetesResource(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.kr, kr, cmpopts.IgnoreUnexported(ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\nGetKubernetesResource(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestParseKindPattern(t *testing.T) {
+	type want struct {
+		p   KindPattern
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		s      string
+		want   want
+	}{
+		"GroupKind": {
+			reason: "A group/Kind pattern should match any version.",
+			s:      "*.aws.upbound.io/*",
+			want:   want{p: KindPattern{Group: "*.aws.upbound.io", Kind: "*"}},
+		},
+		"GroupVersionKind": {
+			reason: "A group/version/Kind pattern should be parsed.",
+			s:      "example.org/v1/Managed",
+			want:   want{p: KindPattern{Group: "example.org", Version: "v1", Kind: "Managed"}},
+		},
+		"Invalid": {
+			reason: "A pattern without a group should be rejected.",
+			s:      "Managed",
+			want:   want{err: errors.Errorf(errFmtInvalidPattern, "Managed")},
+		},
+		"Empty": {
+			reason: "A pattern with an empty segment should be rejected.",
+			s:      "example.org//Managed",
+			want:   want{err: errors.Errorf(errFmtInvalidPattern, "example.org//Managed")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p, err := ParseKindPattern(tc.s)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nParseKindPattern(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.p, p); diff != "" {
+				t.Errorf("\n%s\nParseKindPattern(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestKindPatternMatches(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "ec2.aws.upbound.io", Version: "v1beta1", Kind: "Instance"}
+
+	cases := map[string]struct {
+		p    KindPattern
+		want bool
+	}{
+		"Exact":          {p: KindPattern{Group: "ec2.aws.upbound.io", Version: "v1beta1", Kind: "Instance"}, want: true},
+		"AnyVersion":     {p: KindPattern{Group: "ec2.aws.upbound.io", Kind: "Instance"}, want: true},
+		"Subdomain":      {p: KindPattern{Group: "*.aws.upbound.io", Kind: "*"}, want: true},
+		"OtherSubdomain": {p: KindPattern{Group: "*.gcp.upbound.io", Kind: "*"}, want: false},
+		"OtherKind":      {p: KindPattern{Group: "ec2.aws.upbound.io", Kind: "VPC"}, want: false},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.p.Matches(gvk)); diff != "" {
+				t.Errorf("Matches(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+type mockCategoryIndex map[schema.GroupKind][]string
+
+func (m mockCategoryIndex) Categories(gk schema.GroupKind) []string { return m[gk] }
+
+func TestGetKubernetesResourceCategories(t *testing.T) {
+	managed := schema.GroupVersionKind{Group: "ec2.aws.upbound.io", Version: "v1beta1", Kind: "VPC"}
+	composite := schema.GroupVersionKind{Group: "example.org", Version: "v1alpha1", Kind: "XNetwork"}
+	claim := schema.GroupVersionKind{Group: "example.org", Version: "v1alpha1", Kind: "Network"}
+	other := schema.GroupVersionKind{Group: "example.org", Version: "v1alpha1", Kind: "Other"}
+	overridden := schema.GroupVersionKind{Group: "example.org", Version: "v1alpha1", Kind: "Overridden"}
+
+	ci := mockCategoryIndex{
+		managed.GroupKind():    {"crossplane", "managed", "aws"},
+		composite.GroupKind():  {"composite"},
+		claim.GroupKind():      {"claim"},
+		other.GroupKind():      {"crossplane"},
+		overridden.GroupKind(): {"managed"},
+	}
+	RegisterResourceConverter(overridden, GenericResourceConverter)
+	defer func() {
+		convertersMx.Lock()
+		delete(converters, overridden)
+		convertersMx.Unlock()
+	}()
+
+	u := func(gvk schema.GroupVersionKind) *kunstructured.Unstructured {
+		u := &kunstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		u.SetName("cool")
+		return u
+	}
+
+	cases := map[string]struct {
+		reason string
+		ci     CategoryIndex
+		u      *kunstructured.Unstructured
+		want   KubernetesResource
+	}{
+		"NoIndex": {
+			reason: "A resource should fall back to structural type detection if there is no category index.",
+			u:      u(managed),
+			want:   GetGenericResource(u(managed)),
+		},
+		"Managed": {
+			reason: "A resource in the managed category should be a managed resource, even if it doesn't look like one.",
+			ci:     ci,
+			u:      u(managed),
+			want:   GetManagedResource(u(managed)),
+		},
+		"Composite": {
+			reason: "A resource in the composite category should be a composite resource.",
+			ci:     ci,
+			u:      u(composite),
+			want:   GetCompositeResource(u(composite)),
+		},
+		"Claim": {
+			reason: "A resource in the claim category should be a composite resource claim.",
+			ci:     ci,
+			u:      u(claim),
+			want:   GetCompositeResourceClaim(u(claim)),
+		},
+		"OtherCategory": {
+			reason: "A resource in none of the categories we recognise should fall back to structural type detection.",
+			ci:     ci,
+			u:      u(other),
+			want:   GetGenericResource(u(other)),
+		},
+		"RegisteredConverter": {
+			reason: "A registered converter should take precedence over categories.",
+			ci:     ci,
+			u:      u(overridden),
+			want:   GetGenericResource(u(overridden)),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			kr, err := GetKubernetesResource(tc.u, tc.ci)
+			if err != nil {
+				t.Fatalf("\n%s\nGetKubernetesResource(...): %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, kr, cmpopts.IgnoreUnexported(ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\nGetKubernetesResource(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
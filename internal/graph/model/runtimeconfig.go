@@ -0,0 +1,60 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"encoding/json"
+
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/upbound/xgql/internal/unstructured"
+)
+
+// template returns the supplied partial resource template as JSON, or nil if
+// there is no template.
+func template(in map[string]interface{}) []byte {
+	if in == nil {
+		return nil
+	}
+	out, err := json.Marshal(in)
+	if err != nil {
+		return nil
+	}
+	return out
+}
+
+// GetDeploymentRuntimeConfig from the supplied Crossplane deployment runtime
+// config. Deployment runtime configs are read as unstructured resources, so any
+// API version may be supplied.
+func GetDeploymentRuntimeConfig(u *kunstructured.Unstructured) DeploymentRuntimeConfig {
+	rc := &unstructured.DeploymentRuntimeConfig{Unstructured: *u}
+	return DeploymentRuntimeConfig{
+		ID: ReferenceID{
+			APIVersion: rc.GetAPIVersion(),
+			Kind:       rc.GetKind(),
+			Name:       rc.GetName(),
+		},
+
+		APIVersion: rc.GetAPIVersion(),
+		Kind:       rc.GetKind(),
+		Metadata:   GetObjectMeta(rc),
+		Spec: &DeploymentRuntimeConfigSpec{
+			DeploymentTemplate:     template(rc.GetDeploymentTemplate()),
+			ServiceTemplate:        template(rc.GetServiceTemplate()),
+			ServiceAccountTemplate: template(rc.GetServiceAccountTemplate()),
+		},
+		Unstructured: unstruct(rc),
+	}
+}
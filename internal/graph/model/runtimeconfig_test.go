@@ -0,0 +1,81 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestGetDeploymentRuntimeConfig(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		u      *kunstructured.Unstructured
+		want   DeploymentRuntimeConfig
+	}{
+		"Full": {
+			reason: "All supported fields should be converted to our model",
+			u: &kunstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "pkg.crossplane.io/v1beta1",
+				"kind":       "DeploymentRuntimeConfig",
+				"metadata": map[string]interface{}{
+					"name": "cool",
+				},
+				"spec": map[string]interface{}{
+					"deploymentTemplate":     map[string]interface{}{"spec": map[string]interface{}{"replicas": int64(2)}},
+					"serviceTemplate":        map[string]interface{}{"metadata": map[string]interface{}{"name": "svc"}},
+					"serviceAccountTemplate": map[string]interface{}{"metadata": map[string]interface{}{"name": "sa"}},
+				},
+			}},
+			want: DeploymentRuntimeConfig{
+				ID: ReferenceID{
+					APIVersion: "pkg.crossplane.io/v1beta1",
+					Kind:       "DeploymentRuntimeConfig",
+					Name:       "cool",
+				},
+				APIVersion: "pkg.crossplane.io/v1beta1",
+				Kind:       "DeploymentRuntimeConfig",
+				Metadata: &ObjectMeta{
+					Name: "cool",
+				},
+				Spec: &DeploymentRuntimeConfigSpec{
+					DeploymentTemplate:     []byte(`{"spec":{"replicas":2}}`),
+					ServiceTemplate:        []byte(`{"metadata":{"name":"svc"}}`),
+					ServiceAccountTemplate: []byte(`{"metadata":{"name":"sa"}}`),
+				},
+			},
+		},
+		"Empty": {
+			reason: "Absent optional fields should be absent in our model",
+			u:      &kunstructured.Unstructured{Object: map[string]interface{}{}},
+			want: DeploymentRuntimeConfig{
+				Metadata: &ObjectMeta{},
+				Spec:     &DeploymentRuntimeConfigSpec{},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GetDeploymentRuntimeConfig(tc.u)
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(DeploymentRuntimeConfig{}, "Unstructured"), cmp.AllowUnexported(ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\nGetDeploymentRuntimeConfig(...): -want, +got\n:%s", tc.reason, diff)
+			}
+		})
+	}
+}
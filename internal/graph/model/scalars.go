@@ -0,0 +1,89 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	errFmtNotQuantity = "%T is not a quantity"
+	errFmtNotDuration = "%T is not a duration"
+	errParseQuantity  = "cannot parse quantity"
+	errParseDuration  = "cannot parse duration"
+)
+
+// MarshalQuantity marshals a Kubernetes resource quantity to GraphQL, in its
+// canonical form.
+func MarshalQuantity(val resource.Quantity) graphql.Marshaler {
+	return graphql.MarshalString(val.String())
+}
+
+// UnmarshalQuantity unmarshals a Kubernetes resource quantity from GraphQL.
+func UnmarshalQuantity(v interface{}) (resource.Quantity, error) {
+	var s string
+	switch t := v.(type) {
+	case string:
+		s = t
+	case json.Number:
+		s = t.String()
+	case int:
+		s = strconv.Itoa(t)
+	case int64:
+		s = strconv.FormatInt(t, 10)
+	case float64:
+		s = strconv.FormatFloat(t, 'f', -1, 64)
+	default:
+		return resource.Quantity{}, errors.Errorf(errFmtNotQuantity, v)
+	}
+	q, err := resource.ParseQuantity(s)
+	return q, errors.Wrap(err, errParseQuantity)
+}
+
+// MarshalDuration marshals a duration to GraphQL, e.g. as '1m30s'.
+func MarshalDuration(val time.Duration) graphql.Marshaler {
+	return graphql.MarshalString(val.String())
+}
+
+// UnmarshalDuration unmarshals a duration from GraphQL. Durations may be
+// supplied as a string like '1m30s', or as a number of seconds.
+func UnmarshalDuration(v interface{}) (time.Duration, error) {
+	switch t := v.(type) {
+	case string:
+		d, err := time.ParseDuration(t)
+		return d, errors.Wrap(err, errParseDuration)
+	case json.Number:
+		f, err := t.Float64()
+		return seconds(f), errors.Wrap(err, errParseDuration)
+	case int:
+		return time.Duration(t) * time.Second, nil
+	case int64:
+		return time.Duration(t) * time.Second, nil
+	case float64:
+		return seconds(t), nil
+	default:
+		return 0, errors.Errorf(errFmtNotDuration, v)
+	}
+}
+
+func seconds(f float64) time.Duration {
+	return time.Duration(f * float64(time.Second))
+}
@@ -0,0 +1,144 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestMarshalQuantity(t *testing.T) {
+	b := &bytes.Buffer{}
+	MarshalQuantity(resource.MustParse("0.5")).MarshalGQL(b)
+	if diff := cmp.Diff(`"500m"`, b.String()); diff != "" {
+		t.Errorf("MarshalQuantity(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestUnmarshalQuantity(t *testing.T) {
+	type want struct {
+		q   string
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		v      interface{}
+		want   want
+	}{
+		"String": {
+			reason: "Quantities supplied as strings should be parsed.",
+			v:      "1Gi",
+			want:   want{q: "1Gi"},
+		},
+		"Number": {
+			reason: "Quantities supplied as numbers should be parsed.",
+			v:      json.Number("2"),
+			want:   want{q: "2"},
+		},
+		"Fraction": {
+			reason: "Quantities supplied as fractional numbers should be parsed.",
+			v:      0.25,
+			want:   want{q: "250m"},
+		},
+		"Invalid": {
+			reason: "Strings that aren't quantities should be rejected.",
+			v:      "lots",
+			want:   want{err: errors.Wrap(resource.ErrFormatWrong, errParseQuantity)},
+		},
+		"WrongType": {
+			reason: "Values that aren't strings or numbers should be rejected.",
+			v:      true,
+			want:   want{err: errors.Errorf(errFmtNotQuantity, true)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := UnmarshalQuantity(tc.v)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nUnmarshalQuantity(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.q, got.String()); diff != "" {
+				t.Errorf("\n%s\nUnmarshalQuantity(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMarshalDuration(t *testing.T) {
+	b := &bytes.Buffer{}
+	MarshalDuration(90 * time.Second).MarshalGQL(b)
+	if diff := cmp.Diff(`"1m30s"`, b.String()); diff != "" {
+		t.Errorf("MarshalDuration(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestUnmarshalDuration(t *testing.T) {
+	type want struct {
+		d   time.Duration
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		v      interface{}
+		want   want
+	}{
+		"String": {
+			reason: "Durations supplied as strings should be parsed.",
+			v:      "1m30s",
+			want:   want{d: 90 * time.Second},
+		},
+		"Seconds": {
+			reason: "Durations supplied as numbers should be treated as seconds.",
+			v:      int64(90),
+			want:   want{d: 90 * time.Second},
+		},
+		"FractionalSeconds": {
+			reason: "Durations supplied as fractional numbers should be treated as seconds.",
+			v:      json.Number("1.5"),
+			want:   want{d: 1500 * time.Millisecond},
+		},
+		"WrongType": {
+			reason: "Values that aren't strings or numbers should be rejected.",
+			v:      true,
+			want:   want{err: errors.Errorf(errFmtNotDuration, true)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := UnmarshalDuration(tc.v)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nUnmarshalDuration(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.d, got); diff != "" {
+				t.Errorf("\n%s\nUnmarshalDuration(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -35,7 +35,15 @@ func (r Composition) id() ReferenceID                 { return r.ID }
 func (r CustomResourceDefinition) id() ReferenceID    { return r.ID }
 func (r Secret) id() ReferenceID                      { return r.ID }
 func (r ConfigMap) id() ReferenceID                   { return r.ID }
+func (r ClusterRole) id() ReferenceID                 { return r.ID }
+func (r ClusterRoleBinding) id() ReferenceID          { return r.ID }
+func (r StoreConfig) id() ReferenceID                 { return r.ID }
 func (r GenericResource) id() ReferenceID             { return r.ID }
+func (r Deployment) id() ReferenceID                  { return r.ID }
+func (r Pod) id() ReferenceID                         { return r.ID }
+func (r Function) id() ReferenceID                    { return r.ID }
+func (r DeploymentRuntimeConfig) id() ReferenceID     { return r.ID }
+func (r Lock) id() ReferenceID                        { return r.ID }
 
 func (c *KubernetesResourceConnection) Len() int { return c.TotalCount }
 func (c *KubernetesResourceConnection) Less(i, j int) bool {
@@ -132,3 +140,76 @@ func (c *CompositeResourceClaimConnection) Less(i, j int) bool {
 func (c *CompositeResourceClaimConnection) Swap(i, j int) {
 	c.Nodes[i], c.Nodes[j] = c.Nodes[j], c.Nodes[i]
 }
+
+func (c *ClusterRoleConnection) Len() int { return c.TotalCount }
+func (c *ClusterRoleConnection) Less(i, j int) bool {
+	return join(c.Nodes[i].ID) < join(c.Nodes[j].ID)
+}
+func (c *ClusterRoleConnection) Swap(i, j int) {
+	c.Nodes[i], c.Nodes[j] = c.Nodes[j], c.Nodes[i]
+}
+
+func (c *ClusterRoleBindingConnection) Len() int { return c.TotalCount }
+func (c *ClusterRoleBindingConnection) Less(i, j int) bool {
+	return join(c.Nodes[i].ID) < join(c.Nodes[j].ID)
+}
+func (c *ClusterRoleBindingConnection) Swap(i, j int) {
+	c.Nodes[i], c.Nodes[j] = c.Nodes[j], c.Nodes[i]
+}
+
+func (c *FunctionConnection) Len() int { return c.TotalCount }
+func (c *FunctionConnection) Less(i, j int) bool {
+	return join(c.Nodes[i].ID) < join(c.Nodes[j].ID)
+}
+func (c *FunctionConnection) Swap(i, j int) {
+	c.Nodes[i], c.Nodes[j] = c.Nodes[j], c.Nodes[i]
+}
+
+func (c *DeploymentRuntimeConfigConnection) Len() int { return c.TotalCount }
+func (c *DeploymentRuntimeConfigConnection) Less(i, j int) bool {
+	return join(c.Nodes[i].ID) < join(c.Nodes[j].ID)
+}
+func (c *DeploymentRuntimeConfigConnection) Swap(i, j int) {
+	c.Nodes[i], c.Nodes[j] = c.Nodes[j], c.Nodes[i]
+}
+
+func (c *StoreConfigConnection) Len() int { return c.TotalCount }
+func (c *StoreConfigConnection) Less(i, j int) bool {
+	return join(c.Nodes[i].ID) < join(c.Nodes[j].ID)
+}
+func (c *StoreConfigConnection) Swap(i, j int) {
+	c.Nodes[i], c.Nodes[j] = c.Nodes[j], c.Nodes[i]
+}
+
+func (c *ManagedResourceConnection) Len() int { return c.TotalCount }
+func (c *ManagedResourceConnection) Less(i, j int) bool {
+	return join(c.Nodes[i].ID) < join(c.Nodes[j].ID)
+}
+func (c *ManagedResourceConnection) Swap(i, j int) {
+	c.Nodes[i], c.Nodes[j] = c.Nodes[j], c.Nodes[i]
+}
+
+func (c *ProviderConfigConnection) Len() int { return c.TotalCount }
+func (c *ProviderConfigConnection) Less(i, j int) bool {
+	return join(c.Nodes[i].ID) < join(c.Nodes[j].ID)
+}
+func (c *ProviderConfigConnection) Swap(i, j int) {
+	c.Nodes[i], c.Nodes[j] = c.Nodes[j], c.Nodes[i]
+}
+
+func (c *PodConnection) Len() int { return c.TotalCount }
+func (c *PodConnection) Less(i, j int) bool {
+	return join(c.Nodes[i].ID) < join(c.Nodes[j].ID)
+}
+func (c *PodConnection) Swap(i, j int) {
+	c.Nodes[i], c.Nodes[j] = c.Nodes[j], c.Nodes[i]
+}
+
+func (c *APIResourceConnection) Len() int { return c.TotalCount }
+func (c *APIResourceConnection) Less(i, j int) bool {
+	a, b := c.Nodes[i], c.Nodes[j]
+	return a.Group+a.Kind+a.Version < b.Group+b.Kind+b.Version
+}
+func (c *APIResourceConnection) Swap(i, j int) {
+	c.Nodes[i], c.Nodes[j] = c.Nodes[j], c.Nodes[i]
+}
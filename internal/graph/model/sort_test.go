@@ -37,6 +37,9 @@ var (
 	_ identifiable = Secret{}
 	_ identifiable = ConfigMap{}
 	_ identifiable = GenericResource{}
+	_ identifiable = Function{}
+	_ identifiable = DeploymentRuntimeConfig{}
+	_ identifiable = Lock{}
 )
 
 var (
@@ -51,6 +54,9 @@ var (
 	_ sort.Interface = &CompositeResourceDefinitionConnection{}
 	_ sort.Interface = &CompositeResourceConnection{}
 	_ sort.Interface = &CompositeResourceClaimConnection{}
+	_ sort.Interface = &PodConnection{}
+	_ sort.Interface = &FunctionConnection{}
+	_ sort.Interface = &DeploymentRuntimeConfigConnection{}
 )
 
 func TestSort(t *testing.T) {
@@ -111,6 +117,22 @@ func TestSort(t *testing.T) {
 				},
 			},
 		},
+		"ProviderConfigConnection": {
+			conn: &ProviderConfigConnection{
+				TotalCount: 2,
+				Nodes: []ProviderConfig{
+					{ID: ReferenceID{Name: "b"}},
+					{ID: ReferenceID{Name: "a"}},
+				},
+			},
+			want: &ProviderConfigConnection{
+				TotalCount: 2,
+				Nodes: []ProviderConfig{
+					{ID: ReferenceID{Name: "a"}},
+					{ID: ReferenceID{Name: "b"}},
+				},
+			},
+		},
 		"ProviderRevisionConnection": {
 			conn: &ProviderRevisionConnection{
 				TotalCount: 2,
@@ -0,0 +1,103 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/upbound/xgql/internal/unstructured"
+)
+
+// GetSecretStoreType from the supplied Crossplane secret store type.
+func GetSecretStoreType(t string) *SecretStoreType {
+	switch t {
+	case "Kubernetes":
+		out := SecretStoreTypeKubernetes
+		return &out
+	case "Vault":
+		out := SecretStoreTypeVault
+		return &out
+	case "Plugin":
+		out := SecretStoreTypePlugin
+		return &out
+	default:
+		return nil
+	}
+}
+
+// GetStoreConfigStatus from the supplied Crossplane store config.
+func GetStoreConfigStatus(sc *unstructured.StoreConfig) *StoreConfigStatus {
+	c := sc.GetConditions()
+	if len(c) == 0 {
+		return nil
+	}
+	return &StoreConfigStatus{Conditions: GetConditions(c)}
+}
+
+// GetStoreConfig from the supplied Crossplane store config.
+func GetStoreConfig(u *kunstructured.Unstructured) StoreConfig {
+	sc := &unstructured.StoreConfig{Unstructured: *u}
+
+	scope := ResourceScopeClusterScoped
+	if sc.GetNamespace() != "" {
+		scope = ResourceScopeNamespaceScoped
+	}
+
+	return StoreConfig{
+		ID: ReferenceID{
+			APIVersion: sc.GetAPIVersion(),
+			Kind:       sc.GetKind(),
+			Namespace:  sc.GetNamespace(),
+			Name:       sc.GetName(),
+		},
+
+		APIVersion: sc.GetAPIVersion(),
+		Kind:       sc.GetKind(),
+		Metadata:   GetObjectMeta(sc),
+		Scope:      scope,
+		Spec: &StoreConfigSpec{
+			Type:         GetSecretStoreType(sc.GetType()),
+			DefaultScope: sc.GetDefaultScope(),
+		},
+		Status:       GetStoreConfigStatus(sc),
+		Unstructured: unstruct(sc),
+	}
+}
+
+// PublishConnectionDetailsTo specifies the external secret store to which a
+// resource publishes its connection details.
+type PublishConnectionDetailsTo struct {
+	Name      string                `json:"name"`
+	ConfigRef *StoreConfigReference `json:"configRef"`
+
+	// The API versions at which the referenced store config may be served, in
+	// order of preference. Managed resources reference store configs that are
+	// specific to their provider.
+	StoreConfigAPIVersions []string
+}
+
+// GetPublishConnectionDetailsTo from the supplied Crossplane field. The
+// supplied API versions are those at which the referenced store config may be
+// served, in order of preference.
+func GetPublishConnectionDetailsTo(in *unstructured.PublishConnectionDetailsTo, apiVersions ...string) *PublishConnectionDetailsTo {
+	if in == nil {
+		return nil
+	}
+	out := &PublishConnectionDetailsTo{Name: in.Name, StoreConfigAPIVersions: apiVersions}
+	if in.ConfigRef != nil {
+		out.ConfigRef = &StoreConfigReference{Name: in.ConfigRef.Name}
+	}
+	return out
+}
@@ -0,0 +1,138 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	"github.com/upbound/xgql/internal/unstructured"
+)
+
+func TestGetStoreConfig(t *testing.T) {
+	vault := SecretStoreTypeVault
+
+	cases := map[string]struct {
+		reason string
+		u      *kunstructured.Unstructured
+		want   StoreConfig
+	}{
+		"Full": {
+			reason: "All supported fields should be converted to our model",
+			u: &kunstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": unstructured.APIVersionSecrets,
+				"kind":       "StoreConfig",
+				"metadata":   map[string]interface{}{"name": "vault"},
+				"spec": map[string]interface{}{
+					"type":         "Vault",
+					"defaultScope": "crossplane-system",
+				},
+				"status": map[string]interface{}{
+					"conditions": []interface{}{map[string]interface{}{}},
+				},
+			}},
+			want: StoreConfig{
+				ID: ReferenceID{
+					APIVersion: unstructured.APIVersionSecrets,
+					Kind:       "StoreConfig",
+					Name:       "vault",
+				},
+				APIVersion: unstructured.APIVersionSecrets,
+				Kind:       "StoreConfig",
+				Metadata:   &ObjectMeta{Name: "vault"},
+				Scope:      ResourceScopeClusterScoped,
+				Spec: &StoreConfigSpec{
+					Type:         &vault,
+					DefaultScope: "crossplane-system",
+				},
+				Status: &StoreConfigStatus{
+					Conditions: []Condition{{}},
+				},
+			},
+		},
+		"Namespaced": {
+			reason: "A namespaced store config should include its namespace in its ID, and be namespace scoped.",
+			u: &kunstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "example.org/v1",
+				"kind":       "StoreConfig",
+				"metadata":   map[string]interface{}{"namespace": "default", "name": "cool"},
+			}},
+			want: StoreConfig{
+				ID: ReferenceID{
+					APIVersion: "example.org/v1",
+					Kind:       "StoreConfig",
+					Namespace:  "default",
+					Name:       "cool",
+				},
+				APIVersion: "example.org/v1",
+				Kind:       "StoreConfig",
+				Metadata:   &ObjectMeta{Namespace: pointer.StringPtr("default"), Name: "cool"},
+				Scope:      ResourceScopeNamespaceScoped,
+				Spec:       &StoreConfigSpec{},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GetStoreConfig(tc.u)
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(StoreConfig{}, "Unstructured"), cmp.AllowUnexported(ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\nGetStoreConfig(...): -want, +got\n:%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGetPublishConnectionDetailsTo(t *testing.T) {
+	cases := map[string]struct {
+		reason      string
+		in          *unstructured.PublishConnectionDetailsTo
+		apiVersions []string
+		want        *PublishConnectionDetailsTo
+	}{
+		"Nil": {
+			reason: "A nil input should produce a nil output.",
+			want:   nil,
+		},
+		"Full": {
+			reason: "All supported fields should be converted to our model.",
+			in: &unstructured.PublishConnectionDetailsTo{
+				Name:      "cool-secret",
+				ConfigRef: &xpv1.Reference{Name: "vault"},
+			},
+			apiVersions: []string{unstructured.APIVersionSecrets},
+			want: &PublishConnectionDetailsTo{
+				Name:                   "cool-secret",
+				ConfigRef:              &StoreConfigReference{Name: "vault"},
+				StoreConfigAPIVersions: []string{unstructured.APIVersionSecrets},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GetPublishConnectionDetailsTo(tc.in, tc.apiVersions...)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nGetPublishConnectionDetailsTo(...): -want, +got\n:%s", tc.reason, diff)
+			}
+		})
+	}
+}
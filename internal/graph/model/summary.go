@@ -0,0 +1,56 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// Add a package with the supplied Healthy condition to the summary.
+func (s *PackageHealthSummary) Add(healthy xpv1.Condition) {
+	s.Total++
+	if healthy.Status == corev1.ConditionTrue {
+		s.Healthy++
+		return
+	}
+	s.Unhealthy++
+}
+
+// Add a resource with the supplied Ready and Synced conditions to the summary.
+func (s *ResourceHealthSummary) Add(ready, synced xpv1.Condition) {
+	s.Total++
+	if ready.Status == corev1.ConditionTrue {
+		s.Ready++
+	} else {
+		s.NotReady++
+	}
+	if synced.Status == corev1.ConditionTrue {
+		s.Synced++
+	} else {
+		s.NotSynced++
+	}
+}
+
+// Merge the supplied summary into this one. The supplied summary is assumed to
+// be of the same API group.
+func (s *ResourceHealthSummary) Merge(o *ResourceHealthSummary) {
+	s.Total += o.Total
+	s.Ready += o.Ready
+	s.NotReady += o.NotReady
+	s.Synced += o.Synced
+	s.NotSynced += o.NotSynced
+}
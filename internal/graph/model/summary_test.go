@@ -0,0 +1,48 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+func TestPackageHealthSummaryAdd(t *testing.T) {
+	s := &PackageHealthSummary{}
+	s.Add(xpv1.Condition{Status: corev1.ConditionTrue})
+	s.Add(xpv1.Condition{Status: corev1.ConditionFalse})
+	s.Add(xpv1.Condition{})
+
+	want := &PackageHealthSummary{Total: 3, Healthy: 1, Unhealthy: 2}
+	if diff := cmp.Diff(want, s); diff != "" {
+		t.Errorf("s.Add(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestResourceHealthSummary(t *testing.T) {
+	s := &ResourceHealthSummary{Group: "example.org"}
+	s.Add(xpv1.Condition{Status: corev1.ConditionTrue}, xpv1.Condition{Status: corev1.ConditionTrue})
+	s.Add(xpv1.Condition{Status: corev1.ConditionFalse}, xpv1.Condition{Status: corev1.ConditionTrue})
+	s.Merge(&ResourceHealthSummary{Group: "example.org", Total: 1, NotReady: 1, NotSynced: 1})
+
+	want := &ResourceHealthSummary{Group: "example.org", Total: 3, Ready: 1, NotReady: 2, Synced: 2, NotSynced: 1}
+	if diff := cmp.Diff(want, s); diff != "" {
+		t.Errorf("s.Add(...): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+// The API server prefixes the message of any error returned by an admission
+// webhook with this string.
+const admissionWebhookPrefix = "admission webhook "
+
+// GetValidationErrors from the supplied Kubernetes API status. A status with
+// detailed causes produces one validation error per cause.
+func GetValidationErrors(s metav1.Status) []ValidationError {
+	src := ValidationErrorSourceOther
+	switch {
+	case strings.HasPrefix(s.Message, admissionWebhookPrefix):
+		src = ValidationErrorSourceAdmissionWebhook
+	case s.Reason == metav1.StatusReasonInvalid:
+		src = ValidationErrorSourceSchema
+	}
+
+	var reason *string
+	if s.Reason != "" {
+		reason = pointer.StringPtr(string(s.Reason))
+	}
+
+	if s.Details == nil || len(s.Details.Causes) == 0 {
+		return []ValidationError{{Source: src, Reason: reason, Message: s.Message}}
+	}
+
+	out := make([]ValidationError, len(s.Details.Causes))
+	for i, c := range s.Details.Causes {
+		out[i] = ValidationError{Source: src, Reason: reason, Message: c.Message}
+		if c.Type != "" {
+			out[i].Type = pointer.StringPtr(string(c.Type))
+		}
+		if c.Field != "" {
+			out[i].Field = pointer.StringPtr(c.Field)
+		}
+	}
+	return out
+}
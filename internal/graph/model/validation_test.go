@@ -0,0 +1,100 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestGetValidationErrors(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		s      metav1.Status
+		want   []ValidationError
+	}{
+		"Schema": {
+			reason: "An invalid status should produce one schema validation error per cause.",
+			s: metav1.Status{
+				Reason:  metav1.StatusReasonInvalid,
+				Message: "Example.example.org \"cool\" is invalid",
+				Details: &metav1.StatusDetails{
+					Causes: []metav1.StatusCause{
+						{
+							Type:    metav1.CauseTypeFieldValueRequired,
+							Field:   "spec.coolness",
+							Message: "Required value",
+						},
+						{
+							Message: "something else",
+						},
+					},
+				},
+			},
+			want: []ValidationError{
+				{
+					Source:  ValidationErrorSourceSchema,
+					Reason:  pointer.StringPtr(string(metav1.StatusReasonInvalid)),
+					Type:    pointer.StringPtr(string(metav1.CauseTypeFieldValueRequired)),
+					Field:   pointer.StringPtr("spec.coolness"),
+					Message: "Required value",
+				},
+				{
+					Source:  ValidationErrorSourceSchema,
+					Reason:  pointer.StringPtr(string(metav1.StatusReasonInvalid)),
+					Message: "something else",
+				},
+			},
+		},
+		"AdmissionWebhook": {
+			reason: "A status returned by an admission webhook should produce an admission webhook validation error.",
+			s: metav1.Status{
+				Reason:  metav1.StatusReasonForbidden,
+				Message: "admission webhook \"cool.example.org\" denied the request: nope",
+			},
+			want: []ValidationError{
+				{
+					Source:  ValidationErrorSourceAdmissionWebhook,
+					Reason:  pointer.StringPtr(string(metav1.StatusReasonForbidden)),
+					Message: "admission webhook \"cool.example.org\" denied the request: nope",
+				},
+			},
+		},
+		"Other": {
+			reason: "Any other status should produce an other validation error.",
+			s: metav1.Status{
+				Message: "wat",
+			},
+			want: []ValidationError{
+				{
+					Source:  ValidationErrorSourceOther,
+					Message: "wat",
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GetValidationErrors(tc.s)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nGetValidationErrors(...): -want, +got\n:%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	authnv1 "k8s.io/api/authentication/v1"
+	"k8s.io/utils/pointer"
+)
+
+// GetViewer from the supplied Kubernetes token review status.
+func GetViewer(s authnv1.TokenReviewStatus) *Viewer {
+	out := &Viewer{
+		Username:  s.User.Username,
+		Groups:    s.User.Groups,
+		Audiences: s.Audiences,
+	}
+	if s.User.UID != "" {
+		out.UID = pointer.StringPtr(s.User.UID)
+	}
+	return out
+}
@@ -0,0 +1,67 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	authnv1 "k8s.io/api/authentication/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestGetViewer(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		s      authnv1.TokenReviewStatus
+		want   *Viewer
+	}{
+		"Full": {
+			reason: "All supported fields should be converted to our model.",
+			s: authnv1.TokenReviewStatus{
+				Authenticated: true,
+				User: authnv1.UserInfo{
+					Username: "cool",
+					UID:      "42",
+					Groups:   []string{"system:authenticated"},
+				},
+				Audiences: []string{"https://kubernetes.default.svc"},
+			},
+			want: &Viewer{
+				Username:  "cool",
+				UID:       pointer.StringPtr("42"),
+				Groups:    []string{"system:authenticated"},
+				Audiences: []string{"https://kubernetes.default.svc"},
+			},
+		},
+		"Minimal": {
+			reason: "An empty UID should be omitted.",
+			s: authnv1.TokenReviewStatus{
+				Authenticated: true,
+				User:          authnv1.UserInfo{Username: "cool"},
+			},
+			want: &Viewer{Username: "cool"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GetViewer(tc.s)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nGetViewer(...): -want, +got\n:%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"sort"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+)
+
+// GetDeploymentConditions from the supplied Kubernetes conditions.
+func GetDeploymentConditions(in []appsv1.DeploymentCondition) []Condition {
+	if in == nil {
+		return nil
+	}
+
+	out := make([]Condition, len(in))
+	for i := range in {
+		c := in[i] // So we don't take the address of the range variable.
+		out[i] = Condition{
+			Type:               string(c.Type),
+			Status:             GetConditionStatus(c.Status),
+			LastTransitionTime: c.LastTransitionTime.Time,
+			Reason:             c.Reason,
+		}
+		if c.Message != "" {
+			out[i].Message = &c.Message
+		}
+	}
+	return out
+}
+
+// GetDeploymentSpec from the supplied Kubernetes spec.
+func GetDeploymentSpec(in appsv1.DeploymentSpec) *DeploymentSpec {
+	out := &DeploymentSpec{Containers: GetContainers(in.Template.Spec.Containers)}
+	if in.Replicas != nil {
+		r := int(*in.Replicas)
+		out.Replicas = &r
+	}
+	if in.MinReadySeconds > 0 {
+		d := time.Duration(in.MinReadySeconds) * time.Second
+		out.MinReady = &d
+	}
+	if in.ProgressDeadlineSeconds != nil {
+		d := time.Duration(*in.ProgressDeadlineSeconds) * time.Second
+		out.ProgressDeadline = &d
+	}
+	return out
+}
+
+// GetContainers from the supplied Kubernetes containers.
+func GetContainers(in []corev1.Container) []Container {
+	if in == nil {
+		return nil
+	}
+	out := make([]Container, len(in))
+	for i := range in {
+		out[i] = Container{
+			Name:      in[i].Name,
+			Image:     in[i].Image,
+			Resources: GetResourceRequirements(in[i].Resources),
+		}
+	}
+	return out
+}
+
+// GetResourceRequirements from the supplied Kubernetes resource requirements.
+// Returns nil if no resources are required.
+func GetResourceRequirements(in corev1.ResourceRequirements) *ResourceRequirements {
+	if len(in.Limits) == 0 && len(in.Requests) == 0 {
+		return nil
+	}
+	return &ResourceRequirements{
+		Limits:   getResourceQuantities(in.Limits),
+		Requests: getResourceQuantities(in.Requests),
+	}
+}
+
+// getResourceQuantities returns the supplied resources sorted by name, so that
+// they're returned in a stable order.
+func getResourceQuantities(in corev1.ResourceList) []ResourceQuantity {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]ResourceQuantity, 0, len(in))
+	for name, q := range in {
+		out = append(out, ResourceQuantity{Resource: string(name), Quantity: q})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Resource < out[j].Resource })
+	return out
+}
+
+// GetDeploymentStatus from the supplied Kubernetes status.
+func GetDeploymentStatus(in appsv1.DeploymentStatus) *DeploymentStatus {
+	return &DeploymentStatus{
+		Conditions:          GetDeploymentConditions(in.Conditions),
+		Replicas:            int(in.Replicas),
+		UpdatedReplicas:     int(in.UpdatedReplicas),
+		ReadyReplicas:       int(in.ReadyReplicas),
+		AvailableReplicas:   int(in.AvailableReplicas),
+		UnavailableReplicas: int(in.UnavailableReplicas),
+	}
+}
+
+// GetDeployment from the supplied Kubernetes Deployment.
+func GetDeployment(d *appsv1.Deployment) Deployment {
+	return Deployment{
+		ID: ReferenceID{
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+			Kind:       "Deployment",
+			Namespace:  d.GetNamespace(),
+			Name:       d.GetName(),
+		},
+		APIVersion:   appsv1.SchemeGroupVersion.String(),
+		Kind:         "Deployment",
+		Metadata:     GetObjectMeta(d),
+		Spec:         GetDeploymentSpec(d.Spec),
+		Status:       GetDeploymentStatus(d.Status),
+		Unstructured: unstruct(d),
+	}
+}
+
+// GetPodConditions from the supplied Kubernetes conditions.
+func GetPodConditions(in []corev1.PodCondition) []Condition {
+	if in == nil {
+		return nil
+	}
+
+	out := make([]Condition, len(in))
+	for i := range in {
+		c := in[i] // So we don't take the address of the range variable.
+		out[i] = Condition{
+			Type:               string(c.Type),
+			Status:             GetConditionStatus(c.Status),
+			LastTransitionTime: c.LastTransitionTime.Time,
+			Reason:             c.Reason,
+		}
+		if c.Message != "" {
+			out[i].Message = &c.Message
+		}
+	}
+	return out
+}
+
+// GetContainerStatus from the supplied Kubernetes container status.
+func GetContainerStatus(in corev1.ContainerStatus) ContainerStatus {
+	out := ContainerStatus{
+		Name:         in.Name,
+		Image:        in.Image,
+		Ready:        in.Ready,
+		RestartCount: int(in.RestartCount),
+	}
+
+	switch s := in.State; {
+	case s.Waiting != nil:
+		out.State = pointer.StringPtr("Waiting")
+		out.Reason = nonEmpty(s.Waiting.Reason)
+		out.Message = nonEmpty(s.Waiting.Message)
+	case s.Running != nil:
+		out.State = pointer.StringPtr("Running")
+	case s.Terminated != nil:
+		out.State = pointer.StringPtr("Terminated")
+		out.Reason = nonEmpty(s.Terminated.Reason)
+		out.Message = nonEmpty(s.Terminated.Message)
+	}
+
+	return out
+}
+
+// GetPodStatus from the supplied Kubernetes status.
+func GetPodStatus(in corev1.PodStatus) *PodStatus {
+	out := &PodStatus{
+		Conditions: GetPodConditions(in.Conditions),
+		Phase:      nonEmpty(string(in.Phase)),
+		Ready:      len(in.ContainerStatuses) > 0,
+	}
+
+	if in.ContainerStatuses != nil {
+		out.ContainerStatuses = make([]ContainerStatus, len(in.ContainerStatuses))
+	}
+	for i := range in.ContainerStatuses {
+		cs := GetContainerStatus(in.ContainerStatuses[i])
+		out.ContainerStatuses[i] = cs
+		out.RestartCount += cs.RestartCount
+		out.Ready = out.Ready && cs.Ready
+	}
+
+	return out
+}
+
+// GetPod from the supplied Kubernetes Pod.
+func GetPod(p *corev1.Pod) Pod {
+	return Pod{
+		ID: ReferenceID{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "Pod",
+			Namespace:  p.GetNamespace(),
+			Name:       p.GetName(),
+		},
+		APIVersion:   corev1.SchemeGroupVersion.String(),
+		Kind:         "Pod",
+		Metadata:     GetObjectMeta(p),
+		Status:       GetPodStatus(p.Status),
+		Unstructured: unstruct(p),
+	}
+}
+
+func nonEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
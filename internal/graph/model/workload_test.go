@@ -0,0 +1,244 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestGetDeployment(t *testing.T) {
+	transition := time.Now()
+
+	cases := map[string]struct {
+		reason string
+		d      *appsv1.Deployment
+		want   Deployment
+	}{
+		"Full": {
+			reason: "All supported fields should be converted to our model",
+			d: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "crossplane-system",
+					Name:      "provider-cool-1234",
+				},
+				Spec: appsv1.DeploymentSpec{
+					Replicas:                pointer.Int32Ptr(2),
+					MinReadySeconds:         10,
+					ProgressDeadlineSeconds: pointer.Int32Ptr(600),
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{
+								Name:  "provider",
+								Image: "cool/provider:v1",
+								Resources: corev1.ResourceRequirements{
+									Limits: corev1.ResourceList{
+										corev1.ResourceMemory: resource.MustParse("1Gi"),
+										corev1.ResourceCPU:    resource.MustParse("1"),
+									},
+									Requests: corev1.ResourceList{
+										corev1.ResourceCPU: resource.MustParse("0.5"),
+									},
+								},
+							}},
+						},
+					},
+				},
+				Status: appsv1.DeploymentStatus{
+					Replicas:            2,
+					UpdatedReplicas:     2,
+					ReadyReplicas:       1,
+					AvailableReplicas:   1,
+					UnavailableReplicas: 1,
+					Conditions: []appsv1.DeploymentCondition{{
+						Type:               appsv1.DeploymentAvailable,
+						Status:             corev1.ConditionFalse,
+						LastTransitionTime: metav1.NewTime(transition),
+						Reason:             "MinimumReplicasUnavailable",
+						Message:            "Deployment does not have minimum availability.",
+					}},
+				},
+			},
+			want: Deployment{
+				ID: ReferenceID{
+					APIVersion: appsv1.SchemeGroupVersion.String(),
+					Kind:       "Deployment",
+					Namespace:  "crossplane-system",
+					Name:       "provider-cool-1234",
+				},
+				APIVersion: appsv1.SchemeGroupVersion.String(),
+				Kind:       "Deployment",
+				Metadata: &ObjectMeta{
+					Namespace: pointer.StringPtr("crossplane-system"),
+					Name:      "provider-cool-1234",
+				},
+				Spec: &DeploymentSpec{
+					Replicas:         intPtr(2),
+					MinReady:         durationPtr(10 * time.Second),
+					ProgressDeadline: durationPtr(10 * time.Minute),
+					Containers: []Container{{
+						Name:  "provider",
+						Image: "cool/provider:v1",
+						Resources: &ResourceRequirements{
+							Limits: []ResourceQuantity{
+								{Resource: "cpu", Quantity: resource.MustParse("1")},
+								{Resource: "memory", Quantity: resource.MustParse("1Gi")},
+							},
+							Requests: []ResourceQuantity{
+								{Resource: "cpu", Quantity: resource.MustParse("0.5")},
+							},
+						},
+					}},
+				},
+				Status: &DeploymentStatus{
+					Replicas:            2,
+					UpdatedReplicas:     2,
+					ReadyReplicas:       1,
+					AvailableReplicas:   1,
+					UnavailableReplicas: 1,
+					Conditions: []Condition{{
+						Type:               string(appsv1.DeploymentAvailable),
+						Status:             ConditionStatusFalse,
+						LastTransitionTime: transition,
+						Reason:             "MinimumReplicasUnavailable",
+						Message:            pointer.StringPtr("Deployment does not have minimum availability."),
+					}},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GetDeployment(tc.d)
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(Deployment{}, "Unstructured"), cmp.AllowUnexported(ObjectMeta{}), cmp.Comparer(func(a, b resource.Quantity) bool { return a.Cmp(b) == 0 })); diff != "" {
+				t.Errorf("\n%s\nGetDeployment(...): -want, +got\n:%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGetPod(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		p      *corev1.Pod
+		want   Pod
+	}{
+		"CrashLooping": {
+			reason: "A pod with a crash looping container should not be ready, and should sum the restarts of its containers.",
+			p: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "crossplane-system",
+					Name:      "provider-cool-1234-abcde",
+				},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					ContainerStatuses: []corev1.ContainerStatus{
+						{
+							Name:         "provider",
+							Image:        "cool/provider:v1",
+							RestartCount: 7,
+							State: corev1.ContainerState{
+								Waiting: &corev1.ContainerStateWaiting{
+									Reason:  "CrashLoopBackOff",
+									Message: "back-off 5m0s restarting failed container",
+								},
+							},
+						},
+						{
+							Name:         "sidecar",
+							Image:        "cool/sidecar:v1",
+							Ready:        true,
+							RestartCount: 1,
+							State: corev1.ContainerState{
+								Running: &corev1.ContainerStateRunning{},
+							},
+						},
+					},
+				},
+			},
+			want: Pod{
+				ID: ReferenceID{
+					APIVersion: corev1.SchemeGroupVersion.String(),
+					Kind:       "Pod",
+					Namespace:  "crossplane-system",
+					Name:       "provider-cool-1234-abcde",
+				},
+				APIVersion: corev1.SchemeGroupVersion.String(),
+				Kind:       "Pod",
+				Metadata: &ObjectMeta{
+					Namespace: pointer.StringPtr("crossplane-system"),
+					Name:      "provider-cool-1234-abcde",
+				},
+				Status: &PodStatus{
+					Phase:        pointer.StringPtr(string(corev1.PodRunning)),
+					Ready:        false,
+					RestartCount: 8,
+					ContainerStatuses: []ContainerStatus{
+						{
+							Name:         "provider",
+							Image:        "cool/provider:v1",
+							RestartCount: 7,
+							State:        pointer.StringPtr("Waiting"),
+							Reason:       pointer.StringPtr("CrashLoopBackOff"),
+							Message:      pointer.StringPtr("back-off 5m0s restarting failed container"),
+						},
+						{
+							Name:         "sidecar",
+							Image:        "cool/sidecar:v1",
+							Ready:        true,
+							RestartCount: 1,
+							State:        pointer.StringPtr("Running"),
+						},
+					},
+				},
+			},
+		},
+		"Empty": {
+			reason: "A pod without containers statuses should not be ready.",
+			p:      &corev1.Pod{},
+			want: Pod{
+				ID: ReferenceID{
+					APIVersion: corev1.SchemeGroupVersion.String(),
+					Kind:       "Pod",
+				},
+				APIVersion: corev1.SchemeGroupVersion.String(),
+				Kind:       "Pod",
+				Metadata:   &ObjectMeta{},
+				Status:     &PodStatus{},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GetPod(tc.p)
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(Pod{}, "Unstructured"), cmp.AllowUnexported(ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\nGetPod(...): -want, +got\n:%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func intPtr(i int) *int                          { return &i }
+func durationPtr(d time.Duration) *time.Duration { return &d }
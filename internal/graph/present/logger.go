@@ -0,0 +1,62 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package present
+
+import (
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+// A scrubber is a logger that scrubs credentials from everything it logs.
+type scrubber struct {
+	wrapped logging.Logger
+}
+
+// ScrubLogger returns a logger that scrubs credentials, e.g. bearer tokens,
+// from the messages and values it logs before passing them to the supplied
+// logger. Values that are strings or errors are scrubbed; they're the only
+// values that are likely to contain credentials.
+func ScrubLogger(l logging.Logger) logging.Logger {
+	if _, ok := l.(*scrubber); ok {
+		return l
+	}
+	return &scrubber{wrapped: l}
+}
+
+func (s *scrubber) Info(msg string, keysAndValues ...interface{}) {
+	s.wrapped.Info(Scrub(msg), scrub(keysAndValues)...)
+}
+
+func (s *scrubber) Debug(msg string, keysAndValues ...interface{}) {
+	s.wrapped.Debug(Scrub(msg), scrub(keysAndValues)...)
+}
+
+func (s *scrubber) WithValues(keysAndValues ...interface{}) logging.Logger {
+	return &scrubber{wrapped: s.wrapped.WithValues(scrub(keysAndValues)...)}
+}
+
+func scrub(keysAndValues []interface{}) []interface{} {
+	out := make([]interface{}, len(keysAndValues))
+	for i, v := range keysAndValues {
+		switch t := v.(type) {
+		case string:
+			out[i] = Scrub(t)
+		case error:
+			out[i] = Scrub(t.Error())
+		default:
+			out[i] = v
+		}
+	}
+	return out
+}
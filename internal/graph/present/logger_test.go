@@ -0,0 +1,70 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package present
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+type recorder struct {
+	values []interface{}
+	lines  [][]interface{}
+}
+
+func (r *recorder) Info(msg string, keysAndValues ...interface{}) {
+	r.lines = append(r.lines, append(append([]interface{}{msg}, r.values...), keysAndValues...))
+}
+
+func (r *recorder) Debug(msg string, keysAndValues ...interface{}) {
+	r.Info(msg, keysAndValues...)
+}
+
+func (r *recorder) WithValues(keysAndValues ...interface{}) logging.Logger {
+	return &recorder{values: append(r.values, keysAndValues...)}
+}
+
+func TestScrubLogger(t *testing.T) {
+	r := &recorder{}
+	l := ScrubLogger(r)
+	l.Info("Rejected Bearer abc.def", "error", errors.New("password=hunter2"), "count", 1)
+
+	want := [][]interface{}{{"Rejected Bearer " + Redacted, "error", "password=" + Redacted, "count", 1}}
+	if diff := cmp.Diff(want, r.lines); diff != "" {
+		t.Errorf("l.Info(...): -want, +got:\n%s", diff)
+	}
+
+	if ScrubLogger(l) != l {
+		t.Errorf("ScrubLogger(...): want an existing scrubbing logger to be returned unchanged")
+	}
+}
+
+func TestScrubLoggerWithValues(t *testing.T) {
+	r := &recorder{}
+	l := ScrubLogger(r).WithValues("uri", "/query?token=abc")
+
+	rr, ok := l.(*scrubber).wrapped.(*recorder)
+	if !ok {
+		t.Fatalf("l.WithValues(...): want wrapped recorder")
+	}
+	want := []interface{}{"uri", "/query?token=" + Redacted}
+	if diff := cmp.Diff(want, rr.values); diff != "" {
+		t.Errorf("l.WithValues(...): -want, +got:\n%s", diff)
+	}
+}
@@ -16,6 +16,8 @@ package present
 
 import (
 	"context"
+	"math"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/pkg/errors"
@@ -38,8 +40,16 @@ const (
 
 	// Code is the error code, if any.
 	Code = "code"
+
+	// RetryAfter is the number of seconds after which the caller may retry,
+	// if any.
+	RetryAfter = "retryAfter"
 )
 
+// CodeUnreachable is the code of errors returned while the API server is
+// considered unreachable.
+const CodeUnreachable = "UNREACHABLE"
+
 // An ErrorSource indicates where an error originated.
 type ErrorSource string
 
@@ -77,8 +87,24 @@ func Extend(ctx context.Context, err error, ext map[string]interface{}) *gqlerro
 	return gerr
 }
 
+// A retrier is an error that tells the caller when they may retry.
+type retrier interface {
+	error
+	RetryAfter() time.Duration
+}
+
 // Error 'presents' errors encountered by GraphQL resolvers.
 func Error(ctx context.Context, err error) *gqlerror.Error {
+	// Clients fail fast without calling the API server while it's unreachable.
+	var r retrier
+	if errors.As(err, &r) {
+		return Extend(ctx, err, map[string]interface{}{
+			Source:     ErrorSourceAPIServer,
+			Code:       CodeUnreachable,
+			RetryAfter: int(math.Ceil(r.RetryAfter().Seconds())),
+		})
+	}
+
 	s := kerrors.APIStatus(nil)
 
 	// This does not appear to be an error from the API server.
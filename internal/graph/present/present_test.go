@@ -17,6 +17,7 @@ package present
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
@@ -90,3 +91,22 @@ func TestError(t *testing.T) {
 		})
 	}
 }
+
+type errRetry time.Duration
+
+func (e errRetry) Error() string             { return "unreachable" }
+func (e errRetry) RetryAfter() time.Duration { return time.Duration(e) }
+
+func TestErrorRetryAfter(t *testing.T) {
+	err := errors.Wrap(errRetry(1500*time.Millisecond), "cannot get client")
+	got := Error(context.Background(), err)
+
+	want := map[string]interface{}{
+		Source:     ErrorSourceAPIServer,
+		Code:       CodeUnreachable,
+		RetryAfter: 2,
+	}
+	if diff := cmp.Diff(want, got.Extensions); diff != "" {
+		t.Errorf("Error(...): -want extensions, +got extensions\n%s", diff)
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package present
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/upbound/xgql/internal/request"
+)
+
+// Redacted replaces sensitive details in error messages.
+const Redacted = "[REDACTED]"
+
+// msgInternal replaces the messages of errors that did not originate at the
+// API server when using the strict redaction policy.
+const msgInternal = "internal error"
+
+// A RedactionPolicy determines what is redacted from the error messages that
+// are sent to clients.
+type RedactionPolicy string
+
+// Redaction policies.
+const (
+	// RedactNone does not redact error messages, except to scrub any
+	// credentials from them.
+	RedactNone RedactionPolicy = "none"
+
+	// RedactSensitive redacts credentials, file paths, and dumps of objects
+	// from error messages.
+	RedactSensitive RedactionPolicy = "sensitive"
+
+	// RedactStrict redacts error messages per RedactSensitive, and replaces
+	// the messages of errors that did not originate at the API server with a
+	// generic message.
+	RedactStrict RedactionPolicy = "strict"
+)
+
+// RedactionPolicies that may be configured.
+var RedactionPolicies = []string{string(RedactNone), string(RedactSensitive), string(RedactStrict)}
+
+type redaction struct {
+	re   *regexp.Regexp
+	with string
+}
+
+// Credentials, in the order they're scrubbed.
+var credentials = []redaction{
+	// Authorization header values, e.g. "Bearer eyJh...".
+	{re: regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9\-._~+/]+=*`), with: "$1 " + Redacted},
+
+	// JSON web tokens, e.g. Kubernetes service account tokens.
+	{re: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`), with: Redacted},
+
+	// Key value pairs that look like credentials, e.g. token="abc". We don't
+	// match token: abc because it's indistinguishable from a wrapped error.
+	{re: regexp.MustCompile(`(?i)\b(token|password|secret)(\s*=\s*["']?|["']\s*:\s*["']?|:\s*["'])[^\s"',}]+`), with: "$1$2" + Redacted},
+}
+
+// Other sensitive details, in the order they're redacted once credentials have
+// been scrubbed.
+var sensitive = []redaction{
+	// Absolute file paths. We require the path to follow whitespace or a
+	// quote so that the paths of URLs are not redacted.
+	{re: regexp.MustCompile(`(^|[\s"'(=])/[\w.\-]+(?:/[\w.\-]+)+`), with: "$1" + Redacted},
+}
+
+// literal matches the start of a Go representation of a struct or map, e.g. an
+// object that failed to decode, up to and including its opening bracket.
+var literal = regexp.MustCompile(`&?[A-Za-z_][\w.]*\{[A-Za-z_]\w*:|map\[`)
+
+// mapValues matches the element type and opening brace of the values that
+// follow the key type of a map printed with %#v.
+var mapValues = regexp.MustCompile(`^[\w.*\[\]]*\{`)
+
+// Scrub credentials, e.g. bearer tokens, from the supplied message.
+func Scrub(msg string) string {
+	for _, r := range credentials {
+		msg = r.re.ReplaceAllString(msg, r.with)
+	}
+	return msg
+}
+
+// Redact credentials and other sensitive details from the supplied error
+// message.
+func Redact(msg string) string {
+	msg = Scrub(msg)
+	for _, r := range sensitive {
+		msg = r.re.ReplaceAllString(msg, r.with)
+	}
+	return redactLiterals(msg)
+}
+
+// redactLiterals redacts Go representations of structs and maps. A regular
+// expression can't match balanced brackets, so we find where each literal
+// starts and scan for where it ends.
+func redactLiterals(msg string) string {
+	out := strings.Builder{}
+	for {
+		loc := literal.FindStringIndex(msg)
+		if loc == nil {
+			out.WriteString(msg)
+			return out.String()
+		}
+		out.WriteString(msg[:loc[0]])
+		out.WriteString(Redacted)
+
+		// The literal opens with the first bracket we matched.
+		open := loc[0] + strings.IndexAny(msg[loc[0]:], "{[")
+		end := closing(msg, open)
+
+		// A map printed with %#v is followed by its element type and values,
+		// e.g. map[string]int{"a":1}, which are part of the literal.
+		if v := mapValues.FindStringIndex(msg[end:]); msg[open] == '[' && v != nil {
+			end = closing(msg, end+v[1]-1)
+		}
+		msg = msg[end:]
+	}
+}
+
+// closing returns the index just past the bracket that balances the bracket at
+// index open of the supplied string, ignoring brackets within quoted strings.
+// It returns the length of the string if the bracket is never balanced, for
+// example because the message was truncated.
+func closing(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '{', '[', '(':
+			depth++
+		case '}', ']', ')':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		case '"':
+			// Skip to the end of the quoted string, minding escapes.
+			for i++; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' {
+					i++
+				}
+			}
+		}
+	}
+	return len(s)
+}
+
+// A Redactor presents errors encountered by GraphQL resolvers, redacting their
+// messages according to its policy. Errors are logged before they're redacted,
+// so its logger should scrub credentials; see ScrubLogger.
+type Redactor struct {
+	policy RedactionPolicy
+	log    logging.Logger
+}
+
+// NewRedactor returns a Redactor that uses the supplied policy and logger.
+func NewRedactor(p RedactionPolicy, l logging.Logger) *Redactor {
+	return &Redactor{policy: p, log: l}
+}
+
+// Error 'presents' errors encountered by GraphQL resolvers per Error, then
+// redacts their messages.
+func (r *Redactor) Error(ctx context.Context, err error) *gqlerror.Error {
+	gerr := Error(ctx, err)
+	if r.policy == RedactNone {
+		gerr.Message = Scrub(gerr.Message)
+		return gerr
+	}
+
+	msg := Redact(gerr.Message)
+	if r.policy == RedactStrict && gerr.Extensions[Source] != ErrorSourceAPIServer {
+		msg = msgInternal
+	}
+	if msg == gerr.Message {
+		return gerr
+	}
+
+	r.log.Info("Redacted error message",
+		"request-id", request.ID(ctx),
+		"path", gerr.Path.String(),
+		"error", gerr.Message,
+	)
+	gerr.Message = msg
+	return gerr
+}
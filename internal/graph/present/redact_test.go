@@ -0,0 +1,153 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package present
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+func TestRedact(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		msg    string
+		want   string
+	}{
+		"Nothing": {
+			reason: "Messages without sensitive details should be unchanged.",
+			msg:    `providers.pkg.crossplane.io "cool" is forbidden`,
+			want:   `providers.pkg.crossplane.io "cool" is forbidden`,
+		},
+		"WrappedError": {
+			reason: "Wrapped errors should not be mistaken for credentials.",
+			msg:    `cannot get secret: secrets "cool" not found`,
+			want:   `cannot get secret: secrets "cool" not found`,
+		},
+		"URL": {
+			reason: "The paths of URLs should not be redacted.",
+			msg:    `Get "https://10.0.0.1/apis/pkg.crossplane.io/v1/providers": Unauthorized`,
+			want:   `Get "https://10.0.0.1/apis/pkg.crossplane.io/v1/providers": Unauthorized`,
+		},
+		"BearerToken": {
+			reason: "Bearer tokens should be redacted.",
+			msg:    "invalid header: Bearer abc.def-123==",
+			want:   "invalid header: Bearer " + Redacted,
+		},
+		"JWT": {
+			reason: "JSON web tokens should be redacted.",
+			msg:    "token eyJhbGciOi.eyJzdWIiOi.c2ln rejected",
+			want:   "token " + Redacted + " rejected",
+		},
+		"FilePath": {
+			reason: "Absolute file paths should be redacted.",
+			msg:    "open /var/run/secrets/kubernetes.io/serviceaccount/token: no such file or directory",
+			want:   "open " + Redacted + ": no such file or directory",
+		},
+		"Credentials": {
+			reason: "Values that appear to be credentials should be redacted.",
+			msg:    `config has password=hunter2, token: "abc"`,
+			want:   `config has password=` + Redacted + `, token: "` + Redacted + `"`,
+		},
+		"ObjectDump": {
+			reason: "Go representations of objects should be redacted.",
+			msg:    `cannot decode: &v1.Secret{TypeMeta:v1.TypeMeta{Kind:"Secret"}}`,
+			want:   "cannot decode: " + Redacted,
+		},
+		"ObjectDumpFollowedByText": {
+			reason: "Only the Go representation of an object should be redacted, not any text that follows it.",
+			msg:    `cannot decode &v1.Secret{TypeMeta:v1.TypeMeta{Kind:"Secret"}, Data:"}"}: invalid type`,
+			want:   "cannot decode " + Redacted + ": invalid type",
+		},
+		"MapDump": {
+			reason: "Go representations of maps should be redacted, including their element types and values.",
+			msg:    `cannot use map[string]int{"a":1} as labels; also map[b:2] is invalid`,
+			want:   "cannot use " + Redacted + " as labels; also " + Redacted + " is invalid",
+		},
+		"TruncatedObjectDump": {
+			reason: "A Go representation of an object that is never closed should be redacted to the end of the message.",
+			msg:    `cannot decode: &v1.Secret{TypeMeta:v1.TypeMeta{Kind:"Secret"`,
+			want:   "cannot decode: " + Redacted,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Redact(tc.msg)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("%s\nRedact(...): -want, +got\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRedactorError(t *testing.T) {
+	errSensitive := errors.New("open /etc/xgql/tls.key: permission denied")
+	errForbidden := kerrors.NewForbidden(schema.GroupResource{Group: "pkg.crossplane.io", Resource: "providers"}, "cool", errors.New("nope"))
+
+	cases := map[string]struct {
+		reason string
+		policy RedactionPolicy
+		err    error
+		want   string
+	}{
+		"None": {
+			reason: "Errors should not be redacted when redaction is disabled.",
+			policy: RedactNone,
+			err:    errSensitive,
+			want:   errSensitive.Error(),
+		},
+		"NoneCredentials": {
+			reason: "Credentials should be scrubbed even when redaction is disabled.",
+			policy: RedactNone,
+			err:    errors.New("cannot authenticate using Bearer abc.def"),
+			want:   "cannot authenticate using Bearer " + Redacted,
+		},
+		"Sensitive": {
+			reason: "Sensitive details should be redacted.",
+			policy: RedactSensitive,
+			err:    errSensitive,
+			want:   "open " + Redacted + ": permission denied",
+		},
+		"StrictUnknown": {
+			reason: "Errors that did not originate at the API server should be masked by the strict policy.",
+			policy: RedactStrict,
+			err:    errSensitive,
+			want:   msgInternal,
+		},
+		"StrictAPIServer": {
+			reason: "Errors that originated at the API server should not be masked by the strict policy.",
+			policy: RedactStrict,
+			err:    errForbidden,
+			want:   errForbidden.Error(),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewRedactor(tc.policy, logging.NewNopLogger())
+			got := r.Error(context.Background(), tc.err)
+			if diff := cmp.Diff(tc.want, got.Message); diff != "" {
+				t.Errorf("%s\nr.Error(...): -want message, +got message\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
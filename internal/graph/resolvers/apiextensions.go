@@ -20,7 +20,7 @@ import (
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
+	kextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -31,32 +31,39 @@ import (
 	"github.com/upbound/xgql/internal/auth"
 	"github.com/upbound/xgql/internal/clients"
 	"github.com/upbound/xgql/internal/graph/model"
+	"github.com/upbound/xgql/internal/unstructured"
 )
 
 const (
-	errListResources = "cannot list defined resources"
+	errListResources   = "cannot list defined resources"
+	errListComposites  = "cannot list composite resources"
+	errNoCompositeType = "composition does not specify a composite type"
 )
 
 type xrd struct {
 	clients ClientCache
 }
 
-func (r *xrd) Events(ctx context.Context, obj *model.CompositeResourceDefinition) (*model.EventConnection, error) {
+func (r *xrd) Events(ctx context.Context, obj *model.CompositeResourceDefinition, limit *int) (*model.EventConnection, error) {
 	e := &events{clients: r.clients}
-	return e.Resolve(ctx, &corev1.ObjectReference{
-		APIVersion: obj.APIVersion,
-		Kind:       obj.Kind,
-		Name:       obj.Metadata.Name,
-		UID:        types.UID(obj.Metadata.UID),
-	})
+	return e.Resolve(ctx, involved(obj.ID, obj.Metadata), limit)
 }
 
-func (r *xrd) DefinedCompositeResources(ctx context.Context, obj *model.CompositeResourceDefinition, version *string) (*model.CompositeResourceConnection, error) {
+func (r *xrd) DefinedCompositeResources(ctx context.Context, obj *model.CompositeResourceDefinition, version, namespace *string) (*model.CompositeResourceConnection, error) {
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	// Crossplane v2 composite resources may be namespaced. Listing cluster
+	// scoped composite resources in a namespace will return nothing.
+	gopts := []clients.GetOption{}
+	lopts := []client.ListOption{}
+	if namespace != nil {
+		gopts = []clients.GetOption{clients.ForNamespace(*namespace)}
+		lopts = []client.ListOption{client.InNamespace(*namespace)}
+	}
+
 	creds, _ := auth.FromContext(ctx)
-	c, err := r.clients.Get(creds)
+	c, err := r.clients.Get(creds, gopts...)
 	if err != nil {
 		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
 		return nil, nil
@@ -77,7 +84,7 @@ func (r *xrd) DefinedCompositeResources(ctx context.Context, obj *model.Composit
 		in.SetKind(*lk)
 	}
 
-	if err := c.List(ctx, in); err != nil {
+	if err := c.List(ctx, in, lopts...); err != nil {
 		graphql.AddError(ctx, errors.Wrap(err, errListResources))
 		return nil, nil
 	}
@@ -178,8 +185,34 @@ type xrdSpec struct {
 	clients ClientCache
 }
 
+func (r *xrdSpec) Scope(ctx context.Context, obj *model.CompositeResourceDefinitionSpec) (*model.ResourceScope, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	// The XRD API we build against predates Crossplane v2, and thus doesn't
+	// know about the scope field. Instead we determine the scope of the
+	// defined composite resource from the CRD Crossplane creates for it, which
+	// is always named the same as the XRD.
+	crd := &kextv1.CustomResourceDefinition{}
+	nn := types.NamespacedName{Name: obj.Names.Plural + "." + obj.Group}
+	if err := c.Get(ctx, nn, crd); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetCRD))
+		return nil, nil
+	}
+
+	out := model.GetResourceScope(crd.Spec.Scope)
+	return &out, nil
+}
+
 func (r *xrdSpec) DefaultComposition(ctx context.Context, obj *model.CompositeResourceDefinitionSpec) (*model.Composition, error) {
-	if obj.DefaultCompositionReference == nil {
+	if obj.DefaultCompositionRef == nil {
 		return nil, nil
 	}
 	ctx, cancel := context.WithTimeout(ctx, timeout)
@@ -193,7 +226,7 @@ func (r *xrdSpec) DefaultComposition(ctx context.Context, obj *model.CompositeRe
 	}
 
 	cmp := &extv1.Composition{}
-	nn := types.NamespacedName{Name: obj.DefaultCompositionReference.Name}
+	nn := types.NamespacedName{Name: obj.DefaultCompositionRef.Name}
 	if err := c.Get(ctx, nn, cmp); err != nil {
 		graphql.AddError(ctx, errors.Wrap(err, errGetComposition))
 		return nil, nil
@@ -204,7 +237,7 @@ func (r *xrdSpec) DefaultComposition(ctx context.Context, obj *model.CompositeRe
 }
 
 func (r *xrdSpec) EnforcedComposition(ctx context.Context, obj *model.CompositeResourceDefinitionSpec) (*model.Composition, error) {
-	if obj.EnforcedCompositionReference == nil {
+	if obj.EnforcedCompositionRef == nil {
 		return nil, nil
 	}
 	ctx, cancel := context.WithTimeout(ctx, timeout)
@@ -218,7 +251,7 @@ func (r *xrdSpec) EnforcedComposition(ctx context.Context, obj *model.CompositeR
 	}
 
 	cmp := &extv1.Composition{}
-	nn := types.NamespacedName{Name: obj.EnforcedCompositionReference.Name}
+	nn := types.NamespacedName{Name: obj.EnforcedCompositionRef.Name}
 	if err := c.Get(ctx, nn, cmp); err != nil {
 		graphql.AddError(ctx, errors.Wrap(err, errGetComposition))
 		return nil, nil
@@ -228,16 +261,141 @@ func (r *xrdSpec) EnforcedComposition(ctx context.Context, obj *model.CompositeR
 	return &out, nil
 }
 
+func (r *xrdSpec) DefaultCompositeDeletePolicy(ctx context.Context, obj *model.CompositeResourceDefinitionSpec) (*model.CompositeDeletePolicy, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	// The XRD API we build against predates the defaultCompositeDeletePolicy
+	// field, so we read it from the unstructured XRD. An XRD is always named
+	// <names.plural>.<group>.
+	u := &kunstructured.Unstructured{}
+	u.SetGroupVersionKind(extv1.CompositeResourceDefinitionGroupVersionKind)
+	nn := types.NamespacedName{Name: obj.Names.Plural + "." + obj.Group}
+	if err := c.Get(ctx, nn, u); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetXRD))
+		return nil, nil
+	}
+
+	p, _, _ := kunstructured.NestedString(u.Object, "spec", "defaultCompositeDeletePolicy")
+	return model.GetCompositeDeletePolicy(p), nil
+}
+
+type xrdVersion struct {
+	clients ClientCache
+}
+
+// get the unstructured version of the XRD that defines the supplied version.
+// The XRD API we build against predates some version fields, so we must read
+// them from the unstructured XRD.
+func (r *xrdVersion) get(ctx context.Context, obj *model.CompositeResourceDefinitionVersion) (map[string]interface{}, bool) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, false
+	}
+
+	u := &kunstructured.Unstructured{}
+	u.SetGroupVersionKind(extv1.CompositeResourceDefinitionGroupVersionKind)
+	if err := c.Get(ctx, types.NamespacedName{Name: obj.DefinitionName}, u); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetXRD))
+		return nil, false
+	}
+
+	vs, _, _ := kunstructured.NestedSlice(u.Object, "spec", "versions")
+	for i := range vs {
+		v, ok := vs[i].(map[string]interface{})
+		if ok && v["name"] == obj.Name {
+			return v, true
+		}
+	}
+	return nil, true
+}
+
+func (r *xrdVersion) Deprecated(ctx context.Context, obj *model.CompositeResourceDefinitionVersion) (*bool, error) {
+	v, ok := r.get(ctx, obj)
+	if !ok {
+		return nil, nil
+	}
+	d, _, _ := kunstructured.NestedBool(v, "deprecated")
+	return &d, nil
+}
+
+func (r *xrdVersion) DeprecationWarning(ctx context.Context, obj *model.CompositeResourceDefinitionVersion) (*string, error) {
+	v, ok := r.get(ctx, obj)
+	if !ok {
+		return nil, nil
+	}
+	w, found, _ := kunstructured.NestedString(v, "deprecationWarning")
+	if !found {
+		return nil, nil
+	}
+	return &w, nil
+}
+
 type composition struct {
 	clients ClientCache
 }
 
-func (r *composition) Events(ctx context.Context, obj *model.Composition) (*model.EventConnection, error) {
+func (r *composition) Events(ctx context.Context, obj *model.Composition, limit *int) (*model.EventConnection, error) {
 	e := &events{clients: r.clients}
-	return e.Resolve(ctx, &corev1.ObjectReference{
-		APIVersion: obj.APIVersion,
-		Kind:       obj.Kind,
-		Name:       obj.Metadata.Name,
-		UID:        types.UID(obj.Metadata.UID),
-	})
+	return e.Resolve(ctx, involved(obj.ID, obj.Metadata), limit)
+}
+
+func (r *composition) CompositeResources(ctx context.Context, obj *model.Composition, limit, offset *int) (*model.CompositeResourceConnection, error) {
+	if obj.Spec == nil || obj.Spec.CompositeTypeRef == nil {
+		graphql.AddError(ctx, errors.New(errNoCompositeType))
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	// Composite resources may be namespaced, in which case we list them in
+	// all namespaces.
+	in := &kunstructured.UnstructuredList{}
+	in.SetAPIVersion(obj.Spec.CompositeTypeRef.APIVersion)
+	in.SetKind(obj.Spec.CompositeTypeRef.Kind + "List")
+	if err := c.List(ctx, in); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errListComposites))
+		return nil, nil
+	}
+
+	out := &model.CompositeResourceConnection{
+		Nodes: make([]model.CompositeResource, 0),
+	}
+
+	for i := range in.Items {
+		xr := &unstructured.Composite{Unstructured: in.Items[i]}
+
+		// This composite resource doesn't use this composition.
+		if ref := xr.GetCompositionReference(); ref == nil || ref.Name != obj.Metadata.Name {
+			continue
+		}
+
+		out.Nodes = append(out.Nodes, model.GetCompositeResource(&in.Items[i]))
+		out.TotalCount++
+	}
+
+	sort.Stable(out)
+	lo, hi := page(ctx, limit, offset, len(out.Nodes))
+	out.Nodes = out.Nodes[lo:hi]
+	return out, nil
 }
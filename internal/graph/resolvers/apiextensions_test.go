@@ -23,12 +23,12 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 	"github.com/vektah/gqlparser/v2/gqlerror"
+	kextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	extv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 
@@ -39,9 +39,10 @@ import (
 )
 
 var (
-	_ generated.CompositeResourceDefinitionResolver     = &xrd{}
-	_ generated.CompositeResourceDefinitionSpecResolver = &xrdSpec{}
-	_ generated.CompositionResolver                     = &composition{}
+	_ generated.CompositeResourceDefinitionResolver        = &xrd{}
+	_ generated.CompositeResourceDefinitionSpecResolver    = &xrdSpec{}
+	_ generated.CompositeResourceDefinitionVersionResolver = &xrdVersion{}
+	_ generated.CompositionResolver                        = &composition{}
 )
 
 func TestXRDDefinedCompositeResources(t *testing.T) {
@@ -59,9 +60,10 @@ func TestXRDDefinedCompositeResources(t *testing.T) {
 	listKind := "Examples"
 
 	type args struct {
-		ctx     context.Context
-		obj     *model.CompositeResourceDefinition
-		version *string
+		ctx       context.Context
+		obj       *model.CompositeResourceDefinition
+		version   *string
+		namespace *string
 	}
 	type want struct {
 		crc  *model.CompositeResourceConnection
@@ -262,6 +264,39 @@ func TestXRDDefinedCompositeResources(t *testing.T) {
 				},
 			},
 		},
+		"SpecificNamespace": {
+			reason: "We should successfully return any defined resources in the requested namespace that we can list and model.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						*obj.(*unstructured.UnstructuredList) = unstructured.UnstructuredList{Items: []unstructured.Unstructured{xr}}
+						return nil
+					}),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CompositeResourceDefinition{
+					Spec: &model.CompositeResourceDefinitionSpec{
+						Group: group,
+						Names: &model.CompositeResourceDefinitionNames{Kind: kind},
+						Versions: []model.CompositeResourceDefinitionVersion{
+							{
+								Name:          version,
+								Referenceable: true,
+							},
+						},
+					},
+				},
+				namespace: pointer.StringPtr("default"),
+			},
+			want: want{
+				crc: &model.CompositeResourceConnection{
+					Nodes:      []model.CompositeResource{gxr},
+					TotalCount: 1,
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -270,7 +305,7 @@ func TestXRDDefinedCompositeResources(t *testing.T) {
 
 			// Our GraphQL resolvers never return errors. We instead add an
 			// error to the GraphQL context and return early.
-			got, err := x.DefinedCompositeResources(tc.args.ctx, tc.args.obj, tc.args.version)
+			got, err := x.DefinedCompositeResources(tc.args.ctx, tc.args.obj, tc.args.version, tc.args.namespace)
 			errs := graphql.GetErrors(tc.args.ctx)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
@@ -546,6 +581,113 @@ func TestXRDDefinedCompositeResourceClaims(t *testing.T) {
 	}
 }
 
+func TestCompositeResourceDefinitionSpecScope(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	namespaced := model.ResourceScopeNamespaceScoped
+
+	type args struct {
+		ctx context.Context
+		obj *model.CompositeResourceDefinitionSpec
+	}
+	type want struct {
+		scope *model.ResourceScope
+		err   error
+		errs  gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"GetClientError": {
+			reason: "If we can't get a client we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{}, errBoom
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CompositeResourceDefinitionSpec{
+					Names: &model.CompositeResourceDefinitionNames{},
+				},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetClient).Error()),
+				},
+			},
+		},
+		"GetCRDError": {
+			reason: "If we can't get the defined CRD we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CompositeResourceDefinitionSpec{
+					Names: &model.CompositeResourceDefinitionNames{},
+				},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetCRD).Error()),
+				},
+			},
+		},
+		"Success": {
+			reason: "If we can get the defined CRD we should return its scope.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+						if diff := cmp.Diff("examples.example.org", key.Name); diff != "" {
+							t.Errorf("-want CRD name, +got CRD name:\n%s", diff)
+						}
+						*obj.(*kextv1.CustomResourceDefinition) = kextv1.CustomResourceDefinition{
+							Spec: kextv1.CustomResourceDefinitionSpec{Scope: kextv1.NamespaceScoped},
+						}
+						return nil
+					},
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CompositeResourceDefinitionSpec{
+					Group: "example.org",
+					Names: &model.CompositeResourceDefinitionNames{Plural: "examples"},
+				},
+			},
+			want: want{
+				scope: &namespaced,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := &xrdSpec{clients: tc.clients}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := s.Scope(tc.args.ctx, tc.args.obj)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.Scope(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.Scope(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.scope, got); diff != "" {
+				t.Errorf("\n%s\ns.Scope(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestCompositeResourceDefinitionSpecDefaultComposition(t *testing.T) {
 	errBoom := errors.New("boom")
 
@@ -583,7 +725,7 @@ func TestCompositeResourceDefinitionSpecDefaultComposition(t *testing.T) {
 			args: args{
 				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
 				obj: &model.CompositeResourceDefinitionSpec{
-					DefaultCompositionReference: &xpv1.Reference{},
+					DefaultCompositionRef: &model.CompositionReference{},
 				},
 			},
 			want: want{
@@ -602,7 +744,7 @@ func TestCompositeResourceDefinitionSpecDefaultComposition(t *testing.T) {
 			args: args{
 				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
 				obj: &model.CompositeResourceDefinitionSpec{
-					DefaultCompositionReference: &xpv1.Reference{},
+					DefaultCompositionRef: &model.CompositionReference{},
 				},
 			},
 			want: want{
@@ -621,7 +763,7 @@ func TestCompositeResourceDefinitionSpecDefaultComposition(t *testing.T) {
 			args: args{
 				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
 				obj: &model.CompositeResourceDefinitionSpec{
-					DefaultCompositionReference: &xpv1.Reference{},
+					DefaultCompositionRef: &model.CompositionReference{},
 				},
 			},
 			want: want{
@@ -689,7 +831,7 @@ func TestCompositeResourceDefinitionSpecEnforcedComposition(t *testing.T) {
 			args: args{
 				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
 				obj: &model.CompositeResourceDefinitionSpec{
-					EnforcedCompositionReference: &xpv1.Reference{},
+					EnforcedCompositionRef: &model.CompositionReference{},
 				},
 			},
 			want: want{
@@ -708,7 +850,7 @@ func TestCompositeResourceDefinitionSpecEnforcedComposition(t *testing.T) {
 			args: args{
 				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
 				obj: &model.CompositeResourceDefinitionSpec{
-					EnforcedCompositionReference: &xpv1.Reference{},
+					EnforcedCompositionRef: &model.CompositionReference{},
 				},
 			},
 			want: want{
@@ -727,7 +869,7 @@ func TestCompositeResourceDefinitionSpecEnforcedComposition(t *testing.T) {
 			args: args{
 				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
 				obj: &model.CompositeResourceDefinitionSpec{
-					EnforcedCompositionReference: &xpv1.Reference{},
+					EnforcedCompositionRef: &model.CompositionReference{},
 				},
 			},
 			want: want{
@@ -757,3 +899,461 @@ func TestCompositeResourceDefinitionSpecEnforcedComposition(t *testing.T) {
 		})
 	}
 }
+
+func TestCompositeResourceDefinitionSpecDefaultCompositeDeletePolicy(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	foreground := model.CompositeDeletePolicyForeground
+
+	type args struct {
+		ctx context.Context
+		obj *model.CompositeResourceDefinitionSpec
+	}
+	type want struct {
+		policy *model.CompositeDeletePolicy
+		err    error
+		errs   gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"GetClientError": {
+			reason: "If we can't get a client we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{}, errBoom
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CompositeResourceDefinitionSpec{
+					Names: &model.CompositeResourceDefinitionNames{},
+				},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetClient).Error()),
+				},
+			},
+		},
+		"GetXRDError": {
+			reason: "If we can't get the XRD we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CompositeResourceDefinitionSpec{
+					Names: &model.CompositeResourceDefinitionNames{},
+				},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetXRD).Error()),
+				},
+			},
+		},
+		"NoPolicy": {
+			reason: "If the XRD does not specify a policy we should return nil.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CompositeResourceDefinitionSpec{
+					Names: &model.CompositeResourceDefinitionNames{},
+				},
+			},
+			want: want{},
+		},
+		"Success": {
+			reason: "If we can get the XRD we should return its default composite delete policy.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+						if diff := cmp.Diff("examples.example.org", key.Name); diff != "" {
+							t.Errorf("-want XRD name, +got XRD name:\n%s", diff)
+						}
+						u := obj.(*unstructured.Unstructured)
+						return unstructured.SetNestedField(u.Object, "Foreground", "spec", "defaultCompositeDeletePolicy")
+					},
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CompositeResourceDefinitionSpec{
+					Group: "example.org",
+					Names: &model.CompositeResourceDefinitionNames{Plural: "examples"},
+				},
+			},
+			want: want{
+				policy: &foreground,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := &xrdSpec{clients: tc.clients}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := s.DefaultCompositeDeletePolicy(tc.args.ctx, tc.args.obj)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.DefaultCompositeDeletePolicy(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.DefaultCompositeDeletePolicy(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.policy, got); diff != "" {
+				t.Errorf("\n%s\ns.DefaultCompositeDeletePolicy(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCompositeResourceDefinitionVersionDeprecated(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		ctx context.Context
+		obj *model.CompositeResourceDefinitionVersion
+	}
+	type want struct {
+		deprecated *bool
+		err        error
+		errs       gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"GetClientError": {
+			reason: "If we can't get a client we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{}, errBoom
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CompositeResourceDefinitionVersion{},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetClient).Error()),
+				},
+			},
+		},
+		"GetXRDError": {
+			reason: "If we can't get the XRD we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CompositeResourceDefinitionVersion{},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetXRD).Error()),
+				},
+			},
+		},
+		"NotDeprecated": {
+			reason: "A version that does not specify whether it is deprecated is not deprecated.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CompositeResourceDefinitionVersion{Name: "v1"},
+			},
+			want: want{
+				deprecated: pointer.BoolPtr(false),
+			},
+		},
+		"Deprecated": {
+			reason: "We should return whether the version is deprecated.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+						if diff := cmp.Diff("examples.example.org", key.Name); diff != "" {
+							t.Errorf("-want XRD name, +got XRD name:\n%s", diff)
+						}
+						u := obj.(*unstructured.Unstructured)
+						return unstructured.SetNestedSlice(u.Object, []interface{}{
+							map[string]interface{}{"name": "v1", "deprecated": true},
+							map[string]interface{}{"name": "v2"},
+						}, "spec", "versions")
+					},
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CompositeResourceDefinitionVersion{
+					Name:           "v1",
+					DefinitionName: "examples.example.org",
+				},
+			},
+			want: want{
+				deprecated: pointer.BoolPtr(true),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := &xrdVersion{clients: tc.clients}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := v.Deprecated(tc.args.ctx, tc.args.obj)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nv.Deprecated(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nv.Deprecated(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.deprecated, got); diff != "" {
+				t.Errorf("\n%s\nv.Deprecated(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCompositeResourceDefinitionVersionDeprecationWarning(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		obj *model.CompositeResourceDefinitionVersion
+	}
+	type want struct {
+		warning *string
+		err     error
+		errs    gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"NoWarning": {
+			reason: "If the version has no deprecation warning we should return nil.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CompositeResourceDefinitionVersion{Name: "v1"},
+			},
+			want: want{},
+		},
+		"Warning": {
+			reason: "We should return the version's deprecation warning.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						u := obj.(*unstructured.Unstructured)
+						return unstructured.SetNestedSlice(u.Object, []interface{}{
+							map[string]interface{}{"name": "v1", "deprecated": true, "deprecationWarning": "use v2"},
+						}, "spec", "versions")
+					}),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CompositeResourceDefinitionVersion{Name: "v1"},
+			},
+			want: want{
+				warning: pointer.StringPtr("use v2"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := &xrdVersion{clients: tc.clients}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := v.DeprecationWarning(tc.args.ctx, tc.args.obj)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nv.DeprecationWarning(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nv.DeprecationWarning(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.warning, got); diff != "" {
+				t.Errorf("\n%s\nv.DeprecationWarning(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCompositionCompositeResources(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	xr := func(name, composition string, v2 bool) unstructured.Unstructured {
+		spec := map[string]interface{}{
+			"compositionRef": map[string]interface{}{"name": composition},
+		}
+		if v2 {
+			spec = map[string]interface{}{"crossplane": spec}
+		}
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "example.org/v1",
+			"kind":       "XCool",
+			"metadata":   map[string]interface{}{"name": name},
+			"spec":       spec,
+		}}
+	}
+
+	ours := xr("ours", "cool", false)
+	oursv2 := xr("oursv2", "cool", true)
+	theirs := xr("theirs", "uncool", false)
+	gours := model.GetCompositeResource(&ours)
+	goursv2 := model.GetCompositeResource(&oursv2)
+
+	comp := &model.Composition{
+		Metadata: &model.ObjectMeta{Name: "cool"},
+		Spec: &model.CompositionSpec{
+			CompositeTypeRef: &model.TypeReference{APIVersion: "example.org/v1", Kind: "XCool"},
+		},
+	}
+
+	type args struct {
+		ctx context.Context
+		obj *model.Composition
+	}
+	type want struct {
+		xrc  *model.CompositeResourceConnection
+		err  error
+		errs gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"NoCompositeType": {
+			reason: "If the composition doesn't specify a composite type we should add an error to the GraphQL context and return early.",
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.Composition{},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errNoCompositeType),
+				},
+			},
+		},
+		"GetClientError": {
+			reason: "If we can't get a client we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{}, errBoom
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: comp,
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetClient).Error()),
+				},
+			},
+		},
+		"ListCompositesError": {
+			reason: "If we can't list composite resources we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: comp,
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errListComposites).Error()),
+				},
+			},
+		},
+		"Success": {
+			reason: "We should return only the composite resources that reference our composition.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						u := *obj.(*unstructured.UnstructuredList)
+
+						// Ensure we're being asked to list the expected GVK.
+						got := u.GetObjectKind().GroupVersionKind()
+						want := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XCoolList"}
+						if diff := cmp.Diff(want, got); diff != "" {
+							t.Errorf("-want GVK, +got GVK:\n%s", diff)
+						}
+
+						*obj.(*unstructured.UnstructuredList) = unstructured.UnstructuredList{
+							Items: []unstructured.Unstructured{theirs, oursv2, ours},
+						}
+						return nil
+					}),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: comp,
+			},
+			want: want{
+				xrc: &model.CompositeResourceConnection{
+					Nodes:      []model.CompositeResource{gours, goursv2},
+					TotalCount: 2,
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			x := &composition{clients: tc.clients}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := x.CompositeResources(tc.args.ctx, tc.args.obj, nil, nil)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nx.CompositeResources(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nx.CompositeResources(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.xrc, got, cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\nx.CompositeResources(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
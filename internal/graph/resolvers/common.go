@@ -17,83 +17,154 @@ package resolvers
 import (
 	"context"
 	"sort"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
 	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/json"
+	kversion "k8s.io/apimachinery/pkg/version"
 	"k8s.io/utils/pointer"
 
+	extv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+
 	"github.com/upbound/xgql/internal/auth"
 	"github.com/upbound/xgql/internal/graph/model"
 )
 
 const (
 	errModelDefined = "cannot model defined resource"
+	errGetDefined   = "cannot get defined resource"
+	errUnmarshalRes = "cannot unmarshal resource JSON"
+
+	errFmtVersionNotServed = "version %q is not served"
+	errFmtNotDefined       = "kind %q is not defined by this CRD"
 )
 
 type genericResource struct {
 	clients ClientCache
 }
 
-func (r *genericResource) Events(ctx context.Context, obj *model.GenericResource) (*model.EventConnection, error) {
+func (r *genericResource) Events(ctx context.Context, obj *model.GenericResource, limit *int) (*model.EventConnection, error) {
 	e := &events{clients: r.clients}
-	return e.Resolve(ctx, &corev1.ObjectReference{
-		APIVersion: obj.APIVersion,
-		Kind:       obj.Kind,
-		Name:       obj.Metadata.Name,
-		Namespace:  pointer.StringPtrDerefOr(obj.Metadata.Namespace, ""),
-		UID:        types.UID(obj.Metadata.UID),
-	})
+	return e.Resolve(ctx, involved(obj.ID, obj.Metadata), limit)
+}
+
+func (r *genericResource) Spec(ctx context.Context, obj *model.GenericResource, fieldPath *string) ([]byte, error) {
+	return projectJSON(ctx, topLevel(ctx, obj.Unstructured, "spec"), fieldPath)
+}
+
+func (r *genericResource) Status(ctx context.Context, obj *model.GenericResource, fieldPath *string) ([]byte, error) {
+	return projectJSON(ctx, topLevel(ctx, obj.Unstructured, "status"), fieldPath)
+}
+
+// topLevel returns the supplied top-level object field of the supplied
+// unstructured JSON, or nil if there is no such object.
+func topLevel(ctx context.Context, unstructured []byte, field string) map[string]interface{} {
+	u := map[string]interface{}{}
+	if err := json.Unmarshal(unstructured, &u); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errUnmarshalRes))
+		return nil
+	}
+	f, _ := u[field].(map[string]interface{})
+	return f
+}
+
+type condition struct {
+	now func() time.Time
+}
+
+func (r *condition) Age(_ context.Context, obj *model.Condition) (int, error) {
+	if obj.LastTransitionTime.IsZero() {
+		return 0, nil
+	}
+	return int(r.now().Sub(obj.LastTransitionTime).Seconds()), nil
 }
 
 type secret struct {
 	clients ClientCache
 }
 
-func (r *secret) Events(ctx context.Context, obj *model.Secret) (*model.EventConnection, error) {
+func (r *secret) Events(ctx context.Context, obj *model.Secret, limit *int) (*model.EventConnection, error) {
 	e := &events{clients: r.clients}
-	return e.Resolve(ctx, &corev1.ObjectReference{
-		APIVersion: obj.APIVersion,
-		Kind:       obj.Kind,
-		Name:       obj.Metadata.Name,
-		Namespace:  pointer.StringPtrDerefOr(obj.Metadata.Namespace, ""),
-		UID:        types.UID(obj.Metadata.UID),
-	})
+	return e.Resolve(ctx, involved(obj.ID, obj.Metadata), limit)
 }
 
 type configMap struct {
 	clients ClientCache
 }
 
-func (r *configMap) Events(ctx context.Context, obj *model.ConfigMap) (*model.EventConnection, error) {
+func (r *configMap) Events(ctx context.Context, obj *model.ConfigMap, limit *int) (*model.EventConnection, error) {
 	e := &events{clients: r.clients}
-	return e.Resolve(ctx, &corev1.ObjectReference{
-		APIVersion: obj.APIVersion,
-		Kind:       obj.Kind,
-		Name:       obj.Metadata.Name,
-		Namespace:  pointer.StringPtrDerefOr(obj.Metadata.Namespace, ""),
-		UID:        types.UID(obj.Metadata.UID),
-	})
+	return e.Resolve(ctx, involved(obj.ID, obj.Metadata), limit)
 }
 
 type crd struct {
-	clients ClientCache
+	clients    ClientCache
+	categories model.CategoryIndex
 }
 
-func (r *crd) Events(ctx context.Context, obj *model.CustomResourceDefinition) (*model.EventConnection, error) {
+func (r *crd) Events(ctx context.Context, obj *model.CustomResourceDefinition, limit *int) (*model.EventConnection, error) {
 	e := &events{clients: r.clients}
-	return e.Resolve(ctx, &corev1.ObjectReference{
-		APIVersion: obj.APIVersion,
-		Kind:       obj.Kind,
-		Name:       obj.Metadata.Name,
-		UID:        types.UID(obj.Metadata.UID),
-	})
+	return e.Resolve(ctx, involved(obj.ID, obj.Metadata), limit)
+}
+
+func (r *crd) PackageRevision(ctx context.Context, obj *model.CustomResourceDefinition) (model.PackageRevision, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	// Providers install CRDs directly.
+	pr, err := getOwningRevision(ctx, c, obj.Metadata.OwnerReferences, pkgv1.ProviderRevisionGroupVersionKind, func() pkgv1.PackageRevision { return &pkgv1.ProviderRevision{} })
+	if err != nil {
+		graphql.AddError(ctx, err)
+		return nil, nil
+	}
+	if pr != nil {
+		out := model.GetProviderRevision(pr.(*pkgv1.ProviderRevision))
+		return &out, nil
+	}
+
+	// Configurations install XRDs, which control the CRDs they define.
+	for _, ref := range obj.Metadata.OwnerReferences {
+		if !pointer.BoolPtrDerefOr(ref.Controller, false) {
+			continue
+		}
+		if ref.APIVersion != extv1.CompositeResourceDefinitionGroupVersionKind.GroupVersion().String() || ref.Kind != extv1.CompositeResourceDefinitionKind {
+			continue
+		}
+
+		xrd := &extv1.CompositeResourceDefinition{}
+		if err := c.Get(ctx, types.NamespacedName{Name: ref.Name}, xrd); err != nil {
+			graphql.AddError(ctx, errors.Wrap(err, errGetXRD))
+			return nil, nil
+		}
+
+		cr, err := getOwningRevision(ctx, c, xrd.GetOwnerReferences(), pkgv1.ConfigurationRevisionGroupVersionKind, func() pkgv1.PackageRevision { return &pkgv1.ConfigurationRevision{} })
+		if err != nil {
+			graphql.AddError(ctx, err)
+			return nil, nil
+		}
+		if cr == nil {
+			return nil, nil
+		}
+		out := model.GetConfigurationRevision(cr.(*pkgv1.ConfigurationRevision))
+		return &out, nil
+	}
+
+	return nil, nil
 }
 
-func (r *crd) DefinedResources(ctx context.Context, obj *model.CustomResourceDefinition, version *string) (*model.KubernetesResourceConnection, error) {
+func (r *crd) DefinedResources(ctx context.Context, obj *model.CustomResourceDefinition, version *string, limit, offset *int) (*model.KubernetesResourceConnection, error) {
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
@@ -108,8 +179,12 @@ func (r *crd) DefinedResources(ctx context.Context, obj *model.CustomResourceDef
 	switch {
 	case version != nil:
 		gv.Version = *version
+		if !servesVersion(obj.Spec.Versions, gv.Version) {
+			graphql.AddError(ctx, errors.Errorf(errFmtVersionNotServed, gv.Version))
+			return nil, nil
+		}
 	default:
-		gv.Version = pickCRDVersion(obj.Spec.Versions)
+		gv.Version = highestCRDVersion(obj.Spec.Versions)
 	}
 
 	in := &kunstructured.UnstructuredList{}
@@ -140,7 +215,7 @@ func (r *crd) DefinedResources(ctx context.Context, obj *model.CustomResourceDef
 	for i := range in.Items {
 		u := in.Items[i]
 
-		kr, err := model.GetKubernetesResource(&u)
+		kr, err := model.GetKubernetesResource(&u, r.categories)
 		if err != nil {
 			graphql.AddError(ctx, errors.Wrap(err, errModelDefined))
 		}
@@ -148,12 +223,50 @@ func (r *crd) DefinedResources(ctx context.Context, obj *model.CustomResourceDef
 	}
 
 	sort.Stable(out)
+	lo, hi := page(ctx, limit, offset, len(out.Nodes))
+	out.Nodes = out.Nodes[lo:hi]
 	return out, nil
 }
 
-// TODO(negz): Try to pick the 'highest' version (e.g. v2 > v1 > v1beta1),
-// rather than returning the first served one. There's no guarantee versions
-// will actually follow this convention, but it's ubiquitous.
+func (r *crd) PrinterColumns(ctx context.Context, obj *model.CustomResourceDefinition, resource model.ReferenceID) ([]model.PrinterColumnValue, error) {
+	gv, err := schema.ParseGroupVersion(resource.APIVersion)
+	if err != nil || gv.Group != obj.Spec.Group || resource.Kind != obj.Spec.Names.Kind {
+		graphql.AddError(ctx, errors.Errorf(errFmtNotDefined, resource.Kind))
+		return nil, nil
+	}
+
+	var cols []model.CustomResourceColumnDefinition
+	for _, v := range obj.Spec.Versions {
+		if v.Name == gv.Version {
+			cols = v.AdditionalPrinterColumns
+		}
+	}
+	if cols == nil {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	u := &kunstructured.Unstructured{}
+	u.SetAPIVersion(resource.APIVersion)
+	u.SetKind(resource.Kind)
+	if err := c.Get(ctx, types.NamespacedName{Namespace: resource.Namespace, Name: resource.Name}, u); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetDefined))
+		return nil, nil
+	}
+
+	return model.GetPrinterColumnValues(cols, u.Object), nil
+}
+
+// pickCRDVersion returns the first served version.
 func pickCRDVersion(vs []model.CustomResourceDefinitionVersion) string {
 	for _, v := range vs {
 		if v.Served {
@@ -164,3 +277,31 @@ func pickCRDVersion(vs []model.CustomResourceDefinitionVersion) string {
 	// We shouldn't get here, unless the CRD is serving no versions?
 	return ""
 }
+
+// highestCRDVersion returns the highest served version, e.g. v2 over v1, and
+// v1 over v1beta1. Versions that don't follow Kubernetes conventions are sorted
+// lexically, below those that do.
+func highestCRDVersion(vs []model.CustomResourceDefinitionVersion) string {
+	out := ""
+	for _, v := range vs {
+		if !v.Served {
+			continue
+		}
+		if out == "" || kversion.CompareKubeAwareVersionStrings(v.Name, out) > 0 {
+			out = v.Name
+		}
+	}
+
+	// We shouldn't return an empty string unless the CRD is serving no
+	// versions.
+	return out
+}
+
+func servesVersion(vs []model.CustomResourceDefinitionVersion, name string) bool {
+	for _, v := range vs {
+		if v.Name == name {
+			return v.Served
+		}
+	}
+	return false
+}
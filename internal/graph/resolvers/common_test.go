@@ -17,18 +17,22 @@ package resolvers
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 	"github.com/vektah/gqlparser/v2/gqlerror"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/test"
+	extv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
 
 	"github.com/upbound/xgql/internal/auth"
 	"github.com/upbound/xgql/internal/clients"
@@ -38,11 +42,75 @@ import (
 
 var (
 	_ generated.GenericResourceResolver          = &genericResource{}
+	_ generated.ConditionResolver                = &condition{}
 	_ generated.SecretResolver                   = &secret{}
 	_ generated.ConfigMapResolver                = &configMap{}
 	_ generated.CustomResourceDefinitionResolver = &crd{}
 )
 
+func TestGenericResourceSpecAndStatus(t *testing.T) {
+	u := []byte(`{"apiVersion":"example.org/v1","kind":"Example","spec":{"replicas":3},"status":{"phase":"Running"}}`)
+
+	type args struct {
+		unstructured []byte
+		fieldPath    *string
+	}
+	type want struct {
+		spec   []byte
+		status []byte
+		errs   gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"All": {
+			reason: "We should return all of spec and status if no field path is supplied.",
+			args:   args{unstructured: u},
+			want: want{
+				spec:   []byte(`{"replicas":3}`),
+				status: []byte(`{"phase":"Running"}`),
+			},
+		},
+		"FieldPath": {
+			reason: "We should return only the value at the supplied field path.",
+			args:   args{unstructured: u, fieldPath: pointer.StringPtr("replicas")},
+			want: want{
+				spec: []byte(`3`),
+			},
+		},
+		"Absent": {
+			reason: "We should return nil if the resource has no spec or status.",
+			args:   args{unstructured: []byte(`{"apiVersion":"example.org/v1","kind":"Example"}`)},
+			want:   want{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover)
+
+			r := &genericResource{}
+			obj := &model.GenericResource{Unstructured: tc.args.unstructured}
+			spec, _ := r.Spec(ctx, obj, tc.args.fieldPath)
+			status, _ := r.Status(ctx, obj, tc.args.fieldPath)
+			errs := graphql.GetErrors(ctx)
+
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Spec(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(string(tc.want.spec), string(spec)); diff != "" {
+				t.Errorf("\n%s\nr.Spec(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(string(tc.want.status), string(status)); diff != "" {
+				t.Errorf("\n%s\nr.Status(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestCRDDefinedResources(t *testing.T) {
 	errBoom := errors.New("boom")
 
@@ -57,10 +125,14 @@ func TestCRDDefinedResources(t *testing.T) {
 	// when ListKind is not set, and want to test that this will override it.
 	listKind := "Examples"
 
+	one := 1
+
 	type args struct {
 		ctx     context.Context
 		obj     *model.CustomResourceDefinition
 		version *string
+		limit   *int
+		offset  *int
 	}
 	type want struct {
 		krc  *model.KubernetesResourceConnection
@@ -211,6 +283,66 @@ func TestCRDDefinedResources(t *testing.T) {
 				},
 			},
 		},
+		"UnservedVersion": {
+			reason: "If the caller asks for a version that is not served we should add an error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CustomResourceDefinition{
+					Spec: &model.CustomResourceDefinitionSpec{
+						Group: group,
+						Names: &model.CustomResourceDefinitionNames{Kind: kind},
+						Versions: []model.CustomResourceDefinitionVersion{
+							{
+								Name: version,
+							},
+						},
+					},
+				},
+				version: pointer.StringPtr(version),
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Errorf(errFmtVersionNotServed, version).Error()),
+				},
+			},
+		},
+		"PageOfDefinedResources": {
+			reason: "We should return only the requested page of defined resources, but count them all.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						*obj.(*unstructured.UnstructuredList) = unstructured.UnstructuredList{Items: []unstructured.Unstructured{gr, gr}}
+						return nil
+					}),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CustomResourceDefinition{
+					Spec: &model.CustomResourceDefinitionSpec{
+						Group: group,
+						Names: &model.CustomResourceDefinitionNames{Kind: kind},
+						Versions: []model.CustomResourceDefinitionVersion{
+							{
+								Name:   version,
+								Served: true,
+							},
+						},
+					},
+				},
+				limit:  &one,
+				offset: &one,
+			},
+			want: want{
+				krc: &model.KubernetesResourceConnection{
+					Nodes:      []model.KubernetesResource{ggr},
+					TotalCount: 2,
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -219,7 +351,7 @@ func TestCRDDefinedResources(t *testing.T) {
 
 			// Our GraphQL resolvers never return errors. We instead add an
 			// error to the GraphQL context and return early.
-			got, err := x.DefinedResources(tc.args.ctx, tc.args.obj, tc.args.version)
+			got, err := x.DefinedResources(tc.args.ctx, tc.args.obj, tc.args.version, tc.args.limit, tc.args.offset)
 			errs := graphql.GetErrors(tc.args.ctx)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
@@ -234,3 +366,434 @@ func TestCRDDefinedResources(t *testing.T) {
 		})
 	}
 }
+
+func TestCRDPrinterColumns(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	def := &model.CustomResourceDefinition{
+		Spec: &model.CustomResourceDefinitionSpec{
+			Group: "example.org",
+			Names: &model.CustomResourceDefinitionNames{Kind: "Example"},
+			Versions: []model.CustomResourceDefinitionVersion{
+				{
+					Name:   "v1",
+					Served: true,
+					AdditionalPrinterColumns: []model.CustomResourceColumnDefinition{
+						{Name: "READY", Type: "string", JSONPath: ".status.ready"},
+					},
+				},
+				{
+					Name:   "v2",
+					Served: true,
+				},
+			},
+		},
+	}
+
+	id := model.ReferenceID{APIVersion: "example.org/v1", Kind: "Example", Name: "cool"}
+
+	type args struct {
+		ctx      context.Context
+		obj      *model.CustomResourceDefinition
+		resource model.ReferenceID
+	}
+	type want struct {
+		pcv  []model.PrinterColumnValue
+		err  error
+		errs gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"NotDefined": {
+			reason: "If the resource isn't defined by the CRD we should add an error to the GraphQL context and return early.",
+			args: args{
+				ctx:      graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj:      def,
+				resource: model.ReferenceID{APIVersion: "example.org/v1", Kind: "Other", Name: "cool"},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Errorf(errFmtNotDefined, "Other").Error()),
+				},
+			},
+		},
+		"NoColumns": {
+			reason: "If the resource's version has no printer columns we should return early.",
+			args: args{
+				ctx:      graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj:      def,
+				resource: model.ReferenceID{APIVersion: "example.org/v2", Kind: "Example", Name: "cool"},
+			},
+		},
+		"GetClientError": {
+			reason: "If we can't get a client we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return nil, errBoom
+			}),
+			args: args{
+				ctx:      graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj:      def,
+				resource: id,
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetClient).Error()),
+				},
+			},
+		},
+		"GetDefinedError": {
+			reason: "If we can't get the resource we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx:      graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj:      def,
+				resource: id,
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetDefined).Error()),
+				},
+			},
+		},
+		"Success": {
+			reason: "If we can get the resource we should evaluate its printer columns.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						u := obj.(*unstructured.Unstructured)
+						u.Object["status"] = map[string]interface{}{"ready": "True"}
+						return nil
+					}),
+				}, nil
+			}),
+			args: args{
+				ctx:      graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj:      def,
+				resource: id,
+			},
+			want: want{
+				pcv: []model.PrinterColumnValue{
+					{Name: "READY", Type: "string", Value: pointer.StringPtr("True")},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &crd{clients: tc.clients}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := r.PrinterColumns(tc.args.ctx, tc.args.obj, tc.args.resource)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.PrinterColumns(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.PrinterColumns(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.pcv, got); diff != "" {
+				t.Errorf("\n%s\nr.PrinterColumns(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCRDPackageRevision(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	provider := model.ObjectMeta{
+		OwnerReferences: []metav1.OwnerReference{
+			{
+				APIVersion: pkgv1.ProviderRevisionGroupVersionKind.GroupVersion().String(),
+				Kind:       pkgv1.ProviderRevisionKind,
+				Name:       "inactive",
+			},
+			{
+				APIVersion: pkgv1.ProviderRevisionGroupVersionKind.GroupVersion().String(),
+				Kind:       pkgv1.ProviderRevisionKind,
+				Name:       "active",
+			},
+		},
+	}
+	inactive := pkgv1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Name: "inactive"}}
+	active := pkgv1.ProviderRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "active"},
+		Spec:       pkgv1.PackageRevisionSpec{DesiredState: pkgv1.PackageRevisionActive},
+	}
+	gactive := model.GetProviderRevision(&active)
+
+	defined := model.ObjectMeta{
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: extv1.CompositeResourceDefinitionGroupVersionKind.GroupVersion().String(),
+			Kind:       extv1.CompositeResourceDefinitionKind,
+			Name:       "xclusters.example.org",
+			Controller: pointer.BoolPtr(true),
+		}},
+	}
+	xrd := extv1.CompositeResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "xclusters.example.org",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: pkgv1.ConfigurationRevisionGroupVersionKind.GroupVersion().String(),
+				Kind:       pkgv1.ConfigurationRevisionKind,
+				Name:       "config",
+			}},
+		},
+	}
+	config := pkgv1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "config"}}
+	gconfig := model.GetConfigurationRevision(&config)
+
+	type args struct {
+		ctx context.Context
+		obj *model.CustomResourceDefinition
+	}
+	type want struct {
+		pr   model.PackageRevision
+		err  error
+		errs gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"GetClientError": {
+			reason: "If we can't get a client we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{}, errBoom
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CustomResourceDefinition{Metadata: &provider},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetClient).Error()),
+				},
+			},
+		},
+		"GetProviderRevisionError": {
+			reason: "If we can't get a provider revision that owns the CRD we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CustomResourceDefinition{Metadata: &provider},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetRevision).Error()),
+				},
+			},
+		},
+		"ProviderRevision": {
+			reason: "We should return the active provider revision that owns the CRD.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+						if key.Name == "active" {
+							*obj.(*pkgv1.ProviderRevision) = active
+							return nil
+						}
+						*obj.(*pkgv1.ProviderRevision) = inactive
+						return nil
+					},
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CustomResourceDefinition{Metadata: &provider},
+			},
+			want: want{
+				pr: &gactive,
+			},
+		},
+		"GetXRDError": {
+			reason: "If we can't get the XRD that controls the CRD we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CustomResourceDefinition{Metadata: &defined},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetXRD).Error()),
+				},
+			},
+		},
+		"ConfigurationRevision": {
+			reason: "We should return the configuration revision that owns the XRD that controls the CRD.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						switch o := obj.(type) {
+						case *extv1.CompositeResourceDefinition:
+							*o = xrd
+						case *pkgv1.ConfigurationRevision:
+							*o = config
+						}
+						return nil
+					}),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CustomResourceDefinition{Metadata: &defined},
+			},
+			want: want{
+				pr: &gconfig,
+			},
+		},
+		"NoPackageRevision": {
+			reason: "We should return nil if neither a provider revision nor an XRD owns the CRD.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CustomResourceDefinition{Metadata: &model.ObjectMeta{}},
+			},
+			want: want{
+				pr: nil,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &crd{clients: tc.clients}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := r.PackageRevision(tc.args.ctx, tc.args.obj)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.PackageRevision(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.PackageRevision(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.pr, got, cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\nr.PackageRevision(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPickCRDVersion(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		vs     []model.CustomResourceDefinitionVersion
+		want   string
+	}{
+		"NoServedVersions": {
+			reason: "If no versions are served we should return an empty string.",
+			vs:     []model.CustomResourceDefinitionVersion{{Name: "v1"}},
+			want:   "",
+		},
+		"FirstServedVersion": {
+			reason: "We should return the first served version, even if a higher version is served.",
+			vs: []model.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: false},
+				{Name: "v1beta1", Served: true},
+				{Name: "v1", Served: true},
+			},
+			want: "v1beta1",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := pickCRDVersion(tc.vs)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\npickCRDVersion(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestHighestCRDVersion(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		vs     []model.CustomResourceDefinitionVersion
+		want   string
+	}{
+		"NoServedVersions": {
+			reason: "If no versions are served we should return an empty string.",
+			vs:     []model.CustomResourceDefinitionVersion{{Name: "v1"}},
+			want:   "",
+		},
+		"HighestServedVersion": {
+			reason: "We should return the highest served version.",
+			vs: []model.CustomResourceDefinitionVersion{
+				{Name: "v1beta1", Served: true},
+				{Name: "v1", Served: true},
+				{Name: "v1alpha1", Served: true},
+				{Name: "v2", Served: false},
+			},
+			want: "v1",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := highestCRDVersion(tc.vs)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nhighestCRDVersion(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestConditionAge(t *testing.T) {
+	now := time.Unix(100, 0)
+
+	cases := map[string]struct {
+		reason string
+		c      *model.Condition
+		want   int
+	}{
+		"NeverTransitioned": {
+			reason: "A condition without a transition time should have no age.",
+			c:      &model.Condition{},
+			want:   0,
+		},
+		"Transitioned": {
+			reason: "A condition's age should be the seconds since it last transitioned.",
+			c:      &model.Condition{LastTransitionTime: time.Unix(40, 0)},
+			want:   60,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &condition{now: func() time.Time { return now }}
+			got, _ := r.Age(context.Background(), tc.c)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nr.Age(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -24,35 +24,35 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	extv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 
 	"github.com/upbound/xgql/internal/auth"
 	"github.com/upbound/xgql/internal/graph/model"
+	xunstructured "github.com/upbound/xgql/internal/unstructured"
 )
 
 const (
-	errListXRDs            = "cannot list composite resource definitions"
-	errMalformedAPIVersion = "cannot parse malformed API version"
-	errGetComposition      = "cannot get composition"
-	errGetXR               = "cannot get composite resource"
-	errGetXRC              = "cannot get composite resource claim"
-	errGetComposed         = "cannot get composed resource"
-	errModelComposed       = "cannot model composed resource"
+	errListXRDs               = "cannot list composite resource definitions"
+	errMalformedAPIVersion    = "cannot parse malformed API version"
+	errGetComposition         = "cannot get composition"
+	errGetXR                  = "cannot get composite resource"
+	errGetXRC                 = "cannot get composite resource claim"
+	errGetComposed            = "cannot get composed resource"
+	errModelComposed          = "cannot model composed resource"
+	errGetEnvironmentConfig   = "cannot get environment config"
+	errModelEnvironmentConfig = "cannot model environment config"
 )
 
 type compositeResource struct {
 	clients ClientCache
 }
 
-func (r *compositeResource) Events(ctx context.Context, obj *model.CompositeResource) (*model.EventConnection, error) {
+func (r *compositeResource) Events(ctx context.Context, obj *model.CompositeResource, limit *int) (*model.EventConnection, error) {
 	e := &events{clients: r.clients}
-	return e.Resolve(ctx, &corev1.ObjectReference{
-		APIVersion: obj.APIVersion,
-		Kind:       obj.Kind,
-		Name:       obj.Metadata.Name,
-		UID:        types.UID(obj.Metadata.UID),
-	})
+	return e.Resolve(ctx, involved(obj.ID, obj.Metadata), limit)
 }
 
 func (r *compositeResource) Definition(ctx context.Context, obj *model.CompositeResource) (*model.CompositeResourceDefinition, error) {
@@ -101,8 +101,65 @@ func (r *compositeResource) Definition(ctx context.Context, obj *model.Composite
 	return nil, nil
 }
 
+func (r *compositeResource) ComposedReadiness(ctx context.Context, obj *model.CompositeResource) (*model.ComposedReadiness, error) {
+	if obj.Spec == nil {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	return composedReadiness(ctx, c, obj.Spec.ResourceReferences), nil
+}
+
+// composedReadiness returns the readiness of the supplied composed resources.
+// Resources that can't be read are considered not ready.
+func composedReadiness(ctx context.Context, c client.Client, refs []corev1.ObjectReference) *model.ComposedReadiness {
+	ready := make([]xpv1.Condition, len(refs))
+	forEach(ctx, len(refs), func(i int) {
+		ref := refs[i]
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(ref.APIVersion)
+		u.SetKind(ref.Kind)
+		nn := types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}
+
+		// Resources that can't be read are considered not ready, rather
+		// than errors; a resource that was just composed may not exist yet.
+		if err := c.Get(ctx, nn, u); err != nil {
+			return
+		}
+
+		// Composed resources may be managed or composite resources, but
+		// either way their conditions live at status.conditions.
+		m := &xunstructured.Managed{Unstructured: *u}
+		ready[i] = m.GetCondition(xpv1.TypeReady)
+	})
+
+	out := &model.ComposedReadiness{Total: len(refs)}
+	for _, c := range ready {
+		if c.Status == corev1.ConditionTrue {
+			out.Ready++
+			continue
+		}
+		// We report the first message in reference order so that it's stable
+		// from one request to the next.
+		if out.Message == nil && c.Message != "" {
+			msg := c.Message
+			out.Message = &msg
+		}
+	}
+	return out
+}
+
 type compositeResourceSpec struct {
-	clients ClientCache
+	clients    ClientCache
+	categories model.CategoryIndex
 }
 
 func (r *compositeResourceSpec) Composition(ctx context.Context, obj *model.CompositeResourceSpec) (*model.Composition, error) {
@@ -171,32 +228,95 @@ func (r *compositeResourceSpec) Resources(ctx context.Context, obj *model.Compos
 		return nil, nil
 	}
 
-	out := &model.KubernetesResourceConnection{
-		Nodes: make([]model.KubernetesResource, 0, len(obj.ResourceReferences)),
+	return getResources(ctx, c, r.categories, obj.ResourceReferences, errGetComposed, errModelComposed), nil
+}
+
+func (r *compositeResourceSpec) EnvironmentConfigs(ctx context.Context, obj *model.CompositeResourceSpec) (*model.KubernetesResourceConnection, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	return getResources(ctx, c, r.categories, obj.EnvironmentConfigReferences, errGetEnvironmentConfig, errModelEnvironmentConfig), nil
+}
+
+func (r *compositeResourceSpec) CompositionEnvironment(ctx context.Context, obj *model.CompositeResourceSpec, fieldPath *string) ([]byte, error) {
+	if obj.CompositionReference == nil {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	// The version of Crossplane's API types we use predates composition
+	// environments, so we read the composition as unstructured to avoid
+	// dropping its spec.environment field.
+	cmp := &unstructured.Unstructured{}
+	cmp.SetGroupVersionKind(extv1.CompositionGroupVersionKind)
+	nn := types.NamespacedName{Name: obj.CompositionReference.Name}
+	if err := c.Get(ctx, nn, cmp); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetComposition))
+		return nil, nil
+	}
+
+	env, _, err := unstructured.NestedMap(cmp.Object, "spec", "environment")
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errFieldPath))
+		return nil, nil
 	}
 
-	for _, ref := range obj.ResourceReferences {
-		xrc := &unstructured.Unstructured{}
-		xrc.SetAPIVersion(ref.APIVersion)
-		xrc.SetKind(ref.Kind)
+	return projectJSON(ctx, env, fieldPath)
+}
+
+// getResources gets and models the supplied references. Any errors are wrapped
+// with the supplied messages and added to the GraphQL context; the resources
+// that could be got are still returned.
+func getResources(ctx context.Context, c client.Client, ci model.CategoryIndex, refs []corev1.ObjectReference, errGet, errModel string) *model.KubernetesResourceConnection {
+	nodes := make([]model.KubernetesResource, len(refs))
+	forEach(ctx, len(refs), func(i int) {
+		ref := refs[i]
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(ref.APIVersion)
+		u.SetKind(ref.Kind)
 		nn := types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}
-		if err := c.Get(ctx, nn, xrc); err != nil {
-			graphql.AddError(ctx, errors.Wrap(err, errGetComposed))
-			continue
+		if err := c.Get(ctx, nn, u); err != nil {
+			graphql.AddError(ctx, errors.Wrap(err, errGet))
+			return
 		}
 
-		kr, err := model.GetKubernetesResource(xrc)
+		kr, err := model.GetKubernetesResource(u, ci)
 		if err != nil {
-			graphql.AddError(ctx, errors.Wrap(err, errModelComposed))
-			continue
+			graphql.AddError(ctx, errors.Wrap(err, errModel))
+			return
 		}
 
+		nodes[i] = kr
+	})
+
+	out := &model.KubernetesResourceConnection{
+		Nodes: make([]model.KubernetesResource, 0, len(nodes)),
+	}
+	for _, kr := range nodes {
+		if kr == nil {
+			continue
+		}
 		out.Nodes = append(out.Nodes, kr)
 		out.TotalCount++
 	}
 
 	sort.Stable(out)
-	return out, nil
+	return out
 }
 
 func (r *compositeResourceSpec) ConnectionSecret(ctx context.Context, obj *model.CompositeResourceSpec) (*model.Secret, error) {
@@ -232,14 +352,32 @@ type compositeResourceClaim struct {
 	clients ClientCache
 }
 
-func (r *compositeResourceClaim) Events(ctx context.Context, obj *model.CompositeResourceClaim) (*model.EventConnection, error) {
+func (r *compositeResourceClaim) Events(ctx context.Context, obj *model.CompositeResourceClaim, limit *int) (*model.EventConnection, error) {
 	e := &events{clients: r.clients}
-	return e.Resolve(ctx, &corev1.ObjectReference{
-		APIVersion: obj.APIVersion,
-		Kind:       obj.Kind,
-		Name:       obj.Metadata.Name,
-		UID:        types.UID(obj.Metadata.UID),
-	})
+	return e.Resolve(ctx, involved(obj.ID, obj.Metadata), limit)
+}
+
+func (r *compositeResourceClaim) ComposedReadiness(ctx context.Context, obj *model.CompositeResourceClaim) (*model.ComposedReadiness, error) {
+	if obj.Spec == nil || obj.Spec.ResourceReference == nil {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	xr, err := getClaimed(ctx, c, obj.Spec.ResourceReference)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetXR))
+		return nil, nil
+	}
+
+	return composedReadiness(ctx, c, xr.Spec.ResourceReferences), nil
 }
 
 func (r *compositeResourceClaim) Definition(ctx context.Context, obj *model.CompositeResourceClaim) (*model.CompositeResourceDefinition, error) {
@@ -293,7 +431,8 @@ func (r *compositeResourceClaim) Definition(ctx context.Context, obj *model.Comp
 }
 
 type compositeResourceClaimSpec struct {
-	clients ClientCache
+	clients    ClientCache
+	categories model.CategoryIndex
 }
 
 func (r *compositeResourceClaimSpec) Composition(ctx context.Context, obj *model.CompositeResourceClaimSpec) (*model.Composition, error) {
@@ -335,15 +474,45 @@ func (r *compositeResourceClaimSpec) Resource(ctx context.Context, obj *model.Co
 		return nil, nil
 	}
 
-	xr := &unstructured.Unstructured{}
-	xr.SetAPIVersion(obj.ResourceReference.APIVersion)
-	xr.SetKind(obj.ResourceReference.Kind)
-	nn := types.NamespacedName{Name: obj.ResourceReference.Name}
-	if err := c.Get(ctx, nn, xr); err != nil {
+	out, err := getClaimed(ctx, c, obj.ResourceReference)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetXR))
+		return nil, nil
+	}
+	return out, nil
+}
+
+func (r *compositeResourceClaimSpec) Resources(ctx context.Context, obj *model.CompositeResourceClaimSpec) (*model.KubernetesResourceConnection, error) {
+	if obj.ResourceReference == nil {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	xr, err := getClaimed(ctx, c, obj.ResourceReference)
+	if err != nil {
 		graphql.AddError(ctx, errors.Wrap(err, errGetXR))
 		return nil, nil
 	}
 
+	return getResources(ctx, c, r.categories, xr.Spec.ResourceReferences, errGetComposed, errModelComposed), nil
+}
+
+// getClaimed returns the composite resource a claim is bound to.
+func getClaimed(ctx context.Context, c client.Client, ref *corev1.ObjectReference) (*model.CompositeResource, error) {
+	xr := &unstructured.Unstructured{}
+	xr.SetAPIVersion(ref.APIVersion)
+	xr.SetKind(ref.Kind)
+	if err := c.Get(ctx, types.NamespacedName{Name: ref.Name}, xr); err != nil {
+		return nil, err
+	}
 	out := model.GetCompositeResource(xr)
 	return &out, nil
 }
@@ -26,6 +26,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	kextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -188,6 +189,100 @@ func TestCompositeResourceDefinition(t *testing.T) {
 	}
 }
 
+func TestCompositeResourceComposedReadiness(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	composed := func(name string, c xpv1.Condition) unstructured.Unstructured {
+		m := unstructured.Unstructured{Object: map[string]interface{}{}}
+		m.SetName(name)
+		_ = unstructured.SetNestedSlice(m.Object, []interface{}{
+			map[string]interface{}{"type": string(c.Type), "status": string(c.Status), "message": c.Message},
+		}, "status", "conditions")
+		return m
+	}
+	objs := map[string]unstructured.Unstructured{
+		"ready":    composed("ready", xpv1.Available()),
+		"creating": composed("creating", xpv1.Creating().WithMessage("still creating")),
+		"pending":  composed("pending", xpv1.Unavailable().WithMessage("still pending")),
+	}
+	xr := &model.CompositeResource{Spec: &model.CompositeResourceSpec{
+		ResourceReferences: []corev1.ObjectReference{{Name: "ready"}, {Name: "creating"}, {Name: "pending"}, {Name: "missing"}},
+	}}
+
+	type args struct {
+		ctx context.Context
+		obj *model.CompositeResource
+	}
+	type want struct {
+		cr   *model.ComposedReadiness
+		err  error
+		errs gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"GetClientError": {
+			reason: "If we can't get a client we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{}, errBoom
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: xr,
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetClient).Error()),
+				},
+			},
+		},
+		"Success": {
+			reason: "We should count ready composed resources and return the first not ready message, treating resources we can't get as not ready rather than as errors.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+						u, ok := objs[key.Name]
+						if !ok {
+							return errBoom
+						}
+						*obj.(*unstructured.Unstructured) = u
+						return nil
+					},
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: xr,
+			},
+			want: want{
+				cr: &model.ComposedReadiness{Ready: 1, Total: 4, Message: pointer.StringPtr("still creating")},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			x := &compositeResource{clients: tc.clients}
+			got, err := x.ComposedReadiness(tc.args.ctx, tc.args.obj)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nx.ComposedReadiness(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nx.ComposedReadiness(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, got); diff != "" {
+				t.Errorf("\n%s\nx.ComposedReadiness(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestCompositeResourceSpecComposition(t *testing.T) {
 	errBoom := errors.New("boom")
 
@@ -405,11 +500,11 @@ func TestCompositeResourceSpecResources(t *testing.T) {
 
 	kra := &unstructured.Unstructured{}
 	kra.SetKind("A")
-	gkra, _ := model.GetKubernetesResource(kra)
+	gkra, _ := model.GetKubernetesResource(kra, nil)
 
 	krb := &unstructured.Unstructured{}
 	krb.SetKind("B")
-	gkrb, _ := model.GetKubernetesResource(krb)
+	gkrb, _ := model.GetKubernetesResource(krb, nil)
 
 	type args struct {
 		ctx context.Context
@@ -521,6 +616,220 @@ func TestCompositeResourceSpecResources(t *testing.T) {
 	}
 }
 
+func TestCompositeResourceSpecEnvironmentConfigs(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	ec := &unstructured.Unstructured{}
+	ec.SetAPIVersion("apiextensions.crossplane.io/v1alpha1")
+	ec.SetKind("EnvironmentConfig")
+	gec, _ := model.GetKubernetesResource(ec, nil)
+
+	type args struct {
+		ctx context.Context
+		obj *model.CompositeResourceSpec
+	}
+	type want struct {
+		krc  *model.KubernetesResourceConnection
+		err  error
+		errs gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"GetEnvironmentConfigError": {
+			reason: "If we can't get an environment config we should add the error to the GraphQL context and continue.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CompositeResourceSpec{
+					EnvironmentConfigReferences: []corev1.ObjectReference{{APIVersion: ec.GetAPIVersion(), Kind: ec.GetKind()}},
+				},
+			},
+			want: want{
+				krc: &model.KubernetesResourceConnection{Nodes: []model.KubernetesResource{}},
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetEnvironmentConfig).Error()),
+				},
+			},
+		},
+		"Success": {
+			reason: "If we can get and model environment configs we should return them.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CompositeResourceSpec{
+					EnvironmentConfigReferences: []corev1.ObjectReference{{APIVersion: ec.GetAPIVersion(), Kind: ec.GetKind()}},
+				},
+			},
+			want: want{
+				krc: &model.KubernetesResourceConnection{
+					TotalCount: 1,
+					Nodes:      []model.KubernetesResource{gec},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := &compositeResourceSpec{clients: tc.clients}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := s.EnvironmentConfigs(tc.args.ctx, tc.args.obj)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.EnvironmentConfigs(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.EnvironmentConfigs(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.krc, got, cmpopts.IgnoreFields(model.GenericResource{}, "Unstructured"), cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\ns.EnvironmentConfigs(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCompositeResourceSpecCompositionEnvironment(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	withEnvironment := test.NewMockGetFn(nil, func(obj client.Object) error {
+		u := obj.(*unstructured.Unstructured)
+		return unstructured.SetNestedField(u.Object, map[string]interface{}{
+			"patches": []interface{}{
+				map[string]interface{}{"type": "ToCompositeFieldPath"},
+			},
+		}, "spec", "environment")
+	})
+
+	type args struct {
+		ctx       context.Context
+		obj       *model.CompositeResourceSpec
+		fieldPath *string
+	}
+	type want struct {
+		env  []byte
+		err  error
+		errs gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"NoOp": {
+			reason: "If there is no composition we should return early.",
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CompositeResourceSpec{},
+			},
+			want: want{},
+		},
+		"GetCompositionError": {
+			reason: "If we can't get the composition we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CompositeResourceSpec{
+					CompositionReference: &corev1.ObjectReference{},
+				},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetComposition).Error()),
+				},
+			},
+		},
+		"NoEnvironment": {
+			reason: "If the composition has no environment we should return null.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CompositeResourceSpec{
+					CompositionReference: &corev1.ObjectReference{},
+				},
+			},
+			want: want{},
+		},
+		"Success": {
+			reason: "If the composition has an environment we should return it.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{MockGet: withEnvironment}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CompositeResourceSpec{
+					CompositionReference: &corev1.ObjectReference{},
+				},
+			},
+			want: want{
+				env: []byte(`{"patches":[{"type":"ToCompositeFieldPath"}]}`),
+			},
+		},
+		"FieldPath": {
+			reason: "If a field path is supplied we should return only the value at that path.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{MockGet: withEnvironment}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CompositeResourceSpec{
+					CompositionReference: &corev1.ObjectReference{},
+				},
+				fieldPath: pointer.StringPtr("patches[0].type"),
+			},
+			want: want{
+				env: []byte(`"ToCompositeFieldPath"`),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := &compositeResourceSpec{clients: tc.clients}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := s.CompositionEnvironment(tc.args.ctx, tc.args.obj, tc.args.fieldPath)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.CompositionEnvironment(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.CompositionEnvironment(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(string(tc.want.env), string(got)); diff != "" {
+				t.Errorf("\n%s\ns.CompositionEnvironment(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestCompositeResourceSpecConnectionSecret(t *testing.T) {
 	errBoom := errors.New("boom")
 
@@ -777,6 +1086,111 @@ func TestCompositeResourceClaimDefinition(t *testing.T) {
 	}
 }
 
+func TestCompositeResourceClaimComposedReadiness(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	xr := unstructured.Unstructured{Object: map[string]interface{}{}}
+	xr.SetName("xr")
+	_ = unstructured.SetNestedSlice(xr.Object, []interface{}{
+		map[string]interface{}{"name": "ready"},
+		map[string]interface{}{"name": "creating"},
+	}, "spec", "resourceRefs")
+
+	composed := func(name string, c xpv1.Condition) unstructured.Unstructured {
+		m := unstructured.Unstructured{Object: map[string]interface{}{}}
+		m.SetName(name)
+		_ = unstructured.SetNestedSlice(m.Object, []interface{}{
+			map[string]interface{}{"type": string(c.Type), "status": string(c.Status), "message": c.Message},
+		}, "status", "conditions")
+		return m
+	}
+	objs := map[string]unstructured.Unstructured{
+		"xr":       xr,
+		"ready":    composed("ready", xpv1.Available()),
+		"creating": composed("creating", xpv1.Creating().WithMessage("still creating")),
+	}
+	xrc := &model.CompositeResourceClaim{Spec: &model.CompositeResourceClaimSpec{
+		ResourceReference: &corev1.ObjectReference{Name: "xr"},
+	}}
+
+	type args struct {
+		ctx context.Context
+		obj *model.CompositeResourceClaim
+	}
+	type want struct {
+		cr   *model.ComposedReadiness
+		err  error
+		errs gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"Unbound": {
+			reason: "If the claim is not bound to a composite resource we should return early.",
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CompositeResourceClaim{Spec: &model.CompositeResourceClaimSpec{}},
+			},
+			want: want{},
+		},
+		"GetXRError": {
+			reason: "If we can't get the composite resource we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{MockGet: test.NewMockGetFn(errBoom)}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: xrc,
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetXR).Error()),
+				},
+			},
+		},
+		"Success": {
+			reason: "We should return the readiness of the resources composed by the claim's composite resource.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+						*obj.(*unstructured.Unstructured) = objs[key.Name]
+						return nil
+					},
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: xrc,
+			},
+			want: want{
+				cr: &model.ComposedReadiness{Ready: 1, Total: 2, Message: pointer.StringPtr("still creating")},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			x := &compositeResourceClaim{clients: tc.clients}
+			got, err := x.ComposedReadiness(tc.args.ctx, tc.args.obj)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nx.ComposedReadiness(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nx.ComposedReadiness(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, got); diff != "" {
+				t.Errorf("\n%s\nx.ComposedReadiness(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestCompositeResourceClaimSpecComposition(t *testing.T) {
 	errBoom := errors.New("boom")
 
@@ -989,6 +1403,111 @@ func TestCompositeResourceClaimSpecResource(t *testing.T) {
 	}
 }
 
+func TestCompositeResourceClaimSpecResources(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	xr := unstructured.Unstructured{Object: map[string]interface{}{}}
+	xr.SetName("xr")
+	_ = unstructured.SetNestedSlice(xr.Object, []interface{}{
+		map[string]interface{}{"kind": "A", "name": "a"},
+	}, "spec", "resourceRefs")
+
+	kra := &unstructured.Unstructured{}
+	kra.SetKind("A")
+	gkra, _ := model.GetKubernetesResource(kra, nil)
+
+	type args struct {
+		ctx context.Context
+		obj *model.CompositeResourceClaimSpec
+	}
+	type want struct {
+		krc  *model.KubernetesResourceConnection
+		err  error
+		errs gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"NoOp": {
+			reason: "If there is no resource we should return early.",
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CompositeResourceClaimSpec{},
+			},
+			want: want{},
+		},
+		"GetResourceError": {
+			reason: "If we can't get the resource we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CompositeResourceClaimSpec{
+					ResourceReference: &corev1.ObjectReference{Name: "xr"},
+				},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetXR).Error()),
+				},
+			},
+		},
+		"Success": {
+			reason: "If we can get the resource we should return the resources it is composed of.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+						if key.Name == xr.GetName() {
+							*obj.(*unstructured.Unstructured) = xr
+						}
+						return nil
+					},
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.CompositeResourceClaimSpec{
+					ResourceReference: &corev1.ObjectReference{Name: "xr"},
+				},
+			},
+			want: want{
+				krc: &model.KubernetesResourceConnection{
+					TotalCount: 1,
+					Nodes:      []model.KubernetesResource{gkra},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := &compositeResourceClaimSpec{clients: tc.clients}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := s.Resources(tc.args.ctx, tc.args.obj)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.Resources(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.Resources(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.krc, got, cmpopts.IgnoreFields(model.GenericResource{}, "Unstructured"), cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\ns.Resources(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestCompositeResourceClaimSpecConnectionSecret(t *testing.T) {
 	errBoom := errors.New("boom")
 
@@ -0,0 +1,111 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolvers
+
+import (
+	"context"
+	"sync"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// DefaultConcurrency is the default maximum number of objects a resolver will
+// get concurrently, e.g. when resolving the composed resources of an XR.
+const DefaultConcurrency = 10
+
+type concurrencyKey struct{}
+
+// Concurrency is a GraphQL server extension that bounds the number of objects
+// a resolver will get concurrently. Each resolver may use up to this many
+// goroutines, subject to the rate limits of its Kubernetes client. A
+// concurrency of one or less resolves objects serially.
+type Concurrency int
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationInterceptor
+} = Concurrency(0)
+
+// ExtensionName returns the name of this extension.
+func (c Concurrency) ExtensionName() string {
+	return "Concurrency"
+}
+
+// Validate this extension.
+func (c Concurrency) Validate(_ graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptOperation annotates the operation context with the concurrency.
+func (c Concurrency) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	return next(context.WithValue(ctx, concurrencyKey{}, int(c)))
+}
+
+// A ConcurrencyFn is a Concurrency that is determined as each operation
+// starts, for example so that it may be reconfigured while xgql is running.
+type ConcurrencyFn func() int
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationInterceptor
+} = ConcurrencyFn(nil)
+
+// ExtensionName returns the name of this extension.
+func (fn ConcurrencyFn) ExtensionName() string {
+	return "Concurrency"
+}
+
+// Validate this extension.
+func (fn ConcurrencyFn) Validate(_ graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptOperation annotates the operation context with the concurrency.
+func (fn ConcurrencyFn) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	return Concurrency(fn()).InterceptOperation(ctx, next)
+}
+
+// forEach calls fn once for each of n items, concurrently. It returns once all
+// calls have returned. Callers that collect results should write them to a
+// slice index rather than appending, in order to preserve their ordering.
+func forEach(ctx context.Context, n int, fn func(i int)) {
+	w, ok := ctx.Value(concurrencyKey{}).(int)
+	if !ok {
+		w = DefaultConcurrency
+	}
+	if w < 1 {
+		w = 1
+	}
+	if w > n {
+		w = n
+	}
+
+	items := make(chan int)
+	wg := sync.WaitGroup{}
+	for j := 0; j < w; j++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range items {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		items <- i
+	}
+	close(items)
+	wg.Wait()
+}
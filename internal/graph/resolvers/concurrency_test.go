@@ -0,0 +1,94 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolvers
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestForEach(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		ctx    context.Context
+		n      int
+	}{
+		"NoItems": {
+			reason: "We should return immediately if there are no items.",
+			ctx:    context.Background(),
+			n:      0,
+		},
+		"Default": {
+			reason: "We should use at most the default concurrency if none was annotated.",
+			ctx:    context.Background(),
+			n:      DefaultConcurrency * 3,
+		},
+		"Serial": {
+			reason: "We should process items serially if the annotated concurrency is zero.",
+			ctx:    context.WithValue(context.Background(), concurrencyKey{}, 0),
+			n:      5,
+		},
+		"Bounded": {
+			reason: "We should use at most the annotated concurrency.",
+			ctx:    context.WithValue(context.Background(), concurrencyKey{}, 2),
+			n:      5,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mx := sync.Mutex{}
+			busy, maxBusy := 0, 0
+			got := make([]int, tc.n)
+			want := make([]int, tc.n)
+
+			forEach(tc.ctx, tc.n, func(i int) {
+				mx.Lock()
+				busy++
+				if busy > maxBusy {
+					maxBusy = busy
+				}
+				mx.Unlock()
+
+				got[i] = i
+
+				mx.Lock()
+				busy--
+				mx.Unlock()
+			})
+
+			for i := range want {
+				want[i] = i
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("\n%s\nforEach(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+
+			limit, ok := tc.ctx.Value(concurrencyKey{}).(int)
+			if !ok {
+				limit = DefaultConcurrency
+			}
+			if limit < 1 {
+				limit = 1
+			}
+			if maxBusy > limit {
+				t.Errorf("\n%s\nforEach(...): want at most %d concurrent calls, got %d\n", tc.reason, limit, maxBusy)
+			}
+		})
+	}
+}
@@ -17,42 +17,53 @@ package resolvers
 import (
 	"context"
 	"sort"
-	"strings"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	extv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
 
 	"github.com/upbound/xgql/internal/auth"
 	"github.com/upbound/xgql/internal/graph/model"
+	xunstructured "github.com/upbound/xgql/internal/unstructured"
 )
 
 const (
 	errListConfigRevs = "cannot list configuration revisions"
+	errGetLock        = "cannot get package lock"
+	errListPackages   = "cannot list packages"
 	errGetXRD         = "cannot get composite resource definition"
-	errGetComp        = "cannot get composition"
+
+	errFmtObjectNotFound = "%s %q not found"
 )
 
+// configurationObjectKinds are the kinds of object a configuration package may
+// contain. Other objects are omitted from a configuration's objects.
+var configurationObjectKinds = map[schema.GroupKind]bool{
+	{Group: extv1.Group, Kind: extv1.CompositeResourceDefinitionKind}: true,
+	{Group: extv1.Group, Kind: extv1.CompositionKind}:                 true,
+	{Group: pkgv1.Group, Kind: xunstructured.KindFunction}:            true,
+}
+
 type configuration struct {
 	clients ClientCache
 }
 
-func (r *configuration) Events(ctx context.Context, obj *model.Configuration) (*model.EventConnection, error) {
+func (r *configuration) Events(ctx context.Context, obj *model.Configuration, limit *int) (*model.EventConnection, error) {
 	e := &events{clients: r.clients}
-	return e.Resolve(ctx, &corev1.ObjectReference{
-		APIVersion: obj.APIVersion,
-		Kind:       obj.Kind,
-		Name:       obj.Metadata.Name,
-		UID:        types.UID(obj.Metadata.UID),
-	})
+	return e.Resolve(ctx, involved(obj.ID, obj.Metadata), limit)
 }
 
-func (r *configuration) Revisions(ctx context.Context, obj *model.Configuration) (*model.ConfigurationRevisionConnection, error) {
+func (r *configuration) Revisions(ctx context.Context, obj *model.Configuration, limit *int) (*model.ConfigurationRevisionConnection, error) {
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
@@ -64,7 +75,7 @@ func (r *configuration) Revisions(ctx context.Context, obj *model.Configuration)
 	}
 
 	in := &pkgv1.ConfigurationRevisionList{}
-	if err := c.List(ctx, in); err != nil {
+	if err := c.List(ctx, in, revisionsOf(obj.Metadata.Name)); err != nil {
 		graphql.AddError(ctx, errors.Wrap(err, errListConfigRevs))
 		return nil, nil
 	}
@@ -88,6 +99,7 @@ func (r *configuration) Revisions(ctx context.Context, obj *model.Configuration)
 	}
 
 	sort.Stable(out)
+	out.Nodes = truncate(ctx, limit, out.Nodes)
 	return out, nil
 }
 
@@ -103,7 +115,7 @@ func (r *configuration) ActiveRevision(ctx context.Context, obj *model.Configura
 	}
 
 	in := &pkgv1.ConfigurationRevisionList{}
-	if err := c.List(ctx, in); err != nil {
+	if err := c.List(ctx, in, revisionsOf(obj.Metadata.Name)); err != nil {
 		graphql.AddError(ctx, errors.Wrap(err, errListConfigRevs))
 		return nil, nil
 	}
@@ -130,25 +142,154 @@ func (r *configuration) ActiveRevision(ctx context.Context, obj *model.Configura
 	return nil, nil
 }
 
+func (r *configuration) Dependencies(ctx context.Context, obj *model.Configuration) ([]model.PackageDependency, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	// Dependencies are declared in a configuration package's metadata, not
+	// in the Configuration. The package manager records them in its lock when
+	// it installs each package revision.
+	l, err := getLock(ctx, c)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetLock))
+		return nil, nil
+	}
+
+	// Locked packages are named for the revision that installed them.
+	rev := ""
+	if obj.Status != nil && obj.Status.CurrentRevision != nil {
+		rev = *obj.Status.CurrentRevision
+	}
+	var lp *xunstructured.LockPackage
+	pkgs := l.GetPackages()
+	for i := range pkgs {
+		if rev != "" && pkgs[i].Name == rev {
+			lp = &pkgs[i]
+			break
+		}
+	}
+	if lp == nil {
+		return nil, nil
+	}
+
+	installed, err := getInstalledPackages(ctx, c)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errListPackages))
+		return nil, nil
+	}
+
+	out := make([]model.PackageDependency, 0, len(lp.Dependencies))
+	for _, d := range lp.Dependencies {
+		pd := model.PackageDependency{Package: d.Package, Type: model.GetPackageType(d.Type)}
+		if d.Constraints != "" {
+			pd.Constraints = pointer.StringPtr(d.Constraints)
+		}
+		for _, kr := range installed[d.Package] {
+			// Older versions of Crossplane don't record the type of each
+			// dependency, in which case any package from its source will do.
+			if pd.Type == nil || *pd.Type == kr.t {
+				pd.Installed = kr.kr
+				break
+			}
+		}
+		out = append(out, pd)
+	}
+	return out, nil
+}
+
+// getLock gets the package manager's lock, at whichever API version the API
+// server serves it.
+func getLock(ctx context.Context, c client.Client) (*xunstructured.Lock, error) {
+	gvk, err := servedVersion(c, gkLock, xunstructured.LockVersions)
+	if err != nil {
+		return nil, err
+	}
+	l := &xunstructured.Lock{}
+	l.SetGroupVersionKind(gvk)
+	err = c.Get(ctx, types.NamespacedName{Name: xunstructured.NameLock}, &l.Unstructured)
+	return l, err
+}
+
+type installedPackage struct {
+	t  model.PackageType
+	kr model.KubernetesResource
+}
+
+// getInstalledPackages returns all installed packages, keyed by their source.
+func getInstalledPackages(ctx context.Context, c client.Client) (map[string][]installedPackage, error) {
+	out := map[string][]installedPackage{}
+
+	pl := &pkgv1.ProviderList{}
+	if err := c.List(ctx, pl); err != nil {
+		return nil, err
+	}
+	for i := range pl.Items {
+		src := packageSource(pl.Items[i].Spec.Package)
+		out[src] = append(out[src], installedPackage{t: model.PackageTypeProvider, kr: model.GetProvider(&pl.Items[i])})
+	}
+
+	cl := &pkgv1.ConfigurationList{}
+	if err := c.List(ctx, cl); err != nil {
+		return nil, err
+	}
+	for i := range cl.Items {
+		src := packageSource(cl.Items[i].Spec.Package)
+		out[src] = append(out[src], installedPackage{t: model.PackageTypeConfiguration, kr: model.GetConfiguration(&cl.Items[i])})
+	}
+
+	fl, err := listServed(ctx, c, gkFunction, xunstructured.FunctionVersions)
+	// Not all versions of Crossplane support functions.
+	if kmeta.IsNoMatchError(err) {
+		return out, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := range fl.Items {
+		fn := model.GetFunction(&fl.Items[i])
+		src := packageSource(fn.Spec.Package)
+		out[src] = append(out[src], installedPackage{t: model.PackageTypeFunction, kr: fn})
+	}
+
+	return out, nil
+}
+
+type configurationSpec struct {
+	clients   ClientCache
+	namespace string
+}
+
+func (r *configurationSpec) CommonLabels(ctx context.Context, obj *model.ConfigurationSpec) (map[string]string, error) {
+	return getCommonLabels(ctx, r.clients, pkgv1.ConfigurationGroupVersionKind, obj.ConfigurationName)
+}
+
+func (r *configurationSpec) PullSecrets(ctx context.Context, obj *model.ConfigurationSpec) ([]model.PackagePullSecret, error) {
+	return getPullSecrets(ctx, r.clients, r.namespace, obj.PackagePullSecrets)
+}
+
 type configurationRevision struct {
 	clients ClientCache
 }
 
-func (r *configurationRevision) Events(ctx context.Context, obj *model.ConfigurationRevision) (*model.EventConnection, error) {
+func (r *configurationRevision) Events(ctx context.Context, obj *model.ConfigurationRevision, limit *int) (*model.EventConnection, error) {
 	e := &events{clients: r.clients}
-	return e.Resolve(ctx, &corev1.ObjectReference{
-		APIVersion: obj.APIVersion,
-		Kind:       obj.Kind,
-		Name:       obj.Metadata.Name,
-		UID:        types.UID(obj.Metadata.UID),
-	})
+	return e.Resolve(ctx, involved(obj.ID, obj.Metadata), limit)
 }
 
 type configurationRevisionStatus struct {
-	clients ClientCache
+	clients    ClientCache
+	categories model.CategoryIndex
 }
 
-func (r *configurationRevisionStatus) Objects(ctx context.Context, obj *model.ConfigurationRevisionStatus) (*model.KubernetesResourceConnection, error) {
+func (r *configurationRevisionStatus) Objects(ctx context.Context, obj *model.ConfigurationRevisionStatus, skipForbidden *bool) (*model.KubernetesResourceConnection, error) { //nolint:gocyclo
+	// This isn't _really_ that complex; it's a long but simple loop.
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
@@ -159,39 +300,126 @@ func (r *configurationRevisionStatus) Objects(ctx context.Context, obj *model.Co
 		return nil, nil
 	}
 
-	out := &model.KubernetesResourceConnection{
-		Nodes: make([]model.KubernetesResource, 0, len(obj.ObjectRefs)),
+	skip := skipForbidden != nil && *skipForbidden
+
+	// Crossplane lints configuration packages to ensure they only contain
+	// allowed kinds of object, but this isn't enforced at the API level.
+	// We filter out anything else, just in case. We group the rest by kind
+	// so that we can list each kind once, rather than getting every object;
+	// a configuration may install hundreds of Compositions.
+	kinds := make([]schema.GroupVersionKind, 0)
+	refs := make(map[schema.GroupVersionKind][]int)
+	for i, ref := range obj.ObjectRefs {
+		gvk := schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind)
+		if !configurationObjectKinds[gvk.GroupKind()] {
+			continue
+		}
+		if _, ok := refs[gvk]; !ok {
+			kinds = append(kinds, gvk)
+		}
+		refs[gvk] = append(refs[gvk], i)
 	}
 
-	for _, ref := range obj.ObjectRefs {
-		// Crossplane lints configuration packages to ensure they only contain XRDs and Compositions
-		// but this isn't enforced at the API level. We filter out anything that
-		// isn't a CRD, just in case.
-		if strings.Split(ref.APIVersion, "/")[0] != extv1.Group {
-			continue
+	nodes := make([]model.KubernetesResource, len(obj.ObjectRefs))
+	denied := make([]*model.AccessDeniedObject, len(obj.ObjectRefs))
+	forEach(ctx, len(kinds), func(k int) {
+		gvk := kinds[k]
+
+		objs, err := listConfigurationObjects(ctx, c, gvk)
+		if err != nil {
+			// Record that the caller may not read any object of this kind
+			// when we're skipping forbidden objects.
+			if skip && kerrors.IsForbidden(err) {
+				for _, i := range refs[gvk] {
+					ref := obj.ObjectRefs[i]
+					denied[i] = &model.AccessDeniedObject{
+						APIVersion: ref.APIVersion,
+						Kind:       ref.Kind,
+						Name:       ref.Name,
+						Message:    err.Error(),
+					}
+				}
+				return
+			}
+			graphql.AddError(ctx, errors.Wrapf(err, errFmtListKind, gvk.Kind))
+			return
 		}
 
-		switch ref.Kind {
-		case extv1.CompositeResourceDefinitionKind:
-			xrd := &extv1.CompositeResourceDefinition{}
-			if err := c.Get(ctx, types.NamespacedName{Name: ref.Name}, xrd); err != nil {
-				graphql.AddError(ctx, errors.Wrap(err, errGetXRD))
+		for _, i := range refs[gvk] {
+			ref := obj.ObjectRefs[i]
+			o, ok := objs[ref.Name]
+			if !ok {
+				graphql.AddError(ctx, errors.Errorf(errFmtObjectNotFound, ref.Kind, ref.Name))
 				continue
 			}
 
-			out.Nodes = append(out.Nodes, model.GetCompositeResourceDefinition(xrd))
-			out.TotalCount++
-		case extv1.CompositionKind:
-			cmp := &extv1.Composition{}
-			if err := c.Get(ctx, types.NamespacedName{Name: ref.Name}, cmp); err != nil {
-				graphql.AddError(ctx, errors.Wrap(err, errGetComp))
-				continue
+			switch o := o.(type) {
+			case *extv1.CompositeResourceDefinition:
+				nodes[i] = model.GetCompositeResourceDefinition(o)
+			case *extv1.Composition:
+				nodes[i] = model.GetComposition(o)
+			case *unstructured.Unstructured:
+				kr, err := model.GetKubernetesResource(o, r.categories)
+				if err != nil {
+					graphql.AddError(ctx, errors.Wrap(err, errModelResource))
+					continue
+				}
+				nodes[i] = kr
 			}
+		}
+	})
 
-			out.Nodes = append(out.Nodes, model.GetComposition(cmp))
-			out.TotalCount++
+	out := &model.KubernetesResourceConnection{
+		Nodes: make([]model.KubernetesResource, 0, len(nodes)),
+	}
+	for _, kr := range nodes {
+		if kr == nil {
+			continue
+		}
+		out.Nodes = append(out.Nodes, kr)
+		out.TotalCount++
+	}
+	for _, d := range denied {
+		if d == nil {
+			continue
 		}
+		out.AccessDenied = append(out.AccessDenied, *d)
 	}
 
 	return out, nil
 }
+
+// listConfigurationObjects lists every object of the supplied kind, which must
+// be a kind of object a configuration may contain, keyed by name.
+func listConfigurationObjects(ctx context.Context, c client.Client, gvk schema.GroupVersionKind) (map[string]client.Object, error) {
+	out := make(map[string]client.Object)
+	switch gvk.GroupKind() {
+	case extv1.CompositeResourceDefinitionGroupVersionKind.GroupKind():
+		l := &extv1.CompositeResourceDefinitionList{}
+		if err := c.List(ctx, l); err != nil {
+			return nil, err
+		}
+		for i := range l.Items {
+			out[l.Items[i].GetName()] = &l.Items[i]
+		}
+	case extv1.CompositionGroupVersionKind.GroupKind():
+		l := &extv1.CompositionList{}
+		if err := c.List(ctx, l); err != nil {
+			return nil, err
+		}
+		for i := range l.Items {
+			out[l.Items[i].GetName()] = &l.Items[i]
+		}
+	default:
+		// Kinds we build against no typed API for, e.g. Functions.
+		l := &unstructured.UnstructuredList{}
+		l.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := c.List(ctx, l); err != nil {
+			return nil, err
+		}
+		for i := range l.Items {
+			out[l.Items[i].GetName()] = &l.Items[i]
+		}
+	}
+	return out, nil
+}
@@ -16,6 +16,7 @@ package resolvers
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/99designs/gqlgen/graphql"
@@ -23,7 +24,9 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 	"github.com/vektah/gqlparser/v2/gqlerror"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -38,14 +41,20 @@ import (
 	"github.com/upbound/xgql/internal/clients"
 	"github.com/upbound/xgql/internal/graph/generated"
 	"github.com/upbound/xgql/internal/graph/model"
+	xunstructured "github.com/upbound/xgql/internal/unstructured"
 )
 
 var (
 	_ generated.ConfigurationResolver               = &configuration{}
+	_ generated.ConfigurationSpecResolver           = &configurationSpec{}
 	_ generated.ConfigurationRevisionResolver       = &configurationRevision{}
 	_ generated.ConfigurationRevisionStatusResolver = &configurationRevisionStatus{}
 )
 
+// The API version at which the Crossplane versions that introduced functions
+// serve them. Revisions record the version at which they installed a function.
+const apiVersionFunction = "pkg.crossplane.io/v1beta1"
+
 func TestConfigurationRevisions(t *testing.T) {
 	errBoom := errors.New("boom")
 
@@ -75,8 +84,9 @@ func TestConfigurationRevisions(t *testing.T) {
 	other := pkgv1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "not-ours"}}
 
 	type args struct {
-		ctx context.Context
-		obj *model.Configuration
+		ctx   context.Context
+		obj   *model.Configuration
+		limit *int
 	}
 	type want struct {
 		crc  *model.ConfigurationRevisionConnection
@@ -113,6 +123,9 @@ func TestConfigurationRevisions(t *testing.T) {
 			}),
 			args: args{
 				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.Configuration{
+					Metadata: &model.ObjectMeta{UID: uid},
+				},
 			},
 			want: want{
 				errs: gqlerror.List{
@@ -120,6 +133,32 @@ func TestConfigurationRevisions(t *testing.T) {
 				},
 			},
 		},
+		"SelectRevisionsByPackage": {
+			reason: "We should only list the revisions labelled with our package's name.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: func(_ context.Context, _ client.ObjectList, opts ...client.ListOption) error {
+						lo := &client.ListOptions{}
+						lo.ApplyOptions(opts)
+						if lo.LabelSelector == nil || lo.LabelSelector.String() != labelParentPackage+"=coolconfig" {
+							return errBoom
+						}
+						return nil
+					},
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.Configuration{
+					Metadata: &model.ObjectMeta{Name: "coolconfig", UID: uid},
+				},
+			},
+			want: want{
+				crc: &model.ConfigurationRevisionConnection{
+					Nodes: []model.ConfigurationRevision{},
+				},
+			},
+		},
 		"AllRevisions": {
 			reason: "We should successfully return any revisions we own that we can list and model.",
 			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
@@ -153,7 +192,7 @@ func TestConfigurationRevisions(t *testing.T) {
 
 			// Our GraphQL resolvers never return errors. We instead add an
 			// error to the GraphQL context and return early.
-			got, err := c.Revisions(tc.args.ctx, tc.args.obj)
+			got, err := c.Revisions(tc.args.ctx, tc.args.obj, tc.args.limit)
 			errs := graphql.GetErrors(tc.args.ctx)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
@@ -238,6 +277,9 @@ func TestConfigurationActiveRevision(t *testing.T) {
 			}),
 			args: args{
 				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.Configuration{
+					Metadata: &model.ObjectMeta{UID: uid},
+				},
 			},
 			want: want{
 				errs: gqlerror.List{
@@ -313,15 +355,161 @@ func TestConfigurationActiveRevision(t *testing.T) {
 	}
 }
 
+func TestConfigurationDependencies(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	rev := "config-abc"
+	cfg := &model.Configuration{Status: &model.ConfigurationStatus{CurrentRevision: &rev}}
+
+	lock := map[string]interface{}{
+		"packages": []interface{}{
+			map[string]interface{}{
+				"name":    rev,
+				"type":    "Configuration",
+				"source":  "example.org/config",
+				"version": "v1.0.0",
+				"dependencies": []interface{}{
+					map[string]interface{}{"package": "example.org/provider", "type": "Provider", "constraints": ">=v1.0.0"},
+					map[string]interface{}{"package": "example.org/function", "type": "Function"},
+				},
+			},
+		},
+	}
+
+	p := pkgv1.Provider{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-provider"},
+		Spec:       pkgv1.ProviderSpec{PackageSpec: pkgv1.PackageSpec{Package: "example.org/provider:v1.1.0"}},
+	}
+
+	provider := model.PackageTypeProvider
+	function := model.PackageTypeFunction
+	constraints := ">=v1.0.0"
+
+	type want struct {
+		deps []model.PackageDependency
+		err  error
+		errs gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		obj     *model.Configuration
+		want    want
+	}{
+		"GetClientError": {
+			reason: "If we can't get a client we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{}, errBoom
+			}),
+			obj: cfg,
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetClient).Error()),
+				},
+			},
+		},
+		"GetLockError": {
+			reason: "If we can't get the package lock we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{MockGet: test.NewMockGetFn(errBoom)}, nil
+			}),
+			obj: cfg,
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetLock).Error()),
+				},
+			},
+		},
+		"NotLocked": {
+			reason: "If the configuration's current revision is not locked we should return nil.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{MockGet: test.NewMockGetFn(nil)}, nil
+			}),
+			obj:  cfg,
+			want: want{},
+		},
+		"Success": {
+			reason: "We should return the configuration's dependencies, and any installed packages that satisfy them.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						u := obj.(*unstructured.Unstructured)
+						u.Object = lock
+						return nil
+					}),
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						if l, ok := obj.(*pkgv1.ProviderList); ok {
+							l.Items = []pkgv1.Provider{p}
+						}
+						return nil
+					}),
+				}, nil
+			}),
+			obj: cfg,
+			want: want{
+				deps: []model.PackageDependency{
+					{
+						Package:     "example.org/provider",
+						Type:        &provider,
+						Constraints: &constraints,
+						Installed:   model.GetProvider(&p),
+					},
+					{
+						Package: "example.org/function",
+						Type:    &function,
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover)
+			c := &configuration{clients: tc.clients}
+			got, err := c.Dependencies(ctx, tc.obj)
+			errs := graphql.GetErrors(ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.Dependencies(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.Dependencies(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.deps, got, cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\nc.Dependencies(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestConfigurationRevisionStatusObjects(t *testing.T) {
 	errBoom := errors.New("boom")
 
-	gxrd := model.GetCompositeResourceDefinition(&extv1.CompositeResourceDefinition{})
-	gcmp := model.GetComposition(&extv1.Composition{})
+	xrd := extv1.CompositeResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "coolxrd"}}
+	gxrd := model.GetCompositeResourceDefinition(&xrd)
+	comp := extv1.Composition{ObjectMeta: metav1.ObjectMeta{Name: "coolcomp"}}
+	gcmp := model.GetComposition(&comp)
+	comp2 := extv1.Composition{ObjectMeta: metav1.ObjectMeta{Name: "coolcomp2"}}
+	gcmp2 := model.GetComposition(&comp2)
+
+	// How many times the ListOncePerKind case listed each kind of object.
+	lists := map[string]int{}
+
+	errForbidden := kerrors.NewForbidden(schema.GroupResource{Group: extv1.Group, Resource: "compositions"}, "secret", errors.New("nope"))
+	skip := true
+
+	fn := &unstructured.Unstructured{}
+	fn.SetAPIVersion(apiVersionFunction)
+	fn.SetKind(xunstructured.KindFunction)
+	fn.SetName("cool")
+	gfn := model.GetFunction(fn)
 
 	type args struct {
-		ctx context.Context
-		obj *model.ConfigurationRevisionStatus
+		ctx           context.Context
+		obj           *model.ConfigurationRevisionStatus
+		skipForbidden *bool
 	}
 	type want struct {
 		krc  *model.KubernetesResourceConnection
@@ -350,7 +538,7 @@ func TestConfigurationRevisionStatusObjects(t *testing.T) {
 			},
 		},
 		"UnknownObject": {
-			reason: "We should not attempt to get an object that doesn't seem to be part of the API extensions group.",
+			reason: "We should not attempt to get an object that is not an allowed kind.",
 			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
 				return &test.MockClient{}, nil
 			}),
@@ -371,13 +559,16 @@ func TestConfigurationRevisionStatusObjects(t *testing.T) {
 				},
 			},
 		},
-		"GetXRDError": {
-			reason: "If we can't get an XRD we should add the error to the GraphQL context and continue.",
+		"ListXRDsError": {
+			reason: "If we can't list XRDs we should add the error to the GraphQL context and continue.",
 			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
 				return &test.MockClient{
-					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
-						if _, ok := obj.(*extv1.CompositeResourceDefinition); ok {
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						switch l := obj.(type) {
+						case *extv1.CompositeResourceDefinitionList:
 							return errBoom
+						case *extv1.CompositionList:
+							l.Items = []extv1.Composition{comp}
 						}
 						return nil
 					}),
@@ -390,10 +581,12 @@ func TestConfigurationRevisionStatusObjects(t *testing.T) {
 						{
 							APIVersion: schema.GroupVersion{Group: extv1.Group, Version: extv1.Version}.String(),
 							Kind:       extv1.CompositeResourceDefinitionKind,
+							Name:       "coolxrd",
 						},
 						{
 							APIVersion: schema.GroupVersion{Group: extv1.Group, Version: extv1.Version}.String(),
 							Kind:       extv1.CompositionKind,
+							Name:       "coolcomp",
 						},
 					},
 				},
@@ -406,16 +599,19 @@ func TestConfigurationRevisionStatusObjects(t *testing.T) {
 					TotalCount: 1,
 				},
 				errs: gqlerror.List{
-					gqlerror.Errorf(errors.Wrap(errBoom, errGetXRD).Error()),
+					gqlerror.Errorf(errors.Wrapf(errBoom, errFmtListKind, extv1.CompositeResourceDefinitionKind).Error()),
 				},
 			},
 		},
-		"GetCompositionError": {
-			reason: "If we can't get a Composition we should add the error to the GraphQL context and continue.",
+		"ListCompositionsError": {
+			reason: "If we can't list Compositions we should add the error to the GraphQL context and continue.",
 			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
 				return &test.MockClient{
-					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
-						if _, ok := obj.(*extv1.Composition); ok {
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						switch l := obj.(type) {
+						case *extv1.CompositeResourceDefinitionList:
+							l.Items = []extv1.CompositeResourceDefinition{xrd}
+						case *extv1.CompositionList:
 							return errBoom
 						}
 						return nil
@@ -429,10 +625,12 @@ func TestConfigurationRevisionStatusObjects(t *testing.T) {
 						{
 							APIVersion: schema.GroupVersion{Group: extv1.Group, Version: extv1.Version}.String(),
 							Kind:       extv1.CompositionKind,
+							Name:       "coolcomp",
 						},
 						{
 							APIVersion: schema.GroupVersion{Group: extv1.Group, Version: extv1.Version}.String(),
 							Kind:       extv1.CompositeResourceDefinitionKind,
+							Name:       "coolxrd",
 						},
 					},
 				},
@@ -445,7 +643,233 @@ func TestConfigurationRevisionStatusObjects(t *testing.T) {
 					TotalCount: 1,
 				},
 				errs: gqlerror.List{
-					gqlerror.Errorf(errors.Wrap(errBoom, errGetComp).Error()),
+					gqlerror.Errorf(errors.Wrapf(errBoom, errFmtListKind, extv1.CompositionKind).Error()),
+				},
+			},
+		},
+		"ListFunctionsError": {
+			reason: "If we can't list Functions we should add the error to the GraphQL context and continue.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.ConfigurationRevisionStatus{
+					ObjectRefs: []xpv1.TypedReference{
+						{
+							APIVersion: apiVersionFunction,
+							Kind:       xunstructured.KindFunction,
+							Name:       "cool",
+						},
+					},
+				},
+			},
+			want: want{
+				krc: &model.KubernetesResourceConnection{
+					Nodes:      []model.KubernetesResource{},
+					TotalCount: 0,
+				},
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrapf(errBoom, errFmtListKind, xunstructured.KindFunction).Error()),
+				},
+			},
+		},
+		"ObjectNotFound": {
+			reason: "If an object we reference doesn't exist we should add an error to the GraphQL context and continue.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						switch l := obj.(type) {
+						case *extv1.CompositeResourceDefinitionList:
+							l.Items = []extv1.CompositeResourceDefinition{xrd}
+						case *extv1.CompositionList:
+							l.Items = []extv1.Composition{comp}
+						}
+						return nil
+					}),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.ConfigurationRevisionStatus{
+					ObjectRefs: []xpv1.TypedReference{
+						{
+							APIVersion: schema.GroupVersion{Group: extv1.Group, Version: extv1.Version}.String(),
+							Kind:       extv1.CompositionKind,
+							Name:       "missing",
+						},
+						{
+							APIVersion: schema.GroupVersion{Group: extv1.Group, Version: extv1.Version}.String(),
+							Kind:       extv1.CompositeResourceDefinitionKind,
+							Name:       "coolxrd",
+						},
+					},
+				},
+			},
+			want: want{
+				krc: &model.KubernetesResourceConnection{
+					Nodes: []model.KubernetesResource{
+						gxrd,
+					},
+					TotalCount: 1,
+				},
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Errorf(errFmtObjectNotFound, extv1.CompositionKind, "missing").Error()),
+				},
+			},
+		},
+		"ListOncePerKind": {
+			reason: "We should list each kind of object once, regardless of how many objects of that kind we reference.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+						lists[fmt.Sprintf("%T", obj)]++
+						if lists[fmt.Sprintf("%T", obj)] > 1 {
+							return errBoom
+						}
+						if l, ok := obj.(*extv1.CompositionList); ok {
+							l.Items = []extv1.Composition{comp, comp2}
+						}
+						return nil
+					},
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.ConfigurationRevisionStatus{
+					ObjectRefs: []xpv1.TypedReference{
+						{
+							APIVersion: schema.GroupVersion{Group: extv1.Group, Version: extv1.Version}.String(),
+							Kind:       extv1.CompositionKind,
+							Name:       "coolcomp",
+						},
+						{
+							APIVersion: schema.GroupVersion{Group: extv1.Group, Version: extv1.Version}.String(),
+							Kind:       extv1.CompositionKind,
+							Name:       "coolcomp2",
+						},
+					},
+				},
+			},
+			want: want{
+				krc: &model.KubernetesResourceConnection{
+					Nodes: []model.KubernetesResource{
+						gcmp,
+						gcmp2,
+					},
+					TotalCount: 2,
+				},
+			},
+		},
+		"SkipForbidden": {
+			reason: "If we're skipping forbidden objects we should return a placeholder for objects we may not read, and the objects we may.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						switch l := obj.(type) {
+						case *extv1.CompositeResourceDefinitionList:
+							l.Items = []extv1.CompositeResourceDefinition{xrd}
+						case *extv1.CompositionList:
+							return errForbidden
+						}
+						return nil
+					}),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.ConfigurationRevisionStatus{
+					ObjectRefs: []xpv1.TypedReference{
+						{
+							APIVersion: schema.GroupVersion{Group: extv1.Group, Version: extv1.Version}.String(),
+							Kind:       extv1.CompositionKind,
+							Name:       "secret",
+						},
+						{
+							APIVersion: schema.GroupVersion{Group: extv1.Group, Version: extv1.Version}.String(),
+							Kind:       extv1.CompositeResourceDefinitionKind,
+							Name:       "coolxrd",
+						},
+					},
+				},
+				skipForbidden: &skip,
+			},
+			want: want{
+				krc: &model.KubernetesResourceConnection{
+					Nodes: []model.KubernetesResource{
+						gxrd,
+					},
+					TotalCount: 1,
+					AccessDenied: []model.AccessDeniedObject{{
+						APIVersion: schema.GroupVersion{Group: extv1.Group, Version: extv1.Version}.String(),
+						Kind:       extv1.CompositionKind,
+						Name:       "secret",
+						Message:    errForbidden.Error(),
+					}},
+				},
+			},
+		},
+		"ForbiddenNotSkipped": {
+			reason: "If we're not skipping forbidden objects we should add the error to the GraphQL context.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(errForbidden),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.ConfigurationRevisionStatus{
+					ObjectRefs: []xpv1.TypedReference{
+						{
+							APIVersion: schema.GroupVersion{Group: extv1.Group, Version: extv1.Version}.String(),
+							Kind:       extv1.CompositionKind,
+							Name:       "secret",
+						},
+					},
+				},
+			},
+			want: want{
+				krc: &model.KubernetesResourceConnection{
+					Nodes:      []model.KubernetesResource{},
+					TotalCount: 0,
+				},
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrapf(errForbidden, errFmtListKind, extv1.CompositionKind).Error()),
+				},
+			},
+		},
+		"Function": {
+			reason: "Functions should be included in a configuration's objects.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						l := obj.(*unstructured.UnstructuredList)
+						if diff := cmp.Diff(schema.FromAPIVersionAndKind(apiVersionFunction, xunstructured.KindFunction+"List"), l.GroupVersionKind()); diff != "" {
+							t.Errorf("-want GVK, +got GVK:\n%s", diff)
+						}
+						l.Items = []unstructured.Unstructured{*fn}
+						return nil
+					}),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.ConfigurationRevisionStatus{
+					ObjectRefs: []xpv1.TypedReference{
+						{
+							APIVersion: apiVersionFunction,
+							Kind:       xunstructured.KindFunction,
+							Name:       "cool",
+						},
+					},
+				},
+			},
+			want: want{
+				krc: &model.KubernetesResourceConnection{
+					Nodes:      []model.KubernetesResource{gfn},
+					TotalCount: 1,
 				},
 			},
 		},
@@ -457,7 +881,7 @@ func TestConfigurationRevisionStatusObjects(t *testing.T) {
 
 			// Our GraphQL resolvers never return errors. We instead add an
 			// error to the GraphQL context and return early.
-			got, err := s.Objects(tc.args.ctx, tc.args.obj)
+			got, err := s.Objects(tc.args.ctx, tc.args.obj, tc.args.skipForbidden)
 			errs := graphql.GetErrors(tc.args.ctx)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
@@ -466,7 +890,7 @@ func TestConfigurationRevisionStatusObjects(t *testing.T) {
 			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ns.Objects(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
 			}
-			if diff := cmp.Diff(tc.want.krc, got, cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+			if diff := cmp.Diff(tc.want.krc, got, cmpopts.IgnoreFields(model.GenericResource{}, "Unstructured"), cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
 				t.Errorf("\n%s\ns.Objects(...): -want, +got:\n%s\n", tc.reason, diff)
 			}
 		})
@@ -22,9 +22,12 @@ import (
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/upbound/xgql/internal/auth"
+	"github.com/upbound/xgql/internal/clients"
 	"github.com/upbound/xgql/internal/graph/model"
 )
 
@@ -38,7 +41,7 @@ type events struct {
 	clients ClientCache
 }
 
-func (r *events) Resolve(ctx context.Context, obj *corev1.ObjectReference) (*model.EventConnection, error) {
+func (r *events) Resolve(ctx context.Context, obj *corev1.ObjectReference, limit *int) (*model.EventConnection, error) {
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
@@ -67,6 +70,7 @@ func (r *events) Resolve(ctx context.Context, obj *corev1.ObjectReference) (*mod
 		}
 
 		sort.Stable(sort.Reverse(out))
+		out.Nodes = truncate(ctx, limit, out.Nodes)
 		return out, nil
 	}
 
@@ -92,9 +96,102 @@ func (r *events) Resolve(ctx context.Context, obj *corev1.ObjectReference) (*mod
 	}
 
 	sort.Stable(sort.Reverse(out))
+	out.Nodes = truncate(ctx, limit, out.Nodes)
 	return out, nil
 }
 
+// An eventSelector selects events using API server side field selectors.
+// Unset fields select all events.
+type eventSelector struct {
+	Namespace    string
+	InvolvedKind string
+	Type         *model.EventType
+}
+
+// Selects returns true if the selector selects a subset of events.
+func (s eventSelector) Selects() bool {
+	return s.Namespace != "" || s.InvolvedKind != "" || s.Type != nil
+}
+
+// ListOptions returns the list options used to select events.
+func (s eventSelector) ListOptions() []client.ListOption {
+	lo := []client.ListOption{}
+	if s.Namespace != "" {
+		lo = append(lo, client.InNamespace(s.Namespace))
+	}
+
+	// We build the field selector in a fixed order, rather than from a map,
+	// so that it's the same from one request to the next.
+	f := []fields.Selector{}
+	if s.InvolvedKind != "" {
+		f = append(f, fields.OneTermEqualSelector("involvedObject.kind", s.InvolvedKind))
+	}
+	if s.Type != nil {
+		switch *s.Type {
+		case model.EventTypeNormal:
+			f = append(f, fields.OneTermEqualSelector("type", corev1.EventTypeNormal))
+		case model.EventTypeWarning:
+			f = append(f, fields.OneTermEqualSelector("type", corev1.EventTypeWarning))
+		}
+	}
+	if len(f) > 0 {
+		lo = append(lo, client.MatchingFieldsSelector{Selector: fields.AndSelectors(f...)})
+	}
+	return lo
+}
+
+// Select events across all namespaces. Unlike Resolve, Select reads events
+// directly from the API server so that it may filter them server side using
+// field selectors, rather than listing every event in the cluster. This is
+// intended for cluster-wide event feeds, like recent warnings.
+func (r *events) Select(ctx context.Context, sel eventSelector, limit *int) (*model.EventConnection, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds, clients.Uncached())
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	in := &corev1.EventList{}
+	if err := c.List(ctx, in, sel.ListOptions()...); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errListEvents))
+		return nil, nil
+	}
+
+	out := &model.EventConnection{
+		Nodes:      make([]model.Event, 0, len(in.Items)),
+		TotalCount: len(in.Items),
+	}
+	for i := range in.Items {
+		out.Nodes = append(out.Nodes, model.GetEvent(&in.Items[i]))
+	}
+
+	// We can't ask the API server for the most recent events, so we must sort
+	// and limit them ourselves.
+	sort.Stable(sort.Reverse(out))
+	out.Nodes = truncate(ctx, limit, out.Nodes)
+	return out, nil
+}
+
+// involved returns a reference to the supplied resource that may be used to
+// resolve the events involving it. Every modeled resource has an ID and
+// metadata, so its events may be resolved without knowing its type.
+func involved(id model.ReferenceID, md *model.ObjectMeta) *corev1.ObjectReference {
+	ref := &corev1.ObjectReference{
+		APIVersion: id.APIVersion,
+		Kind:       id.Kind,
+		Namespace:  id.Namespace,
+		Name:       id.Name,
+	}
+	if md != nil {
+		ref.UID = types.UID(md.UID)
+	}
+	return ref
+}
+
 func involves(e *corev1.Event, ref *corev1.ObjectReference) bool {
 	// The supplied object won't always have a UID, but the the event's object
 	// reference should. This test should be sufficient for most resolvers; the
@@ -127,7 +224,8 @@ func involves(e *corev1.Event, ref *corev1.ObjectReference) bool {
 }
 
 type event struct {
-	clients ClientCache
+	clients    ClientCache
+	categories model.CategoryIndex
 }
 
 func (r *event) InvolvedObject(ctx context.Context, obj *model.Event) (model.KubernetesResource, error) {
@@ -153,7 +251,7 @@ func (r *event) InvolvedObject(ctx context.Context, obj *model.Event) (model.Kub
 		return nil, nil
 	}
 
-	out, err := model.GetKubernetesResource(u)
+	out, err := model.GetKubernetesResource(u, r.categories)
 	if err != nil {
 		graphql.AddError(ctx, errors.Wrap(err, errModelInvolved))
 		return nil, nil
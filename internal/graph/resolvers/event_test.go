@@ -17,6 +17,7 @@ package resolvers
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/google/go-cmp/cmp"
@@ -50,9 +51,12 @@ func TestEvent(t *testing.T) {
 		InvolvedObject: corev1.ObjectReference{UID: "wat"},
 	}
 
+	one := 1
+
 	type args struct {
 		ctx      context.Context
 		involved *corev1.ObjectReference
+		limit    *int
 	}
 	type want struct {
 		ec   *model.EventConnection
@@ -137,6 +141,27 @@ func TestEvent(t *testing.T) {
 				},
 			},
 		},
+		"ListLimitedEvents": {
+			reason: "We should return no more than the supplied limit of events, but count all events.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						*obj.(*corev1.EventList) = corev1.EventList{Items: []corev1.Event{related, unrelated}}
+						return nil
+					}),
+				}, nil
+			}),
+			args: args{
+				ctx:   graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				limit: &one,
+			},
+			want: want{
+				ec: &model.EventConnection{
+					Nodes:      []model.Event{model.GetEvent(&related)},
+					TotalCount: 2,
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -145,7 +170,7 @@ func TestEvent(t *testing.T) {
 
 			// Our GraphQL resolvers never return errors. We instead add an
 			// error to the GraphQL context and return early.
-			got, err := e.Resolve(tc.args.ctx, tc.args.involved)
+			got, err := e.Resolve(tc.args.ctx, tc.args.involved, tc.args.limit)
 			errs := graphql.GetErrors(tc.args.ctx)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
@@ -161,6 +186,120 @@ func TestEvent(t *testing.T) {
 	}
 }
 
+func TestEventsSelect(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	older := corev1.Event{
+		ObjectMeta:    metav1.ObjectMeta{Name: "older"},
+		LastTimestamp: metav1.NewTime(time.Unix(1, 0)),
+	}
+	newer := corev1.Event{
+		ObjectMeta:    metav1.ObjectMeta{Name: "newer"},
+		LastTimestamp: metav1.NewTime(time.Unix(2, 0)),
+	}
+
+	warning := model.EventTypeWarning
+	one := 1
+
+	type args struct {
+		ctx   context.Context
+		sel   eventSelector
+		limit *int
+	}
+	type want struct {
+		ec   *model.EventConnection
+		err  error
+		errs gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"GetClientError": {
+			reason: "If we can't get a client we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{}, errBoom
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetClient).Error()),
+				},
+			},
+		},
+		"ListEventsError": {
+			reason: "If we can't list events we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errListEvents).Error()),
+				},
+			},
+		},
+		"SelectEvents": {
+			reason: "We should select events using field selectors, and return the most recent events up to the limit.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: func(_ context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+						lo := &client.ListOptions{}
+						lo.ApplyOptions(opts)
+						if lo.Namespace != "default" {
+							return errors.Errorf("unexpected namespace %q", lo.Namespace)
+						}
+						want := "involvedObject.kind=Pod,type=Warning"
+						if got := lo.FieldSelector.String(); got != want {
+							return errors.Errorf("want field selector %q, got %q", want, got)
+						}
+						*obj.(*corev1.EventList) = corev1.EventList{Items: []corev1.Event{older, newer}}
+						return nil
+					},
+				}, nil
+			}),
+			args: args{
+				ctx:   graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				sel:   eventSelector{Namespace: "default", InvolvedKind: "Pod", Type: &warning},
+				limit: &one,
+			},
+			want: want{
+				ec: &model.EventConnection{
+					Nodes:      []model.Event{model.GetEvent(&newer)},
+					TotalCount: 2,
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &events{clients: tc.clients}
+			got, err := e.Select(tc.args.ctx, tc.args.sel, tc.args.limit)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Select(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Select(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ec, got, cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\ne.Select(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestInvolves(t *testing.T) {
 	wuid := &corev1.ObjectReference{UID: "so-unique"}
 
@@ -265,12 +404,58 @@ func TestInvolves(t *testing.T) {
 	}
 }
 
+func TestInvolved(t *testing.T) {
+	id := model.ReferenceID{APIVersion: "example.org/v1", Kind: "Example", Namespace: "default", Name: "cool"}
+
+	type args struct {
+		id model.ReferenceID
+		md *model.ObjectMeta
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   *corev1.ObjectReference
+	}{
+		"WithMetadata": {
+			reason: "The reference should include the resource's UID, so that its events can be matched by UID.",
+			args:   args{id: id, md: &model.ObjectMeta{Name: "cool", UID: "so-unique"}},
+			want: &corev1.ObjectReference{
+				APIVersion: "example.org/v1",
+				Kind:       "Example",
+				Namespace:  "default",
+				Name:       "cool",
+				UID:        "so-unique",
+			},
+		},
+		"WithoutMetadata": {
+			reason: "The reference should be derived from the resource's ID if it has no metadata.",
+			args:   args{id: id},
+			want: &corev1.ObjectReference{
+				APIVersion: "example.org/v1",
+				Kind:       "Example",
+				Namespace:  "default",
+				Name:       "cool",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := involved(tc.args.id, tc.args.md)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ninvolved(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 var _ generated.EventResolver = &event{}
 
 func TestEventInvolvedObject(t *testing.T) {
 	errBoom := errors.New("boom")
 
-	gu, _ := model.GetKubernetesResource(&unstructured.Unstructured{})
+	gu, _ := model.GetKubernetesResource(&unstructured.Unstructured{}, nil)
 
 	type args struct {
 		ctx context.Context
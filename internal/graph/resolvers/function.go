@@ -0,0 +1,100 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolvers
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/upbound/xgql/internal/auth"
+	"github.com/upbound/xgql/internal/graph/model"
+	xunstructured "github.com/upbound/xgql/internal/unstructured"
+)
+
+const errGetRC = "cannot get deployment runtime config"
+
+type function struct {
+	clients ClientCache
+}
+
+func (r *function) Events(ctx context.Context, obj *model.Function, limit *int) (*model.EventConnection, error) {
+	e := &events{clients: r.clients}
+	return e.Resolve(ctx, involved(obj.ID, obj.Metadata), limit)
+}
+
+type functionSpec struct {
+	clients ClientCache
+}
+
+func (r *functionSpec) RuntimeConfig(ctx context.Context, obj *model.FunctionSpec) (*model.DeploymentRuntimeConfig, error) {
+	if obj.RuntimeConfigName == "" {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	// Deployment runtime configs may not be served at the same API version as
+	// the function that references them.
+	gvk, err := servedVersion(c, gkDeploymentRuntimeConfig, xunstructured.DeploymentRuntimeConfigVersions)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetRC))
+		return nil, nil
+	}
+
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	err = c.Get(ctx, types.NamespacedName{Name: obj.RuntimeConfigName}, u)
+	if kerrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetRC))
+		return nil, nil
+	}
+
+	out := model.GetDeploymentRuntimeConfig(u)
+	return &out, nil
+}
+
+type deploymentRuntimeConfig struct {
+	clients ClientCache
+}
+
+func (r *deploymentRuntimeConfig) Events(ctx context.Context, obj *model.DeploymentRuntimeConfig, limit *int) (*model.EventConnection, error) {
+	e := &events{clients: r.clients}
+	return e.Resolve(ctx, involved(obj.ID, obj.Metadata), limit)
+}
+
+type lock struct {
+	clients ClientCache
+}
+
+func (r *lock) Events(ctx context.Context, obj *model.Lock, limit *int) (*model.EventConnection, error) {
+	e := &events{clients: r.clients}
+	return e.Resolve(ctx, involved(obj.ID, obj.Metadata), limit)
+}
@@ -0,0 +1,133 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolvers
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// DefaultNestedLimit is the default maximum number of nodes returned by a
+// connection that is nested within a list, e.g. the events of each provider
+// returned by the providers query.
+const DefaultNestedLimit = 50
+
+type limitKey struct{}
+
+// NestedLimit is a GraphQL server extension that limits the number of nodes
+// returned by connections that are nested within a list, unless the client
+// explicitly supplies a limit. This prevents naive queries (e.g. the events of
+// the revisions of every provider) from returning N×M nodes. A limit of zero
+// disables the default.
+type NestedLimit int
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationInterceptor
+} = NestedLimit(0)
+
+// ExtensionName returns the name of this extension.
+func (l NestedLimit) ExtensionName() string {
+	return "NestedLimit"
+}
+
+// Validate this extension.
+func (l NestedLimit) Validate(_ graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptOperation annotates the operation context with the limit.
+func (l NestedLimit) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	return next(context.WithValue(ctx, limitKey{}, int(l)))
+}
+
+// A NestedLimitFn is a NestedLimit that is determined as each operation starts,
+// for example so that it may be reconfigured while xgql is running.
+type NestedLimitFn func() int
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationInterceptor
+} = NestedLimitFn(nil)
+
+// ExtensionName returns the name of this extension.
+func (fn NestedLimitFn) ExtensionName() string {
+	return "NestedLimit"
+}
+
+// Validate this extension.
+func (fn NestedLimitFn) Validate(_ graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptOperation annotates the operation context with the limit.
+func (fn NestedLimitFn) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	return NestedLimit(fn()).InterceptOperation(ctx, next)
+}
+
+// maxNodes returns the maximum number of nodes a connection should return, or
+// zero if it should return all nodes. An explicitly supplied limit always
+// wins. Otherwise connections are only limited when they are nested within a
+// list.
+func maxNodes(ctx context.Context, explicit *int) int {
+	if explicit != nil {
+		if *explicit < 0 {
+			return 0
+		}
+		return *explicit
+	}
+	if !nested(ctx) {
+		return 0
+	}
+	l, _ := ctx.Value(limitKey{}).(int)
+	return l
+}
+
+// truncate returns the first maxNodes of the supplied nodes, or all of them if
+// maxNodes returns zero.
+func truncate[T any](ctx context.Context, explicit *int, nodes []T) []T {
+	if n := maxNodes(ctx, explicit); n > 0 && len(nodes) > n {
+		return nodes[:n]
+	}
+	return nodes
+}
+
+// nested returns true if the field being resolved is nested within a list.
+func nested(ctx context.Context) bool {
+	for fc := graphql.GetFieldContext(ctx); fc != nil; fc = fc.Parent {
+		if fc.Index != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// page returns the bounds of the page of a connection's n nodes described by
+// the supplied limit and offset. The limit is subject to the same defaults as
+// maxNodes.
+func page(ctx context.Context, limit, offset *int, n int) (lo, hi int) {
+	if offset != nil && *offset > 0 {
+		lo = *offset
+	}
+	if lo > n {
+		lo = n
+	}
+	hi = n
+	if size := maxNodes(ctx, limit); size > 0 && lo+size < n {
+		hi = lo + size
+	}
+	return lo, hi
+}
@@ -0,0 +1,261 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolvers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMaxNodes(t *testing.T) {
+	zero, three, negative := 0, 3, -1
+	index := 0
+
+	// A context that is annotated with a nested limit of 10 nodes.
+	limited := context.WithValue(context.Background(), limitKey{}, 10)
+
+	// A context in which the field being resolved (e.g. events) belongs to an
+	// item of a list (e.g. providers).
+	nested := graphql.WithFieldContext(
+		graphql.WithFieldContext(limited, &graphql.FieldContext{Index: &index}),
+		&graphql.FieldContext{},
+	)
+
+	type args struct {
+		ctx      context.Context
+		explicit *int
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   int
+	}{
+		"NotNested": {
+			reason: "Connections that are not nested within a list should not be limited by default.",
+			args: args{
+				ctx: graphql.WithFieldContext(limited, &graphql.FieldContext{}),
+			},
+			want: 0,
+		},
+		"Nested": {
+			reason: "Connections that are nested within a list should be limited by default.",
+			args: args{
+				ctx: nested,
+			},
+			want: 10,
+		},
+		"NestedWithoutLimit": {
+			reason: "Connections that are nested within a list should not be limited if no default limit is configured.",
+			args: args{
+				ctx: graphql.WithFieldContext(context.Background(), &graphql.FieldContext{Index: &index}),
+			},
+			want: 0,
+		},
+		"NestedExplicitLimit": {
+			reason: "An explicit limit should override the default limit.",
+			args: args{
+				ctx:      nested,
+				explicit: &three,
+			},
+			want: 3,
+		},
+		"NestedExplicitZero": {
+			reason: "An explicit limit of zero should disable the default limit.",
+			args: args{
+				ctx:      nested,
+				explicit: &zero,
+			},
+			want: 0,
+		},
+		"NegativeLimit": {
+			reason: "A negative limit should be treated as no limit.",
+			args: args{
+				ctx:      context.Background(),
+				explicit: &negative,
+			},
+			want: 0,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := maxNodes(tc.args.ctx, tc.args.explicit)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nmaxNodes(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	two := 2
+	index := 0
+
+	// A context in which the field being resolved belongs to an item of a
+	// list, and is thus limited to one node by default.
+	nested := graphql.WithFieldContext(
+		graphql.WithFieldContext(
+			context.WithValue(context.Background(), limitKey{}, 1),
+			&graphql.FieldContext{Index: &index},
+		),
+		&graphql.FieldContext{},
+	)
+
+	type args struct {
+		ctx      context.Context
+		explicit *int
+		nodes    []string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []string
+	}{
+		"Unlimited": {
+			reason: "All nodes should be returned if the connection is not limited.",
+			args: args{
+				ctx:   context.Background(),
+				nodes: []string{"a", "b", "c"},
+			},
+			want: []string{"a", "b", "c"},
+		},
+		"DefaultLimit": {
+			reason: "Nested connections should be truncated to the default limit.",
+			args: args{
+				ctx:   nested,
+				nodes: []string{"a", "b", "c"},
+			},
+			want: []string{"a"},
+		},
+		"ExplicitLimit": {
+			reason: "Connections should be truncated to an explicit limit.",
+			args: args{
+				ctx:      nested,
+				explicit: &two,
+				nodes:    []string{"a", "b", "c"},
+			},
+			want: []string{"a", "b"},
+		},
+		"FewerNodesThanLimit": {
+			reason: "Connections with fewer nodes than the limit should not be truncated.",
+			args: args{
+				ctx:      context.Background(),
+				explicit: &two,
+				nodes:    []string{"a"},
+			},
+			want: []string{"a"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := truncate(tc.args.ctx, tc.args.explicit, tc.args.nodes)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ntruncate(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPage(t *testing.T) {
+	two, three, ten, negative := 2, 3, 10, -1
+
+	type args struct {
+		ctx    context.Context
+		limit  *int
+		offset *int
+		n      int
+	}
+	type want struct {
+		lo int
+		hi int
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"All": {
+			reason: "If neither a limit nor an offset are supplied all nodes should be returned.",
+			args: args{
+				ctx: context.Background(),
+				n:   5,
+			},
+			want: want{lo: 0, hi: 5},
+		},
+		"Limit": {
+			reason: "A limit should bound the number of nodes returned.",
+			args: args{
+				ctx:   context.Background(),
+				limit: &two,
+				n:     5,
+			},
+			want: want{lo: 0, hi: 2},
+		},
+		"LimitAndOffset": {
+			reason: "An offset should skip nodes before the limit is applied.",
+			args: args{
+				ctx:    context.Background(),
+				limit:  &two,
+				offset: &three,
+				n:      10,
+			},
+			want: want{lo: 3, hi: 5},
+		},
+		"LimitExceedsNodes": {
+			reason: "A limit that exceeds the remaining nodes should return all remaining nodes.",
+			args: args{
+				ctx:    context.Background(),
+				limit:  &ten,
+				offset: &three,
+				n:      5,
+			},
+			want: want{lo: 3, hi: 5},
+		},
+		"OffsetExceedsNodes": {
+			reason: "An offset that exceeds the number of nodes should return an empty page.",
+			args: args{
+				ctx:    context.Background(),
+				offset: &ten,
+				n:      5,
+			},
+			want: want{lo: 5, hi: 5},
+		},
+		"NegativeOffset": {
+			reason: "A negative offset should be treated as no offset.",
+			args: args{
+				ctx:    context.Background(),
+				offset: &negative,
+				n:      5,
+			},
+			want: want{lo: 0, hi: 5},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			lo, hi := page(tc.args.ctx, tc.args.limit, tc.args.offset, tc.args.n)
+			if diff := cmp.Diff(tc.want, want{lo: lo, hi: hi}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\npage(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
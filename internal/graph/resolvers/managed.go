@@ -16,34 +16,41 @@ package resolvers
 
 import (
 	"context"
+	"encoding/json"
+	"sort"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	kextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
 
 	"github.com/upbound/xgql/internal/auth"
 	"github.com/upbound/xgql/internal/graph/model"
 )
 
 const (
-	errListCRDs = "cannot list custom resource definitions"
+	errFieldPath     = "cannot get value at field path"
+	errMarshalFields = "cannot marshal fields to JSON"
 )
 
+// Crossplane providers add all managed resource CRDs to this category.
+const categoryManaged = "managed"
+
 type managedResource struct {
 	clients ClientCache
 }
 
-func (r *managedResource) Events(ctx context.Context, obj *model.ManagedResource) (*model.EventConnection, error) {
+func (r *managedResource) Events(ctx context.Context, obj *model.ManagedResource, limit *int) (*model.EventConnection, error) {
 	e := &events{clients: r.clients}
-	return e.Resolve(ctx, &corev1.ObjectReference{
-		APIVersion: obj.APIVersion,
-		Kind:       obj.Kind,
-		Name:       obj.Metadata.Name,
-		UID:        types.UID(obj.Metadata.UID),
-	})
+	return e.Resolve(ctx, involved(obj.ID, obj.Metadata), limit)
 }
 
 func (r *managedResource) Definition(ctx context.Context, obj *model.ManagedResource) (model.ManagedResourceDefinition, error) {
@@ -57,38 +64,127 @@ func (r *managedResource) Definition(ctx context.Context, obj *model.ManagedReso
 		return nil, nil
 	}
 
+	crd, err := getCRD(ctx, c, obj.APIVersion, obj.Kind)
+	if err != nil {
+		graphql.AddError(ctx, err)
+		return nil, nil
+	}
+	if crd == nil {
+		return nil, nil
+	}
+
+	out := model.GetCustomResourceDefinition(crd)
+	return &out, nil
+}
+
+func (r *managedResource) Provider(ctx context.Context, obj *model.ManagedResource) (*model.Provider, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	pr, err := getProviderRevision(ctx, c, obj.APIVersion, obj.Kind)
+	if err != nil {
+		graphql.AddError(ctx, err)
+		return nil, nil
+	}
+	if pr == nil {
+		return nil, nil
+	}
+
+	// A provider revision's controller is the provider it's a revision of.
+	ref := metav1.GetControllerOf(pr)
+	if ref == nil || ref.Kind != pkgv1.ProviderKind {
+		return nil, nil
+	}
+
+	p := &pkgv1.Provider{}
+	if err := c.Get(ctx, types.NamespacedName{Name: ref.Name}, p); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetPackage))
+		return nil, nil
+	}
+
+	out := model.GetProvider(p)
+	return &out, nil
+}
+
+func (r *managedResource) ProviderRevision(ctx context.Context, obj *model.ManagedResource) (*model.ProviderRevision, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	pr, err := getProviderRevision(ctx, c, obj.APIVersion, obj.Kind)
+	if err != nil {
+		graphql.AddError(ctx, err)
+		return nil, nil
+	}
+	if pr == nil {
+		return nil, nil
+	}
+
+	out := model.GetProviderRevision(pr)
+	return &out, nil
+}
+
+// getCRD returns the CRD that defines the supplied kind of resource, or nil if
+// no CRD defines it.
+func getCRD(ctx context.Context, c client.Client, apiVersion, kind string) (*kextv1.CustomResourceDefinition, error) {
 	in := &kextv1.CustomResourceDefinitionList{}
 	if err := c.List(ctx, in); err != nil {
-		graphql.AddError(ctx, errors.Wrap(err, errListCRDs))
-		return nil, nil
+		return nil, errors.Wrap(err, errListCRDs)
 	}
 
-	gv, err := schema.ParseGroupVersion(obj.APIVersion)
+	gv, err := schema.ParseGroupVersion(apiVersion)
 	if err != nil {
 		// This should be pretty much impossible - the API server should not
 		// return resources with malformed API versions.
-		graphql.AddError(ctx, errors.Wrap(err, errMalformedAPIVersion))
-		return nil, nil
+		return nil, errors.Wrap(err, errMalformedAPIVersion)
 	}
 
 	for i := range in.Items {
-		crd := in.Items[i] // So we don't take the address of a range variable.
+		crd := &in.Items[i]
 
 		if crd.Spec.Group != gv.Group {
 			continue
 		}
 
-		if crd.Spec.Names.Kind != obj.Kind {
+		if crd.Spec.Names.Kind != kind {
 			continue
 		}
 
-		out := model.GetCustomResourceDefinition(&crd)
-		return &out, nil
+		return crd, nil
 	}
 
 	return nil, nil
 }
 
+// getProviderRevision returns the provider revision that installed the CRD
+// that defines the supplied kind of resource, or nil if no provider revision
+// did.
+func getProviderRevision(ctx context.Context, c client.Client, apiVersion, kind string) (*pkgv1.ProviderRevision, error) {
+	crd, err := getCRD(ctx, c, apiVersion, kind)
+	if err != nil || crd == nil {
+		return nil, err
+	}
+
+	pr, err := getOwningRevision(ctx, c, crd.GetOwnerReferences(), pkgv1.ProviderRevisionGroupVersionKind, func() pkgv1.PackageRevision { return &pkgv1.ProviderRevision{} })
+	if err != nil || pr == nil {
+		return nil, err
+	}
+	return pr.(*pkgv1.ProviderRevision), nil
+}
+
 type managedResourceSpec struct {
 	clients ClientCache
 }
@@ -121,3 +217,219 @@ func (r *managedResourceSpec) ConnectionSecret(ctx context.Context, obj *model.M
 	out := model.GetSecret(s)
 	return &out, nil
 }
+
+func (r *managedResourceSpec) ForProvider(ctx context.Context, obj *model.ManagedResourceSpec, fieldPath *string) ([]byte, error) {
+	return projectJSON(ctx, obj.Parameters, fieldPath)
+}
+
+type managedResourceStatus struct{}
+
+func (r *managedResourceStatus) AtProvider(ctx context.Context, obj *model.ManagedResourceStatus, fieldPath *string) ([]byte, error) {
+	return projectJSON(ctx, obj.Observation, fieldPath)
+}
+
+// projectJSON returns the supplied fields as JSON. If a field path is supplied
+// only the value at that path is returned.
+func projectJSON(ctx context.Context, in map[string]interface{}, fieldPath *string) ([]byte, error) {
+	if in == nil {
+		return nil, nil
+	}
+
+	var v interface{} = in
+	if fieldPath != nil && *fieldPath != "" {
+		fv, err := fieldpath.Pave(in).GetValue(*fieldPath)
+		if fieldpath.IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			graphql.AddError(ctx, errors.Wrap(err, errFieldPath))
+			return nil, nil
+		}
+		v = fv
+	}
+
+	out, err := json.Marshal(v)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errMarshalFields))
+		return nil, nil
+	}
+	return out, nil
+}
+
+type managedResources struct {
+	clients ClientCache
+}
+
+// A managedResourceSelector selects managed resources. Unset fields match all
+// managed resources.
+type managedResourceSelector struct {
+	// Only managed resources defined by CRDs with one of these owner UIDs.
+	owners map[types.UID]bool
+
+	// Only managed resources whose Ready and Synced conditions have these
+	// statuses.
+	ready  *model.ConditionStatus
+	synced *model.ConditionStatus
+
+	// Only managed resources that use the provider config of this name.
+	providerConfig *string
+
+	// Only managed resources with all of these labels.
+	labels map[string]string
+
+	// Only managed resources that aren't owned by a composite resource.
+	orphaned bool
+}
+
+// Resolve a page of the managed resources matched by the supplied selector,
+// ordered by ID.
+func (r *managedResources) Resolve(ctx context.Context, s managedResourceSelector, limit, offset *int) (*model.ManagedResourceConnection, error) { //nolint:gocyclo // Only slightly over.
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	in := &kextv1.CustomResourceDefinitionList{}
+	if err := c.List(ctx, in); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errListCRDs))
+		return nil, nil
+	}
+
+	// Claims are bound to composite resources, never directly to managed
+	// resources, so a managed resource without a composite owner is not
+	// part of any claim either.
+	var composites map[schema.GroupKind]bool
+	if s.orphaned {
+		composites = compositeKinds(in.Items)
+	}
+
+	out := &model.ManagedResourceConnection{
+		Nodes: make([]model.ManagedResource, 0),
+	}
+
+	for i := range in.Items {
+		crd := &in.Items[i]
+
+		if s.owners != nil && !ownedBy(crd.GetOwnerReferences(), s.owners) {
+			continue
+		}
+
+		// Providers also install CRDs that aren't managed resources, like
+		// ProviderConfigs.
+		if !hasCategory(crd.Spec.Names.Categories, categoryManaged) {
+			continue
+		}
+
+		gv := schema.GroupVersion{
+			Group:   crd.Spec.Group,
+			Version: pickCRDVersion(model.GetCustomResourceDefinitionVersions(crd.Spec.Versions)),
+		}
+		ul := &kunstructured.UnstructuredList{}
+		ul.SetAPIVersion(gv.String())
+		ul.SetKind(crd.Spec.Names.Kind + "List")
+		if lk := crd.Spec.Names.ListKind; lk != "" {
+			ul.SetKind(lk)
+		}
+
+		if err := c.List(ctx, ul, client.MatchingLabels(s.labels)); err != nil {
+			graphql.AddError(ctx, errors.Wrap(err, errListResources))
+			continue
+		}
+
+		for j := range ul.Items {
+			if s.orphaned && ownedByKind(ul.Items[j].GetOwnerReferences(), composites) {
+				continue
+			}
+			mr := model.GetManagedResource(&ul.Items[j])
+			if !s.matches(mr) {
+				continue
+			}
+			out.Nodes = append(out.Nodes, mr)
+			out.TotalCount++
+		}
+	}
+
+	sort.Stable(out)
+	lo, hi := page(ctx, limit, offset, len(out.Nodes))
+	out.Nodes = out.Nodes[lo:hi]
+	return out, nil
+}
+
+func (s managedResourceSelector) matches(mr model.ManagedResource) bool {
+	if s.providerConfig != nil {
+		if mr.Spec == nil || mr.Spec.ProviderConfigRef == nil || mr.Spec.ProviderConfigRef.Name != *s.providerConfig {
+			return false
+		}
+	}
+	var cs []model.Condition
+	if mr.Status != nil {
+		cs = mr.Status.Conditions
+	}
+	if s.ready != nil && conditionStatus(cs, "Ready") != *s.ready {
+		return false
+	}
+	if s.synced != nil && conditionStatus(cs, "Synced") != *s.synced {
+		return false
+	}
+	return true
+}
+
+// conditionStatus returns the status of the supplied condition type. Absent
+// conditions have an unknown status.
+func conditionStatus(cs []model.Condition, t string) model.ConditionStatus {
+	for _, c := range cs {
+		if c.Type == t {
+			return c.Status
+		}
+	}
+	return model.ConditionStatusUnknown
+}
+
+func ownedBy(in []metav1.OwnerReference, owners map[types.UID]bool) bool {
+	for _, ref := range in {
+		if owners[ref.UID] {
+			return true
+		}
+	}
+	return false
+}
+
+// compositeKinds returns the kinds of composite resource defined by the
+// supplied CRDs.
+func compositeKinds(in []kextv1.CustomResourceDefinition) map[schema.GroupKind]bool {
+	out := make(map[schema.GroupKind]bool)
+	for i := range in {
+		crd := &in[i]
+		if hasCategory(crd.Spec.Names.Categories, categoryComposite) {
+			out[schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}] = true
+		}
+	}
+	return out
+}
+
+func ownedByKind(in []metav1.OwnerReference, kinds map[schema.GroupKind]bool) bool {
+	for _, ref := range in {
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			continue
+		}
+		if kinds[gv.WithKind(ref.Kind).GroupKind()] {
+			return true
+		}
+	}
+	return false
+}
+
+func hasCategory(in []string, category string) bool {
+	for _, c := range in {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
@@ -25,10 +25,15 @@ import (
 	"github.com/vektah/gqlparser/v2/gqlerror"
 	corev1 "k8s.io/api/core/v1"
 	kextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
 
 	"github.com/upbound/xgql/internal/auth"
 	"github.com/upbound/xgql/internal/clients"
@@ -36,7 +41,10 @@ import (
 	"github.com/upbound/xgql/internal/graph/model"
 )
 
-var _ generated.ManagedResourceSpecResolver = &managedResourceSpec{}
+var (
+	_ generated.ManagedResourceSpecResolver   = &managedResourceSpec{}
+	_ generated.ManagedResourceStatusResolver = &managedResourceStatus{}
+)
 
 func TestManagedResourceDefinition(t *testing.T) {
 	errBoom := errors.New("boom")
@@ -181,6 +189,291 @@ func TestManagedResourceDefinition(t *testing.T) {
 	}
 }
 
+func TestManagedResourceProviderRevision(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	owner := func(name string) metav1.OwnerReference {
+		return metav1.OwnerReference{
+			APIVersion: pkgv1.ProviderRevisionGroupVersionKind.GroupVersion().String(),
+			Kind:       pkgv1.ProviderRevisionKind,
+			Name:       name,
+		}
+	}
+
+	crd := kextv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			OwnerReferences: []metav1.OwnerReference{owner("gone"), owner("inactive"), owner("active")},
+		},
+		Spec: kextv1.CustomResourceDefinitionSpec{
+			Group: "example.org",
+			Names: kextv1.CustomResourceDefinitionNames{Kind: "Example"},
+		},
+	}
+	orphan := kextv1.CustomResourceDefinition{
+		Spec: kextv1.CustomResourceDefinitionSpec{
+			Group: "example.org",
+			Names: kextv1.CustomResourceDefinitionNames{Kind: "Orphan"},
+		},
+	}
+
+	active := pkgv1.ProviderRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "active"},
+		Spec:       pkgv1.PackageRevisionSpec{DesiredState: pkgv1.PackageRevisionActive},
+	}
+	gactive := model.GetProviderRevision(&active)
+
+	list := test.NewMockListFn(nil, func(obj client.ObjectList) error {
+		*obj.(*kextv1.CustomResourceDefinitionList) = kextv1.CustomResourceDefinitionList{
+			Items: []kextv1.CustomResourceDefinition{orphan, crd},
+		}
+		return nil
+	})
+
+	type args struct {
+		ctx context.Context
+		obj *model.ManagedResource
+	}
+	type want struct {
+		pr   *model.ProviderRevision
+		err  error
+		errs gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"GetClientError": {
+			reason: "If we can't get a client we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{}, errBoom
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.ManagedResource{},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetClient).Error()),
+				},
+			},
+		},
+		"ListCRDsError": {
+			reason: "If we can't list CRDs we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.ManagedResource{},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errListCRDs).Error()),
+				},
+			},
+		},
+		"GetRevisionError": {
+			reason: "If we can't get a revision that owns the CRD we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: list,
+					MockGet:  test.NewMockGetFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.ManagedResource{APIVersion: "example.org/v1", Kind: "Example"},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetRevision).Error()),
+				},
+			},
+		},
+		"NoOwningRevision": {
+			reason: "If no provider revision owns the CRD we should return nil.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{MockList: list}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.ManagedResource{APIVersion: "example.org/v1", Kind: "Orphan"},
+			},
+			want: want{
+				pr: nil,
+			},
+		},
+		"ActiveRevision": {
+			reason: "We should prefer the active revision when several revisions own the CRD, skipping any that no longer exist.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: list,
+					MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+						switch key.Name {
+						case "gone":
+							return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+						case "active":
+							*obj.(*pkgv1.ProviderRevision) = active
+						default:
+							obj.(*pkgv1.ProviderRevision).SetName(key.Name)
+						}
+						return nil
+					},
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.ManagedResource{APIVersion: "example.org/v1", Kind: "Example"},
+			},
+			want: want{
+				pr: &gactive,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mr := &managedResource{clients: tc.clients}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := mr.ProviderRevision(tc.args.ctx, tc.args.obj)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.ProviderRevision(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.ProviderRevision(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.pr, got, cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\ns.ProviderRevision(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestManagedResourceProvider(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	crd := kextv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: pkgv1.ProviderRevisionGroupVersionKind.GroupVersion().String(),
+				Kind:       pkgv1.ProviderRevisionKind,
+				Name:       "provider-example-1234",
+			}},
+		},
+		Spec: kextv1.CustomResourceDefinitionSpec{
+			Group: "example.org",
+			Names: kextv1.CustomResourceDefinitionNames{Kind: "Example"},
+		},
+	}
+
+	rev := pkgv1.ProviderRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "provider-example-1234",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: pkgv1.ProviderGroupVersionKind.GroupVersion().String(),
+				Kind:       pkgv1.ProviderKind,
+				Name:       "provider-example",
+				Controller: pointer.BoolPtr(true),
+			}},
+		},
+		Spec: pkgv1.PackageRevisionSpec{DesiredState: pkgv1.PackageRevisionActive},
+	}
+
+	p := pkgv1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "provider-example"}}
+	gp := model.GetProvider(&p)
+
+	list := test.NewMockListFn(nil, func(obj client.ObjectList) error {
+		*obj.(*kextv1.CustomResourceDefinitionList) = kextv1.CustomResourceDefinitionList{
+			Items: []kextv1.CustomResourceDefinition{crd},
+		}
+		return nil
+	})
+
+	type want struct {
+		p    *model.Provider
+		err  error
+		errs gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		want    want
+	}{
+		"GetProviderError": {
+			reason: "If we can't get the provider we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: list,
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						if pr, ok := obj.(*pkgv1.ProviderRevision); ok {
+							*pr = rev
+							return nil
+						}
+						return errBoom
+					}),
+				}, nil
+			}),
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetPackage).Error()),
+				},
+			},
+		},
+		"Success": {
+			reason: "We should return the provider that controls the revision that owns the CRD.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: list,
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						switch o := obj.(type) {
+						case *pkgv1.ProviderRevision:
+							*o = rev
+						case *pkgv1.Provider:
+							*o = p
+						}
+						return nil
+					}),
+				}, nil
+			}),
+			want: want{
+				p: &gp,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover)
+			mr := &managedResource{clients: tc.clients}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := mr.Provider(ctx, &model.ManagedResource{APIVersion: "example.org/v1", Kind: "Example"})
+			errs := graphql.GetErrors(ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.Provider(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.Provider(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.p, got, cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\ns.Provider(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestManagedResourceSpecConnectionSecret(t *testing.T) {
 	errBoom := errors.New("boom")
 
@@ -286,3 +579,93 @@ func TestManagedResourceSpecConnectionSecret(t *testing.T) {
 		})
 	}
 }
+
+func TestManagedResourceSpecForProvider(t *testing.T) {
+	params := map[string]interface{}{
+		"region": "us-west-2",
+		"tags":   []interface{}{map[string]interface{}{"key": "cool"}},
+	}
+
+	type args struct {
+		obj       *model.ManagedResourceSpec
+		fieldPath *string
+	}
+	type want struct {
+		json []byte
+		err  error
+		errs gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoParameters": {
+			reason: "We should return nil if the managed resource has no spec.forProvider.",
+			args: args{
+				obj: &model.ManagedResourceSpec{},
+			},
+			want: want{},
+		},
+		"AllParameters": {
+			reason: "We should return all of spec.forProvider if no field path is supplied.",
+			args: args{
+				obj: &model.ManagedResourceSpec{Parameters: params},
+			},
+			want: want{
+				json: []byte(`{"region":"us-west-2","tags":[{"key":"cool"}]}`),
+			},
+		},
+		"FieldPath": {
+			reason: "We should return only the value at the supplied field path.",
+			args: args{
+				obj:       &model.ManagedResourceSpec{Parameters: params},
+				fieldPath: pointer.StringPtr("tags[0].key"),
+			},
+			want: want{
+				json: []byte(`"cool"`),
+			},
+		},
+		"FieldPathNotFound": {
+			reason: "We should return nil if there is no value at the supplied field path.",
+			args: args{
+				obj:       &model.ManagedResourceSpec{Parameters: params},
+				fieldPath: pointer.StringPtr("zone"),
+			},
+			want: want{},
+		},
+		"InvalidFieldPath": {
+			reason: "We should add an error to the GraphQL context if the field path is invalid.",
+			args: args{
+				obj:       &model.ManagedResourceSpec{Parameters: params},
+				fieldPath: pointer.StringPtr("region.nope"),
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errors.New("region: not an object"), errFieldPath).Error()),
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover)
+
+			r := &managedResourceSpec{}
+			got, err := r.ForProvider(ctx, tc.args.obj, tc.args.fieldPath)
+			errs := graphql.GetErrors(ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.ForProvider(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.ForProvider(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(string(tc.want.json), string(got)); diff != "" {
+				t.Errorf("\n%s\nr.ForProvider(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
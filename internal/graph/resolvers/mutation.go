@@ -15,31 +15,80 @@
 package resolvers
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"io"
+	"strings"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	extv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 
 	"github.com/upbound/xgql/internal/auth"
 	"github.com/upbound/xgql/internal/graph/model"
+	xunstructured "github.com/upbound/xgql/internal/unstructured"
 )
 
 const (
 	errCreateResource        = "cannot create Kubernetes resource"
 	errUpdateResource        = "cannot update Kubernetes resource"
 	errDeleteResource        = "cannot delete Kubernetes resource"
+	errValidateResource      = "cannot validate Kubernetes resource"
+	errPauseResource         = "cannot set paused annotation of Kubernetes resource"
+	errReconcileResource     = "cannot request reconcile of Kubernetes resource"
+	errInstallPackage        = "cannot install package"
+	errUpgradePackage        = "cannot upgrade package"
+	errSetDesiredState       = "cannot set desired state of package revision"
+	errMarshalPatch          = "cannot marshal patch JSON"
 	errUnmarshalUnstructured = "cannot unmarshal input unstructured JSON"
+	errReadManifests         = "cannot read manifests"
+	errCreateClaim           = "cannot create composite resource claim"
+	errUnmarshalSpec         = "cannot unmarshal claim spec JSON"
+	errDeleteXR              = "cannot delete composite resource"
+	errDeleteXRC             = "cannot delete composite resource claim"
+	errUpdateComposition     = "cannot update composition"
+	errConvertComposition    = "cannot convert composition"
+	errUnmarshalCompSpec     = "cannot unmarshal composition spec JSON"
 
-	errFmtUnmarshalPatch = "cannot unmarshal unstructured patch JSON at index %d"
-	errFmtPatch          = "cannot apply patch at index %d"
+	errFmtUnmarshalPatch  = "cannot unmarshal unstructured patch JSON at index %d"
+	errFmtPatch           = "cannot apply patch at index %d"
+	errFmtParseManifest   = "cannot parse manifest at index %d"
+	errFmtApplyManifest   = "cannot apply manifest at index %d"
+	errFmtNotXRD          = "kind %q is not a composite resource definition"
+	errFmtNoClaims        = "composite resource definition %q does not offer a claim"
+	errFmtNoReferenceable = "composite resource definition %q has no referenceable version"
+	errFmtNotComposite    = "kind %q is not a composite resource"
+	errFmtNotClaim        = "kind %q is not a composite resource claim"
+	errFmtNotComposition  = "kind %q is not a composition"
+)
+
+const (
+	// Crossplane doesn't reconcile resources with this annotation set to "true".
+	annotationKeyPaused = "crossplane.io/paused"
+
+	// Crossplane doesn't care about this annotation, but updating it causes
+	// the resource's controller to receive a watch event and reconcile it.
+	annotationKeyReconcileRequestedAt = "xgql.upbound.io/reconcile-requested-at"
+
+	// The field manager xgql uses when it applies resources.
+	fieldOwner = "xgql"
 )
 
 // IsRetriable indicates that an error may succeed if retried.
@@ -64,7 +113,8 @@ func IsRetriable(err error) bool { //nolint:gocyclo // It's just a big old switc
 }
 
 type mutation struct {
-	clients ClientCache
+	clients    ClientCache
+	categories model.CategoryIndex
 }
 
 func (r *mutation) CreateKubernetesResource(ctx context.Context, input model.CreateKubernetesResourceInput) (*model.CreateKubernetesResourcePayload, error) {
@@ -102,7 +152,7 @@ func (r *mutation) CreateKubernetesResource(ctx context.Context, input model.Cre
 		return nil, nil
 	}
 
-	kr, err := model.GetKubernetesResource(u)
+	kr, err := model.GetKubernetesResource(u, r.categories)
 	if err != nil {
 		graphql.AddError(ctx, errors.Wrap(err, errModelResource))
 		return nil, nil
@@ -153,7 +203,7 @@ func (r *mutation) UpdateKubernetesResource(ctx context.Context, id model.Refere
 		return nil, nil
 	}
 
-	kr, err := model.GetKubernetesResource(u)
+	kr, err := model.GetKubernetesResource(u, r.categories)
 	if err != nil {
 		graphql.AddError(ctx, errors.Wrap(err, errModelResource))
 		return nil, nil
@@ -182,10 +232,700 @@ func (r *mutation) DeleteKubernetesResource(ctx context.Context, id model.Refere
 		return nil, nil //nolint:nilerr // IgnoreNotFound appears to trigger this linter.
 	}
 
-	kr, err := model.GetKubernetesResource(u)
+	kr, err := model.GetKubernetesResource(u, r.categories)
 	if err != nil {
 		graphql.AddError(ctx, errors.Wrap(err, errModelResource))
 		return nil, nil
 	}
 	return &model.DeleteKubernetesResourcePayload{Resource: kr}, nil
 }
+
+func (r *mutation) DeleteCompositeResource(ctx context.Context, id model.ReferenceID, propagationPolicy *model.DeletionPropagation, dryRun *bool) (*model.DeleteCompositePayload, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	u, err := getUnstructured(ctx, c, id)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetXR))
+		return nil, nil
+	}
+	if !xunstructured.ProbablyComposite(u) {
+		graphql.AddError(ctx, errors.Errorf(errFmtNotComposite, id.Kind))
+		return nil, nil
+	}
+	xr := model.GetCompositeResource(u)
+
+	// Orphaned composed resources outlive their composite resource.
+	affected := []model.KubernetesResource{}
+	if propagationPolicy == nil || *propagationPolicy != model.DeletionPropagationOrphan {
+		affected = getComposed(ctx, c, r.categories, xr.Spec.ResourceReferences)
+	}
+
+	if err := deleteComposite(ctx, c, u, propagationPolicy, dryRun); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errDeleteXR))
+		return nil, nil
+	}
+	return cascaded(xr, affected, dryRun), nil
+}
+
+func (r *mutation) DeleteClaim(ctx context.Context, id model.ReferenceID, propagationPolicy *model.DeletionPropagation, dryRun *bool) (*model.DeleteCompositePayload, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	u, err := getUnstructured(ctx, c, id)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetXRC))
+		return nil, nil
+	}
+	if !xunstructured.ProbablyClaim(u) {
+		graphql.AddError(ctx, errors.Errorf(errFmtNotClaim, id.Kind))
+		return nil, nil
+	}
+	xrc := model.GetCompositeResourceClaim(u)
+
+	// Crossplane deletes a claim's composite resource regardless of the
+	// propagation policy, because the claim doesn't own it.
+	affected := []model.KubernetesResource{}
+	if ref := xrc.Spec.ResourceReference; ref != nil {
+		affected = getComposed(ctx, c, r.categories, []corev1.ObjectReference{*ref})
+	}
+
+	if err := deleteComposite(ctx, c, u, propagationPolicy, dryRun); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errDeleteXRC))
+		return nil, nil
+	}
+	return cascaded(xrc, affected, dryRun), nil
+}
+
+// getUnstructured gets the identified resource.
+func getUnstructured(ctx context.Context, c client.Client, id model.ReferenceID) (*unstructured.Unstructured, error) {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(id.APIVersion)
+	u.SetKind(id.Kind)
+	return u, c.Get(ctx, types.NamespacedName{Namespace: id.Namespace, Name: id.Name}, u)
+}
+
+// getComposed returns the referenced composed resources, followed by the
+// resources they compose, if any. Resources that no longer exist are omitted.
+func getComposed(ctx context.Context, c client.Client, ci model.CategoryIndex, refs []corev1.ObjectReference) []model.KubernetesResource {
+	trees := make([][]model.KubernetesResource, len(refs))
+	forEach(ctx, len(refs), func(i int) {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(refs[i].APIVersion)
+		u.SetKind(refs[i].Kind)
+		nn := types.NamespacedName{Namespace: refs[i].Namespace, Name: refs[i].Name}
+		if err := c.Get(ctx, nn, u); err != nil {
+			if resource.IgnoreNotFound(err) != nil {
+				graphql.AddError(ctx, errors.Wrap(err, errGetComposed))
+			}
+			return
+		}
+
+		kr, err := model.GetKubernetesResource(u, ci)
+		if err != nil {
+			graphql.AddError(ctx, errors.Wrap(err, errModelComposed))
+			return
+		}
+		trees[i] = []model.KubernetesResource{kr}
+
+		if xr, ok := kr.(model.CompositeResource); ok {
+			trees[i] = append(trees[i], getComposed(ctx, c, ci, xr.Spec.ResourceReferences)...)
+		}
+	})
+
+	out := make([]model.KubernetesResource, 0, len(refs))
+	for _, t := range trees {
+		out = append(out, t...)
+	}
+	return out
+}
+
+// deleteComposite deletes the supplied composite resource or claim, unless
+// this is a dry run. The API server still authorizes a dry run delete.
+func deleteComposite(ctx context.Context, c client.Client, u *unstructured.Unstructured, pp *model.DeletionPropagation, dryRun *bool) error {
+	o := []client.DeleteOption{}
+	if pp != nil {
+		o = append(o, client.PropagationPolicy(propagation(*pp)))
+	}
+	if pointer.BoolPtrDerefOr(dryRun, false) {
+		o = append(o, client.DryRunAll)
+	}
+	return retry.OnError(retry.DefaultBackoff, IsRetriable, func() error { return c.Delete(ctx, u, o...) })
+}
+
+// propagation returns the Kubernetes equivalent of the supplied propagation
+// policy.
+func propagation(pp model.DeletionPropagation) v1.DeletionPropagation {
+	switch pp {
+	case model.DeletionPropagationForeground:
+		return v1.DeletePropagationForeground
+	case model.DeletionPropagationOrphan:
+		return v1.DeletePropagationOrphan
+	case model.DeletionPropagationBackground:
+		return v1.DeletePropagationBackground
+	default:
+		return v1.DeletePropagationBackground
+	}
+}
+
+// cascaded returns the payload of a composite resource or claim deletion that
+// cascades to the supplied resources.
+func cascaded(kr model.KubernetesResource, affected []model.KubernetesResource, dryRun *bool) *model.DeleteCompositePayload {
+	out := &model.DeleteCompositePayload{
+		Resource:          kr,
+		Deleted:           !pointer.BoolPtrDerefOr(dryRun, false),
+		AffectedResources: affected,
+	}
+	for _, a := range affected {
+		if mr, ok := a.(model.ManagedResource); ok && mr.Spec != nil && mr.Spec.DeletesExternalResource {
+			out.ExternalResourceCount++
+		}
+	}
+	return out
+}
+
+// rejected returns the reasons the API server rejected a dry-run, or nil if the
+// supplied error does not indicate that the API server rejected the resource.
+// An error that isn't the resource's fault, for example because the caller is
+// not authorized to create it, is not considered a rejection.
+func rejected(err error) []model.ValidationError {
+	se, ok := err.(kerrors.APIStatus)
+	if !ok {
+		return nil
+	}
+	s := se.Status()
+	errs := model.GetValidationErrors(s)
+	if errs[0].Source == model.ValidationErrorSourceAdmissionWebhook {
+		return errs
+	}
+	switch s.Reason {
+	case v1.StatusReasonInvalid, v1.StatusReasonBadRequest, v1.StatusReasonAlreadyExists, v1.StatusReasonNotFound, v1.StatusReasonConflict:
+		return errs
+	default:
+		return nil
+	}
+}
+
+func (r *mutation) ValidateResource(ctx context.Context, input model.ValidateResourceInput) (*model.ValidateResourcePayload, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	u := &unstructured.Unstructured{}
+	if err := json.Unmarshal(input.Unstructured, u); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errUnmarshalUnstructured))
+		return nil, nil
+	}
+
+	pv := fieldpath.Pave(u.Object)
+	for i, p := range input.Patches {
+		var v interface{}
+		if err := json.Unmarshal(p.Unstructured, &v); err != nil {
+			graphql.AddError(ctx, errors.Wrapf(err, errFmtUnmarshalPatch, i))
+			return nil, nil
+		}
+		if err := pv.SetValue(p.FieldPath, v); err != nil {
+			graphql.AddError(ctx, errors.Wrapf(err, errFmtPatch, i))
+			return nil, nil
+		}
+	}
+
+	dryRun := func() error { return c.Create(ctx, u, client.DryRunAll) }
+	if input.Operation != nil && *input.Operation == model.ValidationOperationUpdate {
+		dryRun = func() error { return c.Update(ctx, u, client.DryRunAll) }
+	}
+
+	err = retry.OnError(retry.DefaultBackoff, IsRetriable, dryRun)
+	if errs := rejected(err); errs != nil {
+		return &model.ValidateResourcePayload{Valid: false, Errors: errs}, nil
+	}
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errValidateResource))
+		return nil, nil
+	}
+
+	kr, err := model.GetKubernetesResource(u, r.categories)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errModelResource))
+		return nil, nil
+	}
+	return &model.ValidateResourcePayload{Valid: true, Resource: kr}, nil
+}
+
+func (r *mutation) ApplyManifests(ctx context.Context, file graphql.Upload, dryRun *bool) (*model.ApplyManifestsPayload, error) {
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	manifests, err := parseManifests(file.File)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errReadManifests))
+		return nil, nil
+	}
+
+	// We validate every resource before we apply any, so that a file with an
+	// invalid resource isn't partially applied.
+	out := &model.ApplyManifestsPayload{}
+	out.Results, out.Valid = applyManifests(ctx, c, r.categories, manifests, client.DryRunAll)
+	if !out.Valid || (dryRun != nil && *dryRun) {
+		return out, nil
+	}
+	out.Results, out.Applied = applyManifests(ctx, c, r.categories, manifests)
+	return out, nil
+}
+
+// parseManifests parses the supplied stream of YAML or JSON documents. Each
+// document must be a Kubernetes resource, or a List of resources. Empty
+// documents are ignored.
+func parseManifests(r io.Reader) ([]*unstructured.Unstructured, error) {
+	out := make([]*unstructured.Unstructured, 0)
+	yr := yaml.NewYAMLReader(bufio.NewReader(r))
+	for {
+		doc, err := yr.Read()
+		if errors.Is(err, io.EOF) {
+			return out, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		j, err := yaml.ToJSON(doc)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtParseManifest, len(out))
+		}
+		if len(bytes.TrimSpace(j)) == 0 || bytes.Equal(j, []byte("null")) {
+			continue
+		}
+
+		u := &unstructured.Unstructured{}
+		if err := u.UnmarshalJSON(j); err != nil {
+			return nil, errors.Wrapf(err, errFmtParseManifest, len(out))
+		}
+		if !u.IsList() {
+			out = append(out, u)
+			continue
+		}
+		if err := u.EachListItem(func(o runtime.Object) error {
+			i, ok := o.(*unstructured.Unstructured)
+			if ok {
+				out = append(out, i)
+			}
+			return nil
+		}); err != nil {
+			return nil, errors.Wrapf(err, errFmtParseManifest, len(out))
+		}
+	}
+}
+
+// applyManifests applies each of the supplied resources using server-side
+// apply, with the supplied options. It returns the result of applying each
+// resource, and whether all resources were applied successfully. The supplied
+// resources are not modified.
+func applyManifests(ctx context.Context, c client.Client, ci model.CategoryIndex, manifests []*unstructured.Unstructured, o ...client.PatchOption) ([]model.ManifestResult, bool) {
+	o = append([]client.PatchOption{client.FieldOwner(fieldOwner)}, o...)
+	out := make([]model.ManifestResult, len(manifests))
+	ok := true
+	for i := range manifests {
+		out[i] = applyManifest(ctx, c, ci, i, manifests[i].DeepCopy(), o...)
+		ok = ok && out[i].Resource != nil
+	}
+	return out, ok
+}
+
+// applyManifest applies the supplied resource using server-side apply. Errors
+// that aren't the resource's fault, for example because the caller is not
+// authorized to apply it, are added to the GraphQL response rather than the
+// result.
+func applyManifest(ctx context.Context, c client.Client, ci model.CategoryIndex, i int, u *unstructured.Unstructured, o ...client.PatchOption) model.ManifestResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	out := model.ManifestResult{Index: i}
+	err := retry.OnError(retry.DefaultBackoff, IsRetriable, func() error { return c.Patch(ctx, u, client.Apply, o...) })
+	if errs := rejected(err); errs != nil {
+		out.Errors = errs
+		return out
+	}
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrapf(err, errFmtApplyManifest, i))
+		return out
+	}
+
+	kr, err := model.GetKubernetesResource(u, ci)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errModelResource))
+		return out
+	}
+	out.Resource = kr
+	return out
+}
+
+func (r *mutation) CreateClaim(ctx context.Context, xrd model.ReferenceID, namespace string, name *string, spec []byte) (*model.CreateClaimPayload, error) {
+	if !strings.HasPrefix(xrd.APIVersion, extv1.Group+"/") || xrd.Kind != extv1.CompositeResourceDefinitionKind {
+		graphql.AddError(ctx, errors.Errorf(errFmtNotXRD, xrd.Kind))
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	d := &extv1.CompositeResourceDefinition{}
+	if err := c.Get(ctx, types.NamespacedName{Name: xrd.Name}, d); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetXRD))
+		return nil, nil
+	}
+
+	u, err := newClaim(d, namespace, name, spec)
+	if err != nil {
+		graphql.AddError(ctx, err)
+		return nil, nil
+	}
+
+	// The API server validates the claim against the schema of the XRD's
+	// referenceable version, which it uses to generate the claim's CRD.
+	err = retry.OnError(retry.DefaultBackoff, IsRetriable, func() error { return c.Create(ctx, u) })
+	if errs := rejected(err); errs != nil {
+		return &model.CreateClaimPayload{Errors: errs}, nil
+	}
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errCreateClaim))
+		return nil, nil
+	}
+
+	cm := model.GetCompositeResourceClaim(u)
+	return &model.CreateClaimPayload{Claim: &cm}, nil
+}
+
+// newClaim returns a claim of the kind offered by the supplied XRD, at its
+// referenceable version, with the supplied spec.
+func newClaim(xrd *extv1.CompositeResourceDefinition, namespace string, name *string, spec []byte) (*unstructured.Unstructured, error) {
+	if xrd.Spec.ClaimNames == nil {
+		return nil, errors.Errorf(errFmtNoClaims, xrd.GetName())
+	}
+
+	version := ""
+	for _, v := range xrd.Spec.Versions {
+		if v.Referenceable {
+			version = v.Name
+			break
+		}
+	}
+	if version == "" {
+		return nil, errors.Errorf(errFmtNoReferenceable, xrd.GetName())
+	}
+
+	s := map[string]interface{}{}
+	if err := json.Unmarshal(spec, &s); err != nil {
+		return nil, errors.Wrap(err, errUnmarshalSpec)
+	}
+
+	u := &unstructured.Unstructured{Object: map[string]interface{}{"spec": s}}
+	u.SetAPIVersion(schema.GroupVersion{Group: xrd.Spec.Group, Version: version}.String())
+	u.SetKind(xrd.Spec.ClaimNames.Kind)
+	u.SetNamespace(namespace)
+	if name != nil && *name != "" {
+		u.SetName(*name)
+	} else {
+		u.SetGenerateName(strings.ToLower(xrd.Spec.ClaimNames.Kind) + "-")
+	}
+	return u, nil
+}
+
+func (r *mutation) UpdateComposition(ctx context.Context, id model.ReferenceID, spec []byte, resourceVersion *string, dryRun *bool) (*model.UpdateCompositionPayload, error) {
+	if !strings.HasPrefix(id.APIVersion, extv1.Group+"/") || id.Kind != extv1.CompositionKind {
+		graphql.AddError(ctx, errors.Errorf(errFmtNotComposition, id.Kind))
+		return nil, nil
+	}
+
+	s := map[string]interface{}{}
+	if err := json.Unmarshal(spec, &s); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errUnmarshalCompSpec))
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	// We update an unstructured composition so that we don't drop any fields
+	// our copy of the Composition type doesn't know about.
+	u, err := getUnstructured(ctx, c, id)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetComposition))
+		return nil, nil
+	}
+	u.Object["spec"] = s
+	if resourceVersion != nil {
+		u.SetResourceVersion(*resourceVersion)
+	}
+
+	// Nothing is persisted unless the dry-run succeeds. Rejections are part of
+	// the payload, so that an editor can show them alongside the spec.
+	dr := u.DeepCopy()
+	err = retry.OnError(retry.DefaultBackoff, IsRetriable, func() error { return c.Update(ctx, dr, client.DryRunAll) })
+	if errs := rejected(err); errs != nil {
+		return &model.UpdateCompositionPayload{Errors: errs}, nil
+	}
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errUpdateComposition))
+		return nil, nil
+	}
+
+	if pointer.BoolPtrDerefOr(dryRun, false) {
+		u = dr
+	} else {
+		err = retry.OnError(retry.DefaultBackoff, IsRetriable, func() error { return c.Update(ctx, u) })
+		if errs := rejected(err); errs != nil {
+			return &model.UpdateCompositionPayload{Errors: errs}, nil
+		}
+		if err != nil {
+			graphql.AddError(ctx, errors.Wrap(err, errUpdateComposition))
+			return nil, nil
+		}
+	}
+
+	cmp := &extv1.Composition{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, cmp); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errConvertComposition))
+		return nil, nil
+	}
+	out := model.GetComposition(cmp)
+	return &model.UpdateCompositionPayload{Composition: &out}, nil
+}
+
+// mergePatch applies the supplied JSON merge patch to the identified resource,
+// and returns the patched resource. We patch rather than update so that we
+// only touch the fields we care about, and don't need to read the resource
+// first.
+func mergePatch(ctx context.Context, c client.Client, id model.ReferenceID, patch interface{}) (*unstructured.Unstructured, error) {
+	p, err := json.Marshal(patch)
+	if err != nil {
+		return nil, errors.Wrap(err, errMarshalPatch)
+	}
+
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(id.APIVersion)
+	u.SetKind(id.Kind)
+	u.SetNamespace(id.Namespace)
+	u.SetName(id.Name)
+
+	err = retry.OnError(retry.DefaultBackoff, IsRetriable, func() error { return c.Patch(ctx, u, client.RawPatch(types.MergePatchType, p)) })
+	return u, err
+}
+
+// annotate sets the supplied annotation of the identified resource, and returns
+// the patched resource. A nil value removes the annotation.
+func annotate(ctx context.Context, c client.Client, id model.ReferenceID, key string, value interface{}) (*unstructured.Unstructured, error) {
+	return mergePatch(ctx, c, id, map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{key: value},
+		},
+	})
+}
+
+func (r *mutation) SetResourcePaused(ctx context.Context, id model.ReferenceID, paused bool) (*model.SetResourcePausedPayload, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	// A null value removes the annotation.
+	var v interface{}
+	if paused {
+		v = "true"
+	}
+	u, err := annotate(ctx, c, id, annotationKeyPaused, v)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errPauseResource))
+		return nil, nil
+	}
+
+	kr, err := model.GetKubernetesResource(u, r.categories)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errModelResource))
+		return nil, nil
+	}
+	return &model.SetResourcePausedPayload{Resource: kr}, nil
+}
+
+func (r *mutation) ForceReconcile(ctx context.Context, id model.ReferenceID) (*model.ForceReconcilePayload, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	u, err := annotate(ctx, c, id, annotationKeyReconcileRequestedAt, time.Now().UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errReconcileResource))
+		return nil, nil
+	}
+
+	kr, err := model.GetKubernetesResource(u, r.categories)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errModelResource))
+		return nil, nil
+	}
+	return &model.ForceReconcilePayload{Resource: kr}, nil
+}
+
+func (r *mutation) InstallPackage(ctx context.Context, typeArg model.PackageType, pkg string, name *string, revisionActivationPolicy *model.RevisionActivationPolicy, packagePullPolicy *model.PackagePullPolicy, packagePullSecrets []string) (*model.InstallPackagePayload, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	// Crossplane serves functions at different API versions depending on its
+	// version, so we create them at whichever version is served.
+	var fn schema.GroupVersionKind
+	if typeArg == model.PackageTypeFunction {
+		if fn, err = servedVersion(c, gkFunction, xunstructured.FunctionVersions); err != nil {
+			graphql.AddError(ctx, errors.Wrap(err, errInstallPackage))
+			return nil, nil
+		}
+	}
+
+	u := newPackage(typeArg, pkg, fn)
+	u.SetName(pointer.StringPtrDerefOr(name, packageName(pkg)))
+
+	pv := fieldpath.Pave(u.Object)
+	if p := getRevisionActivationPolicy(revisionActivationPolicy); p != "" {
+		_ = pv.SetString("spec.revisionActivationPolicy", p)
+	}
+	if p := getPullPolicy(packagePullPolicy); p != "" {
+		_ = pv.SetString("spec.packagePullPolicy", p)
+	}
+	if len(packagePullSecrets) > 0 {
+		refs := make([]interface{}, len(packagePullSecrets))
+		for i, s := range packagePullSecrets {
+			refs[i] = map[string]interface{}{"name": s}
+		}
+		_ = pv.SetValue("spec.packagePullSecrets", refs)
+	}
+
+	if err := retry.OnError(retry.DefaultBackoff, IsRetriable, func() error { return c.Create(ctx, u) }); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errInstallPackage))
+		return nil, nil
+	}
+
+	kr, err := model.GetKubernetesResource(u, r.categories)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errModelResource))
+		return nil, nil
+	}
+	return &model.InstallPackagePayload{Resource: kr}, nil
+}
+
+func (r *mutation) UpgradePackage(ctx context.Context, id model.ReferenceID, pkg string) (*model.UpgradePackagePayload, error) {
+	if !isPackage(id) {
+		graphql.AddError(ctx, errors.Errorf(errFmtNotPackage, id.Kind))
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	u, err := mergePatch(ctx, c, id, map[string]interface{}{
+		"spec": map[string]interface{}{"package": pkg},
+	})
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errUpgradePackage))
+		return nil, nil
+	}
+
+	kr, err := model.GetKubernetesResource(u, r.categories)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errModelResource))
+		return nil, nil
+	}
+	return &model.UpgradePackagePayload{Resource: kr}, nil
+}
+
+func (r *mutation) SetRevisionDesiredState(ctx context.Context, id model.ReferenceID, desiredState model.PackageRevisionDesiredState) (*model.SetRevisionDesiredStatePayload, error) {
+	if !isPackageRevision(id) {
+		graphql.AddError(ctx, errors.Errorf(errFmtNotPackageRevision, id.Kind))
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	u, err := mergePatch(ctx, c, id, map[string]interface{}{
+		"spec": map[string]interface{}{"desiredState": getDesiredState(desiredState)},
+	})
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errSetDesiredState))
+		return nil, nil
+	}
+
+	kr, err := model.GetKubernetesResource(u, r.categories)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errModelResource))
+		return nil, nil
+	}
+	return &model.SetRevisionDesiredStatePayload{Resource: kr}, nil
+}
@@ -17,21 +17,30 @@ package resolvers
 import (
 	"context"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 	"github.com/vektah/gqlparser/v2/gqlerror"
+	kextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
+	extv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
 
 	"github.com/upbound/xgql/internal/auth"
 	"github.com/upbound/xgql/internal/clients"
@@ -101,7 +110,7 @@ func TestCreateKubernetesResource(t *testing.T) {
 	u.SetName("example")
 	uj, _ := json.Marshal(u)
 
-	kr, _ := model.GetKubernetesResource(u)
+	kr, _ := model.GetKubernetesResource(u, nil)
 
 	type args struct {
 		ctx   context.Context
@@ -268,7 +277,7 @@ func TestUpdateKubernetesResource(t *testing.T) {
 	u.SetName("example")
 	uj, _ := json.Marshal(u)
 
-	kr, _ := model.GetKubernetesResource(u)
+	kr, _ := model.GetKubernetesResource(u, nil)
 
 	type args struct {
 		ctx   context.Context
@@ -444,7 +453,7 @@ func TestDeleteKubernetesResource(t *testing.T) {
 	u.SetKind("Example")
 	u.SetName("example")
 
-	kr, _ := model.GetKubernetesResource(u)
+	kr, _ := model.GetKubernetesResource(u, nil)
 
 	cases := map[string]struct {
 		reason  string
@@ -528,3 +537,1474 @@ func TestDeleteKubernetesResource(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteCompositeResource(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	ref := func(u *unstructured.Unstructured) map[string]interface{} {
+		return map[string]interface{}{"apiVersion": u.GetAPIVersion(), "kind": u.GetKind(), "name": u.GetName()}
+	}
+	managed := func(name, deletionPolicy string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "example.org/v1",
+			"kind":       "Managed",
+			"metadata":   map[string]interface{}{"name": name},
+			"spec": map[string]interface{}{
+				"providerConfigRef": map[string]interface{}{"name": "default"},
+				"deletionPolicy":    deletionPolicy,
+			},
+		}}
+	}
+	composite := func(name string, composed ...*unstructured.Unstructured) *unstructured.Unstructured {
+		refs := make([]interface{}, len(composed))
+		for i := range composed {
+			refs[i] = ref(composed[i])
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "example.org/v1",
+			"kind":       "Composite",
+			"metadata":   map[string]interface{}{"name": name},
+			"spec":       map[string]interface{}{"resourceRefs": refs},
+		}}
+	}
+
+	deletes := managed("deletes", "Delete")
+	orphans := managed("orphans", "Orphan")
+	nested := composite("nested", orphans)
+	gone := managed("gone", "Delete")
+	xr := composite("xr", deletes, nested, gone)
+
+	existing := map[string]*unstructured.Unstructured{"xr": xr, "nested": nested, "deletes": deletes, "orphans": orphans}
+	get := func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+		u, ok := existing[key.Name]
+		if !ok {
+			return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+		}
+		*obj.(*unstructured.Unstructured) = *u.DeepCopy()
+		return nil
+	}
+
+	modelled := func(u *unstructured.Unstructured) model.KubernetesResource {
+		kr, _ := model.GetKubernetesResource(u, nil)
+		return kr
+	}
+
+	id := model.ReferenceID{APIVersion: xr.GetAPIVersion(), Kind: xr.GetKind(), Name: xr.GetName()}
+	orphan := model.DeletionPropagationOrphan
+
+	type args struct {
+		ctx               context.Context
+		id                model.ReferenceID
+		propagationPolicy *model.DeletionPropagation
+		dryRun            *bool
+	}
+	type want struct {
+		payload *model.DeleteCompositePayload
+		opts    []client.DeleteOption
+		err     error
+		errs    gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason string
+		get    test.MockGetFn
+		delete error
+		args   args
+		want   want
+	}{
+		"GetError": {
+			reason: "If we can't get the composite resource we should add the error to the GraphQL context and return early.",
+			get:    test.NewMockGetFn(errBoom),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				id:  id,
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetXR).Error()),
+				},
+			},
+		},
+		"NotComposite": {
+			reason: "If the identified resource is not a composite resource we should add an error to the GraphQL context and return early.",
+			get:    get,
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				id:  model.ReferenceID{APIVersion: deletes.GetAPIVersion(), Kind: deletes.GetKind(), Name: deletes.GetName()},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Errorf(errFmtNotComposite, deletes.GetKind()).Error()),
+				},
+			},
+		},
+		"DeleteError": {
+			reason: "If we can't delete the composite resource we should add the error to the GraphQL context and return early.",
+			get:    get,
+			delete: errBoom,
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				id:  id,
+			},
+			want: want{
+				opts: []client.DeleteOption{},
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errDeleteXR).Error()),
+				},
+			},
+		},
+		"DryRun": {
+			reason: "A dry run should return the composed resources the delete would cascade to, including those of nested composite resources, without deleting anything.",
+			get:    get,
+			args: args{
+				ctx:    graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				id:     id,
+				dryRun: pointer.BoolPtr(true),
+			},
+			want: want{
+				payload: &model.DeleteCompositePayload{
+					Resource:              modelled(xr),
+					Deleted:               false,
+					AffectedResources:     []model.KubernetesResource{modelled(deletes), modelled(nested), modelled(orphans)},
+					ExternalResourceCount: 1,
+				},
+				opts: []client.DeleteOption{client.DryRunAll},
+			},
+		},
+		"Orphan": {
+			reason: "Orphaned composed resources should not be affected by the delete.",
+			get:    get,
+			args: args{
+				ctx:               graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				id:                id,
+				propagationPolicy: &orphan,
+			},
+			want: want{
+				payload: &model.DeleteCompositePayload{
+					Resource:          modelled(xr),
+					Deleted:           true,
+					AffectedResources: []model.KubernetesResource{},
+				},
+				opts: []client.DeleteOption{client.PropagationPolicy(metav1.DeletePropagationOrphan)},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var opts []client.DeleteOption
+			cc := ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: tc.get,
+					MockDelete: func(_ context.Context, _ client.Object, o ...client.DeleteOption) error {
+						opts = o
+						return tc.delete
+					},
+				}, nil
+			})
+			m := &mutation{clients: cc}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := m.DeleteCompositeResource(tc.args.ctx, tc.args.id, tc.args.propagationPolicy, tc.args.dryRun)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.DeleteCompositeResource(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.DeleteCompositeResource(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.opts, opts); diff != "" {
+				t.Errorf("\n%s\ns.DeleteCompositeResource(...): -want delete options, +got delete options:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.payload, got, cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\ns.DeleteCompositeResource(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDeleteClaim(t *testing.T) {
+	managed := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.org/v1",
+		"kind":       "Managed",
+		"metadata":   map[string]interface{}{"name": "managed"},
+		"spec": map[string]interface{}{
+			"providerConfigRef": map[string]interface{}{"name": "default"},
+		},
+	}}
+	xr := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.org/v1",
+		"kind":       "Composite",
+		"metadata":   map[string]interface{}{"name": "xr"},
+		"spec": map[string]interface{}{
+			"resourceRefs": []interface{}{
+				map[string]interface{}{"apiVersion": "example.org/v1", "kind": "Managed", "name": "managed"},
+			},
+		},
+	}}
+	xrc := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.org/v1",
+		"kind":       "Claim",
+		"metadata":   map[string]interface{}{"namespace": "default", "name": "xrc"},
+		"spec": map[string]interface{}{
+			"resourceRef": map[string]interface{}{"apiVersion": "example.org/v1", "kind": "Composite", "name": "xr"},
+		},
+	}}
+
+	existing := map[string]*unstructured.Unstructured{"xrc": xrc, "xr": xr, "managed": managed}
+	get := func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+		*obj.(*unstructured.Unstructured) = *existing[key.Name].DeepCopy()
+		return nil
+	}
+
+	modelled := func(u *unstructured.Unstructured) model.KubernetesResource {
+		kr, _ := model.GetKubernetesResource(u, nil)
+		return kr
+	}
+
+	type args struct {
+		ctx context.Context
+		id  model.ReferenceID
+	}
+	type want struct {
+		payload *model.DeleteCompositePayload
+		err     error
+		errs    gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotClaim": {
+			reason: "If the identified resource is not a claim we should add an error to the GraphQL context and return early.",
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				id:  model.ReferenceID{APIVersion: xr.GetAPIVersion(), Kind: xr.GetKind(), Name: xr.GetName()},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Errorf(errFmtNotClaim, xr.GetKind()).Error()),
+				},
+			},
+		},
+		"Success": {
+			reason: "Deleting a claim should affect its composite resource and the resources it composes.",
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				id:  model.ReferenceID{APIVersion: xrc.GetAPIVersion(), Kind: xrc.GetKind(), Namespace: xrc.GetNamespace(), Name: xrc.GetName()},
+			},
+			want: want{
+				payload: &model.DeleteCompositePayload{
+					Resource:              modelled(xrc),
+					Deleted:               true,
+					AffectedResources:     []model.KubernetesResource{modelled(xr), modelled(managed)},
+					ExternalResourceCount: 1,
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cc := ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet:    get,
+					MockDelete: test.NewMockDeleteFn(nil),
+				}, nil
+			})
+			m := &mutation{clients: cc}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := m.DeleteClaim(tc.args.ctx, tc.args.id, nil, nil)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.DeleteClaim(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.DeleteClaim(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.payload, got, cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\ns.DeleteClaim(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateResource(t *testing.T) {
+	errBoom := errors.New("boom")
+	errUnmarshal := json.Unmarshal([]byte("\""), &unstructured.Unstructured{})
+	errExists := kerrors.NewAlreadyExists(schema.GroupResource{Group: "example.org", Resource: "examples"}, "example")
+
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("example.org/v1")
+	u.SetKind("Example")
+	u.SetName("example")
+	uj, _ := json.Marshal(u)
+
+	kr, _ := model.GetKubernetesResource(u, nil)
+	update := model.ValidationOperationUpdate
+
+	type args struct {
+		ctx   context.Context
+		input model.ValidateResourceInput
+	}
+	type want struct {
+		payload *model.ValidateResourcePayload
+		err     error
+		errs    gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"GetClientError": {
+			reason: "If we can't get a client we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{}, errBoom
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetClient).Error()),
+				},
+			},
+		},
+		"UnmarshalUnstructuredError": {
+			reason: "If we can't get unmarshal the unstructured input we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				input: model.ValidateResourceInput{
+					Unstructured: []byte("\""),
+				},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errUnmarshal, errUnmarshalUnstructured).Error()),
+				},
+			},
+		},
+		"ValidateError": {
+			reason: "If the dry-run fails for a reason other than the resource being rejected we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockCreate: test.NewMockCreateFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				input: model.ValidateResourceInput{
+					Unstructured: uj,
+				},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errValidateResource).Error()),
+				},
+			},
+		},
+		"Rejected": {
+			reason: "If the API server rejects the resource we should return the reasons it was rejected.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockCreate: test.NewMockCreateFn(errExists),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				input: model.ValidateResourceInput{
+					Unstructured: uj,
+				},
+			},
+			want: want{
+				payload: &model.ValidateResourcePayload{
+					Valid:  false,
+					Errors: model.GetValidationErrors(errExists.Status()),
+				},
+			},
+		},
+		"ValidCreate": {
+			reason: "If a dry-run create succeeds we should model and return the resource.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockCreate: func(_ context.Context, _ client.Object, opts ...client.CreateOption) error {
+						o := &client.CreateOptions{}
+						o.ApplyOptions(opts)
+						if len(o.DryRun) == 0 {
+							return errors.New("not a dry-run")
+						}
+						return nil
+					},
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				input: model.ValidateResourceInput{
+					Unstructured: uj,
+				},
+			},
+			want: want{
+				payload: &model.ValidateResourcePayload{
+					Valid:    true,
+					Resource: kr,
+				},
+			},
+		},
+		"ValidUpdate": {
+			reason: "If a dry-run update succeeds we should model and return the resource.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockUpdate: func(_ context.Context, _ client.Object, opts ...client.UpdateOption) error {
+						o := &client.UpdateOptions{}
+						o.ApplyOptions(opts)
+						if len(o.DryRun) == 0 {
+							return errors.New("not a dry-run")
+						}
+						return nil
+					},
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				input: model.ValidateResourceInput{
+					Unstructured: uj,
+					Operation:    &update,
+				},
+			},
+			want: want{
+				payload: &model.ValidateResourcePayload{
+					Valid:    true,
+					Resource: kr,
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := &mutation{clients: tc.clients}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := m.ValidateResource(tc.args.ctx, tc.args.input)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.ValidateResource(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.ValidateResource(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.payload, got, cmpopts.IgnoreFields(model.GenericResource{}, "Unstructured"), cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\ns.ValidateResource(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestApplyManifests(t *testing.T) {
+	errExists := kerrors.NewAlreadyExists(schema.GroupResource{Group: "example.org", Resource: "examples"}, "a")
+	errNoKind := (&unstructured.Unstructured{}).UnmarshalJSON([]byte(`{"apiVersion":"v1"}`))
+
+	manifests := `
+apiVersion: example.org/v1
+kind: Example
+metadata:
+  name: a
+---
+# Nothing to see here.
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: example.org/v1
+  kind: Example
+  metadata:
+    name: b
+`
+
+	kr := func(name string) model.KubernetesResource {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("example.org/v1")
+		u.SetKind("Example")
+		u.SetName(name)
+		kr, _ := model.GetKubernetesResource(u, nil)
+		return kr
+	}
+
+	// patches returns a patch function that records how many dry-run and real
+	// patches it was called for, and returns the supplied error.
+	type calls struct{ dryRun, applied int }
+	patches := func(c *calls, err error) test.MockPatchFn {
+		return func(_ context.Context, _ client.Object, p client.Patch, opts ...client.PatchOption) error {
+			if p.Type() != client.Apply.Type() {
+				return errors.New("not a server-side apply")
+			}
+			o := &client.PatchOptions{}
+			o.ApplyOptions(opts)
+			if len(o.DryRun) > 0 {
+				c.dryRun++
+			} else {
+				c.applied++
+			}
+			return err
+		}
+	}
+
+	type args struct {
+		manifests string
+		dryRun    *bool
+	}
+	type want struct {
+		payload *model.ApplyManifestsPayload
+		errs    gqlerror.List
+		calls   calls
+	}
+
+	cases := map[string]struct {
+		reason  string
+		patchFn func(c *calls) test.MockPatchFn
+		args    args
+		want    want
+	}{
+		"ParseError": {
+			reason: "If we can't parse the manifests we should add the error to the GraphQL context and return early.",
+			args:   args{manifests: "apiVersion: v1\n"},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errors.Wrapf(errNoKind, errFmtParseManifest, 0), errReadManifests).Error()),
+				},
+			},
+		},
+		"Rejected": {
+			reason: "If the API server rejects a resource we should return the reasons it was rejected, and not apply any resources.",
+			patchFn: func(c *calls) test.MockPatchFn {
+				return patches(c, errExists)
+			},
+			args: args{manifests: manifests},
+			want: want{
+				payload: &model.ApplyManifestsPayload{
+					Results: []model.ManifestResult{
+						{Index: 0, Errors: model.GetValidationErrors(errExists.Status())},
+						{Index: 1, Errors: model.GetValidationErrors(errExists.Status())},
+					},
+				},
+				calls: calls{dryRun: 2},
+			},
+		},
+		"DryRun": {
+			reason: "If this is a dry run we should validate but not apply the resources.",
+			patchFn: func(c *calls) test.MockPatchFn {
+				return patches(c, nil)
+			},
+			args: args{manifests: manifests, dryRun: func() *bool { b := true; return &b }()},
+			want: want{
+				payload: &model.ApplyManifestsPayload{
+					Valid: true,
+					Results: []model.ManifestResult{
+						{Index: 0, Resource: kr("a")},
+						{Index: 1, Resource: kr("b")},
+					},
+				},
+				calls: calls{dryRun: 2},
+			},
+		},
+		"Applied": {
+			reason: "If all resources are valid we should apply them.",
+			patchFn: func(c *calls) test.MockPatchFn {
+				return patches(c, nil)
+			},
+			args: args{manifests: manifests},
+			want: want{
+				payload: &model.ApplyManifestsPayload{
+					Valid:   true,
+					Applied: true,
+					Results: []model.ManifestResult{
+						{Index: 0, Resource: kr("a")},
+						{Index: 1, Resource: kr("b")},
+					},
+				},
+				calls: calls{dryRun: 2, applied: 2},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &calls{}
+			mc := &test.MockClient{}
+			if tc.patchFn != nil {
+				mc.MockPatch = tc.patchFn(c)
+			}
+			m := &mutation{clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return mc, nil
+			})}
+
+			ctx := graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover)
+			got, err := m.ApplyManifests(ctx, graphql.Upload{File: strings.NewReader(tc.args.manifests)}, tc.args.dryRun)
+			errs := graphql.GetErrors(ctx)
+
+			if err != nil {
+				t.Errorf("\n%s\nm.ApplyManifests(...): unexpected error: %s\n", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nm.ApplyManifests(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.payload, got, cmpopts.IgnoreFields(model.GenericResource{}, "Unstructured"), cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\nm.ApplyManifests(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.calls, *c, cmp.AllowUnexported(calls{})); diff != "" {
+				t.Errorf("\n%s\nm.ApplyManifests(...): -want patch calls, +got patch calls:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreateClaim(t *testing.T) {
+	errBoom := errors.New("boom")
+	errInvalid := kerrors.NewInvalid(schema.GroupKind{Group: "example.org", Kind: "Example"}, "", field.ErrorList{
+		field.Required(field.NewPath("spec", "size"), "size is required"),
+	})
+
+	id := model.ReferenceID{APIVersion: extv1.SchemeGroupVersion.String(), Kind: extv1.CompositeResourceDefinitionKind, Name: "xexamples.example.org"}
+	xrd := extv1.CompositeResourceDefinition{
+		Spec: extv1.CompositeResourceDefinitionSpec{
+			Group:      "example.org",
+			ClaimNames: &kextv1.CustomResourceDefinitionNames{Kind: "Example"},
+			Versions: []extv1.CompositeResourceDefinitionVersion{
+				{Name: "v1alpha1"},
+				{Name: "v1", Referenceable: true},
+			},
+		},
+	}
+	getXRD := func(d extv1.CompositeResourceDefinition) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(obj client.Object) error {
+			*obj.(*extv1.CompositeResourceDefinition) = d
+			return nil
+		})
+	}
+
+	claim := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{"size": int64(1)}}}
+	claim.SetAPIVersion("example.org/v1")
+	claim.SetKind("Example")
+	claim.SetNamespace("default")
+	claim.SetGenerateName("example-")
+	cm := model.GetCompositeResourceClaim(claim)
+
+	type args struct {
+		xrd  model.ReferenceID
+		spec []byte
+	}
+	type want struct {
+		payload *model.CreateClaimPayload
+		errs    gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Client
+		args   args
+		want   want
+	}{
+		"NotXRD": {
+			reason: "If the supplied ID is not an XRD we should add an error to the GraphQL context and return early.",
+			args:   args{xrd: model.ReferenceID{APIVersion: "example.org/v1", Kind: "Example", Name: "cool"}},
+			want: want{
+				errs: gqlerror.List{gqlerror.Errorf(errors.Errorf(errFmtNotXRD, "Example").Error())},
+			},
+		},
+		"GetXRDError": {
+			reason: "If we can't get the XRD we should add the error to the GraphQL context and return early.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			args:   args{xrd: id},
+			want: want{
+				errs: gqlerror.List{gqlerror.Errorf(errors.Wrap(errBoom, errGetXRD).Error())},
+			},
+		},
+		"NoClaims": {
+			reason: "If the XRD does not offer a claim we should add an error to the GraphQL context and return early.",
+			client: &test.MockClient{MockGet: getXRD(extv1.CompositeResourceDefinition{})},
+			args:   args{xrd: id},
+			want: want{
+				errs: gqlerror.List{gqlerror.Errorf(errors.Errorf(errFmtNoClaims, "").Error())},
+			},
+		},
+		"Rejected": {
+			reason: "If the API server rejects the claim we should return the reasons it was rejected.",
+			client: &test.MockClient{
+				MockGet:    getXRD(xrd),
+				MockCreate: test.NewMockCreateFn(errInvalid),
+			},
+			args: args{xrd: id, spec: []byte(`{}`)},
+			want: want{
+				payload: &model.CreateClaimPayload{Errors: model.GetValidationErrors(errInvalid.Status())},
+			},
+		},
+		"Created": {
+			reason: "If the claim is created we should return it.",
+			client: &test.MockClient{
+				MockGet: getXRD(xrd),
+				MockCreate: test.NewMockCreateFn(nil, func(obj client.Object) error {
+					if diff := cmp.Diff(claim, obj); diff != "" {
+						t.Errorf("-want claim, +got claim:\n%s", diff)
+					}
+					return nil
+				}),
+			},
+			args: args{xrd: id, spec: []byte(`{"size":1}`)},
+			want: want{
+				payload: &model.CreateClaimPayload{Claim: &cm},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := &mutation{clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return tc.client, nil
+			})}
+
+			ctx := graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover)
+			got, err := m.CreateClaim(ctx, tc.args.xrd, "default", nil, tc.args.spec)
+			errs := graphql.GetErrors(ctx)
+
+			if err != nil {
+				t.Errorf("\n%s\nm.CreateClaim(...): unexpected error: %s\n", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nm.CreateClaim(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.payload, got, cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\nm.CreateClaim(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpdateComposition(t *testing.T) {
+	errInvalid := kerrors.NewInvalid(schema.GroupKind{Group: extv1.Group, Kind: extv1.CompositionKind}, "example", field.ErrorList{
+		field.Required(field.NewPath("spec", "compositeTypeRef"), "compositeTypeRef is required"),
+	})
+
+	id := model.ReferenceID{APIVersion: extv1.SchemeGroupVersion.String(), Kind: extv1.CompositionKind, Name: "example"}
+	current := &extv1.Composition{
+		TypeMeta:   metav1.TypeMeta{APIVersion: extv1.SchemeGroupVersion.String(), Kind: extv1.CompositionKind},
+		ObjectMeta: metav1.ObjectMeta{Name: "example", ResourceVersion: "2"},
+		Spec: extv1.CompositionSpec{
+			CompositeTypeRef: extv1.TypeReference{APIVersion: "example.org/v1", Kind: "XExample"},
+		},
+	}
+	getComposition := test.NewMockGetFn(nil, func(obj client.Object) error {
+		u, _ := runtime.DefaultUnstructuredConverter.ToUnstructured(current)
+		obj.(*unstructured.Unstructured).SetUnstructuredContent(u)
+		return nil
+	})
+
+	// updated returns the composition with our new spec, at the supplied
+	// resource version.
+	updated := func(rv string) *model.Composition {
+		u := current.DeepCopy()
+		u.SetResourceVersion(rv)
+		u.Spec.CompositeTypeRef.Kind = "XCoolExample"
+		cm := model.GetComposition(u)
+		return &cm
+	}
+
+	type args struct {
+		id              model.ReferenceID
+		resourceVersion *string
+		dryRun          *bool
+	}
+	type want struct {
+		payload *model.UpdateCompositionPayload
+		errs    gqlerror.List
+		updates int
+	}
+
+	cases := map[string]struct {
+		reason string
+		update func(obj client.Object, o ...client.UpdateOption) error
+		args   args
+		want   want
+	}{
+		"NotComposition": {
+			reason: "If the ID is not a composition we should add an error to the GraphQL context and return early.",
+			args:   args{id: model.ReferenceID{APIVersion: "example.org/v1", Kind: "Example", Name: "example"}},
+			want: want{
+				errs: gqlerror.List{gqlerror.Errorf(errors.Errorf(errFmtNotComposition, "Example").Error())},
+			},
+		},
+		"Rejected": {
+			reason: "If the dry-run is rejected we should return the reasons, and not persist the update.",
+			update: func(_ client.Object, _ ...client.UpdateOption) error { return errInvalid },
+			args:   args{id: id},
+			want: want{
+				payload: &model.UpdateCompositionPayload{Errors: model.GetValidationErrors(errInvalid.Status())},
+				updates: 1,
+			},
+		},
+		"DryRun": {
+			reason: "A dry run should return the composition as it would be persisted without persisting it.",
+			update: func(_ client.Object, o ...client.UpdateOption) error {
+				if diff := cmp.Diff([]client.UpdateOption{client.DryRunAll}, o); diff != "" {
+					t.Errorf("-want update options, +got update options:\n%s", diff)
+				}
+				return nil
+			},
+			args: args{id: id, dryRun: pointer.BoolPtr(true)},
+			want: want{
+				payload: &model.UpdateCompositionPayload{Composition: updated("2")},
+				updates: 1,
+			},
+		},
+		"Updated": {
+			reason: "If the dry-run succeeds we should persist and return the updated composition, at the supplied resource version.",
+			update: func(obj client.Object, _ ...client.UpdateOption) error {
+				if diff := cmp.Diff("1", obj.GetResourceVersion()); diff != "" {
+					t.Errorf("-want resource version, +got resource version:\n%s", diff)
+				}
+				return nil
+			},
+			args: args{id: id, resourceVersion: pointer.StringPtr("1")},
+			want: want{
+				payload: &model.UpdateCompositionPayload{Composition: updated("1")},
+				updates: 2,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			updates := 0
+			m := &mutation{clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: getComposition,
+					MockUpdate: func(_ context.Context, obj client.Object, o ...client.UpdateOption) error {
+						updates++
+						return tc.update(obj, o...)
+					},
+				}, nil
+			})}
+
+			ctx := graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover)
+			got, err := m.UpdateComposition(ctx, tc.args.id, []byte(`{"compositeTypeRef":{"apiVersion":"example.org/v1","kind":"XCoolExample"}}`), tc.args.resourceVersion, tc.args.dryRun)
+			errs := graphql.GetErrors(ctx)
+
+			if err != nil {
+				t.Errorf("\n%s\nm.UpdateComposition(...): unexpected error: %s\n", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nm.UpdateComposition(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.updates, updates); diff != "" {
+				t.Errorf("\n%s\nm.UpdateComposition(...): -want updates, +got updates:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.payload, got, cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\nm.UpdateComposition(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSetResourcePaused(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("example.org/v1")
+	u.SetKind("Example")
+	u.SetName("example")
+
+	kr, _ := model.GetKubernetesResource(u, nil)
+
+	id := model.ReferenceID{
+		APIVersion: u.GetAPIVersion(),
+		Kind:       u.GetKind(),
+		Namespace:  u.GetNamespace(),
+		Name:       u.GetName(),
+	}
+
+	type args struct {
+		ctx    context.Context
+		id     model.ReferenceID
+		paused bool
+	}
+	type want struct {
+		payload *model.SetResourcePausedPayload
+		err     error
+		errs    gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"GetClientError": {
+			reason: "If we can't get a client we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{}, errBoom
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetClient).Error()),
+				},
+			},
+		},
+		"PatchError": {
+			reason: "If we can't patch a Kubernetes resource we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockPatch: test.NewMockPatchFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errPauseResource).Error()),
+				},
+			},
+		},
+		"Paused": {
+			reason: "If we successfully pause a Kubernetes resource we should model and return it.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockPatch: func(_ context.Context, obj client.Object, p client.Patch, _ ...client.PatchOption) error {
+						got, _ := p.Data(obj)
+						want := `{"metadata":{"annotations":{"crossplane.io/paused":"true"}}}`
+						if diff := cmp.Diff(want, string(got)); diff != "" {
+							t.Errorf("Patch(...): -want, +got:\n%s", diff)
+						}
+						return nil
+					},
+				}, nil
+			}),
+			args: args{
+				ctx:    graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				id:     id,
+				paused: true,
+			},
+			want: want{
+				payload: &model.SetResourcePausedPayload{
+					Resource: kr,
+				},
+			},
+		},
+		"Resumed": {
+			reason: "If we successfully resume a Kubernetes resource we should model and return it.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockPatch: func(_ context.Context, obj client.Object, p client.Patch, _ ...client.PatchOption) error {
+						got, _ := p.Data(obj)
+						want := `{"metadata":{"annotations":{"crossplane.io/paused":null}}}`
+						if diff := cmp.Diff(want, string(got)); diff != "" {
+							t.Errorf("Patch(...): -want, +got:\n%s", diff)
+						}
+						return nil
+					},
+				}, nil
+			}),
+			args: args{
+				ctx:    graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				id:     id,
+				paused: false,
+			},
+			want: want{
+				payload: &model.SetResourcePausedPayload{
+					Resource: kr,
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := &mutation{clients: tc.clients}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := m.SetResourcePaused(tc.args.ctx, tc.args.id, tc.args.paused)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.SetResourcePaused(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.SetResourcePaused(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.payload, got, cmpopts.IgnoreFields(model.GenericResource{}, "Unstructured"), cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\ns.SetResourcePaused(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestForceReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("example.org/v1")
+	u.SetKind("Example")
+	u.SetName("example")
+
+	kr, _ := model.GetKubernetesResource(u, nil)
+
+	type args struct {
+		ctx context.Context
+		id  model.ReferenceID
+	}
+	type want struct {
+		payload *model.ForceReconcilePayload
+		err     error
+		errs    gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"GetClientError": {
+			reason: "If we can't get a client we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{}, errBoom
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetClient).Error()),
+				},
+			},
+		},
+		"PatchError": {
+			reason: "If we can't patch a Kubernetes resource we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockPatch: test.NewMockPatchFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errReconcileResource).Error()),
+				},
+			},
+		},
+		"Success": {
+			reason: "If we successfully request a reconcile we should model and return the resource.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockPatch: func(_ context.Context, obj client.Object, p client.Patch, _ ...client.PatchOption) error {
+						d, _ := p.Data(obj)
+						got := &unstructured.Unstructured{}
+						_ = json.Unmarshal(d, &got.Object)
+						if _, err := time.Parse(time.RFC3339Nano, got.GetAnnotations()[annotationKeyReconcileRequestedAt]); err != nil {
+							t.Errorf("Patch(...): want a timestamp annotation: %s", err)
+						}
+						return nil
+					},
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				id: model.ReferenceID{
+					APIVersion: u.GetAPIVersion(),
+					Kind:       u.GetKind(),
+					Name:       u.GetName(),
+				},
+			},
+			want: want{
+				payload: &model.ForceReconcilePayload{
+					Resource: kr,
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := &mutation{clients: tc.clients}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := m.ForceReconcile(tc.args.ctx, tc.args.id)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.ForceReconcile(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.ForceReconcile(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.payload, got, cmpopts.IgnoreFields(model.GenericResource{}, "Unstructured"), cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\ns.ForceReconcile(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestInstallPackage(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	manual := model.RevisionActivationPolicyManual
+	always := model.PackagePullPolicyAlways
+
+	p := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"package":                  "xpkg.upbound.io/crossplane-contrib/provider-aws:v0.1.0",
+			"revisionActivationPolicy": "Manual",
+			"packagePullPolicy":        "Always",
+			"packagePullSecrets":       []interface{}{map[string]interface{}{"name": "creds"}},
+		},
+	}}
+	p.SetGroupVersionKind(pkgv1.ProviderGroupVersionKind)
+	p.SetName("crossplane-contrib-provider-aws")
+
+	kr, _ := model.GetKubernetesResource(p, nil)
+
+	type args struct {
+		ctx                      context.Context
+		t                        model.PackageType
+		pkg                      string
+		name                     *string
+		revisionActivationPolicy *model.RevisionActivationPolicy
+		packagePullPolicy        *model.PackagePullPolicy
+		packagePullSecrets       []string
+	}
+	type want struct {
+		payload *model.InstallPackagePayload
+		err     error
+		errs    gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"GetClientError": {
+			reason: "If we can't get a client we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{}, errBoom
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetClient).Error()),
+				},
+			},
+		},
+		"CreateError": {
+			reason: "If we can't create a package we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockCreate: test.NewMockCreateFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				t:   model.PackageTypeProvider,
+				pkg: "xpkg.upbound.io/crossplane-contrib/provider-aws:v0.1.0",
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errInstallPackage).Error()),
+				},
+			},
+		},
+		"Success": {
+			reason: "If we successfully create a package we should model and return it.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockCreate: test.NewMockCreateFn(nil, func(obj client.Object) error {
+						if diff := cmp.Diff(p, obj); diff != "" {
+							t.Errorf("Create(...): -want, +got:\n%s", diff)
+						}
+						return nil
+					}),
+				}, nil
+			}),
+			args: args{
+				ctx:                      graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				t:                        model.PackageTypeProvider,
+				pkg:                      "xpkg.upbound.io/crossplane-contrib/provider-aws:v0.1.0",
+				revisionActivationPolicy: &manual,
+				packagePullPolicy:        &always,
+				packagePullSecrets:       []string{"creds"},
+			},
+			want: want{
+				payload: &model.InstallPackagePayload{
+					Resource: kr,
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := &mutation{clients: tc.clients}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := m.InstallPackage(tc.args.ctx, tc.args.t, tc.args.pkg, tc.args.name, tc.args.revisionActivationPolicy, tc.args.packagePullPolicy, tc.args.packagePullSecrets)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.InstallPackage(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.InstallPackage(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.payload, got, cmpopts.IgnoreFields(model.Provider{}, "Unstructured"), cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\ns.InstallPackage(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpgradePackage(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	id := model.ReferenceID{
+		APIVersion: pkgv1.ProviderGroupVersionKind.GroupVersion().String(),
+		Kind:       pkgv1.ProviderKind,
+		Name:       "provider-aws",
+	}
+
+	p := &unstructured.Unstructured{}
+	p.SetGroupVersionKind(pkgv1.ProviderGroupVersionKind)
+	p.SetName("provider-aws")
+
+	kr, _ := model.GetKubernetesResource(p, nil)
+
+	type args struct {
+		ctx context.Context
+		id  model.ReferenceID
+		pkg string
+	}
+	type want struct {
+		payload *model.UpgradePackagePayload
+		err     error
+		errs    gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"NotAPackage": {
+			reason: "If the ID doesn't identify a package we should add an error to the GraphQL context and return early.",
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				id:  model.ReferenceID{APIVersion: "example.org/v1", Kind: "Example", Name: "example"},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Errorf(errFmtNotPackage, "Example").Error()),
+				},
+			},
+		},
+		"PatchError": {
+			reason: "If we can't patch a package we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockPatch: test.NewMockPatchFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				id:  id,
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errUpgradePackage).Error()),
+				},
+			},
+		},
+		"Success": {
+			reason: "If we successfully patch a package we should model and return it.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockPatch: func(_ context.Context, obj client.Object, p client.Patch, _ ...client.PatchOption) error {
+						got, _ := p.Data(obj)
+						want := `{"spec":{"package":"crossplane/provider-aws:v0.2.0"}}`
+						if diff := cmp.Diff(want, string(got)); diff != "" {
+							t.Errorf("Patch(...): -want, +got:\n%s", diff)
+						}
+						return nil
+					},
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				id:  id,
+				pkg: "crossplane/provider-aws:v0.2.0",
+			},
+			want: want{
+				payload: &model.UpgradePackagePayload{
+					Resource: kr,
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := &mutation{clients: tc.clients}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := m.UpgradePackage(tc.args.ctx, tc.args.id, tc.args.pkg)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.UpgradePackage(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.UpgradePackage(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.payload, got, cmpopts.IgnoreFields(model.Provider{}, "Unstructured"), cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\ns.UpgradePackage(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSetRevisionDesiredState(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	id := model.ReferenceID{
+		APIVersion: pkgv1.ProviderRevisionGroupVersionKind.GroupVersion().String(),
+		Kind:       pkgv1.ProviderRevisionKind,
+		Name:       "provider-aws-a1b2c3",
+	}
+
+	pr := &unstructured.Unstructured{}
+	pr.SetGroupVersionKind(pkgv1.ProviderRevisionGroupVersionKind)
+	pr.SetName("provider-aws-a1b2c3")
+
+	kr, _ := model.GetKubernetesResource(pr, nil)
+
+	type args struct {
+		ctx          context.Context
+		id           model.ReferenceID
+		desiredState model.PackageRevisionDesiredState
+	}
+	type want struct {
+		payload *model.SetRevisionDesiredStatePayload
+		err     error
+		errs    gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"NotARevision": {
+			reason: "If the ID doesn't identify a package revision we should add an error to the GraphQL context and return early.",
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				id:  model.ReferenceID{APIVersion: pkgv1.ProviderGroupVersionKind.GroupVersion().String(), Kind: pkgv1.ProviderKind, Name: "provider-aws"},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Errorf(errFmtNotPackageRevision, pkgv1.ProviderKind).Error()),
+				},
+			},
+		},
+		"PatchError": {
+			reason: "If we can't patch a package revision we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockPatch: test.NewMockPatchFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx:          graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				id:           id,
+				desiredState: model.PackageRevisionDesiredStateActive,
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errSetDesiredState).Error()),
+				},
+			},
+		},
+		"Success": {
+			reason: "If we successfully patch a package revision we should model and return it.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockPatch: func(_ context.Context, obj client.Object, p client.Patch, _ ...client.PatchOption) error {
+						got, _ := p.Data(obj)
+						want := `{"spec":{"desiredState":"Inactive"}}`
+						if diff := cmp.Diff(want, string(got)); diff != "" {
+							t.Errorf("Patch(...): -want, +got:\n%s", diff)
+						}
+						return nil
+					},
+				}, nil
+			}),
+			args: args{
+				ctx:          graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				id:           id,
+				desiredState: model.PackageRevisionDesiredStateInactive,
+			},
+			want: want{
+				payload: &model.SetRevisionDesiredStatePayload{
+					Resource: kr,
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := &mutation{clients: tc.clients}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := m.SetRevisionDesiredState(tc.args.ctx, tc.args.id, tc.args.desiredState)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.SetRevisionDesiredState(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.SetRevisionDesiredState(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.payload, got, cmpopts.IgnoreFields(model.ProviderRevision{}, "Unstructured"), cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\ns.SetRevisionDesiredState(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
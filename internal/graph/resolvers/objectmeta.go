@@ -33,7 +33,8 @@ const (
 )
 
 type objectMeta struct {
-	clients ClientCache
+	clients    ClientCache
+	categories model.CategoryIndex
 }
 
 func (r *objectMeta) Owners(ctx context.Context, obj *model.ObjectMeta) (*model.OwnerConnection, error) {
@@ -59,7 +60,7 @@ func (r *objectMeta) Owners(ctx context.Context, obj *model.ObjectMeta) (*model.
 			continue
 		}
 
-		kr, err := model.GetKubernetesResource(u)
+		kr, err := model.GetKubernetesResource(u, r.categories)
 		if err != nil {
 			graphql.AddError(ctx, errors.Wrap(err, errModelOwner))
 			continue
@@ -97,7 +98,7 @@ func (r *objectMeta) Controller(ctx context.Context, obj *model.ObjectMeta) (mod
 			return nil, nil
 		}
 
-		kr, err := model.GetKubernetesResource(u)
+		kr, err := model.GetKubernetesResource(u, r.categories)
 		if err != nil {
 			graphql.AddError(ctx, errors.Wrap(err, errModelOwner))
 			return nil, nil
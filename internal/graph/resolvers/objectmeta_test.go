@@ -50,7 +50,7 @@ func TestObjectMetaOwners(t *testing.T) {
 	own := unstructured.Unstructured{}
 	own.SetAPIVersion("example.org/v1")
 	own.SetKind("AnOwner")
-	gown, _ := model.GetKubernetesResource(&own)
+	gown, _ := model.GetKubernetesResource(&own, nil)
 
 	type args struct {
 		ctx context.Context
@@ -153,7 +153,7 @@ func TestObjectMetaController(t *testing.T) {
 	ctrl := unstructured.Unstructured{}
 	ctrl.SetAPIVersion("example.org/v1")
 	ctrl.SetKind("TheController")
-	gctrl, _ := model.GetKubernetesResource(&ctrl)
+	gctrl, _ := model.GetKubernetesResource(&ctrl, nil)
 
 	// An owner
 	own := unstructured.Unstructured{}
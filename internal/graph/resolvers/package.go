@@ -0,0 +1,403 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolvers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+
+	"github.com/upbound/xgql/internal/auth"
+	"github.com/upbound/xgql/internal/graph/model"
+	xunstructured "github.com/upbound/xgql/internal/unstructured"
+)
+
+const (
+	errGetRevision       = "cannot get package revision"
+	errGetRevisionObject = "cannot get object installed by package revision"
+	errGetPackage        = "cannot get package"
+
+	errFmtNotRevision = "kind %q is not a provider or configuration revision"
+	errFmtNotPackage  = "kind %q is not a provider, configuration, or function"
+
+	errFmtNotPackageRevision = "kind %q is not a provider, configuration, or function revision"
+)
+
+// Kinds of package manager resource that are not part of the version of
+// Crossplane we import, and thus whose API version we negotiate.
+var (
+	gkFunction                = schema.GroupKind{Group: pkgv1.Group, Kind: xunstructured.KindFunction}
+	gkDeploymentRuntimeConfig = schema.GroupKind{Group: pkgv1.Group, Kind: xunstructured.KindDeploymentRuntimeConfig}
+	gkLock                    = schema.GroupKind{Group: pkgv1.Group, Kind: xunstructured.KindLock}
+)
+
+// servedVersion returns the newest of the supplied API versions of the
+// supplied kind that the API server serves. Crossplane graduates the kinds of
+// its package manager to new API versions at different rates, so which version
+// is served depends on the version of Crossplane. The client's REST mapper
+// discovers the versions the API server serves, and rediscovers them when
+// asked about one it doesn't know. If none of the supplied versions are served
+// we fall back to the API server's preferred version, if any, so that kinds
+// keep working when they graduate to a version we don't know about. The newest
+// supplied version is assumed if the client has no REST mapper.
+func servedVersion(c client.Client, gk schema.GroupKind, versions []string) (schema.GroupVersionKind, error) {
+	rm := c.RESTMapper()
+	if rm == nil {
+		return gk.WithVersion(versions[0]), nil
+	}
+	m, err := rm.RESTMapping(gk, versions...)
+	if kmeta.IsNoMatchError(err) {
+		m, err = rm.RESTMapping(gk)
+	}
+	if err != nil {
+		return schema.GroupVersionKind{}, err
+	}
+	return m.GroupVersionKind, nil
+}
+
+// listServed lists resources of the supplied kind at whichever of the supplied
+// API versions the API server serves. See servedVersion.
+func listServed(ctx context.Context, c client.Client, gk schema.GroupKind, versions []string) (*unstructured.UnstructuredList, error) {
+	gvk, err := servedVersion(c, gk, versions)
+	if err != nil {
+		return nil, err
+	}
+	l := &unstructured.UnstructuredList{}
+	l.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	return l, c.List(ctx, l)
+}
+
+// labelParentPackage is applied by the package manager to every package
+// revision. Its value is the name of the package that owns the revision.
+const labelParentPackage = "pkg.crossplane.io/package"
+
+// revisionsOf selects the revisions of the named package. The package manager
+// labels each revision with its package, so selecting by label lets the API
+// server (or our cache) return only the revisions we're interested in rather
+// than every revision in the cluster. Callers should still check that the
+// package is each revision's controller; labels are not authoritative.
+func revisionsOf(pkg string) client.MatchingLabels {
+	return client.MatchingLabels{labelParentPackage: pkg}
+}
+
+// getOwningRevision returns the revision of the supplied kind that is one of
+// the supplied owners. The package manager makes every revision of a package an
+// owner of the objects it installs, so we prefer the active revision and
+// otherwise return the first that still exists. newRevision must return an
+// empty revision of the supplied kind. Returns nil if no such revision exists.
+func getOwningRevision(ctx context.Context, c client.Client, owners []metav1.OwnerReference, gvk schema.GroupVersionKind, newRevision func() pkgv1.PackageRevision) (pkgv1.PackageRevision, error) {
+	var out pkgv1.PackageRevision
+	for _, ref := range owners {
+		if ref.APIVersion != gvk.GroupVersion().String() || ref.Kind != gvk.Kind {
+			continue
+		}
+
+		pr := newRevision()
+		err := c.Get(ctx, types.NamespacedName{Name: ref.Name}, pr)
+		if kerrors.IsNotFound(err) {
+			// The revision may have been garbage collected since.
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errGetRevision)
+		}
+
+		if pr.GetDesiredState() == pkgv1.PackageRevisionActive {
+			return pr, nil
+		}
+		if out == nil {
+			out = pr
+		}
+	}
+	return out, nil
+}
+
+// newPackage returns a new package of the supplied type. Functions are created
+// with the supplied kind, which should be negotiated using servedVersion.
+func newPackage(t model.PackageType, pkg string, fn schema.GroupVersionKind) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"package": pkg},
+	}}
+	switch t {
+	case model.PackageTypeProvider:
+		u.SetGroupVersionKind(pkgv1.ProviderGroupVersionKind)
+	case model.PackageTypeConfiguration:
+		u.SetGroupVersionKind(pkgv1.ConfigurationGroupVersionKind)
+	case model.PackageTypeFunction:
+		u.SetGroupVersionKind(fn)
+	}
+	return u
+}
+
+// isPackage returns true if the supplied ID identifies a provider,
+// configuration, or function.
+func isPackage(id model.ReferenceID) bool {
+	if !strings.HasPrefix(id.APIVersion, pkgv1.Group+"/") {
+		return false
+	}
+	switch id.Kind {
+	case pkgv1.ProviderKind, pkgv1.ConfigurationKind, xunstructured.KindFunction:
+		return true
+	default:
+		return false
+	}
+}
+
+// isPackageRevision returns true if the supplied ID identifies a provider,
+// configuration, or function revision.
+func isPackageRevision(id model.ReferenceID) bool {
+	if !strings.HasPrefix(id.APIVersion, pkgv1.Group+"/") {
+		return false
+	}
+	switch id.Kind {
+	case pkgv1.ProviderRevisionKind, pkgv1.ConfigurationRevisionKind, xunstructured.KindFunctionRevision:
+		return true
+	default:
+		return false
+	}
+}
+
+// packageName derives a package name from the supplied OCI reference, by
+// omitting its registry, tag, and digest. For example the name of
+// xpkg.upbound.io/crossplane-contrib/provider-aws:v0.1.0 is
+// crossplane-contrib-provider-aws.
+func packageName(pkg string) string {
+	parts := strings.Split(packageSource(pkg), "/")
+	if len(parts) > 1 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		parts = parts[1:]
+	}
+	return strings.ToLower(strings.Join(parts, "-"))
+}
+
+// packageSource returns the supplied OCI reference without its tag or digest.
+// For example the source of xpkg.upbound.io/crossplane-contrib/provider-aws:v0.1.0
+// is xpkg.upbound.io/crossplane-contrib/provider-aws.
+func packageSource(pkg string) string {
+	if i := strings.Index(pkg, "@"); i >= 0 {
+		pkg = pkg[:i]
+	}
+	// A colon after the final slash separates a tag, rather than a port.
+	if i := strings.LastIndex(pkg, ":"); i > strings.LastIndex(pkg, "/") {
+		pkg = pkg[:i]
+	}
+	return pkg
+}
+
+// getRevisionActivationPolicy returns the Crossplane representation of the
+// supplied policy, or an empty string if it is nil.
+func getRevisionActivationPolicy(p *model.RevisionActivationPolicy) string {
+	if p == nil {
+		return ""
+	}
+	switch *p {
+	case model.RevisionActivationPolicyAutomatic:
+		return string(pkgv1.AutomaticActivation)
+	case model.RevisionActivationPolicyManual:
+		return string(pkgv1.ManualActivation)
+	}
+	return ""
+}
+
+// getDesiredState returns the Crossplane representation of the supplied state.
+func getDesiredState(s model.PackageRevisionDesiredState) string {
+	switch s {
+	case model.PackageRevisionDesiredStateActive:
+		return string(pkgv1.PackageRevisionActive)
+	case model.PackageRevisionDesiredStateInactive:
+		return string(pkgv1.PackageRevisionInactive)
+	}
+	return ""
+}
+
+// getPullPolicy returns the Kubernetes representation of the supplied policy,
+// or an empty string if it is nil.
+func getPullPolicy(p *model.PackagePullPolicy) string {
+	if p == nil {
+		return ""
+	}
+	switch *p {
+	case model.PackagePullPolicyAlways:
+		return string(corev1.PullAlways)
+	case model.PackagePullPolicyNever:
+		return string(corev1.PullNever)
+	case model.PackagePullPolicyIfNotPresent:
+		return string(corev1.PullIfNotPresent)
+	}
+	return ""
+}
+
+// getObjectRefs returns the references to the objects installed by the
+// supplied provider or configuration revision.
+func getObjectRefs(ctx context.Context, c client.Client, id model.ReferenceID) ([]xpv1.TypedReference, error) {
+	nn := types.NamespacedName{Name: id.Name}
+	switch id.Kind {
+	case pkgv1.ProviderRevisionKind:
+		pr := &pkgv1.ProviderRevision{}
+		if err := c.Get(ctx, nn, pr); err != nil {
+			return nil, errors.Wrap(err, errGetRevision)
+		}
+		return pr.Status.ObjectRefs, nil
+	case pkgv1.ConfigurationRevisionKind:
+		cr := &pkgv1.ConfigurationRevision{}
+		if err := c.Get(ctx, nn, cr); err != nil {
+			return nil, errors.Wrap(err, errGetRevision)
+		}
+		return cr.Status.ObjectRefs, nil
+	default:
+		return nil, errors.Errorf(errFmtNotRevision, id.Kind)
+	}
+}
+
+// getRevisionObjects returns the supplied objects installed by a package
+// revision. Objects that no longer exist are returned without their content.
+func getRevisionObjects(ctx context.Context, c client.Client, refs []xpv1.TypedReference) ([]model.RevisionObject, error) {
+	out := make([]model.RevisionObject, len(refs))
+	for i, ref := range refs {
+		out[i].Reference = ref
+
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(ref.APIVersion)
+		u.SetKind(ref.Kind)
+		err := c.Get(ctx, types.NamespacedName{Name: ref.Name}, u)
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errGetRevisionObject)
+		}
+		out[i].Object = u
+	}
+	return out, nil
+}
+
+type revisionObjectDiff struct {
+	clients    ClientCache
+	categories model.CategoryIndex
+}
+
+func (r *revisionObjectDiff) Resource(ctx context.Context, obj *model.RevisionObjectDiff) (model.KubernetesResource, error) {
+	// Prefer the API version at which the newer revision installed the object.
+	apiVersion := pointer.StringPtrDerefOr(obj.ToAPIVersion, pointer.StringPtrDerefOr(obj.FromAPIVersion, ""))
+	if apiVersion == "" {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(apiVersion)
+	u.SetKind(obj.Kind)
+	err = c.Get(ctx, types.NamespacedName{Name: obj.Name}, u)
+	if kerrors.IsNotFound(err) {
+		// Objects that were removed may no longer exist.
+		return nil, nil
+	}
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetResource))
+		return nil, nil
+	}
+
+	out, err := model.GetKubernetesResource(u, r.categories)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errModelResource))
+		return nil, nil
+	}
+	return out, nil
+}
+
+// getCommonLabels returns the common labels of the package with the supplied
+// GVK and name. The package API we build against predates the commonLabels
+// field, so we must read it from the unstructured package.
+func getCommonLabels(ctx context.Context, clients ClientCache, gvk schema.GroupVersionKind, name string) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	if err := c.Get(ctx, types.NamespacedName{Name: name}, u); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetPackage))
+		return nil, nil
+	}
+
+	l, _, _ := unstructured.NestedStringMap(u.Object, "spec", "commonLabels")
+	return l, nil
+}
+
+// getPullSecrets returns the supplied package pull secrets, which must exist in
+// the supplied namespace. Only the metadata of each secret is returned.
+func getPullSecrets(ctx context.Context, clients ClientCache, namespace string, names []string) ([]model.PackagePullSecret, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	out := make([]model.PackagePullSecret, len(names))
+	for i, name := range names {
+		out[i] = model.PackagePullSecret{Name: name, Namespace: namespace}
+
+		s := &corev1.Secret{}
+		err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, s)
+		if kerrors.IsNotFound(err) {
+			// A missing pull secret is a likely cause of registry auth
+			// problems, so we report it as a secret without metadata.
+			continue
+		}
+		if err != nil {
+			graphql.AddError(ctx, errors.Wrap(err, errGetSecret))
+			continue
+		}
+		out[i].Metadata = model.GetObjectMeta(s)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,255 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolvers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/upbound/xgql/internal/auth"
+	"github.com/upbound/xgql/internal/clients"
+	"github.com/upbound/xgql/internal/graph/generated"
+	"github.com/upbound/xgql/internal/graph/model"
+)
+
+var _ generated.RevisionObjectDiffResolver = &revisionObjectDiff{}
+
+func TestRevisionObjectDiffResource(t *testing.T) {
+	errBoom := errors.New("boom")
+	errNotFound := kerrors.NewNotFound(schema.GroupResource{}, "removed")
+
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("apiextensions.k8s.io/v1")
+	u.SetKind("CustomResourceDefinition")
+	u.SetName("cool")
+	gkr, _ := model.GetKubernetesResource(u, nil)
+
+	type args struct {
+		ctx context.Context
+		obj *model.RevisionObjectDiff
+	}
+	type want struct {
+		kr   model.KubernetesResource
+		err  error
+		errs gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"GetResourceError": {
+			reason: "If we can't get the object we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.RevisionObjectDiff{
+					Kind:         "CustomResourceDefinition",
+					Name:         "cool",
+					ToAPIVersion: pointer.StringPtr("apiextensions.k8s.io/v1"),
+				},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetResource).Error()),
+				},
+			},
+		},
+		"NotFound": {
+			reason: "If the object no longer exists we should return nil without error.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(errNotFound),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.RevisionObjectDiff{
+					Kind:           "CustomResourceDefinition",
+					Name:           "removed",
+					FromAPIVersion: pointer.StringPtr("apiextensions.k8s.io/v1"),
+				},
+			},
+		},
+		"Success": {
+			reason: "If we can get the object we should model and return it.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						*obj.(*unstructured.Unstructured) = *u
+						return nil
+					}),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.RevisionObjectDiff{
+					Kind:         "CustomResourceDefinition",
+					Name:         "cool",
+					ToAPIVersion: pointer.StringPtr("apiextensions.k8s.io/v1"),
+				},
+			},
+			want: want{
+				kr: gkr,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &revisionObjectDiff{clients: tc.clients}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := r.Resource(tc.args.ctx, tc.args.obj)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Resource(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Resource(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.kr, got, cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\nr.Resource(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPackageName(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		pkg    string
+		want   string
+	}{
+		"RegistryAndTag": {
+			reason: "The registry and tag should be omitted.",
+			pkg:    "xpkg.upbound.io/crossplane-contrib/provider-aws:v0.1.0",
+			want:   "crossplane-contrib-provider-aws",
+		},
+		"RegistryWithPort": {
+			reason: "A registry with a port should be omitted.",
+			pkg:    "registry.example.org:5000/example/provider-example:v1",
+			want:   "example-provider-example",
+		},
+		"Digest": {
+			reason: "A digest should be omitted.",
+			pkg:    "crossplane/provider-gcp@sha256:ed7a",
+			want:   "crossplane-provider-gcp",
+		},
+		"Uppercase": {
+			reason: "The name should be lowercase.",
+			pkg:    "Example/Provider-Example",
+			want:   "example-provider-example",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := packageName(tc.pkg)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\npackageName(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestServedVersion(t *testing.T) {
+	gk := schema.GroupKind{Group: "pkg.crossplane.io", Kind: "Lock"}
+
+	// mapper returns a REST mapper that serves locks at the supplied
+	// versions, in order of preference.
+	mapper := func(versions ...string) kmeta.RESTMapper {
+		gvs := make([]schema.GroupVersion, len(versions))
+		for i, v := range versions {
+			gvs[i] = schema.GroupVersion{Group: gk.Group, Version: v}
+		}
+		rm := kmeta.NewDefaultRESTMapper(gvs)
+		for _, gv := range gvs {
+			rm.Add(gv.WithKind(gk.Kind), kmeta.RESTScopeRoot)
+		}
+		return rm
+	}
+
+	type want struct {
+		gvk schema.GroupVersionKind
+		err bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      client.Client
+		want   want
+	}{
+		"NoRESTMapper": {
+			reason: "The newest supplied version should be assumed if the client has no REST mapper.",
+			c:      &test.MockClient{},
+			want:   want{gvk: gk.WithVersion("v1beta1")},
+		},
+		"NewestServed": {
+			reason: "The newest supplied version should be returned if it is served.",
+			c:      &mapperClient{MockClient: &test.MockClient{}, mapper: mapper("v1alpha1", "v1beta1")},
+			want:   want{gvk: gk.WithVersion("v1beta1")},
+		},
+		"OldestServed": {
+			reason: "An older supplied version should be returned if the newer versions aren't served.",
+			c:      &mapperClient{MockClient: &test.MockClient{}, mapper: mapper("v1alpha1")},
+			want:   want{gvk: gk.WithVersion("v1alpha1")},
+		},
+		"UnknownServed": {
+			reason: "The preferred version should be returned if none of the supplied versions are served.",
+			c:      &mapperClient{MockClient: &test.MockClient{}, mapper: mapper("v1")},
+			want:   want{gvk: gk.WithVersion("v1")},
+		},
+		"NoneServed": {
+			reason: "An error should be returned if the kind isn't served at all.",
+			c:      &mapperClient{MockClient: &test.MockClient{}, mapper: mapper()},
+			want:   want{err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := servedVersion(tc.c, gk, []string{"v1beta1", "v1alpha1"})
+			if (err != nil) != tc.want.err {
+				t.Errorf("\n%s\nservedVersion(...): want error %t, got %v\n", tc.reason, tc.want.err, err)
+			}
+			if diff := cmp.Diff(tc.want.gvk, got); diff != "" {
+				t.Errorf("\n%s\nservedVersion(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
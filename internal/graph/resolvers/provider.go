@@ -21,7 +21,7 @@ import (
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	kextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -41,17 +41,12 @@ type provider struct {
 	clients ClientCache
 }
 
-func (r *provider) Events(ctx context.Context, obj *model.Provider) (*model.EventConnection, error) {
+func (r *provider) Events(ctx context.Context, obj *model.Provider, limit *int) (*model.EventConnection, error) {
 	e := &events{clients: r.clients}
-	return e.Resolve(ctx, &corev1.ObjectReference{
-		APIVersion: obj.APIVersion,
-		Kind:       obj.Kind,
-		Name:       obj.Metadata.Name,
-		UID:        types.UID(obj.Metadata.UID),
-	})
+	return e.Resolve(ctx, involved(obj.ID, obj.Metadata), limit)
 }
 
-func (r *provider) Revisions(ctx context.Context, obj *model.Provider) (*model.ProviderRevisionConnection, error) {
+func (r *provider) Revisions(ctx context.Context, obj *model.Provider, limit *int) (*model.ProviderRevisionConnection, error) {
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
@@ -63,7 +58,7 @@ func (r *provider) Revisions(ctx context.Context, obj *model.Provider) (*model.P
 	}
 
 	in := &pkgv1.ProviderRevisionList{}
-	if err := c.List(ctx, in); err != nil {
+	if err := c.List(ctx, in, revisionsOf(obj.Metadata.Name)); err != nil {
 		graphql.AddError(ctx, errors.Wrap(err, errListProviderRevs))
 		return nil, nil
 	}
@@ -87,6 +82,7 @@ func (r *provider) Revisions(ctx context.Context, obj *model.Provider) (*model.P
 	}
 
 	sort.Stable(out)
+	out.Nodes = truncate(ctx, limit, out.Nodes)
 	return out, nil
 }
 
@@ -102,7 +98,7 @@ func (r *provider) ActiveRevision(ctx context.Context, obj *model.Provider) (*mo
 	}
 
 	in := &pkgv1.ProviderRevisionList{}
-	if err := c.List(ctx, in); err != nil {
+	if err := c.List(ctx, in, revisionsOf(obj.Metadata.Name)); err != nil {
 		graphql.AddError(ctx, errors.Wrap(err, errListProviderRevs))
 		return nil, nil
 	}
@@ -129,18 +125,139 @@ func (r *provider) ActiveRevision(ctx context.Context, obj *model.Provider) (*mo
 	return nil, nil
 }
 
+func (r *provider) Rbac(ctx context.Context, obj *model.Provider) (*model.ProviderRbac, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	prl := &pkgv1.ProviderRevisionList{}
+	if err := c.List(ctx, prl, revisionsOf(obj.Metadata.Name)); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errListProviderRevs))
+		return nil, nil
+	}
+
+	// The RBAC manager creates cluster roles and bindings for each of our
+	// revisions, and makes the revision their controller reference.
+	revs := map[types.UID]bool{}
+	for i := range prl.Items {
+		pr := &prl.Items[i]
+		if c := metav1.GetControllerOf(pr); c == nil || c.UID != types.UID(obj.Metadata.UID) {
+			continue
+		}
+		revs[pr.GetUID()] = true
+	}
+
+	crl := &rbacv1.ClusterRoleList{}
+	if err := c.List(ctx, crl); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errListClusterRoles))
+		return nil, nil
+	}
+
+	crbl := &rbacv1.ClusterRoleBindingList{}
+	if err := c.List(ctx, crbl); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errListClusterRoleBindings))
+		return nil, nil
+	}
+
+	out := &model.ProviderRbac{
+		ClusterRoles:        &model.ClusterRoleConnection{Nodes: make([]model.ClusterRole, 0)},
+		ClusterRoleBindings: &model.ClusterRoleBindingConnection{Nodes: make([]model.ClusterRoleBinding, 0)},
+	}
+
+	for i := range crl.Items {
+		cr := &crl.Items[i]
+		if c := metav1.GetControllerOf(cr); c == nil || !revs[c.UID] {
+			continue
+		}
+		out.ClusterRoles.Nodes = append(out.ClusterRoles.Nodes, model.GetClusterRole(cr))
+		out.ClusterRoles.TotalCount++
+	}
+
+	for i := range crbl.Items {
+		crb := &crbl.Items[i]
+		if c := metav1.GetControllerOf(crb); c == nil || !revs[c.UID] {
+			continue
+		}
+		out.ClusterRoleBindings.Nodes = append(out.ClusterRoleBindings.Nodes, model.GetClusterRoleBinding(crb))
+		out.ClusterRoleBindings.TotalCount++
+	}
+
+	sort.Stable(out.ClusterRoles)
+	sort.Stable(out.ClusterRoleBindings)
+	return out, nil
+}
+
+func (r *provider) ManagedResources(ctx context.Context, obj *model.Provider, limit, offset *int) (*model.ManagedResourceConnection, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	in := &pkgv1.ProviderRevisionList{}
+	if err := c.List(ctx, in, revisionsOf(obj.Metadata.Name)); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errListProviderRevs))
+		return nil, nil
+	}
+
+	// The package manager makes every revision of a provider an owner of the
+	// CRDs it installs, so we consider CRDs owned by any of our revisions.
+	owners := map[types.UID]bool{}
+	for i := range in.Items {
+		pr := &in.Items[i]
+		if c := metav1.GetControllerOf(pr); c == nil || c.UID != types.UID(obj.Metadata.UID) {
+			continue
+		}
+		owners[pr.GetUID()] = true
+	}
+
+	mr := &managedResources{clients: r.clients}
+	return mr.Resolve(ctx, managedResourceSelector{owners: owners}, limit, offset)
+}
+
+type providerSpec struct {
+	clients   ClientCache
+	namespace string
+}
+
+func (r *providerSpec) CommonLabels(ctx context.Context, obj *model.ProviderSpec) (map[string]string, error) {
+	return getCommonLabels(ctx, r.clients, pkgv1.ProviderGroupVersionKind, obj.ProviderName)
+}
+
+func (r *providerSpec) PullSecrets(ctx context.Context, obj *model.ProviderSpec) ([]model.PackagePullSecret, error) {
+	return getPullSecrets(ctx, r.clients, r.namespace, obj.PackagePullSecrets)
+}
+
 type providerRevision struct {
 	clients ClientCache
 }
 
-func (r *providerRevision) Events(ctx context.Context, obj *model.ProviderRevision) (*model.EventConnection, error) {
+func (r *providerRevision) ManagedResources(ctx context.Context, obj *model.ProviderRevision, limit, offset *int) (*model.ManagedResourceConnection, error) {
+	mr := &managedResources{clients: r.clients}
+	return mr.Resolve(ctx, managedResourceSelector{owners: map[types.UID]bool{types.UID(obj.Metadata.UID): true}}, limit, offset)
+}
+
+func (r *providerRevision) Deployment(ctx context.Context, obj *model.ProviderRevision) (*model.Deployment, error) {
+	return revisionDeployment(ctx, r.clients, obj.Metadata)
+}
+
+func (r *providerRevision) Pods(ctx context.Context, obj *model.ProviderRevision) (*model.PodConnection, error) {
+	return revisionPods(ctx, r.clients, obj.Metadata)
+}
+
+func (r *providerRevision) Events(ctx context.Context, obj *model.ProviderRevision, limit *int) (*model.EventConnection, error) {
 	e := &events{clients: r.clients}
-	return e.Resolve(ctx, &corev1.ObjectReference{
-		APIVersion: obj.APIVersion,
-		Kind:       obj.Kind,
-		Name:       obj.Metadata.Name,
-		UID:        types.UID(obj.Metadata.UID),
-	})
+	return e.Resolve(ctx, involved(obj.ID, obj.Metadata), limit)
 }
 
 type providerRevisionStatus struct {
@@ -158,28 +275,37 @@ func (r *providerRevisionStatus) Objects(ctx context.Context, obj *model.Provide
 		return nil, nil
 	}
 
-	out := &model.KubernetesResourceConnection{
-		Nodes: make([]model.KubernetesResource, 0, len(obj.ObjectRefs)),
-	}
+	nodes := make([]model.KubernetesResource, len(obj.ObjectRefs))
+	forEach(ctx, len(obj.ObjectRefs), func(i int) {
+		ref := obj.ObjectRefs[i]
 
-	for _, ref := range obj.ObjectRefs {
 		// Crossplane lints provider packages to ensure they only contain CRDs,
 		// but this isn't enforced at the API level. We filter out anything that
 		// isn't a CRD, just in case.
 		if ref.Kind != "CustomResourceDefinition" {
-			continue
+			return
 		}
 		if strings.Split(ref.APIVersion, "/")[0] != kextv1.GroupName {
-			continue
+			return
 		}
 
 		crd := &kextv1.CustomResourceDefinition{}
 		if err := c.Get(ctx, types.NamespacedName{Name: ref.Name}, crd); err != nil {
 			graphql.AddError(ctx, errors.Wrap(err, errGetCRD))
-			continue
+			return
 		}
 
-		out.Nodes = append(out.Nodes, model.GetCustomResourceDefinition(crd))
+		nodes[i] = model.GetCustomResourceDefinition(crd)
+	})
+
+	out := &model.KubernetesResourceConnection{
+		Nodes: make([]model.KubernetesResource, 0, len(nodes)),
+	}
+	for _, kr := range nodes {
+		if kr == nil {
+			continue
+		}
+		out.Nodes = append(out.Nodes, kr)
 		out.TotalCount++
 	}
 
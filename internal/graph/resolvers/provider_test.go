@@ -23,10 +23,15 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 	"github.com/vektah/gqlparser/v2/gqlerror"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	kextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -42,6 +47,7 @@ import (
 
 var (
 	_ generated.ProviderResolver               = &provider{}
+	_ generated.ProviderSpecResolver           = &providerSpec{}
 	_ generated.ProviderRevisionResolver       = &providerRevision{}
 	_ generated.ProviderRevisionStatusResolver = &providerRevisionStatus{}
 )
@@ -74,9 +80,12 @@ func TestProviderRevisions(t *testing.T) {
 	// A ProviderRevision which we do not control.
 	other := pkgv1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Name: "not-ours"}}
 
+	one := 1
+
 	type args struct {
-		ctx context.Context
-		obj *model.Provider
+		ctx   context.Context
+		obj   *model.Provider
+		limit *int
 	}
 	type want struct {
 		pc   *model.ProviderRevisionConnection
@@ -113,6 +122,9 @@ func TestProviderRevisions(t *testing.T) {
 			}),
 			args: args{
 				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.Provider{
+					Metadata: &model.ObjectMeta{UID: uid},
+				},
 			},
 			want: want{
 				errs: gqlerror.List{
@@ -145,6 +157,32 @@ func TestProviderRevisions(t *testing.T) {
 				},
 			},
 		},
+		"LimitedRevisions": {
+			reason: "We should return no more than the supplied limit of revisions, but count all revisions.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						*obj.(*pkgv1.ProviderRevisionList) = pkgv1.ProviderRevisionList{
+							Items: []pkgv1.ProviderRevision{other, active, inactive},
+						}
+						return nil
+					}),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.Provider{
+					Metadata: &model.ObjectMeta{UID: uid},
+				},
+				limit: &one,
+			},
+			want: want{
+				pc: &model.ProviderRevisionConnection{
+					Nodes:      []model.ProviderRevision{gactive},
+					TotalCount: 2,
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -153,7 +191,7 @@ func TestProviderRevisions(t *testing.T) {
 
 			// Our GraphQL resolvers never return errors. We instead add an
 			// error to the GraphQL context and return early.
-			got, err := c.Revisions(tc.args.ctx, tc.args.obj)
+			got, err := c.Revisions(tc.args.ctx, tc.args.obj, tc.args.limit)
 			errs := graphql.GetErrors(tc.args.ctx)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
@@ -238,6 +276,9 @@ func TestProviderActiveRevision(t *testing.T) {
 			}),
 			args: args{
 				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.Provider{
+					Metadata: &model.ObjectMeta{UID: uid},
+				},
 			},
 			want: want{
 				errs: gqlerror.List{
@@ -313,6 +354,625 @@ func TestProviderActiveRevision(t *testing.T) {
 	}
 }
 
+func TestProviderRbac(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	uid := "no-you-id"
+	revUID := "rev-you-id"
+
+	// A ProviderRevision that we control.
+	rev := pkgv1.ProviderRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "coolprovider-1234",
+			UID:             types.UID(revUID),
+			OwnerReferences: []metav1.OwnerReference{meta.AsController(&xpv1.TypedReference{UID: types.UID(uid)})},
+		},
+	}
+
+	// A ProviderRevision which we do not control.
+	otherRev := pkgv1.ProviderRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "not-ours", UID: types.UID("other-you-id")},
+	}
+
+	controlled := metav1.ObjectMeta{
+		Name:            "crossplane:provider:coolprovider-1234:system",
+		OwnerReferences: []metav1.OwnerReference{meta.AsController(&xpv1.TypedReference{UID: types.UID(revUID)})},
+	}
+
+	cr := rbacv1.ClusterRole{ObjectMeta: controlled}
+	gcr := model.GetClusterRole(&cr)
+	otherCR := rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "not-ours"}}
+
+	crb := rbacv1.ClusterRoleBinding{ObjectMeta: controlled}
+	gcrb := model.GetClusterRoleBinding(&crb)
+	otherCRB := rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "not-ours"}}
+
+	type args struct {
+		ctx context.Context
+		obj *model.Provider
+	}
+	type want struct {
+		rbac *model.ProviderRbac
+		err  error
+		errs gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"GetClientError": {
+			reason: "If we can't get a client we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{}, errBoom
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetClient).Error()),
+				},
+			},
+		},
+		"ListRevisionsError": {
+			reason: "If we can't list revisions we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.Provider{
+					Metadata: &model.ObjectMeta{UID: uid},
+				},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errListProviderRevs).Error()),
+				},
+			},
+		},
+		"ListClusterRolesError": {
+			reason: "If we can't list cluster roles we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						if _, ok := obj.(*rbacv1.ClusterRoleList); ok {
+							return errBoom
+						}
+						return nil
+					}),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.Provider{
+					Metadata: &model.ObjectMeta{UID: uid},
+				},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errListClusterRoles).Error()),
+				},
+			},
+		},
+		"ListClusterRoleBindingsError": {
+			reason: "If we can't list cluster role bindings we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						if _, ok := obj.(*rbacv1.ClusterRoleBindingList); ok {
+							return errBoom
+						}
+						return nil
+					}),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.Provider{
+					Metadata: &model.ObjectMeta{UID: uid},
+				},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errListClusterRoleBindings).Error()),
+				},
+			},
+		},
+		"Success": {
+			reason: "We should return the cluster roles and bindings controlled by our revisions.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						switch l := obj.(type) {
+						case *pkgv1.ProviderRevisionList:
+							*l = pkgv1.ProviderRevisionList{Items: []pkgv1.ProviderRevision{otherRev, rev}}
+						case *rbacv1.ClusterRoleList:
+							*l = rbacv1.ClusterRoleList{Items: []rbacv1.ClusterRole{otherCR, cr}}
+						case *rbacv1.ClusterRoleBindingList:
+							*l = rbacv1.ClusterRoleBindingList{Items: []rbacv1.ClusterRoleBinding{otherCRB, crb}}
+						}
+						return nil
+					}),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.Provider{
+					Metadata: &model.ObjectMeta{UID: uid},
+				},
+			},
+			want: want{
+				rbac: &model.ProviderRbac{
+					ClusterRoles: &model.ClusterRoleConnection{
+						Nodes:      []model.ClusterRole{gcr},
+						TotalCount: 1,
+					},
+					ClusterRoleBindings: &model.ClusterRoleBindingConnection{
+						Nodes:      []model.ClusterRoleBinding{gcrb},
+						TotalCount: 1,
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := &provider{clients: tc.clients}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := p.Rbac(tc.args.ctx, tc.args.obj)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nq.Rbac(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nq.Rbac(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.rbac, got, cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\nq.Rbac(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestProviderManagedResources(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	uid := "no-you-id"
+
+	// A ProviderRevision that we control.
+	rev := pkgv1.ProviderRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "coolrev",
+			UID:             types.UID("coolrev"),
+			OwnerReferences: []metav1.OwnerReference{meta.AsController(&xpv1.TypedReference{UID: types.UID(uid)})},
+		},
+	}
+
+	// A ProviderRevision which we do not control.
+	other := pkgv1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Name: "not-ours", UID: types.UID("not-ours")}}
+
+	crd := func(kind string, owner types.UID, categories ...string) kextv1.CustomResourceDefinition {
+		return kextv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				OwnerReferences: []metav1.OwnerReference{{UID: owner}},
+			},
+			Spec: kextv1.CustomResourceDefinitionSpec{
+				Group: "example.org",
+				Names: kextv1.CustomResourceDefinitionNames{
+					Kind:       kind,
+					ListKind:   kind + "List",
+					Categories: categories,
+				},
+				Versions: []kextv1.CustomResourceDefinitionVersion{{Name: "v1", Served: true}},
+			},
+		}
+	}
+
+	mr := func(kind, name string) kunstructured.Unstructured {
+		u := kunstructured.Unstructured{}
+		u.SetAPIVersion("example.org/v1")
+		u.SetKind(kind)
+		u.SetName(name)
+		return u
+	}
+
+	a := mr("Cool", "a")
+	b := mr("Cool", "b")
+	c := mr("Cooler", "c")
+	ga := model.GetManagedResource(&a)
+	gb := model.GetManagedResource(&b)
+	gc := model.GetManagedResource(&c)
+
+	list := func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+		switch l := obj.(type) {
+		case *pkgv1.ProviderRevisionList:
+			l.Items = []pkgv1.ProviderRevision{rev, other}
+		case *kextv1.CustomResourceDefinitionList:
+			l.Items = []kextv1.CustomResourceDefinition{
+				crd("Cool", rev.GetUID(), "crossplane", "managed"),
+				crd("Cooler", rev.GetUID(), "crossplane", "managed"),
+				crd("ProviderConfig", rev.GetUID(), "crossplane", "provider"),
+				crd("NotOurs", other.GetUID(), "crossplane", "managed"),
+			}
+		case *kunstructured.UnstructuredList:
+			switch l.GetKind() {
+			case "CoolList":
+				l.Items = []kunstructured.Unstructured{b, a}
+			case "CoolerList":
+				l.Items = []kunstructured.Unstructured{c}
+			default:
+				return errors.Errorf("unexpected list kind %q", l.GetKind())
+			}
+		}
+		return nil
+	}
+
+	one := 1
+
+	type args struct {
+		ctx    context.Context
+		obj    *model.Provider
+		limit  *int
+		offset *int
+	}
+	type want struct {
+		mrc  *model.ManagedResourceConnection
+		err  error
+		errs gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"GetClientError": {
+			reason: "If we can't get a client we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{}, errBoom
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetClient).Error()),
+				},
+			},
+		},
+		"ListRevisionsError": {
+			reason: "If we can't list revisions we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.Provider{
+					Metadata: &model.ObjectMeta{UID: uid},
+				},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errListProviderRevs).Error()),
+				},
+			},
+		},
+		"ListCRDsError": {
+			reason: "If we can't list CRDs we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+						if _, ok := obj.(*kextv1.CustomResourceDefinitionList); ok {
+							return errBoom
+						}
+						return nil
+					},
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.Provider{
+					Metadata: &model.ObjectMeta{UID: uid},
+				},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errListCRDs).Error()),
+				},
+			},
+		},
+		"AllManagedResources": {
+			reason: "We should return all managed resources defined by CRDs owned by our revisions, ordered by ID.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{MockList: list}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.Provider{
+					Metadata: &model.ObjectMeta{UID: uid},
+				},
+			},
+			want: want{
+				mrc: &model.ManagedResourceConnection{
+					Nodes:      []model.ManagedResource{ga, gb, gc},
+					TotalCount: 3,
+				},
+			},
+		},
+		"PageOfManagedResources": {
+			reason: "We should return only the requested page of managed resources, but count them all.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{MockList: list}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.Provider{
+					Metadata: &model.ObjectMeta{UID: uid},
+				},
+				limit:  &one,
+				offset: &one,
+			},
+			want: want{
+				mrc: &model.ManagedResourceConnection{
+					Nodes:      []model.ManagedResource{gb},
+					TotalCount: 3,
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := &provider{clients: tc.clients}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := p.ManagedResources(tc.args.ctx, tc.args.obj, tc.args.limit, tc.args.offset)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\np.ManagedResources(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\np.ManagedResources(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.mrc, got, cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\np.ManagedResources(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestProviderSpecCommonLabels(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		ctx context.Context
+		obj *model.ProviderSpec
+	}
+	type want struct {
+		labels map[string]string
+		err    error
+		errs   gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"GetClientError": {
+			reason: "If we can't get a client we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return nil, errBoom
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.ProviderSpec{ProviderName: "cool"},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetClient).Error()),
+				},
+			},
+		},
+		"GetProviderError": {
+			reason: "If we can't get the provider we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.ProviderSpec{ProviderName: "cool"},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetPackage).Error()),
+				},
+			},
+		},
+		"Success": {
+			reason: "If we can get the provider we should return its common labels.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+						u := obj.(*kunstructured.Unstructured)
+						if diff := cmp.Diff(pkgv1.ProviderGroupVersionKind, u.GroupVersionKind()); diff != "" {
+							t.Errorf("-want GVK, +got GVK:\n%s", diff)
+						}
+						if diff := cmp.Diff("cool", key.Name); diff != "" {
+							t.Errorf("-want name, +got name:\n%s", diff)
+						}
+						return kunstructured.SetNestedStringMap(u.Object, map[string]string{"team": "platform"}, "spec", "commonLabels")
+					},
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.ProviderSpec{ProviderName: "cool"},
+			},
+			want: want{
+				labels: map[string]string{"team": "platform"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := &providerSpec{clients: tc.clients}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := s.CommonLabels(tc.args.ctx, tc.args.obj)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.CommonLabels(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.CommonLabels(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.labels, got); diff != "" {
+				t.Errorf("\n%s\ns.CommonLabels(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestProviderSpecPullSecrets(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		ctx context.Context
+		obj *model.ProviderSpec
+	}
+	type want struct {
+		secrets []model.PackagePullSecret
+		err     error
+		errs    gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"NoPullSecrets": {
+			reason: "If the provider has no pull secrets we should return early.",
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.ProviderSpec{},
+			},
+		},
+		"GetClientError": {
+			reason: "If we can't get a client we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return nil, errBoom
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.ProviderSpec{PackagePullSecrets: []string{"cool"}},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetClient).Error()),
+				},
+			},
+		},
+		"GetSecretError": {
+			reason: "If we can't get a secret we should add the error to the GraphQL context and return it without metadata.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.ProviderSpec{PackagePullSecrets: []string{"cool"}},
+			},
+			want: want{
+				secrets: []model.PackagePullSecret{{Name: "cool", Namespace: DefaultCrossplaneNamespace}},
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetSecret).Error()),
+				},
+			},
+		},
+		"Success": {
+			reason: "We should return the metadata of secrets that exist, and no metadata for those that don't.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+						if diff := cmp.Diff(DefaultCrossplaneNamespace, key.Namespace); diff != "" {
+							t.Errorf("-want namespace, +got namespace:\n%s", diff)
+						}
+						if key.Name == "missing" {
+							return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+						}
+						obj.(*corev1.Secret).SetName(key.Name)
+						obj.(*corev1.Secret).SetNamespace(key.Namespace)
+						return nil
+					},
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.ProviderSpec{PackagePullSecrets: []string{"cool", "missing"}},
+			},
+			want: want{
+				secrets: []model.PackagePullSecret{
+					{
+						Name:      "cool",
+						Namespace: DefaultCrossplaneNamespace,
+						Metadata:  &model.ObjectMeta{Name: "cool", Namespace: pointer.StringPtr(DefaultCrossplaneNamespace)},
+					},
+					{
+						Name:      "missing",
+						Namespace: DefaultCrossplaneNamespace,
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := &providerSpec{clients: tc.clients, namespace: DefaultCrossplaneNamespace}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := s.PullSecrets(tc.args.ctx, tc.args.obj)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.PullSecrets(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.PullSecrets(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.secrets, got, cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\ns.PullSecrets(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestProviderRevisionStatusObjects(t *testing.T) {
 	errBoom := errors.New("boom")
 
@@ -17,10 +17,8 @@ package resolvers
 import (
 	"context"
 
-	corev1 "k8s.io/api/core/v1"
 	kextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/pkg/errors"
@@ -33,14 +31,9 @@ type providerConfig struct {
 	clients ClientCache
 }
 
-func (r *providerConfig) Events(ctx context.Context, obj *model.ProviderConfig) (*model.EventConnection, error) {
+func (r *providerConfig) Events(ctx context.Context, obj *model.ProviderConfig, limit *int) (*model.EventConnection, error) {
 	e := &events{clients: r.clients}
-	return e.Resolve(ctx, &corev1.ObjectReference{
-		APIVersion: obj.APIVersion,
-		Kind:       obj.Kind,
-		Name:       obj.Metadata.Name,
-		UID:        types.UID(obj.Metadata.UID),
-	})
+	return e.Resolve(ctx, involved(obj.ID, obj.Metadata), limit)
 }
 
 func (r *providerConfig) Definition(ctx context.Context, obj *model.ProviderConfig) (model.ProviderConfigDefinition, error) {
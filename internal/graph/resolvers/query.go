@@ -15,40 +15,78 @@
 package resolvers
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"sort"
+	"sync"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	kextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	extv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
 
 	"github.com/upbound/xgql/internal/auth"
 	"github.com/upbound/xgql/internal/clients"
 	"github.com/upbound/xgql/internal/graph/model"
+	xunstructured "github.com/upbound/xgql/internal/unstructured"
+	"github.com/upbound/xgql/internal/version"
+	xschema "github.com/upbound/xgql/schema"
 )
 
 const (
-	errGetResource   = "cannot get Kubernetes resource"
-	errModelResource = "cannot model Kubernetes resource"
-	errGetClient     = "cannot get client"
-	errGetSecret     = "cannot get secret"
-	errGetConfigMap  = "cannot get config map"
-	errListProviders = "cannot list providers"
-	errListConfigs   = "cannot list configurations"
+	errGetResource    = "cannot get Kubernetes resource"
+	errModelResource  = "cannot model Kubernetes resource"
+	errGetClient      = "cannot get client"
+	errGetSecret      = "cannot get secret"
+	errGetConfigMap   = "cannot get config map"
+	errListProviders  = "cannot list providers"
+	errListConfigs    = "cannot list configurations"
+	errListFunctions  = "cannot list functions"
+	errListRCs        = "cannot list deployment runtime configs"
+	errLogsDisabled   = "pod logs are not enabled"
+	errGetLogs        = "cannot get pod logs"
+	errReadLogs       = "cannot read pod logs"
+	errFmtNotPod      = "kind %q is not a pod"
+	errFmtNotNode     = "kind %q is not a node"
+	errListCRDs       = "cannot list custom resource definitions"
+	errFmtListKind    = "cannot list %s"
+	errViewerDisabled = "viewer is not enabled"
+	errNoBearerToken  = "caller did not supply a bearer token"
+	errReviewToken    = "cannot review bearer token"
+	errFmtNotAuthd    = "bearer token is not authenticated: %s"
+	errDiscDisabled   = "API resource discovery is not enabled"
+	errDiscover       = "cannot discover API resources"
+	errFmtNotProvider = "kind %q is not a provider"
+)
+
+// Pod logs can be huge. Unless the caller asks for a specific number of lines
+// we only return the most recent lines, and never more than 1MiB.
+const (
+	defaultTailLines = 1000
+	maxLogBytes      = 1 << 20
 )
 
 type query struct {
-	clients ClientCache
+	clients    ClientCache
+	logs       PodLogStreamer
+	tokens     TokenReviewer
+	discovery  Discoverer
+	categories model.CategoryIndex
 }
 
 func (r *query) KubernetesResource(ctx context.Context, id model.ReferenceID) (model.KubernetesResource, error) {
@@ -63,15 +101,12 @@ func (r *query) KubernetesResource(ctx context.Context, id model.ReferenceID) (m
 	}
 
 	u := &unstructured.Unstructured{}
-	u.SetAPIVersion(id.APIVersion)
-	u.SetKind(id.Kind)
-	nn := types.NamespacedName{Namespace: id.Namespace, Name: id.Name}
-	if err := c.Get(ctx, nn, u); err != nil {
+	if err := getByID(ctx, c, id, u); err != nil {
 		graphql.AddError(ctx, errors.Wrap(err, errGetResource))
 		return nil, nil
 	}
 
-	out, err := model.GetKubernetesResource(u)
+	out, err := model.GetKubernetesResource(u, r.categories)
 	if err != nil {
 		graphql.AddError(ctx, errors.Wrap(err, errModelResource))
 		return nil, nil
@@ -79,6 +114,159 @@ func (r *query) KubernetesResource(ctx context.Context, id model.ReferenceID) (m
 	return out, nil
 }
 
+func (r *query) Node(ctx context.Context, id model.ReferenceID) (model.Node, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	n, err := getNode(ctx, c, r.categories, id)
+	if err != nil {
+		graphql.AddError(ctx, err)
+		return nil, nil
+	}
+	return n, nil
+}
+
+func (r *query) Nodes(ctx context.Context, ids []model.ReferenceID) ([]model.Node, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	// Per the Relay spec the returned list must be the same length as the
+	// supplied IDs, with nodes that can't be fetched returned as null.
+	out := make([]model.Node, len(ids))
+	forEach(ctx, len(ids), func(i int) {
+		n, err := getNode(ctx, c, r.categories, ids[i])
+		if err != nil {
+			graphql.AddError(ctx, err)
+			return
+		}
+		out[i] = n
+	})
+	return out, nil
+}
+
+// getNode gets and models the node with the supplied ID. Every type that
+// implements Node is a KubernetesResource except for Event.
+func getNode(ctx context.Context, c client.Client, ci model.CategoryIndex, id model.ReferenceID) (model.Node, error) {
+	nn := types.NamespacedName{Namespace: id.Namespace, Name: id.Name}
+
+	if id.APIVersion == corev1.SchemeGroupVersion.String() && id.Kind == "Event" {
+		e := &corev1.Event{}
+		if err := c.Get(ctx, nn, e); err != nil {
+			return nil, errors.Wrap(err, errGetResource)
+		}
+		// Typed objects read from the cache don't have their type metadata
+		// set, but we need it to derive the event's ID.
+		e.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Event"))
+		return model.GetEvent(e), nil
+	}
+
+	u := &unstructured.Unstructured{}
+	if err := getByID(ctx, c, id, u); err != nil {
+		return nil, errors.Wrap(err, errGetResource)
+	}
+
+	kr, err := model.GetKubernetesResource(u, ci)
+	if err != nil {
+		return nil, errors.Wrap(err, errModelResource)
+	}
+	n, ok := kr.(model.Node)
+	if !ok {
+		// This should be impossible; all KubernetesResources are Nodes.
+		return nil, errors.Errorf(errFmtNotNode, id.Kind)
+	}
+	return n, nil
+}
+
+// The response extension in which upgraded IDs are recorded.
+const extUpgradedIDs = "upgradedIDs"
+
+// getByID gets the object with the supplied ID. IDs outlive the API versions
+// they were created for, so if the ID's API version is no longer served (e.g.
+// after a CRD version bump) we get the object at its API group's preferred
+// version instead, and record that the ID was upgraded in the response.
+func getByID(ctx context.Context, c client.Client, id model.ReferenceID, u *unstructured.Unstructured) error {
+	nn := types.NamespacedName{Namespace: id.Namespace, Name: id.Name}
+	u.SetAPIVersion(id.APIVersion)
+	u.SetKind(id.Kind)
+	err := c.Get(ctx, nn, u)
+	if !kmeta.IsNoMatchError(err) && !kerrors.IsNotFound(err) {
+		return err
+	}
+
+	gv, perr := schema.ParseGroupVersion(id.APIVersion)
+	rm := c.RESTMapper()
+	if perr != nil || rm == nil {
+		return err
+	}
+	m, merr := rm.RESTMapping(schema.GroupKind{Group: gv.Group, Kind: id.Kind})
+	if merr != nil || m.GroupVersionKind.Version == gv.Version {
+		// The ID's version is the preferred version, so the object
+		// presumably doesn't exist.
+		return err
+	}
+
+	u.SetAPIVersion(m.GroupVersionKind.GroupVersion().String())
+	u.SetKind(id.Kind)
+	if err := c.Get(ctx, nn, u); err != nil {
+		return err
+	}
+
+	upgraded := id
+	upgraded.APIVersion = u.GetAPIVersion()
+	recordUpgrade(ctx, id, upgraded)
+	return nil
+}
+
+// An idUpgrade records that an ID was upgraded to a newer API version.
+type idUpgrade struct {
+	ID         string `json:"id"`
+	UpgradedID string `json:"upgradedId"`
+}
+
+// The IDs upgraded while resolving an operation. Many resolvers may upgrade
+// IDs concurrently, so they share this extension.
+type idUpgrades struct {
+	mx  sync.Mutex
+	ids []idUpgrade
+}
+
+func (u *idUpgrades) MarshalJSON() ([]byte, error) {
+	u.mx.Lock()
+	defer u.mx.Unlock()
+	return json.Marshal(u.ids)
+}
+
+// Guards registration of the idUpgrades extension.
+var upgradesMx sync.Mutex
+
+func recordUpgrade(ctx context.Context, from, to model.ReferenceID) {
+	upgradesMx.Lock()
+	u, ok := graphql.GetExtension(ctx, extUpgradedIDs).(*idUpgrades)
+	if !ok {
+		u = &idUpgrades{}
+		graphql.RegisterExtension(ctx, extUpgradedIDs, u)
+	}
+	upgradesMx.Unlock()
+
+	u.mx.Lock()
+	u.ids = append(u.ids, idUpgrade{ID: from.String(), UpgradedID: to.String()})
+	u.mx.Unlock()
+}
+
 func (r *query) KubernetesResources(ctx context.Context, apiVersion, kind string, listKind, namespace *string) (*model.KubernetesResourceConnection, error) {
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
@@ -114,7 +302,7 @@ func (r *query) KubernetesResources(ctx context.Context, apiVersion, kind string
 	}
 
 	for i := range in.Items {
-		kr, err := model.GetKubernetesResource(&in.Items[i])
+		kr, err := model.GetKubernetesResource(&in.Items[i], r.categories)
 		if err != nil {
 			graphql.AddError(ctx, errors.Wrap(err, errModelResource))
 			continue
@@ -127,11 +315,24 @@ func (r *query) KubernetesResources(ctx context.Context, apiVersion, kind string
 	return out, nil
 }
 
-func (r *query) Events(ctx context.Context, involved *model.ReferenceID) (*model.EventConnection, error) {
+func (r *query) Events(ctx context.Context, involved *model.ReferenceID, namespace *string, involvedKind *string, typeArg *model.EventType, limit *int) (*model.EventConnection, error) {
 	e := events{clients: r.clients}
+
+	sel := eventSelector{Type: typeArg}
+	if namespace != nil {
+		sel.Namespace = *namespace
+	}
+	if involvedKind != nil {
+		sel.InvolvedKind = *involvedKind
+	}
+	if involved == nil && sel.Selects() {
+		// Select events server side.
+		return e.Select(ctx, sel, limit)
+	}
+
 	if involved == nil {
 		// Resolve all events.
-		return e.Resolve(ctx, nil)
+		return e.Resolve(ctx, nil, limit)
 	}
 
 	// Resolve events pertaining to the supplied ID.
@@ -140,7 +341,7 @@ func (r *query) Events(ctx context.Context, involved *model.ReferenceID) (*model
 		Kind:       involved.Kind,
 		Namespace:  involved.Namespace,
 		Name:       involved.Name,
-	})
+	}, limit)
 }
 
 func (r *query) Secret(ctx context.Context, namespace, name string) (*model.Secret, error) {
@@ -187,6 +388,133 @@ func (r *query) ConfigMap(ctx context.Context, namespace, name string) (*model.C
 	return &out, nil
 }
 
+// Crossplane adds this category to the CRDs of composite resources, e.g. to
+// support kubectl get composite.
+const categoryComposite = "composite"
+
+func (r *query) Summary(ctx context.Context) (*model.Summary, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	out := &model.Summary{
+		Providers:          &model.PackageHealthSummary{},
+		Configurations:     &model.PackageHealthSummary{},
+		CompositeResources: []model.ResourceHealthSummary{},
+		ManagedResources:   []model.ResourceHealthSummary{},
+	}
+
+	pl := &pkgv1.ProviderList{}
+	if err := c.List(ctx, pl); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errListProviders))
+		return nil, nil
+	}
+	for i := range pl.Items {
+		out.Providers.Add(pl.Items[i].GetCondition(pkgv1.TypeHealthy))
+	}
+
+	cl := &pkgv1.ConfigurationList{}
+	if err := c.List(ctx, cl); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errListConfigs))
+		return nil, nil
+	}
+	for i := range cl.Items {
+		out.Configurations.Add(cl.Items[i].GetCondition(pkgv1.TypeHealthy))
+	}
+
+	crds := &kextv1.CustomResourceDefinitionList{}
+	if err := c.List(ctx, crds); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errListCRDs))
+		return nil, nil
+	}
+
+	xrs := make([]kextv1.CustomResourceDefinition, 0)
+	mrs := make([]kextv1.CustomResourceDefinition, 0)
+	for _, crd := range crds.Items {
+		for _, cat := range crd.Spec.Names.Categories {
+			switch cat {
+			case categoryComposite:
+				xrs = append(xrs, crd)
+			case categoryManaged:
+				mrs = append(mrs, crd)
+			}
+		}
+	}
+
+	out.CompositeResources = summarizeResources(ctx, c, xrs)
+	out.ManagedResources = summarizeResources(ctx, c, mrs)
+	return out, nil
+}
+
+// summarizeResources summarizes the health of all resources defined by the
+// supplied CRDs, grouped by API group. Resources that can't be listed are
+// omitted from the summary.
+func summarizeResources(ctx context.Context, c client.Client, crds []kextv1.CustomResourceDefinition) []model.ResourceHealthSummary {
+	groups := make([]map[string]*model.ResourceHealthSummary, len(crds))
+	forEach(ctx, len(crds), func(i int) {
+		crd := crds[i]
+		v := storageVersion(crd)
+		if v == "" {
+			return
+		}
+
+		l := &kunstructured.UnstructuredList{}
+		l.SetAPIVersion(schema.GroupVersion{Group: crd.Spec.Group, Version: v}.String())
+		l.SetKind(crd.Spec.Names.ListKind)
+		if err := c.List(ctx, l); err != nil {
+			graphql.AddError(ctx, errors.Wrapf(err, errFmtListKind, crd.GetName()))
+			return
+		}
+
+		s := &model.ResourceHealthSummary{Group: crd.Spec.Group}
+		for j := range l.Items {
+			m := &xunstructured.Managed{Unstructured: l.Items[j]}
+			s.Add(m.GetCondition(xpv1.TypeReady), m.GetCondition(xpv1.TypeSynced))
+		}
+		groups[i] = map[string]*model.ResourceHealthSummary{crd.Spec.Group: s}
+	})
+
+	merged := map[string]*model.ResourceHealthSummary{}
+	for _, g := range groups {
+		for group, s := range g {
+			if m, ok := merged[group]; ok {
+				m.Merge(s)
+				continue
+			}
+			merged[group] = s
+		}
+	}
+
+	out := make([]model.ResourceHealthSummary, 0, len(merged))
+	for _, s := range merged {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Group < out[j].Group })
+	return out
+}
+
+// storageVersion returns the version in which the supplied CRD's resources are
+// stored, falling back to the first served version.
+func storageVersion(crd kextv1.CustomResourceDefinition) string {
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			return v.Name
+		}
+	}
+	for _, v := range crd.Spec.Versions {
+		if v.Served {
+			return v.Name
+		}
+	}
+	return ""
+}
+
 func (r *query) Providers(ctx context.Context) (*model.ProviderConnection, error) {
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
@@ -368,7 +696,46 @@ func (r *query) ConfigurationRevisions(ctx context.Context, configuration *model
 	return out, nil
 }
 
-func (r *query) CompositeResourceDefinitions(ctx context.Context, revision *model.ReferenceID, dangling *bool) (*model.CompositeResourceDefinitionConnection, error) {
+func (r *query) RevisionDiff(ctx context.Context, a, b model.ReferenceID) (*model.RevisionDiff, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	from, err := getObjectRefs(ctx, c, a)
+	if err != nil {
+		graphql.AddError(ctx, err)
+		return nil, nil
+	}
+
+	to, err := getObjectRefs(ctx, c, b)
+	if err != nil {
+		graphql.AddError(ctx, err)
+		return nil, nil
+	}
+
+	fromObjs, err := getRevisionObjects(ctx, c, from)
+	if err != nil {
+		graphql.AddError(ctx, err)
+		return nil, nil
+	}
+
+	toObjs, err := getRevisionObjects(ctx, c, to)
+	if err != nil {
+		graphql.AddError(ctx, err)
+		return nil, nil
+	}
+
+	return model.GetRevisionDiff(fromObjs, toObjs), nil
+}
+
+func (r *query) CompositeResourceDefinitions(ctx context.Context, revision *model.ReferenceID, dangling *bool, group, kind, claimKind *string) (*model.CompositeResourceDefinitionConnection, error) { //nolint:gocyclo
+	// This isn't _really_ that complex; it's a series of simple filters.
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
@@ -402,6 +769,22 @@ func (r *query) CompositeResourceDefinitions(ctx context.Context, revision *mode
 			continue
 		}
 
+		// We only want XRDs that define XRs in a different API group.
+		if group != nil && xrd.Spec.Group != *group {
+			continue
+		}
+
+		// We only want XRDs that define a different kind of XR.
+		if kind != nil && xrd.Spec.Names.Kind != *kind {
+			continue
+		}
+
+		// We only want XRDs that define a different kind of claim, or this
+		// XRD doesn't offer a claim.
+		if claimKind != nil && (xrd.Spec.ClaimNames == nil || xrd.Spec.ClaimNames.Kind != *claimKind) {
+			continue
+		}
+
 		out.Nodes = append(out.Nodes, model.GetCompositeResourceDefinition(xrd))
 		out.TotalCount++
 	}
@@ -410,7 +793,7 @@ func (r *query) CompositeResourceDefinitions(ctx context.Context, revision *mode
 	return out, nil
 }
 
-func (r *query) Compositions(ctx context.Context, revision *model.ReferenceID, dangling *bool) (*model.CompositionConnection, error) {
+func (r *query) Compositions(ctx context.Context, revision *model.ReferenceID, dangling *bool, xrKind *string, labels map[string]string) (*model.CompositionConnection, error) {
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
@@ -422,7 +805,7 @@ func (r *query) Compositions(ctx context.Context, revision *model.ReferenceID, d
 	}
 
 	in := &extv1.CompositionList{}
-	if err := c.List(ctx, in); err != nil {
+	if err := c.List(ctx, in, client.MatchingLabels(labels)); err != nil {
 		graphql.AddError(ctx, errors.Wrap(err, errListConfigs))
 		return nil, nil
 	}
@@ -444,6 +827,11 @@ func (r *query) Compositions(ctx context.Context, revision *model.ReferenceID, d
 			continue
 		}
 
+		// We only want Compositions that compose a different kind of XR.
+		if xrKind != nil && cmp.Spec.CompositeTypeRef.Kind != *xrKind {
+			continue
+		}
+
 		out.Nodes = append(out.Nodes, model.GetComposition(cmp))
 		out.TotalCount++
 	}
@@ -452,6 +840,440 @@ func (r *query) Compositions(ctx context.Context, revision *model.ReferenceID, d
 	return out, nil
 }
 
+func (r *query) StoreConfigs(ctx context.Context) (*model.StoreConfigConnection, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	out := &model.StoreConfigConnection{
+		Nodes: make([]model.StoreConfig, 0),
+	}
+
+	for _, k := range []string{xunstructured.KindStoreConfig, xunstructured.KindClusterStoreConfig} {
+		in := &kunstructured.UnstructuredList{}
+		in.SetAPIVersion(xunstructured.APIVersionSecrets)
+		in.SetKind(k + "List")
+
+		// Not all versions of Crossplane serve all kinds of store config.
+		err := c.List(ctx, in)
+		if kmeta.IsNoMatchError(err) {
+			continue
+		}
+		if err != nil {
+			graphql.AddError(ctx, errors.Wrap(err, errListStoreConfigs))
+			return nil, nil
+		}
+
+		for i := range in.Items {
+			out.Nodes = append(out.Nodes, model.GetStoreConfig(&in.Items[i]))
+			out.TotalCount++
+		}
+	}
+
+	sort.Stable(out)
+	return out, nil
+}
+
+func (r *query) Functions(ctx context.Context) (*model.FunctionConnection, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	in, err := listServed(ctx, c, gkFunction, xunstructured.FunctionVersions)
+	// Not all versions of Crossplane support functions.
+	if kmeta.IsNoMatchError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errListFunctions))
+		return nil, nil
+	}
+
+	out := &model.FunctionConnection{
+		Nodes:      make([]model.Function, 0, len(in.Items)),
+		TotalCount: len(in.Items),
+	}
+
+	for i := range in.Items {
+		out.Nodes = append(out.Nodes, model.GetFunction(&in.Items[i]))
+	}
+
+	sort.Stable(out)
+	return out, nil
+}
+
+func (r *query) DeploymentRuntimeConfigs(ctx context.Context) (*model.DeploymentRuntimeConfigConnection, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	in, err := listServed(ctx, c, gkDeploymentRuntimeConfig, xunstructured.DeploymentRuntimeConfigVersions)
+	// Not all versions of Crossplane support deployment runtime configs.
+	if kmeta.IsNoMatchError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errListRCs))
+		return nil, nil
+	}
+
+	out := &model.DeploymentRuntimeConfigConnection{
+		Nodes:      make([]model.DeploymentRuntimeConfig, 0, len(in.Items)),
+		TotalCount: len(in.Items),
+	}
+
+	for i := range in.Items {
+		out.Nodes = append(out.Nodes, model.GetDeploymentRuntimeConfig(&in.Items[i]))
+	}
+
+	sort.Stable(out)
+	return out, nil
+}
+
+func (r *query) Lock(ctx context.Context) (*model.Lock, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	l, err := getLock(ctx, c)
+	// The package manager creates the lock when it installs its first package.
+	if kerrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetLock))
+		return nil, nil
+	}
+
+	out := model.GetLock(&l.Unstructured)
+	return &out, nil
+}
+
+func (r *query) ManagedResources(ctx context.Context, ready, synced *model.ConditionStatus, providerConfig *string, labels map[string]string, limit, offset *int) (*model.ManagedResourceConnection, error) {
+	mr := &managedResources{clients: r.clients}
+	return mr.Resolve(ctx, managedResourceSelector{
+		ready:          ready,
+		synced:         synced,
+		providerConfig: providerConfig,
+		labels:         labels,
+	}, limit, offset)
+}
+
+func (r *query) OrphanedManagedResources(ctx context.Context, limit, offset *int) (*model.ManagedResourceConnection, error) {
+	mr := &managedResources{clients: r.clients}
+	return mr.Resolve(ctx, managedResourceSelector{orphaned: true}, limit, offset)
+}
+
+// Resources are considered unhealthy by default if they're not ready or not
+// synced.
+var defaultUnhealthy = []model.ConditionPredicate{
+	{Type: string(xpv1.TypeReady), Status: model.ConditionStatusFalse},
+	{Type: string(xpv1.TypeSynced), Status: model.ConditionStatusFalse},
+}
+
+func (r *query) UnhealthyResources(ctx context.Context, conditions []model.ConditionPredicate, limit, offset *int) (*model.KubernetesResourceConnection, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	in := &kextv1.CustomResourceDefinitionList{}
+	if err := c.List(ctx, in); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errListCRDs))
+		return nil, nil
+	}
+
+	crds := make([]kextv1.CustomResourceDefinition, 0)
+	for _, crd := range in.Items {
+		cats := crd.Spec.Names.Categories
+		if hasCategory(cats, categoryComposite) || hasCategory(cats, categoryManaged) {
+			crds = append(crds, crd)
+		}
+	}
+
+	if conditions == nil {
+		conditions = defaultUnhealthy
+	}
+	now := time.Now()
+
+	found := make([][]model.KubernetesResource, len(crds))
+	forEach(ctx, len(crds), func(i int) {
+		crd := crds[i]
+		v := storageVersion(crd)
+		if v == "" {
+			return
+		}
+
+		l := &kunstructured.UnstructuredList{}
+		l.SetAPIVersion(schema.GroupVersion{Group: crd.Spec.Group, Version: v}.String())
+		l.SetKind(crd.Spec.Names.ListKind)
+		if err := c.List(ctx, l); err != nil {
+			graphql.AddError(ctx, errors.Wrapf(err, errFmtListKind, crd.GetName()))
+			return
+		}
+
+		composite := hasCategory(crd.Spec.Names.Categories, categoryComposite)
+		for j := range l.Items {
+			u := &l.Items[j]
+			if !anyConditionMatches(&xunstructured.Managed{Unstructured: *u}, conditions, now) {
+				continue
+			}
+			if composite {
+				found[i] = append(found[i], model.GetCompositeResource(u))
+				continue
+			}
+			found[i] = append(found[i], model.GetManagedResource(u))
+		}
+	})
+
+	out := &model.KubernetesResourceConnection{Nodes: make([]model.KubernetesResource, 0)}
+	for _, krs := range found {
+		out.Nodes = append(out.Nodes, krs...)
+	}
+	out.TotalCount = len(out.Nodes)
+
+	sort.Stable(out)
+	lo, hi := page(ctx, limit, offset, len(out.Nodes))
+	out.Nodes = out.Nodes[lo:hi]
+	return out, nil
+}
+
+// anyConditionMatches returns true if the supplied resource has a condition
+// that matches any of the supplied predicates at the supplied time.
+func anyConditionMatches(m *xunstructured.Managed, ps []model.ConditionPredicate, now time.Time) bool {
+	for _, p := range ps {
+		c := m.GetCondition(xpv1.ConditionType(p.Type))
+		if model.GetConditionStatus(c.Status) != p.Status {
+			continue
+		}
+		if p.For != nil && now.Sub(c.LastTransitionTime.Time) < *p.For {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func (r *query) ProviderConfigs(ctx context.Context, provider *model.ReferenceID) (*model.ProviderConfigConnection, error) { //nolint:gocyclo
+	// This isn't _really_ that complex; it's a series of simple filters.
+	if provider != nil && (provider.APIVersion != pkgv1.ProviderGroupVersionKind.GroupVersion().String() || provider.Kind != pkgv1.ProviderKind) {
+		graphql.AddError(ctx, errors.Errorf(errFmtNotProvider, provider.Kind))
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	// The package manager makes every revision of a provider an owner of the
+	// CRDs it installs, so we consider CRDs owned by any of its revisions.
+	var owners map[types.UID]bool
+	if provider != nil {
+		prl := &pkgv1.ProviderRevisionList{}
+		if err := c.List(ctx, prl, revisionsOf(provider.Name)); err != nil {
+			graphql.AddError(ctx, errors.Wrap(err, errListProviderRevs))
+			return nil, nil
+		}
+		owners = map[types.UID]bool{}
+		for i := range prl.Items {
+			pr := &prl.Items[i]
+			if c := metav1.GetControllerOf(pr); c == nil || c.Kind != pkgv1.ProviderKind || c.Name != provider.Name {
+				continue
+			}
+			owners[pr.GetUID()] = true
+		}
+	}
+
+	in := &kextv1.CustomResourceDefinitionList{}
+	if err := c.List(ctx, in); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errListCRDs))
+		return nil, nil
+	}
+
+	out := &model.ProviderConfigConnection{
+		Nodes: make([]model.ProviderConfig, 0),
+	}
+
+	for i := range in.Items {
+		crd := &in.Items[i]
+
+		if owners != nil && !ownedBy(crd.GetOwnerReferences(), owners) {
+			continue
+		}
+
+		// Providers also install CRDs that aren't provider configs, like
+		// managed resources and provider config usages.
+		switch crd.Spec.Names.Kind {
+		case xunstructured.KindProviderConfig, xunstructured.KindClusterProviderConfig:
+		default:
+			continue
+		}
+
+		gv := schema.GroupVersion{
+			Group:   crd.Spec.Group,
+			Version: pickCRDVersion(model.GetCustomResourceDefinitionVersions(crd.Spec.Versions)),
+		}
+		ul := &kunstructured.UnstructuredList{}
+		ul.SetAPIVersion(gv.String())
+		ul.SetKind(crd.Spec.Names.Kind + "List")
+		if lk := crd.Spec.Names.ListKind; lk != "" {
+			ul.SetKind(lk)
+		}
+
+		if err := c.List(ctx, ul); err != nil {
+			graphql.AddError(ctx, errors.Wrapf(err, errFmtListKind, crd.GetName()))
+			continue
+		}
+
+		for j := range ul.Items {
+			if !xunstructured.ProbablyProviderConfig(&ul.Items[j]) {
+				continue
+			}
+			out.Nodes = append(out.Nodes, model.GetProviderConfig(&ul.Items[j]))
+			out.TotalCount++
+		}
+	}
+
+	sort.Stable(out)
+	return out, nil
+}
+
+func (r *query) Logs(ctx context.Context, id model.ReferenceID, container *string, tailLines, sinceSeconds *int) (*model.PodLogs, error) {
+	if id.APIVersion != corev1.SchemeGroupVersion.String() || id.Kind != "Pod" {
+		graphql.AddError(ctx, errors.Errorf(errFmtNotPod, id.Kind))
+		return nil, nil
+	}
+	if r.logs == nil {
+		graphql.AddError(ctx, errors.New(errLogsDisabled))
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	o := &corev1.PodLogOptions{
+		Container:  pointer.StringPtrDerefOr(container, ""),
+		TailLines:  pointer.Int64Ptr(defaultTailLines),
+		LimitBytes: pointer.Int64Ptr(maxLogBytes),
+	}
+	if tailLines != nil {
+		o.TailLines = pointer.Int64Ptr(int64(*tailLines))
+	}
+	if sinceSeconds != nil {
+		o.SinceSeconds = pointer.Int64Ptr(int64(*sinceSeconds))
+	}
+
+	creds, _ := auth.FromContext(ctx)
+	rc, err := r.logs.Stream(ctx, creds, id.Namespace, id.Name, o)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetLogs))
+		return nil, nil
+	}
+	defer rc.Close() //nolint:errcheck // Nothing useful to do with this error.
+
+	out := &model.PodLogs{Container: container, Lines: make([]string, 0)}
+	s := bufio.NewScanner(rc)
+	s.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLogBytes)
+	for s.Scan() {
+		out.Lines = append(out.Lines, s.Text())
+	}
+	if err := s.Err(); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errReadLogs))
+		return nil, nil
+	}
+
+	return out, nil
+}
+
+func (r *query) Viewer(ctx context.Context) (*model.Viewer, error) {
+	if r.tokens == nil {
+		graphql.AddError(ctx, errors.New(errViewerDisabled))
+		return nil, nil
+	}
+
+	creds, _ := auth.FromContext(ctx)
+	if creds.BearerToken == "" {
+		graphql.AddError(ctx, errors.New(errNoBearerToken))
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	s, err := r.tokens.Review(ctx, creds.BearerToken)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errReviewToken))
+		return nil, nil
+	}
+	if !s.Authenticated {
+		graphql.AddError(ctx, errors.Errorf(errFmtNotAuthd, s.Error))
+		return nil, nil
+	}
+
+	return model.GetViewer(*s), nil
+}
+
+func (r *query) APIResources(ctx context.Context, group *string) (*model.APIResourceConnection, error) {
+	if r.discovery == nil {
+		graphql.AddError(ctx, errors.New(errDiscDisabled))
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	groups, lists, err := r.discovery.Discover(ctx, creds, group)
+	if err != nil {
+		// Discovery may partially succeed, e.g. if one aggregated API
+		// server is unavailable.
+		graphql.AddError(ctx, errors.Wrap(err, errDiscover))
+		if len(lists) == 0 {
+			return nil, nil
+		}
+	}
+
+	nodes := model.GetAPIResources(groups, lists)
+	out := &model.APIResourceConnection{Nodes: nodes, TotalCount: len(nodes)}
+	sort.Stable(out)
+	return out, nil
+}
+
 func containsCR(in []metav1.OwnerReference) bool {
 	for _, ref := range in {
 		switch {
@@ -479,3 +1301,7 @@ func containsID(in []metav1.OwnerReference, id model.ReferenceID) bool {
 	}
 	return false
 }
+
+func (r *query) BuildInfo(_ context.Context) (*model.BuildInfo, error) {
+	return &model.BuildInfo{Version: version.Version, SchemaVersion: xschema.Version()}, nil
+}
@@ -16,13 +16,19 @@ package resolvers
 
 import (
 	"context"
+	"io"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/pkg/errors"
 	"github.com/vektah/gqlparser/v2/gqlerror"
+	authnv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 	kextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -32,6 +38,7 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	extv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
@@ -40,6 +47,8 @@ import (
 	"github.com/upbound/xgql/internal/clients"
 	"github.com/upbound/xgql/internal/graph/generated"
 	"github.com/upbound/xgql/internal/graph/model"
+	"github.com/upbound/xgql/internal/version"
+	xschema "github.com/upbound/xgql/schema"
 )
 
 var _ generated.QueryResolver = &query{}
@@ -47,7 +56,13 @@ var _ generated.QueryResolver = &query{}
 func TestQueryKubernetesResource(t *testing.T) {
 	errBoom := errors.New("boom")
 
-	gkr, _ := model.GetKubernetesResource(&unstructured.Unstructured{})
+	gkr, _ := model.GetKubernetesResource(&unstructured.Unstructured{}, nil)
+
+	mid := model.ReferenceID{APIVersion: "ec2.aws.upbound.io/v1beta1", Kind: "VPC", Name: "cool"}
+	mu := &unstructured.Unstructured{}
+	mu.SetAPIVersion(mid.APIVersion)
+	mu.SetKind(mid.Kind)
+	ci := mockCategoryIndex{mu.GroupVersionKind().GroupKind(): {model.CategoryManaged}}
 
 	type args struct {
 		ctx context.Context
@@ -60,10 +75,11 @@ func TestQueryKubernetesResource(t *testing.T) {
 	}
 
 	cases := map[string]struct {
-		reason  string
-		clients ClientCache
-		args    args
-		want    want
+		reason     string
+		clients    ClientCache
+		categories model.CategoryIndex
+		args       args
+		want       want
 	}{
 		"GetClientError": {
 			reason: "If we can't get a client we should add the error to the GraphQL context and return early.",
@@ -109,11 +125,27 @@ func TestQueryKubernetesResource(t *testing.T) {
 				kr: gkr,
 			},
 		},
+		"CategoryIndex": {
+			reason: "The resolver's category index should be consulted to determine the resource's type.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+				}, nil
+			}),
+			categories: ci,
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				id:  mid,
+			},
+			want: want{
+				kr: model.GetManagedResource(mu),
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			q := &query{clients: tc.clients}
+			q := &query{clients: tc.clients, categories: tc.categories}
 
 			// Our GraphQL resolvers never return errors. We instead add an
 			// error to the GraphQL context and return early.
@@ -133,11 +165,232 @@ func TestQueryKubernetesResource(t *testing.T) {
 	}
 }
 
+func TestQueryNode(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	gkr, _ := model.GetKubernetesResource(&unstructured.Unstructured{}, nil)
+	ev := &corev1.Event{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cool"}}
+	ev.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Event"))
+	gev := model.GetEvent(ev)
+
+	type args struct {
+		ctx context.Context
+		id  model.ReferenceID
+	}
+	type want struct {
+		n    model.Node
+		err  error
+		errs gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"GetClientError": {
+			reason: "If we can't get a client we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{}, errBoom
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetClient).Error()),
+				},
+			},
+		},
+		"GetNodeError": {
+			reason: "If we can't get the node we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetResource).Error()),
+				},
+			},
+		},
+		"Event": {
+			reason: "If the ID is that of an event we should return an event.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						*obj.(*corev1.Event) = corev1.Event{ObjectMeta: ev.ObjectMeta}
+						return nil
+					}),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				id:  gev.ID,
+			},
+			want: want{
+				n: gev,
+			},
+		},
+		"Success": {
+			reason: "If we can get and model the node we should return it.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+			},
+			want: want{
+				n: gkr.(model.Node),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			q := &query{clients: tc.clients}
+
+			got, err := q.Node(tc.args.ctx, tc.args.id)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nq.Node(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nq.Node(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.n, got, cmpopts.IgnoreFields(model.GenericResource{}, "Unstructured"), cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\nq.Node(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestQueryNodes(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	gkr, _ := model.GetKubernetesResource(&unstructured.Unstructured{}, nil)
+
+	c := &test.MockClient{
+		MockGet: func(_ context.Context, key client.ObjectKey, _ client.Object) error {
+			if key.Name == "missing" {
+				return errBoom
+			}
+			return nil
+		},
+	}
+	q := &query{clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+		return c, nil
+	})}
+
+	ctx := graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover)
+	got, err := q.Nodes(ctx, []model.ReferenceID{{Name: "found"}, {Name: "missing"}})
+	if err != nil {
+		t.Errorf("q.Nodes(...): %s", err)
+	}
+
+	// Nodes that can't be fetched should be null, and should not affect the
+	// order of those that can.
+	want := []model.Node{gkr.(model.Node), nil}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(model.GenericResource{}, "Unstructured"), cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+		t.Errorf("q.Nodes(...): -want, +got:\n%s", diff)
+	}
+	wantErrs := gqlerror.List{gqlerror.Errorf(errors.Wrap(errBoom, errGetResource).Error())}
+	if diff := cmp.Diff(wantErrs, graphql.GetErrors(ctx), test.EquateErrors()); diff != "" {
+		t.Errorf("q.Nodes(...): -want GraphQL errors, +got GraphQL errors:\n%s", diff)
+	}
+}
+
+// A mapperClient is a mock client with a REST mapper.
+type mapperClient struct {
+	*test.MockClient
+	mapper kmeta.RESTMapper
+}
+
+func (c *mapperClient) RESTMapper() kmeta.RESTMapper { return c.mapper }
+
+func TestGetByID(t *testing.T) {
+	v1 := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Example"}
+	v2 := schema.GroupVersionKind{Group: "example.org", Version: "v2", Kind: "Example"}
+
+	rm := kmeta.NewDefaultRESTMapper([]schema.GroupVersion{v2.GroupVersion()})
+	rm.Add(v2, kmeta.RESTScopeNamespace)
+
+	// The API server only serves v2.
+	c := &mapperClient{
+		MockClient: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+			if obj.GetObjectKind().GroupVersionKind() != v2 {
+				return &kmeta.NoKindMatchError{GroupKind: v1.GroupKind(), SearchedVersions: []string{v1.Version}}
+			}
+			return nil
+		})},
+		mapper: rm,
+	}
+
+	type want struct {
+		gvk      schema.GroupVersionKind
+		upgraded []idUpgrade
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason string
+		id     model.ReferenceID
+		want   want
+	}{
+		"Served": {
+			reason: "An object whose ID's version is served should be got at that version.",
+			id:     model.ReferenceID{APIVersion: v2.GroupVersion().String(), Kind: v2.Kind, Namespace: "default", Name: "cool"},
+			want:   want{gvk: v2},
+		},
+		"Upgraded": {
+			reason: "An object whose ID's version is not served should be got at the preferred version, and the ID's upgrade recorded.",
+			id:     model.ReferenceID{APIVersion: v1.GroupVersion().String(), Kind: v1.Kind, Namespace: "default", Name: "cool"},
+			want: want{
+				gvk: v2,
+				upgraded: []idUpgrade{{
+					ID:         (&model.ReferenceID{APIVersion: v1.GroupVersion().String(), Kind: v1.Kind, Namespace: "default", Name: "cool"}).String(),
+					UpgradedID: (&model.ReferenceID{APIVersion: v2.GroupVersion().String(), Kind: v2.Kind, Namespace: "default", Name: "cool"}).String(),
+				}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover)
+			u := &unstructured.Unstructured{}
+			err := getByID(ctx, c, tc.id, u)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ngetByID(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.gvk, u.GroupVersionKind()); diff != "" {
+				t.Errorf("\n%s\ngetByID(...): -want GVK, +got GVK:\n%s\n", tc.reason, diff)
+			}
+			var got []idUpgrade
+			if ups, ok := graphql.GetExtension(ctx, extUpgradedIDs).(*idUpgrades); ok {
+				got = ups.ids
+			}
+			if diff := cmp.Diff(tc.want.upgraded, got); diff != "" {
+				t.Errorf("\n%s\ngetByID(...): -want upgraded IDs, +got upgraded IDs:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestQueryKubernetesResources(t *testing.T) {
 	errBoom := errors.New("boom")
 
 	kr := unstructured.Unstructured{}
-	gkr, _ := model.GetKubernetesResource(&kr)
+	gkr, _ := model.GetKubernetesResource(&kr, nil)
 
 	group := "example.org"
 	version := "v1"
@@ -501,6 +754,123 @@ func TestQueryConfigMap(t *testing.T) {
 	}
 }
 
+func TestQuerySummary(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	healthy := pkgv1.Provider{}
+	healthy.SetConditions(pkgv1.Healthy())
+	unhealthy := pkgv1.Provider{}
+	unhealthy.SetConditions(pkgv1.Unhealthy())
+
+	crd := func(name, category string) kextv1.CustomResourceDefinition {
+		return kextv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: kextv1.CustomResourceDefinitionSpec{
+				Group:    "example.org",
+				Names:    kextv1.CustomResourceDefinitionNames{ListKind: name + "List", Categories: []string{category}},
+				Versions: []kextv1.CustomResourceDefinitionVersion{{Name: "v1", Served: true, Storage: true}},
+			},
+		}
+	}
+
+	ready := unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": string(xpv1.TypeReady), "status": string(corev1.ConditionTrue)},
+			},
+		},
+	}}
+
+	type want struct {
+		s    *model.Summary
+		err  error
+		errs gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		want    want
+	}{
+		"GetClientError": {
+			reason: "If we can't get a client we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{}, errBoom
+			}),
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetClient).Error()),
+				},
+			},
+		},
+		"ListProvidersError": {
+			reason: "If we can't list providers we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(errBoom),
+				}, nil
+			}),
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errListProviders).Error()),
+				},
+			},
+		},
+		"Success": {
+			reason: "We should summarize the health of packages, and of composite and managed resources by API group.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						switch l := obj.(type) {
+						case *pkgv1.ProviderList:
+							l.Items = []pkgv1.Provider{healthy, unhealthy}
+						case *kextv1.CustomResourceDefinitionList:
+							l.Items = []kextv1.CustomResourceDefinition{crd("xwidgets", "composite"), crd("widgets", "managed"), crd("gadgets", "managed")}
+						case *unstructured.UnstructuredList:
+							if l.GetKind() == "widgetsList" {
+								return errBoom
+							}
+							l.Items = []unstructured.Unstructured{ready}
+						}
+						return nil
+					}),
+				}, nil
+			}),
+			want: want{
+				s: &model.Summary{
+					Providers:          &model.PackageHealthSummary{Total: 2, Healthy: 1, Unhealthy: 1},
+					Configurations:     &model.PackageHealthSummary{},
+					CompositeResources: []model.ResourceHealthSummary{{Group: "example.org", Total: 1, Ready: 1, NotSynced: 1}},
+					ManagedResources:   []model.ResourceHealthSummary{{Group: "example.org", Total: 1, Ready: 1, NotSynced: 1}},
+				},
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrapf(errBoom, errFmtListKind, "widgets").Error()),
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			q := &query{clients: tc.clients}
+			ctx := graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover)
+
+			got, err := q.Summary(ctx)
+			errs := graphql.GetErrors(ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nq.Summary(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nq.Summary(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.s, got); diff != "" {
+				t.Errorf("\n%s\nq.Summary(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestQueryProviders(t *testing.T) {
 	errBoom := errors.New("boom")
 
@@ -1234,49 +1604,249 @@ func TestQueryConfigurationRevisions(t *testing.T) {
 	}
 }
 
-func TestQueryCompositeResourceDefinitions(t *testing.T) {
+func TestQueryRevisionDiff(t *testing.T) {
 	errBoom := errors.New("boom")
 
-	id := model.ReferenceID{
-		APIVersion: pkgv1.ConfigurationRevisionGroupVersionKind.GroupVersion().String(),
-		Kind:       pkgv1.ConfigurationRevisionKind,
-		Name:       "example",
+	a := model.ReferenceID{
+		APIVersion: pkgv1.ProviderRevisionGroupVersionKind.GroupVersion().String(),
+		Kind:       pkgv1.ProviderRevisionKind,
+		Name:       "a",
+	}
+	b := model.ReferenceID{
+		APIVersion: pkgv1.ProviderRevisionGroupVersionKind.GroupVersion().String(),
+		Kind:       pkgv1.ProviderRevisionKind,
+		Name:       "b",
+	}
+	notrev := model.ReferenceID{
+		APIVersion: pkgv1.ProviderGroupVersionKind.GroupVersion().String(),
+		Kind:       pkgv1.ProviderKind,
+		Name:       "a",
 	}
 
-	owned := extv1.CompositeResourceDefinition{ObjectMeta: metav1.ObjectMeta{
-		Name: "coolconfig",
-		OwnerReferences: []metav1.OwnerReference{
-			// Some spurious owner references that we should ignore.
-			{
-				APIVersion: "wat",
-			},
-			{
-				APIVersion: id.APIVersion,
-				Kind:       "wat",
-			},
-			{
-				APIVersion: id.APIVersion,
-				Kind:       id.Kind,
-				Name:       "wat",
-			},
-			// The reference that indicates this XRD is owned by our desired
-			// ConfigurationRevision (or a ConfigurationRevision generally).
-			{
-				APIVersion: id.APIVersion,
-				Kind:       id.Kind,
-				Name:       id.Name,
-			},
+	refs := map[string][]xpv1.TypedReference{
+		"a": {
+			{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "removed"},
+			{APIVersion: "apiextensions.k8s.io/v1beta1", Kind: "CustomResourceDefinition", Name: "upgraded"},
 		},
-	}}
-	gowned := model.GetCompositeResourceDefinition(&owned)
+		"b": {
+			{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "added"},
+			{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "upgraded"},
+		},
+	}
+
+	type args struct {
+		ctx context.Context
+		a   model.ReferenceID
+		b   model.ReferenceID
+	}
+	type want struct {
+		rd   *model.RevisionDiff
+		err  error
+		errs gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"GetClientError": {
+			reason: "If we can't get a client we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{}, errBoom
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetClient).Error()),
+				},
+			},
+		},
+		"NotARevision": {
+			reason: "If either ID is not a package revision we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				a:   notrev,
+				b:   b,
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Errorf(errFmtNotRevision, notrev.Kind).Error()),
+				},
+			},
+		},
+		"GetRevisionError": {
+			reason: "If we can't get a revision we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				a:   a,
+				b:   b,
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetRevision).Error()),
+				},
+			},
+		},
+		"GetObjectError": {
+			reason: "If we can't get an object installed by a revision we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+						if pr, ok := obj.(*pkgv1.ProviderRevision); ok {
+							pr.Status.ObjectRefs = refs[key.Name]
+							return nil
+						}
+						return errBoom
+					},
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				a:   a,
+				b:   b,
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetRevisionObject).Error()),
+				},
+			},
+		},
+		"Success": {
+			reason: "If we can get both revisions we should return the difference between their objects.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+						switch o := obj.(type) {
+						case *pkgv1.ProviderRevision:
+							o.Status.ObjectRefs = refs[key.Name]
+						case *unstructured.Unstructured:
+							if key.Name == "removed" {
+								return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+							}
+							o.SetName(key.Name)
+						}
+						return nil
+					},
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				a:   a,
+				b:   b,
+			},
+			want: want{
+				rd: &model.RevisionDiff{
+					Added: []model.RevisionObjectDiff{{
+						Kind:         "CustomResourceDefinition",
+						Name:         "added",
+						ToAPIVersion: pointer.StringPtr("apiextensions.k8s.io/v1"),
+					}},
+					Removed: []model.RevisionObjectDiff{{
+						Kind:           "CustomResourceDefinition",
+						Name:           "removed",
+						FromAPIVersion: pointer.StringPtr("apiextensions.k8s.io/v1"),
+					}},
+					Changed: []model.RevisionObjectDiff{{
+						Kind:           "CustomResourceDefinition",
+						Name:           "upgraded",
+						FromAPIVersion: pointer.StringPtr("apiextensions.k8s.io/v1beta1"),
+						ToAPIVersion:   pointer.StringPtr("apiextensions.k8s.io/v1"),
+					}},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			q := &query{clients: tc.clients}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := q.RevisionDiff(tc.args.ctx, tc.args.a, tc.args.b)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nq.RevisionDiff(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nq.RevisionDiff(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.rd, got); diff != "" {
+				t.Errorf("\n%s\nq.RevisionDiff(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestQueryCompositeResourceDefinitions(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	id := model.ReferenceID{
+		APIVersion: pkgv1.ConfigurationRevisionGroupVersionKind.GroupVersion().String(),
+		Kind:       pkgv1.ConfigurationRevisionKind,
+		Name:       "example",
+	}
+
+	owned := extv1.CompositeResourceDefinition{ObjectMeta: metav1.ObjectMeta{
+		Name: "coolconfig",
+		OwnerReferences: []metav1.OwnerReference{
+			// Some spurious owner references that we should ignore.
+			{
+				APIVersion: "wat",
+			},
+			{
+				APIVersion: id.APIVersion,
+				Kind:       "wat",
+			},
+			{
+				APIVersion: id.APIVersion,
+				Kind:       id.Kind,
+				Name:       "wat",
+			},
+			// The reference that indicates this XRD is owned by our desired
+			// ConfigurationRevision (or a ConfigurationRevision generally).
+			{
+				APIVersion: id.APIVersion,
+				Kind:       id.Kind,
+				Name:       id.Name,
+			},
+		},
+	}}
+	gowned := model.GetCompositeResourceDefinition(&owned)
 
 	dangler := extv1.CompositeResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "coolconfig"}}
 	gdangler := model.GetCompositeResourceDefinition(&dangler)
 
+	claimable := extv1.CompositeResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "xclusters.example.org"},
+		Spec: extv1.CompositeResourceDefinitionSpec{
+			Group:      "example.org",
+			Names:      kextv1.CustomResourceDefinitionNames{Kind: "XCluster"},
+			ClaimNames: &kextv1.CustomResourceDefinitionNames{Kind: "Cluster"},
+		},
+	}
+	gclaimable := model.GetCompositeResourceDefinition(&claimable)
+
 	type args struct {
-		ctx      context.Context
-		revision *model.ReferenceID
-		dangling *bool
+		ctx       context.Context
+		revision  *model.ReferenceID
+		dangling  *bool
+		group     *string
+		kind      *string
+		claimKind *string
 	}
 	type want struct {
 		xrdc *model.CompositeResourceDefinitionConnection
@@ -1404,6 +1974,90 @@ func TestQueryCompositeResourceDefinitions(t *testing.T) {
 				},
 			},
 		},
+		"XRDsByGroup": {
+			reason: "We should only return the XRDs that define composite resources in the supplied API group.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						*obj.(*extv1.CompositeResourceDefinitionList) = extv1.CompositeResourceDefinitionList{
+							Items: []extv1.CompositeResourceDefinition{
+								dangler,
+								claimable,
+							},
+						}
+						return nil
+					}),
+				}, nil
+			}),
+			args: args{
+				ctx:   graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				group: pointer.StringPtr("example.org"),
+			},
+			want: want{
+				xrdc: &model.CompositeResourceDefinitionConnection{
+					Nodes: []model.CompositeResourceDefinition{
+						gclaimable,
+					},
+					TotalCount: 1,
+				},
+			},
+		},
+		"XRDsByKind": {
+			reason: "We should only return the XRDs that define composite resources of the supplied kind.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						*obj.(*extv1.CompositeResourceDefinitionList) = extv1.CompositeResourceDefinitionList{
+							Items: []extv1.CompositeResourceDefinition{
+								dangler,
+								claimable,
+							},
+						}
+						return nil
+					}),
+				}, nil
+			}),
+			args: args{
+				ctx:  graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				kind: pointer.StringPtr("XCluster"),
+			},
+			want: want{
+				xrdc: &model.CompositeResourceDefinitionConnection{
+					Nodes: []model.CompositeResourceDefinition{
+						gclaimable,
+					},
+					TotalCount: 1,
+				},
+			},
+		},
+		"XRDsByClaimKind": {
+			reason: "We should only return the XRDs that define claims of the supplied kind.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						*obj.(*extv1.CompositeResourceDefinitionList) = extv1.CompositeResourceDefinitionList{
+							Items: []extv1.CompositeResourceDefinition{
+								dangler,
+								claimable,
+							},
+						}
+						return nil
+					}),
+				}, nil
+			}),
+			args: args{
+				ctx:       graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				claimKind: pointer.StringPtr("Cluster"),
+			},
+			want: want{
+				xrdc: &model.CompositeResourceDefinitionConnection{
+					Nodes: []model.CompositeResourceDefinition{
+						gclaimable,
+					},
+					TotalCount: 1,
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -1412,7 +2066,7 @@ func TestQueryCompositeResourceDefinitions(t *testing.T) {
 
 			// Our GraphQL resolvers never return errors. We instead add an
 			// error to the GraphQL context and return early.
-			got, err := q.CompositeResourceDefinitions(tc.args.ctx, tc.args.revision, tc.args.dangling)
+			got, err := q.CompositeResourceDefinitions(tc.args.ctx, tc.args.revision, tc.args.dangling, tc.args.group, tc.args.kind, tc.args.claimKind)
 			errs := graphql.GetErrors(tc.args.ctx)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
@@ -1468,10 +2122,20 @@ func TestQueryCompositions(t *testing.T) {
 	dangler := extv1.Composition{ObjectMeta: metav1.ObjectMeta{Name: "coolconfig"}}
 	gdangler := model.GetComposition(&dangler)
 
+	cluster := extv1.Composition{
+		ObjectMeta: metav1.ObjectMeta{Name: "xclusters.aws.example.org"},
+		Spec: extv1.CompositionSpec{
+			CompositeTypeRef: extv1.TypeReference{APIVersion: "example.org/v1", Kind: "XCluster"},
+		},
+	}
+	gcluster := model.GetComposition(&cluster)
+
 	type args struct {
 		ctx      context.Context
 		revision *model.ReferenceID
 		dangling *bool
+		xrKind   *string
+		labels   map[string]string
 	}
 	type want struct {
 		cc   *model.CompositionConnection
@@ -1599,6 +2263,64 @@ func TestQueryCompositions(t *testing.T) {
 				},
 			},
 		},
+		"CompositionsByXRKind": {
+			reason: "We should only return the compositions that compose the supplied kind of composite resource.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						*obj.(*extv1.CompositionList) = extv1.CompositionList{
+							Items: []extv1.Composition{
+								dangler,
+								cluster,
+							},
+						}
+						return nil
+					}),
+				}, nil
+			}),
+			args: args{
+				ctx:    graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				xrKind: pointer.StringPtr("XCluster"),
+			},
+			want: want{
+				cc: &model.CompositionConnection{
+					Nodes: []model.Composition{
+						gcluster,
+					},
+					TotalCount: 1,
+				},
+			},
+		},
+		"CompositionsByLabels": {
+			reason: "We should ask the API server for only the compositions with the supplied labels.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: func(_ context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+						lo := &client.ListOptions{}
+						lo.ApplyOptions(opts)
+						if lo.LabelSelector == nil || lo.LabelSelector.String() != "provider=aws" {
+							return errBoom
+						}
+						*obj.(*extv1.CompositionList) = extv1.CompositionList{
+							Items: []extv1.Composition{cluster},
+						}
+						return nil
+					},
+				}, nil
+			}),
+			args: args{
+				ctx:    graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				labels: map[string]string{"provider": "aws"},
+			},
+			want: want{
+				cc: &model.CompositionConnection{
+					Nodes: []model.Composition{
+						gcluster,
+					},
+					TotalCount: 1,
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -1607,7 +2329,7 @@ func TestQueryCompositions(t *testing.T) {
 
 			// Our GraphQL resolvers never return errors. We instead add an
 			// error to the GraphQL context and return early.
-			got, err := q.Compositions(tc.args.ctx, tc.args.revision, tc.args.dangling)
+			got, err := q.Compositions(tc.args.ctx, tc.args.revision, tc.args.dangling, tc.args.xrKind, tc.args.labels)
 			errs := graphql.GetErrors(tc.args.ctx)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
@@ -1622,3 +2344,967 @@ func TestQueryCompositions(t *testing.T) {
 		})
 	}
 }
+
+func TestQueryManagedResources(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	crd := kextv1.CustomResourceDefinition{
+		Spec: kextv1.CustomResourceDefinitionSpec{
+			Group: "example.org",
+			Names: kextv1.CustomResourceDefinitionNames{
+				Kind:       "Cool",
+				ListKind:   "CoolList",
+				Categories: []string{"crossplane", "managed"},
+			},
+			Versions: []kextv1.CustomResourceDefinitionVersion{{Name: "v1", Served: true}},
+		},
+	}
+	notmanaged := kextv1.CustomResourceDefinition{
+		Spec: kextv1.CustomResourceDefinitionSpec{
+			Group: "example.org",
+			Names: kextv1.CustomResourceDefinitionNames{
+				Kind:     "Uncool",
+				ListKind: "UncoolList",
+			},
+			Versions: []kextv1.CustomResourceDefinitionVersion{{Name: "v1", Served: true}},
+		},
+	}
+
+	mr := func(name, pc, ready string) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "example.org/v1",
+			"kind":       "Cool",
+			"metadata":   map[string]interface{}{"name": name},
+			"spec": map[string]interface{}{
+				"providerConfigRef": map[string]interface{}{"name": pc},
+			},
+			"status": map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Ready", "status": ready},
+				},
+			},
+		}}
+	}
+
+	a := mr("a", "default", "True")
+	b := mr("b", "other", "False")
+	ga := model.GetManagedResource(&a)
+	gb := model.GetManagedResource(&b)
+
+	list := func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+		switch l := obj.(type) {
+		case *kextv1.CustomResourceDefinitionList:
+			l.Items = []kextv1.CustomResourceDefinition{crd, notmanaged}
+		case *unstructured.UnstructuredList:
+			if l.GetKind() != "CoolList" {
+				return errors.Errorf("unexpected list kind %q", l.GetKind())
+			}
+			l.Items = []unstructured.Unstructured{b, a}
+		}
+		return nil
+	}
+
+	ready := model.ConditionStatusTrue
+	unknown := model.ConditionStatusUnknown
+
+	type args struct {
+		ctx            context.Context
+		ready          *model.ConditionStatus
+		synced         *model.ConditionStatus
+		providerConfig *string
+	}
+	type want struct {
+		mrc  *model.ManagedResourceConnection
+		err  error
+		errs gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"GetClientError": {
+			reason: "If we can't get a client we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{}, errBoom
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetClient).Error()),
+				},
+			},
+		},
+		"ListCRDsError": {
+			reason: "If we can't list CRDs we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errListCRDs).Error()),
+				},
+			},
+		},
+		"AllManagedResources": {
+			reason: "We should return all managed resources when no filters are supplied.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{MockList: list}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+			},
+			want: want{
+				mrc: &model.ManagedResourceConnection{
+					Nodes:      []model.ManagedResource{ga, gb},
+					TotalCount: 2,
+				},
+			},
+		},
+		"ReadyManagedResources": {
+			reason: "We should only return managed resources whose Ready condition matches the supplied status.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{MockList: list}, nil
+			}),
+			args: args{
+				ctx:   graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				ready: &ready,
+			},
+			want: want{
+				mrc: &model.ManagedResourceConnection{
+					Nodes:      []model.ManagedResource{ga},
+					TotalCount: 1,
+				},
+			},
+		},
+		"UnknownSyncedManagedResources": {
+			reason: "Managed resources without a Synced condition should be considered to have an unknown Synced status.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{MockList: list}, nil
+			}),
+			args: args{
+				ctx:    graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				synced: &unknown,
+			},
+			want: want{
+				mrc: &model.ManagedResourceConnection{
+					Nodes:      []model.ManagedResource{ga, gb},
+					TotalCount: 2,
+				},
+			},
+		},
+		"ProviderConfigManagedResources": {
+			reason: "We should only return managed resources that use the supplied provider config.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{MockList: list}, nil
+			}),
+			args: args{
+				ctx:            graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				providerConfig: pointer.StringPtr("other"),
+			},
+			want: want{
+				mrc: &model.ManagedResourceConnection{
+					Nodes:      []model.ManagedResource{gb},
+					TotalCount: 1,
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			q := &query{clients: tc.clients}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := q.ManagedResources(tc.args.ctx, tc.args.ready, tc.args.synced, tc.args.providerConfig, nil, nil, nil)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nq.ManagedResources(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nq.ManagedResources(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.mrc, got, cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\nq.ManagedResources(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestQueryOrphanedManagedResources(t *testing.T) {
+	crd := kextv1.CustomResourceDefinition{
+		Spec: kextv1.CustomResourceDefinitionSpec{
+			Group: "example.org",
+			Names: kextv1.CustomResourceDefinitionNames{
+				Kind:       "Cool",
+				ListKind:   "CoolList",
+				Categories: []string{"crossplane", "managed"},
+			},
+			Versions: []kextv1.CustomResourceDefinitionVersion{{Name: "v1", Served: true}},
+		},
+	}
+	xcrd := kextv1.CustomResourceDefinition{
+		Spec: kextv1.CustomResourceDefinitionSpec{
+			Group: "example.org",
+			Names: kextv1.CustomResourceDefinitionNames{
+				Kind:       "XCool",
+				ListKind:   "XCoolList",
+				Categories: []string{"crossplane", "composite"},
+			},
+			Versions: []kextv1.CustomResourceDefinitionVersion{{Name: "v1", Served: true}},
+		},
+	}
+
+	mr := func(name string, owners ...metav1.OwnerReference) unstructured.Unstructured {
+		u := unstructured.Unstructured{}
+		u.SetAPIVersion("example.org/v1")
+		u.SetKind("Cool")
+		u.SetName(name)
+		u.SetOwnerReferences(owners)
+		return u
+	}
+
+	composed := mr("composed", metav1.OwnerReference{APIVersion: "example.org/v1", Kind: "XCool", Name: "xr"})
+	direct := mr("direct")
+	other := mr("other", metav1.OwnerReference{APIVersion: "other.org/v1", Kind: "Operator", Name: "op"})
+
+	cases := map[string]struct {
+		reason string
+		list   []unstructured.Unstructured
+		want   []unstructured.Unstructured
+	}{
+		"ComposedResourcesExcluded": {
+			reason: "Managed resources owned by a composite resource should not be returned.",
+			list:   []unstructured.Unstructured{composed, direct},
+			want:   []unstructured.Unstructured{direct},
+		},
+		"OtherOwnersIncluded": {
+			reason: "Managed resources owned by something other than a composite resource should be returned.",
+			list:   []unstructured.Unstructured{other, composed},
+			want:   []unstructured.Unstructured{other},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &test.MockClient{
+				MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+					switch l := obj.(type) {
+					case *kextv1.CustomResourceDefinitionList:
+						l.Items = []kextv1.CustomResourceDefinition{crd, xcrd}
+					case *unstructured.UnstructuredList:
+						if l.GetKind() == "CoolList" {
+							l.Items = tc.list
+						}
+					}
+					return nil
+				},
+			}
+			q := &query{clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return c, nil
+			})}
+
+			ctx := graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover)
+			got, _ := q.OrphanedManagedResources(ctx, nil, nil)
+			if errs := graphql.GetErrors(ctx); errs != nil {
+				t.Errorf("\n%s\nq.OrphanedManagedResources(...): unexpected GraphQL errors: %s\n", tc.reason, errs)
+			}
+
+			want := &model.ManagedResourceConnection{Nodes: make([]model.ManagedResource, 0)}
+			for i := range tc.want {
+				want.Nodes = append(want.Nodes, model.GetManagedResource(&tc.want[i]))
+				want.TotalCount++
+			}
+			if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\nq.OrphanedManagedResources(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestQueryUnhealthyResources(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	crd := func(kind, category string) kextv1.CustomResourceDefinition {
+		return kextv1.CustomResourceDefinition{
+			Spec: kextv1.CustomResourceDefinitionSpec{
+				Group: "example.org",
+				Names: kextv1.CustomResourceDefinitionNames{
+					Kind:       kind,
+					ListKind:   kind + "List",
+					Categories: []string{"crossplane", category},
+				},
+				Versions: []kextv1.CustomResourceDefinitionVersion{{Name: "v1", Served: true, Storage: true}},
+			},
+		}
+	}
+
+	hourAgo := time.Now().Add(-1 * time.Hour).Format(time.RFC3339)
+	res := func(kind, name string, conditions ...interface{}) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "example.org/v1",
+			"kind":       kind,
+			"metadata":   map[string]interface{}{"name": name},
+			"status":     map[string]interface{}{"conditions": conditions},
+		}}
+	}
+	cond := func(ct, status, ltt string) interface{} {
+		return map[string]interface{}{"type": ct, "status": status, "lastTransitionTime": ltt}
+	}
+
+	healthy := res("Managed", "healthy", cond("Ready", "True", hourAgo), cond("Synced", "True", hourAgo))
+	unsynced := res("Managed", "unsynced", cond("Ready", "True", hourAgo), cond("Synced", "False", hourAgo))
+	unready := res("Composite", "unready", cond("Ready", "False", time.Now().Format(time.RFC3339)))
+
+	list := func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+		switch l := obj.(type) {
+		case *kextv1.CustomResourceDefinitionList:
+			l.Items = []kextv1.CustomResourceDefinition{crd("Managed", "managed"), crd("Composite", "composite"), crd("Other", "other")}
+		case *unstructured.UnstructuredList:
+			switch l.GetKind() {
+			case "ManagedList":
+				l.Items = []unstructured.Unstructured{unsynced, healthy}
+			case "CompositeList":
+				l.Items = []unstructured.Unstructured{unready}
+			default:
+				return errors.Errorf("unexpected list kind %q", l.GetKind())
+			}
+		}
+		return nil
+	}
+
+	tenMinutes := 10 * time.Minute
+
+	type want struct {
+		krc  *model.KubernetesResourceConnection
+		errs gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason     string
+		clients    ClientCache
+		conditions []model.ConditionPredicate
+		want       want
+	}{
+		"ListCRDsError": {
+			reason: "If we can't list CRDs we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{MockList: test.NewMockListFn(errBoom)}, nil
+			}),
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errListCRDs).Error()),
+				},
+			},
+		},
+		"DefaultConditions": {
+			reason: "By default we should return composite and managed resources that aren't ready or aren't synced.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{MockList: list}, nil
+			}),
+			want: want{
+				krc: &model.KubernetesResourceConnection{
+					Nodes:      []model.KubernetesResource{model.GetCompositeResource(&unready), model.GetManagedResource(&unsynced)},
+					TotalCount: 2,
+				},
+			},
+		},
+		"ConditionFor": {
+			reason: "We should only return resources whose condition has had the supplied status for the supplied duration.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{MockList: list}, nil
+			}),
+			conditions: []model.ConditionPredicate{
+				{Type: "Ready", Status: model.ConditionStatusFalse, For: &tenMinutes},
+				{Type: "Synced", Status: model.ConditionStatusTrue, For: &tenMinutes},
+			},
+			want: want{
+				krc: &model.KubernetesResourceConnection{
+					Nodes:      []model.KubernetesResource{model.GetManagedResource(&healthy)},
+					TotalCount: 1,
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			q := &query{clients: tc.clients}
+			ctx := graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover)
+
+			got, _ := q.UnhealthyResources(ctx, tc.conditions, nil, nil)
+			errs := graphql.GetErrors(ctx)
+
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nq.UnhealthyResources(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.krc, got, cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\nq.UnhealthyResources(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestQueryProviderConfigs(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	provider := model.ReferenceID{
+		APIVersion: pkgv1.ProviderGroupVersionKind.GroupVersion().String(),
+		Kind:       pkgv1.ProviderKind,
+		Name:       "provider-aws",
+	}
+
+	// A revision of our provider, which owns the CRDs it installs.
+	rev := pkgv1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{
+		Name: "provider-aws-1234",
+		UID:  "aws-rev",
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: provider.APIVersion,
+			Kind:       provider.Kind,
+			Name:       provider.Name,
+			Controller: pointer.BoolPtr(true),
+		}},
+	}}
+
+	pcCRD := func(group string, owners ...metav1.OwnerReference) kextv1.CustomResourceDefinition {
+		return kextv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "providerconfigs." + group, OwnerReferences: owners},
+			Spec: kextv1.CustomResourceDefinitionSpec{
+				Group: group,
+				Names: kextv1.CustomResourceDefinitionNames{
+					Kind:     "ProviderConfig",
+					ListKind: "ProviderConfigList",
+				},
+				Versions: []kextv1.CustomResourceDefinitionVersion{{Name: "v1", Served: true}},
+			},
+		}
+	}
+	aws := pcCRD("aws.example.org", metav1.OwnerReference{UID: rev.GetUID()})
+	gcp := pcCRD("gcp.example.org")
+	managed := kextv1.CustomResourceDefinition{
+		Spec: kextv1.CustomResourceDefinitionSpec{
+			Group: "aws.example.org",
+			Names: kextv1.CustomResourceDefinitionNames{
+				Kind:       "Cool",
+				ListKind:   "CoolList",
+				Categories: []string{"crossplane", "managed"},
+			},
+			Versions: []kextv1.CustomResourceDefinitionVersion{{Name: "v1", Served: true}},
+		},
+	}
+
+	pc := func(apiVersion string) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": apiVersion,
+			"kind":       "ProviderConfig",
+			"metadata":   map[string]interface{}{"name": "default"},
+		}}
+	}
+	awspc := pc("aws.example.org/v1")
+	gcppc := pc("gcp.example.org/v1")
+	gawspc := model.GetProviderConfig(&awspc)
+	ggcppc := model.GetProviderConfig(&gcppc)
+
+	list := func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+		switch l := obj.(type) {
+		case *pkgv1.ProviderRevisionList:
+			l.Items = []pkgv1.ProviderRevision{rev}
+		case *kextv1.CustomResourceDefinitionList:
+			l.Items = []kextv1.CustomResourceDefinition{managed, gcp, aws}
+		case *unstructured.UnstructuredList:
+			switch l.GetAPIVersion() + "/" + l.GetKind() {
+			case "aws.example.org/v1/ProviderConfigList":
+				l.Items = []unstructured.Unstructured{awspc}
+			case "gcp.example.org/v1/ProviderConfigList":
+				l.Items = []unstructured.Unstructured{gcppc}
+			default:
+				return errors.Errorf("unexpected list %s/%s", l.GetAPIVersion(), l.GetKind())
+			}
+		}
+		return nil
+	}
+
+	type args struct {
+		ctx      context.Context
+		provider *model.ReferenceID
+	}
+	type want struct {
+		pcc  *model.ProviderConfigConnection
+		err  error
+		errs gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"NotAProvider": {
+			reason: "If the supplied ID isn't a provider we should add an error to the GraphQL context and return early.",
+			args: args{
+				ctx:      graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				provider: &model.ReferenceID{APIVersion: "v1", Kind: "Pod", Name: "provider-aws"},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Errorf(errFmtNotProvider, "Pod").Error()),
+				},
+			},
+		},
+		"GetClientError": {
+			reason: "If we can't get a client we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{}, errBoom
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetClient).Error()),
+				},
+			},
+		},
+		"ListRevisionsError": {
+			reason: "If we can't list the provider's revisions we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx:      graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				provider: &provider,
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errListProviderRevs).Error()),
+				},
+			},
+		},
+		"ListCRDsError": {
+			reason: "If we can't list CRDs we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errListCRDs).Error()),
+				},
+			},
+		},
+		"AllProviderConfigs": {
+			reason: "We should return the provider configs of every provider when no provider is supplied.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{MockList: list}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+			},
+			want: want{
+				pcc: &model.ProviderConfigConnection{
+					Nodes:      []model.ProviderConfig{gawspc, ggcppc},
+					TotalCount: 2,
+				},
+			},
+		},
+		"ProviderConfigsOfProvider": {
+			reason: "We should only return the provider configs defined by CRDs that the supplied provider's revisions own.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{MockList: list}, nil
+			}),
+			args: args{
+				ctx:      graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				provider: &provider,
+			},
+			want: want{
+				pcc: &model.ProviderConfigConnection{
+					Nodes:      []model.ProviderConfig{gawspc},
+					TotalCount: 1,
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			q := &query{clients: tc.clients}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := q.ProviderConfigs(tc.args.ctx, tc.args.provider)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nq.ProviderConfigs(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nq.ProviderConfigs(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.pcc, got, cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\nq.ProviderConfigs(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestQueryLogs(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	pod := model.ReferenceID{APIVersion: "v1", Kind: "Pod", Namespace: "crossplane-system", Name: "provider-cool-1234-abcde"}
+
+	type args struct {
+		ctx          context.Context
+		id           model.ReferenceID
+		container    *string
+		tailLines    *int
+		sinceSeconds *int
+	}
+	type want struct {
+		logs *model.PodLogs
+		err  error
+		errs gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason string
+		logs   PodLogStreamer
+		args   args
+		want   want
+	}{
+		"NotAPod": {
+			reason: "If the supplied ID is not a pod we should add an error to the GraphQL context and return early.",
+			logs: PodLogStreamerFn(func(_ context.Context, _ auth.Credentials, _, _ string, _ *corev1.PodLogOptions) (io.ReadCloser, error) {
+				return io.NopCloser(strings.NewReader("")), nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				id:  model.ReferenceID{APIVersion: "v1", Kind: "Secret", Name: "cool"},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Errorf(errFmtNotPod, "Secret").Error()),
+				},
+			},
+		},
+		"LogsDisabled": {
+			reason: "If pod logs are not enabled we should add an error to the GraphQL context and return early.",
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				id:  pod,
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errLogsDisabled),
+				},
+			},
+		},
+		"StreamError": {
+			reason: "If we can't stream the pod's logs we should add the error to the GraphQL context and return early.",
+			logs: PodLogStreamerFn(func(_ context.Context, _ auth.Credentials, _, _ string, _ *corev1.PodLogOptions) (io.ReadCloser, error) {
+				return nil, errBoom
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				id:  pod,
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetLogs).Error()),
+				},
+			},
+		},
+		"Success": {
+			reason: "We should stream the requested logs of the supplied pod and return them line by line.",
+			logs: PodLogStreamerFn(func(_ context.Context, _ auth.Credentials, namespace, name string, o *corev1.PodLogOptions) (io.ReadCloser, error) {
+				if namespace != pod.Namespace || name != pod.Name {
+					t.Errorf("Stream(...): want %s/%s, got %s/%s", pod.Namespace, pod.Name, namespace, name)
+				}
+				want := &corev1.PodLogOptions{
+					Container:    "provider",
+					TailLines:    pointer.Int64Ptr(10),
+					SinceSeconds: pointer.Int64Ptr(60),
+					LimitBytes:   pointer.Int64Ptr(maxLogBytes),
+				}
+				if diff := cmp.Diff(want, o); diff != "" {
+					t.Errorf("Stream(...): -want options, +got options:\n%s", diff)
+				}
+				return io.NopCloser(strings.NewReader("hello\nworld\n")), nil
+			}),
+			args: args{
+				ctx:          graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				id:           pod,
+				container:    pointer.StringPtr("provider"),
+				tailLines:    intPtr(10),
+				sinceSeconds: intPtr(60),
+			},
+			want: want{
+				logs: &model.PodLogs{
+					Container: pointer.StringPtr("provider"),
+					Lines:     []string{"hello", "world"},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			q := &query{logs: tc.logs}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := q.Logs(tc.args.ctx, tc.args.id, tc.args.container, tc.args.tailLines, tc.args.sinceSeconds)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nq.Logs(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nq.Logs(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.logs, got); diff != "" {
+				t.Errorf("\n%s\nq.Logs(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestQueryViewer(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	withToken := func() context.Context {
+		ctx := auth.WithCredentials(context.Background(), auth.Credentials{BearerToken: "cool"})
+		return graphql.WithResponseContext(ctx, graphql.DefaultErrorPresenter, graphql.DefaultRecover)
+	}
+
+	type want struct {
+		viewer *model.Viewer
+		err    error
+		errs   gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason string
+		tokens TokenReviewer
+		ctx    context.Context
+		want   want
+	}{
+		"ViewerDisabled": {
+			reason: "If we can't review tokens we should add an error to the GraphQL context and return early.",
+			ctx:    withToken(),
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errViewerDisabled),
+				},
+			},
+		},
+		"NoBearerToken": {
+			reason: "If the caller didn't supply a bearer token we should add an error to the GraphQL context and return early.",
+			tokens: TokenReviewerFn(func(_ context.Context, _ string) (*authnv1.TokenReviewStatus, error) {
+				return nil, errBoom
+			}),
+			ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errNoBearerToken),
+				},
+			},
+		},
+		"ReviewError": {
+			reason: "If we can't review the caller's token we should add the error to the GraphQL context and return early.",
+			tokens: TokenReviewerFn(func(_ context.Context, _ string) (*authnv1.TokenReviewStatus, error) {
+				return nil, errBoom
+			}),
+			ctx: withToken(),
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errReviewToken).Error()),
+				},
+			},
+		},
+		"NotAuthenticated": {
+			reason: "If the caller's token is not authenticated we should add an error to the GraphQL context and return early.",
+			tokens: TokenReviewerFn(func(_ context.Context, _ string) (*authnv1.TokenReviewStatus, error) {
+				return &authnv1.TokenReviewStatus{Error: "token expired"}, nil
+			}),
+			ctx: withToken(),
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Errorf(errFmtNotAuthd, "token expired").Error()),
+				},
+			},
+		},
+		"Success": {
+			reason: "We should return the identity of the caller's token.",
+			tokens: TokenReviewerFn(func(_ context.Context, token string) (*authnv1.TokenReviewStatus, error) {
+				if diff := cmp.Diff("cool", token); diff != "" {
+					t.Errorf("Review(...): -want token, +got token:\n%s", diff)
+				}
+				return &authnv1.TokenReviewStatus{
+					Authenticated: true,
+					User:          authnv1.UserInfo{Username: "cool", Groups: []string{"system:authenticated"}},
+				}, nil
+			}),
+			ctx: withToken(),
+			want: want{
+				viewer: &model.Viewer{Username: "cool", Groups: []string{"system:authenticated"}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			q := &query{tokens: tc.tokens}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := q.Viewer(tc.ctx)
+			errs := graphql.GetErrors(tc.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nq.Viewer(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nq.Viewer(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.viewer, got); diff != "" {
+				t.Errorf("\n%s\nq.Viewer(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestQueryAPIResources(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	groups := []metav1.APIGroup{{Name: "", PreferredVersion: metav1.GroupVersionForDiscovery{GroupVersion: "v1", Version: "v1"}}}
+	lists := []*metav1.APIResourceList{{
+		GroupVersion: "v1",
+		APIResources: []metav1.APIResource{
+			{Name: "secrets", Kind: "Secret", Namespaced: true},
+			{Name: "configmaps", Kind: "ConfigMap", Namespaced: true},
+		},
+	}}
+
+	type want struct {
+		rc   *model.APIResourceConnection
+		err  error
+		errs gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason    string
+		discovery Discoverer
+		want      want
+	}{
+		"DiscoveryDisabled": {
+			reason: "If we can't discover API resources we should add an error to the GraphQL context and return early.",
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errDiscDisabled),
+				},
+			},
+		},
+		"DiscoverError": {
+			reason: "If discovery fails entirely we should add the error to the GraphQL context and return early.",
+			discovery: DiscovererFn(func(_ context.Context, _ auth.Credentials, _ *string) ([]metav1.APIGroup, []*metav1.APIResourceList, error) {
+				return nil, nil, errBoom
+			}),
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errDiscover).Error()),
+				},
+			},
+		},
+		"PartialDiscovery": {
+			reason: "If discovery partially succeeds we should add the error to the GraphQL context and return what we discovered.",
+			discovery: DiscovererFn(func(_ context.Context, _ auth.Credentials, _ *string) ([]metav1.APIGroup, []*metav1.APIResourceList, error) {
+				return groups, lists, errBoom
+			}),
+			want: want{
+				rc: &model.APIResourceConnection{
+					Nodes: []model.APIResource{
+						{Version: "v1", Kind: "ConfigMap", Name: "configmaps", Namespaced: true, Preferred: true},
+						{Version: "v1", Kind: "Secret", Name: "secrets", Namespaced: true, Preferred: true},
+					},
+					TotalCount: 2,
+				},
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errDiscover).Error()),
+				},
+			},
+		},
+		"Success": {
+			reason: "We should return the discovered API resources, sorted.",
+			discovery: DiscovererFn(func(_ context.Context, _ auth.Credentials, _ *string) ([]metav1.APIGroup, []*metav1.APIResourceList, error) {
+				return groups, lists, nil
+			}),
+			want: want{
+				rc: &model.APIResourceConnection{
+					Nodes: []model.APIResource{
+						{Version: "v1", Kind: "ConfigMap", Name: "configmaps", Namespaced: true, Preferred: true},
+						{Version: "v1", Kind: "Secret", Name: "secrets", Namespaced: true, Preferred: true},
+					},
+					TotalCount: 2,
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			q := &query{discovery: tc.discovery}
+			ctx := graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover)
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := q.APIResources(ctx, nil)
+			errs := graphql.GetErrors(ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nq.APIResources(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nq.APIResources(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.rc, got); diff != "" {
+				t.Errorf("\n%s\nq.APIResources(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestQueryBuildInfo(t *testing.T) {
+	q := &query{}
+	got, err := q.BuildInfo(context.Background())
+	if err != nil {
+		t.Fatalf("q.BuildInfo(...): %s", err)
+	}
+	want := &model.BuildInfo{Version: version.Version, SchemaVersion: xschema.Version()}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("q.BuildInfo(...): -want, +got:\n%s", diff)
+	}
+}
+
+func intPtr(i int) *int { return &i }
+
+type mockCategoryIndex map[schema.GroupKind][]string
+
+func (m mockCategoryIndex) Categories(gk schema.GroupKind) []string { return m[gk] }
@@ -0,0 +1,76 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolvers
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/pkg/errors"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/upbound/xgql/internal/auth"
+	"github.com/upbound/xgql/internal/graph/model"
+)
+
+const (
+	errListClusterRoles        = "cannot list cluster roles"
+	errListClusterRoleBindings = "cannot list cluster role bindings"
+	errGetClusterRole          = "cannot get cluster role"
+)
+
+type clusterRole struct {
+	clients ClientCache
+}
+
+func (r *clusterRole) Events(ctx context.Context, obj *model.ClusterRole, limit *int) (*model.EventConnection, error) {
+	e := &events{clients: r.clients}
+	return e.Resolve(ctx, involved(obj.ID, obj.Metadata), limit)
+}
+
+type clusterRoleBinding struct {
+	clients ClientCache
+}
+
+func (r *clusterRoleBinding) Events(ctx context.Context, obj *model.ClusterRoleBinding, limit *int) (*model.EventConnection, error) {
+	e := &events{clients: r.clients}
+	return e.Resolve(ctx, involved(obj.ID, obj.Metadata), limit)
+}
+
+func (r *clusterRoleBinding) ClusterRole(ctx context.Context, obj *model.ClusterRoleBinding) (*model.ClusterRole, error) {
+	if obj.RoleRef == nil || obj.RoleRef.Kind != "ClusterRole" {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	cr := &rbacv1.ClusterRole{}
+	if err := c.Get(ctx, types.NamespacedName{Name: obj.RoleRef.Name}, cr); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClusterRole))
+		return nil, nil
+	}
+
+	out := model.GetClusterRole(cr)
+	return &out, nil
+}
@@ -15,13 +15,20 @@
 package resolvers
 
 import (
+	"context"
+	"io"
 	"time"
 
+	authnv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/upbound/xgql/internal/auth"
 	"github.com/upbound/xgql/internal/clients"
 	"github.com/upbound/xgql/internal/graph/generated"
+	"github.com/upbound/xgql/internal/graph/model"
 )
 
 // Default resolver timeout.
@@ -41,29 +48,184 @@ func (fn ClientCacheFn) Get(cr auth.Credentials, o ...clients.GetOption) (client
 	return fn(cr, o...)
 }
 
+// A PodLogStreamer streams the logs of a pod.
+type PodLogStreamer interface {
+	// Stream the logs of the supplied pod using the supplied credentials.
+	Stream(ctx context.Context, cr auth.Credentials, namespace, name string, o *corev1.PodLogOptions) (io.ReadCloser, error)
+}
+
+// A PodLogStreamerFn is a function that streams the logs of a pod.
+type PodLogStreamerFn func(ctx context.Context, cr auth.Credentials, namespace, name string, o *corev1.PodLogOptions) (io.ReadCloser, error)
+
+// Stream the logs of the supplied pod using the supplied credentials.
+func (fn PodLogStreamerFn) Stream(ctx context.Context, cr auth.Credentials, namespace, name string, o *corev1.PodLogOptions) (io.ReadCloser, error) {
+	return fn(ctx, cr, namespace, name, o)
+}
+
+// A TokenReviewer reviews bearer tokens.
+type TokenReviewer interface {
+	// Review the supplied bearer token.
+	Review(ctx context.Context, token string) (*authnv1.TokenReviewStatus, error)
+}
+
+// A TokenReviewerFn is a function that reviews bearer tokens.
+type TokenReviewerFn func(ctx context.Context, token string) (*authnv1.TokenReviewStatus, error)
+
+// Review the supplied bearer token.
+func (fn TokenReviewerFn) Review(ctx context.Context, token string) (*authnv1.TokenReviewStatus, error) {
+	return fn(ctx, token)
+}
+
+// A Discoverer discovers the API resources served by the API server.
+type Discoverer interface {
+	// Discover the API groups and resources served by the API server using
+	// the supplied credentials, optionally limited to the supplied group.
+	Discover(ctx context.Context, cr auth.Credentials, group *string) ([]metav1.APIGroup, []*metav1.APIResourceList, error)
+}
+
+// A DiscovererFn is a function that discovers API resources.
+type DiscovererFn func(ctx context.Context, cr auth.Credentials, group *string) ([]metav1.APIGroup, []*metav1.APIResourceList, error)
+
+// Discover the API groups and resources served by the API server using the
+// supplied credentials, optionally limited to the supplied group.
+func (fn DiscovererFn) Discover(ctx context.Context, cr auth.Credentials, group *string) ([]metav1.APIGroup, []*metav1.APIResourceList, error) {
+	return fn(ctx, cr, group)
+}
+
+// A Watcher watches Kubernetes resources.
+type Watcher interface {
+	// Watch objects of the supplied kind using the supplied credentials.
+	Watch(ctx context.Context, cr auth.Credentials, obj client.Object, o ...clients.GetOption) (<-chan clients.WatchEvent, error)
+}
+
+// A WatcherFn is a function that watches Kubernetes resources.
+type WatcherFn func(ctx context.Context, cr auth.Credentials, obj client.Object, o ...clients.GetOption) (<-chan clients.WatchEvent, error)
+
+// Watch objects of the supplied kind using the supplied credentials.
+func (fn WatcherFn) Watch(ctx context.Context, cr auth.Credentials, obj client.Object, o ...clients.GetOption) (<-chan clients.WatchEvent, error) {
+	return fn(ctx, cr, obj, o...)
+}
+
+// An ObjectWatcher watches a single Kubernetes resource.
+type ObjectWatcher interface {
+	// WatchObject watches the supplied object using the supplied credentials.
+	WatchObject(ctx context.Context, cr auth.Credentials, gvk schema.GroupVersionKind, namespace, name string) (<-chan clients.WatchEvent, error)
+}
+
+// An ObjectWatcherFn is a function that watches a single Kubernetes resource.
+type ObjectWatcherFn func(ctx context.Context, cr auth.Credentials, gvk schema.GroupVersionKind, namespace, name string) (<-chan clients.WatchEvent, error)
+
+// WatchObject watches the supplied object using the supplied credentials.
+func (fn ObjectWatcherFn) WatchObject(ctx context.Context, cr auth.Credentials, gvk schema.GroupVersionKind, namespace, name string) (<-chan clients.WatchEvent, error) {
+	return fn(ctx, cr, gvk, namespace, name)
+}
+
+// DefaultCrossplaneNamespace is the namespace in which Crossplane runs by
+// default.
+const DefaultCrossplaneNamespace = "crossplane-system"
+
 // The Root resolver.
 type Root struct {
-	clients ClientCache
+	clients    ClientCache
+	logs       PodLogStreamer
+	tokens     TokenReviewer
+	discovery  Discoverer
+	watcher    Watcher
+	objects    ObjectWatcher
+	categories model.CategoryIndex
+	namespace  string
+}
+
+// An Option configures the root resolver.
+type Option func(r *Root)
+
+// WithPodLogs configures how the root resolver streams pod logs. Pod logs
+// are unavailable by default.
+func WithPodLogs(l PodLogStreamer) Option {
+	return func(r *Root) {
+		r.logs = l
+	}
+}
+
+// WithTokenReviewer configures how the root resolver reviews bearer tokens in
+// order to identify callers. The viewer query is unavailable by default.
+func WithTokenReviewer(tr TokenReviewer) Option {
+	return func(r *Root) {
+		r.tokens = tr
+	}
+}
+
+// WithDiscoverer configures how the root resolver discovers the API resources
+// served by the API server. The apiResources query is unavailable by default.
+func WithDiscoverer(d Discoverer) Option {
+	return func(r *Root) {
+		r.discovery = d
+	}
+}
+
+// WithWatcher configures how the root resolver watches Kubernetes resources in
+// order to resolve subscriptions. Subscriptions are unavailable by default.
+func WithWatcher(w Watcher) Option {
+	return func(r *Root) {
+		r.watcher = w
+	}
+}
+
+// WithObjectWatcher configures how the root resolver watches individual
+// Kubernetes resources in order to resolve subscriptions. Subscriptions to
+// individual resources are unavailable by default.
+func WithObjectWatcher(w ObjectWatcher) Option {
+	return func(r *Root) {
+		r.objects = w
+	}
+}
+
+// WithCategoryIndex configures the index the root resolver consults to
+// determine whether a resource is a managed resource, a composite resource, or
+// a composite resource claim. Resources are classified by their structure
+// alone by default.
+func WithCategoryIndex(ci model.CategoryIndex) Option {
+	return func(r *Root) {
+		r.categories = ci
+	}
+}
+
+// WithCrossplaneNamespace configures the namespace in which Crossplane runs,
+// which is where package pull secrets must exist. DefaultCrossplaneNamespace is
+// used by default.
+func WithCrossplaneNamespace(ns string) Option {
+	return func(r *Root) {
+		r.namespace = ns
+	}
 }
 
 // New returns a new root resolver.
-func New(cc ClientCache) *Root {
-	return &Root{clients: cc}
+func New(cc ClientCache, o ...Option) *Root {
+	r := &Root{clients: cc, namespace: DefaultCrossplaneNamespace}
+	for _, fn := range o {
+		fn(r)
+	}
+	return r
 }
 
 // Query resolves GraphQL queries.
 func (r *Root) Query() generated.QueryResolver {
-	return &query{clients: r.clients}
+	return &query{clients: r.clients, logs: r.logs, tokens: r.tokens, discovery: r.discovery, categories: r.categories}
+}
+
+// Subscription resolves GraphQL subscriptions.
+func (r *Root) Subscription() generated.SubscriptionResolver {
+	return &subscription{watcher: r.watcher, objects: r.objects, categories: r.categories}
 }
 
 // Mutation resolves GraphQL mutations.
 func (r *Root) Mutation() generated.MutationResolver {
-	return &mutation{clients: r.clients}
+	return &mutation{clients: r.clients, categories: r.categories}
 }
 
 // ObjectMeta resolves properties of the ObjectMeta GraphQL type.
 func (r *Root) ObjectMeta() generated.ObjectMetaResolver {
-	return &objectMeta{clients: r.clients}
+	return &objectMeta{clients: r.clients, categories: r.categories}
 }
 
 // Secret resolves properties of the Secret GraphQL type.
@@ -84,7 +246,7 @@ func (r *Root) CompositeResource() generated.CompositeResourceResolver {
 // CompositeResourceSpec resolves properties of the CompositeResourceSpec
 // GraphQL type.
 func (r *Root) CompositeResourceSpec() generated.CompositeResourceSpecResolver {
-	return &compositeResourceSpec{clients: r.clients}
+	return &compositeResourceSpec{clients: r.clients, categories: r.categories}
 }
 
 // CompositeResourceClaim resolves properties of the CompositeResourceClaim
@@ -96,7 +258,7 @@ func (r *Root) CompositeResourceClaim() generated.CompositeResourceClaimResolver
 // CompositeResourceClaimSpec resolves properties of the CompositeResourceClaimSpec
 // GraphQL type.
 func (r *Root) CompositeResourceClaimSpec() generated.CompositeResourceClaimSpecResolver {
-	return &compositeResourceClaimSpec{clients: r.clients}
+	return &compositeResourceClaimSpec{clients: r.clients, categories: r.categories}
 }
 
 // CompositeResourceDefinition resolves properties of the
@@ -111,16 +273,43 @@ func (r *Root) CompositeResourceDefinitionSpec() generated.CompositeResourceDefi
 	return &xrdSpec{clients: r.clients}
 }
 
+// CompositeResourceDefinitionVersion resolves properties of the
+// CompositeResourceDefinitionVersion GraphQL type.
+func (r *Root) CompositeResourceDefinitionVersion() generated.CompositeResourceDefinitionVersionResolver {
+	return &xrdVersion{clients: r.clients}
+}
+
+// ClusterRole resolves properties of the ClusterRole GraphQL type.
+func (r *Root) ClusterRole() generated.ClusterRoleResolver {
+	return &clusterRole{clients: r.clients}
+}
+
+// ClusterRoleBinding resolves properties of the ClusterRoleBinding GraphQL
+// type.
+func (r *Root) ClusterRoleBinding() generated.ClusterRoleBindingResolver {
+	return &clusterRoleBinding{clients: r.clients}
+}
+
 // Composition resolves properties of the Composition GraphQL type.
 func (r *Root) Composition() generated.CompositionResolver {
 	return &composition{clients: r.clients}
 }
 
+// Condition resolves properties of the Condition GraphQL type.
+func (r *Root) Condition() generated.ConditionResolver {
+	return &condition{now: time.Now}
+}
+
 // Configuration resolves properties of the Configuration GraphQL type.
 func (r *Root) Configuration() generated.ConfigurationResolver {
 	return &configuration{clients: r.clients}
 }
 
+// ConfigurationSpec resolves properties of the ConfigurationSpec GraphQL type.
+func (r *Root) ConfigurationSpec() generated.ConfigurationSpecResolver {
+	return &configurationSpec{clients: r.clients, namespace: r.namespace}
+}
+
 // ConfigurationRevision resolves properties of the ConfigurationRevision
 // GraphQL type.
 func (r *Root) ConfigurationRevision() generated.ConfigurationRevisionResolver {
@@ -130,18 +319,39 @@ func (r *Root) ConfigurationRevision() generated.ConfigurationRevisionResolver {
 // ConfigurationRevisionStatus resolves properties of the
 // ConfigurationRevisionStatus GraphQL type.
 func (r *Root) ConfigurationRevisionStatus() generated.ConfigurationRevisionStatusResolver {
-	return &configurationRevisionStatus{clients: r.clients}
+	return &configurationRevisionStatus{clients: r.clients, categories: r.categories}
 }
 
 // CustomResourceDefinition resolves properties of the CustomResourceDefinition
 // GraphQL type.
 func (r *Root) CustomResourceDefinition() generated.CustomResourceDefinitionResolver {
-	return &crd{clients: r.clients}
+	return &crd{clients: r.clients, categories: r.categories}
+}
+
+// Deployment resolves properties of the Deployment GraphQL type.
+func (r *Root) Deployment() generated.DeploymentResolver {
+	return &deployment{clients: r.clients}
+}
+
+// DeploymentRuntimeConfig resolves properties of the DeploymentRuntimeConfig
+// GraphQL type.
+func (r *Root) DeploymentRuntimeConfig() generated.DeploymentRuntimeConfigResolver {
+	return &deploymentRuntimeConfig{clients: r.clients}
 }
 
 // Event resolves properties of the Event GraphQL type.
 func (r *Root) Event() generated.EventResolver {
-	return &event{clients: r.clients}
+	return &event{clients: r.clients, categories: r.categories}
+}
+
+// Function resolves properties of the Function GraphQL type.
+func (r *Root) Function() generated.FunctionResolver {
+	return &function{clients: r.clients}
+}
+
+// FunctionSpec resolves properties of the FunctionSpec GraphQL type.
+func (r *Root) FunctionSpec() generated.FunctionSpecResolver {
+	return &functionSpec{clients: r.clients}
 }
 
 // GenericResource resolves properties of the GenericResource GraphQL type.
@@ -149,6 +359,11 @@ func (r *Root) GenericResource() generated.GenericResourceResolver {
 	return &genericResource{clients: r.clients}
 }
 
+// Lock resolves properties of the Lock GraphQL type.
+func (r *Root) Lock() generated.LockResolver {
+	return &lock{clients: r.clients}
+}
+
 // ManagedResource resolves properties of the CustomResourceDefinition GraphQL
 // type.
 func (r *Root) ManagedResource() generated.ManagedResourceResolver {
@@ -161,11 +376,27 @@ func (r *Root) ManagedResourceSpec() generated.ManagedResourceSpecResolver {
 	return &managedResourceSpec{clients: r.clients}
 }
 
+// ManagedResourceStatus resolves properties of the ManagedResourceStatus
+// GraphQL type.
+func (r *Root) ManagedResourceStatus() generated.ManagedResourceStatusResolver {
+	return &managedResourceStatus{}
+}
+
+// Pod resolves properties of the Pod GraphQL type.
+func (r *Root) Pod() generated.PodResolver {
+	return &pod{clients: r.clients}
+}
+
 // Provider resolves properties of the Provider GraphQL type.
 func (r *Root) Provider() generated.ProviderResolver {
 	return &provider{clients: r.clients}
 }
 
+// ProviderSpec resolves properties of the ProviderSpec GraphQL type.
+func (r *Root) ProviderSpec() generated.ProviderSpecResolver {
+	return &providerSpec{clients: r.clients, namespace: r.namespace}
+}
+
 // ProviderRevision resolves properties of the ProviderRevision GraphQL type.
 func (r *Root) ProviderRevision() generated.ProviderRevisionResolver {
 	return &providerRevision{clients: r.clients}
@@ -181,3 +412,20 @@ func (r *Root) ProviderRevisionStatus() generated.ProviderRevisionStatusResolver
 func (r *Root) ProviderConfig() generated.ProviderConfigResolver {
 	return &providerConfig{clients: r.clients}
 }
+
+// RevisionObjectDiff resolves properties of the RevisionObjectDiff GraphQL
+// type.
+func (r *Root) RevisionObjectDiff() generated.RevisionObjectDiffResolver {
+	return &revisionObjectDiff{clients: r.clients, categories: r.categories}
+}
+
+// StoreConfig resolves properties of the StoreConfig GraphQL type.
+func (r *Root) StoreConfig() generated.StoreConfigResolver {
+	return &storeConfig{clients: r.clients}
+}
+
+// PublishConnectionDetailsTo resolves properties of the
+// PublishConnectionDetailsTo GraphQL type.
+func (r *Root) PublishConnectionDetailsTo() generated.PublishConnectionDetailsToResolver {
+	return &publishConnectionDetailsTo{clients: r.clients}
+}
@@ -0,0 +1,84 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolvers
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/upbound/xgql/internal/auth"
+	"github.com/upbound/xgql/internal/graph/model"
+	"github.com/upbound/xgql/internal/unstructured"
+)
+
+const (
+	errGetStoreConfig   = "cannot get store config"
+	errListStoreConfigs = "cannot list store configs"
+)
+
+type storeConfig struct {
+	clients ClientCache
+}
+
+func (r *storeConfig) Events(ctx context.Context, obj *model.StoreConfig, limit *int) (*model.EventConnection, error) {
+	e := &events{clients: r.clients}
+	return e.Resolve(ctx, involved(obj.ID, obj.Metadata), limit)
+}
+
+type publishConnectionDetailsTo struct {
+	clients ClientCache
+}
+
+func (r *publishConnectionDetailsTo) StoreConfig(ctx context.Context, obj *model.PublishConnectionDetailsTo) (*model.StoreConfig, error) {
+	if obj.ConfigRef == nil || len(obj.StoreConfigAPIVersions) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := r.clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	// Try each API version at which the store config may be served, in order,
+	// skipping those that aren't served or don't have it.
+	var u *kunstructured.Unstructured
+	for _, v := range obj.StoreConfigAPIVersions {
+		u = &kunstructured.Unstructured{}
+		u.SetAPIVersion(v)
+		u.SetKind(unstructured.KindStoreConfig)
+		err = c.Get(ctx, types.NamespacedName{Name: obj.ConfigRef.Name}, u)
+		if !kmeta.IsNoMatchError(err) && !kerrors.IsNotFound(err) {
+			break
+		}
+	}
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetStoreConfig))
+		return nil, nil
+	}
+
+	out := model.GetStoreConfig(u)
+	return &out, nil
+}
@@ -0,0 +1,190 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolvers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/upbound/xgql/internal/auth"
+	"github.com/upbound/xgql/internal/clients"
+	"github.com/upbound/xgql/internal/graph/generated"
+	"github.com/upbound/xgql/internal/graph/model"
+	"github.com/upbound/xgql/internal/unstructured"
+)
+
+var (
+	_ generated.StoreConfigResolver                = &storeConfig{}
+	_ generated.PublishConnectionDetailsToResolver = &publishConnectionDetailsTo{}
+)
+
+func TestPublishConnectionDetailsToStoreConfig(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	sc := kunstructured.Unstructured{}
+	sc.SetAPIVersion(unstructured.APIVersionSecrets)
+	sc.SetKind(unstructured.KindStoreConfig)
+	sc.SetName("vault")
+	gsc := model.GetStoreConfig(&sc)
+
+	psc := kunstructured.Unstructured{}
+	psc.SetAPIVersion("aws.upbound.io/v1alpha1")
+	psc.SetKind(unstructured.KindStoreConfig)
+	psc.SetName("vault")
+	gpsc := model.GetStoreConfig(&psc)
+
+	type args struct {
+		ctx context.Context
+		obj *model.PublishConnectionDetailsTo
+	}
+	type want struct {
+		sc   *model.StoreConfig
+		err  error
+		errs gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		args    args
+		want    want
+	}{
+		"UnknownAPIVersion": {
+			reason: "If we don't know the API version of the store config we should return early.",
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.PublishConnectionDetailsTo{
+					ConfigRef: &model.StoreConfigReference{Name: "vault"},
+				},
+			},
+		},
+		"GetClientError": {
+			reason: "If we can't get a client we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return nil, errBoom
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.PublishConnectionDetailsTo{
+					ConfigRef:              &model.StoreConfigReference{Name: "vault"},
+					StoreConfigAPIVersions: []string{unstructured.APIVersionSecrets},
+				},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetClient).Error()),
+				},
+			},
+		},
+		"GetStoreConfigError": {
+			reason: "If we can't get the store config we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.PublishConnectionDetailsTo{
+					ConfigRef:              &model.StoreConfigReference{Name: "vault"},
+					StoreConfigAPIVersions: []string{unstructured.APIVersionSecrets},
+				},
+			},
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetStoreConfig).Error()),
+				},
+			},
+		},
+		"Success": {
+			reason: "If we can get the store config we should return it.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						*obj.(*kunstructured.Unstructured) = sc
+						return nil
+					}),
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.PublishConnectionDetailsTo{
+					ConfigRef:              &model.StoreConfigReference{Name: "vault"},
+					StoreConfigAPIVersions: []string{unstructured.APIVersionSecrets},
+				},
+			},
+			want: want{
+				sc: &gsc,
+			},
+		},
+		"ProviderStoreConfig": {
+			reason: "If the store config isn't served at the first API version we should try the next.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+						if obj.GetObjectKind().GroupVersionKind().GroupVersion() != psc.GroupVersionKind().GroupVersion() {
+							return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+						}
+						*obj.(*kunstructured.Unstructured) = psc
+						return nil
+					},
+				}, nil
+			}),
+			args: args{
+				ctx: graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover),
+				obj: &model.PublishConnectionDetailsTo{
+					ConfigRef:              &model.StoreConfigReference{Name: "vault"},
+					StoreConfigAPIVersions: unstructured.ProviderStoreConfigAPIVersions("ec2.aws.upbound.io"),
+				},
+			},
+			want: want{
+				sc: &gpsc,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := &publishConnectionDetailsTo{clients: tc.clients}
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := p.StoreConfig(tc.args.ctx, tc.args.obj)
+			errs := graphql.GetErrors(tc.args.ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\np.StoreConfig(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\np.StoreConfig(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.sc, got, cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\np.StoreConfig(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,289 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolvers
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+
+	"github.com/upbound/xgql/internal/auth"
+	"github.com/upbound/xgql/internal/clients"
+	"github.com/upbound/xgql/internal/graph/model"
+)
+
+const (
+	errWatchDisabled = "subscriptions are not enabled"
+	errWatchEvents   = "cannot watch events"
+	errWatchResource = "cannot watch Kubernetes resource"
+)
+
+// NOTE(negz): Unlike queries and mutations our subscription resolvers return
+// errors rather than adding them to the GraphQL context. gqlgen only reports
+// the errors of a subscription that fails to start if they're returned.
+
+type subscription struct {
+	watcher    Watcher
+	objects    ObjectWatcher
+	categories model.CategoryIndex
+}
+
+func (r *subscription) Events(ctx context.Context, involved *model.ReferenceID, namespace *string, typeArg *model.EventType) (<-chan *model.Event, error) {
+	if r.watcher == nil {
+		return nil, errors.New(errWatchDisabled)
+	}
+
+	creds, _ := auth.FromContext(ctx)
+	in, err := r.watcher.Watch(ctx, creds, &corev1.Event{})
+	if err != nil {
+		return nil, errors.Wrap(err, errWatchEvents)
+	}
+
+	var ref *corev1.ObjectReference
+	if involved != nil {
+		ref = &corev1.ObjectReference{
+			APIVersion: involved.APIVersion,
+			Kind:       involved.Kind,
+			Namespace:  involved.Namespace,
+			Name:       involved.Name,
+		}
+	}
+
+	// Watches start by replaying all extant events. Event timestamps have
+	// second granularity, so we truncate our start time to match.
+	started := time.Now().Truncate(time.Second)
+
+	out := make(chan *model.Event)
+	go func() {
+		defer close(out)
+		for {
+			var we clients.WatchEvent
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-in:
+				if !ok {
+					return
+				}
+				we = e
+			}
+
+			e, ok := we.Object.(*corev1.Event)
+			switch {
+			case !ok, we.Type == watch.Deleted:
+				continue
+			case lastSeen(e).Before(started):
+				continue
+			case ref != nil && !involves(e, ref):
+				continue
+			case namespace != nil && e.GetNamespace() != *namespace:
+				continue
+			}
+
+			m := model.GetEvent(e)
+			if typeArg != nil && (m.Type == nil || *m.Type != *typeArg) {
+				continue
+			}
+
+			select {
+			case out <- &m:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+func (r *subscription) WatchResource(ctx context.Context, id model.ReferenceID) (<-chan model.KubernetesResource, error) {
+	if r.objects == nil {
+		return nil, errors.New(errWatchDisabled)
+	}
+
+	creds, _ := auth.FromContext(ctx)
+	gvk := schema.FromAPIVersionAndKind(id.APIVersion, id.Kind)
+	in, err := r.objects.WatchObject(ctx, creds, gvk, id.Namespace, id.Name)
+	if err != nil {
+		return nil, errors.Wrap(err, errWatchResource)
+	}
+
+	out := make(chan model.KubernetesResource)
+	go func() {
+		defer close(out)
+		for {
+			var we clients.WatchEvent
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-in:
+				if !ok {
+					return
+				}
+				we = e
+			}
+
+			u, ok := we.Object.(*unstructured.Unstructured)
+			if !ok {
+				continue
+			}
+			kr, err := model.GetKubernetesResource(u, r.categories)
+			if err != nil {
+				// There's no way to surface an error without ending the
+				// subscription, so we just skip the update.
+				continue
+			}
+
+			select {
+			case out <- kr:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+func (r *subscription) WatchConditions(ctx context.Context, id model.ReferenceID) (<-chan *model.ConditionsChange, error) {
+	if r.objects == nil {
+		return nil, errors.New(errWatchDisabled)
+	}
+
+	creds, _ := auth.FromContext(ctx)
+	gvk := schema.FromAPIVersionAndKind(id.APIVersion, id.Kind)
+	in, err := r.objects.WatchObject(ctx, creds, gvk, id.Namespace, id.Name)
+	if err != nil {
+		return nil, errors.Wrap(err, errWatchResource)
+	}
+
+	out := make(chan *model.ConditionsChange)
+	go func() {
+		defer close(out)
+
+		// prev is nil until we've seen the resource, so that we always stream
+		// its initial conditions.
+		var prev []xpv1.Condition
+		for {
+			var we clients.WatchEvent
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-in:
+				if !ok {
+					return
+				}
+				we = e
+			}
+
+			u, ok := we.Object.(*unstructured.Unstructured)
+			if !ok || we.Type == watch.Deleted {
+				continue
+			}
+
+			cs := &xpv1.ConditionedStatus{}
+			// The path is directly `status` because conditions are inline.
+			_ = fieldpath.Pave(u.Object).GetValueInto("status", cs)
+
+			changed, removed := diffConditions(prev, cs.Conditions)
+			if prev != nil && len(changed) == 0 && len(removed) == 0 {
+				continue
+			}
+			prev = cs.Conditions
+			if prev == nil {
+				prev = []xpv1.Condition{}
+			}
+
+			kr, err := model.GetKubernetesResource(u, r.categories)
+			if err != nil {
+				// There's no way to surface an error without ending the
+				// subscription, so we just skip the update.
+				continue
+			}
+
+			c := &model.ConditionsChange{
+				Resource:   kr,
+				Conditions: nonNil(model.GetConditions(cs.Conditions)),
+				Changed:    nonNil(model.GetConditions(changed)),
+				Removed:    removed,
+			}
+
+			select {
+			case out <- c:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// diffConditions returns the conditions in cur that are not in prev, or whose
+// status or reason differs from that in prev, and the types of any conditions
+// in prev that are not in cur. Changes to only a condition's message or last
+// transition time are not considered changes.
+func diffConditions(prev, cur []xpv1.Condition) ([]xpv1.Condition, []string) {
+	seen := make(map[xpv1.ConditionType]xpv1.Condition, len(prev))
+	for _, c := range prev {
+		seen[c.Type] = c
+	}
+
+	changed := make([]xpv1.Condition, 0)
+	for _, c := range cur {
+		p, ok := seen[c.Type]
+		delete(seen, c.Type)
+		if ok && p.Status == c.Status && p.Reason == c.Reason {
+			continue
+		}
+		changed = append(changed, c)
+	}
+
+	removed := make([]string, 0, len(seen))
+	for _, c := range prev {
+		if _, ok := seen[c.Type]; ok {
+			removed = append(removed, string(c.Type))
+		}
+	}
+
+	return changed, removed
+}
+
+func nonNil(in []model.Condition) []model.Condition {
+	if in == nil {
+		return []model.Condition{}
+	}
+	return in
+}
+
+// lastSeen returns the last time the supplied event occurred.
+func lastSeen(e *corev1.Event) time.Time {
+	switch {
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	default:
+		return e.GetCreationTimestamp().Time
+	}
+}
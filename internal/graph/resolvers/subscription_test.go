@@ -0,0 +1,363 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolvers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/upbound/xgql/internal/auth"
+	"github.com/upbound/xgql/internal/clients"
+	"github.com/upbound/xgql/internal/graph/generated"
+	"github.com/upbound/xgql/internal/graph/model"
+)
+
+var _ generated.SubscriptionResolver = &subscription{}
+
+func TestSubscriptionEvents(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	now := metav1.NewTime(time.Now().Add(1 * time.Minute))
+	old := metav1.NewTime(time.Now().Add(-1 * time.Hour))
+
+	involved := corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "Cool", Name: "cool"}
+
+	current := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: "default", Name: "current"},
+		InvolvedObject: involved,
+		Type:           corev1.EventTypeWarning,
+		LastTimestamp:  now,
+	}
+	stale := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: "default", Name: "stale"},
+		InvolvedObject: involved,
+		Type:           corev1.EventTypeWarning,
+		LastTimestamp:  old,
+	}
+	normal := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: "default", Name: "normal"},
+		InvolvedObject: involved,
+		Type:           corev1.EventTypeNormal,
+		LastTimestamp:  now,
+	}
+	elsewhere := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: "elsewhere", Name: "elsewhere"},
+		InvolvedObject: involved,
+		Type:           corev1.EventTypeWarning,
+		LastTimestamp:  now,
+	}
+	unrelated := &corev1.Event{
+		ObjectMeta:    metav1.ObjectMeta{Namespace: "default", Name: "unrelated"},
+		Type:          corev1.EventTypeWarning,
+		LastTimestamp: now,
+	}
+
+	all := []clients.WatchEvent{
+		{Type: watch.Added, Object: stale},
+		{Type: watch.Added, Object: current},
+		{Type: watch.Modified, Object: normal},
+		{Type: watch.Added, Object: elsewhere},
+		{Type: watch.Added, Object: unrelated},
+		{Type: watch.Deleted, Object: current},
+	}
+
+	gcurrent := model.GetEvent(current)
+	gnormal := model.GetEvent(normal)
+	gelsewhere := model.GetEvent(elsewhere)
+	gunrelated := model.GetEvent(unrelated)
+	warning := model.EventTypeWarning
+
+	watcher := WatcherFn(func(_ context.Context, _ auth.Credentials, _ client.Object, _ ...clients.GetOption) (<-chan clients.WatchEvent, error) {
+		ch := make(chan clients.WatchEvent, len(all))
+		for _, e := range all {
+			ch <- e
+		}
+		close(ch)
+		return ch, nil
+	})
+
+	type args struct {
+		involved  *model.ReferenceID
+		namespace *string
+		typeArg   *model.EventType
+	}
+	type want struct {
+		events []*model.Event
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		watcher Watcher
+		args    args
+		want    want
+	}{
+		"WatchDisabled": {
+			reason: "If subscriptions are not enabled we should return an error.",
+			want: want{
+				err: errors.New(errWatchDisabled),
+			},
+		},
+		"WatchError": {
+			reason: "If we can't watch events we should return an error.",
+			watcher: WatcherFn(func(_ context.Context, _ auth.Credentials, _ client.Object, _ ...clients.GetOption) (<-chan clients.WatchEvent, error) {
+				return nil, errBoom
+			}),
+			want: want{
+				err: errors.Wrap(errBoom, errWatchEvents),
+			},
+		},
+		"AllEvents": {
+			reason:  "We should stream all events that occurred after the subscription started.",
+			watcher: watcher,
+			want: want{
+				events: []*model.Event{&gcurrent, &gnormal, &gelsewhere, &gunrelated},
+			},
+		},
+		"FilteredEvents": {
+			reason:  "We should only stream events that pass all of the supplied filters.",
+			watcher: watcher,
+			args: args{
+				involved:  &model.ReferenceID{APIVersion: involved.APIVersion, Kind: involved.Kind, Name: involved.Name},
+				namespace: &current.Namespace,
+				typeArg:   &warning,
+			},
+			want: want{
+				events: []*model.Event{&gcurrent},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := &subscription{watcher: tc.watcher}
+			ch, err := s.Events(context.Background(), tc.args.involved, tc.args.namespace, tc.args.typeArg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.Events(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+
+			got := []*model.Event{}
+			for e := range ch {
+				got = append(got, e)
+			}
+			if diff := cmp.Diff(tc.want.events, got, cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\ns.Events(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSubscriptionWatchResource(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	id := model.ReferenceID{APIVersion: "example.org/v1", Kind: "Cool", Namespace: "default", Name: "cool"}
+
+	u := &kunstructured.Unstructured{}
+	u.SetAPIVersion(id.APIVersion)
+	u.SetKind(id.Kind)
+	u.SetNamespace(id.Namespace)
+	u.SetName(id.Name)
+	gu, _ := model.GetKubernetesResource(u, nil)
+
+	type want struct {
+		resources []model.KubernetesResource
+		err       error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		objects ObjectWatcher
+		want    want
+	}{
+		"WatchDisabled": {
+			reason: "If subscriptions are not enabled we should return an error.",
+			want: want{
+				err: errors.New(errWatchDisabled),
+			},
+		},
+		"WatchError": {
+			reason: "If we can't watch the resource we should return an error.",
+			objects: ObjectWatcherFn(func(_ context.Context, _ auth.Credentials, _ schema.GroupVersionKind, _, _ string) (<-chan clients.WatchEvent, error) {
+				return nil, errBoom
+			}),
+			want: want{
+				err: errors.Wrap(errBoom, errWatchResource),
+			},
+		},
+		"Success": {
+			reason: "We should stream the modelled resource each time it changes.",
+			objects: ObjectWatcherFn(func(_ context.Context, _ auth.Credentials, gvk schema.GroupVersionKind, namespace, name string) (<-chan clients.WatchEvent, error) {
+				if diff := cmp.Diff(id, model.ReferenceID{APIVersion: gvk.GroupVersion().String(), Kind: gvk.Kind, Namespace: namespace, Name: name}); diff != "" {
+					t.Errorf("WatchObject(...): -want, +got:\n%s", diff)
+				}
+				ch := make(chan clients.WatchEvent, 3)
+				ch <- clients.WatchEvent{Type: watch.Added, Object: u}
+				ch <- clients.WatchEvent{Type: watch.Modified, Object: &corev1.Event{}}
+				ch <- clients.WatchEvent{Type: watch.Deleted, Object: u}
+				close(ch)
+				return ch, nil
+			}),
+			want: want{
+				resources: []model.KubernetesResource{gu, gu},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := &subscription{objects: tc.objects}
+			ch, err := s.WatchResource(context.Background(), id)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.WatchResource(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+
+			got := []model.KubernetesResource{}
+			for kr := range ch {
+				got = append(got, kr)
+			}
+			if diff := cmp.Diff(tc.want.resources, got, cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\ns.WatchResource(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSubscriptionWatchConditions(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	id := model.ReferenceID{APIVersion: "example.org/v1", Kind: "Cool", Name: "cool"}
+
+	withConditions := func(c ...xpv1.Condition) *kunstructured.Unstructured {
+		u := &kunstructured.Unstructured{}
+		u.SetAPIVersion(id.APIVersion)
+		u.SetKind(id.Kind)
+		u.SetName(id.Name)
+		_ = fieldpath.Pave(u.Object).SetValue("status.conditions", c)
+		return u
+	}
+
+	synced := xpv1.ReconcileSuccess()
+	creating := xpv1.Creating()
+	available := xpv1.Available()
+	chatty := xpv1.Creating()
+	chatty.Message = "still creating"
+
+	initial := withConditions(creating, synced)
+	noop := withConditions(chatty, synced)
+	ready := withConditions(available, synced)
+	unsynced := withConditions(available)
+
+	change := func(u *kunstructured.Unstructured, all, changed []xpv1.Condition, removed ...string) *model.ConditionsChange {
+		kr, _ := model.GetKubernetesResource(u, nil)
+		if removed == nil {
+			removed = []string{}
+		}
+		return &model.ConditionsChange{
+			Resource:   kr,
+			Conditions: nonNil(model.GetConditions(all)),
+			Changed:    nonNil(model.GetConditions(changed)),
+			Removed:    removed,
+		}
+	}
+
+	type want struct {
+		changes []*model.ConditionsChange
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		objects ObjectWatcher
+		want    want
+	}{
+		"WatchDisabled": {
+			reason: "If subscriptions are not enabled we should return an error.",
+			want: want{
+				err: errors.New(errWatchDisabled),
+			},
+		},
+		"WatchError": {
+			reason: "If we can't watch the resource we should return an error.",
+			objects: ObjectWatcherFn(func(_ context.Context, _ auth.Credentials, _ schema.GroupVersionKind, _, _ string) (<-chan clients.WatchEvent, error) {
+				return nil, errBoom
+			}),
+			want: want{
+				err: errors.Wrap(errBoom, errWatchResource),
+			},
+		},
+		"Success": {
+			reason: "We should stream the initial conditions, then only changes to the type, status, or reason of conditions.",
+			objects: ObjectWatcherFn(func(_ context.Context, _ auth.Credentials, _ schema.GroupVersionKind, _, _ string) (<-chan clients.WatchEvent, error) {
+				ch := make(chan clients.WatchEvent, 5)
+				ch <- clients.WatchEvent{Type: watch.Added, Object: initial}
+				ch <- clients.WatchEvent{Type: watch.Modified, Object: noop}
+				ch <- clients.WatchEvent{Type: watch.Modified, Object: ready}
+				ch <- clients.WatchEvent{Type: watch.Modified, Object: unsynced}
+				ch <- clients.WatchEvent{Type: watch.Deleted, Object: unsynced}
+				close(ch)
+				return ch, nil
+			}),
+			want: want{
+				changes: []*model.ConditionsChange{
+					change(initial, []xpv1.Condition{creating, synced}, []xpv1.Condition{creating, synced}),
+					change(ready, []xpv1.Condition{available, synced}, []xpv1.Condition{available}),
+					change(unsynced, []xpv1.Condition{available}, nil, string(xpv1.TypeSynced)),
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := &subscription{objects: tc.objects}
+			ch, err := s.WatchConditions(context.Background(), id)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.WatchConditions(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+
+			got := []*model.ConditionsChange{}
+			for c := range ch {
+				got = append(got, c)
+			}
+			if diff := cmp.Diff(tc.want.changes, got, cmpopts.IgnoreUnexported(model.ObjectMeta{}), cmpopts.EquateApproxTime(time.Second)); diff != "" {
+				t.Errorf("\n%s\ns.WatchConditions(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolvers
+
+import (
+	"context"
+	"sort"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/upbound/xgql/internal/auth"
+	"github.com/upbound/xgql/internal/graph/model"
+)
+
+const (
+	errListDeployments = "cannot list deployments"
+	errListPods        = "cannot list pods"
+)
+
+// The package manager labels the pods (and in recent versions of Crossplane
+// the deployments) that run a package revision with the revision's name.
+const labelRevision = "pkg.crossplane.io/revision"
+
+type deployment struct {
+	clients ClientCache
+}
+
+func (r *deployment) Events(ctx context.Context, obj *model.Deployment, limit *int) (*model.EventConnection, error) {
+	e := &events{clients: r.clients}
+	return e.Resolve(ctx, involved(obj.ID, obj.Metadata), limit)
+}
+
+type pod struct {
+	clients ClientCache
+}
+
+func (r *pod) Events(ctx context.Context, obj *model.Pod, limit *int) (*model.EventConnection, error) {
+	e := &events{clients: r.clients}
+	return e.Resolve(ctx, involved(obj.ID, obj.Metadata), limit)
+}
+
+// revisionDeployment returns the deployment that runs the supplied package
+// revision, if any.
+func revisionDeployment(ctx context.Context, clients ClientCache, rev *model.ObjectMeta) (*model.Deployment, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	in := &appsv1.DeploymentList{}
+	if err := c.List(ctx, in); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errListDeployments))
+		return nil, nil
+	}
+
+	for i := range in.Items {
+		d := in.Items[i] // So we don't take the address of a range variable.
+
+		// Older versions of Crossplane don't label the deployment, but it is
+		// always controlled by the revision it runs.
+		// https://github.com/kubernetes/community/blob/0331e/contributors/design-proposals/api-machinery/controller-ref.md
+		ctrl := metav1.GetControllerOf(&d)
+		if d.GetLabels()[labelRevision] != rev.Name && (ctrl == nil || ctrl.UID != types.UID(rev.UID)) {
+			continue
+		}
+
+		out := model.GetDeployment(&d)
+		return &out, nil
+	}
+
+	return nil, nil
+}
+
+// revisionPods returns the pods that run the supplied package revision.
+func revisionPods(ctx context.Context, clients ClientCache, rev *model.ObjectMeta) (*model.PodConnection, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds, _ := auth.FromContext(ctx)
+	c, err := clients.Get(creds)
+	if err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errGetClient))
+		return nil, nil
+	}
+
+	in := &corev1.PodList{}
+	if err := c.List(ctx, in, client.MatchingLabels{labelRevision: rev.Name}); err != nil {
+		graphql.AddError(ctx, errors.Wrap(err, errListPods))
+		return nil, nil
+	}
+
+	out := &model.PodConnection{
+		Nodes:      make([]model.Pod, len(in.Items)),
+		TotalCount: len(in.Items),
+	}
+	for i := range in.Items {
+		out.Nodes[i] = model.GetPod(&in.Items[i])
+	}
+
+	sort.Stable(out)
+	return out, nil
+}
@@ -0,0 +1,252 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolvers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/upbound/xgql/internal/auth"
+	"github.com/upbound/xgql/internal/clients"
+	"github.com/upbound/xgql/internal/graph/generated"
+	"github.com/upbound/xgql/internal/graph/model"
+)
+
+var (
+	_ generated.DeploymentResolver = &deployment{}
+	_ generated.PodResolver        = &pod{}
+)
+
+func TestRevisionDeployment(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	rev := &model.ObjectMeta{Name: "provider-cool-1234", UID: "no-you-id"}
+
+	other := appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "other"}}
+	labelled := appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name:   "labelled",
+		Labels: map[string]string{labelRevision: rev.Name},
+	}}
+	controlled := appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name: "controlled",
+		OwnerReferences: []metav1.OwnerReference{{
+			UID:        types.UID(rev.UID),
+			Controller: pointer.BoolPtr(true),
+		}},
+	}}
+
+	glabelled := model.GetDeployment(&labelled)
+	gcontrolled := model.GetDeployment(&controlled)
+
+	type want struct {
+		d    *model.Deployment
+		err  error
+		errs gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		want    want
+	}{
+		"GetClientError": {
+			reason: "If we can't get a client we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return nil, errBoom
+			}),
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetClient).Error()),
+				},
+			},
+		},
+		"ListDeploymentsError": {
+			reason: "If we can't list deployments we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(errBoom),
+				}, nil
+			}),
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errListDeployments).Error()),
+				},
+			},
+		},
+		"NoDeployment": {
+			reason: "If no deployment runs the revision we should return nil.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						*obj.(*appsv1.DeploymentList) = appsv1.DeploymentList{Items: []appsv1.Deployment{other}}
+						return nil
+					}),
+				}, nil
+			}),
+			want: want{},
+		},
+		"LabelledDeployment": {
+			reason: "We should return a deployment labelled with the revision's name.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						*obj.(*appsv1.DeploymentList) = appsv1.DeploymentList{Items: []appsv1.Deployment{other, labelled}}
+						return nil
+					}),
+				}, nil
+			}),
+			want: want{
+				d: &glabelled,
+			},
+		},
+		"ControlledDeployment": {
+			reason: "We should return a deployment controlled by the revision.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						*obj.(*appsv1.DeploymentList) = appsv1.DeploymentList{Items: []appsv1.Deployment{other, controlled}}
+						return nil
+					}),
+				}, nil
+			}),
+			want: want{
+				d: &gcontrolled,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover)
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := revisionDeployment(ctx, tc.clients, rev)
+			errs := graphql.GetErrors(ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nrevisionDeployment(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nrevisionDeployment(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.d, got, cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\nrevisionDeployment(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRevisionPods(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	rev := &model.ObjectMeta{Name: "provider-cool-1234"}
+
+	a := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "crossplane-system", Name: "a"}}
+	b := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "crossplane-system", Name: "b"}}
+
+	type want struct {
+		pc   *model.PodConnection
+		err  error
+		errs gqlerror.List
+	}
+
+	cases := map[string]struct {
+		reason  string
+		clients ClientCache
+		want    want
+	}{
+		"GetClientError": {
+			reason: "If we can't get a client we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return nil, errBoom
+			}),
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errGetClient).Error()),
+				},
+			},
+		},
+		"ListPodsError": {
+			reason: "If we can't list pods we should add the error to the GraphQL context and return early.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: test.NewMockListFn(errBoom),
+				}, nil
+			}),
+			want: want{
+				errs: gqlerror.List{
+					gqlerror.Errorf(errors.Wrap(errBoom, errListPods).Error()),
+				},
+			},
+		},
+		"Success": {
+			reason: "We should return the pods labelled with the revision's name, sorted by ID.",
+			clients: ClientCacheFn(func(_ auth.Credentials, _ ...clients.GetOption) (client.Client, error) {
+				return &test.MockClient{
+					MockList: func(_ context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+						want := []client.ListOption{client.MatchingLabels{labelRevision: rev.Name}}
+						if diff := cmp.Diff(want, opts); diff != "" {
+							t.Errorf("-want options, +got options:\n%s", diff)
+						}
+						*obj.(*corev1.PodList) = corev1.PodList{Items: []corev1.Pod{b, a}}
+						return nil
+					},
+				}, nil
+			}),
+			want: want{
+				pc: &model.PodConnection{
+					Nodes:      []model.Pod{model.GetPod(&a), model.GetPod(&b)},
+					TotalCount: 2,
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover)
+
+			// Our GraphQL resolvers never return errors. We instead add an
+			// error to the GraphQL context and return early.
+			got, err := revisionPods(ctx, tc.clients, rev)
+			errs := graphql.GetErrors(ctx)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nrevisionPods(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.errs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nrevisionPods(...): -want GraphQL errors, +got GraphQL errors:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.pc, got, cmpopts.IgnoreUnexported(model.ObjectMeta{})); diff != "" {
+				t.Errorf("\n%s\nrevisionPods(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package loadshed rejects new GraphQL queries while xgql is overloaded, so
+// that it degrades gracefully rather than running out of memory.
+package loadshed
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"github.com/upbound/xgql/internal/graph/present"
+)
+
+// CodeRetryable is the code of errors returned for operations that were shed.
+// Callers may retry them, ideally with backoff.
+const CodeRetryable = "RETRYABLE"
+
+const errOverloaded = "server is overloaded; please retry later"
+
+// How often heap usage is sampled. Reading memory statistics briefly stops
+// the world, so we don't do it for every operation.
+const sampleInterval = 1 * time.Second
+
+// A Shedder is a GraphQL server extension that rejects new queries with a
+// retryable error while too many queries are in flight, or while heap usage
+// is too high. Mutations and subscriptions are never rejected; mutations are
+// rarely retried safely, and subscriptions are long-lived and cheap once
+// established. Requests that aren't GraphQL operations, such as health checks,
+// are unaffected.
+type Shedder struct {
+	maxInFlight int64
+	maxHeap     uint64
+
+	inflight int64
+
+	mx       sync.Mutex
+	sampled  time.Time
+	heap     uint64
+	now      func() time.Time
+	readHeap func() uint64
+}
+
+// New returns a Shedder that rejects queries while more than maxInFlight
+// queries are in flight, or while more than maxHeap bytes of heap are in use.
+// Zero disables either threshold.
+func New(maxInFlight int, maxHeap uint64) *Shedder {
+	return &Shedder{
+		maxInFlight: int64(maxInFlight),
+		maxHeap:     maxHeap,
+		now:         time.Now,
+		readHeap: func() uint64 {
+			ms := &runtime.MemStats{}
+			runtime.ReadMemStats(ms)
+			return ms.HeapAlloc
+		},
+	}
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationInterceptor
+} = &Shedder{}
+
+// ExtensionName returns the name of this extension.
+func (s *Shedder) ExtensionName() string {
+	return "LoadShedder"
+}
+
+// Validate this extension (a no-op).
+func (s *Shedder) Validate(_ graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptOperation rejects queries while xgql is overloaded, and tracks how
+// many queries are in flight.
+func (s *Shedder) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	oc := graphql.GetOperationContext(ctx)
+	if oc.Operation == nil || oc.Operation.Operation != ast.Query {
+		return next(ctx)
+	}
+
+	if s.Overloaded() {
+		err := &gqlerror.Error{Message: errOverloaded, Extensions: map[string]interface{}{present.Code: CodeRetryable}}
+		return graphql.OneShot(&graphql.Response{Errors: gqlerror.List{err}})
+	}
+
+	atomic.AddInt64(&s.inflight, 1)
+	rh := next(ctx)
+	var once sync.Once
+	return func(ctx context.Context) *graphql.Response {
+		defer once.Do(func() { atomic.AddInt64(&s.inflight, -1) })
+		return rh(ctx)
+	}
+}
+
+// Overloaded returns true if either threshold has been crossed.
+func (s *Shedder) Overloaded() bool {
+	if s.maxInFlight > 0 && atomic.LoadInt64(&s.inflight) >= s.maxInFlight {
+		return true
+	}
+	return s.maxHeap > 0 && s.heapInUse() > s.maxHeap
+}
+
+func (s *Shedder) heapInUse() uint64 {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	if now := s.now(); now.Sub(s.sampled) >= sampleInterval {
+		s.heap = s.readHeap()
+		s.sampled = now
+	}
+	return s.heap
+}
@@ -0,0 +1,135 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadshed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"github.com/upbound/xgql/internal/graph/present"
+)
+
+func TestInterceptOperation(t *testing.T) {
+	ok := &graphql.Response{}
+	shed := &graphql.Response{Errors: gqlerror.List{{Message: errOverloaded, Extensions: map[string]interface{}{present.Code: CodeRetryable}}}}
+
+	cases := map[string]struct {
+		reason   string
+		s        *Shedder
+		op       ast.Operation
+		inflight int64
+		heap     uint64
+		want     *graphql.Response
+	}{
+		"Disabled": {
+			reason:   "Queries should never be shed if both thresholds are disabled.",
+			s:        New(0, 0),
+			op:       ast.Query,
+			inflight: 100,
+			heap:     1 << 30,
+			want:     ok,
+		},
+		"UnderThresholds": {
+			reason:   "Queries should be executed while under both thresholds.",
+			s:        New(10, 1<<30),
+			op:       ast.Query,
+			inflight: 9,
+			heap:     1 << 20,
+			want:     ok,
+		},
+		"TooManyInFlight": {
+			reason:   "Queries should be shed while too many queries are in flight.",
+			s:        New(10, 0),
+			op:       ast.Query,
+			inflight: 10,
+			want:     shed,
+		},
+		"TooMuchHeap": {
+			reason: "Queries should be shed while too much heap is in use.",
+			s:      New(0, 1<<20),
+			op:     ast.Query,
+			heap:   1 << 30,
+			want:   shed,
+		},
+		"Mutation": {
+			reason:   "Mutations should never be shed.",
+			s:        New(10, 1<<20),
+			op:       ast.Mutation,
+			inflight: 10,
+			heap:     1 << 30,
+			want:     ok,
+		},
+		"Subscription": {
+			reason:   "Subscriptions should never be shed.",
+			s:        New(10, 1<<20),
+			op:       ast.Subscription,
+			inflight: 10,
+			heap:     1 << 30,
+			want:     ok,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.s.inflight = tc.inflight
+			tc.s.readHeap = func() uint64 { return tc.heap }
+
+			ctx := graphql.WithOperationContext(context.Background(), &graphql.OperationContext{
+				Operation: &ast.OperationDefinition{Operation: tc.op},
+			})
+			next := func(_ context.Context) graphql.ResponseHandler {
+				return graphql.OneShot(ok)
+			}
+
+			got := tc.s.InterceptOperation(ctx, next)(ctx)
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreUnexported(gqlerror.Error{})); diff != "" {
+				t.Errorf("\n%s\nInterceptOperation(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.inflight, tc.s.inflight); diff != "" {
+				t.Errorf("\n%s\nInterceptOperation(...): -want in flight, +got in flight:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestHeapSampling(t *testing.T) {
+	now := time.Now()
+	reads := 0
+	s := New(0, 1<<20)
+	s.now = func() time.Time { return now }
+	s.readHeap = func() uint64 {
+		reads++
+		return 1 << 30
+	}
+
+	s.Overloaded()
+	s.Overloaded()
+	if reads != 1 {
+		t.Errorf("Overloaded(): want heap read once within the sample interval, got %d reads", reads)
+	}
+
+	now = now.Add(sampleInterval)
+	s.Overloaded()
+	if reads != 2 {
+		t.Errorf("Overloaded(): want heap read again after the sample interval, got %d reads", reads)
+	}
+}
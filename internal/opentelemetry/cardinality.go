@@ -0,0 +1,88 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opentelemetry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+)
+
+// Labels used in place of operation names.
+const (
+	LabelUnnamed = "unnamed"
+	LabelOther   = "other"
+)
+
+// The number of hex characters of a hash appended to truncated names.
+const hashLength = 8
+
+// An OperationLabeler bounds the cardinality of the operation names used to
+// label metrics. Callers choose operation names, so without bounds a caller
+// could create an unbounded number of metric series.
+type OperationLabeler struct {
+	maxOperations int
+	maxLength     int
+
+	mx   sync.Mutex
+	seen map[string]bool
+}
+
+// NewOperationLabeler returns an OperationLabeler that labels at most
+// maxOperations distinct operations. Operations seen after that are labelled
+// 'other'. Names longer than maxLength are truncated, and suffixed with a hash
+// of the full name so that they remain distinct. Zero disables either limit.
+func NewOperationLabeler(maxOperations, maxLength int) *OperationLabeler {
+	return &OperationLabeler{maxOperations: maxOperations, maxLength: maxLength, seen: make(map[string]bool)}
+}
+
+// Label returns the label of the supplied operation name. Unnamed operations
+// are labelled 'unnamed'. A nil OperationLabeler labels operations by name.
+func (l *OperationLabeler) Label(name string) string {
+	if name == "" {
+		return LabelUnnamed
+	}
+	if l == nil {
+		return name
+	}
+	if l.maxLength > 0 && len(name) > l.maxLength {
+		name = truncate(name, l.maxLength)
+	}
+	if l.maxOperations <= 0 {
+		return name
+	}
+
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	if l.seen[name] {
+		return name
+	}
+	if len(l.seen) >= l.maxOperations {
+		return LabelOther
+	}
+	l.seen[name] = true
+	return name
+}
+
+// truncate the supplied name to max characters, including a hash of the full
+// name.
+func truncate(name string, max int) string {
+	sum := sha256.Sum256([]byte(name))
+	h := hex.EncodeToString(sum[:])[:hashLength]
+	if max <= hashLength+1 {
+		return h
+	}
+	return name[:max-hashLength-1] + "-" + h
+}
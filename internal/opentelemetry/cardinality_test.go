@@ -0,0 +1,72 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opentelemetry
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOperationLabeler(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		l      *OperationLabeler
+		names  []string
+		want   []string
+	}{
+		"Nil": {
+			reason: "A nil labeler should label operations by name, except for unnamed operations.",
+			l:      nil,
+			names:  []string{"ListProviders", ""},
+			want:   []string{"ListProviders", LabelUnnamed},
+		},
+		"MaxOperations": {
+			reason: "Operations seen after the maximum should be labelled other, unless they've been seen before.",
+			l:      NewOperationLabeler(2, 0),
+			names:  []string{"A", "B", "C", "A", ""},
+			want:   []string{"A", "B", LabelOther, "A", LabelUnnamed},
+		},
+		"MaxLength": {
+			reason: "Long names should be truncated and suffixed with a hash of the full name.",
+			l:      NewOperationLabeler(0, 12),
+			names:  []string{"Short", "VeryLongOperationName", "VeryLongOperationNameToo"},
+			want:   []string{"Short", truncate("VeryLongOperationName", 12), truncate("VeryLongOperationNameToo", 12)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := make([]string, len(tc.names))
+			for i, n := range tc.names {
+				got[i] = tc.l.Label(n)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nl.Label(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	a := truncate("VeryLongOperationName", 12)
+	b := truncate("VeryLongOperationNameToo", 12)
+	if len(a) != 12 {
+		t.Errorf("truncate(...): want 12 characters, got %q", a)
+	}
+	if a == b {
+		t.Errorf("truncate(...): want distinct names to remain distinct, got %q for both", a)
+	}
+}
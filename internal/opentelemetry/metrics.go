@@ -25,7 +25,11 @@ import (
 )
 
 // A MetricEmitter that exports OpenTelemetry metrics.
-type MetricEmitter struct{}
+type MetricEmitter struct {
+	// Operations labels the operations metrics pertain to. Operations are
+	// labelled by name if it's nil.
+	Operations *OperationLabeler
+}
 
 var _ interface {
 	graphql.HandlerExtension
@@ -77,7 +81,7 @@ func (t MetricEmitter) Validate(schema graphql.ExecutableSchema) error {
 func (t MetricEmitter) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
 	if graphql.HasOperationContext(ctx) {
 		oc := graphql.GetOperationContext(ctx)
-		reqStarted.Add(ctx, 1, operation.String(oc.OperationName))
+		reqStarted.Add(ctx, 1, operation.String(t.Operations.Label(oc.OperationName)))
 	}
 	return next(ctx)
 }
@@ -88,8 +92,9 @@ func (t MetricEmitter) InterceptResponse(ctx context.Context, next graphql.Respo
 		errs := graphql.GetErrors(ctx)
 		oc := graphql.GetOperationContext(ctx)
 		ms := time.Since(oc.Stats.OperationStart).Milliseconds()
-		reqCompleted.Add(ctx, 1, operation.String(oc.OperationName), success.Bool(len(errs) > 0))
-		reqDuration.Record(ctx, ms, operation.String(oc.OperationName), success.Bool(len(errs) > 0))
+		op := operation.String(t.Operations.Label(oc.OperationName))
+		reqCompleted.Add(ctx, 1, op, success.Bool(len(errs) > 0))
+		reqDuration.Record(ctx, ms, op, success.Bool(len(errs) > 0))
 	}
 
 	return next(ctx)
@@ -0,0 +1,65 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opentelemetry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// An operationSampler samples the spans of particular GraphQL operations at
+// their own ratio.
+type operationSampler struct {
+	fallback   sdktrace.Sampler
+	operations map[string]sdktrace.Sampler
+}
+
+// OperationSampler returns a sampler that samples the span of each supplied
+// GraphQL operation at the supplied ratio, regardless of whether its parent
+// (i.e. the HTTP request that carried the operation) was sampled. All other
+// spans, including the spans of the fields an operation resolves, are sampled
+// by the fallback sampler. The fallback should usually respect the decision
+// of a span's parent, so that fields are sampled along with their operation.
+func OperationSampler(fallback sdktrace.Sampler, ratios map[string]float64) sdktrace.Sampler {
+	s := operationSampler{fallback: fallback, operations: make(map[string]sdktrace.Sampler, len(ratios))}
+	for name, r := range ratios {
+		s.operations[name] = sdktrace.TraceIDRatioBased(r)
+	}
+	return s
+}
+
+func (s operationSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	for _, a := range p.Attributes {
+		if a.Key != operation {
+			continue
+		}
+		if o, ok := s.operations[a.Value.AsString()]; ok {
+			return o.ShouldSample(p)
+		}
+	}
+	return s.fallback.ShouldSample(p)
+}
+
+func (s operationSampler) Description() string {
+	ops := make([]string, 0, len(s.operations))
+	for name, o := range s.operations {
+		ops = append(ops, name+":"+o.Description())
+	}
+	sort.Strings(ops)
+	return fmt.Sprintf("OperationSampler{%s,fallback:%s}", strings.Join(ops, ","), s.fallback.Description())
+}
@@ -0,0 +1,67 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opentelemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestOperationSampler(t *testing.T) {
+	s := OperationSampler(sdktrace.NeverSample(), map[string]float64{
+		"always": 1,
+		"never":  0,
+	})
+
+	cases := map[string]struct {
+		reason string
+		attrs  []attribute.KeyValue
+		want   sdktrace.SamplingDecision
+	}{
+		"NotAnOperation": {
+			reason: "Spans that aren't operations should be sampled by the fallback sampler.",
+			attrs:  []attribute.KeyValue{field.String("always")},
+			want:   sdktrace.Drop,
+		},
+		"UnknownOperation": {
+			reason: "Operations without a ratio should be sampled by the fallback sampler.",
+			attrs:  []attribute.KeyValue{operation.String("other")},
+			want:   sdktrace.Drop,
+		},
+		"AlwaysSampled": {
+			reason: "Operations should be sampled at their ratio.",
+			attrs:  []attribute.KeyValue{query.String("{}"), operation.String("always")},
+			want:   sdktrace.RecordAndSample,
+		},
+		"NeverSampled": {
+			reason: "Operations should be sampled at their ratio.",
+			attrs:  []attribute.KeyValue{operation.String("never")},
+			want:   sdktrace.Drop,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := s.ShouldSample(sdktrace.SamplingParameters{ParentContext: context.Background(), Attributes: tc.attrs})
+			if diff := cmp.Diff(tc.want, got.Decision); diff != "" {
+				t.Errorf("\n%s\ns.ShouldSample(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
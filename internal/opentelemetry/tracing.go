@@ -52,8 +52,13 @@ func (t Tracer) InterceptResponse(ctx context.Context, next graphql.ResponseHand
 		return next(ctx)
 	}
 
+	// The operation attribute must be supplied when the span is started, so
+	// that OperationSampler can sample the span by operation.
 	oc := graphql.GetOperationContext(ctx)
-	ctx, span := tracer.Start(ctx, operationName(oc), trace.WithAttributes(query.String(oc.RawQuery)))
+	ctx, span := tracer.Start(ctx, operationName(oc), trace.WithAttributes(
+		operation.String(oc.OperationName),
+		query.String(oc.RawQuery),
+	))
 	defer span.End()
 	if !span.IsRecording() {
 		return next(ctx)
@@ -0,0 +1,201 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package proxy routes GraphQL requests to one of many control planes, so that
+// a single xgql may front many (e.g. hosted) Crossplane control planes.
+package proxy
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/rest"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+// A request may identify the control plane it is intended for using either
+// this header or this query parameter.
+const (
+	HeaderControlPlane = "X-Control-Plane"
+	QueryControlPlane  = "controlPlane"
+)
+
+const (
+	errMissingControlPlane = "request must identify a control plane using the " + HeaderControlPlane + " header or the " + QueryControlPlane + " query parameter"
+	errFmtUnknown          = "unknown control plane %q"
+	errFmtUnavailable      = "control plane %q is unavailable"
+	errStopped             = "router is stopped"
+)
+
+// ControlPlane returns the identifier of the control plane the supplied request
+// is intended for, or an empty string if it does not identify one.
+func ControlPlane(r *http.Request) string {
+	if id := r.Header.Get(HeaderControlPlane); id != "" {
+		return id
+	}
+	return r.URL.Query().Get(QueryControlPlane)
+}
+
+// A Backend serves GraphQL requests for a single control plane.
+type Backend interface {
+	http.Handler
+
+	// Stop the backend, releasing any resources (e.g. caches) it holds.
+	Stop()
+}
+
+// A NewBackendFn returns a Backend for the supplied control plane.
+type NewBackendFn func(id string, cfg *rest.Config) (Backend, error)
+
+// A Router routes each request to the backend for the control plane it is
+// intended for. Backends are created the first time a control plane is used,
+// and are not shared between control planes; each has its own client cache.
+type Router struct {
+	configs    map[string]*rest.Config
+	newBackend NewBackendFn
+	fallback   http.Handler
+	log        logging.Logger
+
+	mx       sync.Mutex
+	backends map[string]Backend
+	pending  map[string]*pending
+	stopped  bool
+}
+
+// A pending backend is being created. Requests for its control plane wait for
+// it to be created rather than creating their own.
+type pending struct {
+	done chan struct{}
+	b    Backend
+	err  error
+}
+
+// A RouterOption configures a Router.
+type RouterOption func(r *Router)
+
+// WithFallback configures the handler that serves requests that do not
+// identify a control plane. Such requests are rejected by default.
+func WithFallback(h http.Handler) RouterOption {
+	return func(r *Router) {
+		r.fallback = h
+	}
+}
+
+// WithLogger configures the logger used by the Router.
+func WithLogger(l logging.Logger) RouterOption {
+	return func(r *Router) {
+		r.log = l
+	}
+}
+
+// NewRouter returns a Router that routes requests to the supplied control
+// planes, which are keyed by their identifiers.
+func NewRouter(configs map[string]*rest.Config, fn NewBackendFn, o ...RouterOption) *Router {
+	r := &Router{
+		configs:    configs,
+		newBackend: fn,
+		log:        logging.NewNopLogger(),
+		backends:   make(map[string]Backend),
+		pending:    make(map[string]*pending),
+	}
+	for _, fn := range o {
+		fn(r)
+	}
+	return r
+}
+
+// ServeHTTP routes the supplied request to its control plane's backend.
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	id := ControlPlane(req)
+	if id == "" {
+		if r.fallback == nil {
+			http.Error(w, errMissingControlPlane, http.StatusBadRequest)
+			return
+		}
+		r.fallback.ServeHTTP(w, req)
+		return
+	}
+
+	cfg, ok := r.configs[id]
+	if !ok {
+		http.Error(w, fmt.Sprintf(errFmtUnknown, id), http.StatusNotFound)
+		return
+	}
+
+	b, err := r.backend(id, cfg)
+	if err != nil {
+		r.log.Info("Cannot create control plane backend", "control-plane", id, "error", err)
+		http.Error(w, fmt.Sprintf(errFmtUnavailable, id), http.StatusBadGateway)
+		return
+	}
+	b.ServeHTTP(w, req)
+}
+
+// backend returns the backend for the supplied control plane, creating it if
+// necessary. Backends that can't be created are retried on the next request.
+// Creating a backend may be slow (e.g. if its control plane is unreachable), so
+// it happens without holding the lock; requests for other control planes are
+// not blocked.
+func (r *Router) backend(id string, cfg *rest.Config) (Backend, error) {
+	r.mx.Lock()
+	if r.stopped {
+		r.mx.Unlock()
+		return nil, errors.New(errStopped)
+	}
+	if b, ok := r.backends[id]; ok {
+		r.mx.Unlock()
+		return b, nil
+	}
+	if p, ok := r.pending[id]; ok {
+		r.mx.Unlock()
+		<-p.done
+		return p.b, p.err
+	}
+	p := &pending{done: make(chan struct{})}
+	r.pending[id] = p
+	r.mx.Unlock()
+
+	p.b, p.err = r.newBackend(id, cfg)
+
+	r.mx.Lock()
+	delete(r.pending, id)
+	switch {
+	case p.err != nil:
+	case r.stopped:
+		// We were stopped while the backend was being created.
+		p.b.Stop()
+		p.b, p.err = nil, errors.New(errStopped)
+	default:
+		r.backends[id] = p.b
+	}
+	r.mx.Unlock()
+	close(p.done)
+
+	return p.b, p.err
+}
+
+// Stop all backends. Requests routed after the Router is stopped fail.
+func (r *Router) Stop() {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	r.stopped = true
+	for id, b := range r.backends {
+		b.Stop()
+		delete(r.backends, id)
+	}
+}
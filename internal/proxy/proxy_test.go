@@ -0,0 +1,234 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/rest"
+)
+
+type backend struct {
+	http.Handler
+	stopped bool
+}
+
+func (b *backend) Stop() { b.stopped = true }
+
+// named returns a backend that responds with the supplied control plane ID.
+func named(id string) *backend {
+	return &backend{Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(id))
+	})}
+}
+
+func TestControlPlane(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		r      func() *http.Request
+		want   string
+	}{
+		"None": {
+			reason: "A request that does not identify a control plane should return an empty string.",
+			r:      func() *http.Request { return httptest.NewRequest(http.MethodPost, "/query", nil) },
+			want:   "",
+		},
+		"Header": {
+			reason: "A control plane may be identified by header.",
+			r: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/query", nil)
+				r.Header.Set(HeaderControlPlane, "cool")
+				return r
+			},
+			want: "cool",
+		},
+		"QueryParameter": {
+			reason: "A control plane may be identified by query parameter.",
+			r:      func() *http.Request { return httptest.NewRequest(http.MethodGet, "/query?controlPlane=cool", nil) },
+			want:   "cool",
+		},
+		"HeaderTakesPrecedence": {
+			reason: "The header should take precedence over the query parameter.",
+			r: func() *http.Request {
+				r := httptest.NewRequest(http.MethodGet, "/query?controlPlane=lame", nil)
+				r.Header.Set(HeaderControlPlane, "cool")
+				return r
+			},
+			want: "cool",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ControlPlane(tc.r())
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nControlPlane(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRouter(t *testing.T) {
+	errBoom := errors.New("boom")
+	configs := map[string]*rest.Config{"cool": {}, "broken": {}}
+	newBackend := func(id string, _ *rest.Config) (Backend, error) {
+		if id == "broken" {
+			return nil, errBoom
+		}
+		return named(id), nil
+	}
+
+	type want struct {
+		code int
+		body string
+	}
+
+	cases := map[string]struct {
+		reason string
+		o      []RouterOption
+		id     string
+		want   want
+	}{
+		"MissingControlPlane": {
+			reason: "A request that does not identify a control plane should be rejected.",
+			want:   want{code: http.StatusBadRequest},
+		},
+		"Fallback": {
+			reason: "A request that does not identify a control plane should be served by the fallback, if any.",
+			o:      []RouterOption{WithFallback(named("fallback"))},
+			want:   want{code: http.StatusOK, body: "fallback"},
+		},
+		"UnknownControlPlane": {
+			reason: "A request for an unknown control plane should be rejected.",
+			id:     "lame",
+			want:   want{code: http.StatusNotFound},
+		},
+		"BackendError": {
+			reason: "A request for a control plane whose backend can't be created should be rejected.",
+			id:     "broken",
+			want:   want{code: http.StatusBadGateway},
+		},
+		"Routed": {
+			reason: "A request should be served by its control plane's backend.",
+			id:     "cool",
+			want:   want{code: http.StatusOK, body: "cool"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/query", nil)
+			if tc.id != "" {
+				r.Header.Set(HeaderControlPlane, tc.id)
+			}
+			w := httptest.NewRecorder()
+			NewRouter(configs, newBackend, tc.o...).ServeHTTP(w, r)
+
+			if diff := cmp.Diff(tc.want.code, w.Code); diff != "" {
+				t.Errorf("\n%s\nServeHTTP(...): -want status code, +got status code:\n%s", tc.reason, diff)
+			}
+			if tc.want.code != http.StatusOK {
+				return
+			}
+			if diff := cmp.Diff(tc.want.body, w.Body.String()); diff != "" {
+				t.Errorf("\n%s\nServeHTTP(...): -want body, +got body:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRouterStop(t *testing.T) {
+	created := 0
+	b := named("cool")
+	rt := NewRouter(map[string]*rest.Config{"cool": {}}, func(_ string, _ *rest.Config) (Backend, error) {
+		created++
+		return b, nil
+	})
+
+	for i := 0; i < 3; i++ {
+		r := httptest.NewRequest(http.MethodPost, "/query", nil)
+		r.Header.Set(HeaderControlPlane, "cool")
+		rt.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	rt.Stop()
+
+	// Each control plane's backend should be created once, and stopped.
+	if diff := cmp.Diff(1, created); diff != "" {
+		t.Errorf("ServeHTTP(...): -want backends created, +got backends created:\n%s", diff)
+	}
+	if !b.stopped {
+		t.Errorf("Stop(): backend was not stopped")
+	}
+}
+
+func TestRouterSlowBackend(t *testing.T) {
+	created := make(chan string, 3)
+	release := make(chan struct{})
+	rt := NewRouter(map[string]*rest.Config{"slow": {}, "cool": {}}, func(id string, _ *rest.Config) (Backend, error) {
+		created <- id
+		if id == "slow" {
+			<-release
+		}
+		return named(id), nil
+	})
+
+	serve := func(id string) string {
+		r := httptest.NewRequest(http.MethodPost, "/query", nil)
+		r.Header.Set(HeaderControlPlane, id)
+		w := httptest.NewRecorder()
+		rt.ServeHTTP(w, r)
+		return w.Body.String()
+	}
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if diff := cmp.Diff("slow", serve("slow")); diff != "" {
+				t.Errorf("ServeHTTP(...): -want body, +got body:\n%s", diff)
+			}
+		}()
+	}
+	<-created
+
+	// Creating one control plane's backend should not block requests for
+	// another.
+	done := make(chan string)
+	go func() { done <- serve("cool") }()
+	select {
+	case got := <-done:
+		if diff := cmp.Diff("cool", got); diff != "" {
+			t.Errorf("ServeHTTP(...): -want body, +got body:\n%s", diff)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("ServeHTTP(...): request was blocked by another control plane's backend")
+	}
+	<-created
+
+	close(release)
+	wg.Wait()
+
+	// Concurrent requests for a control plane should create one backend.
+	if diff := cmp.Diff(0, len(created)); diff != "" {
+		t.Errorf("ServeHTTP(...): -want extra backends created, +got extra backends created:\n%s", diff)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package querycost reports what it cost to resolve a GraphQL operation via
+// the response's extensions, so that callers can optimize their queries.
+package querycost
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/complexity"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/upbound/xgql/internal/clients"
+)
+
+const extension = "cost"
+
+// A Cost is what it cost to resolve an operation.
+type Cost struct {
+	// Complexity of the operation, computed from its selections. Each field
+	// has a complexity of one, plus the complexity of its selections.
+	Complexity int `json:"complexity"`
+
+	// APICalls made to the Kubernetes API server.
+	APICalls int64 `json:"apiCalls"`
+
+	// CacheHits are reads served by a cache that was already populated.
+	CacheHits int64 `json:"cacheHits"`
+
+	// CacheMisses are reads that had to populate a cache. Each is also an
+	// API call.
+	CacheMisses int64 `json:"cacheMisses"`
+}
+
+// Extension is a GraphQL server extension that reports the cost of each query
+// and mutation in the response's extensions. Subscriptions are not reported;
+// their cost accrues for as long as they're open.
+type Extension struct {
+	es graphql.ExecutableSchema
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationInterceptor
+} = &Extension{}
+
+// ExtensionName returns the name of this extension.
+func (e *Extension) ExtensionName() string {
+	return "QueryCost"
+}
+
+// Validate records the schema used to compute the complexity of operations.
+func (e *Extension) Validate(es graphql.ExecutableSchema) error {
+	e.es = es
+	return nil
+}
+
+// InterceptOperation counts the calls clients make while resolving queries and
+// mutations, and reports them along with the operation's complexity once the
+// operation has been resolved.
+func (e *Extension) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	oc := graphql.GetOperationContext(ctx)
+	if oc.Operation == nil || oc.Operation.Operation == ast.Subscription {
+		return next(ctx)
+	}
+
+	c := 0
+	if e.es != nil {
+		c = complexity.Calculate(e.es, oc.Operation, oc.Variables)
+	}
+
+	u := &clients.Usage{}
+	rh := next(clients.WithUsage(ctx, u))
+	return func(ctx context.Context) *graphql.Response {
+		rsp := rh(ctx)
+		if rsp == nil {
+			return rsp
+		}
+		if rsp.Extensions == nil {
+			rsp.Extensions = map[string]interface{}{}
+		}
+		rsp.Extensions[extension] = Cost{
+			Complexity:  c,
+			APICalls:    u.APICalls(),
+			CacheHits:   u.CacheHits(),
+			CacheMisses: u.CacheMisses(),
+		}
+		return rsp
+	}
+}
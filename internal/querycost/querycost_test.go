@@ -0,0 +1,65 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querycost
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/google/go-cmp/cmp"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestInterceptOperation(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		op     ast.Operation
+		want   map[string]interface{}
+	}{
+		"Query": {
+			reason: "The cost of a query should be reported.",
+			op:     ast.Query,
+			want:   map[string]interface{}{extension: Cost{}},
+		},
+		"Mutation": {
+			reason: "The cost of a mutation should be reported.",
+			op:     ast.Mutation,
+			want:   map[string]interface{}{extension: Cost{}},
+		},
+		"Subscription": {
+			reason: "The cost of a subscription should not be reported.",
+			op:     ast.Subscription,
+			want:   nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := graphql.WithOperationContext(context.Background(), &graphql.OperationContext{
+				Operation: &ast.OperationDefinition{Operation: tc.op},
+			})
+			next := func(ctx context.Context) graphql.ResponseHandler {
+				return func(ctx context.Context) *graphql.Response { return &graphql.Response{} }
+			}
+
+			e := &Extension{}
+			rsp := e.InterceptOperation(ctx, next)(ctx)
+			if diff := cmp.Diff(tc.want, rsp.Extensions); diff != "" {
+				t.Errorf("\n%s\ne.InterceptOperation(...): -want extensions, +got extensions:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
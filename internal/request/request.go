@@ -0,0 +1,134 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package request propagates the identity of a GraphQL request to the API
+// server calls made while resolving it, so that API server audit logs can be
+// correlated with the GraphQL requests that triggered them.
+package request
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// HeaderAuditID is the HTTP header the API server uses as the audit ID of a
+// request. The API server will generate an audit ID if this header is not
+// supplied.
+const HeaderAuditID = "Audit-ID"
+
+type key int
+
+const operationKey key = iota
+
+// WithOperation returns a copy of the supplied context that is annotated with
+// the supplied GraphQL operation name.
+func WithOperation(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, operationKey, name)
+}
+
+// Operation returns the GraphQL operation name annotated on the supplied
+// context, if any.
+func Operation(ctx context.Context) string {
+	op, _ := ctx.Value(operationKey).(string)
+	return op
+}
+
+// MaxIDLength is the maximum length of a request ID that will be propagated to
+// the API server.
+const MaxIDLength = 128
+
+// ID returns the request ID annotated on the supplied context, if any. Request
+// IDs are annotated by chi's RequestID middleware, which uses the ID supplied
+// by the caller if there is one. A caller may not supply an ID that is longer
+// than MaxIDLength, or that contains characters other than letters, digits,
+// and '-', '_', '.', ':', or '/'; an empty string is returned if it does. The
+// IDs chi generates satisfy these constraints.
+func ID(ctx context.Context) string {
+	id := middleware.GetReqID(ctx)
+	if !validID(id) {
+		return ""
+	}
+	return id
+}
+
+func validID(id string) bool {
+	if len(id) > MaxIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':', c == '/':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// AroundOperations is a GraphQL operation middleware that annotates the
+// operation context with the name of the operation being executed.
+func AroundOperations(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	oc := graphql.GetOperationContext(ctx)
+	return next(WithOperation(ctx, oc.OperationName))
+}
+
+// UserAgent returns the supplied user agent, suffixed with the request ID and
+// GraphQL operation name annotated on the supplied context. The user agent is
+// returned unchanged if the context is not annotated.
+func UserAgent(ctx context.Context, ua string) string {
+	id, op := ID(ctx), Operation(ctx)
+	switch {
+	case id != "" && op != "":
+		return fmt.Sprintf("%s (request/%s; operation/%s)", ua, id, op)
+	case id != "":
+		return fmt.Sprintf("%s (request/%s)", ua, id)
+	case op != "":
+		return fmt.Sprintf("%s (operation/%s)", ua, op)
+	}
+	return ua
+}
+
+// Transport wraps the supplied HTTP round tripper such that any request made
+// with a context that is annotated with a request ID or GraphQL operation
+// name will propagate them to the API server. The request ID is used as the
+// audit ID of the API server request, and both are appended to its user agent.
+// It is intended to be used as a REST config's WrapTransport.
+func Transport(rt http.RoundTripper) http.RoundTripper {
+	return &transport{wrapped: rt}
+}
+
+type transport struct {
+	wrapped http.RoundTripper
+}
+
+func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	ctx := r.Context()
+	id, op := ID(ctx), Operation(ctx)
+	if id == "" && op == "" {
+		return t.wrapped.RoundTrip(r)
+	}
+
+	// A RoundTripper must not modify the request it is passed.
+	out := r.Clone(ctx)
+	out.Header.Set("User-Agent", UserAgent(ctx, r.Header.Get("User-Agent")))
+	if id != "" {
+		out.Header.Set(HeaderAuditID, id)
+	}
+	return t.wrapped.RoundTrip(out)
+}
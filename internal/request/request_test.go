@@ -0,0 +1,117 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package request
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/go-cmp/cmp"
+)
+
+type roundTripperFn func(r *http.Request) (*http.Response, error)
+
+func (fn roundTripperFn) RoundTrip(r *http.Request) (*http.Response, error) { return fn(r) }
+
+func TestTransport(t *testing.T) {
+	ua := "xgql/v0.1.0"
+
+	type want struct {
+		ua      string
+		auditID string
+	}
+
+	cases := map[string]struct {
+		reason string
+		ctx    context.Context
+		want   want
+	}{
+		"Unannotated": {
+			reason: "Requests with an unannotated context should be passed through unchanged.",
+			ctx:    context.Background(),
+			want: want{
+				ua: ua,
+			},
+		},
+		"RequestID": {
+			reason: "Requests with a request ID should propagate it as the audit ID, and suffix it to the user agent.",
+			ctx:    context.WithValue(context.Background(), middleware.RequestIDKey, "coolid"),
+			want: want{
+				ua:      ua + " (request/coolid)",
+				auditID: "coolid",
+			},
+		},
+		"InvalidRequestID": {
+			reason: "Request IDs containing characters we don't allow should not be propagated.",
+			ctx:    context.WithValue(context.Background(), middleware.RequestIDKey, "cool id\r\nX-Evil: yes"),
+			want: want{
+				ua: ua,
+			},
+		},
+		"LongRequestID": {
+			reason: "Request IDs longer than MaxIDLength should not be propagated.",
+			ctx:    context.WithValue(context.Background(), middleware.RequestIDKey, strings.Repeat("a", MaxIDLength+1)),
+			want: want{
+				ua: ua,
+			},
+		},
+		"Operation": {
+			reason: "Requests with an operation name should suffix it to the user agent.",
+			ctx:    WithOperation(context.Background(), "coolop"),
+			want: want{
+				ua: ua + " (operation/coolop)",
+			},
+		},
+		"RequestIDAndOperation": {
+			reason: "Requests with a request ID and an operation name should propagate both.",
+			ctx:    WithOperation(context.WithValue(context.Background(), middleware.RequestIDKey, "coolid"), "coolop"),
+			want: want{
+				ua:      ua + " (request/coolid; operation/coolop)",
+				auditID: "coolid",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got *http.Request
+			rt := Transport(roundTripperFn(func(r *http.Request) (*http.Response, error) {
+				got = r
+				return httptest.NewRecorder().Result(), nil
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(tc.ctx)
+			r.Header.Set("User-Agent", ua)
+
+			if _, err := rt.RoundTrip(r); err != nil {
+				t.Fatalf("\n%s\nrt.RoundTrip(...): unexpected error: %s", tc.reason, err)
+			}
+
+			if diff := cmp.Diff(tc.want.ua, got.Header.Get("User-Agent")); diff != "" {
+				t.Errorf("\n%s\nrt.RoundTrip(...): -want User-Agent, +got User-Agent:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.auditID, got.Header.Get(HeaderAuditID)); diff != "" {
+				t.Errorf("\n%s\nrt.RoundTrip(...): -want Audit-ID, +got Audit-ID:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(ua, r.Header.Get("User-Agent")); diff != "" {
+				t.Errorf("\n%s\nrt.RoundTrip(...): must not modify the supplied request: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
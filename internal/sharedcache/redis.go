@@ -0,0 +1,331 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sharedcache
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultRedisTimeout is the default time allowed for a Redis command,
+	// including connecting to Redis if necessary.
+	DefaultRedisTimeout = 1 * time.Second
+
+	// DefaultRedisIdleTimeout is the default time for which an idle
+	// connection to Redis may be reused. Redis servers may be configured to
+	// close connections that are idle for longer.
+	DefaultRedisIdleTimeout = 30 * time.Second
+)
+
+const maxIdleConns = 8
+
+const (
+	errFmtParseURL    = "cannot parse Redis URL %q"
+	errFmtScheme      = "unsupported Redis URL scheme %q: must be redis or rediss"
+	errFmtDatabase    = "invalid Redis database %q"
+	errDial           = "cannot connect to Redis"
+	errAuth           = "cannot authenticate to Redis"
+	errSelect         = "cannot select Redis database"
+	errFmtCommand     = "cannot run Redis %s command"
+	errFmtUnsupported = "unsupported Redis reply type %q"
+)
+
+// Redis is a Store backed by Redis. It speaks just enough of the Redis
+// serialization protocol (RESP) to get and set keys.
+type Redis struct {
+	addr        string
+	username    string
+	password    string
+	db          int
+	timeout     time.Duration
+	idleTimeout time.Duration
+	tls         *tls.Config
+	dial        func(ctx context.Context, network, addr string) (net.Conn, error)
+
+	mx   sync.Mutex
+	idle []*redisConn
+}
+
+// A RedisOption configures a Redis store.
+type RedisOption func(r *Redis)
+
+// WithRedisTimeout configures the time allowed for each Redis command,
+// including connecting to Redis if necessary.
+func WithRedisTimeout(d time.Duration) RedisOption {
+	return func(r *Redis) {
+		r.timeout = d
+	}
+}
+
+// WithRedisIdleTimeout configures the time for which an idle connection to
+// Redis may be reused. Connections that have been idle for longer are closed.
+func WithRedisIdleTimeout(d time.Duration) RedisOption {
+	return func(r *Redis) {
+		r.idleTimeout = d
+	}
+}
+
+// WithRedisTLSConfig configures the TLS configuration used to connect to a
+// Redis server with a rediss:// URL. The server's certificate is verified
+// using the system's root CAs by default.
+func WithRedisTLSConfig(c *tls.Config) RedisOption {
+	return func(r *Redis) {
+		r.tls = c.Clone()
+	}
+}
+
+type redisConn struct {
+	net.Conn
+	r    *bufio.Reader
+	used time.Time
+}
+
+// A redisError is an error reply from Redis. Redis remains ready to serve
+// further commands on the connection that returned it.
+type redisError string
+
+func (e redisError) Error() string { return string(e) }
+
+// NewRedis returns a Store backed by the Redis server at the supplied URL, of
+// the form redis://[[username]:password@]host[:port][/database]. Use the
+// rediss scheme to connect to Redis using TLS.
+func NewRedis(rawurl string, o ...RedisOption) (*Redis, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtParseURL, rawurl)
+	}
+	if u.Scheme != "redis" && u.Scheme != "rediss" {
+		return nil, errors.Errorf(errFmtScheme, u.Scheme)
+	}
+
+	r := &Redis{
+		addr:        u.Host,
+		timeout:     DefaultRedisTimeout,
+		idleTimeout: DefaultRedisIdleTimeout,
+		dial:        (&net.Dialer{}).DialContext,
+	}
+	if u.Port() == "" {
+		r.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if pw, ok := u.User.Password(); ok {
+		r.username = u.User.Username()
+		r.password = pw
+	}
+	if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+		r.db, err = strconv.Atoi(db)
+		if err != nil {
+			return nil, errors.Errorf(errFmtDatabase, db)
+		}
+	}
+
+	for _, fn := range o {
+		fn(r)
+	}
+
+	if u.Scheme == "rediss" {
+		if r.tls == nil {
+			r.tls = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		if r.tls.ServerName == "" {
+			r.tls.ServerName = u.Hostname()
+		}
+		r.dial = (&tls.Dialer{Config: r.tls}).DialContext
+	}
+	return r, nil
+}
+
+// Get the value of the supplied key.
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	v, err := r.do(ctx, "GET", key)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, errFmtCommand, "GET")
+	}
+	return v, v != nil, nil
+}
+
+// Set the supplied key to the supplied value, expiring after ttl.
+func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	_, err := r.do(ctx, "SET", key, string(value), "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	return errors.Wrapf(err, errFmtCommand, "SET")
+}
+
+// Close any idle connections to Redis.
+func (r *Redis) Close() error {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	for _, c := range r.idle {
+		_ = c.Close()
+	}
+	r.idle = nil
+	return nil
+}
+
+func (r *Redis) do(ctx context.Context, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	c, reused, err := r.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := c.command(ctx, args...)
+	if err != nil && reused && !isRedisError(err) {
+		// Redis may have closed a connection we reused, for example because
+		// it was idle for too long. We try once more using a new one. It's
+		// safe to run our commands twice.
+		_ = c.Close()
+		if c, err = r.connect(ctx); err != nil {
+			return nil, err
+		}
+		v, err = c.command(ctx, args...)
+	}
+	if err != nil && !isRedisError(err) {
+		// We don't know what state the connection is in, so we don't reuse it.
+		_ = c.Close()
+		return nil, err
+	}
+
+	r.release(c)
+	return v, err
+}
+
+// conn returns an idle connection to Redis if there is one, or a new one. It
+// returns true if the connection was idle.
+func (r *Redis) conn(ctx context.Context) (*redisConn, bool, error) {
+	r.mx.Lock()
+	for n := len(r.idle); n > 0; n = len(r.idle) {
+		c := r.idle[n-1]
+		r.idle = r.idle[:n-1]
+		if time.Since(c.used) < r.idleTimeout {
+			r.mx.Unlock()
+			return c, true, nil
+		}
+		_ = c.Close()
+	}
+	r.mx.Unlock()
+
+	c, err := r.connect(ctx)
+	return c, false, err
+}
+
+// connect returns a new connection to Redis, authenticated and using the
+// configured database.
+func (r *Redis) connect(ctx context.Context) (*redisConn, error) {
+	nc, err := r.dial(ctx, "tcp", r.addr)
+	if err != nil {
+		return nil, errors.Wrap(err, errDial)
+	}
+	c := &redisConn{Conn: nc, r: bufio.NewReader(nc)}
+	if r.password != "" {
+		auth := []string{"AUTH", r.password}
+		if r.username != "" {
+			auth = []string{"AUTH", r.username, r.password}
+		}
+		if _, err := c.command(ctx, auth...); err != nil {
+			_ = c.Close()
+			return nil, errors.Wrap(err, errAuth)
+		}
+	}
+	if r.db != 0 {
+		if _, err := c.command(ctx, "SELECT", strconv.Itoa(r.db)); err != nil {
+			_ = c.Close()
+			return nil, errors.Wrap(err, errSelect)
+		}
+	}
+	return c, nil
+}
+
+// release the supplied connection, allowing it to be reused.
+func (r *Redis) release(c *redisConn) {
+	c.used = time.Now()
+
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	if len(r.idle) >= maxIdleConns {
+		_ = c.Close()
+		return
+	}
+	r.idle = append(r.idle, c)
+}
+
+// command sends the supplied command and returns its reply. Nil replies, e.g.
+// for a key that is not set, are returned as a nil slice.
+func (c *redisConn) command(ctx context.Context, args ...string) ([]byte, error) {
+	d, _ := ctx.Deadline()
+	if err := c.SetDeadline(d); err != nil {
+		return nil, err
+	}
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "*%d\r\n", len(args))
+	for _, a := range args {
+		fmt.Fprintf(b, "$%d\r\n%s\r\n", len(a), a)
+	}
+	if _, err := io.WriteString(c, b.String()); err != nil {
+		return nil, err
+	}
+	return readReply(c.r)
+}
+
+func isRedisError(err error) bool {
+	var rerr redisError
+	return errors.As(err, &rerr)
+}
+
+func readReply(r *bufio.Reader) ([]byte, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.Errorf(errFmtUnsupported, line)
+	}
+
+	switch line[0] {
+	case '+', ':':
+		return []byte(line[1:]), nil
+	case '-':
+		return nil, redisError(line[1:])
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		v := make([]byte, n+2) // Plus the trailing \r\n.
+		if _, err := io.ReadFull(r, v); err != nil {
+			return nil, err
+		}
+		return v[:n], nil
+	default:
+		return nil, errors.Errorf(errFmtUnsupported, line[0:1])
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sharedcache caches persisted queries and rendered responses in an
+// external store (e.g. Redis) that is shared by all xgql replicas, so that
+// cache hit rates survive replicas being scaled and restarted.
+package sharedcache
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/upbound/xgql/internal/auth"
+)
+
+// DefaultQueryTTL is the default time for which persisted queries are cached.
+const DefaultQueryTTL = 24 * time.Hour
+
+const (
+	prefixQuery    = "xgql:query:"
+	prefixResponse = "xgql:response:"
+)
+
+// A Store is an external key value store shared by xgql replicas.
+type Store interface {
+	// Get the value of the supplied key. Returns false if the key is not set.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set the supplied key to the supplied value, expiring after ttl.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// Queries adapts a Store for use as a persisted query cache.
+type Queries struct {
+	Store Store
+	TTL   time.Duration
+	Log   logging.Logger
+}
+
+var _ graphql.Cache = Queries{}
+
+// Get the persisted query with the supplied hash.
+func (q Queries) Get(ctx context.Context, key string) (interface{}, bool) {
+	v, ok, err := q.Store.Get(ctx, prefixQuery+key)
+	if err != nil {
+		q.log().Debug("Cannot get persisted query from shared cache", "error", err)
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+	return string(v), true
+}
+
+// Add the supplied persisted query.
+func (q Queries) Add(ctx context.Context, key string, value interface{}) {
+	s, ok := value.(string)
+	if !ok {
+		return
+	}
+	ttl := q.TTL
+	if ttl <= 0 {
+		ttl = DefaultQueryTTL
+	}
+	if err := q.Store.Set(ctx, prefixQuery+key, []byte(s), ttl); err != nil {
+		q.log().Debug("Cannot add persisted query to shared cache", "error", err)
+	}
+}
+
+func (q Queries) log() logging.Logger {
+	if q.Log == nil {
+		return logging.NewNopLogger()
+	}
+	return q.Log
+}
+
+// A response that was cached in a Store.
+type response struct {
+	ContentType  string `json:"contentType"`
+	CacheControl string `json:"cacheControl"`
+	ETag         string `json:"etag,omitempty"`
+	Body         []byte `json:"body"`
+}
+
+// Responses returns HTTP middleware that caches rendered responses in the
+// supplied store. A response is cached only if it succeeded and its
+// Cache-Control header permits caching, for the max-age the header specifies.
+// Most responses depend on the caller's RBAC permissions, so responses are
+// keyed by the caller's credentials as well as their request. The supplied
+// namespace distinguishes otherwise identical requests, e.g. those served for
+// different control planes. Credentials must be present in the request context.
+func Responses(s Store, namespace string, log logging.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !cacheable(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			creds, _ := auth.FromContext(r.Context())
+			key := prefixResponse + creds.Hash([]byte(strings.Join([]string{namespace, r.Method, r.URL.RawQuery, string(body)}, "\n")))
+
+			if v, ok, err := s.Get(r.Context(), key); err != nil {
+				log.Debug("Cannot get response from shared cache", "error", err)
+			} else if ok {
+				rsp := &response{}
+				if err := json.Unmarshal(v, rsp); err == nil {
+					w.Header().Set("Content-Type", rsp.ContentType)
+					w.Header().Set("Cache-Control", rsp.CacheControl)
+					if rsp.ETag != "" {
+						w.Header().Set("ETag", rsp.ETag)
+					}
+					_, _ = w.Write(rsp.Body)
+					return
+				}
+			}
+
+			rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			cc := w.Header().Get("Cache-Control")
+			ttl := maxAge(cc)
+			if rec.status != http.StatusOK || ttl <= 0 {
+				return
+			}
+			v, _ := json.Marshal(&response{ContentType: w.Header().Get("Content-Type"), CacheControl: cc, ETag: w.Header().Get("ETag"), Body: rec.body.Bytes()})
+			if err := s.Set(r.Context(), key, v, ttl); err != nil {
+				log.Debug("Cannot add response to shared cache", "error", err)
+			}
+		})
+	}
+}
+
+// cacheable returns true if the supplied request may have a cacheable
+// response. Websocket upgrades and multipart (i.e. file upload) requests are
+// never cacheable.
+func cacheable(r *http.Request) bool {
+	if r.Header.Get("Upgrade") != "" {
+		return false
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+		return false
+	}
+	return r.Method == http.MethodGet || r.Method == http.MethodPost
+}
+
+// maxAge returns the max-age of the supplied Cache-Control header, or zero if
+// the response may not be cached.
+func maxAge(cc string) time.Duration {
+	var age time.Duration
+	for _, d := range strings.Split(cc, ",") {
+		d = strings.TrimSpace(d)
+		switch {
+		case d == "no-store" || d == "no-cache":
+			return 0
+		case strings.HasPrefix(d, "max-age="):
+			s, err := strconv.Atoi(strings.TrimPrefix(d, "max-age="))
+			if err != nil {
+				return 0
+			}
+			age = time.Duration(s) * time.Second
+		}
+	}
+	return age
+}
+
+// A recorder records the status and body of a response as it is written.
+type recorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *recorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	_, _ = r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,197 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sharedcache
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/upbound/xgql/internal/auth"
+)
+
+type mapStore struct {
+	mx   sync.Mutex
+	data map[string][]byte
+	ttls map[string]time.Duration
+}
+
+func newMapStore() *mapStore {
+	return &mapStore{data: map[string][]byte{}, ttls: map[string]time.Duration{}}
+}
+
+func (m *mapStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	v, ok := m.data[key]
+	return v, ok, nil
+}
+
+func (m *mapStore) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	m.data[key] = value
+	m.ttls[key] = ttl
+	return nil
+}
+
+func TestQueries(t *testing.T) {
+	q := Queries{Store: newMapStore()}
+
+	if _, ok := q.Get(context.Background(), "hash"); ok {
+		t.Errorf("q.Get(...): want miss for query that was never added")
+	}
+	q.Add(context.Background(), "hash", "query { kubernetesResources { totalCount } }")
+	got, ok := q.Get(context.Background(), "hash")
+	if !ok {
+		t.Fatalf("q.Get(...): want hit for query that was added")
+	}
+	if diff := cmp.Diff("query { kubernetesResources { totalCount } }", got); diff != "" {
+		t.Errorf("q.Get(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestResponses(t *testing.T) {
+	type request struct {
+		method string
+		body   string
+		token  string
+	}
+	type want struct {
+		served int
+		bodies []string
+		cached int
+	}
+
+	cases := map[string]struct {
+		reason       string
+		cacheControl string
+		status       int
+		requests     []request
+		want         want
+	}{
+		"Cacheable": {
+			reason:       "Identical requests by the same caller should be served from the cache.",
+			cacheControl: "max-age=60, private",
+			requests: []request{
+				{method: http.MethodPost, body: "{}", token: "a"},
+				{method: http.MethodPost, body: "{}", token: "a"},
+			},
+			want: want{served: 1, bodies: []string{"1", "1"}, cached: 1},
+		},
+		"DifferentCallers": {
+			reason:       "Responses should not be shared between callers with different credentials.",
+			cacheControl: "max-age=60, private",
+			requests: []request{
+				{method: http.MethodPost, body: "{}", token: "a"},
+				{method: http.MethodPost, body: "{}", token: "b"},
+			},
+			want: want{served: 2, bodies: []string{"1", "2"}, cached: 2},
+		},
+		"NoStore": {
+			reason:       "Responses that may not be stored should not be cached.",
+			cacheControl: "no-store",
+			requests: []request{
+				{method: http.MethodPost, body: "{}", token: "a"},
+				{method: http.MethodPost, body: "{}", token: "a"},
+			},
+			want: want{served: 2, bodies: []string{"1", "2"}},
+		},
+		"Failed": {
+			reason:       "Unsuccessful responses should not be cached.",
+			cacheControl: "max-age=60, private",
+			status:       http.StatusBadRequest,
+			requests: []request{
+				{method: http.MethodPost, body: "{}", token: "a"},
+				{method: http.MethodPost, body: "{}", token: "a"},
+			},
+			want: want{served: 2, bodies: []string{"1", "2"}},
+		},
+		"UnsupportedMethod": {
+			reason:       "Requests that aren't GET or POST should never be cached.",
+			cacheControl: "max-age=60, private",
+			requests: []request{
+				{method: http.MethodPut, body: "{}", token: "a"},
+				{method: http.MethodPut, body: "{}", token: "a"},
+			},
+			want: want{served: 2, bodies: []string{"1", "2"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			served := 0
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				served++
+				w.Header().Set("Cache-Control", tc.cacheControl)
+				if tc.status != 0 {
+					w.WriteHeader(tc.status)
+				}
+				_, _ = w.Write([]byte(strconv.Itoa(served)))
+			})
+			s := newMapStore()
+			h := Responses(s, "test", logging.NewNopLogger())(next)
+
+			bodies := make([]string, 0, len(tc.requests))
+			for _, req := range tc.requests {
+				r := httptest.NewRequest(req.method, "/query", strings.NewReader(req.body))
+				r = r.WithContext(auth.WithCredentials(r.Context(), auth.Credentials{BearerToken: req.token}))
+				w := httptest.NewRecorder()
+				h.ServeHTTP(w, r)
+				bodies = append(bodies, w.Body.String())
+			}
+
+			if diff := cmp.Diff(tc.want.served, served); diff != "" {
+				t.Errorf("\n%s\nResponses(...): -want served, +got served:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.bodies, bodies); diff != "" {
+				t.Errorf("\n%s\nResponses(...): -want bodies, +got bodies:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cached, len(s.data)); diff != "" {
+				t.Errorf("\n%s\nResponses(...): -want cached, +got cached:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMaxAge(t *testing.T) {
+	cases := map[string]struct {
+		cc   string
+		want time.Duration
+	}{
+		"Empty":   {cc: "", want: 0},
+		"NoStore": {cc: "no-store", want: 0},
+		"Private": {cc: "max-age=60, private", want: 60 * time.Second},
+		"Public":  {cc: "public, max-age=5", want: 5 * time.Second},
+		"Invalid": {cc: "max-age=soon", want: 0},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, maxAge(tc.cc)); diff != "" {
+				t.Errorf("maxAge(%q): -want, +got:\n%s", tc.cc, diff)
+			}
+		})
+	}
+}
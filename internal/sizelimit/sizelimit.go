@@ -0,0 +1,99 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sizelimit limits the size of GraphQL requests, in order to protect
+// xgql from callers that would exhaust its memory, e.g. by sending huge
+// manifests to mutations.
+package sizelimit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Default limits.
+const (
+	DefaultMaxBodySize      = 4 << 20 // 4MiB
+	DefaultMaxVariablesSize = 2 << 20 // 2MiB
+)
+
+const (
+	errFmtBodyTooLarge      = "request body is %d bytes, which exceeds the maximum of %d bytes"
+	errFmtVariablesTooLarge = "variables are %d bytes, which exceeds the maximum of %d bytes"
+	errMarshalVariables     = "cannot determine the size of the supplied variables"
+)
+
+// Body returns HTTP middleware that rejects requests with a body larger than
+// the supplied number of bytes. Requests that declare their content length are
+// rejected before their body is read. Reads of other requests fail once they
+// exceed the limit. Zero disables the limit.
+func Body(max int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if max <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > max {
+				http.Error(w, fmt.Sprintf(errFmtBodyTooLarge, r.ContentLength, max), http.StatusRequestEntityTooLarge)
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, max)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Variables is a GraphQL server extension that rejects operations whose
+// variables, encoded as JSON, are larger than Max bytes. Unlike a body size
+// limit it also applies to operations sent using a GET request's query
+// parameters, or over a websocket. Zero disables the limit.
+type Variables struct {
+	Max int
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationParameterMutator
+} = Variables{}
+
+// ExtensionName returns the name of this extension.
+func (v Variables) ExtensionName() string {
+	return "VariablesSizeLimit"
+}
+
+// Validate this extension (a no-op).
+func (v Variables) Validate(_ graphql.ExecutableSchema) error {
+	return nil
+}
+
+// MutateOperationParameters returns an error if the operation's variables are
+// too large.
+func (v Variables) MutateOperationParameters(_ context.Context, p *graphql.RawParams) *gqlerror.Error {
+	if v.Max <= 0 || len(p.Variables) == 0 {
+		return nil
+	}
+	b, err := json.Marshal(p.Variables)
+	if err != nil {
+		return gqlerror.Errorf(errMarshalVariables)
+	}
+	if len(b) > v.Max {
+		return gqlerror.Errorf(errFmtVariablesTooLarge, len(b), v.Max)
+	}
+	return nil
+}
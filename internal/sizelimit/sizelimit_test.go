@@ -0,0 +1,121 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sizelimit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestBody(t *testing.T) {
+	// echo responds with the request body, or a 400 if it can't be read.
+	echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write(b)
+	})
+
+	cases := map[string]struct {
+		reason  string
+		max     int64
+		body    string
+		chunked bool
+		want    int
+	}{
+		"Disabled": {
+			reason: "Requests of any size should be served if the limit is disabled.",
+			body:   "{}",
+			want:   http.StatusOK,
+		},
+		"WithinLimit": {
+			reason: "Requests within the limit should be served.",
+			max:    2,
+			body:   "{}",
+			want:   http.StatusOK,
+		},
+		"ContentLengthTooLarge": {
+			reason: "Requests that declare a content length over the limit should be rejected.",
+			max:    1,
+			body:   "{}",
+			want:   http.StatusRequestEntityTooLarge,
+		},
+		"BodyTooLarge": {
+			reason:  "Reading bodies over the limit should fail.",
+			max:     1,
+			body:    "{}",
+			chunked: true,
+			want:    http.StatusBadRequest,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(tc.body))
+			if tc.chunked {
+				r.ContentLength = -1
+			}
+			w := httptest.NewRecorder()
+			Body(tc.max)(echo).ServeHTTP(w, r)
+
+			if diff := cmp.Diff(tc.want, w.Code); diff != "" {
+				t.Errorf("\n%s\nServeHTTP(...): -want status code, +got status code:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestVariablesMutateOperationParameters(t *testing.T) {
+	vars := map[string]interface{}{"cool": "very"} // {"cool":"very"} is 15 bytes.
+
+	cases := map[string]struct {
+		reason string
+		max    int
+		want   *gqlerror.Error
+	}{
+		"Disabled": {
+			reason: "Variables of any size should be allowed if the limit is disabled.",
+		},
+		"WithinLimit": {
+			reason: "Variables within the limit should be allowed.",
+			max:    15,
+		},
+		"TooLarge": {
+			reason: "Variables over the limit should be rejected.",
+			max:    14,
+			want:   gqlerror.Errorf(errFmtVariablesTooLarge, 15, 14),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Variables{Max: tc.max}.MutateOperationParameters(context.Background(), &graphql.RawParams{Variables: vars})
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreUnexported(gqlerror.Error{})); diff != "" {
+				t.Errorf("\n%s\nMutateOperationParameters(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
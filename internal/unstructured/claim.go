@@ -90,6 +90,42 @@ func (c *Claim) SetCompositionReference(ref *corev1.ObjectReference) {
 	_ = fieldpath.Pave(c.Object).SetValue("spec.compositionRef", ref)
 }
 
+// GetCompositionRevisionReference of this composite resource claim.
+func (c *Claim) GetCompositionRevisionReference() *corev1.ObjectReference {
+	out := &corev1.ObjectReference{}
+	if err := fieldpath.Pave(c.Object).GetValueInto("spec.compositionRevisionRef", out); err != nil {
+		return nil
+	}
+	return out
+}
+
+// SetCompositionRevisionReference of this composite resource claim.
+func (c *Claim) SetCompositionRevisionReference(ref *corev1.ObjectReference) {
+	_ = fieldpath.Pave(c.Object).SetValue("spec.compositionRevisionRef", ref)
+}
+
+// GetCompositionUpdatePolicy of this composite resource claim.
+func (c *Claim) GetCompositionUpdatePolicy() string {
+	p, _ := fieldpath.Pave(c.Object).GetString("spec.compositionUpdatePolicy")
+	return p
+}
+
+// SetCompositionUpdatePolicy of this composite resource claim.
+func (c *Claim) SetCompositionUpdatePolicy(p string) {
+	_ = fieldpath.Pave(c.Object).SetValue("spec.compositionUpdatePolicy", p)
+}
+
+// GetCompositeDeletePolicy of this composite resource claim.
+func (c *Claim) GetCompositeDeletePolicy() string {
+	p, _ := fieldpath.Pave(c.Object).GetString("spec.compositeDeletePolicy")
+	return p
+}
+
+// SetCompositeDeletePolicy of this composite resource claim.
+func (c *Claim) SetCompositeDeletePolicy(p string) {
+	_ = fieldpath.Pave(c.Object).SetValue("spec.compositeDeletePolicy", p)
+}
+
 // GetResourceReference of this composite resource claim.
 func (c *Claim) GetResourceReference() *corev1.ObjectReference {
 	out := &corev1.ObjectReference{}
@@ -118,6 +154,11 @@ func (c *Claim) SetWriteConnectionSecretToReference(ref *xpv1.LocalSecretReferen
 	_ = fieldpath.Pave(c.Object).SetValue("spec.writeConnectionSecretToRef", ref)
 }
 
+// GetPublishConnectionDetailsTo of this Claim.
+func (c *Claim) GetPublishConnectionDetailsTo() *PublishConnectionDetailsTo {
+	return getPublishConnectionDetailsTo(&c.Unstructured)
+}
+
 // GetCondition of this composite resource claim.
 func (c *Claim) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
 	conditioned := xpv1.ConditionedStatus{}
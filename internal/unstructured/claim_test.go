@@ -277,6 +277,44 @@ func TestCompositionReference(t *testing.T) {
 	}
 }
 
+func TestCompositionRevisionReference(t *testing.T) {
+	ref := &corev1.ObjectReference{Name: "cool-1234"}
+	cases := map[string]struct {
+		u    *Claim
+		set  *corev1.ObjectReference
+		want *corev1.ObjectReference
+	}{
+		"NewRef": {
+			u:    emptyXRC(),
+			set:  ref,
+			want: ref,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.u.SetCompositionRevisionReference(tc.set)
+			got := tc.u.GetCompositionRevisionReference()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nu.GetCompositionRevisionReference(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPolicies(t *testing.T) {
+	u := emptyXRC()
+	u.SetCompositionUpdatePolicy("Manual")
+	u.SetCompositeDeletePolicy("Foreground")
+
+	if diff := cmp.Diff("Manual", u.GetCompositionUpdatePolicy()); diff != "" {
+		t.Errorf("\nu.GetCompositionUpdatePolicy(): -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff("Foreground", u.GetCompositeDeletePolicy()); diff != "" {
+		t.Errorf("\nu.GetCompositeDeletePolicy(): -want, +got:\n%s", diff)
+	}
+}
+
 func TestResourceReference(t *testing.T) {
 	ref := &corev1.ObjectReference{Namespace: "ns", Name: "cool"}
 	cases := map[string]struct {
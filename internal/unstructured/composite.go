@@ -31,6 +31,8 @@ import (
 // ProbablyComposite returns true if the supplied *Unstructured is probably a
 // composite resource. It considers any cluster scoped resource with at least
 // one of the fields we inject into the OpenAPI schema of composite resources set.
+// Crossplane v2 composite resources may be namespaced; it considers any
+// resource with at least one of these fields set under spec.crossplane.
 // Note that it is possible for this to produce a false negative. All of these
 // injected fields are optional so it's possible that an XR has none of them
 // set. Such an XR would not be functional, as indicated by not having an array
@@ -39,6 +41,12 @@ func ProbablyComposite(u *unstructured.Unstructured) bool {
 	p := fieldpath.Pave(u.Object)
 	r := []corev1.ObjectReference{}
 	switch {
+	case p.GetValueInto("spec.crossplane.compositionRef", &corev1.ObjectReference{}) == nil:
+		return true
+	case p.GetValueInto("spec.crossplane.compositionSelector", &metav1.LabelSelector{}) == nil:
+		return true
+	case p.GetValueInto("spec.crossplane.resourceRefs", &r) == nil:
+		return true
 	case u.GetNamespace() != "":
 		return false
 	case p.GetValueInto("spec.compositionRef", &corev1.ObjectReference{}) == nil:
@@ -62,10 +70,28 @@ func (c *Composite) GetUnstructured() *unstructured.Unstructured {
 	return &c.Unstructured
 }
 
+// IsV2 returns true if this Composite resource is a Crossplane v2 composite
+// resource. Crossplane v2 composite resources nest the fields Crossplane
+// injects into their schema under spec.crossplane. They may be namespaced, and
+// may not be claimed.
+func (c *Composite) IsV2() bool {
+	_, err := fieldpath.Pave(c.Object).GetValue("spec.crossplane")
+	return err == nil
+}
+
+// path returns the path to the supplied Crossplane machinery field, which
+// depends on whether this is a Crossplane v2 composite resource.
+func (c *Composite) path(field string) string {
+	if c.IsV2() {
+		return "spec.crossplane." + field
+	}
+	return "spec." + field
+}
+
 // GetCompositionSelector of this Composite resource.
 func (c *Composite) GetCompositionSelector() *metav1.LabelSelector {
 	out := &metav1.LabelSelector{}
-	if err := fieldpath.Pave(c.Object).GetValueInto("spec.compositionSelector", out); err != nil {
+	if err := fieldpath.Pave(c.Object).GetValueInto(c.path("compositionSelector"), out); err != nil {
 		return nil
 	}
 	return out
@@ -73,13 +99,13 @@ func (c *Composite) GetCompositionSelector() *metav1.LabelSelector {
 
 // SetCompositionSelector of this Composite resource.
 func (c *Composite) SetCompositionSelector(sel *metav1.LabelSelector) {
-	_ = fieldpath.Pave(c.Object).SetValue("spec.compositionSelector", sel)
+	_ = fieldpath.Pave(c.Object).SetValue(c.path("compositionSelector"), sel)
 }
 
 // GetCompositionReference of this Composite resource.
 func (c *Composite) GetCompositionReference() *corev1.ObjectReference {
 	out := &corev1.ObjectReference{}
-	if err := fieldpath.Pave(c.Object).GetValueInto("spec.compositionRef", out); err != nil {
+	if err := fieldpath.Pave(c.Object).GetValueInto(c.path("compositionRef"), out); err != nil {
 		return nil
 	}
 	return out
@@ -87,13 +113,51 @@ func (c *Composite) GetCompositionReference() *corev1.ObjectReference {
 
 // SetCompositionReference of this Composite resource.
 func (c *Composite) SetCompositionReference(ref *corev1.ObjectReference) {
-	_ = fieldpath.Pave(c.Object).SetValue("spec.compositionRef", ref)
+	_ = fieldpath.Pave(c.Object).SetValue(c.path("compositionRef"), ref)
+}
+
+// GetCompositionRevisionReference of this Composite resource.
+func (c *Composite) GetCompositionRevisionReference() *corev1.ObjectReference {
+	out := &corev1.ObjectReference{}
+	if err := fieldpath.Pave(c.Object).GetValueInto(c.path("compositionRevisionRef"), out); err != nil {
+		return nil
+	}
+	return out
+}
+
+// SetCompositionRevisionReference of this Composite resource.
+func (c *Composite) SetCompositionRevisionReference(ref *corev1.ObjectReference) {
+	_ = fieldpath.Pave(c.Object).SetValue(c.path("compositionRevisionRef"), ref)
+}
+
+// GetCompositionUpdatePolicy of this Composite resource.
+func (c *Composite) GetCompositionUpdatePolicy() string {
+	p, _ := fieldpath.Pave(c.Object).GetString(c.path("compositionUpdatePolicy"))
+	return p
+}
+
+// SetCompositionUpdatePolicy of this Composite resource.
+func (c *Composite) SetCompositionUpdatePolicy(p string) {
+	_ = fieldpath.Pave(c.Object).SetValue(c.path("compositionUpdatePolicy"), p)
+}
+
+// GetEnvironmentConfigReferences of this Composite resource. These are the
+// EnvironmentConfigs selected by the composite resource's composition.
+func (c *Composite) GetEnvironmentConfigReferences() []corev1.ObjectReference {
+	out := &[]corev1.ObjectReference{}
+	_ = fieldpath.Pave(c.Object).GetValueInto(c.path("environmentConfigRefs"), out)
+	return *out
+}
+
+// SetEnvironmentConfigReferences of this Composite resource.
+func (c *Composite) SetEnvironmentConfigReferences(refs []corev1.ObjectReference) {
+	_ = fieldpath.Pave(c.Object).SetValue(c.path("environmentConfigRefs"), refs)
 }
 
 // GetClaimReference of this Composite resource.
 func (c *Composite) GetClaimReference() *corev1.ObjectReference {
 	out := &corev1.ObjectReference{}
-	if err := fieldpath.Pave(c.Object).GetValueInto("spec.claimRef", out); err != nil {
+	if err := fieldpath.Pave(c.Object).GetValueInto(c.path("claimRef"), out); err != nil {
 		return nil
 	}
 	return out
@@ -101,13 +165,13 @@ func (c *Composite) GetClaimReference() *corev1.ObjectReference {
 
 // SetClaimReference of this Composite resource.
 func (c *Composite) SetClaimReference(ref *corev1.ObjectReference) {
-	_ = fieldpath.Pave(c.Object).SetValue("spec.claimRef", ref)
+	_ = fieldpath.Pave(c.Object).SetValue(c.path("claimRef"), ref)
 }
 
 // GetResourceReferences of this Composite resource.
 func (c *Composite) GetResourceReferences() []corev1.ObjectReference {
 	out := &[]corev1.ObjectReference{}
-	_ = fieldpath.Pave(c.Object).GetValueInto("spec.resourceRefs", out)
+	_ = fieldpath.Pave(c.Object).GetValueInto(c.path("resourceRefs"), out)
 	return *out
 }
 
@@ -123,7 +187,7 @@ func (c *Composite) SetResourceReferences(refs []corev1.ObjectReference) {
 		}
 		filtered = append(filtered, ref)
 	}
-	_ = fieldpath.Pave(c.Object).SetValue("spec.resourceRefs", filtered)
+	_ = fieldpath.Pave(c.Object).SetValue(c.path("resourceRefs"), filtered)
 }
 
 // GetWriteConnectionSecretToReference of this Composite resource.
@@ -140,6 +204,11 @@ func (c *Composite) SetWriteConnectionSecretToReference(ref *xpv1.SecretReferenc
 	_ = fieldpath.Pave(c.Object).SetValue("spec.writeConnectionSecretToRef", ref)
 }
 
+// GetPublishConnectionDetailsTo of this Composite resource.
+func (c *Composite) GetPublishConnectionDetailsTo() *PublishConnectionDetailsTo {
+	return getPublishConnectionDetailsTo(&c.Unstructured)
+}
+
 // GetCondition of this Composite resource.
 func (c *Composite) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
 	conditioned := xpv1.ConditionedStatus{}
@@ -173,6 +242,21 @@ func (c *Composite) SetConnectionDetailsLastPublishedTime(t *metav1.Time) {
 	_ = fieldpath.Pave(c.Object).SetValue("status.connectionDetails.lastPublishedTime", t)
 }
 
+// GetClaimConditionTypes of this Composite resource. Crossplane propagates
+// conditions of these types to the resource's claim, if any.
+func (c *Composite) GetClaimConditionTypes() []string {
+	out := []string{}
+	if err := fieldpath.Pave(c.Object).GetValueInto("status.claimConditionTypes", &out); err != nil {
+		return nil
+	}
+	return out
+}
+
+// SetClaimConditionTypes of this Composite resource.
+func (c *Composite) SetClaimConditionTypes(t []string) {
+	_ = fieldpath.Pave(c.Object).SetValue("status.claimConditionTypes", t)
+}
+
 // NOTE(negz): The below method isn't part of the resource.Composite interface;
 // it exists to allow us to extract conditions to convert to our GraphQL model.
 
@@ -37,6 +37,12 @@ func emptyXR() *Composite {
 	return &Composite{Unstructured: unstructured.Unstructured{Object: map[string]interface{}{}}}
 }
 
+func emptyV2XR() *Composite {
+	return &Composite{Unstructured: unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"crossplane": map[string]interface{}{}},
+	}}}
+}
+
 func TestProbablyComposite(t *testing.T) {
 	cases := map[string]struct {
 		reason string
@@ -92,6 +98,33 @@ func TestProbablyComposite(t *testing.T) {
 			}(),
 			want: false,
 		},
+		"V2HasCompositionRef": {
+			reason: "A namespaced resource with a Crossplane v2 composition ref is probably an XR.",
+			u: func() *unstructured.Unstructured {
+				o := map[string]interface{}{}
+				fieldpath.Pave(o).SetValue("spec.crossplane.compositionRef", &corev1.ObjectReference{
+					Name: "coolcomposition",
+				})
+				u := &unstructured.Unstructured{Object: o}
+				u.SetNamespace("default")
+				return u
+			}(),
+			want: true,
+		},
+		"V2HasResourceRefs": {
+			reason: "A cluster scoped resource with an array of Crossplane v2 resource refs is probably an XR.",
+			u: func() *unstructured.Unstructured {
+				o := map[string]interface{}{}
+				r := []corev1.ObjectReference{{
+					APIVersion: "example.org/v1",
+					Kind:       "Example",
+					Name:       "coolexample",
+				}}
+				fieldpath.Pave(o).SetValue("spec.crossplane.resourceRefs", &r)
+				return &unstructured.Unstructured{Object: o}
+			}(),
+			want: true,
+		},
 		"WeirdResourceRefs": {
 			reason: "A cluster scoped resource with a non-objectref resourceRefs array is not an XR.",
 			u: func() *unstructured.Unstructured {
@@ -252,6 +285,11 @@ func TestCompositeCompositionReference(t *testing.T) {
 			set:  ref,
 			want: ref,
 		},
+		"NewV2Ref": {
+			u:    emptyV2XR(),
+			set:  ref,
+			want: ref,
+		},
 	}
 
 	for name, tc := range cases {
@@ -265,6 +303,100 @@ func TestCompositeCompositionReference(t *testing.T) {
 	}
 }
 
+func TestCompositeCompositionRevisionReference(t *testing.T) {
+	ref := &corev1.ObjectReference{Name: "cool-1234"}
+	cases := map[string]struct {
+		u    *Composite
+		set  *corev1.ObjectReference
+		want *corev1.ObjectReference
+	}{
+		"NewRef": {
+			u:    emptyXR(),
+			set:  ref,
+			want: ref,
+		},
+		"NewV2Ref": {
+			u:    emptyV2XR(),
+			set:  ref,
+			want: ref,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.u.SetCompositionRevisionReference(tc.set)
+			got := tc.u.GetCompositionRevisionReference()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nu.GetCompositionRevisionReference(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCompositeCompositionUpdatePolicy(t *testing.T) {
+	cases := map[string]struct {
+		u    *Composite
+		set  string
+		want string
+	}{
+		"NewPolicy": {
+			u:    emptyXR(),
+			set:  "Manual",
+			want: "Manual",
+		},
+		"NewV2Policy": {
+			u:    emptyV2XR(),
+			set:  "Manual",
+			want: "Manual",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.u.SetCompositionUpdatePolicy(tc.set)
+			got := tc.u.GetCompositionUpdatePolicy()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nu.GetCompositionUpdatePolicy(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCompositeIsV2(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		u      *Composite
+		want   bool
+		path   string
+	}{
+		"Legacy": {
+			reason: "A composite resource without spec.crossplane is not a Crossplane v2 XR.",
+			u:      emptyXR(),
+			want:   false,
+			path:   "spec.compositionRef",
+		},
+		"V2": {
+			reason: "A composite resource with spec.crossplane is a Crossplane v2 XR.",
+			u:      emptyV2XR(),
+			want:   true,
+			path:   "spec.crossplane.compositionRef",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.u.IsV2()); diff != "" {
+				t.Errorf("\n%s\nu.IsV2(): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			tc.u.SetCompositionReference(&corev1.ObjectReference{Name: "cool"})
+			if _, err := fieldpath.Pave(tc.u.Object).GetValue(tc.path); err != nil {
+				t.Errorf("\n%s\nu.SetCompositionReference(...): expected value at %s: %s", tc.reason, tc.path, err)
+			}
+		})
+	}
+}
+
 func TestCompositeClaimReference(t *testing.T) {
 	ref := &corev1.ObjectReference{Namespace: "ns", Name: "cool"}
 	cases := map[string]struct {
@@ -290,6 +422,36 @@ func TestCompositeClaimReference(t *testing.T) {
 	}
 }
 
+func TestCompositeClaimConditionTypes(t *testing.T) {
+	cases := map[string]struct {
+		u    *Composite
+		set  []string
+		want []string
+	}{
+		"NewTypes": {
+			u:    emptyXR(),
+			set:  []string{"DatabaseReady", "CacheReady"},
+			want: []string{"DatabaseReady", "CacheReady"},
+		},
+		"NoTypes": {
+			u:    emptyXR(),
+			want: nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if tc.set != nil {
+				tc.u.SetClaimConditionTypes(tc.set)
+			}
+			got := tc.u.GetClaimConditionTypes()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nu.GetClaimConditionTypes(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestCompositeResourceReferences(t *testing.T) {
 	ref := corev1.ObjectReference{Namespace: "ns", Name: "cool"}
 	cases := map[string]struct {
@@ -315,6 +477,36 @@ func TestCompositeResourceReferences(t *testing.T) {
 	}
 }
 
+func TestCompositeEnvironmentConfigReferences(t *testing.T) {
+	ref := corev1.ObjectReference{APIVersion: "apiextensions.crossplane.io/v1alpha1", Kind: "EnvironmentConfig", Name: "cool"}
+	cases := map[string]struct {
+		u    *Composite
+		set  []corev1.ObjectReference
+		want []corev1.ObjectReference
+	}{
+		"NewRefs": {
+			u:    emptyXR(),
+			set:  []corev1.ObjectReference{ref},
+			want: []corev1.ObjectReference{ref},
+		},
+		"NewV2Refs": {
+			u:    emptyV2XR(),
+			set:  []corev1.ObjectReference{ref},
+			want: []corev1.ObjectReference{ref},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.u.SetEnvironmentConfigReferences(tc.set)
+			got := tc.u.GetEnvironmentConfigReferences()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nu.GetEnvironmentConfigReferences(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestCompositeWriteConnectionSecretToReference(t *testing.T) {
 	ref := &xpv1.SecretReference{Namespace: "ns", Name: "cool"}
 	cases := map[string]struct {
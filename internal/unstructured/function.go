@@ -0,0 +1,77 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unstructured
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+// Functions are not part of the version of Crossplane we build against, so we
+// read them as unstructured resources. They're packages much like providers
+// and configurations, and share the same spec and status fields.
+const (
+	KindFunction         = "Function"
+	KindFunctionRevision = "FunctionRevision"
+)
+
+// FunctionVersions are the API versions at which Crossplane may serve
+// functions, newest first. Functions graduated to v1 after the lock and
+// deployment runtime configs, which remain at v1beta1.
+var FunctionVersions = []string{"v1", "v1beta1"}
+
+// A Function is a composition function package.
+type Function struct {
+	unstructured.Unstructured
+}
+
+// GetUnstructured returns the underlying *Unstructured.
+func (u *Function) GetUnstructured() *unstructured.Unstructured {
+	return &u.Unstructured
+}
+
+// GetPackageSpec of this function.
+func (u *Function) GetPackageSpec() pkgv1.PackageSpec {
+	out := pkgv1.PackageSpec{}
+	_ = fieldpath.Pave(u.Object).GetValueInto("spec", &out)
+	return out
+}
+
+// GetRuntimeConfigName returns the name of the deployment runtime config this
+// function uses, if any.
+func (u *Function) GetRuntimeConfigName() string {
+	out, _ := fieldpath.Pave(u.Object).GetString("spec.runtimeConfigRef.name")
+	return out
+}
+
+// GetPackageStatus of this function.
+func (u *Function) GetPackageStatus() pkgv1.PackageStatus {
+	out := pkgv1.PackageStatus{}
+	_ = fieldpath.Pave(u.Object).GetValueInto("status", &out)
+	return out
+}
+
+// GetConditions of this function.
+func (u *Function) GetConditions() []xpv1.Condition {
+	conditioned := xpv1.ConditionedStatus{}
+	// The path is directly `status` because conditions are inline.
+	if err := fieldpath.Pave(u.Object).GetValueInto("status", &conditioned); err != nil {
+		return nil
+	}
+	return conditioned.Conditions
+}
@@ -0,0 +1,89 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unstructured
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFunctionGetPackageSpec(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		u      *Function
+		want   pkgv1.PackageSpec
+	}{
+		"NoSpec": {
+			reason: "A function without a spec should return an empty spec.",
+			u:      &Function{unstructured.Unstructured{Object: map[string]interface{}{}}},
+			want:   pkgv1.PackageSpec{},
+		},
+		"Spec": {
+			reason: "The package spec of a function should be returned.",
+			u: &Function{unstructured.Unstructured{Object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"package":          "example.org/function:v1",
+					"runtimeConfigRef": map[string]interface{}{"name": "default"},
+				},
+			}}},
+			want: pkgv1.PackageSpec{Package: "example.org/function:v1"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.u.GetPackageSpec()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nu.GetPackageSpec(): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFunctionGetRuntimeConfigName(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		u      *Function
+		want   string
+	}{
+		"NoRuntimeConfig": {
+			reason: "A function that doesn't reference a runtime config should return an empty name.",
+			u:      &Function{unstructured.Unstructured{Object: map[string]interface{}{}}},
+			want:   "",
+		},
+		"RuntimeConfig": {
+			reason: "The name of the referenced runtime config should be returned.",
+			u: &Function{unstructured.Unstructured{Object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"runtimeConfigRef": map[string]interface{}{"name": "default"},
+				},
+			}}},
+			want: "default",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.u.GetRuntimeConfigName()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nu.GetRuntimeConfigName(): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unstructured
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+)
+
+// The package manager records the packages it installs, and their
+// dependencies, in a single cluster scoped lock.
+const (
+	KindLock = "Lock"
+	NameLock = "lock"
+)
+
+// LockVersions are the API versions at which Crossplane may serve the lock,
+// newest first. Older versions of Crossplane serve the lock at v1alpha1.
+var LockVersions = []string{"v1beta1", "v1alpha1"}
+
+// A Lock records the packages installed by Crossplane's package manager.
+type Lock struct {
+	unstructured.Unstructured
+}
+
+// A LockPackage is a package recorded in the lock.
+type LockPackage struct {
+	// Name of the package revision.
+	Name string `json:"name"`
+
+	// Type of the package, e.g. Provider.
+	Type string `json:"type,omitempty"`
+
+	// Source of the package, i.e. its OCI repository without a tag.
+	Source string `json:"source"`
+
+	// Version of the package.
+	Version string `json:"version"`
+
+	// Dependencies of the package.
+	Dependencies []LockDependency `json:"dependencies,omitempty"`
+}
+
+// A LockDependency is a package upon which a locked package depends.
+type LockDependency struct {
+	// Package is the OCI repository of the dependency, without a tag.
+	Package string `json:"package"`
+
+	// Type of the dependency, e.g. Provider.
+	Type string `json:"type,omitempty"`
+
+	// Constraints are the semantic version constraints of the dependency.
+	Constraints string `json:"constraints,omitempty"`
+}
+
+// GetUnstructured returns the underlying *Unstructured.
+func (u *Lock) GetUnstructured() *unstructured.Unstructured {
+	return &u.Unstructured
+}
+
+// GetPackages recorded in this lock.
+func (u *Lock) GetPackages() []LockPackage {
+	out := []LockPackage{}
+	if err := fieldpath.Pave(u.Object).GetValueInto("packages", &out); err != nil {
+		return nil
+	}
+	return out
+}
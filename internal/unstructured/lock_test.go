@@ -0,0 +1,77 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unstructured
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLockGetPackages(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		u      *Lock
+		want   []LockPackage
+	}{
+		"NoPackages": {
+			reason: "A lock without packages should return nil.",
+			u:      &Lock{unstructured.Unstructured{Object: map[string]interface{}{}}},
+			want:   nil,
+		},
+		"Packages": {
+			reason: "Packages and their dependencies should be returned.",
+			u: &Lock{unstructured.Unstructured{Object: map[string]interface{}{
+				"packages": []interface{}{
+					map[string]interface{}{
+						"name":    "config-abc",
+						"type":    "Configuration",
+						"source":  "example.org/config",
+						"version": "v1.0.0",
+						"dependencies": []interface{}{
+							map[string]interface{}{
+								"package":     "example.org/provider",
+								"type":        "Provider",
+								"constraints": ">=v1.0.0",
+							},
+						},
+					},
+				},
+			}}},
+			want: []LockPackage{{
+				Name:    "config-abc",
+				Type:    "Configuration",
+				Source:  "example.org/config",
+				Version: "v1.0.0",
+				Dependencies: []LockDependency{{
+					Package:     "example.org/provider",
+					Type:        "Provider",
+					Constraints: ">=v1.0.0",
+				}},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.u.GetPackages()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nu.GetPackages(): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -87,6 +87,11 @@ func (u *Managed) SetWriteConnectionSecretToReference(ref *xpv1.SecretReference)
 	_ = fieldpath.Pave(u.Object).SetValue("spec.writeConnectionSecretToRef", ref)
 }
 
+// GetPublishConnectionDetailsTo of this managed resource.
+func (u *Managed) GetPublishConnectionDetailsTo() *PublishConnectionDetailsTo {
+	return getPublishConnectionDetailsTo(&u.Unstructured)
+}
+
 // GetDeletionPolicy of this managed resource.
 func (u *Managed) GetDeletionPolicy() xpv1.DeletionPolicy {
 	// The default
@@ -131,3 +136,34 @@ func (u *Managed) GetConditions() []xpv1.Condition {
 	}
 	return conditioned.Conditions
 }
+
+// GetManagementPolicies of this managed resource, if any. Management policies
+// were introduced after the version of crossplane-runtime we use, so they're
+// not part of the resource.Managed interface.
+func (u *Managed) GetManagementPolicies() []string {
+	p := []string{}
+	if err := fieldpath.Pave(u.Object).GetValueInto("spec.managementPolicies", &p); err != nil {
+		return nil
+	}
+	return p
+}
+
+// GetForProvider returns the parameters of this managed resource's external
+// resource, i.e. its spec.forProvider field, if any.
+func (u *Managed) GetForProvider() map[string]interface{} {
+	p := map[string]interface{}{}
+	if err := fieldpath.Pave(u.Object).GetValueInto("spec.forProvider", &p); err != nil {
+		return nil
+	}
+	return p
+}
+
+// GetAtProvider returns the observed state of this managed resource's external
+// resource, i.e. its status.atProvider field, if any.
+func (u *Managed) GetAtProvider() map[string]interface{} {
+	o := map[string]interface{}{}
+	if err := fieldpath.Pave(u.Object).GetValueInto("status.atProvider", &o); err != nil {
+		return nil
+	}
+	return o
+}
@@ -280,3 +280,76 @@ func TestManagedDeletionPolicy(t *testing.T) {
 		})
 	}
 }
+
+func TestManagedForProvider(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		u      *Managed
+		want   map[string]interface{}
+	}{
+		"ForProvider": {
+			reason: "It should be possible to get spec.forProvider.",
+			u: &Managed{unstructured.Unstructured{Object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"forProvider": map[string]interface{}{"region": "us-west-2"},
+				},
+			}}},
+			want: map[string]interface{}{"region": "us-west-2"},
+		},
+		"NoForProvider": {
+			reason: "Getting spec.forProvider should return nil if it is not set.",
+			u:      emptyMR(),
+			want:   nil,
+		},
+		"WeirdForProvider": {
+			reason: "Getting spec.forProvider should return nil if it is not an object.",
+			u: &Managed{unstructured.Unstructured{Object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"forProvider": "wat",
+				},
+			}}},
+			want: nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.u.GetForProvider()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nu.GetForProvider(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestManagedAtProvider(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		u      *Managed
+		want   map[string]interface{}
+	}{
+		"AtProvider": {
+			reason: "It should be possible to get status.atProvider.",
+			u: &Managed{unstructured.Unstructured{Object: map[string]interface{}{
+				"status": map[string]interface{}{
+					"atProvider": map[string]interface{}{"arn": "cool"},
+				},
+			}}},
+			want: map[string]interface{}{"arn": "cool"},
+		},
+		"NoAtProvider": {
+			reason: "Getting status.atProvider should return nil if it is not set.",
+			u:      emptyMR(),
+			want:   nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.u.GetAtProvider()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nu.GetAtProvider(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -21,11 +21,25 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 )
 
+// Kinds of provider config.
+const (
+	KindProviderConfig        = "ProviderConfig"
+	KindClusterProviderConfig = "ClusterProviderConfig"
+)
+
 // ProbablyProviderConfig returns true if the supplied *Unstructured is probably
-// a provider config. It considers any cluster scoped resource of kind:
-// ProviderConfig to probably be a provider config.
+// a provider config. It considers any resource of kind: ProviderConfig, and any
+// cluster scoped resource of kind: ClusterProviderConfig to probably be a
+// provider config. Providers may offer namespaced ProviderConfigs alongside
+// cluster scoped ClusterProviderConfigs.
 func ProbablyProviderConfig(u *unstructured.Unstructured) bool {
-	return u.GetNamespace() == "" && u.GetKind() == "ProviderConfig"
+	switch u.GetKind() {
+	case KindProviderConfig:
+		return true
+	case KindClusterProviderConfig:
+		return u.GetNamespace() == ""
+	}
+	return false
 }
 
 // A ProviderConfig resource.
@@ -57,13 +57,32 @@ func TestProbablyProviderConfig(t *testing.T) {
 			want: false,
 		},
 		"Namespaced": {
-			reason: "A namespaced resource is not a Crossplane ProviderConfig.",
+			reason: "A namespaced resource of kind: ProviderConfig is probably a Crossplane ProviderConfig.",
 			u: func() *unstructured.Unstructured {
 				u := &unstructured.Unstructured{Object: map[string]interface{}{}}
 				u.SetNamespace("default")
 				u.SetKind("ProviderConfig")
 				return u
 			}(),
+			want: true,
+		},
+		"ClusterProviderConfig": {
+			reason: "A cluster scoped resource of kind: ClusterProviderConfig is probably a Crossplane ProviderConfig.",
+			u: func() *unstructured.Unstructured {
+				u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+				u.SetKind("ClusterProviderConfig")
+				return u
+			}(),
+			want: true,
+		},
+		"NamespacedClusterProviderConfig": {
+			reason: "A namespaced resource of kind: ClusterProviderConfig is not a Crossplane ProviderConfig.",
+			u: func() *unstructured.Unstructured {
+				u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+				u.SetNamespace("default")
+				u.SetKind("ClusterProviderConfig")
+				return u
+			}(),
 			want: false,
 		},
 	}
@@ -0,0 +1,65 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unstructured
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+)
+
+// KindDeploymentRuntimeConfig is the kind of a deployment runtime config.
+const KindDeploymentRuntimeConfig = "DeploymentRuntimeConfig"
+
+// DeploymentRuntimeConfigVersions are the API versions at which Crossplane may
+// serve deployment runtime configs, newest first.
+var DeploymentRuntimeConfigVersions = []string{"v1beta1", "v1alpha1"}
+
+// A DeploymentRuntimeConfig configures how the package manager deploys the
+// controllers of providers and functions.
+type DeploymentRuntimeConfig struct {
+	unstructured.Unstructured
+}
+
+// GetUnstructured returns the underlying *Unstructured.
+func (u *DeploymentRuntimeConfig) GetUnstructured() *unstructured.Unstructured {
+	return &u.Unstructured
+}
+
+// GetDeploymentTemplate of this deployment runtime config. The template is a
+// partial Kubernetes Deployment.
+func (u *DeploymentRuntimeConfig) GetDeploymentTemplate() map[string]interface{} {
+	return u.getTemplate("spec.deploymentTemplate")
+}
+
+// GetServiceTemplate of this deployment runtime config. The template is a
+// partial Kubernetes Service.
+func (u *DeploymentRuntimeConfig) GetServiceTemplate() map[string]interface{} {
+	return u.getTemplate("spec.serviceTemplate")
+}
+
+// GetServiceAccountTemplate of this deployment runtime config. The template is
+// a partial Kubernetes ServiceAccount.
+func (u *DeploymentRuntimeConfig) GetServiceAccountTemplate() map[string]interface{} {
+	return u.getTemplate("spec.serviceAccountTemplate")
+}
+
+func (u *DeploymentRuntimeConfig) getTemplate(path string) map[string]interface{} {
+	out := map[string]interface{}{}
+	if err := fieldpath.Pave(u.Object).GetValueInto(path, &out); err != nil {
+		return nil
+	}
+	return out
+}
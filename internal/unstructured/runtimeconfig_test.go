@@ -0,0 +1,59 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unstructured
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDeploymentRuntimeConfigGetDeploymentTemplate(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		u      *DeploymentRuntimeConfig
+		want   map[string]interface{}
+	}{
+		"NoTemplate": {
+			reason: "A runtime config without a deployment template should return nil.",
+			u:      &DeploymentRuntimeConfig{unstructured.Unstructured{Object: map[string]interface{}{}}},
+			want:   nil,
+		},
+		"Template": {
+			reason: "The deployment template should be returned.",
+			u: &DeploymentRuntimeConfig{unstructured.Unstructured{Object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"deploymentTemplate": map[string]interface{}{
+						"metadata": map[string]interface{}{"name": "cool"},
+					},
+				},
+			}}},
+			want: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "cool"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.u.GetDeploymentTemplate()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nu.GetDeploymentTemplate(): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unstructured
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+)
+
+// APIVersionSecrets is the API version of Crossplane's own store configs, which
+// are used by composite resources and claims. Providers offer their own store
+// configs for use by managed resources.
+const APIVersionSecrets = "secrets.crossplane.io/v1alpha1"
+
+// providerStoreConfigVersion is the API version at which providers serve their
+// store configs.
+const providerStoreConfigVersion = "v1alpha1"
+
+// Kinds of store config.
+const (
+	KindStoreConfig        = "StoreConfig"
+	KindClusterStoreConfig = "ClusterStoreConfig"
+)
+
+// ProbablyStoreConfig returns true if the supplied *Unstructured is probably a
+// store config. It considers any resource of kind: StoreConfig, and any cluster
+// scoped resource of kind: ClusterStoreConfig to probably be a store config.
+func ProbablyStoreConfig(u *unstructured.Unstructured) bool {
+	switch u.GetKind() {
+	case KindStoreConfig:
+		return true
+	case KindClusterStoreConfig:
+		return u.GetNamespace() == ""
+	}
+	return false
+}
+
+// ProviderStoreConfigAPIVersions returns the API versions at which the store
+// configs used by managed resources of the supplied API group may be served, in
+// order of preference. Providers serve their store configs either in the API
+// group of their managed resources, or in its parent; for example aws.upbound.io
+// for ec2.aws.upbound.io.
+func ProviderStoreConfigAPIVersions(group string) []string {
+	if group == "" {
+		return nil
+	}
+	out := []string{group + "/" + providerStoreConfigVersion}
+	if parent := group[strings.Index(group, ".")+1:]; strings.Contains(parent, ".") {
+		out = append(out, parent+"/"+providerStoreConfigVersion)
+	}
+	return out
+}
+
+// A StoreConfig configures an external secret store to which connection
+// details may be published.
+type StoreConfig struct {
+	unstructured.Unstructured
+}
+
+// GetUnstructured returns the underlying *Unstructured.
+func (u *StoreConfig) GetUnstructured() *unstructured.Unstructured {
+	return &u.Unstructured
+}
+
+// GetType of secret store configured by this store config, e.g. Kubernetes.
+func (u *StoreConfig) GetType() string {
+	out, _ := fieldpath.Pave(u.Object).GetString("spec.type")
+	return out
+}
+
+// GetDefaultScope of this store config. Secrets are stored in this scope (e.g.
+// a Kubernetes namespace) when the resource publishing them does not specify
+// one.
+func (u *StoreConfig) GetDefaultScope() string {
+	out, _ := fieldpath.Pave(u.Object).GetString("spec.defaultScope")
+	return out
+}
+
+// GetConditions of this store config.
+func (u *StoreConfig) GetConditions() []xpv1.Condition {
+	conditioned := xpv1.ConditionedStatus{}
+	// The path is directly `status` because conditions are inline.
+	if err := fieldpath.Pave(u.Object).GetValueInto("status", &conditioned); err != nil {
+		return nil
+	}
+	return conditioned.Conditions
+}
+
+// PublishConnectionDetailsTo specifies the external secret store to which a
+// resource publishes its connection details.
+type PublishConnectionDetailsTo struct {
+	// Name of the connection secret.
+	Name string `json:"name"`
+
+	// ConfigRef references the store config to publish to.
+	ConfigRef *xpv1.Reference `json:"configRef,omitempty"`
+}
+
+func getPublishConnectionDetailsTo(u *unstructured.Unstructured) *PublishConnectionDetailsTo {
+	out := &PublishConnectionDetailsTo{}
+	if err := fieldpath.Pave(u.Object).GetValueInto("spec.publishConnectionDetailsTo", out); err != nil {
+		return nil
+	}
+	return out
+}
@@ -0,0 +1,167 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unstructured
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/google/go-cmp/cmp"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+func TestProbablyStoreConfig(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		u      *unstructured.Unstructured
+		want   bool
+	}{
+		"Probably": {
+			reason: "A resource of kind: StoreConfig is probably a store config.",
+			u: func() *unstructured.Unstructured {
+				u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+				u.SetKind("StoreConfig")
+				return u
+			}(),
+			want: true,
+		},
+		"WrongKind": {
+			reason: "A resource that is not of kind: StoreConfig is not a store config.",
+			u: func() *unstructured.Unstructured {
+				u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+				u.SetKind("Elephant")
+				return u
+			}(),
+			want: false,
+		},
+		"ClusterStoreConfig": {
+			reason: "A cluster scoped resource of kind: ClusterStoreConfig is probably a store config.",
+			u: func() *unstructured.Unstructured {
+				u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+				u.SetKind("ClusterStoreConfig")
+				return u
+			}(),
+			want: true,
+		},
+		"NamespacedClusterStoreConfig": {
+			reason: "A namespaced resource of kind: ClusterStoreConfig is not a store config.",
+			u: func() *unstructured.Unstructured {
+				u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+				u.SetNamespace("default")
+				u.SetKind("ClusterStoreConfig")
+				return u
+			}(),
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ProbablyStoreConfig(tc.u)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nProbablyStoreConfig(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestStoreConfigSpec(t *testing.T) {
+	sc := &StoreConfig{Unstructured: unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"type":         "Vault",
+			"defaultScope": "crossplane-system",
+		},
+	}}}
+
+	if diff := cmp.Diff("Vault", sc.GetType()); diff != "" {
+		t.Errorf("\nsc.GetType(): -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff("crossplane-system", sc.GetDefaultScope()); diff != "" {
+		t.Errorf("\nsc.GetDefaultScope(): -want, +got:\n%s", diff)
+	}
+}
+
+func TestGetPublishConnectionDetailsTo(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		u      *unstructured.Unstructured
+		want   *PublishConnectionDetailsTo
+	}{
+		"Unset": {
+			reason: "A resource that does not publish connection details to an external store should return nil.",
+			u:      &unstructured.Unstructured{Object: map[string]interface{}{}},
+			want:   nil,
+		},
+		"Set": {
+			reason: "A resource that publishes connection details to an external store should return where.",
+			u: &unstructured.Unstructured{Object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"publishConnectionDetailsTo": map[string]interface{}{
+						"name":      "cool-secret",
+						"configRef": map[string]interface{}{"name": "vault"},
+					},
+				},
+			}},
+			want: &PublishConnectionDetailsTo{
+				Name:      "cool-secret",
+				ConfigRef: &xpv1.Reference{Name: "vault"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := (&Composite{Unstructured: *tc.u}).GetPublishConnectionDetailsTo()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nGetPublishConnectionDetailsTo(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestProviderStoreConfigAPIVersions(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		group  string
+		want   []string
+	}{
+		"Empty": {
+			reason: "The core API group is not served by a provider.",
+			group:  "",
+			want:   nil,
+		},
+		"ServiceGroup": {
+			reason: "A provider's store configs may be served in the API group of its managed resources, or in its parent.",
+			group:  "ec2.aws.upbound.io",
+			want:   []string{"ec2.aws.upbound.io/v1alpha1", "aws.upbound.io/v1alpha1"},
+		},
+		"TopLevelGroup": {
+			reason: "A top level domain is never considered to be a parent API group.",
+			group:  "example.org",
+			want:   []string{"example.org/v1alpha1"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ProviderStoreConfigAPIVersions(tc.group)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nProviderStoreConfigAPIVersions(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xgql
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/upbound/xgql/internal/graph/model"
+)
+
+// A KubernetesResource is the GraphQL model of a Kubernetes resource.
+type KubernetesResource = model.KubernetesResource
+
+// A ResourceConverter converts an unstructured Kubernetes resource of a
+// particular kind to its GraphQL model.
+type ResourceConverter = model.ResourceConverter
+
+// A ResourceConverterFn is a function that satisfies ResourceConverter.
+type ResourceConverterFn = model.ResourceConverterFn
+
+// Converters to the GraphQL models of kinds of Crossplane resource.
+var (
+	ManagedResourceConverter        = model.ManagedResourceConverter
+	ProviderConfigConverter         = model.ProviderConfigConverter
+	StoreConfigConverter            = model.StoreConfigConverter
+	CompositeResourceConverter      = model.CompositeResourceConverter
+	CompositeResourceClaimConverter = model.CompositeResourceClaimConverter
+	GenericResourceConverter        = model.GenericResourceConverter
+)
+
+// RegisterResourceConverter registers a converter for the supplied kind of
+// resource. Resources of that kind are modelled by the converter rather than
+// by xgql's built in type detection, which models any resource it can't
+// identify as a GenericResource. Converters are registered for all Handlers,
+// and should be registered before any Handler is created. A converter must
+// return a model of a type that is defined by xgql's GraphQL schema.
+func RegisterResourceConverter(gvk schema.GroupVersionKind, c ResourceConverter) {
+	model.RegisterResourceConverter(gvk, c)
+}
+
+// A KindPattern matches kinds of resource, e.g. every kind in the subdomains of
+// a provider's API group.
+type KindPattern = model.KindPattern
+
+// ParseKindPattern parses a pattern of the form group/Kind or
+// group/version/Kind. Any segment may be "*", and a group of the form
+// "*.example.org" matches any subdomain of example.org.
+func ParseKindPattern(s string) (KindPattern, error) {
+	return model.ParseKindPattern(s)
+}
+
+// RegisterResourceConverterPattern registers a converter for any kind of
+// resource that matches the supplied pattern. Converters registered for a
+// specific kind take precedence over patterns, which are matched in the order
+// they were registered. Like RegisterResourceConverter, patterns should be
+// registered before any Handler is created.
+func RegisterResourceConverterPattern(p KindPattern, c ResourceConverter) {
+	model.RegisterResourceConverterPattern(p, c)
+}
@@ -0,0 +1,86 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package schema embeds the xgql GraphQL schema, so that it may be served to
+// clients that wish to validate or generate code against it.
+package schema
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"io/fs"
+	"net/http"
+	"sync"
+)
+
+//go:embed *.gql
+var files embed.FS
+
+// The length of the schema version, in hex characters.
+const versionLength = 16
+
+var (
+	loadOnce sync.Once
+	sdl      []byte
+	version  string
+)
+
+func load() {
+	// The schema files are embedded at build time, so we can't fail to read
+	// them. ReadDir returns them sorted by name, so the SDL is deterministic.
+	entries, _ := fs.ReadDir(files, ".")
+	b := &bytes.Buffer{}
+	for _, e := range entries {
+		f, _ := files.ReadFile(e.Name())
+		b.WriteString("# " + e.Name() + "\n\n")
+		b.Write(bytes.TrimSpace(f))
+		b.WriteString("\n\n")
+	}
+	sdl = b.Bytes()
+
+	sum := sha256.Sum256(sdl)
+	version = hex.EncodeToString(sum[:])[:versionLength]
+}
+
+// SDL returns the xgql GraphQL schema, in the GraphQL schema definition
+// language. The schema includes the directives xgql uses to configure code
+// generation and caching.
+func SDL() []byte {
+	loadOnce.Do(load)
+	return sdl
+}
+
+// Version returns an opaque identifier for the xgql GraphQL schema. It changes
+// whenever the schema changes, regardless of the xgql version.
+func Version() string {
+	loadOnce.Do(load)
+	return version
+}
+
+// Handler returns the xgql GraphQL schema, in the GraphQL schema definition
+// language. The schema's version is returned as its ETag.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := `"` + Version() + `"`
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write(SDL())
+	})
+}
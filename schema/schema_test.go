@@ -0,0 +1,74 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestSDL(t *testing.T) {
+	// The SDL should be a valid schema in its own right.
+	if _, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: string(SDL())}); err != nil {
+		t.Errorf("gqlparser.LoadSchema(SDL()): %s", err)
+	}
+	if diff := cmp.Diff(versionLength, len(Version())); diff != "" {
+		t.Errorf("Version(): -want length, +got length:\n%s", diff)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	cases := map[string]struct {
+		reason      string
+		ifNoneMatch string
+		want        int
+	}{
+		"Fetch": {
+			reason: "The schema should be returned.",
+			want:   http.StatusOK,
+		},
+		"NotModified": {
+			reason:      "The schema should not be returned if the caller already has this version.",
+			ifNoneMatch: `"` + Version() + `"`,
+			want:        http.StatusNotModified,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/schema.graphql", nil)
+			if tc.ifNoneMatch != "" {
+				r.Header.Set("If-None-Match", tc.ifNoneMatch)
+			}
+			w := httptest.NewRecorder()
+			Handler().ServeHTTP(w, r)
+
+			if diff := cmp.Diff(tc.want, w.Code); diff != "" {
+				t.Errorf("\n%s\nServeHTTP(...): -want status code, +got status code:\n%s", tc.reason, diff)
+			}
+			if tc.want != http.StatusOK {
+				return
+			}
+			if diff := cmp.Diff(string(SDL()), w.Body.String()); diff != "" {
+				t.Errorf("\n%s\nServeHTTP(...): -want body, +got body:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
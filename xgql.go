@@ -0,0 +1,633 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package xgql serves a GraphQL API for Crossplane. It allows other Go servers
+// to mount the xgql GraphQL endpoint, rather than running the xgql binary.
+package xgql
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/apollotracing"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	authv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kextv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	extv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+
+	"github.com/upbound/xgql/internal/allowlist"
+	"github.com/upbound/xgql/internal/auth"
+	"github.com/upbound/xgql/internal/authz"
+	"github.com/upbound/xgql/internal/cachecontrol"
+	"github.com/upbound/xgql/internal/clients"
+	"github.com/upbound/xgql/internal/demo"
+	"github.com/upbound/xgql/internal/etag"
+	"github.com/upbound/xgql/internal/feature"
+	"github.com/upbound/xgql/internal/graph/generated"
+	"github.com/upbound/xgql/internal/graph/present"
+	"github.com/upbound/xgql/internal/graph/resolvers"
+	"github.com/upbound/xgql/internal/loadshed"
+	"github.com/upbound/xgql/internal/opentelemetry"
+	"github.com/upbound/xgql/internal/querycost"
+	"github.com/upbound/xgql/internal/request"
+	"github.com/upbound/xgql/internal/sharedcache"
+	"github.com/upbound/xgql/internal/sizelimit"
+)
+
+const (
+	errNewScheme       = "cannot create scheme"
+	errNewRESTMapper   = "cannot create REST mapper"
+	errNewTokenReviews = "cannot create token reviewer"
+	errNewCategories   = "cannot create category index"
+	errNewDemo         = "cannot create demo cluster"
+)
+
+// demoHost identifies the demo dataset in place of an API server's host, e.g.
+// when keying responses in a shared cache.
+const demoHost = "demo"
+
+// An OverflowPolicy determines which events are dropped when a subscriber
+// falls behind.
+type OverflowPolicy = clients.OverflowPolicy
+
+// Overflow policies.
+const (
+	DropNewest = clients.DropNewest
+	DropOldest = clients.DropOldest
+)
+
+// A RedactionPolicy determines what is redacted from the error messages sent
+// to callers.
+type RedactionPolicy = present.RedactionPolicy
+
+// Redaction policies.
+const (
+	RedactNone      = present.RedactNone
+	RedactSensitive = present.RedactSensitive
+	RedactStrict    = present.RedactStrict
+)
+
+// Features determine which experimental features are enabled.
+type Features = feature.Flags
+
+// A SharedCache is an external key value store, e.g. Redis, that is shared by
+// all xgql replicas.
+type SharedCache = sharedcache.Store
+
+// A set of resources that we never want to cache. Clients take a watch on any
+// kind of resource they're asked to read unless it's in this list. We allow
+// caching of arbitrary resources (i.e. *unstructured.Unstructured, which may
+// have any GVK) in order to allow us to cache managed and composite resources.
+// We're particularly at risk of caching resources like these unexpectedly when
+// iterating through arrays of arbitrary object references (e.g. owner refs).
+var noCache = []client.Object{
+	// We don't cache these resources because there's a (very slim) possibility
+	// they could end up as the owner reference of a resource we're concerned
+	// with, and we don't want to try to watch (e.g.) all pods in the cluster
+	// just because a pod somehow became the owner reference of an XR.
+	&corev1.Node{},
+	&corev1.Namespace{},
+	&corev1.Pod{},
+	&corev1.ConfigMap{},
+	&corev1.Service{},
+	&corev1.ServiceAccount{},
+	&appsv1.Deployment{},
+	&appsv1.DaemonSet{},
+	&rbacv1.RoleBinding{},
+	&rbacv1.ClusterRoleBinding{},
+
+	// We don't cache secrets because there's a high risk that the caller won't
+	// have access to list and watch secrets across all namespaces.
+	&corev1.Secret{},
+}
+
+// Scheme returns a new scheme that contains the types xgql reads.
+func Scheme() (*runtime.Scheme, error) {
+	s := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{
+		corev1.AddToScheme,
+		kextv1.AddToScheme,
+		kextv1beta1.AddToScheme,
+		pkgv1.AddToScheme,
+		extv1.AddToScheme,
+		appsv1.AddToScheme,
+		rbacv1.AddToScheme,
+		authv1.AddToScheme,
+	} {
+		if err := add(s); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+type options struct {
+	scheme    *runtime.Scheme
+	log       logging.Logger
+	namespace string
+
+	expiry    time.Duration
+	nocache   []client.Object
+	strip     bool
+	maxSize   int
+	wbuffer   int
+	woverflow OverflowPolicy
+
+	features    Features
+	redaction   RedactionPolicy
+	nestedLimit func() int
+	concurrency func() int
+	tracing     bool
+
+	introspection bool
+	allowlist     []string
+	maxBody       int64
+	maxVariables  int
+
+	tokenCookie string
+	csrfCookie  string
+
+	sharedCache SharedCache
+
+	maxInFlight int
+	maxHeap     uint64
+
+	bthreshold int
+	bcooldown  time.Duration
+
+	maxMetricOperations int
+	maxMetricNameLength int
+	maxMetricKinds      int
+
+	hooks []ResolverHook
+}
+
+// An Option configures a Handler.
+type Option func(o *options)
+
+// WithScheme configures the scheme used by the Handler's clients. It must
+// contain the types in the scheme returned by Scheme, which is used by
+// default.
+func WithScheme(s *runtime.Scheme) Option {
+	return func(o *options) {
+		o.scheme = s
+	}
+}
+
+// WithLogger configures the Handler's logger. A no-op logger is used by
+// default.
+func WithLogger(l logging.Logger) Option {
+	return func(o *options) {
+		o.log = l
+	}
+}
+
+// WithCrossplaneNamespace configures the namespace in which Crossplane runs.
+// The crossplane-system namespace is used by default.
+func WithCrossplaneNamespace(ns string) Option {
+	return func(o *options) {
+		o.namespace = ns
+	}
+}
+
+// WithCacheExpiry configures how long each caller's client cache may go
+// unused before it is garbage collected. Five minutes by default.
+func WithCacheExpiry(d time.Duration) Option {
+	return func(o *options) {
+		o.expiry = d
+	}
+}
+
+// WithUncachedObjects configures the kinds of object that are never cached.
+// Nodes, namespaces, secrets, and other kinds that xgql rarely reads, or that
+// callers are rarely permitted to list and watch, are not cached by default.
+func WithUncachedObjects(objs ...client.Object) Option {
+	return func(o *options) {
+		o.nocache = objs
+	}
+}
+
+// WithStrippedMetadata configures whether managed fields and the
+// last-applied-configuration annotation are stripped from cached objects.
+// They're stripped by default.
+func WithStrippedMetadata(strip bool) Option {
+	return func(o *options) {
+		o.strip = strip
+	}
+}
+
+// WithMaxCachedObjectSize configures the maximum size in bytes of a cached
+// object. Objects of any size are cached by default.
+func WithMaxCachedObjectSize(bytes int) Option {
+	return func(o *options) {
+		o.maxSize = bytes
+	}
+}
+
+// WithWatchBuffer configures how many events are buffered for each subscriber
+// before events are dropped.
+func WithWatchBuffer(n int) Option {
+	return func(o *options) {
+		o.wbuffer = n
+	}
+}
+
+// WithWatchOverflow configures which events are dropped when a subscriber's
+// buffer is full. DropNewest is used by default.
+func WithWatchOverflow(p OverflowPolicy) Option {
+	return func(o *options) {
+		o.woverflow = p
+	}
+}
+
+// WithFeatures configures which experimental features are enabled. Features
+// are enabled per their defaults by default.
+func WithFeatures(f Features) Option {
+	return func(o *options) {
+		o.features = f
+	}
+}
+
+// WithErrorRedaction configures what is redacted from the error messages sent
+// to callers. RedactSensitive is used by default.
+func WithErrorRedaction(p RedactionPolicy) Option {
+	return func(o *options) {
+		o.redaction = p
+	}
+}
+
+// WithNestedLimit configures a function that returns the maximum number of
+// nodes returned by connections nested within a list. It's called for each
+// operation, so the limit may change at runtime.
+func WithNestedLimit(fn func() int) Option {
+	return func(o *options) {
+		o.nestedLimit = fn
+	}
+}
+
+// WithConcurrency configures a function that returns the maximum number of
+// Kubernetes objects each resolver may get concurrently. It's called for each
+// operation, so the limit may change at runtime.
+func WithConcurrency(fn func() int) Option {
+	return func(o *options) {
+		o.concurrency = fn
+	}
+}
+
+// WithTracing configures whether operations are traced using the global
+// OpenTelemetry tracer provider, and using Apollo tracing. Operations are
+// traced by default.
+func WithTracing(enabled bool) Option {
+	return func(o *options) {
+		o.tracing = enabled
+	}
+}
+
+// WithIntrospection configures whether callers may introspect the GraphQL
+// schema. Introspection is enabled by default.
+func WithIntrospection(enabled bool) Option {
+	return func(o *options) {
+		o.introspection = enabled
+	}
+}
+
+// WithOperationAllowlist restricts the GraphQL operations the Handler will
+// execute to those in the supplied allowlist. Each entry is either the hex
+// encoded SHA-256 hash of an allowed GraphQL document, or the name of an
+// allowed operation. Allowing an operation by name allows any document that
+// names its operation accordingly. All operations are allowed by default.
+func WithOperationAllowlist(entries ...string) Option {
+	return func(o *options) {
+		o.allowlist = append(o.allowlist, entries...)
+	}
+}
+
+// WithMaxBodySize configures the maximum size in bytes of a request body.
+// Larger requests are rejected. Zero disables the limit, which is 4MiB by
+// default.
+func WithMaxBodySize(bytes int64) Option {
+	return func(o *options) {
+		o.maxBody = bytes
+	}
+}
+
+// WithMaxVariablesSize configures the maximum size in bytes of the variables
+// of a GraphQL operation, encoded as JSON. Operations with larger variables
+// are rejected. Zero disables the limit, which is 2MiB by default.
+func WithMaxVariablesSize(bytes int) Option {
+	return func(o *options) {
+		o.maxVariables = bytes
+	}
+}
+
+// WithTokenCookie configures the Handler to read the caller's bearer token
+// from the supplied cookie if they don't supply an Authorization header. Such
+// callers must echo the supplied CSRF cookie in the X-CSRF-Token header.
+func WithTokenCookie(token, csrf string) Option {
+	return func(o *options) {
+		o.tokenCookie = token
+		o.csrfCookie = csrf
+	}
+}
+
+// WithSharedCache configures the Handler to cache persisted queries and
+// rendered responses in the supplied store, which may be shared with other
+// replicas of the Handler. Responses are cached only if their Cache-Control
+// header permits it, and only for the caller that made the request. Persisted
+// queries are cached in memory by default, and responses are not cached.
+func WithSharedCache(c SharedCache) Option {
+	return func(o *options) {
+		o.sharedCache = c
+	}
+}
+
+// WithLoadShedding configures the Handler to reject new queries with a
+// RETRYABLE error while more than maxInFlight queries are in flight, or while
+// the process is using more than maxHeap bytes of heap. Mutations and
+// subscriptions are never rejected. Zero disables either threshold. Load
+// shedding is disabled by default.
+func WithLoadShedding(maxInFlight int, maxHeap uint64) Option {
+	return func(o *options) {
+		o.maxInFlight = maxInFlight
+		o.maxHeap = maxHeap
+	}
+}
+
+// WithCircuitBreaker configures each caller's client to fail fast with an
+// UNREACHABLE error, rather than waiting to time out, once threshold
+// consecutive API server calls have failed because the API server was
+// unreachable. Clients fail fast for the supplied cooldown before trying the
+// API server again. Zero disables the circuit breaker, which by default opens
+// after five failures for 30 seconds.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(o *options) {
+		o.bthreshold = threshold
+		o.bcooldown = cooldown
+	}
+}
+
+// WithMetricLimits bounds the cardinality of the Handler's metrics. At most
+// maxOperations distinct GraphQL operation names are used to label metrics;
+// further operations are labelled 'other'. Operation names longer than
+// maxNameLength are truncated and suffixed with a hash. The number and size of
+// cached objects is reported for at most maxKinds kinds of object; the rest
+// are reported together as 'other'. Limits apply to each Handler. Zero
+// disables a limit, and all limits are disabled by default.
+func WithMetricLimits(maxOperations, maxNameLength, maxKinds int) Option {
+	return func(o *options) {
+		o.maxMetricOperations = maxOperations
+		o.maxMetricNameLength = maxNameLength
+		o.maxMetricKinds = maxKinds
+	}
+}
+
+// newOptions returns the supplied options, applied to their defaults.
+func newOptions(o ...Option) (*options, error) {
+	opts := &options{
+		log:         logging.NewNopLogger(),
+		namespace:   resolvers.DefaultCrossplaneNamespace,
+		expiry:      clients.DefaultExpiry,
+		nocache:     noCache,
+		strip:       true,
+		wbuffer:     clients.DefaultWatchBuffer,
+		woverflow:   DropNewest,
+		features:    feature.Set{},
+		redaction:   RedactSensitive,
+		nestedLimit: func() int { return resolvers.DefaultNestedLimit },
+		concurrency: func() int { return resolvers.DefaultConcurrency },
+		tracing:     true,
+
+		introspection: true,
+		maxBody:       sizelimit.DefaultMaxBodySize,
+		maxVariables:  sizelimit.DefaultMaxVariablesSize,
+
+		bthreshold: clients.DefaultBreakerThreshold,
+		bcooldown:  clients.DefaultBreakerCooldown,
+	}
+	for _, fn := range o {
+		fn(opts)
+	}
+
+	// Nothing we log should include the credentials callers supply.
+	opts.log = present.ScrubLogger(opts.log)
+
+	if opts.scheme == nil {
+		s, err := Scheme()
+		if err != nil {
+			return nil, errors.Wrap(err, errNewScheme)
+		}
+		opts.scheme = s
+	}
+
+	return opts, nil
+}
+
+// A Handler serves xgql GraphQL queries, mutations, and subscriptions for the
+// API server (i.e. control plane) it was created for.
+type Handler struct {
+	http.Handler
+	stop func()
+}
+
+// NewHandler returns a Handler that serves the API server described by the
+// supplied REST config. xgql uses the config's credentials to discover the
+// API server's API resources and to review callers' tokens. All other API
+// server calls use the credentials supplied by each caller.
+func NewHandler(cfg *rest.Config, o ...Option) (*Handler, error) {
+	opts, err := newOptions(o...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Our Kubernetes clients need to know what REST API resources are offered
+	// by the API server. The discovery process takes a few ms and makes many
+	// API server calls. Kubernetes allows any authenticated user to access the
+	// discovery API via the system:discovery ClusterRoleBinding, so we create
+	// a REST mapper using our own credentials for all clients to share.
+	// Discovery happens once when the handler is created, and then once any
+	// time a client asks for an unknown kind of API resource (subject to
+	// caching/rate limiting).
+	rm, err := clients.RESTMapper(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewRESTMapper)
+	}
+
+	// Propagate the ID and GraphQL operation name of each request to the API
+	// server calls made while resolving it, so that API server audit logs may
+	// be correlated with the GraphQL requests that triggered them.
+	acfg := clients.Anonymize(cfg)
+	acfg.WrapTransport = request.Transport
+
+	ca := clients.NewCache(opts.scheme,
+		acfg,
+		clients.WithRESTMapper(rm),
+		clients.DoNotCache(opts.nocache),
+		clients.WithLogger(opts.log),
+		clients.WithExpiry(opts.expiry),
+		clients.WithWatchBuffer(opts.wbuffer),
+		clients.WithOverflowPolicy(opts.woverflow),
+		clients.StripMetadata(opts.strip),
+		clients.WithMaxObjectSize(opts.maxSize),
+		clients.WithCircuitBreaker(opts.bthreshold, opts.bcooldown),
+		clients.WithMaxMetricKinds(opts.maxMetricKinds),
+
+		// Kubernetes served CustomResourceDefinitions only at v1beta1 prior
+		// to v1.16. Our resolvers only know about v1.
+		clients.ConvertLegacyCRDs(clients.ServesLegacyCRDs(rm)),
+	)
+
+	// Callers are rarely permitted to review their own tokens, so we review
+	// them using our own credentials.
+	tr, err := clients.NewTokenReviews(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewTokenReviews)
+	}
+
+	// CRDs declare the categories (e.g. managed, composite, claim) their kind
+	// of resource belongs to. We index them using our own credentials, for
+	// the same reasons we do for the REST mapper, and keep the index fresh in
+	// the background so that newly installed providers are recognised.
+	cs, err := clients.NewCategories(cfg, opts.log)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewCategories)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	go cs.Run(ctx, clients.DefaultCategoryRefresh)
+
+	rs := resolvers.New(ca,
+		resolvers.WithPodLogs(clients.NewPodLogs(acfg)),
+		resolvers.WithTokenReviewer(tr),
+		resolvers.WithDiscoverer(clients.NewDiscovery(acfg)),
+		resolvers.WithWatcher(ca),
+		resolvers.WithObjectWatcher(ca),
+		resolvers.WithCategoryIndex(cs),
+		resolvers.WithCrossplaneNamespace(opts.namespace),
+	)
+
+	h := newServer(rs, ca, cfg.Host, opts)
+	return &Handler{Handler: h, stop: func() { cancel(); ca.Stop() }}, nil
+}
+
+// NewDemoHandler returns a Handler that serves an embedded dataset of
+// representative Crossplane resources instead of an API server, so no cluster
+// is required. Every caller is treated as a demo user who may do anything.
+// Writes are kept in memory, and are lost when the Handler is stopped. Options
+// that configure clients of the API server, e.g. WithCacheExpiry, have no
+// effect.
+func NewDemoHandler(o ...Option) (*Handler, error) {
+	opts, err := newOptions(o...)
+	if err != nil {
+		return nil, err
+	}
+
+	dc, err := demo.New(opts.scheme)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewDemo)
+	}
+
+	rs := resolvers.New(dc,
+		resolvers.WithPodLogs(dc),
+		resolvers.WithTokenReviewer(dc),
+		resolvers.WithDiscoverer(dc),
+		resolvers.WithWatcher(dc),
+		resolvers.WithObjectWatcher(dc),
+		resolvers.WithCategoryIndex(dc),
+		resolvers.WithCrossplaneNamespace(opts.namespace),
+	)
+
+	h := newServer(rs, dc, demoHost, opts)
+	return &Handler{Handler: h, stop: func() {}}, nil
+}
+
+// newServer returns a handler that serves GraphQL using the supplied root
+// resolver. The supplied host identifies the API server being served, if any.
+func newServer(rs *resolvers.Root, ca authz.ClientCache, host string, opts *options) http.Handler {
+	// This is equivalent to handler.NewDefaultServer, except that websocket
+	// connections may supply credentials in their init payload.
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: rs}))
+	srv.AddTransport(transport.Websocket{KeepAlivePingInterval: 10 * time.Second, InitFunc: auth.WebsocketInit})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{})
+	srv.SetQueryCache(lru.New(1000))
+	if opts.introspection {
+		srv.Use(extension.Introspection{})
+	}
+	var apq graphql.Cache = lru.New(100)
+	if opts.sharedCache != nil {
+		apq = sharedcache.Queries{Store: opts.sharedCache, TTL: sharedcache.DefaultQueryTTL, Log: opts.log}
+	}
+	srv.Use(extension.AutomaticPersistedQuery{Cache: apq})
+	if opts.maxInFlight > 0 || opts.maxHeap > 0 {
+		srv.Use(loadshed.New(opts.maxInFlight, opts.maxHeap))
+	}
+	srv.Use(sizelimit.Variables{Max: opts.maxVariables})
+	if len(opts.allowlist) > 0 {
+		srv.Use(allowlist.Gate{Allowlist: allowlist.New(opts.allowlist...)})
+	}
+	srv.SetErrorPresenter(present.NewRedactor(opts.redaction, opts.log).Error)
+	srv.AroundOperations(request.AroundOperations)
+	srv.Use(feature.Gate{Flags: opts.features})
+	srv.Use(authz.Gate{Flags: opts.features, Reviewer: authz.NewSelfReviewer(ca)})
+	srv.Use(resolvers.NestedLimitFn(opts.nestedLimit))
+	srv.Use(resolvers.ConcurrencyFn(opts.concurrency))
+	if len(opts.hooks) > 0 {
+		srv.Use(hooks(opts.hooks))
+	}
+	srv.Use(opentelemetry.MetricEmitter{Operations: opentelemetry.NewOperationLabeler(opts.maxMetricOperations, opts.maxMetricNameLength)})
+	if opts.tracing {
+		srv.Use(opentelemetry.Tracer{})
+		srv.Use(apollotracing.Tracer{})
+	}
+	srv.Use(cachecontrol.Extension{})
+	srv.Use(&querycost.Extension{})
+	srv.Use(etag.Extension{})
+
+	var h http.Handler = cachecontrol.Middleware(srv)
+	if opts.sharedCache != nil {
+		// Responses are keyed by API server so that control planes served by
+		// the same shared cache don't see each other's responses.
+		h = sharedcache.Responses(opts.sharedCache, host, opts.log)(h)
+	}
+	h = etag.Middleware(h)
+	if opts.tokenCookie != "" {
+		h = auth.CookieMiddleware(opts.tokenCookie, opts.csrfCookie)(h)
+	}
+	h = auth.Middleware(h)
+	h = sizelimit.Body(opts.maxBody)(h)
+	return h
+}
+
+// Stop the Handler's client caches. Stop is intended to be called when
+// shutting down; the Handler should not serve requests after it is called.
+func (h *Handler) Stop() {
+	h.stop()
+}
@@ -0,0 +1,38 @@
+// Copyright 2021 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xgql
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+func TestScheme(t *testing.T) {
+	s, err := Scheme()
+	if err != nil {
+		t.Fatalf("Scheme(): %s", err)
+	}
+
+	// Every kind of object we never cache must be known to the scheme, in
+	// order for the cache machinery to determine its GVK.
+	for _, o := range append(noCache, &pkgv1.Provider{}, &corev1.Event{}) {
+		if _, _, err := s.ObjectKinds(o); err != nil {
+			t.Errorf("Scheme(): %s", err)
+		}
+	}
+}